@@ -0,0 +1,38 @@
+package cert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// NewCertificateFromExternalFiles loads the externally issued certificate and its key
+// and verifies that the key matches the certificate.
+func NewCertificateFromExternalFiles(certFilePath, keyFilePath string) (*Certificate, error) {
+	cert, err := NewCertificateFromFile(certFilePath, keyFilePath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tls.X509KeyPair(cert.Cert, cert.Key); err != nil {
+		return nil, fmt.Errorf("key %s doesn't match certificate %s: %w", keyFilePath, certFilePath, err)
+	}
+
+	return cert, nil
+}
+
+// LoadExternalCACert loads the PEM encoded certificate of the external CA.
+func LoadExternalCACert(caFilePath string) ([]byte, error) {
+	ca, err := utils.ReadFileContent(caFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading CA cert file: %w", err)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("CA cert file %s doesn't contain a PEM encoded certificate", caFilePath)
+	}
+
+	return ca, nil
+}
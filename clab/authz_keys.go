@@ -10,19 +10,23 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
 )
 
 const (
-	pubKeysGlob = "~/.ssh/*.pub"
-	// authorized keys file path on a clab host that is used to create the clabAuthzKeys file.
-	authzKeysFPath = "~/.ssh/authorized_keys"
+	// pubKeysGlob is the glob of the public key files relative to the home dir of the invoking user.
+	pubKeysGlob = ".ssh/*.pub"
+	// authorized keys file path relative to the home dir of the invoking user
+	// that is used to create the clabAuthzKeys file.
+	authzKeysFPath = ".ssh/authorized_keys"
 )
 
 // CreateAuthzKeysFile creates the authorized_keys file in the lab directory
@@ -44,61 +48,141 @@ func (c *CLab) CreateAuthzKeysFile() error {
 	return os.Chmod(clabAuthzKeysFPath, 0644) // skipcq: GSC-G302
 }
 
-// RetrieveSSHPubKeysFromFiles retrieves public keys from the ~/.ssh/*.authorized_keys
-// and ~/.ssh/*.pub files.
+// RetrieveSSHPubKeysFromFiles retrieves public keys from the ~/.ssh/authorized_keys
+// and ~/.ssh/*.pub files of the invoking user.
 func RetrieveSSHPubKeysFromFiles() ([]ssh.PublicKey, error) {
-	var keys []ssh.PublicKey
-	p := utils.ResolvePath(pubKeysGlob, "")
-
-	all, err := filepath.Glob(p)
+	files, err := sshKeyFiles(nil, utils.HomeDir(), "")
 	if err != nil {
-		return nil, fmt.Errorf("failed globbing the path %s", p)
+		return nil, err
 	}
 
-	f := utils.ResolvePath(authzKeysFPath, "")
+	return utils.LoadSSHPubKeysFromFiles(files)
+}
+
+// sshKeyFiles returns the files to load the public keys from according to the ssh config.
+// The host key files are looked up in the home dir of the invoking user,
+// the files set in the config are resolved relative to the topology dir and must exist.
+func sshKeyFiles(cfg *types.SSHConfig, home, topoDir string) ([]string, error) {
+	var files []string
+
+	if cfg.GetIncludeHostKeys() {
+		p := filepath.Join(home, pubKeysGlob)
+
+		all, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed globbing the path %s", p)
+		}
+
+		files = append(files, all...)
 
-	if utils.FileExists(f) {
-		log.Debugf("%s found, adding it to the list of files to get public keys from", f)
-		all = append(all, f)
+		f := filepath.Join(home, authzKeysFPath)
+		if utils.FileExists(f) {
+			log.Debugf("%s found, adding it to the list of files to get public keys from", f)
+			files = append(files, f)
+		}
 	}
 
-	keys, err = utils.LoadSSHPubKeysFromFiles(all)
-	if err != nil {
-		return nil, err
+	var errs error
+
+	explicit := cfg.GetKeyFiles()
+	if f := cfg.GetAuthorizedKeysFile(); f != "" {
+		explicit = append(explicit[:len(explicit):len(explicit)], f)
 	}
 
-	return keys, nil
+	for _, f := range explicit {
+		p := utils.ResolvePath(f, topoDir)
+		if !utils.FileExists(p) {
+			errs = errors.Join(errs, fmt.Errorf("ssh key file %s does not exist", p))
+			continue
+		}
+
+		files = append(files, p)
+	}
+
+	return files, errs
 }
 
-// RetrieveSSHPubKeys retrieves the PubKeys from the different sources
-// SSHAgent as well as all home dir based /.ssh/*.pub files.
+// RetrieveSSHPubKeys retrieves the PubKeys from the sources selected in the ssh config:
+// the SSHAgent, the home dir based ~/.ssh/*.pub and ~/.ssh/authorized_keys files
+// and the key files set in the config.
 func (c *CLab) RetrieveSSHPubKeys() ([]ssh.PublicKey, error) {
-	keys := make([]ssh.PublicKey, 0)
+	cfg := c.Config.SSH
 
-	var errs error
+	var keys []utils.SSHAuthorizedKey
 
 	// any errors encountered during the retrieval of the keys are not fatal
 	// we accumulate them and log.
-	fkeys, err := RetrieveSSHPubKeysFromFiles()
-	if err != nil {
-		errs = errors.Join(err)
+	files, errs := sshKeyFiles(cfg, utils.HomeDir(), c.TopoPaths.TopologyFileDir())
+
+	for _, f := range files {
+		fkeys, err := utils.LoadSSHAuthorizedKeysFromFiles([]string{f})
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed loading ssh keys from %s: %w", f, err))
+			continue
+		}
+
+		keys = append(keys, fkeys...)
 	}
 
-	agentKeys, err := RetrieveSSHAgentKeys()
-	if err != nil {
-		errs = errors.Join(err)
+	if cfg.GetIncludeAgentKeys() {
+		agentKeys, err := retrieveSSHAgentAuthorizedKeys()
+		if err != nil {
+			errs = errors.Join(errs, err)
+		}
+
+		keys = append(keys, agentKeys...)
 	}
 
-	keysM := map[string]ssh.PublicKey{}
-	for _, k := range append(fkeys, agentKeys...) {
-		keysM[string(ssh.MarshalAuthorizedKey(k))] = k
+	return selectSSHKeys(keys, cfg.GetKeyComments(), cfg.GetMaxKeys()), errs
+}
+
+// selectSSHKeys returns the unique keys in the order of their appearance
+// with the comment matching any of the comment patterns, when set.
+// The keys past the max number of keys are dropped with a warning, max 0 disables the limit.
+func selectSSHKeys(keys []utils.SSHAuthorizedKey, comments []string, max int) []ssh.PublicKey {
+	selected := make([]ssh.PublicKey, 0, len(keys))
+	seen := map[string]struct{}{}
+
+	for _, k := range keys {
+		if !sshKeyCommentMatches(k.Comment, comments) {
+			log.Debugf("skipping ssh key %q not matching the key comment patterns", k.Comment)
+			continue
+		}
+
+		m := string(ssh.MarshalAuthorizedKey(k.Key))
+		if _, ok := seen[m]; ok {
+			continue
+		}
+
+		seen[m] = struct{}{}
+
+		selected = append(selected, k.Key)
 	}
 
-	for _, k := range keysM {
-		keys = append(keys, k)
+	if max > 0 && len(selected) > max {
+		log.Warnf("found %d ssh public keys, only the first %d are added to the nodes, "+
+			"use the ssh block of the topology to select the keys", len(selected), max)
+
+		selected = selected[:max]
 	}
 
-	return keys, errs
+	return selected
+}
+
+// sshKeyCommentMatches returns true if the key comment matches any of the glob patterns
+// or no patterns are set.
+func sshKeyCommentMatches(comment string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, comment); ok {
+			return true
+		}
+	}
+
+	return false
 }
 
 // addKeyToBuffer adds a key to the buffer if the key is not already present.
@@ -110,6 +194,21 @@ func addKeyToBuffer(b *bytes.Buffer, key string) {
 
 // RetrieveSSHAgentKeys retrieves public keys registered with the ssh-agent.
 func RetrieveSSHAgentKeys() ([]ssh.PublicKey, error) {
+	authzKeys, err := retrieveSSHAgentAuthorizedKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var pubKeys []ssh.PublicKey
+	for _, k := range authzKeys {
+		pubKeys = append(pubKeys, k.Key)
+	}
+
+	return pubKeys, nil
+}
+
+// retrieveSSHAgentAuthorizedKeys retrieves public keys registered with the ssh-agent along with their comments.
+func retrieveSSHAgentAuthorizedKeys() ([]utils.SSHAuthorizedKey, error) {
 	socket := os.Getenv("SSH_AUTH_SOCK")
 	if len(socket) == 0 {
 		log.Debug("SSH_AUTH_SOCK not set, skipping pubkey fetching")
@@ -119,6 +218,7 @@ func RetrieveSSHAgentKeys() ([]ssh.PublicKey, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open SSH_AUTH_SOCK: %w", err)
 	}
+	defer conn.Close()
 
 	agentClient := agent.NewClient(conn)
 	keys, err := agentClient.List()
@@ -128,14 +228,14 @@ func RetrieveSSHAgentKeys() ([]ssh.PublicKey, error) {
 
 	log.Debugf("extracted %d keys from ssh-agent", len(keys))
 
-	var pubKeys []ssh.PublicKey
+	var pubKeys []utils.SSHAuthorizedKey
 
 	for _, key := range keys {
 		pkey, err := ssh.ParsePublicKey(key.Blob)
 		if err != nil {
 			return nil, err
 		}
-		pubKeys = append(pubKeys, pkey)
+		pubKeys = append(pubKeys, utils.SSHAuthorizedKey{Key: pkey, Comment: key.Comment})
 	}
 
 	return pubKeys, nil
@@ -0,0 +1,186 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"golang.org/x/crypto/ssh"
+)
+
+// writeFixtureFile writes the file under the dir creating the parent dirs.
+func writeFixtureFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newAuthorizedKey returns a new ed25519 public key with the comment.
+func newAuthorizedKey(t *testing.T, comment string) utils.SSHAuthorizedKey {
+	t.Helper()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return utils.SSHAuthorizedKey{Key: k, Comment: comment}
+}
+
+func TestSSHKeyFiles(t *testing.T) {
+	// root is the home dir of the root user running containerlab via sudo,
+	// its keys must never be collected
+	root := t.TempDir()
+	writeFixtureFile(t, root, ".ssh/id_rsa.pub", "")
+
+	// home is the home dir of the invoking sudo user
+	home := t.TempDir()
+	writeFixtureFile(t, home, ".ssh/id_ed25519.pub", "")
+	writeFixtureFile(t, home, ".ssh/id_rsa.pub", "")
+	writeFixtureFile(t, home, ".ssh/authorized_keys", "")
+
+	topoDir := t.TempDir()
+	writeFixtureFile(t, topoDir, "keys/lab.pub", "")
+	writeFixtureFile(t, topoDir, "keys/authorized_keys", "")
+
+	f := func(dir, name string) string { return filepath.Join(dir, name) }
+
+	tests := map[string]struct {
+		cfg     *types.SSHConfig
+		want    []string
+		wantErr bool
+	}{
+		"defaults": {
+			want: []string{
+				f(home, ".ssh/id_ed25519.pub"),
+				f(home, ".ssh/id_rsa.pub"),
+				f(home, ".ssh/authorized_keys"),
+			},
+		},
+		"host keys and key files": {
+			cfg: &types.SSHConfig{
+				KeyFiles:           []string{"keys/lab.pub"},
+				AuthorizedKeysFile: f(topoDir, "keys/authorized_keys"),
+			},
+			want: []string{
+				f(home, ".ssh/id_ed25519.pub"),
+				f(home, ".ssh/id_rsa.pub"),
+				f(home, ".ssh/authorized_keys"),
+				f(topoDir, "keys/lab.pub"),
+				f(topoDir, "keys/authorized_keys"),
+			},
+		},
+		"host keys excluded": {
+			cfg: &types.SSHConfig{
+				IncludeHostKeys: utils.BoolPointer(false),
+				KeyFiles:        []string{"keys/lab.pub"},
+			},
+			want: []string{f(topoDir, "keys/lab.pub")},
+		},
+		"missing key file": {
+			cfg: &types.SSHConfig{
+				IncludeHostKeys: utils.BoolPointer(false),
+				KeyFiles:        []string{"keys/missing.pub", "keys/lab.pub"},
+			},
+			want:    []string{f(topoDir, "keys/lab.pub")},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := sshKeyFiles(tt.cfg, home, topoDir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("sshKeyFiles() mismatch (-want +got):\n%s", d)
+			}
+
+			for _, p := range got {
+				if strings.HasPrefix(p, root) {
+					t.Errorf("key file %s of the root user is collected", p)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectSSHKeys(t *testing.T) {
+	alice := newAuthorizedKey(t, "alice@laptop")
+	bob := newAuthorizedKey(t, "bob@laptop")
+	ci := newAuthorizedKey(t, "ci-runner")
+	// the agent copy of the alice key with the different comment
+	aliceAgent := utils.SSHAuthorizedKey{Key: alice.Key, Comment: "/home/alice/.ssh/id_ed25519"}
+
+	keys := []utils.SSHAuthorizedKey{alice, bob, aliceAgent, ci}
+
+	tests := map[string]struct {
+		comments []string
+		max      int
+		want     []utils.SSHAuthorizedKey
+	}{
+		"deduplicated in order": {
+			want: []utils.SSHAuthorizedKey{alice, bob, ci},
+		},
+		"comment filter": {
+			comments: []string{"*@laptop"},
+			want:     []utils.SSHAuthorizedKey{alice, bob},
+		},
+		"comment filters": {
+			comments: []string{"bob@*", "ci-*"},
+			want:     []utils.SSHAuthorizedKey{bob, ci},
+		},
+		"capped": {
+			max:  2,
+			want: []utils.SSHAuthorizedKey{alice, bob},
+		},
+		"below the cap": {
+			max:  5,
+			want: []utils.SSHAuthorizedKey{alice, bob, ci},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := selectSSHKeys(keys, tt.comments, tt.max)
+
+			var want []string
+			for _, k := range tt.want {
+				want = append(want, string(ssh.MarshalAuthorizedKey(k.Key)))
+			}
+
+			var gotS []string
+			for _, k := range got {
+				gotS = append(gotS, string(ssh.MarshalAuthorizedKey(k)))
+			}
+
+			if d := cmp.Diff(want, gotS); d != "" {
+				t.Errorf("selectSSHKeys() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// bootLogSuffix is the suffix of the boot log file name of a node.
+const bootLogSuffix = ".boot.log"
+
+// bootLogs captures the logs of the node containers to the boot log files while the lab is deployed.
+type bootLogs struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newBootLogs() *bootLogs {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &bootLogs{ctx: ctx, cancel: cancel}
+}
+
+// capture copies the logs stream to the file until the stream ends or the capture is stopped.
+func (b *bootLogs) capture(path string, rc io.ReadCloser) error {
+	f, err := os.Create(path)
+	if err != nil {
+		rc.Close()
+		return err
+	}
+
+	done := make(chan struct{})
+
+	b.wg.Add(2)
+
+	// closing the stream unblocks the copy when the capture is stopped
+	go func() {
+		defer b.wg.Done()
+
+		select {
+		case <-b.ctx.Done():
+		case <-done:
+		}
+
+		rc.Close()
+	}()
+
+	go func() {
+		defer b.wg.Done()
+		defer close(done)
+		defer f.Close()
+
+		if _, err := io.Copy(f, rc); err != nil && b.ctx.Err() == nil {
+			log.Debugf("boot log capture to %s ended with error: %v", path, err)
+		}
+	}()
+
+	return nil
+}
+
+// stop stops the captures and waits for the captured logs to be written.
+func (b *bootLogs) stop() {
+	b.cancel()
+	b.wg.Wait()
+}
+
+// captureBootLog starts the capture of the node container logs to the node boot log file.
+func (c *CLab) captureBootLog(node nodes.Node) {
+	cfg := node.Config()
+
+	rc, err := node.GetRuntime().GetContainerLogs(c.bootLogs.ctx, cfg.LongName, runtime.LogOptions{Follow: true})
+	if err != nil {
+		log.Warnf("failed to capture the boot log of node %q: %v", cfg.ShortName, err)
+		return
+	}
+
+	path := filepath.Join(c.TopoPaths.TopologyLabDir(), cfg.ShortName+bootLogSuffix)
+
+	if err := c.bootLogs.capture(path, rc); err != nil {
+		log.Warnf("failed to capture the boot log of node %q: %v", cfg.ShortName, err)
+		return
+	}
+
+	log.Debugf("capturing the boot log of node %q to %s", cfg.ShortName, path)
+}
+
+// StopBootLogs stops capturing the boot logs of the nodes, the logs captured so far are kept.
+func (c *CLab) StopBootLogs() {
+	c.bootLogs.stop()
+}
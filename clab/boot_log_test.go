@@ -0,0 +1,72 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBootLogsCapture(t *testing.T) {
+	b := newBootLogs()
+
+	pr, pw := io.Pipe()
+
+	path := filepath.Join(t.TempDir(), "n1"+bootLogSuffix)
+
+	if err := b.capture(path, pr); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := pw.Write([]byte("booting\nready\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the followed stream never ends by itself, stopping the capture closes it
+	b.stop()
+
+	if _, err := pw.Write([]byte("after stop\n")); err == nil {
+		t.Error("the logs stream is not closed after the capture is stopped")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "booting\nready\n" {
+		t.Errorf("got boot log %q, want %q", got, "booting\nready\n")
+	}
+}
+
+func TestBootLogsCaptureStreamEnd(t *testing.T) {
+	b := newBootLogs()
+
+	pr, pw := io.Pipe()
+
+	path := filepath.Join(t.TempDir(), "n1"+bootLogSuffix)
+
+	if err := b.capture(path, pr); err != nil {
+		t.Fatal(err)
+	}
+
+	pw.Write([]byte("exited\n"))
+	pw.Close()
+
+	// the capture of the ended stream is finished without being stopped
+	b.wg.Wait()
+	b.stop()
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "exited\n" {
+		t.Errorf("got boot log %q, want %q", got, "exited\n")
+	}
+}
@@ -6,11 +6,14 @@ package clab
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +34,20 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+const (
+	// topoAuthHeaderEnv is the env var holding the Authorization header value
+	// used to download the topology file over http(s).
+	topoAuthHeaderEnv = "CLAB_TOPO_AUTH_HEADER"
+	// defaultDownloadedTopoFileName is the name of the downloaded topology file
+	// when the url path doesn't contain the file name.
+	defaultDownloadedTopoFileName = "topology.clab.yml"
+	// defaultTopoDownloadTimeout is used when no timeout is set for containerlab.
+	defaultTopoDownloadTimeout = 30 * time.Second
+	// downloadedTopoDirPrefix is the name prefix of the clab temp dir directories
+	// the topology files served over http(s) are downloaded to.
+	downloadedTopoDirPrefix = "topo-download-"
+)
+
 type CLab struct {
 	Config    *Config `json:"config,omitempty"`
 	TopoPaths *types.TopoPaths
@@ -45,13 +62,69 @@ type CLab struct {
 	// and ~/.ssh/*.pub files.
 	// The keys are used to enable key-based SSH access for the nodes.
 	SSHPubKeys []ssh.PublicKey
+	// DisabledNodes are the nodes defined in the topology, but disabled by a user.
+	// These nodes are initialized, but not deployed.
+	DisabledNodes map[string]nodes.Node `json:"disabled-nodes,omitempty"`
 
 	m             *sync.RWMutex
 	timeout       time.Duration
 	globalRuntime string
+	// timeoutIsDefault is set when the timeout is not set explicitly,
+	// such timeout is overridden by the timeout set in the topology settings.
+	timeoutIsDefault bool
 	// nodeFilter is a list of node names to be deployed,
 	// names are provided exactly as they are listed in the topology file.
 	nodeFilter []string
+	// ignoreHostTuningFailures makes failures of the mgmt bridge tuning non-fatal.
+	ignoreHostTuningFailures bool
+	// autoShortenNames allows the over-long container names to be shortened with a hash suffix.
+	autoShortenNames bool
+	// relaxedNodeNames allows the node names that are not valid hostnames,
+	// such nodes get the hostname derived from the node name.
+	relaxedNodeNames bool
+	// hooks receive the lifecycle events of the lab deployment.
+	hooks []LifecycleHook
+	// licenseAssignments are the license pool files assigned to the nodes, keyed by the node name.
+	licenseAssignments map[string]string
+	// resolvedTopology is the topology rendered from the template with the included files merged.
+	resolvedTopology []byte
+	// linkStates tracks the deployment status of the lab links.
+	linkStates *linkStates
+	// topologyBackups are the topology copies written to the lab directory on deploy.
+	topologyBackups *TopologyBackups
+	// bootLogs captures the container logs of the nodes with the boot log enabled.
+	bootLogs *bootLogs
+	// nodeOverrides are the node overrides applied to the lab, the cmd and entrypoint overrides
+	// are applied to the nodes after they are initialized.
+	nodeOverrides []*NodeOverride
+	// skipRenderedTopoWrite disables writing the rendered topology next to the topology file.
+	skipRenderedTopoWrite bool
+	// shutdownTimeout is the time the NOS of a node is given to shut down gracefully before its container is stopped,
+	// zero value disables the graceful shutdown of the nodes.
+	shutdownTimeout time.Duration
+	// dumpSpec makes the deployment write the container specs of the nodes instead of creating their containers.
+	dumpSpec bool
+	// version is the containerlab version recorded in the labels of the nodes.
+	version string
+	// nodeTimings records the times the nodes enter their deployment phases.
+	nodeTimings *nodeTimings
+	// maxWorkers limits the number of the images pulled concurrently, zero means the number of CPUs.
+	maxWorkers uint
+	// healthcheckTimeoutDefault is the maximum time to wait for a node to become healthy
+	// used for the nodes without the healthcheck-timeout set.
+	healthcheckTimeoutDefault time.Duration
+	// healthcheckInterval is the interval between the checks of the node health.
+	healthcheckInterval time.Duration
+	// resolveOnly makes the lab initialize the nodes without side effects,
+	// such lab is used to resolve the node configs and can't be deployed.
+	resolveOnly bool
+	// forceBuild makes the node images built from the build contexts rebuilt even if they are up to date.
+	forceBuild bool
+	// skipPostDeploy makes the lab skip the post-deploy phase of the nodes.
+	skipPostDeploy bool
+	// postDeployed marks the nodes which post-deploy phase has run, keyed by the node name.
+	postDeployed   map[string]bool
+	postDeployedMu sync.Mutex
 }
 
 type ClabOption func(c *CLab) error
@@ -66,6 +139,24 @@ func WithTimeout(dur time.Duration) ClabOption {
 	}
 }
 
+// WithDefaultTimeout sets the timeout used when the topology doesn't set the timeout in its settings.
+func WithDefaultTimeout(dur time.Duration) ClabOption {
+	return func(c *CLab) error {
+		if dur <= 0 {
+			return errors.New("zero or negative timeouts are not allowed")
+		}
+
+		// the timeout of the topology read before this option is kept
+		if c.Config.Settings.GetTimeout() == 0 {
+			c.timeout = dur
+		}
+
+		c.timeoutIsDefault = true
+
+		return nil
+	}
+}
+
 // WithDebug sets debug mode.
 func WithDebug(debug bool) ClabOption {
 	return func(c *CLab) error {
@@ -84,20 +175,91 @@ func WithRuntime(name string, rtconfig *runtime.RuntimeConfig) ClabOption {
 
 		c.globalRuntime = name
 
-		r := rInit()
-		log.Debugf("Running runtime.Init with params %+v and %+v", rtconfig, c.Config.Mgmt)
-		err = r.Init(
-			runtime.WithConfig(rtconfig),
-			runtime.WithMgmtNet(c.Config.Mgmt),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to init the container runtime: %v", err)
+		// the lab-wide runtime resources, e.g. the mgmt network, are labeled with the lab name
+		rtconfig.LabName = c.Config.Name
+
+		// the socket set with the --runtime-socket flag takes precedence over the topology settings
+		if rtconfig.Socket == "" {
+			rtconfig.Socket = c.Config.Settings.GetRuntimeSocket()
 		}
 
-		c.Runtimes[name] = r
-		log.Debugf("initialized a runtime with params %+v", r)
+		rtconfig.RegistryTLS = c.registryTLS()
+
+		// the runtime requests share the timeout set in the topology settings
+		if c.timeoutIsDefault && c.Config.Settings.GetTimeout() > 0 {
+			rtconfig.Timeout = c.timeout
+		}
+
+		return c.initRuntime(name, rInit, rtconfig, c.Config.Mgmt)
+	}
+}
+
+// registryTLS returns the registry TLS material set in the settings
+// with the paths resolved relative to the topology file.
+func (c *CLab) registryTLS() map[string]*types.TLSConfig {
+	regTLS := c.Config.Settings.GetRegistryTLS()
+	if len(regTLS) == 0 || c.TopoPaths == nil {
 		return nil
 	}
+
+	res := make(map[string]*types.TLSConfig, len(regTLS))
+	for registry, tc := range regTLS {
+		if tc == nil {
+			continue
+		}
+		res[registry] = &types.TLSConfig{
+			Cert: c.resolvePath(tc.Cert),
+			Key:  c.resolvePath(tc.Key),
+			CA:   c.resolvePath(tc.CA),
+		}
+	}
+
+	return res
+}
+
+// initRuntime initializes the runtime with the runtime config and the mgmt network config
+// and adds it to the lab runtimes.
+func (c *CLab) initRuntime(name string, rInit runtime.Initializer, rtconfig *runtime.RuntimeConfig,
+	mgmt *types.MgmtNet,
+) error {
+	r := rInit()
+	log.Debugf("Running runtime.Init with params %+v and %+v", rtconfig, mgmt)
+	err := r.Init(
+		runtime.WithConfig(rtconfig),
+		runtime.WithMgmtNet(mgmt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to init the container runtime: %v", err)
+	}
+
+	c.Runtimes[name] = r
+	log.Debugf("initialized a runtime with params %+v", r)
+
+	return nil
+}
+
+// initExtraRuntime initializes the runtime used by the nodes in addition to the global runtime.
+// The runtime shares the config of the global runtime, except for the socket, and gets
+// its own copy of the mgmt network config, since the runtimes record the mgmt network
+// parameters, e.g. the bridge name, when the network is created.
+func (c *CLab) initExtraRuntime(name string) error {
+	rInit, ok := runtime.ContainerRuntimes[name]
+	if !ok {
+		return fmt.Errorf("unknown container runtime %q", name)
+	}
+
+	var rtconfig runtime.RuntimeConfig
+	if r := c.GlobalRuntime(); r != nil {
+		rtconfig = r.Config()
+	}
+
+	rtconfig.LabName = c.Config.Name
+	// the socket of the global runtime is not valid for the other runtimes
+	rtconfig.Socket = ""
+
+	mgmt := *c.Config.Mgmt
+
+	return c.initRuntime(name, rInit, &rtconfig, &mgmt)
 }
 
 // RuntimeInitializer returns a runtime initializer function for a provided runtime name.
@@ -126,36 +288,187 @@ func WithKeepMgmtNet() ClabOption {
 	}
 }
 
-func WithTopoPath(path, varsFile string) ClabOption {
+// WithGracefulShutdown makes the node deletion ask the NOS of the nodes to shut down gracefully
+// before their containers are stopped. The NOS of a node which is not down within the timeout
+// is stopped with its container.
+func WithGracefulShutdown(timeout time.Duration) ClabOption {
 	return func(c *CLab) error {
-		var file string
-		var err error
+		if timeout <= 0 {
+			return errors.New("zero or negative shutdown timeouts are not allowed")
+		}
+		c.shutdownTimeout = timeout
+		return nil
+	}
+}
 
-		switch path {
-		case "-", "stdin":
-			file, err = c.readFromStdin()
-			if err != nil {
-				return err
-			}
+// WithMaxWorkers limits the number of the images pulled concurrently before the nodes are created.
+func WithMaxWorkers(n uint) ClabOption {
+	return func(c *CLab) error {
+		c.maxWorkers = n
+		return nil
+	}
+}
 
-		case "":
-			return fmt.Errorf("provide a path to the clab topology file")
+// WithHealthcheckTimeout sets the maximum time to wait for a node to become healthy,
+// used for the nodes without the healthcheck-timeout set.
+func WithHealthcheckTimeout(timeout time.Duration) ClabOption {
+	return func(c *CLab) error {
+		if timeout < 0 {
+			return errors.New("negative healthcheck timeouts are not allowed")
+		}
+		c.healthcheckTimeoutDefault = timeout
+		return nil
+	}
+}
 
-		default:
-			file, err = findTopoFileByPath(path)
-			if err != nil {
-				return err
-			}
+// WithHealthcheckInterval sets the interval between the checks of the node health.
+func WithHealthcheckInterval(interval time.Duration) ClabOption {
+	return func(c *CLab) error {
+		if interval <= 0 {
+			return errors.New("zero or negative healthcheck intervals are not allowed")
+		}
+		c.healthcheckInterval = interval
+		return nil
+	}
+}
+
+// WithForceBuild makes the node images built from the build contexts rebuilt
+// even if the images with the build tags are built from the unchanged contexts.
+func WithForceBuild() ClabOption {
+	return func(c *CLab) error {
+		c.forceBuild = true
+		return nil
+	}
+}
+
+// WithSkipPostDeploy makes the lab skip the post-deploy phase of the nodes, the nodes waiting for
+// a node in the configured phase wait for it to be created only.
+func WithSkipPostDeploy() ClabOption {
+	return func(c *CLab) error {
+		c.skipPostDeploy = true
+		return nil
+	}
+}
+
+// WithIgnoreHostTuningFailures makes the failures of the management bridge tuning non-fatal.
+func WithIgnoreHostTuningFailures() ClabOption {
+	return func(c *CLab) error {
+		c.ignoreHostTuningFailures = true
+		return nil
+	}
+}
+
+// WithAutoShortenNames allows deploying the nodes which container names exceed the length limit
+// with the names truncated and suffixed with a stable hash.
+func WithAutoShortenNames() ClabOption {
+	return func(c *CLab) error {
+		c.autoShortenNames = true
+		return nil
+	}
+}
+
+// WithRelaxedNodeNames allows the node names that are not valid RFC 1123 hostnames.
+// The hostnames of such nodes are derived from the node names by mapping the invalid characters.
+func WithRelaxedNodeNames() ClabOption {
+	return func(c *CLab) error {
+		c.relaxedNodeNames = true
+		return nil
+	}
+}
+
+// WithDumpSpec makes the deployment write the specs the runtime would submit to create the containers
+// of the nodes to their lab directories, the containers are not created.
+func WithDumpSpec() ClabOption {
+	return func(c *CLab) error {
+		c.dumpSpec = true
+		return nil
+	}
+}
+
+// WithVersion sets the containerlab version recorded in the labels of the lab nodes.
+func WithVersion(v string) ClabOption {
+	return func(c *CLab) error {
+		c.version = v
+		return nil
+	}
+}
+
+func WithTopoPath(path, varsFile string) ClabOption {
+	return func(c *CLab) error {
+		file, err := c.topoFileFromPath(path)
+		if err != nil {
+			return err
 		}
 
 		if err := c.GetTopology(file, varsFile); err != nil {
 			return fmt.Errorf("failed to read topology file: %v", err)
 		}
 
+		if err := c.applySettingsTimeout(); err != nil {
+			return err
+		}
+
 		return c.initMgmtNetwork()
 	}
 }
 
+// applySettingsTimeout validates the timeout set in the topology settings
+// and uses it unless the timeout is set explicitly.
+func (c *CLab) applySettingsTimeout() error {
+	if c.Config.Settings == nil || c.Config.Settings.Timeout == nil {
+		return nil
+	}
+
+	t := c.Config.Settings.GetTimeout()
+	if t <= 0 {
+		return fmt.Errorf("settings.timeout must be a positive duration, got %s", t)
+	}
+
+	// the timeout is also used when no timeout option is set at all
+	if c.timeoutIsDefault || c.timeout <= 0 {
+		c.timeout = t
+	}
+
+	return nil
+}
+
+// RenderTopology returns the topology referenced by path the way it is used to create the lab,
+// i.e. with the template rendered, env vars expanded and the included topology files merged.
+func RenderTopology(path, varsFile string, timeout time.Duration) ([]byte, error) {
+	c := &CLab{timeout: timeout}
+
+	file, err := c.topoFileFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.TopoPaths, err = types.NewTopoPaths(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.renderTopology(varsFile)
+}
+
+// topoFileFromPath returns the path to the topology file referenced by path.
+// The path might point to a topology file, a directory with a topology file, a http(s) url
+// or be set to "-" or "stdin" to read the topology from stdin.
+func (c *CLab) topoFileFromPath(path string) (string, error) {
+	switch {
+	case path == "-" || path == "stdin":
+		return c.readFromStdin()
+
+	case path == "":
+		return "", fmt.Errorf("provide a path to the clab topology file")
+
+	case utils.IsHttpUri(path):
+		return c.downloadTopoFile(path)
+
+	default:
+		return findTopoFileByPath(path)
+	}
+}
+
 // findTopoFileByPath takes a topology path, which might be the path to a directory
 // and returns the topology file name if found.
 func findTopoFileByPath(path string) (string, error) {
@@ -216,6 +529,82 @@ func (c *CLab) readFromStdin() (string, error) {
 	return tmpFile.Name(), nil
 }
 
+// downloadTopoFile downloads the topology file referenced by the http(s) url
+// to the clab temp directory and returns a path to the downloaded file.
+// The value of the CLAB_TOPO_AUTH_HEADER env var, if set, is used as the Authorization header.
+func (c *CLab) downloadTopoFile(topoURL string) (string, error) {
+	headers := map[string]string{}
+	if v := os.Getenv(topoAuthHeaderEnv); v != "" {
+		headers["Authorization"] = v
+	}
+
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = defaultTopoDownloadTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Infof("Downloading topology file from %s", topoURL)
+
+	file, err := fetchTopoFile(ctx, c.TopoPaths.ClabTmpDir(), topoURL, headers)
+	if err != nil {
+		return "", fmt.Errorf("failed to download topology file: %w", err)
+	}
+
+	return file, nil
+}
+
+// fetchTopoFile downloads the topology file referenced by the http(s) url to the directory
+// of the url in the tmpDir and returns a path to the downloaded file.
+// The directory is named after the hash of the url, so that the file downloaded again
+// for the next command run against the lab, e.g. destroy, replaces the one the lab was deployed from,
+// and the files downloaded from the different urls don't collide.
+// The lab directory is created next to the downloaded file and the relative paths used in the topology
+// are resolved against the directory of the url as well.
+func fetchTopoFile(ctx context.Context, tmpDir, topoURL string, headers map[string]string) (string, error) {
+	u, err := url.Parse(topoURL)
+	if err != nil {
+		return "", err
+	}
+
+	fname := filepath.Base(u.Path)
+	if fname == "." || fname == "/" {
+		fname = defaultDownloadedTopoFileName
+	}
+
+	dir := filepath.Join(tmpDir, fmt.Sprintf("%s%x", downloadedTopoDirPrefix, sha256.Sum256([]byte(topoURL))))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// the file is downloaded next to the topology file and renamed once complete,
+	// so that a failed download doesn't clobber the topology file of the deployed lab
+	tmp, err := os.CreateTemp(dir, fname+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := utils.DownloadFile(ctx, topoURL, tmp.Name(), headers); err != nil {
+		return "", err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, fname)
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
 // WithNodeFilter option sets a filter for nodes to be deployed.
 // A filter is a list of node names to be deployed,
 // names are provided exactly as they are listed in the topology file.
@@ -261,11 +650,13 @@ func NewContainerLab(opts ...ClabOption) (*CLab, error) {
 			Mgmt:     new(types.MgmtNet),
 			Topology: types.NewTopology(),
 		},
-		m:        new(sync.RWMutex),
-		Nodes:    make(map[string]nodes.Node),
-		Links:    make(map[int]links.Link),
-		Runtimes: make(map[string]runtime.ContainerRuntime),
-		Cert:     &cert.Cert{},
+		m:           new(sync.RWMutex),
+		Nodes:       make(map[string]nodes.Node),
+		Links:       make(map[int]links.Link),
+		Runtimes:    make(map[string]runtime.ContainerRuntime),
+		Cert:        &cert.Cert{},
+		bootLogs:    newBootLogs(),
+		nodeTimings: newNodeTimings(),
 	}
 
 	// init a new NodeRegistry
@@ -283,6 +674,8 @@ func NewContainerLab(opts ...ClabOption) (*CLab, error) {
 
 	var err error
 	if c.TopoPaths.TopologyFileIsSet() {
+		c.pruneTmpDirOnLoad()
+
 		err = c.parseTopology()
 	}
 
@@ -446,8 +839,19 @@ func createStaticDynamicDependency(n map[string]nodes.Node, dm dependency_manage
 func createWaitForDependency(n map[string]nodes.Node, dm dependency_manager.DependencyManager) error {
 	for waiterNode, node := range n {
 		// add node's waitFor nodes to the dependency manager
-		for _, waitForNode := range node.Config().WaitFor {
-			err := dm.AddDependency(waitForNode, waiterNode)
+		for _, w := range node.Config().WaitFor {
+			var err error
+
+			switch w.Phase {
+			case types.WaitForPhaseConfigured:
+				err = dm.AddStateDependency(w.Node, waiterNode, dependency_manager.NodeStateConfigured)
+			case types.WaitForPhaseExit:
+				err = dm.AddStateDependency(w.Node, waiterNode, dependency_manager.NodeStateExited)
+			default:
+				// the nodes waited for in the healthy phase are waited for to be created first
+				err = dm.AddDependency(w.Node, waiterNode)
+			}
+
 			if err != nil {
 				return err
 			}
@@ -457,11 +861,18 @@ func createWaitForDependency(n map[string]nodes.Node, dm dependency_manager.Depe
 	return nil
 }
 
+// scheduleNodes deploys the scheduled nodes by maxWorkers concurrent workers once their dependencies are satisfied.
+// The nodes of the groups with the max-workers limit set in the topology are deployed
+// by no more than the group limit of the workers at a time.
 func (c *CLab) scheduleNodes(ctx context.Context, maxWorkers int,
 	scheduledNodes map[string]nodes.Node, dm dependency_manager.DependencyManager,
 ) *sync.WaitGroup {
 	concurrentChan := make(chan nodes.Node)
 
+	linksTracker := newLinksTracker(scheduledNodes)
+
+	groups := newGroupLimiter(c.Config.Topology.GetGroupsMaxWorkers())
+
 	workerFunc := func(i int, input chan nodes.Node, wg *sync.WaitGroup,
 		dm dependency_manager.DependencyManager,
 	) {
@@ -475,42 +886,10 @@ func (c *CLab) scheduleNodes(ctx context.Context, maxWorkers int,
 				}
 				log.Debugf("Worker %d received node: %+v", i, node.Config())
 
-				// Apply startup delay
-				delay := node.Config().StartupDelay
-				if delay > 0 {
-					log.Infof("node %q is being delayed for %d seconds", node.Config().ShortName, delay)
-					time.Sleep(time.Duration(delay) * time.Second)
-				}
+				c.deployNode(ctx, node, linksTracker, dm)
 
-				// PreDeploy
-				err := node.PreDeploy(
-					ctx,
-					&nodes.PreDeployParams{
-						Cert:         c.Cert,
-						TopologyName: c.Config.Name,
-						TopoPaths:    c.TopoPaths,
-						SSHPubKeys:   c.SSHPubKeys,
-					},
-				)
-				if err != nil {
-					log.Errorf("failed pre-deploy phase for node %q: %v", node.Config().ShortName, err)
-					continue
-				}
-				// Deploy
-				err = node.Deploy(ctx, &nodes.DeployParams{})
-				if err != nil {
-					log.Errorf("failed deploy phase for node %q: %v", node.Config().ShortName, err)
-					continue
-				}
-
-				err = node.DeployLinks(ctx)
-				if err != nil {
-					log.Errorf("failed deploy links for node %q: %v", node.Config().ShortName, err)
-					continue
-				}
-
-				// signal to dependency manager that this node is done with creation
-				dm.SignalDone(node.Config().ShortName, dependency_manager.NodeStateCreated)
+				// the group slot is acquired by the scheduler before the node is handed to the worker
+				groups.release(node.Config().Group)
 
 			case <-ctx.Done():
 				return
@@ -545,12 +924,22 @@ func (c *CLab) scheduleNodes(ctx context.Context, maxWorkers int,
 				workerChan chan<- nodes.Node, wfcwg *sync.WaitGroup,
 			) {
 				// wait for all the nodes that node depends on
+				c.NotifyNodePhase(node.Config().ShortName, NodePhaseWaitingDeps, nil)
 				err := dm.WaitForNodeDependencies(node.Config().ShortName)
 				if err != nil {
 					log.Error(err)
 				}
+				// wait for the nodes the node waits for to become healthy
+				if !c.dumpSpec {
+					c.waitForHealthyDependencies(ctx, node.Config())
+				}
 				// wait for possible external dependencies
 				c.WaitForExternalNodeDependencies(ctx, node.Config().ShortName)
+				// wait for a free slot of the node group
+				if !groups.acquire(ctx, node.Config().Group) {
+					wfcwg.Done()
+					return
+				}
 				// when all nodes that this node depends on are created, push it into the channel
 				workerChan <- node
 				// indicate we are done, such that only when all of these functions are done, the workerChan is being closed
@@ -566,9 +955,108 @@ func (c *CLab) scheduleNodes(ctx context.Context, maxWorkers int,
 	return wg
 }
 
+// deployNode runs the pre-deploy and deploy phases of the node and deploys its links.
+// The dependency manager is signaled once the node is created.
+func (c *CLab) deployNode(ctx context.Context, node nodes.Node, linksTracker *linksTracker,
+	dm dependency_manager.DependencyManager,
+) {
+	// Apply startup delay
+	delay := node.Config().StartupDelay
+	if delay > 0 {
+		log.Infof("node %q is being delayed for %d seconds", node.Config().ShortName, delay)
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	// PreDeploy
+	c.NotifyNodePhase(node.Config().ShortName, NodePhasePreDeploy, nil)
+	err := node.PreDeploy(
+		ctx,
+		&nodes.PreDeployParams{
+			Cert:         c.Cert,
+			TopologyName: c.Config.Name,
+			TopoPaths:    c.TopoPaths,
+			SSHPubKeys:   c.SSHPubKeys,
+		},
+	)
+	if err != nil {
+		log.Errorf("failed pre-deploy phase for node %q: %v", node.Config().ShortName, err)
+		c.NotifyNodePhase(node.Config().ShortName, NodePhaseFailed,
+			fmt.Errorf("failed pre-deploy phase: %w", err))
+		return
+	}
+
+	// the node is not deployed, but its dependents still wait for it to be created
+	if c.dumpSpec {
+		if err := c.dumpContainerSpec(ctx, node); err != nil {
+			log.Errorf("failed to dump the container spec of node %q: %v", node.Config().ShortName, err)
+			c.NotifyNodePhase(node.Config().ShortName, NodePhaseFailed,
+				fmt.Errorf("failed to dump the container spec: %w", err))
+		}
+
+		dm.SignalDone(node.Config().ShortName, dependency_manager.NodeStateCreated)
+		c.signalWaitedPhases(ctx, node, dm)
+		return
+	}
+
+	// Deploy
+	c.NotifyNodePhase(node.Config().ShortName, NodePhaseDeploying, nil)
+	err = node.Deploy(ctx, &nodes.DeployParams{})
+	if err != nil {
+		log.Errorf("failed deploy phase for node %q: %v", node.Config().ShortName, err)
+		c.NotifyNodePhase(node.Config().ShortName, NodePhaseFailed,
+			fmt.Errorf("failed deploy phase: %w", err))
+		return
+	}
+
+	if node.Config().BootLog {
+		c.captureBootLog(node)
+	}
+
+	// the post-start exec commands run before the links of the node are created
+	runtime.RunExecPhase(ctx, node.Config(), types.ExecPhasePostStart, node.RunExec)
+
+	err = node.DeployLinks(ctx)
+	if err != nil {
+		c.linkStates.linkFailed(node, err)
+		log.Errorf("failed deploy links for node %q: %v", node.Config().ShortName, err)
+		c.NotifyNodePhase(node.Config().ShortName, NodePhaseFailed,
+			fmt.Errorf("failed deploy links: %w", err))
+		return
+	}
+
+	c.linkStates.linksDeployed(node)
+	c.notifyLinks()
+
+	// run the post-links exec commands of the nodes which links are all created now
+	for _, n := range linksTracker.linksDeployed(node) {
+		runtime.RunExecPhase(ctx, n.Config(), types.ExecPhasePostLinks, n.RunExec)
+	}
+
+	// the nodes depending on this one are created once it is ready
+	if node.Config().StartupWait.IsSet() {
+		c.NotifyNodePhase(node.Config().ShortName, NodePhaseStartupWait, nil)
+
+		if err := waitForStartup(ctx, node); err != nil {
+			log.Errorf("node %q didn't meet its startup-wait condition: %v", node.Config().ShortName, err)
+			c.NotifyNodePhase(node.Config().ShortName, NodePhaseFailed,
+				fmt.Errorf("failed startup wait: %w", err))
+			return
+		}
+	}
+
+	// signal to dependency manager that this node is done with creation
+	dm.SignalDone(node.Config().ShortName, dependency_manager.NodeStateCreated)
+	c.NotifyNodePhase(node.Config().ShortName, NodePhaseCreated, nil)
+	// the nodes waiting for this one to be configured or to exit are released once it does
+	c.signalWaitedPhases(ctx, node, dm)
+}
+
 // WaitForExternalNodeDependencies makes nodes that have a reference to an external container network-namespace (network-mode: container:<NAME>)
 // to wait until the referenced container is in started status.
-// The wait time is 15 minutes by default.
+// When the network mode has the healthy phase (network-mode: container:<NAME>:healthy), the node also waits
+// for the referenced container to become healthy, be it an external container or a node of the lab.
+// The external container is waited for to be running for 15 minutes, the wait for a container to become healthy
+// is limited by the healthcheck timeout, after which the node is deployed regardless.
 func (c *CLab) WaitForExternalNodeDependencies(ctx context.Context, nodeName string) {
 	if _, exists := c.Nodes[nodeName]; !exists {
 		log.Errorf("unable to find referenced node %q", nodeName)
@@ -583,13 +1071,32 @@ func (c *CLab) WaitForExternalNodeDependencies(ctx context.Context, nodeName str
 	// the referenced container might be an external pre-existing or a container created also by the given clab topology.
 	contName := netModeArr[1]
 
-	// if the container does not exist in the list of container, it must be an external dependency
-	// it can be ignored for internal processing so -> continue
-	if _, exists := c.Nodes[contName]; exists {
+	// the lab nodes are waited for by the dependency manager to be created,
+	// the health of the node container is checked on top of that
+	if n, exists := c.Nodes[contName]; exists {
+		if nodeConfig.NetworkModePhase == types.ContainerPhaseHealthy {
+			if err := c.WaitForHealthy(ctx, contName, c.healthcheckTimeout(contName)); err != nil {
+				log.Warn(err)
+			}
+		}
+		return
+	}
+
+	if err := runtime.WaitForContainerRunning(ctx, c.Runtimes[c.globalRuntime], contName, nodeName); err != nil {
 		return
 	}
 
-	runtime.WaitForContainerRunning(ctx, c.Runtimes[c.globalRuntime], contName, nodeName)
+	if nodeConfig.NetworkModePhase == types.ContainerPhaseHealthy {
+		interval := c.healthcheckInterval
+		if interval <= 0 {
+			interval = defaultHealthcheckInterval
+		}
+
+		if err := runtime.WaitForContainerHealthy(ctx, c.Runtimes[c.globalRuntime], contName, nodeName,
+			c.healthcheckTimeout(contName), interval); err != nil {
+			log.Warn(err)
+		}
+	}
 }
 
 func (c *CLab) DeleteNodes(ctx context.Context, workers uint, serialNodes map[string]struct{}) {
@@ -607,6 +1114,7 @@ func (c *CLab) DeleteNodes(ctx context.Context, workers uint, serialNodes map[st
 					log.Debugf("Worker %d terminating...", i)
 					return
 				}
+				c.shutdownNode(ctx, n)
 				err := n.Delete(ctx)
 				if err != nil {
 					log.Errorf("could not remove container %q: %v", n.Config().LongName, err)
@@ -699,6 +1207,13 @@ func (c *CLab) ListNodesContainersIgnoreNotFound(ctx context.Context) ([]runtime
 }
 
 func (c *CLab) GetNodeRuntime(contName string) (runtime.ContainerRuntime, error) {
+	// shortened container names can't be parsed, so the nodes are matched by their container names first
+	for _, n := range c.Nodes {
+		if n.Config().LongName == contName {
+			return n.GetRuntime(), nil
+		}
+	}
+
 	shortName, err := getShortName(c.Config.Name, c.Config.Prefix, contName)
 	if err != nil {
 		return nil, err
@@ -751,6 +1266,7 @@ func (c *CLab) ResolveLinks() error {
 		Nodes:          c.GetLinkNodes(),
 		MgmtBridgeName: c.Config.Mgmt.Bridge,
 		NodesFilter:    c.nodeFilter,
+		DisabledNodes:  c.disabledNodeNames(),
 	}
 
 	for i, l := range c.Config.Topology.Links {
@@ -768,9 +1284,36 @@ func (c *CLab) ResolveLinks() error {
 		c.Links[i] = l
 	}
 
+	c.linkStates = newLinkStates(c.Links, c.Nodes)
+
 	return nil
 }
 
+// disabledNodeNames returns the sorted names of the disabled nodes.
+func (c *CLab) disabledNodeNames() []string {
+	names := make([]string, 0, len(c.DisabledNodes))
+	for name := range c.DisabledNodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// AddDeployedDisabledNodes adds the disabled nodes that have containers running, e.g. deployed
+// before the nodes were disabled, to the lab nodes. This allows destroy to clean them up.
+func (c *CLab) AddDeployedDisabledNodes(ctx context.Context) {
+	for name, n := range c.DisabledNodes {
+		if _, err := n.GetContainers(ctx); err != nil {
+			continue
+		}
+
+		log.Infof("Node %q is disabled, but it has been deployed before and will be removed", name)
+		c.Nodes[name] = n
+		delete(c.DisabledNodes, name)
+	}
+}
+
 // ExtractDNSServers extracts DNS servers from the resolv.conf files
 // and populates the Nodes DNS Config with these if not specifically provided.
 func (c *CLab) ExtractDNSServers(filesys fs.FS) error {
@@ -7,10 +7,18 @@ package clab
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab/dependency_manager"
 	errs "github.com/srl-labs/containerlab/errors"
 	"github.com/srl-labs/containerlab/mocks"
 	"github.com/srl-labs/containerlab/mocks/mocknodes"
@@ -73,7 +81,7 @@ func getNodeMap(mockCtrl *gomock.Controller) map[string]nodes.Node {
 		&types.NodeConfig{
 			Image:     "alpine:3",
 			ShortName: "node2",
-			WaitFor:   []string{"node1"},
+			WaitFor:   []types.WaitFor{types.ParseWaitFor("node1")},
 		},
 	).AnyTimes()
 
@@ -84,7 +92,7 @@ func getNodeMap(mockCtrl *gomock.Controller) map[string]nodes.Node {
 			Image:       "alpine:3",
 			NetworkMode: "container:node2",
 			ShortName:   "node3",
-			WaitFor:     []string{"node1", "node2"},
+			WaitFor:     []types.WaitFor{types.ParseWaitFor("node1"), types.ParseWaitFor("node2:healthy")},
 		},
 	).AnyTimes()
 
@@ -106,7 +114,7 @@ func getNodeMap(mockCtrl *gomock.Controller) map[string]nodes.Node {
 			Image:           "alpine:3",
 			MgmtIPv4Address: "172.10.10.2",
 			ShortName:       "node5",
-			WaitFor:         []string{"node3", "node4"},
+			WaitFor:         []types.WaitFor{types.ParseWaitFor("node3"), types.ParseWaitFor("node4:configured")},
 		},
 	).AnyTimes()
 
@@ -137,7 +145,7 @@ func Test_createWaitForDependency(t *testing.T) {
 	dm.EXPECT().AddDependency("node1", "node3")
 	dm.EXPECT().AddDependency("node2", "node3")
 	dm.EXPECT().AddDependency("node3", "node5")
-	dm.EXPECT().AddDependency("node4", "node5")
+	dm.EXPECT().AddStateDependency("node4", "node5", dependency_manager.NodeStateConfigured)
 
 	err := createWaitForDependency(nodeMap, dm)
 	if err != nil {
@@ -186,6 +194,82 @@ func Test_WaitForExternalNodeDependencies_OK(t *testing.T) {
 	}
 }
 
+func Test_WaitForExternalNodeDependencies_Healthy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	crMock := mockruntime.NewMockContainerRuntime(mockCtrl)
+
+	ctx := context.TODO()
+
+	statuses := []string{"Up 1 second (health: starting)", "Up 2 seconds (unhealthy)", "Up 3 seconds (healthy)"}
+	counter := 0
+	crMock.EXPECT().ListContainers(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ []*types.GenericFilter) ([]runtime.GenericContainer, error) {
+			status := statuses[counter]
+			counter++
+			return []runtime.GenericContainer{{State: "running", Status: status}}, nil
+		},
+	).Times(len(statuses))
+
+	owner := mocknodes.NewMockNode(mockCtrl)
+	owner.EXPECT().Config().Return(&types.NodeConfig{ShortName: "nos", LongName: "clab-test-nos"}).AnyTimes()
+	owner.EXPECT().GetRuntime().Return(crMock).AnyTimes()
+
+	sidecar := mocknodes.NewMockNode(mockCtrl)
+	sidecar.EXPECT().Config().Return(&types.NodeConfig{
+		ShortName:        "sidecar",
+		NetworkMode:      "container:nos",
+		NetworkModePhase: types.ContainerPhaseHealthy,
+	}).AnyTimes()
+
+	c := CLab{
+		Nodes:               map[string]nodes.Node{"nos": owner, "sidecar": sidecar},
+		healthcheckInterval: time.Millisecond,
+	}
+
+	c.WaitForExternalNodeDependencies(ctx, "sidecar")
+
+	if counter != len(statuses) {
+		t.Errorf("expected %d status checks of the namespace owner, seen %d", len(statuses), counter)
+	}
+}
+
+func Test_healthcheckTimeout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	withTimeout := mocknodes.NewMockNode(mockCtrl)
+	withTimeout.EXPECT().Config().Return(&types.NodeConfig{HealthcheckTimeout: time.Minute}).AnyTimes()
+
+	withoutTimeout := mocknodes.NewMockNode(mockCtrl)
+	withoutTimeout.EXPECT().Config().Return(&types.NodeConfig{}).AnyTimes()
+
+	tests := map[string]struct {
+		node       string
+		labTimeout time.Duration
+		want       time.Duration
+	}{
+		"node timeout":       {node: "with", labTimeout: time.Hour, want: time.Minute},
+		"lab timeout":        {node: "without", labTimeout: time.Hour, want: time.Hour},
+		"default timeout":    {node: "without", want: DefaultHealthcheckTimeout},
+		"external container": {node: "external", labTimeout: time.Hour, want: time.Hour},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := CLab{
+				Nodes:                     map[string]nodes.Node{"with": withTimeout, "without": withoutTimeout},
+				healthcheckTimeoutDefault: tt.labTimeout,
+			}
+
+			if got := c.healthcheckTimeout(tt.node); got != tt.want {
+				t.Errorf("got timeout %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_WaitForExternalNodeDependencies_NoContainerNetworkMode(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -306,6 +390,34 @@ func Test_filterClabNodes(t *testing.T) {
 	}
 }
 
+func TestAddDeployedDisabledNodes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	deployed := mocknodes.NewMockNode(mockCtrl)
+	deployed.EXPECT().GetContainers(gomock.Any()).Return([]runtime.GenericContainer{{ShortID: "abc"}}, nil)
+
+	notDeployed := mocknodes.NewMockNode(mockCtrl)
+	notDeployed.EXPECT().GetContainers(gomock.Any()).Return(nil, nodes.ErrContainersNotFound)
+
+	c := &CLab{
+		Nodes: map[string]nodes.Node{},
+		DisabledNodes: map[string]nodes.Node{
+			"deployed":     deployed,
+			"not-deployed": notDeployed,
+		},
+	}
+
+	c.AddDeployedDisabledNodes(context.Background())
+
+	if _, ok := c.Nodes["deployed"]; !ok {
+		t.Error("deployed disabled node was not added to the lab nodes")
+	}
+
+	if _, ok := c.Nodes["not-deployed"]; ok {
+		t.Error("not deployed disabled node was added to the lab nodes")
+	}
+}
+
 func TestWithTopo(t *testing.T) {
 	type args struct {
 		topoRef string
@@ -376,3 +488,183 @@ func TestWithTopo(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistryTLSSettings(t *testing.T) {
+	dir := t.TempDir()
+
+	topo := `name: regtls
+settings:
+  registry-tls:
+    registry.example.com:5000:
+      cert: certs/client.pem
+      key: certs/client.key
+      ca: /etc/pki/registry-ca.pem
+topology:
+  nodes:
+    n1:
+      kind: linux
+      image: registry.example.com:5000/alpine:3
+`
+	topoPath := filepath.Join(dir, "regtls.clab.yml")
+	if err := os.WriteFile(topoPath, []byte(topo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(topoPath, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*types.TLSConfig{
+		"registry.example.com:5000": {
+			Cert: filepath.Join(dir, "certs/client.pem"),
+			Key:  filepath.Join(dir, "certs/client.key"),
+			CA:   "/etc/pki/registry-ca.pem",
+		},
+	}
+
+	if d := cmp.Diff(want, c.registryTLS()); d != "" {
+		t.Errorf("registry tls mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestSettingsTimeout(t *testing.T) {
+	tests := map[string]struct {
+		// settings is the settings section of the topology
+		settings string
+		opt      ClabOption
+		want     time.Duration
+		wantErr  string
+	}{
+		"default timeout without settings": {
+			opt:  WithDefaultTimeout(2 * time.Minute),
+			want: 2 * time.Minute,
+		},
+		"settings timeout overrides default": {
+			settings: "settings:\n  timeout: 5m\n",
+			opt:      WithDefaultTimeout(2 * time.Minute),
+			want:     5 * time.Minute,
+		},
+		"explicit timeout overrides settings": {
+			settings: "settings:\n  timeout: 5m\n",
+			opt:      WithTimeout(30 * time.Second),
+			want:     30 * time.Second,
+		},
+		"zero settings timeout": {
+			settings: "settings:\n  timeout: 0s\n",
+			opt:      WithDefaultTimeout(2 * time.Minute),
+			wantErr:  "settings.timeout must be a positive duration, got 0s",
+		},
+		"negative settings timeout": {
+			settings: "settings:\n  timeout: -1m\n",
+			opt:      WithTimeout(30 * time.Second),
+			wantErr:  "settings.timeout must be a positive duration, got -1m0s",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+			topo := "name: timeout\n" + tc.settings + `topology:
+  nodes:
+    n1:
+      kind: linux
+      image: alpine:3
+`
+			topoPath := filepath.Join(t.TempDir(), "timeout.clab.yml")
+			if err := os.WriteFile(topoPath, []byte(topo), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := NewContainerLab(tc.opt, WithTopoPath(topoPath, ""))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.timeout != tc.want {
+				t.Fatalf("got timeout %s, want %s", c.timeout, tc.want)
+			}
+		})
+	}
+}
+
+func TestFetchTopoFile(t *testing.T) {
+	version := 1
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.clab.yml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprintf(w, "name: lab%d\n", version)
+	}))
+	defer srv.Close()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	file, err := fetchTopoFile(ctx, tmpDir, srv.URL+"/a/lab.clab.yml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(file) != "lab.clab.yml" || !strings.HasPrefix(file, tmpDir+string(filepath.Separator)) {
+		t.Errorf("got topology file %s, want lab.clab.yml in %s", file, tmpDir)
+	}
+
+	// the file downloaded again from the same url, e.g. to destroy the lab, replaces the previous one
+	version = 2
+
+	again, err := fetchTopoFile(ctx, tmpDir, srv.URL+"/a/lab.clab.yml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again != file {
+		t.Errorf("got topology file %s for the same url, want %s", again, file)
+	}
+
+	if b, _ := os.ReadFile(file); string(b) != "name: lab2\n" {
+		t.Errorf("got topology %q, want the downloaded again one", b)
+	}
+
+	// the file with the same name downloaded from another url doesn't collide
+	other, err := fetchTopoFile(ctx, tmpDir, srv.URL+"/b/lab.clab.yml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Dir(other) == filepath.Dir(file) {
+		t.Errorf("the topology files from the different urls are downloaded to the same dir %s", filepath.Dir(file))
+	}
+
+	// the url without the file name
+	noName, err := fetchTopoFile(ctx, tmpDir, srv.URL+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(noName) != defaultDownloadedTopoFileName {
+		t.Errorf("got topology file %s, want %s", noName, defaultDownloadedTopoFileName)
+	}
+
+	if _, err := fetchTopoFile(ctx, tmpDir, srv.URL+"/missing.clab.yml", nil); err == nil {
+		t.Error("got no error for the missing topology file")
+	}
+
+	// nothing but the topology files is left in the download dirs
+	leftovers, _ := filepath.Glob(filepath.Join(tmpDir, "*", "*.tmp-*"))
+	if len(leftovers) != 0 {
+		t.Errorf("got temp files left after the downloads: %v", leftovers)
+	}
+}
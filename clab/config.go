@@ -10,10 +10,13 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/pmorjan/kmod"
+	"github.com/docker/go-connections/nat"
+	"github.com/dustin/go-humanize"
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/labels"
 	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/nodes"
@@ -31,6 +34,9 @@ const (
 	dockerNetIPv6Addr = "2001:172:20:20::/64"
 	// veth link mtu.
 	DefaultVethLinkMTU = 9500
+	// range of the oom_score_adj values accepted by the kernel.
+	oomScoreAdjMin = -1000
+	oomScoreAdjMax = 1000
 
 	// clab specific topology variables.
 	clabDirVar = "__clabDir__"
@@ -44,6 +50,8 @@ type Config struct {
 	Mgmt     *types.MgmtNet  `json:"mgmt,omitempty"`
 	Settings *types.Settings `json:"settings,omitempty"`
 	Topology *types.Topology `json:"topology,omitempty"`
+	// SSH selects the sources of the public keys added to the authorized keys of the nodes.
+	SSH *types.SSHConfig `json:"ssh,omitempty"`
 	// the debug flag value as passed via cli
 	// may be used by other packages to enable debug logging
 	Debug bool `json:"debug"`
@@ -65,6 +73,7 @@ func (c *CLab) parseTopology() error {
 
 	// initialize Nodes and Links variable
 	c.Nodes = make(map[string]nodes.Node)
+	c.DisabledNodes = make(map[string]nodes.Node)
 	c.Links = make(map[int]links.Link)
 
 	// initialize the Node information from the topology map
@@ -76,46 +85,34 @@ func (c *CLab) parseTopology() error {
 
 	// collect node runtimes in a map[NodeName] -> RuntimeName
 	nodeRuntimes := make(map[string]string)
-
-	for nodeName, topologyNode := range c.Config.Topology.Nodes {
-		// this case is when runtime was overridden at the node level
-		if r := c.Config.Topology.GetNodeRuntime(nodeName); r != "" {
-			nodeRuntimes[nodeName] = r
-			continue
-		}
-
-		// this case if for non-default runtimes overriding the global default
-		if r, ok := nodes.NonDefaultRuntimes[topologyNode.GetKind()]; ok {
-			nodeRuntimes[nodeName] = r
-			continue
-		}
-
-		// saving the global default runtime
-		nodeRuntimes[nodeName] = c.globalRuntime
+	for nodeName := range c.Config.Topology.Nodes {
+		nodeRuntimes[nodeName] = c.nodeRuntimeName(nodeName)
 	}
 
-	// initialize any extra runtimes
+	// initialize any extra runtimes, the nodes of the runtimes which are not registered
+	// are left without a runtime and are reported by CheckTopologyDefinition
 	for _, r := range nodeRuntimes {
 		// this is the case for already init'ed runtimes
-		if _, ok := c.Runtimes[r]; ok {
+		// and the nodes resolved without the runtimes
+		if _, ok := c.Runtimes[r]; ok || c.resolveOnly {
 			continue
 		}
 
-		if rInit, ok := clabRuntimes.ContainerRuntimes[r]; ok {
-
-			newRuntime := rInit()
-			defaultConfig := c.Runtimes[c.globalRuntime].Config()
-			err := newRuntime.Init(
-				clabRuntimes.WithConfig(&defaultConfig),
-			)
-			if err != nil {
-				return fmt.Errorf("failed to init the container runtime: %s", err)
-			}
+		if _, ok := clabRuntimes.ContainerRuntimes[r]; !ok {
+			log.Debugf("container runtime %q is not registered", r)
+			continue
+		}
 
-			c.Runtimes[r] = newRuntime
+		if err := c.initExtraRuntime(r); err != nil {
+			return err
 		}
 	}
 
+	c.licenseAssignments, err = c.assignLicensePools(nodeNames)
+	if err != nil {
+		return err
+	}
+
 	for idx, nodeName := range nodeNames {
 		err = c.NewNode(nodeName, nodeRuntimes[nodeName], c.Config.Topology.Nodes[nodeName], idx)
 		if err != nil {
@@ -126,6 +123,23 @@ func (c *CLab) parseTopology() error {
 	return nil
 }
 
+// nodeRuntimeName returns the name of the runtime of the node. The runtime set for the node
+// takes precedence over the runtime of its kind, if the kind requires a non-default one, and the global runtime.
+func (c *CLab) nodeRuntimeName(nodeName string) string {
+	// this case is when runtime was overridden at the node level
+	if r := c.Config.Topology.GetNodeRuntime(nodeName); r != "" {
+		return r
+	}
+
+	// this case if for non-default runtimes overriding the global default
+	if r, ok := nodes.NonDefaultRuntimes[c.Config.Topology.GetNodeKind(nodeName)]; ok {
+		return r
+	}
+
+	// saving the global default runtime
+	return c.globalRuntime
+}
+
 // NewNode initializes a new node object.
 func (c *CLab) NewNode(nodeName, nodeRuntime string, nodeDef *types.NodeDefinition, idx int) error {
 	nodeCfg, err := c.createNodeCfg(nodeName, nodeDef, idx)
@@ -146,7 +160,16 @@ func (c *CLab) NewNode(nodeName, nodeRuntime string, nodeDef *types.NodeDefiniti
 		return fmt.Errorf("failed to initialize node %q: %v", nodeCfg.ShortName, err)
 	}
 
-	c.Nodes[nodeName] = n
+	nodes.SetKindSysctls(n.Config(), n.DefaultSysctls())
+
+	c.applyNodeConfigOverrides(n.Config())
+
+	if c.Config.Topology.GetNodeEnabled(nodeName) {
+		c.Nodes[nodeName] = n
+	} else {
+		log.Infof("Node %q is disabled and will not be deployed", nodeName)
+		c.DisabledNodes[nodeName] = n
+	}
 
 	c.addDefaultLabels(n)
 
@@ -157,20 +180,19 @@ func (c *CLab) NewNode(nodeName, nodeRuntime string, nodeDef *types.NodeDefiniti
 
 func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx int) (*types.NodeConfig, error) {
 	// default longName follows $prefix-$lab-$nodeName pattern
-	longName := fmt.Sprintf("%s-%s-%s", *c.Config.Prefix, c.Config.Name, nodeName)
+	longName := c.longName(nodeName)
 
-	switch {
-	// when prefix is an empty string longName will match shortName/nodeName
-	case *c.Config.Prefix == "":
-		longName = nodeName
-	case *c.Config.Prefix == "__lab-name":
-		longName = fmt.Sprintf("%s-%s", c.Config.Name, nodeName)
+	// over-long container names are shortened when a user opted in and it is safe to do so,
+	// otherwise the topology check rejects them.
+	if c.autoShortenNames && len(longName) > maxNetnsNameLen && c.canShortenName(nodeName) {
+		longName = shortenName(longName, maxNetnsNameLen)
 	}
 
 	nodeCfg := &types.NodeConfig{
 		ShortName:       nodeName, // just the node name as seen in the topo file
 		LongName:        longName, // by default clab-$labName-$nodeName
-		Fqdn:            strings.Join([]string{nodeName, c.Config.Name, "io"}, "."),
+		Fqdn:            strings.Join([]string{c.hostname(nodeName), c.Config.Name, "io"}, "."),
+		Hostname:        c.hostname(nodeName),
 		LabDir:          c.TopoPaths.NodeDir(nodeName),
 		Index:           idx,
 		Group:           c.Config.Topology.GetNodeGroup(nodeName),
@@ -183,7 +205,7 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 		Entrypoint:      c.Config.Topology.GetNodeEntrypoint(nodeName),
 		Cmd:             c.Config.Topology.GetNodeCmd(nodeName),
 		Exec:            c.Config.Topology.GetNodeExec(nodeName),
-		Env:             c.Config.Topology.GetNodeEnv(nodeName),
+		ExecPhases:      c.Config.Topology.GetNodeExecPhases(nodeName),
 		NetworkMode:     strings.ToLower(c.Config.Topology.GetNodeNetworkMode(nodeName)),
 		MgmtIPv4Address: nodeDef.GetMgmtIPv4(),
 		MgmtIPv6Address: nodeDef.GetMgmtIPv6(),
@@ -195,25 +217,44 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 		CPU:             c.Config.Topology.GetNodeCPU(nodeName),
 		CPUSet:          c.Config.Topology.GetNodeCPUSet(nodeName),
 		Memory:          c.Config.Topology.GetNodeMemory(nodeName),
+		MemorySwap:      c.Config.Topology.GetNodeMemorySwap(nodeName),
+		CgroupParent:    c.Config.Topology.GetNodeCgroupParent(nodeName),
+		Platform:        c.Config.Topology.GetNodePlatform(nodeName),
+		OomKillDisable:  c.Config.Topology.GetNodeOomKillDisable(nodeName),
+		OomScoreAdj:     c.Config.Topology.GetNodeOomScoreAdj(nodeName),
+		Init:            c.Config.Topology.GetNodeInit(nodeName),
 		StartupDelay:    c.Config.Topology.GetNodeStartupDelay(nodeName),
+		StartupWait:     c.Config.Topology.GetNodeStartupWait(nodeName),
+		BootLog:         c.Config.Topology.GetNodeBootLog(nodeName),
 		AutoRemove:      c.Config.Topology.GetNodeAutoRemove(nodeName),
 		SANs:            c.Config.Topology.GetSANs(nodeName),
 		Extras:          c.Config.Topology.GetNodeExtras(nodeName),
 		WaitFor:         c.Config.Topology.GetWaitFor(nodeName),
 		DNS:             c.Config.Topology.GetNodeDns(nodeName),
+		Timezone:        c.Config.Topology.GetNodeTimezone(nodeName),
+		NTPServers:      c.Config.Topology.GetNodeNTPServers(nodeName),
 		Certificate:     c.Config.Topology.GetCertificateConfig(nodeName),
+		Console:         c.Config.Topology.GetNodeConsoleConfig(nodeName),
 	}
 
+	// the network mode is checked here to fail before any node gets deployed,
+	// the runtimes only process the valid modes at the container creation time
+	if err := types.ValidateNetworkMode(nodeCfg.NetworkMode); err != nil {
+		return nil, fmt.Errorf("node %q: %w", nodeName, err)
+	}
+	// the runtimes only handle the container:<name> mode, the phase is only used to schedule the node
+	nodeCfg.NetworkMode, nodeCfg.NetworkModePhase = types.SplitNetworkModePhase(nodeCfg.NetworkMode)
+	// the maximum time the nodes waiting for this node to become healthy wait for it
+	nodeCfg.HealthcheckTimeout = c.Config.Topology.GetNodeHealthcheckTimeout(nodeName)
+	// the health check overriding the one of the image
+	nodeCfg.Healthcheck = c.Config.Topology.GetNodeHealthcheck(nodeName)
+
 	var err error
 
-	// Load content of the EnvVarFiles
-	envFileContent, err := utils.LoadEnvVarFiles(c.TopoPaths.TopologyFileDir(),
-		c.Config.Topology.GetNodeEnvFiles(nodeName))
+	nodeCfg.Env, err = c.nodeEnv(nodeName)
 	if err != nil {
 		return nil, err
 	}
-	// Merge EnvVarFiles content and the existing env variable
-	nodeCfg.Env = utils.MergeStringMaps(envFileContent, nodeCfg.Env)
 
 	log.Debugf("node config: %+v", nodeCfg)
 
@@ -225,11 +266,45 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 
 	nodeCfg.EnforceStartupConfig = c.Config.Topology.GetNodeEnforceStartupConfig(nodeCfg.ShortName)
 	nodeCfg.SuppressStartupConfig = c.Config.Topology.GetNodeSuppressStartupConfig(nodeCfg.ShortName)
+	nodeCfg.StartupConfigBackups = c.Config.Settings.GetStartupConfigBackups()
+
+	// resolve the image archive path relative to the topology file
+	if p, ok := utils.ImageArchivePath(nodeCfg.Image); ok {
+		nodeCfg.Image = utils.ImageArchivePrefix + c.resolvePath(p)
+	}
+
+	// the node is created from the image built from the build context,
+	// the image is tagged with the node image when the build tag is not set
+	if b := c.Config.Topology.GetNodeImageBuild(nodeName); b != nil {
+		if b.Context != "" {
+			b.Context = c.resolvePath(b.Context)
+		}
+		if b.Tag == "" {
+			b.Tag = nodeCfg.Image
+		}
+		b.Force = c.forceBuild
+
+		nodeCfg.Image = b.Tag
+		nodeCfg.ImageBuild = b
+	}
 
 	// initialize license field
 	p := c.Config.Topology.GetNodeLicense(nodeCfg.ShortName)
 	// resolve the lic path to an abs path
-	nodeCfg.License = utils.ResolvePath(p, c.TopoPaths.TopologyFileDir())
+	nodeCfg.License = c.resolvePath(p)
+	// the license assigned from the kind license pool takes precedence over the kind and default licenses
+	if l, ok := c.licenseAssignments[nodeName]; ok {
+		nodeCfg.License = l
+	}
+
+	// resolve the paths of the externally issued tls material
+	if tc := c.Config.Topology.GetNodeTLSConfig(nodeCfg.ShortName); tc != nil {
+		nodeCfg.TLS = &types.TLSConfig{
+			Cert: c.resolvePath(tc.Cert),
+			Key:  c.resolvePath(tc.Key),
+			CA:   c.resolvePath(tc.CA),
+		}
+	}
 
 	// initialize bind mounts
 	binds, err := c.Config.Topology.GetNodeBinds(nodeName)
@@ -241,6 +316,9 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 		return nil, err
 	}
 	nodeCfg.Binds = binds
+
+	nodeCfg.Extras = c.resolveExtrasPaths(nodeCfg.Extras)
+
 	nodeCfg.PortSet, nodeCfg.PortBindings, err = c.Config.Topology.GetNodePorts(nodeName)
 	if err != nil {
 		return nil, err
@@ -248,11 +326,219 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 
 	nodeCfg.Labels = c.Config.Topology.GetNodeLabels(nodeCfg.ShortName)
 
+	err = processTimezone(nodeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.processConsole(nodeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.processExpose(nodeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOS kinds don't swap by default, so that a node exceeding its memory limit fails fast instead of thrashing
+	if _, ok := nodes.SwapDisabledKinds[nodeCfg.Kind]; ok && nodeCfg.MemorySwap == "" {
+		nodeCfg.MemorySwap = nodeCfg.Memory
+	}
+
 	nodeCfg.Config = c.Config.Topology.GetNodeConfigDispatcher(nodeCfg.ShortName)
 
 	return nodeCfg, nil
 }
 
+// processConsole publishes the serial console port of the node to the host when the console is exposed.
+// The host port is either set by the user or picked from the dynamic port range.
+// The host port is recorded in the node config and in the node labels.
+func (c *CLab) processConsole(nodeCfg *types.NodeConfig) error {
+	if !nodeCfg.Console.IsExposed() {
+		return nil
+	}
+
+	ctrPort, ok := nodes.ConsolePorts[nodeCfg.Kind]
+	if !ok {
+		return fmt.Errorf("node %q: console access is not supported by the %q kind", nodeCfg.ShortName, nodeCfg.Kind)
+	}
+
+	hostPort, err := c.consoleHostPort(nodeCfg.Console.HostPort, nodeCfg)
+	if err != nil {
+		return fmt.Errorf("node %q: %w", nodeCfg.ShortName, err)
+	}
+
+	port, err := nat.NewPort("tcp", strconv.Itoa(ctrPort))
+	if err != nil {
+		return err
+	}
+
+	if nodeCfg.PortSet == nil {
+		nodeCfg.PortSet = nat.PortSet{}
+	}
+
+	if nodeCfg.PortBindings == nil {
+		nodeCfg.PortBindings = nat.PortMap{}
+	}
+
+	nodeCfg.PortSet[port] = struct{}{}
+	nodeCfg.PortBindings[port] = append(nodeCfg.PortBindings[port], nat.PortBinding{HostPort: strconv.Itoa(hostPort)})
+
+	nodeCfg.ConsolePort = hostPort
+
+	if nodeCfg.Labels == nil {
+		nodeCfg.Labels = map[string]string{}
+	}
+
+	nodeCfg.Labels[labels.NodeConsolePort] = strconv.Itoa(hostPort)
+
+	log.Debugf("node %q: console port %d is published on the host port %d", nodeCfg.ShortName, ctrPort, hostPort)
+
+	return nil
+}
+
+// consoleHostPort returns the host port the console of the node is published on.
+// For the "auto" value a free port not used by other nodes is picked from the dynamic port range.
+func (c *CLab) consoleHostPort(hostPort string, nodeCfg *types.NodeConfig) (int, error) {
+	if hostPort != types.ConsoleHostPortAuto {
+		p, err := strconv.Atoi(hostPort)
+		if err != nil || p < 1 || p > 65535 {
+			return 0, fmt.Errorf("invalid console host port %q, expected a port number or %q",
+				hostPort, types.ConsoleHostPortAuto)
+		}
+
+		return p, nil
+	}
+
+	return utils.FreeTCPPort(utils.DynamicPortRangeStart, utils.DynamicPortRangeEnd, c.reservedHostPorts(nodeCfg))
+}
+
+// reservedHostPorts returns the host ports the consoles, the exposed services and the port bindings
+// of the lab nodes and of the node being configured are published on.
+func (c *CLab) reservedHostPorts(nodeCfg *types.NodeConfig) map[int]struct{} {
+	cfgs := []*types.NodeConfig{nodeCfg}
+	for _, nodeMap := range []map[string]nodes.Node{c.Nodes, c.DisabledNodes} {
+		for _, n := range nodeMap {
+			cfgs = append(cfgs, n.Config())
+		}
+	}
+
+	reserved := map[int]struct{}{}
+	for _, cfg := range cfgs {
+		if cfg.ConsolePort != 0 {
+			reserved[cfg.ConsolePort] = struct{}{}
+		}
+
+		for _, p := range cfg.ExposedPorts {
+			reserved[p] = struct{}{}
+		}
+
+		for _, bindings := range cfg.PortBindings {
+			for _, b := range bindings {
+				if p, err := strconv.Atoi(b.HostPort); err == nil {
+					reserved[p] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return reserved
+}
+
+// processExpose publishes the container ports of the services exposed by the node to the host ports
+// picked from the dynamic port range. The services inherited from the defaults which the kind doesn't provide
+// are skipped, while the services set for the node or its kind must be provided by the kind.
+// The service already published with a port binding keeps the host port of the binding.
+// The host ports are recorded in the node config and in the node labels.
+func (c *CLab) processExpose(nodeCfg *types.NodeConfig) error {
+	services, fromDefaults := c.Config.Topology.GetNodeExpose(nodeCfg.ShortName)
+	if len(services) == 0 || c.Reg.Kind(nodeCfg.Kind).Fields().Support("expose") == nodes.FieldIgnored {
+		return nil
+	}
+
+	for _, s := range services {
+		ctrPort, ok := nodes.ExposedServices[nodeCfg.Kind][s]
+		if !ok {
+			if fromDefaults {
+				log.Debugf("node %q: service %q exposed by the defaults is not provided by the %q kind",
+					nodeCfg.ShortName, s, nodeCfg.Kind)
+
+				continue
+			}
+
+			supported := nodes.KindExposedServices(nodeCfg.Kind)
+			if len(supported) == 0 {
+				return fmt.Errorf("node %q: service %q can't be exposed, the %q kind doesn't expose any services",
+					nodeCfg.ShortName, s, nodeCfg.Kind)
+			}
+
+			return fmt.Errorf("node %q: service %q can't be exposed by the %q kind, supported services are: %s",
+				nodeCfg.ShortName, s, nodeCfg.Kind, strings.Join(supported, ", "))
+		}
+
+		port, err := nat.NewPort("tcp", strconv.Itoa(ctrPort))
+		if err != nil {
+			return err
+		}
+
+		hostPort, err := c.exposedHostPort(nodeCfg, port)
+		if err != nil {
+			return fmt.Errorf("node %q: %w", nodeCfg.ShortName, err)
+		}
+
+		if nodeCfg.ExposedPorts == nil {
+			nodeCfg.ExposedPorts = map[string]int{}
+		}
+
+		nodeCfg.ExposedPorts[s] = hostPort
+
+		log.Debugf("node %q: %s port %d is published on the host port %d", nodeCfg.ShortName, s, ctrPort, hostPort)
+	}
+
+	if len(nodeCfg.ExposedPorts) == 0 {
+		return nil
+	}
+
+	if nodeCfg.Labels == nil {
+		nodeCfg.Labels = map[string]string{}
+	}
+
+	nodeCfg.Labels[labels.NodeExposedPorts] = types.FormatExposedPorts(nodeCfg.ExposedPorts)
+
+	return nil
+}
+
+// exposedHostPort returns the host port the container port of the exposed service is published on.
+// The port already published with a port binding keeps its host port, otherwise a free port
+// not used by other nodes is picked from the dynamic port range and the binding is added to the node.
+func (c *CLab) exposedHostPort(nodeCfg *types.NodeConfig, port nat.Port) (int, error) {
+	for _, b := range nodeCfg.PortBindings[port] {
+		if p, err := strconv.Atoi(b.HostPort); err == nil && p > 0 {
+			return p, nil
+		}
+	}
+
+	hostPort, err := utils.FreeTCPPort(utils.DynamicPortRangeStart, utils.DynamicPortRangeEnd,
+		c.reservedHostPorts(nodeCfg))
+	if err != nil {
+		return 0, err
+	}
+
+	if nodeCfg.PortSet == nil {
+		nodeCfg.PortSet = nat.PortSet{}
+	}
+
+	if nodeCfg.PortBindings == nil {
+		nodeCfg.PortBindings = nat.PortMap{}
+	}
+
+	nodeCfg.PortSet[port] = struct{}{}
+	nodeCfg.PortBindings[port] = append(nodeCfg.PortBindings[port], nat.PortBinding{HostPort: strconv.Itoa(hostPort)})
+
+	return hostPort, nil
+}
+
 // processStartupConfig processes the raw path of the startup-config as it is defined in the topology file.
 // It handles remote files, local files and embedded configs.
 // Returns an absolute path to the startup-config file.
@@ -266,7 +552,7 @@ func (c *CLab) processStartupConfig(nodeCfg *types.NodeConfig) error {
 	// downloadable config starts with http(s)://
 	isDownloadableConfig := utils.IsHttpUri(p)
 
-	if isEmbeddedConfig || isDownloadableConfig {
+	if (isEmbeddedConfig || isDownloadableConfig) && !c.resolveOnly {
 		// both embedded and downloadable configs are require clab tmp dir to be created
 		c.TopoPaths.CreateTmpDir()
 
@@ -303,30 +589,83 @@ func (c *CLab) processStartupConfig(nodeCfg *types.NodeConfig) error {
 			// adjust the nodeconfig by pointing startup-config to the local downloaded file
 			p = absDestFile
 		}
+	} else if isEmbeddedConfig {
+		// the lab resolved without side effects points the node to the file the config would be stored in
+		p = c.TopoPaths.StartupConfigDownloadFileAbsPath(nodeCfg.ShortName, "embedded.partial.cfg")
+	} else if isDownloadableConfig {
+		p = c.TopoPaths.StartupConfigDownloadFileAbsPath(nodeCfg.ShortName, utils.FilenameForURL(p))
 	}
 	// resolve the startup config path to an abs path
-	nodeCfg.StartupConfig = utils.ResolvePath(p, c.TopoPaths.TopologyFileDir())
+	nodeCfg.StartupConfig = c.resolvePath(p)
 
 	return nil
 }
 
+// topologyCheck is a check of the topology definition.
+type topologyCheck struct {
+	// name identifies the check in the lint report
+	name  string
+	check func() error
+}
+
+// definitionChecks returns the checks of the topology definition that need neither the node images
+// nor the deployed lab. They are shared by the deploy and the lint commands.
+func (c *CLab) definitionChecks() []topologyCheck {
+	return []topologyCheck{
+		{name: "runtimes", check: c.verifyNodeRuntimes},
+		{name: "links", check: c.verifyLinks},
+		{name: "root-netns-links", check: c.verifyRootNetNSLinks},
+		{name: "oom", check: c.verifyOomSettings},
+		{name: "platforms", check: c.verifyPlatforms},
+		{name: "image-pull-policies", check: c.verifyImagePullPolicies},
+		{name: "kind-fields", check: c.verifyKindFields},
+		{name: "tls", check: c.verifyNodesTLS},
+		{name: "image-builds", check: c.verifyImageBuilds},
+		{name: "disabled-nodes", check: c.verifyDisabledNodesReferences},
+		{name: "wait-for", check: c.verifyWaitFor},
+		{name: "healthchecks", check: c.verifyHealthchecks},
+		{name: "startup-waits", check: c.verifyStartupWaits},
+		{name: "duplicate-macs", check: c.verifyDuplicateMACs},
+		{name: "node-names", check: c.verifyNodeNames},
+		{name: "name-lengths", check: c.verifyNameLengths},
+	}
+}
+
 // CheckTopologyDefinition runs topology checks and returns any errors found.
 // This function runs after topology file is parsed and all nodes/links are initialized.
 func (c *CLab) CheckTopologyDefinition(ctx context.Context) error {
 	var err error
-	if err = c.verifyLinks(); err != nil {
+	for _, tc := range c.definitionChecks() {
+		if err = tc.check(); err != nil {
+			return err
+		}
+	}
+	// image pull errors are collected for all nodes
+	// to report all image problems at once before any container is created
+	pullErrs := clabRuntimes.ImagePullErrors{}
+	// the images built from the build contexts are not pulled
+	if err = c.buildImages(ctx); err != nil {
 		return err
 	}
-	if err = c.verifyRootNetNSLinks(); err != nil {
+	// the unique images of the nodes are pulled concurrently once
+	// instead of being pulled by every node one after another
+	if err = c.pullImages(ctx, pullErrs); err != nil {
 		return err
 	}
 	for _, node := range c.Nodes {
 		err := node.CheckDeploymentConditions(ctx)
 		if err != nil {
+			if pullErrs.Add(err) {
+				continue
+			}
 			return err
 		}
 	}
-	if err = c.verifyDuplicateAddresses(); err != nil {
+	if len(pullErrs) > 0 {
+		log.Error(pullErrs.Report())
+		return fmt.Errorf("failed to pull %d image(s) required by the lab", len(pullErrs))
+	}
+	if err = c.verifyMgmtAddresses(ctx); err != nil {
 		return err
 	}
 	if err = c.VerifyContainersUniqueness(ctx); err != nil {
@@ -335,6 +674,57 @@ func (c *CLab) CheckTopologyDefinition(ctx context.Context) error {
 	return nil
 }
 
+// verifyNodeRuntimes makes sure that the runtimes of all nodes are registered and initialized,
+// the nodes are listed by the runtime they reference.
+func (c *CLab) verifyNodeRuntimes() error {
+	missing := map[string][]string{}
+
+	for name, n := range c.Nodes {
+		r := c.nodeRuntimeName(name)
+		if _, ok := c.Runtimes[r]; ok && n.GetRuntime() != nil {
+			continue
+		}
+
+		missing[r] = append(missing[r], name)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	registered := make([]string, 0, len(clabRuntimes.ContainerRuntimes))
+	for name := range clabRuntimes.ContainerRuntimes {
+		registered = append(registered, name)
+	}
+
+	sort.Strings(registered)
+
+	rtNames := make([]string, 0, len(missing))
+	for r := range missing {
+		rtNames = append(rtNames, r)
+	}
+
+	sort.Strings(rtNames)
+
+	var errs []error
+
+	for _, r := range rtNames {
+		nodeNames := missing[r]
+		sort.Strings(nodeNames)
+
+		if r == "" {
+			errs = append(errs, fmt.Errorf("no container runtime is set for nodes %s",
+				strings.Join(nodeNames, ", ")))
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("container runtime %q of nodes %s is not registered, the registered runtimes are %s",
+			r, strings.Join(nodeNames, ", "), strings.Join(registered, ", ")))
+	}
+
+	return errors.Join(errs...)
+}
+
 // verifyRootNetNSLinks makes sure, that there will be no overlap in
 // interface names for Root Network Namespace bases nodes.
 func (c *CLab) verifyRootNetNSLinks() error {
@@ -370,6 +760,265 @@ func (c *CLab) verifyRootNetNSLinks() error {
 	return nil
 }
 
+// verifyPlatforms checks that the image platforms set for the nodes are in the os/arch[/variant] format.
+func (c *CLab) verifyPlatforms() error {
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if cfg.Platform == "" {
+			continue
+		}
+		if _, err := utils.ParsePlatform(cfg.Platform); err != nil {
+			return fmt.Errorf("node %q: %w", cfg.ShortName, err)
+		}
+	}
+	return nil
+}
+
+// verifyImagePullPolicies checks the image pull policies set in the defaults, kinds and nodes sections,
+// since the unknown values would otherwise fall back to IfNotPresent and an image meant to be re-pulled would not be.
+func (c *CLab) verifyImagePullPolicies() error {
+	t := c.Config.Topology
+
+	if err := types.ValidatePullPolicyValue(t.GetDefaults().GetImagePullPolicy()); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+
+	for kind, kdef := range t.Kinds {
+		if err := types.ValidatePullPolicyValue(kdef.GetImagePullPolicy()); err != nil {
+			return fmt.Errorf("kind %q: %w", kind, err)
+		}
+	}
+
+	for name, ndef := range t.Nodes {
+		if err := types.ValidatePullPolicyValue(ndef.GetImagePullPolicy()); err != nil {
+			return fmt.Errorf("node %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// kindFieldIssue is a field set for a node which is not supported by the node kind.
+type kindFieldIssue struct {
+	node  string
+	field string
+	// value is set when only this value of the field is not supported
+	value   string
+	kind    string
+	support nodes.FieldSupport
+}
+
+func (i kindFieldIssue) String() string {
+	if i.value != "" {
+		return fmt.Sprintf("node %q: field %q value %q is %s for kind %q", i.node, i.field, i.value, i.support, i.kind)
+	}
+
+	return fmt.Sprintf("node %q: field %q is %s for kind %q", i.node, i.field, i.support, i.kind)
+}
+
+// verifyKindFields checks the fields set for the nodes and their kinds in the topology file against
+// the support matrix of the node kinds. The forbidden fields are reported as errors
+// and the ignored ones as warnings.
+func (c *CLab) verifyKindFields() error {
+	var errs []error
+	for _, i := range c.kindFieldIssues() {
+		if i.support == nodes.FieldForbidden {
+			errs = append(errs, errors.New(i.String()))
+			continue
+		}
+
+		log.Warn(i.String())
+	}
+
+	return errors.Join(errs...)
+}
+
+// kindFieldIssues returns the ignored and forbidden fields set for the nodes and their kinds, ordered by the node name.
+// The defaults apply to all kinds and are not checked.
+func (c *CLab) kindFieldIssues() []kindFieldIssue {
+	t := c.Config.Topology
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []kindFieldIssue
+	for _, name := range names {
+		kind := c.Nodes[name].Config().Kind
+		fields := c.Reg.Kind(kind).Fields()
+
+		seen := map[string]struct{}{}
+		for _, def := range []*types.NodeDefinition{t.Nodes[name], t.GetKind(t.GetNodeKind(name))} {
+			for _, f := range def.SetFields() {
+				if _, ok := seen[f]; ok {
+					continue
+				}
+				seen[f] = struct{}{}
+
+				i := kindFieldIssue{node: name, field: f, kind: kind, support: fields.Support(f)}
+				if s := fields.ValueSupport(f, def.FieldValue(f)); s != i.support {
+					i.value, i.support = def.FieldValue(f), s
+				}
+
+				if i.support != nodes.FieldSupported {
+					issues = append(issues, i)
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// verifyNodesTLS checks that the externally issued tls material of the nodes exists
+// and that the keys match the certificates.
+func (c *CLab) verifyNodesTLS() error {
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if !cfg.TLS.IsSet() {
+			continue
+		}
+		if cfg.TLS.Cert == "" || cfg.TLS.Key == "" {
+			return fmt.Errorf("node %q: both tls cert and key must be set", cfg.ShortName)
+		}
+		if _, err := cert.NewCertificateFromExternalFiles(cfg.TLS.Cert, cfg.TLS.Key); err != nil {
+			return fmt.Errorf("node %q: %w", cfg.ShortName, err)
+		}
+		if cfg.TLS.CA == "" {
+			continue
+		}
+		if _, err := cert.LoadExternalCACert(cfg.TLS.CA); err != nil {
+			return fmt.Errorf("node %q: %w", cfg.ShortName, err)
+		}
+	}
+	return nil
+}
+
+// verifyOomSettings checks that the oom-score-adj value of the nodes is in the range accepted by the kernel
+// and that the nodes disabling the OOM killer or limiting the swap have the memory limit set.
+func (c *CLab) verifyOomSettings() error {
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if cfg.OomScoreAdj != nil && (*cfg.OomScoreAdj < oomScoreAdjMin || *cfg.OomScoreAdj > oomScoreAdjMax) {
+			return fmt.Errorf("node %q: oom-score-adj value %d is out of the allowed range [%d, %d]",
+				cfg.ShortName, *cfg.OomScoreAdj, oomScoreAdjMin, oomScoreAdjMax)
+		}
+		// without a memory limit the host processes may be killed instead of the node processes
+		if cfg.OomKillDisable && cfg.Memory == "" {
+			return fmt.Errorf("node %q: oom-kill-disable requires the memory limit to be set", cfg.ShortName)
+		}
+		if cfg.MemorySwap == "" {
+			continue
+		}
+		if cfg.Memory == "" {
+			return fmt.Errorf("node %q: memory-swap requires the memory limit to be set", cfg.ShortName)
+		}
+		if err := verifyMemorySwap(cfg.Memory, cfg.MemorySwap); err != nil {
+			return fmt.Errorf("node %q: %w", cfg.ShortName, err)
+		}
+	}
+	return nil
+}
+
+// verifyMemorySwap checks that the memory plus swap limit is not lower than the memory limit.
+func verifyMemorySwap(memory, memorySwap string) error {
+	swap, err := clabRuntimes.ParseMemorySwap(memorySwap)
+	if err != nil {
+		return err
+	}
+	if swap == -1 {
+		return nil
+	}
+	mem, err := humanize.ParseBytes(memory)
+	if err != nil {
+		return fmt.Errorf("failed to parse memory value %q: %w", memory, err)
+	}
+	if swap < int64(mem) {
+		return fmt.Errorf("memory-swap value %q is lower than the memory limit %q", memorySwap, memory)
+	}
+	return nil
+}
+
+// verifyDisabledNodesReferences makes sure that the enabled nodes do not reference the disabled nodes
+// in their wait-for and network-mode settings, since the disabled nodes are never deployed.
+func (c *CLab) verifyDisabledNodesReferences() error {
+	if len(c.DisabledNodes) == 0 {
+		return nil
+	}
+
+	nodeNames := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	var errs []error
+	for _, name := range nodeNames {
+		cfg := c.Nodes[name].Config()
+
+		for _, w := range cfg.WaitFor {
+			waitFor := w.Node
+			if _, ok := c.DisabledNodes[waitFor]; ok {
+				errs = append(errs, fmt.Errorf("node %q waits for node %q which is disabled and will never be deployed. "+
+					"Remove %q from the wait-for list of node %q or enable it", name, waitFor, waitFor, name))
+			}
+		}
+
+		netModeArr := strings.SplitN(cfg.NetworkMode, ":", 2)
+		if netModeArr[0] != "container" || len(netModeArr) != 2 {
+			continue
+		}
+		if _, ok := c.DisabledNodes[netModeArr[1]]; ok {
+			errs = append(errs, fmt.Errorf("node %q uses network namespace of node %q which is disabled and will never be deployed. "+
+				"Enable node %q or change the network-mode of node %q", name, netModeArr[1], netModeArr[1], name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyWaitFor makes sure that the wait-for entries of the nodes name the nodes
+// and use the known phases.
+func (c *CLab) verifyWaitFor() error {
+	var errs []error
+	for _, name := range c.sortedNodeNames() {
+		for _, w := range c.Nodes[name].Config().WaitFor {
+			if err := w.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("node %q: %w", name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyHealthchecks makes sure that the health checks of the nodes have the known test form
+// and no negative durations.
+func (c *CLab) verifyHealthchecks() error {
+	var errs []error
+	for _, name := range c.sortedNodeNames() {
+		if err := c.Nodes[name].Config().Healthcheck.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("node %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyStartupWaits makes sure that the log lines of the startup-wait conditions are valid regular expressions.
+func (c *CLab) verifyStartupWaits() error {
+	var errs []error
+	for _, name := range c.sortedNodeNames() {
+		if err := c.Nodes[name].Config().StartupWait.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("node %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // verifyLinks checks if all the endpoints in the links section of the topology file
 // appear only once.
 func (c *CLab) verifyLinks() error {
@@ -387,65 +1036,65 @@ func (c *CLab) verifyLinks() error {
 	return nil
 }
 
-// LoadKernelModules loads containerlab-required kernel modules.
-func (c *CLab) LoadKernelModules() error {
-	modules := []string{"ip_tables", "ip6_tables"}
-
-	for _, m := range modules {
-		isLoaded, err := utils.IsKernelModuleLoaded(m)
-		if err != nil {
-			return err
+// verifyDuplicateMACs checks that the MAC addresses of the node management interfaces and
+// the link endpoints are unique across the lab. The management MAC addresses are normalized.
+// The error lists every pair of the interfaces sharing a MAC address.
+func (c *CLab) verifyDuplicateMACs() error {
+	// owners maps a MAC address to the interfaces using it
+	owners := map[string][]string{}
+	var macs []string
+
+	add := func(mac, owner string) {
+		if _, ok := owners[mac]; !ok {
+			macs = append(macs, mac)
 		}
+		owners[mac] = append(owners[mac], owner)
+	}
 
-		if isLoaded {
-			log.Debugf("kernel module %q is already loaded", m)
+	nodeNames := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
 
+	for _, name := range nodeNames {
+		cfg := c.Nodes[name].Config()
+		if cfg.MacAddress == "" {
 			continue
 		}
 
-		log.Debugf("kernel module %q is not loaded. Trying to load", m)
-		// trying to load the kernel modules.
-		km, err := kmod.New()
-		if err != nil {
-			log.Warnf("Unable to init module loader: %v. Skipping...", err)
-
-			return nil
-		}
-
-		err = km.Load(m, "", 0)
+		m, err := utils.ParseInterfaceMAC(cfg.MacAddress)
 		if err != nil {
-			log.Warnf("Unable to load kernel module %q automatically %q", m, err)
-
-			return nil
+			return fmt.Errorf("node %s: %w", name, err)
 		}
 
-		log.Debugf("kernel module %q loaded successfully", m)
+		cfg.MacAddress = m.String()
+		add(cfg.MacAddress, name+" management interface")
 	}
 
-	return nil
-}
-
-// verifyDuplicateAddresses checks that every static IP address in the topology is unique.
-func (c *CLab) verifyDuplicateAddresses() error {
-	dupIps := map[string]struct{}{}
-	for _, node := range c.Nodes {
-		ips := []string{node.Config().MgmtIPv4Address, node.Config().MgmtIPv6Address}
-		if ips[0] == "" && ips[1] == "" {
+	for _, e := range c.Endpoints {
+		// macvlan host endpoint is the existing host interface the macvlan interface is created on
+		if _, ok := e.(*links.EndpointMacVlan); ok || e.GetMac() == nil {
 			continue
 		}
 
-		for _, ip := range ips {
-			if _, exists := dupIps[ip]; !exists {
-				if ip == "" {
-					continue
-				}
-				dupIps[ip] = struct{}{}
-			} else {
-				return fmt.Errorf("management IP address %s appeared more than once in the topology file", ip)
+		add(e.GetMac().String(), e.String())
+	}
+
+	var dups []string
+	for _, mac := range macs {
+		o := owners[mac]
+		for i := 0; i < len(o); i++ {
+			for j := i + 1; j < len(o); j++ {
+				dups = append(dups, fmt.Sprintf("%s: %s and %s", mac, o[i], o[j]))
 			}
 		}
 	}
 
+	if len(dups) > 0 {
+		return fmt.Errorf("duplicate MAC addresses found in the topology:\n%s", strings.Join(dups, "\n"))
+	}
+
 	return nil
 }
 
@@ -491,10 +1140,45 @@ func (c *CLab) VerifyContainersUniqueness(ctx context.Context) error {
 	return nil
 }
 
+// resolvePath resolves a user-provided path of the topology file by expanding `~` and the env vars
+// and resolving the relative path against the directory of the topology file.
+// All path fields of the nodes are resolved with it during the node initialization.
+func (c *CLab) resolvePath(p string) string {
+	return utils.ResolvePath(p, c.TopoPaths.TopologyFileDir())
+}
+
+// resolveExtrasPaths returns a copy of the node extras with the paths of the files
+// copied to the node resolved. The files referenced by a URL are left as is.
+// The extras are shared by the nodes inheriting them from the kind or the defaults, so they are not changed in place.
+func (c *CLab) resolveExtrasPaths(extras *types.Extras) *types.Extras {
+	if extras == nil {
+		return nil
+	}
+
+	resolved := *extras
+
+	resolved.SRLAgents = make([]string, 0, len(extras.SRLAgents))
+	for _, p := range extras.SRLAgents {
+		if !utils.IsHttpUri(p) {
+			p = c.resolvePath(p)
+		}
+
+		resolved.SRLAgents = append(resolved.SRLAgents, p)
+	}
+
+	resolved.CeosCopyToFlash = make([]string, 0, len(extras.CeosCopyToFlash))
+	for _, p := range extras.CeosCopyToFlash {
+		resolved.CeosCopyToFlash = append(resolved.CeosCopyToFlash, c.resolvePath(p))
+	}
+
+	return &resolved
+}
+
 // resolveBindPaths resolves the host paths in a bind string, such as /hostpath:/remotepath(:options) string
 // it allows host path to have `~` and relative path to an absolute path
 // the list of binds will be changed in place.
 // if the host path doesn't exist, the error will be returned.
+// binds with a named volume as a source are left as is.
 func (c *CLab) resolveBindPaths(binds []string, nodedir string) error {
 	for i := range binds {
 		// host path is a first element in a /hostpath:/remotepath(:options) string
@@ -503,15 +1187,23 @@ func (c *CLab) resolveBindPaths(binds []string, nodedir string) error {
 		// replace special variable
 		r := strings.NewReplacer(clabDirVar, c.TopoPaths.TopologyLabDir(), nodeDirVar, nodedir)
 		hp := r.Replace(elems[0])
-		hp = utils.ResolvePath(hp, c.TopoPaths.TopologyFileDir())
+		hp = c.resolvePath(hp)
 
 		_, err := os.Stat(hp)
+
+		// a source that looks like a volume name is still a host path if it exists
+		// next to the topology file, this keeps the binds like `configs:/configs` working
+		if b, berr := types.NewBind(binds[i]); berr == nil && b.IsVolume() && err != nil {
+			log.Debugf("bind %q uses the named volume %q", binds[i], b.Src())
+			continue
+		}
+
 		if err != nil {
-			// check if the hostpath mount has a reference to ansible-inventory.yml or topology-data.json
+			// check if the hostpath mount has a reference to ansible-inventory.yml or the topology data file
 			// if that is the case, we do not emit an error on missing file, since these files
 			// will be created by containerlab upon lab deployment
 			if hp != c.TopoPaths.AnsibleInventoryFileAbsPath() &&
-				hp != c.TopoPaths.TopoExportFile() {
+				hp != c.TopoPaths.TopoExportFile() && hp != c.TopoPaths.TopoExportMsgpackFile() {
 				return fmt.Errorf("failed to verify bind path: %v", err)
 			}
 		}
@@ -522,6 +1214,27 @@ func (c *CLab) resolveBindPaths(binds []string, nodedir string) error {
 	return nil
 }
 
+// nodeEnv returns the env vars of the node merged from the defaults, the kind and the node definitions
+// with the defaults < kinds < nodes precedence. At every level the env vars loaded from the env-files
+// are overridden by the env vars set with the env property of the same level.
+func (c *CLab) nodeEnv(nodeName string) (map[string]string, error) {
+	t := c.Config.Topology
+	levels := []*types.NodeDefinition{t.GetDefaults(), t.GetKind(t.GetNodeKind(nodeName)), t.Nodes[nodeName]}
+
+	var env map[string]string
+
+	for _, l := range levels {
+		fileEnv, err := utils.LoadEnvVarFiles(c.TopoPaths.TopologyFileDir(), l.GetEnvFiles())
+		if err != nil {
+			return nil, err
+		}
+
+		env = utils.MergeStringMaps(env, fileEnv, l.GetEnv())
+	}
+
+	return env, nil
+}
+
 // setClabIntfsEnvVar sets CLAB_INTFS env var for each node
 // which holds the number of interfaces a node expects to have (without mgmt interfaces).
 func (c *CLab) SetClabIntfsEnvVar() {
@@ -573,6 +1286,11 @@ func (c *CLab) addDefaultLabels(n nodes.Node) {
 	cfg.Labels[labels.NodeGroup] = cfg.Group
 	cfg.Labels[labels.NodeLabDir] = cfg.LabDir
 	cfg.Labels[labels.TopoFile] = c.TopoPaths.TopologyFilenameAbsPath()
+	cfg.Labels[labels.Owner] = utils.GetOwner()
+
+	if c.version != "" {
+		cfg.Labels[labels.Version] = c.version
+	}
 }
 
 // labelsToEnvVars adds labels to env vars with CLAB_LABEL_ prefix added
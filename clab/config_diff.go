@@ -0,0 +1,97 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// ConfigDrifted is the config diff status of the node which running config differs from the rendered one.
+	ConfigDrifted = "drifted"
+	// ConfigClean is the config diff status of the node which running config matches the rendered one.
+	ConfigClean = "clean"
+	// ConfigUnsupported is the config diff status of the node which kind doesn't support the config diff.
+	ConfigUnsupported = "unsupported"
+	// ConfigFailed is the config diff status of the node which configs failed to be retrieved.
+	ConfigFailed = "failed"
+)
+
+// NodeConfigDiff is the result of the config drift detection of a node.
+type NodeConfigDiff struct {
+	Node   string
+	Status string
+	// Diff is the unified diff of the rendered startup config and the running config.
+	Diff string
+	// Reason explains the unsupported and failed statuses.
+	Reason string
+}
+
+// DiffConfigs compares the running configs of the lab nodes with their startup configs
+// rendered from the topology. The results are sorted by the node name.
+func (c *CLab) DiffConfigs(ctx context.Context) []*NodeConfigDiff {
+	results := make([]*NodeConfigDiff, 0, len(c.Nodes))
+	mu := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+
+	wg.Add(len(c.Nodes))
+
+	for _, n := range c.Nodes {
+		go func(n nodes.Node) {
+			defer wg.Done()
+
+			r := diffNodeConfig(ctx, n)
+
+			mu.Lock()
+			results = append(results, r)
+			mu.Unlock()
+		}(n)
+	}
+
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Node < results[j].Node
+	})
+
+	return results
+}
+
+// diffNodeConfig compares the running config of the node n with its rendered startup config.
+func diffNodeConfig(ctx context.Context, n nodes.Node) *NodeConfigDiff {
+	name := n.Config().ShortName
+	r := &NodeConfigDiff{Node: name}
+
+	// the templates may refer to the runtime info, e.g. the mgmt addresses assigned by the runtime
+	if err := n.UpdateConfigWithRuntimeInfo(ctx); err != nil {
+		r.Status, r.Reason = ConfigFailed, err.Error()
+		return r
+	}
+
+	p, err := n.GetConfigPair(ctx)
+	switch {
+	case errors.Is(err, nodes.ErrConfigDiffNotSupported):
+		r.Status, r.Reason = ConfigUnsupported, err.Error()
+		return r
+	case err != nil:
+		r.Status, r.Reason = ConfigFailed, err.Error()
+		return r
+	}
+
+	r.Diff = utils.UnifiedDiff(p.Rendered, p.Running, name+"/rendered", name+"/running")
+
+	r.Status = ConfigClean
+	if r.Diff != "" {
+		r.Status = ConfigDrifted
+	}
+
+	return r
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pairs := map[string]struct {
+		pair *nodes.ConfigPair
+		err  error
+	}{
+		"clean":       {pair: &nodes.ConfigPair{Rendered: "a\nb\n", Running: "a\nb\n"}},
+		"drifted":     {pair: &nodes.ConfigPair{Rendered: "a\nb\n", Running: "a\nc\n"}},
+		"unsupported": {err: fmt.Errorf("%w for \"linux\" node kind", nodes.ErrConfigDiffNotSupported)},
+		"failed":      {err: errors.New("exec failed")},
+	}
+
+	c := &CLab{Nodes: map[string]nodes.Node{}}
+
+	for name, p := range pairs {
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().Config().Return(&types.NodeConfig{ShortName: name}).AnyTimes()
+		n.EXPECT().UpdateConfigWithRuntimeInfo(gomock.Any()).Return(nil)
+		n.EXPECT().GetConfigPair(gomock.Any()).Return(p.pair, p.err)
+
+		c.Nodes[name] = n
+	}
+
+	var got []string
+	for _, r := range c.DiffConfigs(context.Background()) {
+		got = append(got, r.Node+":"+r.Status)
+
+		if r.Status == ConfigDrifted && !strings.Contains(r.Diff, "-b\n+c\n") {
+			t.Errorf("unexpected diff of the drifted node:\n%s", r.Diff)
+		}
+	}
+
+	want := "clean:clean,drifted:drifted,failed:failed,unsupported:unsupported"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("got config diff statuses %q, want %q", strings.Join(got, ","), want)
+	}
+}
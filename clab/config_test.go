@@ -10,16 +10,24 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/containers/podman/v4/pkg/util"
+	"github.com/docker/go-connections/nat"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/labels"
 	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/runtime/docker"
+	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -382,11 +390,475 @@ func TestVerifyLinks(t *testing.T) {
 	}
 }
 
+func TestDisabledNodesAndLinks(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo13-disabled.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Nodes["n2"]; ok {
+		t.Error("disabled node n2 is part of the lab nodes")
+	}
+
+	if _, ok := c.DisabledNodes["n2"]; !ok {
+		t.Error("disabled node n2 is not part of the disabled nodes")
+	}
+
+	err = c.ResolveLinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the n1:eth2 <-> n3:eth2 link is expected to be resolved,
+	// since the other links are either disabled or touch the disabled node n2
+	if len(c.Links) != 1 {
+		t.Fatalf("got %d links, want 1", len(c.Links))
+	}
+
+	for _, l := range c.Links {
+		for _, e := range l.GetEndpoints() {
+			if e.GetIfaceName() != "eth2" {
+				t.Errorf("unexpected endpoint %s:%s", e.GetNode().GetShortName(), e.GetIfaceName())
+			}
+		}
+	}
+}
+
+func TestVerifyDisabledNodesReferences(t *testing.T) {
+	tests := map[string]struct {
+		topo    string
+		wantErr string
+	}{
+		"no references to disabled nodes": {
+			topo: "test_data/topo13-disabled.yml",
+		},
+		"wait-for a disabled node": {
+			topo:    "test_data/topo14-disabled-wait-for.yml",
+			wantErr: `node "n3" waits for node "n2" which is disabled`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoPath(tc.topo, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.verifyDisabledNodesReferences()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestVerifyNodeRuntimes(t *testing.T) {
+	tests := map[string]struct {
+		topo    string
+		wantErr string
+	}{
+		"registered runtimes": {
+			topo: "test_data/topo13-disabled.yml",
+		},
+		"unregistered runtime": {
+			topo:    "test_data/topo25-runtimes.yml",
+			wantErr: `container runtime "foo" of nodes n1, n2 is not registered`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(
+				WithTopoPath(tc.topo, ""),
+				WithRuntime(docker.RuntimeName, &runtime.RuntimeConfig{}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.verifyNodeRuntimes()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.wantErr)
+			assert.NotContains(t, err.Error(), "n3")
+		})
+	}
+}
+
+func TestVerifyImagePullPolicies(t *testing.T) {
+	tests := map[string]struct {
+		topo    string
+		wantErr string
+	}{
+		"default policies": {
+			topo: "test_data/topo1.yml",
+		},
+		"invalid node policy": {
+			topo:    "test_data/topo26-pull-policy.yml",
+			wantErr: `node "n2": invalid image pull policy "Allways"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoPath(tc.topo, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.verifyImagePullPolicies()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+
+	c, err := NewContainerLab(WithTopoPath("test_data/topo26-pull-policy.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the node policy overrides the policy of the kind, which overrides the defaults
+	assert.Equal(t, types.PullPolicyNever, c.Nodes["n1"].Config().ImagePullPolicy)
+	assert.Equal(t, types.PullPolicyAlways, c.Nodes["n3"].Config().ImagePullPolicy)
+}
+
+func TestConsoleInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo15-console.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	consolePort, err := nat.NewPort("tcp", strconv.Itoa(nodes.VrConsolePort))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sr1 and sr3 use auto host ports, which must be different and within the dynamic port range
+	autoPorts := map[int]string{}
+
+	for _, name := range []string{"sr1", "sr2", "sr3"} {
+		cfg := c.Nodes[name].Config()
+
+		if cfg.ConsolePort == 0 {
+			t.Fatalf("node %s: console port is not set", name)
+		}
+
+		if name == "sr2" {
+			assert.Equal(t, 5101, cfg.ConsolePort)
+		} else {
+			if cfg.ConsolePort < utils.DynamicPortRangeStart || cfg.ConsolePort > utils.DynamicPortRangeEnd {
+				t.Errorf("node %s: console port %d is outside of the dynamic port range", name, cfg.ConsolePort)
+			}
+
+			if other, ok := autoPorts[cfg.ConsolePort]; ok {
+				t.Errorf("nodes %s and %s got the same console port %d", other, name, cfg.ConsolePort)
+			}
+			autoPorts[cfg.ConsolePort] = name
+		}
+
+		assert.Equal(t, strconv.Itoa(cfg.ConsolePort), cfg.Labels[labels.NodeConsolePort])
+
+		if _, ok := cfg.PortSet[consolePort]; !ok {
+			t.Errorf("node %s: console port is not exposed", name)
+		}
+
+		assert.Equal(t, []nat.PortBinding{{HostPort: strconv.Itoa(cfg.ConsolePort)}}, cfg.PortBindings[consolePort])
+	}
+
+	// console of sr4 is not exposed
+	cfg := c.Nodes["sr4"].Config()
+	assert.Zero(t, cfg.ConsolePort)
+	assert.NotContains(t, cfg.Labels, labels.NodeConsolePort)
+
+	_, err = NewContainerLab(WithTopoPath("test_data/topo16-console-unsupported.yml", ""))
+	assert.ErrorContains(t, err, `console access is not supported by the "linux" kind`)
+}
+
+func TestExposeInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo29-expose.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the kinds map the services to their container ports on the registration
+	assert.Equal(t, map[string]int{"ssh": 22, "gnmi": 57400, "netconf": 830, "http": 80, "https": 443},
+		nodes.ExposedServices["nokia_srlinux"])
+	assert.Equal(t, 6030, nodes.ExposedServices["ceos"]["gnmi"])
+	assert.Equal(t, 830, nodes.ExposedServices["vr-sros"]["netconf"])
+	assert.Equal(t, []string{"ssh"}, nodes.KindExposedServices("linux"))
+
+	wantServices := map[string][]string{
+		// the kind services replace the defaults
+		"srl1": {"gnmi", "ssh"},
+		// the node services replace the kind services and keep the host port of the port binding
+		"srl2": {"netconf"},
+		// the empty list disables the exposure
+		"srl3": nil,
+		// the services of the defaults are inherited
+		"client": {"ssh"},
+		// the nodes not running in a container don't expose services
+		"br1": nil,
+	}
+
+	autoPorts := map[int]string{}
+
+	for name, want := range wantServices {
+		cfg := c.Nodes[name].Config()
+
+		var got []string
+		for s := range cfg.ExposedPorts {
+			got = append(got, s)
+		}
+		sort.Strings(got)
+
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("node %s exposed services mismatch (-want +got):\n%s", name, d)
+		}
+
+		if len(want) == 0 {
+			assert.NotContains(t, cfg.Labels, labels.NodeExposedPorts)
+			continue
+		}
+
+		assert.Equal(t, types.FormatExposedPorts(cfg.ExposedPorts), cfg.Labels[labels.NodeExposedPorts])
+
+		for s, hostPort := range cfg.ExposedPorts {
+			ctrPort, err := nat.NewPort("tcp", strconv.Itoa(nodes.ExposedServices[cfg.Kind][s]))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := cfg.PortSet[ctrPort]; !ok {
+				t.Errorf("node %s: %s port is not exposed", name, s)
+			}
+
+			if name == "srl2" {
+				assert.Equal(t, 8830, hostPort)
+				continue
+			}
+
+			if hostPort < utils.DynamicPortRangeStart || hostPort > utils.DynamicPortRangeEnd {
+				t.Errorf("node %s: %s host port %d is outside of the dynamic port range", name, s, hostPort)
+			}
+
+			if other, ok := autoPorts[hostPort]; ok {
+				t.Errorf("%s of node %s and %s got the same host port %d", s, name, other, hostPort)
+			}
+			autoPorts[hostPort] = name + " " + s
+
+			assert.Equal(t, []nat.PortBinding{{HostPort: strconv.Itoa(hostPort)}}, cfg.PortBindings[ctrPort])
+		}
+	}
+
+	_, err = NewContainerLab(WithTopoPath("test_data/topo30-expose-unsupported.yml", ""))
+	assert.ErrorContains(t, err, `service "gnmi" can't be exposed by the "linux" kind, supported services are: ssh`)
+}
+
+func TestMemorySwapInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo20-memory-swap.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOS kinds don't swap by default, the other kinds use the runtime default
+	assert.Equal(t, "4GiB", c.Nodes["sr1"].Config().MemorySwap)
+	assert.Equal(t, "-1", c.Nodes["sr2"].Config().MemorySwap)
+	assert.Equal(t, "", c.Nodes["client"].Config().MemorySwap)
+
+	assert.NoError(t, c.verifyOomSettings())
+
+	c.Nodes["client"].Config().MemorySwap = "512MiB"
+	assert.ErrorContains(t, c.verifyOomSettings(), `memory-swap value "512MiB" is lower than the memory limit "1GiB"`)
+
+	c.Nodes["client"].Config().Memory = ""
+	assert.ErrorContains(t, c.verifyOomSettings(), "memory-swap requires the memory limit to be set")
+
+	c.Nodes["client"].Config().MemorySwap = ""
+	c.Nodes["client"].Config().OomKillDisable = true
+	assert.ErrorContains(t, c.verifyOomSettings(), "oom-kill-disable requires the memory limit to be set")
+}
+
+func TestNodesTLSInit(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := cert.NewCA()
+	caCert, err := ca.GenerateCACert(&cert.CACSRInput{CommonName: "external-ca", KeySize: 2048, Expiry: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ca.SetCACert(caCert); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ca.pem"), caCert.Cert, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"srl1", "srl2"} {
+		nodeCert, err := ca.GenerateAndSignNodeCert(&cert.NodeCSRInput{CommonName: name, Hosts: []string{name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := nodeCert.Write(filepath.Join(dir, name+".pem"), filepath.Join(dir, name+".key"), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	topo := `name: tls
+topology:
+  defaults:
+    tls:
+      ca: ca.pem
+  nodes:
+    srl1:
+      kind: nokia_srlinux
+      image: ghcr.io/nokia/srlinux
+      tls:
+        cert: srl1.pem
+        key: srl1.key
+    srl2:
+      kind: nokia_srlinux
+      image: ghcr.io/nokia/srlinux
+`
+	topoPath := filepath.Join(dir, "tls.clab.yml")
+	if err := os.WriteFile(topoPath, []byte(topo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(topoPath, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the paths are resolved relative to the topology file
+	assert.Equal(t, &types.TLSConfig{
+		Cert: filepath.Join(dir, "srl1.pem"),
+		Key:  filepath.Join(dir, "srl1.key"),
+		CA:   filepath.Join(dir, "ca.pem"),
+	}, c.Nodes["srl1"].Config().TLS)
+
+	// srl2 has only the CA set
+	assert.ErrorContains(t, c.verifyNodesTLS(), `node "srl2": both tls cert and key must be set`)
+
+	c.Nodes["srl2"].Config().TLS = nil
+	assert.NoError(t, c.verifyNodesTLS())
+
+	// the key of another certificate
+	c.Nodes["srl1"].Config().TLS.Key = filepath.Join(dir, "srl2.key")
+	assert.ErrorContains(t, c.verifyNodesTLS(), "doesn't match certificate")
+
+	c.Nodes["srl1"].Config().TLS.Key = filepath.Join(dir, "missing.key")
+	assert.ErrorContains(t, c.verifyNodesTLS(), "failed loading key file")
+}
+
+func TestTimezoneInit(t *testing.T) {
+	dir := t.TempDir()
+
+	// fake zoneinfo database
+	zdir := filepath.Join(dir, "zoneinfo")
+	for _, tz := range []string{"Europe/Berlin", "America/New_York"} {
+		if err := os.MkdirAll(filepath.Join(zdir, filepath.Dir(tz)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(zdir, tz), []byte("TZif"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	origZoneinfoDir := zoneinfoDir
+	zoneinfoDir = zdir
+	defer func() { zoneinfoDir = origZoneinfoDir }()
+
+	topo := `name: tz
+topology:
+  defaults:
+    timezone: Europe/Berlin
+    ntp-servers:
+      - 192.0.2.1
+      - 192.0.2.2
+  nodes:
+    l1:
+      kind: linux
+      image: alpine:3
+    l2:
+      kind: linux
+      image: alpine:3
+      timezone: America/New_York
+      ntp-servers:
+        - ntp.example.com
+    l3:
+      kind: linux
+      image: alpine:3
+      env:
+        TZ: UTC
+      binds:
+        - zoneinfo/America/New_York:/etc/localtime:ro
+    srl1:
+      kind: nokia_srlinux
+      image: ghcr.io/nokia/srlinux
+`
+	topoPath := filepath.Join(dir, "tz.clab.yml")
+	if err := os.WriteFile(topoPath, []byte(topo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(topoPath, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l1 := c.Nodes["l1"].Config()
+	assert.Equal(t, "Europe/Berlin", l1.Timezone)
+	assert.Equal(t, "Europe/Berlin", l1.Env["TZ"])
+	assert.Contains(t, l1.Binds, filepath.Join(zdir, "Europe/Berlin")+":/etc/localtime:ro")
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, l1.NTPServers)
+
+	// the node overrides the defaults
+	l2 := c.Nodes["l2"].Config()
+	assert.Equal(t, "America/New_York", l2.Env["TZ"])
+	assert.Contains(t, l2.Binds, filepath.Join(zdir, "America/New_York")+":/etc/localtime:ro")
+	assert.Equal(t, []string{"ntp.example.com"}, l2.NTPServers)
+
+	// the env var and the bind set by the user are kept
+	l3 := c.Nodes["l3"].Config()
+	assert.Equal(t, "UTC", l3.Env["TZ"])
+	assert.Equal(t, []string{filepath.Join(zdir, "America/New_York") + ":/etc/localtime:ro"}, l3.Binds)
+
+	// NOS kinds only get the ntp servers for their config templates
+	srl1 := c.Nodes["srl1"].Config()
+	assert.NotContains(t, srl1.Binds, filepath.Join(zdir, "Europe/Berlin")+":/etc/localtime:ro")
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, srl1.NTPServers)
+
+	// the time zone is checked against the zoneinfo database
+	for _, tz := range []string{"Mars/Olympus_Mons", "../zoneinfo/Europe/Berlin", "/etc/passwd", "Europe"} {
+		_, err := zoneinfoFile(tz)
+		assert.Error(t, err, tz)
+	}
+
+	bad := strings.Replace(topo, "timezone: America/New_York", "timezone: Mars/Olympus_Mons", 1)
+	if err := os.WriteFile(topoPath, []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewContainerLab(WithTopoPath(topoPath, ""))
+	assert.ErrorContains(t, err, `node "l2": unknown timezone "Mars/Olympus_Mons"`)
+}
+
 func TestLabelsInit(t *testing.T) {
 	tests := map[string]struct {
-		got  string
-		node string
-		want map[string]string
+		got     string
+		node    string
+		version string
+		want    map[string]string
 	}{
 		"only_default_labels": {
 			got:  "test_data/topo1.yml",
@@ -415,6 +887,21 @@ func TestLabelsInit(t *testing.T) {
 				"node-label":        "value",
 			},
 		},
+		"version_label": {
+			got:     "test_data/topo1.yml",
+			node:    "node1",
+			version: "0.48.1",
+			want: map[string]string{
+				labels.Containerlab: "topo1",
+				labels.NodeName:     "node1",
+				labels.NodeKind:     "srl",
+				labels.NodeType:     "ixrd2",
+				labels.NodeGroup:    "",
+				labels.NodeLabDir:   "../clab-topo1/node1",
+				labels.TopoFile:     "topo1.yml",
+				labels.Version:      "0.48.1",
+			},
+		},
 		"custom_kind_label": {
 			got:  "test_data/topo2.yml",
 			node: "node1",
@@ -451,6 +938,7 @@ func TestLabelsInit(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			opts := []ClabOption{
+				WithVersion(tc.version),
 				WithTopoPath(tc.got, ""),
 			}
 			c, err := NewContainerLab(opts...)
@@ -460,6 +948,7 @@ func TestLabelsInit(t *testing.T) {
 
 			tc.want[labels.NodeLabDir] = utils.ResolvePath(tc.want[labels.NodeLabDir], c.TopoPaths.TopologyFileDir())
 			tc.want[labels.TopoFile] = utils.ResolvePath(tc.want[labels.TopoFile], c.TopoPaths.TopologyFileDir())
+			tc.want[labels.Owner] = utils.GetOwner()
 
 			labels := c.Nodes[tc.node].Config().Labels
 
@@ -530,6 +1019,73 @@ func TestVerifyRootNetNSLinks(t *testing.T) {
 	}
 }
 
+func TestVerifyDuplicateMACs(t *testing.T) {
+	tests := map[string]struct {
+		topo string
+		// wantMACs are the normalized MAC addresses of the endpoints
+		wantMACs map[string]string
+		// wantResolveErr is the error returned by the links resolution
+		wantResolveErr string
+		wantErr        string
+	}{
+		"normalized": {
+			topo: "test_data/topo17-macs.yml",
+			wantMACs: map[string]string{
+				"l1:eth1": "00:1c:73:00:00:01",
+				"l2:eth1": "00:1c:73:00:00:02",
+				"l1:eth2": "00:1c:73:00:00:03",
+				"l3:eth1": "00:1c:73:00:00:04",
+			},
+		},
+		"duplicates": {
+			topo: "test_data/topo18-dup-macs.yml",
+			wantErr: "duplicate MAC addresses found in the topology:\n" +
+				"00:1c:73:00:00:01: l1:eth1 and l2:eth1\n" +
+				"00:1c:73:00:00:01: l1:eth1 and l1:eth2\n" +
+				"00:1c:73:00:00:01: l2:eth1 and l1:eth2\n" +
+				"00:1c:73:00:00:02: l3:eth1 and l2:eth2",
+		},
+		"invalid": {
+			topo:           "test_data/topo19-invalid-mac.yml",
+			wantResolveErr: "endpoint l1:eth1: address 00:1c:73:zz:00:01: invalid MAC address",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoPath(tc.topo, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.ResolveLinks()
+			if tc.wantResolveErr != "" {
+				if err == nil || err.Error() != tc.wantResolveErr {
+					t.Fatalf("wanted resolve error %q, got %v", tc.wantResolveErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, e := range c.Endpoints {
+				if want, ok := tc.wantMACs[e.String()]; ok && e.GetMac().String() != want {
+					t.Errorf("endpoint %s: got MAC %s, want %s", e, e.GetMac(), want)
+				}
+			}
+
+			err = c.verifyDuplicateMACs()
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr):
+				t.Fatalf("wanted error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestVerifyContainersUniqueness(t *testing.T) {
 	tests := map[string]struct {
 		mockResult struct {
@@ -658,6 +1214,33 @@ func TestEnvFileInit(t *testing.T) {
 				"ENVFILE2": "THISANDTHAT",
 			},
 		},
+		"defaults_env_overrides_defaults_env-file": {
+			got:  "test_data/topo24-env-precedence.yml",
+			node: "node1",
+			want: map[string]string{
+				"ENVFILE1": "defaults",
+				"ENVFILE2": "defaults",
+				"KIND_ENV": "kind",
+			},
+		},
+		"node_env-file_overrides_defaults_env": {
+			got:  "test_data/topo24-env-precedence.yml",
+			node: "node2",
+			want: map[string]string{
+				"ENVFILE1": "SOMEENVVARDATA",
+				"ENVFILE2": "defaults",
+				"KIND_ENV": "kind",
+			},
+		},
+		"node_env_overrides_node_env-file_and_kind_env": {
+			got:  "test_data/topo24-env-precedence.yml",
+			node: "node3",
+			want: map[string]string{
+				"ENVFILE1": "node",
+				"ENVFILE2": "defaults",
+				"KIND_ENV": "node",
+			},
+		},
 	}
 
 	teardownTestCase := setupTestCase(t)
@@ -728,3 +1311,61 @@ func TestSuppressConfigInit(t *testing.T) {
 		})
 	}
 }
+
+func TestKindFieldIssues(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo27-kind-fields.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the license set in the defaults applies to all kinds and is not reported
+	want := []kindFieldIssue{
+		{node: "linux1", field: "license", kind: "linux", support: nodes.FieldIgnored},
+		{node: "linux1", field: "ntp-servers", kind: "linux", support: nodes.FieldIgnored},
+		{node: "srl1", field: "sandbox", kind: "srl", support: nodes.FieldIgnored},
+		{node: "srl2", field: "network-mode", value: "host", kind: "srl", support: nodes.FieldForbidden},
+		{node: "sros1", field: "network-mode", kind: "vr-sros", support: nodes.FieldForbidden},
+		{node: "sros1", field: "timezone", kind: "vr-sros", support: nodes.FieldIgnored},
+	}
+
+	if d := cmp.Diff(want, c.kindFieldIssues(), cmp.AllowUnexported(kindFieldIssue{})); d != "" {
+		t.Errorf("kind field issues diff (-want +got):\n%s", d)
+	}
+
+	err = c.verifyKindFields()
+	assert.ErrorContains(t, err, `node "srl2": field "network-mode" value "host" is forbidden for kind "srl"`)
+	assert.ErrorContains(t, err, `node "sros1": field "network-mode" is forbidden for kind "vr-sros"`)
+	assert.NotContains(t, err.Error(), "ignored")
+}
+
+func TestResolveNodePaths(t *testing.T) {
+	t.Setenv("SUDO_UID", "")
+	os.Unsetenv("SUDO_UID")
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("home dir is unknown: %v", err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath("test_data/topo28-paths.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topoDir := c.TopoPaths.TopologyFileDir()
+
+	srl1 := c.Nodes["srl1"].Config()
+	assert.Equal(t, filepath.Join(home, "licenses/srl.key"), srl1.License)
+	assert.Equal(t, filepath.Join(topoDir, "srl1.cfg"), srl1.StartupConfig)
+
+	wantAgents := []string{filepath.Join(topoDir, "agents/agent.yml"), "https://example.com/agents/agent2.yml"}
+	assert.Equal(t, wantAgents, srl1.Extras.SRLAgents)
+	assert.Equal(t, wantAgents, c.Nodes["srl2"].Config().Extras.SRLAgents)
+
+	// the extras of the kind shared by the nodes are not changed
+	assert.Equal(t, []string{"agents/agent.yml", "https://example.com/agents/agent2.yml"},
+		c.Config.Topology.Kinds["srl"].Extras.SRLAgents)
+
+	assert.Equal(t, []string{filepath.Join(home, "flash/ceos-config")},
+		c.Nodes["ceos1"].Config().Extras.CeosCopyToFlash)
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// containerSpecFileName is the name of the file in the node's lab directory
+// the container spec of the node is written to.
+const containerSpecFileName = "container-spec.json"
+
+// dumpContainerSpec writes the spec the runtime of the node submits to create the node container
+// to the container-spec.json file in the node's lab directory.
+// The nodes without an image, e.g. bridges or the host, have no container and are skipped.
+func (c *CLab) dumpContainerSpec(ctx context.Context, node nodes.Node) error {
+	cfg := node.Config()
+	if cfg.Image == "" {
+		log.Debugf("node %q has no container, skipping its container spec", cfg.ShortName)
+		return nil
+	}
+
+	spec, err := node.GetRuntime().GetContainerSpec(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(cfg.LabDir, 0755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	path := filepath.Join(cfg.LabDir, containerSpecFileName)
+	if err := os.WriteFile(path, append(b, '\n'), 0644); err != nil { // skipcq: GSC-G306
+		return err
+	}
+
+	log.Infof("Container spec of node %q written to %s", cfg.ShortName, path)
+
+	return nil
+}
+
+// hasContainerSpecs returns true if the container spec of a node was written to the lab directory,
+// i.e. the lab directory was created by the deployment with the dumped container specs.
+func hasContainerSpecs(labDir string) bool {
+	specs, _ := filepath.Glob(filepath.Join(labDir, "*", containerSpecFileName))
+
+	return len(specs) > 0
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestDumpContainerSpec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	dir := t.TempDir()
+
+	cfg := &types.NodeConfig{
+		ShortName: "r1",
+		LongName:  "clab-test-r1",
+		Image:     "alpine:3",
+		LabDir:    filepath.Join(dir, "r1"),
+	}
+
+	spec := map[string]interface{}{"name": cfg.LongName, "image": cfg.Image}
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().GetContainerSpec(gomock.Any(), cfg).Return(spec, nil)
+
+	n := mocknodes.NewMockNode(ctrl)
+	n.EXPECT().Config().Return(cfg).AnyTimes()
+	n.EXPECT().GetRuntime().Return(rt)
+
+	c := &CLab{}
+
+	if err := c.dumpContainerSpec(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(cfg.LabDir, containerSpecFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\n  \"image\": \"alpine:3\",\n  \"name\": \"clab-test-r1\"\n}\n"
+	if string(got) != want {
+		t.Errorf("got spec file %q, want %q", got, want)
+	}
+
+	// the nodes without a container are skipped
+	bridge := mocknodes.NewMockNode(ctrl)
+	bridge.EXPECT().Config().Return(&types.NodeConfig{ShortName: "br1", LabDir: filepath.Join(dir, "br1")}).AnyTimes()
+
+	if err := c.dumpContainerSpec(context.Background(), bridge); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "br1")); !os.IsNotExist(err) {
+		t.Errorf("got the lab directory of the node without a container created, err %v", err)
+	}
+}
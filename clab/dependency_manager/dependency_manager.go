@@ -14,6 +14,9 @@ type DependencyManager interface {
 	// AddDependency adds a dependency between depender and dependee.
 	// The depender will effectively wait for the dependee to finish.
 	AddDependency(dependee, depender string) error
+	// AddStateDependency adds a dependency between depender and dependee,
+	// such that the depender waits for the dependee to reach the provided state.
+	AddStateDependency(dependee, depender string, state NodeState) error
 	// WaitForNodeDependencies is called by a node that is meant to be created.
 	// This call will bock until all the nodes that this node depends on are created.
 	WaitForNodeDependencies(nodeName string) error
@@ -32,17 +35,22 @@ type NodeState int
 
 const (
 	NodeStateCreated NodeState = iota
+	// NodeStateConfigured is reached when the post-deploy configuration of the node is done.
+	NodeStateConfigured
+	// NodeStateExited is reached when the container of the node exits.
+	NodeStateExited
 	// dependency is a special state that is used to indicate that a node depends on other node.
 	dependency = 99
 )
 
-var RegularNodeStates = []NodeState{NodeStateCreated}
+var RegularNodeStates = []NodeState{NodeStateCreated, NodeStateConfigured, NodeStateExited}
 
 // dependencyNode is the representation of a node in the dependency concept.
 type dependencyNode struct {
-	name          string
-	WaitState     map[NodeState]*sync.WaitGroup
-	nodeDependers map[string]*dependencyNode
+	name      string
+	WaitState map[NodeState]*sync.WaitGroup
+	// nodeDependers are the nodes waiting for this node to reach the state, keyed by the state
+	nodeDependers map[NodeState]map[string]*dependencyNode
 	m             sync.Mutex
 }
 
@@ -53,7 +61,7 @@ func newDependencyNode(name string) *dependencyNode {
 		// WaitState is initialized with a wait group for each node state.
 		// WaitState is used to for a dependee to wait for a depender to reach a certain state.
 		WaitState:     map[NodeState]*sync.WaitGroup{},
-		nodeDependers: map[string]*dependencyNode{},
+		nodeDependers: map[NodeState]map[string]*dependencyNode{},
 	}
 
 	// node states must be initialized,
@@ -91,8 +99,8 @@ func (d *dependencyNode) Done(n NodeState) error {
 	wg.Done()
 
 	// special handling of dependencies
-	if n == NodeStateCreated {
-		for _, d := range d.nodeDependers {
+	if n != dependency {
+		for _, d := range d.nodeDependers[n] {
 			d.Done(dependency)
 		}
 	}
@@ -119,6 +127,12 @@ func (dm *defaultDependencyManager) AddNode(name string) {
 // AddDependency adds a dependency between depender and dependee.
 // The depender will effectively wait for the dependee to finish.
 func (dm *defaultDependencyManager) AddDependency(dependee, depender string) error {
+	return dm.AddStateDependency(dependee, depender, NodeStateCreated)
+}
+
+// AddStateDependency adds a dependency between depender and dependee,
+// such that the depender waits for the dependee to reach the provided state.
+func (dm *defaultDependencyManager) AddStateDependency(dependee, depender string, state NodeState) error {
 	// first check if the referenced nodes are known to the dm
 	depder, exists := dm.nodes[depender]
 	if !exists {
@@ -129,18 +143,46 @@ func (dm *defaultDependencyManager) AddDependency(dependee, depender string) err
 		return fmt.Errorf("node %q is not known to the dependency manager", dependee)
 	}
 
-	depdee.addDepender(depder)
+	depdee.addDepender(depder, state)
 	return nil
 }
 
-// addDepender adds a depender to the dependencyNode. This will also add the dependee to the depender.
-// to increase the waitgroup count for the depender.
-func (d *dependencyNode) addDepender(depender *dependencyNode) error {
-	d.nodeDependers[depender.name] = depender
+// addDepender adds a depender waiting for the dependencyNode to reach the state. This will also add the dependee
+// to the depender to increase the waitgroup count for the depender. The repeated dependencies are added once,
+// as the depender is notified once the state is reached.
+func (d *dependencyNode) addDepender(depender *dependencyNode, state NodeState) error {
+	if _, exists := d.nodeDependers[state]; !exists {
+		d.nodeDependers[state] = map[string]*dependencyNode{}
+	}
+
+	if _, exists := d.nodeDependers[state][depender.name]; exists {
+		return nil
+	}
+
+	d.nodeDependers[state][depender.name] = depender
 	depender.addDependee()
 	return nil
 }
 
+// dependerNames returns the names of the nodes waiting for the node to reach any of the states.
+func (d *dependencyNode) dependerNames() []string {
+	seen := map[string]struct{}{}
+	var names []string
+
+	for _, s := range RegularNodeStates {
+		for name := range d.nodeDependers[s] {
+			if _, ok := seen[name]; ok {
+				continue
+			}
+
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
 // addDependee is an internal call used to increase the Dependee WaitGroup.
 func (d *dependencyNode) addDependee() {
 	d.getStateWG(dependency).Add(1)
@@ -210,9 +252,7 @@ func (dm *defaultDependencyManager) String() string {
 
 	// build the dependency datastruct
 	for nodeName, node := range dm.nodes {
-		for _, depender := range node.nodeDependers {
-			dependencies[nodeName] = append(dependencies[nodeName], depender.name)
-		}
+		dependencies[nodeName] = append(dependencies[nodeName], node.dependerNames()...)
 	}
 
 	var result []string
@@ -229,11 +269,9 @@ func (dm *defaultDependencyManager) CheckAcyclicity() error {
 
 	nodeDependers := map[string][]string{}
 
+	// the dependencies of all the states are considered, as the dependers wait for any of them
 	for _, n := range dm.nodes {
-		nodeDependers[n.name] = []string{}
-		for _, dep := range n.nodeDependers {
-			nodeDependers[n.name] = append(nodeDependers[n.name], dep.name)
-		}
+		nodeDependers[n.name] = append([]string{}, n.dependerNames()...)
 	}
 
 	if !isAcyclic(nodeDependers, 1) {
@@ -2,6 +2,7 @@ package dependency_manager
 
 import (
 	"testing"
+	"time"
 )
 
 func Test_recursiveAcyclicityCheck(t *testing.T) {
@@ -97,3 +98,79 @@ func Test_recursiveAcyclicityCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestStateDependencies(t *testing.T) {
+	dm := NewDependencyManager()
+	for _, n := range []string{"router", "client", "job"} {
+		dm.AddNode(n)
+	}
+
+	if err := dm.AddStateDependency("router", "client", NodeStateConfigured); err != nil {
+		t.Fatal(err)
+	}
+
+	// the repeated dependencies are satisfied at once
+	for i := 0; i < 2; i++ {
+		if err := dm.AddDependency("router", "client"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := dm.AddStateDependency("job", "client", NodeStateExited); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dm.CheckAcyclicity(); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		dm.WaitForNodeDependencies("client")
+		close(done)
+	}()
+
+	for _, s := range []struct {
+		node  string
+		state NodeState
+	}{
+		{"router", NodeStateCreated},
+		{"job", NodeStateCreated},
+		{"router", NodeStateConfigured},
+	} {
+		dm.SignalDone(s.node, s.state)
+
+		select {
+		case <-done:
+			t.Fatalf("client is released once %s reached state %d", s.node, s.state)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	dm.SignalDone("job", NodeStateExited)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("client is not released once all its dependencies are satisfied")
+	}
+}
+
+func TestStateDependenciesCycle(t *testing.T) {
+	dm := NewDependencyManager()
+	dm.AddNode("a")
+	dm.AddNode("b")
+
+	// the cycles spanning the dependencies of different states are detected too
+	if err := dm.AddDependency("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dm.AddStateDependency("b", "a", NodeStateExited); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dm.CheckAcyclicity(); err == nil {
+		t.Error("cyclic dependencies are not detected")
+	}
+}
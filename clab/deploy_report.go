@@ -0,0 +1,168 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// NodeStatusCreated is the deploy report status of the node that was deployed.
+	NodeStatusCreated = "created"
+	// NodeStatusFailed is the deploy report status of the node that failed to deploy.
+	NodeStatusFailed = "failed"
+)
+
+// DeployReport is the machine-readable result of the lab deployment.
+type DeployReport struct {
+	Name     string        `json:"name"`
+	Started  time.Time     `json:"started"`
+	Finished time.Time     `json:"finished"`
+	Duration string        `json:"duration"`
+	Nodes    []*NodeReport `json:"nodes"`
+	// Exports are the results of writing the topology data export to the export targets.
+	Exports []*ExportTargetReport `json:"exports,omitempty"`
+
+	mu sync.Mutex
+	// nodes holds the deployment progress of the nodes collected from the lifecycle events
+	nodes map[string]*nodeDeployRecord
+}
+
+// NodeReport is the deployment result of a lab node.
+type NodeReport struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Image       string `json:"image"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+	ContainerID string `json:"container-id,omitempty"`
+	IPv4Address string `json:"ipv4-address,omitempty"`
+	IPv6Address string `json:"ipv6-address,omitempty"`
+	// Duration is the time it took to create the node, starting from its first lifecycle event.
+	Duration string `json:"duration,omitempty"`
+	// Exec is the results of the exec commands run on the node keyed by the node lifecycle phase.
+	Exec map[string][]*exec.ExecResult `json:"exec,omitempty"`
+}
+
+type nodeDeployRecord struct {
+	started time.Time
+	created time.Time
+	err     error
+}
+
+// NewDeployReport returns the deploy report that starts at the current time.
+// The report collects the nodes deployment progress with its HandleEvent lifecycle hook.
+func NewDeployReport() *DeployReport {
+	return &DeployReport{
+		Started: time.Now(),
+		nodes:   map[string]*nodeDeployRecord{},
+	}
+}
+
+// HandleEvent records the node phase changes, it is registered as the lab lifecycle hook.
+func (r *DeployReport) HandleEvent(ev LifecycleEvent) {
+	if ev.Type != LifecycleEventNodePhase {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.nodes[ev.Node]
+	if !ok {
+		rec = &nodeDeployRecord{started: ev.Time}
+		r.nodes[ev.Node] = rec
+	}
+
+	switch ev.Phase {
+	case NodePhaseCreated:
+		rec.created = ev.Time
+	case NodePhaseFailed:
+		if rec.err == nil {
+			rec.err = ev.Err
+		}
+	}
+}
+
+// Complete fills the report with the results of the lab nodes deployment.
+// containers are the lab containers used to get the assigned addresses,
+// execs are the results of the nodes exec commands.
+func (r *DeployReport) Complete(c *CLab, containers []runtime.GenericContainer, execs *exec.ExecCollection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Name = c.Config.Name
+	r.Finished = time.Now()
+	r.Duration = r.Finished.Sub(r.Started).Round(time.Millisecond).String()
+
+	ctrs := map[string]*runtime.GenericContainer{}
+	for i := range containers {
+		ctrs[containers[i].Labels[labels.NodeName]] = &containers[i]
+	}
+
+	r.Nodes = make([]*NodeReport, 0, len(c.Nodes))
+
+	for name, n := range c.Nodes {
+		nr := &NodeReport{
+			Name:   name,
+			Kind:   n.Config().Kind,
+			Image:  n.Config().Image,
+			Status: NodeStatusFailed,
+		}
+
+		if rec, ok := r.nodes[name]; ok {
+			switch {
+			case rec.err != nil:
+				nr.Error = rec.err.Error()
+			case !rec.created.IsZero():
+				nr.Status = NodeStatusCreated
+				nr.Duration = rec.created.Sub(rec.started).Round(time.Millisecond).String()
+			}
+		}
+
+		if ctr, ok := ctrs[name]; ok {
+			nr.ContainerID = ctr.ShortID
+			nr.IPv4Address = ctr.NetworkSettings.IPv4addr
+			nr.IPv6Address = ctr.NetworkSettings.IPv6addr
+		}
+
+		if execs != nil {
+			nr.Exec = execs.GetPhaseResults(name)
+		}
+
+		r.Nodes = append(r.Nodes, nr)
+	}
+
+	sort.Slice(r.Nodes, func(i, j int) bool { return r.Nodes[i].Name < r.Nodes[j].Name })
+}
+
+// SetExports records the results of writing the topology data export to the export targets.
+func (r *DeployReport) SetExports(exports []*ExportTargetReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Exports = exports
+}
+
+// WriteDeployReport writes the deploy report to the lab directory.
+func (c *CLab) WriteDeployReport(r *DeployReport) error {
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r, "", "  ")
+	r.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(c.TopoPaths.DeployReportFileAbsPath(), append(b, '\n'), 0644)
+}
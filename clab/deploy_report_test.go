@@ -0,0 +1,131 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestDeployReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	topoPaths, err := types.NewCaTopoPaths(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &CLab{
+		Config:    &Config{Name: "report"},
+		Nodes:     map[string]nodes.Node{},
+		TopoPaths: topoPaths,
+	}
+
+	for _, name := range []string{"srl1", "srl2", "client", "late"} {
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().Config().Return(&types.NodeConfig{
+			ShortName: name,
+			Kind:      "linux",
+			Image:     "alpine:3",
+		}).AnyTimes()
+
+		c.Nodes[name] = n
+	}
+
+	r := NewDeployReport()
+
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	for _, ev := range []LifecycleEvent{
+		{Type: LifecycleEventNodePhase, Node: "srl1", Phase: NodePhaseWaitingDeps, Time: at(0)},
+		{Type: LifecycleEventNodePhase, Node: "srl1", Phase: NodePhaseCreated, Time: at(1500 * time.Millisecond)},
+		{Type: LifecycleEventLinks, LinksDeployed: 1, LinksTotal: 1, Time: at(time.Second)},
+		{Type: LifecycleEventNodePhase, Node: "srl2", Phase: NodePhasePreDeploy, Time: at(0)},
+		{
+			Type: LifecycleEventNodePhase, Node: "srl2", Phase: NodePhaseFailed, Time: at(time.Second),
+			Err: errors.New("failed deploy phase: image not found"),
+		},
+		{Type: LifecycleEventNodePhase, Node: "client", Phase: NodePhasePreDeploy, Time: at(time.Second)},
+		{Type: LifecycleEventNodePhase, Node: "client", Phase: NodePhaseCreated, Time: at(3 * time.Second)},
+		// post-deploy failure of a created node
+		{
+			Type: LifecycleEventNodePhase, Node: "client", Phase: NodePhaseFailed, Time: at(4 * time.Second),
+			Err: errors.New("failed post-deploy phase: timeout"),
+		},
+	} {
+		r.HandleEvent(ev)
+	}
+
+	containers := []runtime.GenericContainer{
+		{
+			ShortID: "abc", Labels: map[string]string{labels.NodeName: "srl1"},
+			NetworkSettings: runtime.GenericMgmtIPs{IPv4addr: "172.20.20.2", IPv6addr: "2001:172:20:20::2"},
+		},
+		{
+			ShortID: "def", Labels: map[string]string{labels.NodeName: "client"},
+			NetworkSettings: runtime.GenericMgmtIPs{IPv4addr: "172.20.20.3"},
+		},
+	}
+
+	execs := exec.NewExecCollection()
+	execs.AddPhase("srl1", "post-healthy", &exec.ExecResult{Cmd: []string{"echo", "hi"}, Stdout: "hi"})
+
+	r.Complete(c, containers, execs)
+
+	if err := c.WriteDeployReport(r); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(c.TopoPaths.DeployReportFileAbsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got DeployReport
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Name != "report" || got.Duration == "" {
+		t.Errorf("unexpected report header: name %q, duration %q", got.Name, got.Duration)
+	}
+
+	want := []*NodeReport{
+		{
+			Name: "client", Kind: "linux", Image: "alpine:3", Status: NodeStatusFailed,
+			Error: "failed post-deploy phase: timeout", ContainerID: "def", IPv4Address: "172.20.20.3",
+		},
+		// no lifecycle events were received for the node
+		{Name: "late", Kind: "linux", Image: "alpine:3", Status: NodeStatusFailed},
+		{
+			Name: "srl1", Kind: "linux", Image: "alpine:3", Status: NodeStatusCreated, ContainerID: "abc",
+			IPv4Address: "172.20.20.2", IPv6Address: "2001:172:20:20::2", Duration: "1.5s",
+			Exec: map[string][]*exec.ExecResult{
+				"post-healthy": {{Cmd: []string{"echo", "hi"}, Stdout: "hi"}},
+			},
+		},
+		{
+			Name: "srl2", Kind: "linux", Image: "alpine:3", Status: NodeStatusFailed,
+			Error: "failed deploy phase: image not found",
+		},
+	}
+
+	if d := cmp.Diff(want, got.Nodes); d != "" {
+		t.Fatalf("node reports mismatch (-want +got):\n%s", d)
+	}
+}
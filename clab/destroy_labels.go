@@ -0,0 +1,123 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// predefinedNetworks are the networks of the container runtime which are never deleted with a lab.
+var predefinedNetworks = map[string]struct{}{
+	"bridge": {},
+	"host":   {},
+	"none":   {},
+}
+
+// DestroyFromLabels destroys the lab of the given name relying only on the labels of its containers,
+// so that a lab can be cleaned up after its topology file is lost.
+// The containers of the lab are removed along with their network namespace symlinks,
+// the /etc/hosts entries and the ssh config of the lab, and the management network unless keepMgmtNet is set.
+func (c *CLab) DestroyFromLabels(ctx context.Context, labName string, keepMgmtNet bool) error {
+	nets, err := c.removeLabContainers(ctx, labName)
+
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	log.Info("Removing containerlab host entries from /etc/hosts file")
+	if err := DeleteEntriesFromHostsFile(labName); err != nil {
+		errs = append(errs, fmt.Errorf("error while trying to clean up the hosts file: %w", err))
+	}
+
+	log.Info("Removing ssh config for containerlab nodes")
+	topoPaths := &types.TopoPaths{}
+	if err := topoPaths.SetLabDir(labName); err != nil {
+		return err
+	}
+
+	if err := c.RemoveSSHConfig(topoPaths); err != nil {
+		log.Errorf("failed to remove ssh config file: %v", err)
+	}
+
+	if !keepMgmtNet {
+		c.deleteLabNetworks(ctx, nets)
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeLabContainers removes the containers labeled with the lab name and their network namespace symlinks.
+// The management networks of the removed containers are returned, keyed by the network name
+// with the name of the network bridge as the value.
+func (c *CLab) removeLabContainers(ctx context.Context, labName string) (map[string]string, error) {
+	r := c.GlobalRuntime()
+
+	containers, err := r.ListContainers(ctx, []*types.GenericFilter{{
+		FilterType: "label", Field: labels.Containerlab,
+		Operator: "=", Match: labName,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no containers of lab %q were found", labName)
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		return utils.NaturalLess(containers[i].Names[0], containers[j].Names[0])
+	})
+
+	log.Infof("Destroying lab %s using the labels of its %d container(s)", labName, len(containers))
+
+	nets := map[string]string{}
+
+	var errs []error
+	for _, ctr := range containers {
+		name := ctr.Names[0]
+
+		if err := r.DeleteContainer(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove container %s: %w", name, err))
+			continue
+		}
+
+		if err := utils.DeleteNetnsSymlink(name); err != nil {
+			errs = append(errs, fmt.Errorf("error while deleting netns symlinks: %w", err))
+		}
+
+		network := ctr.NetworkSettings.Network
+		if _, ok := predefinedNetworks[network]; ok || network == "" {
+			continue
+		}
+
+		nets[network] = ctr.Labels[labels.NodeMgmtNetBr]
+	}
+
+	return nets, errors.Join(errs...)
+}
+
+// deleteLabNetworks deletes the management networks of the lab, keyed by the network name
+// with the name of the network bridge as the value. The networks still used by other containers are kept.
+func (c *CLab) deleteLabNetworks(ctx context.Context, nets map[string]string) {
+	r := c.GlobalRuntime()
+
+	for network, bridge := range nets {
+		r.WithMgmtNet(&types.MgmtNet{Network: network, Bridge: bridge})
+
+		log.Debugf("Deleting management network %q", network)
+		if err := r.DeleteNet(ctx); err != nil {
+			log.Errorf("failed to delete management network %q: %v", network, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func newLabelsLab(rt runtime.ContainerRuntime) *CLab {
+	return &CLab{
+		Config:        &Config{},
+		Runtimes:      map[string]runtime.ContainerRuntime{"docker": rt},
+		globalRuntime: "docker",
+	}
+}
+
+func TestRemoveLabContainers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+	rt.EXPECT().ListContainers(gomock.Any(), []*types.GenericFilter{{
+		FilterType: "label", Field: labels.Containerlab,
+		Operator: "=", Match: "lab1",
+	}}).Return([]runtime.GenericContainer{
+		{
+			Names:           []string{"clab-lab1-srl2"},
+			Labels:          map[string]string{labels.NodeMgmtNetBr: "br-clab"},
+			NetworkSettings: runtime.GenericMgmtIPs{Network: "clab"},
+		},
+		{
+			Names:           []string{"clab-lab1-srl1"},
+			Labels:          map[string]string{labels.NodeMgmtNetBr: "br-clab"},
+			NetworkSettings: runtime.GenericMgmtIPs{Network: "clab"},
+		},
+		{
+			Names:           []string{"clab-lab1-host1"},
+			NetworkSettings: runtime.GenericMgmtIPs{Network: "host"},
+		},
+		{
+			Names:           []string{"clab-lab1-broken"},
+			NetworkSettings: runtime.GenericMgmtIPs{Network: "broken-net"},
+		},
+	}, nil)
+
+	gomock.InOrder(
+		rt.EXPECT().DeleteContainer(gomock.Any(), "clab-lab1-broken").Return(errors.New("busy")),
+		rt.EXPECT().DeleteContainer(gomock.Any(), "clab-lab1-host1").Return(nil),
+		rt.EXPECT().DeleteContainer(gomock.Any(), "clab-lab1-srl1").Return(nil),
+		rt.EXPECT().DeleteContainer(gomock.Any(), "clab-lab1-srl2").Return(nil),
+	)
+
+	nets, err := newLabelsLab(rt).removeLabContainers(context.Background(), "lab1")
+	if err == nil {
+		t.Fatal("expected the container removal error")
+	}
+
+	// the network of the container that failed to be removed is still in use
+	if d := cmp.Diff(map[string]string{"clab": "br-clab"}, nets); d != "" {
+		t.Errorf("networks diff (-want +got):\n%s", d)
+	}
+}
+
+func TestRemoveLabContainersNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().ListContainers(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	_, err := newLabelsLab(rt).removeLabContainers(context.Background(), "lab1")
+	if err == nil {
+		t.Fatal("expected an error for the lab without containers")
+	}
+}
+
+func TestDeleteLabNetworks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+	gomock.InOrder(
+		rt.EXPECT().WithMgmtNet(&types.MgmtNet{Network: "clab", Bridge: "br-clab"}),
+		rt.EXPECT().DeleteNet(gomock.Any()).Return(nil),
+	)
+
+	newLabelsLab(rt).deleteLabNetworks(context.Background(), map[string]string{"clab": "br-clab"})
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"time"
+
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes/state"
+)
+
+// NodePhase is the deployment phase of a node.
+type NodePhase string
+
+const (
+	// NodePhaseWaitingDeps is set when the node waits for the nodes it depends on.
+	NodePhaseWaitingDeps NodePhase = "waiting-deps"
+	NodePhasePreDeploy   NodePhase = "pre-deploy"
+	NodePhaseDeploying   NodePhase = "deploying"
+	// NodePhaseStartupWait is set when the node waits to meet its startup-wait condition.
+	NodePhaseStartupWait NodePhase = "startup-wait"
+	// NodePhaseCreated is set when the node container and its links are created.
+	NodePhaseCreated    NodePhase = "created"
+	NodePhasePostDeploy NodePhase = "post-deploy"
+	// NodePhaseHealthy is set when the post-deploy phase of the node succeeded.
+	NodePhaseHealthy NodePhase = "healthy"
+	NodePhaseFailed  NodePhase = "failed"
+)
+
+// LifecycleEventType is the type of the lifecycle event.
+type LifecycleEventType string
+
+const (
+	// LifecycleEventNodePhase is emitted when a node enters a deployment phase.
+	LifecycleEventNodePhase LifecycleEventType = "node-phase"
+	// LifecycleEventLinks is emitted when the number of the deployed links changes.
+	LifecycleEventLinks LifecycleEventType = "links"
+)
+
+// LifecycleEvent describes a change in the deployment state of the lab.
+type LifecycleEvent struct {
+	Type LifecycleEventType
+	Time time.Time
+	// Node is the name of the node which phase changed, set for the node phase events.
+	Node  string
+	Phase NodePhase
+	// Err is the error that made the node fail, set for the failed phase.
+	Err error
+	// LinksDeployed and LinksTotal are the number of the deployed and all links of the lab,
+	// set for the links events.
+	LinksDeployed int
+	LinksTotal    int
+}
+
+// LifecycleHook is a function called on every lifecycle event.
+// Hooks are called synchronously from the deployment workers, so they must return quickly.
+type LifecycleHook func(LifecycleEvent)
+
+// WithLifecycleHook registers a hook that receives the lifecycle events of the lab deployment.
+func WithLifecycleHook(h LifecycleHook) ClabOption {
+	return func(c *CLab) error {
+		c.hooks = append(c.hooks, h)
+		return nil
+	}
+}
+
+// NotifyNodePhase emits the node phase event to the registered lifecycle hooks.
+// err is reported for the failed phase.
+func (c *CLab) NotifyNodePhase(node string, phase NodePhase, err error) {
+	c.nodeTimings.record(node, phase, time.Now())
+
+	c.emit(LifecycleEvent{
+		Type:  LifecycleEventNodePhase,
+		Node:  node,
+		Phase: phase,
+		Err:   err,
+	})
+}
+
+// notifyLinks emits the links event with the number of the lab links deployed so far.
+// A link is deployed when the nodes of all its endpoints are deployed.
+func (c *CLab) notifyLinks() {
+	if len(c.hooks) == 0 {
+		return
+	}
+
+	deployed := 0
+
+	for _, l := range c.Links {
+		if linkDeployed(l) {
+			deployed++
+		}
+	}
+
+	c.emit(LifecycleEvent{
+		Type:          LifecycleEventLinks,
+		LinksDeployed: deployed,
+		LinksTotal:    len(c.Links),
+	})
+}
+
+func linkDeployed(l links.Link) bool {
+	for _, ep := range l.GetEndpoints() {
+		if ep.GetNode().GetState() != state.Deployed {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *CLab) emit(ev LifecycleEvent) {
+	if len(c.hooks) == 0 {
+		return
+	}
+
+	ev.Time = time.Now()
+
+	for _, h := range c.hooks {
+		h(ev)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 
@@ -33,6 +35,9 @@ func ParseExecOutputFormat(s string) (string, error) {
 // ExecCmd represents an exec command.
 type ExecCmd struct {
 	Cmd []string `json:"cmd"` // Cmd is a slice-based representation of a string command.
+	// ExpectedReturnCode is the return code the command is expected to exit with.
+	// When not set, the non-zero return codes are reported as failures, but the command is not asserted.
+	ExpectedReturnCode *int `json:"expected-return-code,omitempty"`
 }
 
 // NewExecCmdFromString creates ExecCmd for a string-based command.
@@ -71,12 +76,14 @@ func (s Stdout) MarshalJSON() ([]byte, error) {
 type ExecResult struct {
 	Cmd        []string `json:"cmd"`
 	ReturnCode int      `json:"return-code"`
-	Stdout     Stdout   `json:"stdout"`
-	Stderr     string   `json:"stderr"`
+	// ExpectedReturnCode is the return code the command was expected to exit with, if set.
+	ExpectedReturnCode *int   `json:"expected-return-code,omitempty"`
+	Stdout             Stdout `json:"stdout"`
+	Stderr             string `json:"stderr"`
 }
 
 func NewExecResult(op *ExecCmd) *ExecResult {
-	er := &ExecResult{Cmd: op.GetCmd()}
+	er := &ExecResult{Cmd: op.GetCmd(), ExpectedReturnCode: op.ExpectedReturnCode}
 	return er
 }
 
@@ -90,6 +97,11 @@ func (e *ExecCmd) SetCmd(cmd string) error {
 	return nil
 }
 
+// SetExpectedReturnCode sets the return code the command is expected to exit with.
+func (e *ExecCmd) SetExpectedReturnCode(rc int) {
+	e.ExpectedReturnCode = &rc
+}
+
 // GetCmd sets the command that is to be executed.
 func (e *ExecCmd) GetCmd() []string {
 	return e.Cmd
@@ -105,6 +117,10 @@ func (e *ExecResult) String() string {
 
 	s.WriteString(fmt.Sprintf("Cmd: %s\nReturnCode: %d", e.GetCmdString(), e.ReturnCode))
 
+	if e.ExpectedReturnCode != nil {
+		s.WriteString(fmt.Sprintf("\nExpectedReturnCode: %d", *e.ExpectedReturnCode))
+	}
+
 	if e.Stdout != "" {
 		s.WriteString(fmt.Sprintf("\nStdout: %q", e.Stdout))
 	}
@@ -144,6 +160,21 @@ func (e *ExecResult) SetReturnCode(rc int) {
 	e.ReturnCode = rc
 }
 
+// Failed returns true if the command exited with the return code other than the expected one,
+// which is zero when the expected return code is not set.
+func (e *ExecResult) Failed() bool {
+	if e.ExpectedReturnCode != nil {
+		return e.ReturnCode != *e.ExpectedReturnCode
+	}
+
+	return e.ReturnCode != 0
+}
+
+// Unexpected returns true if the command has the expected return code set and exited with a different one.
+func (e *ExecResult) Unexpected() bool {
+	return e.ExpectedReturnCode != nil && e.ReturnCode != *e.ExpectedReturnCode
+}
+
 func (e *ExecResult) GetStdOutString() string {
 	return string(e.Stdout)
 }
@@ -175,32 +206,84 @@ func (e *ExecResult) SetStdErr(data []byte) {
 // execEntries is a map indexed by container IDs storing lists of ExecResult.
 type execEntries map[string][]*ExecResult
 
+// phaseEntries is a map indexed by container IDs storing lists of ExecResult
+// keyed by the node lifecycle phase the commands were executed in.
+type phaseEntries map[string]map[string][]*ExecResult
+
 // ExecCollection represents a datastore for exec commands execution results.
+// It is safe for concurrent use.
 type ExecCollection struct {
+	mu sync.Mutex
 	execEntries
+	phaseEntries
 }
 
 // NewExecCollection initializes the collection of exec command results.
 func NewExecCollection() *ExecCollection {
 	return &ExecCollection{
-		execEntries{},
+		execEntries:  execEntries{},
+		phaseEntries: phaseEntries{},
 	}
 }
 
 func (ec *ExecCollection) Add(cId string, e *ExecResult) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
 	ec.execEntries[cId] = append(ec.execEntries[cId], e)
 }
 
 func (ec *ExecCollection) AddAll(cId string, e []*ExecResult) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	ec.execEntries[cId] = append(ec.execEntries[cId], e...)
+}
+
+// AddPhase adds the execution results of the commands executed in the node lifecycle phase.
+func (ec *ExecCollection) AddPhase(cId, phase string, e ...*ExecResult) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
 	ec.execEntries[cId] = append(ec.execEntries[cId], e...)
+
+	if ec.phaseEntries[cId] == nil {
+		ec.phaseEntries[cId] = map[string][]*ExecResult{}
+	}
+	ec.phaseEntries[cId][phase] = append(ec.phaseEntries[cId][phase], e...)
+}
+
+// GetPhaseResults returns the execution results stored for the node cId keyed by the node lifecycle phase.
+func (ec *ExecCollection) GetPhaseResults(cId string) map[string][]*ExecResult {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	return ec.phaseEntries[cId]
+}
+
+// GetResults returns the execution results stored for the node cId.
+func (ec *ExecCollection) GetResults(cId string) []*ExecResult {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	return ec.execEntries[cId]
 }
 
 // Dump dumps the contents of ExecCollection as a string in one of the provided formats.
+// The JSON dump of the results added with their lifecycle phase is keyed by the node and the phase.
 func (ec *ExecCollection) Dump(format string) (string, error) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
 	result := strings.Builder{}
 	switch format {
 	case ExecFormatJSON:
-		byteData, err := json.MarshalIndent(ec.execEntries, "", "  ")
+		var entries any = ec.execEntries
+		if len(ec.phaseEntries) != 0 {
+			entries = ec.phaseEntries
+		}
+
+		byteData, err := json.MarshalIndent(entries, "", "  ")
 		if err != nil {
 			return "", err
 		}
@@ -236,10 +319,16 @@ func (ec *ExecCollection) Dump(format string) (string, error) {
 // If execution result contains error, the error log facility is used,
 // otherwise it is logged as INFO.
 func (ec *ExecCollection) Log() {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
 	for k, execResults := range ec.execEntries {
 		for _, er := range execResults {
 			switch {
-			case er.GetReturnCode() != 0:
+			case er.Unexpected():
+				log.Errorf("Command %q on the node %q returned rc=%d, expected rc=%d,\nstdout:\n%s\nstderr:\n%s",
+					er.GetCmdString(), k, er.GetReturnCode(), *er.ExpectedReturnCode, er.GetStdOutString(), er.GetStdErrString())
+			case er.Failed():
 				log.Errorf("Failed to execute command %q on the node %q. rc=%d,\nstdout:\n%s\nstderr:\n%s",
 					er.GetCmdString(), k, er.GetReturnCode(), er.GetStdOutString(), er.GetStdErrString())
 			default:
@@ -249,3 +338,30 @@ func (ec *ExecCollection) Log() {
 		}
 	}
 }
+
+// Unexpected returns the descriptions of the commands that have the expected return code set
+// and exited with a different one, sorted by the node name and in the execution order.
+func (ec *ExecCollection) Unexpected() []string {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	nodes := make([]string, 0, len(ec.execEntries))
+	for k := range ec.execEntries {
+		nodes = append(nodes, k)
+	}
+
+	sort.Strings(nodes)
+
+	var res []string
+
+	for _, k := range nodes {
+		for _, er := range ec.execEntries[k] {
+			if er.Unexpected() {
+				res = append(res, fmt.Sprintf("node %q: command %q returned rc=%d, expected rc=%d",
+					k, er.GetCmdString(), er.GetReturnCode(), *er.ExpectedReturnCode))
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// ExecResultsFileName is the name of the file in the node's lab directory
+// storing the results of the exec commands run on deploy.
+const ExecResultsFileName = "exec-results.json"
+
+// WriteExecResults writes the exec results of every node to the node's lab directory.
+// The results are written regardless of the deploy output format to keep a record
+// of the executed commands for the post-deploy debugging.
+func (c *CLab) WriteExecResults(execs *exec.ExecCollection) error {
+	var errs []error
+
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+
+		results := execs.GetPhaseResults(cfg.ShortName)
+		if len(results) == 0 || cfg.LabDir == "" {
+			continue
+		}
+
+		nodeExecs := exec.NewExecCollection()
+		for phase, r := range results {
+			nodeExecs.AddPhase(cfg.ShortName, phase, r...)
+		}
+
+		data, err := nodeExecs.Dump(exec.ExecFormatJSON)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", cfg.ShortName, err))
+			continue
+		}
+
+		if err := os.MkdirAll(cfg.LabDir, 0777); err != nil { // skipcq: GSC-G301
+			errs = append(errs, fmt.Errorf("node %s: %w", cfg.ShortName, err))
+			continue
+		}
+
+		err = utils.WriteFileAtomic(filepath.Join(cfg.LabDir, ExecResultsFileName), []byte(data+"\n"), 0644)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", cfg.ShortName, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
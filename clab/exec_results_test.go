@@ -0,0 +1,81 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestWriteExecResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	labDir := t.TempDir()
+
+	c := &CLab{Nodes: map[string]nodes.Node{}}
+
+	for _, name := range []string{"n1", "n2"} {
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().Config().Return(&types.NodeConfig{
+			ShortName: name,
+			LabDir:    filepath.Join(labDir, name),
+		}).AnyTimes()
+
+		c.Nodes[name] = n
+	}
+
+	execs := exec.NewExecCollection()
+	execs.AddPhase("n1", string(types.ExecPhasePostStart), &exec.ExecResult{
+		Cmd:    []string{"ip", "link"},
+		Stdout: "1: lo",
+	})
+	execs.AddPhase("n1", string(types.ExecPhasePostHealthy), &exec.ExecResult{
+		Cmd:        []string{"false"},
+		ReturnCode: 1,
+		Stderr:     "failed",
+	})
+
+	if err := c.WriteExecResults(execs); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(labDir, "n1", ExecResultsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]map[string][]map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string][]map[string]any{
+		"n1": {
+			"post-start": {
+				{"cmd": []any{"ip", "link"}, "return-code": 0.0, "stdout": "1: lo", "stderr": ""},
+			},
+			"post-healthy": {
+				{"cmd": []any{"false"}, "return-code": 1.0, "stdout": "", "stderr": "failed"},
+			},
+		},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("exec results mismatch (-want +got):\n%s", d)
+	}
+
+	// nodes without exec results get no file
+	if _, err := os.Stat(filepath.Join(labDir, "n2", ExecResultsFileName)); !os.IsNotExist(err) {
+		t.Fatalf("unexpected exec results file of the node without results: %v", err)
+	}
+}
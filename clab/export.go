@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"io"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"text/template"
 
 	"github.com/hairyhenderson/gomplate/v3"
@@ -16,11 +18,32 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/ugorji/go/codec"
 )
 
+const (
+	// ExportFormatJSON is the default export format, the topology data is rendered with the export template.
+	ExportFormatJSON = "json"
+	// ExportFormatMsgpack is the compact binary export format, the topology data model
+	// is encoded with msgpack. The export template is not used for this format.
+	ExportFormatMsgpack = "msgpack"
+)
+
+// ExportFormats are the supported formats of the topology data export.
+var ExportFormats = []string{ExportFormatJSON, ExportFormatMsgpack}
+
 // GenerateExports generates various export files and writes it to a lab location.
-func (c *CLab) GenerateExports(ctx context.Context, f io.Writer, p string) error {
-	err := c.exportTopologyDataWithTemplate(ctx, f, p)
+// The lab resource usage is included in the export when u is not nil.
+// The template p is only used for the json format, its relative path is resolved against the current dir.
+func (c *CLab) GenerateExports(ctx context.Context, f io.Writer, format, p string, u *LabUsage) error {
+	if format == ExportFormatMsgpack {
+		return c.exportTopologyDataMsgpack(f, u)
+	}
+
+	p = utils.ResolvePath(p, "")
+
+	err := c.exportTopologyDataWithTemplate(ctx, f, p, u)
 	if err != nil {
 		log.Warningf("Cannot parse export template %s: %v", p, err)
 		// a minimal topology data file that just provides the name of a lab that failed to generate a proper export data
@@ -39,10 +62,11 @@ type TopologyExport struct {
 	Type        string                       `json:"type"`
 	Clab        *CLab                        `json:"clab,omitempty"`
 	NodeConfigs map[string]*types.NodeConfig `json:"nodeconfigs,omitempty"`
+	Usage       *LabUsage                    `json:"usage,omitempty"`
 }
 
 // exportTopologyDataWithTemplate generates and writes topology data file to w using a template.
-func (c *CLab) exportTopologyDataWithTemplate(ctx context.Context, w io.Writer, p string) error {
+func (c *CLab) exportTopologyDataWithTemplate(ctx context.Context, w io.Writer, p string, u *LabUsage) error {
 	n := filepath.Base(p)
 	t, err := template.New(n).
 		Funcs(gomplate.CreateFuncs(context.Background(), new(data.Data))).
@@ -66,6 +90,7 @@ func (c *CLab) exportTopologyDataWithTemplate(ctx context.Context, w io.Writer,
 		Type:        "clab",
 		Clab:        c,
 		NodeConfigs: make(map[string]*types.NodeConfig),
+		Usage:       u,
 	}
 
 	for _, n := range c.Nodes {
@@ -105,3 +130,175 @@ func (c *CLab) exportTopologyDataWithMinimalTemplate(w io.Writer) error {
 	log.Debug("Exported topology data using built-in template")
 	return err
 }
+
+// TopologyData is the model of the topology data exported in the binary formats.
+// It has the same structure and field names as the json document rendered with the default auto.tmpl template,
+// and is described by the topology-data JSON schema.
+type TopologyData struct {
+	Name  string                       `json:"name"`
+	Type  string                       `json:"type"`
+	Clab  TopologyDataClab             `json:"clab"`
+	Nodes map[string]*TopologyDataNode `json:"nodes"`
+	Links []*TopologyDataLink          `json:"links"`
+	Usage *LabUsage                    `json:"usage,omitempty"`
+}
+
+// TopologyDataClab is the lab configuration of the exported topology data.
+type TopologyDataClab struct {
+	Config TopologyDataConfig `json:"config"`
+}
+
+// TopologyDataConfig is the lab prefix and management network of the exported topology data.
+type TopologyDataConfig struct {
+	Prefix string         `json:"prefix"`
+	Mgmt   *types.MgmtNet `json:"mgmt"`
+}
+
+// TopologyDataNode is a node of the exported topology data.
+type TopologyDataNode struct {
+	// Index is the index of the node, a string as in the auto.tmpl template.
+	Index                string                     `json:"index"`
+	ShortName            string                     `json:"shortname"`
+	LongName             string                     `json:"longname"`
+	Fqdn                 string                     `json:"fqdn"`
+	Hostname             string                     `json:"hostname"`
+	Group                string                     `json:"group"`
+	LabDir               string                     `json:"labdir"`
+	Kind                 string                     `json:"kind"`
+	Image                string                     `json:"image"`
+	MgmtNet              string                     `json:"mgmt-net"`
+	MgmtIntf             string                     `json:"mgmt-intf"`
+	MgmtIPv4Address      string                     `json:"mgmt-ipv4-address"`
+	MgmtIPv4PrefixLength int                        `json:"mgmt-ipv4-prefix-length"`
+	MgmtIPv6Address      string                     `json:"mgmt-ipv6-address"`
+	MgmtIPv6PrefixLength int                        `json:"mgmt-ipv6-prefix-length"`
+	MacAddress           string                     `json:"mac-address"`
+	ConsolePort          int                        `json:"console-port,omitempty"`
+	ExposedPorts         map[string]int             `json:"exposed-ports,omitempty"`
+	Labels               map[string]string          `json:"labels"`
+	PortBindings         []*TopologyDataPortBinding `json:"port-bindings"`
+}
+
+// TopologyDataPortBinding is a port binding of a node of the exported topology data.
+type TopologyDataPortBinding struct {
+	HostIP        string `json:"host-ip"`
+	HostPort      int    `json:"host-port"`
+	ContainerPort int    `json:"port"`
+	Protocol      string `json:"protocol"`
+}
+
+// TopologyDataLink is a link of the exported topology data.
+type TopologyDataLink struct {
+	A *TopologyDataEndpoint `json:"a"`
+	Z *TopologyDataEndpoint `json:"z"`
+}
+
+// TopologyDataEndpoint is an endpoint of a link of the exported topology data.
+type TopologyDataEndpoint struct {
+	Node      string `json:"node"`
+	Interface string `json:"interface"`
+	MAC       string `json:"mac"`
+	Peer      string `json:"peer"`
+}
+
+// TopologyData returns the topology data of the lab.
+// The lab resource usage is included when u is not nil.
+func (c *CLab) TopologyData(u *LabUsage) *TopologyData {
+	d := &TopologyData{
+		Name:  c.Config.Name,
+		Type:  "clab",
+		Clab:  TopologyDataClab{Config: TopologyDataConfig{Mgmt: c.Config.Mgmt}},
+		Nodes: make(map[string]*TopologyDataNode, len(c.Nodes)),
+		Links: make([]*TopologyDataLink, 0, len(c.Links)),
+		Usage: u,
+	}
+
+	if c.Config.Prefix != nil {
+		d.Clab.Config.Prefix = *c.Config.Prefix
+	}
+
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+
+		dn := &TopologyDataNode{
+			Index:                strconv.Itoa(cfg.Index),
+			ShortName:            cfg.ShortName,
+			LongName:             cfg.LongName,
+			Fqdn:                 cfg.Fqdn,
+			Hostname:             cfg.GetHostname(),
+			Group:                cfg.Group,
+			LabDir:               cfg.LabDir,
+			Kind:                 cfg.Kind,
+			Image:                cfg.Image,
+			MgmtNet:              cfg.MgmtNet,
+			MgmtIntf:             cfg.MgmtIntf,
+			MgmtIPv4Address:      cfg.MgmtIPv4Address,
+			MgmtIPv4PrefixLength: cfg.MgmtIPv4PrefixLength,
+			MgmtIPv6Address:      cfg.MgmtIPv6Address,
+			MgmtIPv6PrefixLength: cfg.MgmtIPv6PrefixLength,
+			MacAddress:           cfg.MacAddress,
+			ConsolePort:          cfg.ConsolePort,
+			ExposedPorts:         cfg.ExposedPorts,
+			Labels:               cfg.Labels,
+			PortBindings:         make([]*TopologyDataPortBinding, 0, len(cfg.ResultingPortBindings)),
+		}
+
+		for _, p := range cfg.ResultingPortBindings {
+			dn.PortBindings = append(dn.PortBindings, &TopologyDataPortBinding{
+				HostIP:        p.HostIP,
+				HostPort:      p.HostPort,
+				ContainerPort: p.ContainerPort,
+				Protocol:      p.Protocol,
+			})
+		}
+
+		d.Nodes[cfg.ShortName] = dn
+	}
+
+	// the links are ordered by their index in the topology as in the templates
+	idxs := make([]int, 0, len(c.Links))
+	for i := range c.Links {
+		idxs = append(idxs, i)
+	}
+
+	sort.Ints(idxs)
+
+	for _, i := range idxs {
+		eps := c.Links[i].GetEndpoints()
+		if len(eps) < 2 {
+			continue
+		}
+
+		d.Links = append(d.Links, &TopologyDataLink{
+			A: &TopologyDataEndpoint{
+				Node:      eps[0].GetNode().GetShortName(),
+				Interface: eps[0].GetIfaceName(),
+				MAC:       eps[0].GetMac().String(),
+				Peer:      "z",
+			},
+			Z: &TopologyDataEndpoint{
+				Node:      eps[1].GetNode().GetShortName(),
+				Interface: eps[1].GetIfaceName(),
+				MAC:       eps[1].GetMac().String(),
+				Peer:      "a",
+			},
+		})
+	}
+
+	return d
+}
+
+// exportTopologyDataMsgpack writes the topology data to w encoded with msgpack.
+// The field names are the same as in the json export, the maps are encoded with the sorted keys.
+func (c *CLab) exportTopologyDataMsgpack(w io.Writer, u *LabUsage) error {
+	h := &codec.MsgpackHandle{}
+	h.Canonical = true
+
+	if err := codec.NewEncoder(w, h).Encode(c.TopologyData(u)); err != nil {
+		return err
+	}
+
+	log.Debug("Exported topology data in the msgpack format")
+
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// ExportTargetStatusPublished is the deploy report status of the export target the export was written to.
+	ExportTargetStatusPublished = "published"
+	// ExportTargetStatusFailed is the deploy report status of the export target the export failed to be written to.
+	ExportTargetStatusFailed = "failed"
+
+	// defaultExportAttempts is the number of attempts to publish the export to an HTTP target.
+	defaultExportAttempts = 3
+	// defaultExportRetryInterval is the interval before the first retry, the interval is doubled with every retry.
+	defaultExportRetryInterval = time.Second
+	// exportRequestTimeout is the timeout of a single export request to an HTTP target.
+	exportRequestTimeout = 30 * time.Second
+)
+
+// ExportTargetReport is the result of writing the topology data export to an export target.
+type ExportTargetReport struct {
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExportPublisher writes the rendered topology data export to the export targets.
+// A target is a local file path, a .gz file path the export is compressed for,
+// or an http(s):// URL the export is POSTed to.
+type ExportPublisher struct {
+	// Targets are the export targets.
+	Targets []string
+	// Headers are the headers of the requests to the HTTP targets.
+	Headers map[string]string
+	// ContentType is the content type of the export sent to the HTTP targets.
+	ContentType string
+	// Attempts is the number of attempts to publish the export to an HTTP target,
+	// the requests failed with a server error or not reaching the server are retried.
+	Attempts int
+	// RetryInterval is the interval before the first retry, the interval is doubled with every retry.
+	RetryInterval time.Duration
+	// Strict makes Publish fail when the export can't be written to a target,
+	// otherwise the failures are only logged and reported.
+	Strict bool
+	Client *http.Client
+}
+
+// NewExportPublisher returns the export publisher of the targets.
+// The headers are in the "Name: value" format, the environment variables referenced in the values are expanded,
+// so that the auth tokens don't have to be passed on the command line.
+func NewExportPublisher(targets, headers []string, format string, strict bool) (*ExportPublisher, error) {
+	p := &ExportPublisher{
+		Targets:       targets,
+		Strict:        strict,
+		Headers:       map[string]string{},
+		ContentType:   "application/json",
+		Attempts:      defaultExportAttempts,
+		RetryInterval: defaultExportRetryInterval,
+		Client:        &http.Client{Timeout: exportRequestTimeout},
+	}
+
+	if format == ExportFormatMsgpack {
+		p.ContentType = "application/msgpack"
+	}
+
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid export header %q, expected format is \"Name: value\"", h)
+		}
+
+		p.Headers[strings.TrimSpace(name)] = os.ExpandEnv(strings.TrimSpace(value))
+	}
+
+	return p, nil
+}
+
+// Publish writes the export data to all the targets and returns the result of every target.
+// In the strict mode the error joins the errors of all the failed targets,
+// otherwise the failures are logged as warnings and no error is returned.
+func (p *ExportPublisher) Publish(ctx context.Context, data []byte) ([]*ExportTargetReport, error) {
+	reports := make([]*ExportTargetReport, 0, len(p.Targets))
+
+	var errs []error
+
+	for _, t := range p.Targets {
+		r := &ExportTargetReport{Target: t, Status: ExportTargetStatusPublished}
+
+		var err error
+		if isHTTPExportTarget(t) {
+			err = p.post(ctx, t, data)
+		} else {
+			err = writeExportFile(t, data)
+		}
+
+		if err != nil {
+			r.Status = ExportTargetStatusFailed
+			r.Error = err.Error()
+			errs = append(errs, fmt.Errorf("export target %s: %w", t, err))
+		} else {
+			log.Debugf("Exported topology data to %s", t)
+		}
+
+		reports = append(reports, r)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil && !p.Strict {
+		log.Warnf("failed to write the topology data export: %v", err)
+		return reports, nil
+	}
+
+	return reports, err
+}
+
+// isHTTPExportTarget returns true if the export target is a URL the export is POSTed to.
+func isHTTPExportTarget(t string) bool {
+	return strings.HasPrefix(t, "https://") || strings.HasPrefix(t, "http://")
+}
+
+// writeExportFile writes the export to the file, the export is compressed for the .gz file.
+func writeExportFile(path string, data []byte) error {
+	if strings.HasSuffix(path, ".gz") {
+		var buf bytes.Buffer
+
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		data = buf.Bytes()
+	}
+
+	return utils.WriteFileAtomic(path, data, 0644)
+}
+
+// post POSTs the export to the URL, retrying the requests that failed with a server error or didn't reach the server.
+func (p *ExportPublisher) post(ctx context.Context, url string, data []byte) error {
+	interval := p.RetryInterval
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		var retry bool
+
+		retry, err = p.postOnce(ctx, url, data)
+		if err == nil || !retry || attempt >= p.Attempts {
+			return err
+		}
+
+		log.Debugf("Export to %s failed, retrying in %s: %v", url, interval, err)
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		interval *= 2
+	}
+}
+
+// postOnce sends a single export request and returns whether the failed request can be retried.
+func (p *ExportPublisher) postOnce(ctx context.Context, url string, data []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", p.ContentType)
+
+	for name, value := range p.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	err = fmt.Errorf("unexpected response status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+
+	return resp.StatusCode >= 500, err
+}
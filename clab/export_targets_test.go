@@ -0,0 +1,203 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testExport = `{"name": "lab"}`
+
+// newTestPublisher returns the strict export publisher of the targets with the short retry interval.
+func newTestPublisher(t *testing.T, targets, headers []string) *ExportPublisher {
+	t.Helper()
+
+	p, err := NewExportPublisher(targets, headers, ExportFormatJSON, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.RetryInterval = time.Millisecond
+
+	return p
+}
+
+func TestExportPublisherAuthHeader(t *testing.T) {
+	t.Setenv("CLAB_TEST_EXPORT_TOKEN", "s3cr3t")
+
+	var gotAuth, gotType, gotBody string
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	p := newTestPublisher(t, []string{srv.URL}, []string{"Authorization: Bearer ${CLAB_TEST_EXPORT_TOKEN}"})
+	p.Client = srv.Client()
+
+	reports, err := p.Publish(context.Background(), []byte(testExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotAuth != "Bearer s3cr3t" {
+		t.Errorf("got Authorization header %q, want %q", gotAuth, "Bearer s3cr3t")
+	}
+
+	if gotType != "application/json" {
+		t.Errorf("got Content-Type header %q, want %q", gotType, "application/json")
+	}
+
+	if gotBody != testExport {
+		t.Errorf("got body %q, want %q", gotBody, testExport)
+	}
+
+	if len(reports) != 1 || reports[0].Status != ExportTargetStatusPublished {
+		t.Errorf("unexpected reports %+v", reports[0])
+	}
+}
+
+func TestExportPublisherRetry(t *testing.T) {
+	tests := map[string]struct {
+		// statuses are the response statuses of the consecutive requests
+		statuses     []int
+		wantRequests int32
+		wantErr      bool
+	}{
+		"recovers after server errors": {
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantRequests: 3,
+		},
+		"gives up after all attempts": {
+			statuses:     []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			wantRequests: 3,
+			wantErr:      true,
+		},
+		"client error is not retried": {
+			statuses:     []int{http.StatusUnauthorized},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				n := requests.Add(1)
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer srv.Close()
+
+			p := newTestPublisher(t, []string{srv.URL}, nil)
+
+			reports, err := p.Publish(context.Background(), []byte(testExport))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", got, tt.wantRequests)
+			}
+
+			wantStatus := ExportTargetStatusPublished
+			if tt.wantErr {
+				wantStatus = ExportTargetStatusFailed
+			}
+
+			if reports[0].Status != wantStatus {
+				t.Errorf("got status %q, want %q", reports[0].Status, wantStatus)
+			}
+		})
+	}
+}
+
+func TestExportPublisherStrict(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "topology-data.json")
+
+	for _, strict := range []bool{true, false} {
+		p := newTestPublisher(t, []string{srv.URL, file}, nil)
+		p.Strict = strict
+
+		reports, err := p.Publish(context.Background(), []byte(testExport))
+
+		// the failed remote target only fails the publishing in the strict mode
+		if (err != nil) != strict {
+			t.Errorf("strict=%v: got error %v", strict, err)
+		}
+
+		// the targets are written to regardless of the failures of the other targets
+		if len(reports) != 2 || reports[0].Status != ExportTargetStatusFailed || reports[0].Error == "" ||
+			reports[1].Status != ExportTargetStatusPublished {
+			t.Errorf("strict=%v: unexpected reports %+v, %+v", strict, reports[0], reports[1])
+		}
+	}
+}
+
+func TestExportPublisherFiles(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "topology-data.json")
+	compressed := filepath.Join(dir, "topology-data.json.gz")
+
+	p := newTestPublisher(t, []string{plain, compressed}, nil)
+
+	if _, err := p.Publish(context.Background(), []byte(testExport)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != testExport {
+		t.Errorf("got %q, want %q", b, testExport)
+	}
+
+	f, err := os.Open(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != testExport {
+		t.Errorf("got %q, want %q", b, testExport)
+	}
+}
+
+func TestNewExportPublisherInvalidHeader(t *testing.T) {
+	if _, err := NewExportPublisher(nil, []string{"Authorization"}, ExportFormatJSON, false); err == nil {
+		t.Fatal("expected an error for the header without a value")
+	}
+}
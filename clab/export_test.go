@@ -0,0 +1,182 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/srl-labs/containerlab/schemas"
+	"github.com/ugorji/go/codec"
+)
+
+// autoExportTemplate is the default export template.
+const autoExportTemplate = "../templates/export/auto.tmpl"
+
+// decodeTopologyData decodes the topology data exported in the format.
+func decodeTopologyData(b []byte, format string) (*TopologyData, error) {
+	d := &TopologyData{}
+
+	if format == ExportFormatMsgpack {
+		return d, codec.NewDecoderBytes(b, &codec.MsgpackHandle{}).Decode(d)
+	}
+
+	return d, json.Unmarshal(b, d)
+}
+
+// TestExportFormatsCarryIdenticalData checks that the msgpack export carries the same data
+// as the json export rendered with the default template, and that the json export conforms to the schema.
+func TestExportFormatsCarryIdenticalData(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(schemas.TopologyData, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := newGraphTestLab(t)
+
+	// the exposed ports are rendered by the template as a map
+	c.Nodes["srl1"].Config().ExposedPorts = map[string]int{"ssh": 49152, "gnmi": 49153}
+
+	memory, cpu, imagesSize := uint64(512<<20), 3.5, int64(1<<30)
+
+	for name, u := range map[string]*LabUsage{
+		"without usage": nil,
+		"with usage": {
+			Nodes: []*NodeUsage{
+				{Name: "clab-graph-srl1", Image: "ghcr.io/nokia/srlinux:23.10.1", MemoryBytes: &memory, CPUPercent: &cpu},
+				{Name: "clab-graph-l1", Image: "alpine:3"},
+			},
+			ImagesSizeBytes: &imagesSize,
+			UniqueImages:    2,
+			Veths:           2,
+			MgmtIPv4:        &SubnetUsage{Subnet: "172.100.100.0/24", Used: 2, Capacity: 254},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var jsonExport, msgpackExport bytes.Buffer
+
+			if err := c.GenerateExports(context.Background(), &jsonExport, ExportFormatJSON,
+				autoExportTemplate, u); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := c.GenerateExports(context.Background(), &msgpackExport, ExportFormatMsgpack,
+				autoExportTemplate, u); err != nil {
+				t.Fatal(err)
+			}
+
+			fromJSON, err := decodeTopologyData(jsonExport.Bytes(), ExportFormatJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the json export falls back to the minimal template on the template errors
+			if len(fromJSON.Nodes) != 2 || len(fromJSON.Links) != 2 {
+				t.Fatalf("json export has %d nodes and %d links, want 2 and 2:\n%s",
+					len(fromJSON.Nodes), len(fromJSON.Links), jsonExport.String())
+			}
+
+			fromMsgpack, err := decodeTopologyData(msgpackExport.Bytes(), ExportFormatMsgpack)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(fromJSON, fromMsgpack, cmpopts.EquateEmpty()); d != "" {
+				t.Errorf("topology data mismatch (-json +msgpack):\n%s", d)
+			}
+
+			var doc any
+			if err := json.Unmarshal(jsonExport.Bytes(), &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, err := range validateSchema(schema, schema, doc, "$") {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// newExportBenchLab returns a lab of n linux nodes connected in a chain.
+func newExportBenchLab(b *testing.B, n int) *CLab {
+	b.Helper()
+
+	dir := b.TempDir()
+	b.Setenv("CLAB_LABDIR_BASE", dir)
+
+	var sb strings.Builder
+
+	sb.WriteString("name: bench\ntopology:\n  nodes:\n")
+
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "    n%d:\n      kind: linux\n      image: alpine:3\n", i)
+	}
+
+	sb.WriteString("  links:\n")
+
+	for i := 1; i < n; i++ {
+		fmt.Fprintf(&sb, "    - endpoints: [\"n%d:eth1\", \"n%d:eth2\"]\n", i, i+1)
+	}
+
+	p := filepath.Join(dir, "bench.clab.yml")
+	if err := os.WriteFile(p, []byte(sb.String()), 0o644); err != nil {
+		b.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(p, ""))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		b.Fatal(err)
+	}
+
+	return c
+}
+
+// BenchmarkTopologyDataExport compares the size of the topology data and the time to export and to parse it
+// in the json and the msgpack formats for a 500 node lab.
+func BenchmarkTopologyDataExport(b *testing.B) {
+	c := newExportBenchLab(b, 500)
+
+	for _, format := range ExportFormats {
+		var export bytes.Buffer
+
+		if err := c.GenerateExports(context.Background(), &export, format, autoExportTemplate, nil); err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(format+"/export", func(b *testing.B) {
+			var buf bytes.Buffer
+
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+
+				if err := c.GenerateExports(context.Background(), &buf, format, autoExportTemplate, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(buf.Len()), "size-bytes")
+		})
+
+		b.Run(format+"/parse", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeTopologyData(export.Bytes(), format); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -37,18 +37,38 @@ func (c *CLab) GetTopology(topo, varsFile string) error {
 		return err
 	}
 
+	yamlFile, err := c.renderTopology(varsFile)
+	if err != nil {
+		return err
+	}
+
+	c.resolvedTopology = yamlFile
+
+	err = yaml.UnmarshalStrict(yamlFile, c.Config)
+	if err != nil {
+		return fmt.Errorf("%w\nConsult with release notes to see if any fields were changed/removed", err)
+	}
+
+	c.Config.Topology.ImportEnvs()
+
+	return nil
+}
+
+// renderTopology renders the topology template, expands the env vars
+// and merges the included topology files into the topology.
+func (c *CLab) renderTopology(varsFile string) ([]byte, error) {
 	// load the topology file/template
 	topologyTemplate, err := template.New(c.TopoPaths.TopologyFilenameBase()).
 		Funcs(gomplate.CreateFuncs(context.Background(), new(data.Data))).
 		ParseFiles(c.TopoPaths.TopologyFilenameAbsPath())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// read template variables
 	templateVars, err := readTemplateVariables(c.TopoPaths.TopologyFilenameAbsPath(), varsFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	log.Debugf("template variables: %v", templateVars)
@@ -57,11 +77,11 @@ func (c *CLab) GetTopology(topo, varsFile string) error {
 
 	err = topologyTemplate.Execute(buf, templateVars)
 	if err != nil {
-		return fmt.Errorf("failed to execute template: %v", err)
+		return nil, fmt.Errorf("failed to execute template: %v", err)
 	}
 
 	// create a hidden file that will contain the rendered topology
-	if !strings.HasPrefix(c.TopoPaths.TopologyFilenameBase(), ".") {
+	if !c.skipRenderedTopoWrite && !strings.HasPrefix(c.TopoPaths.TopologyFilenameBase(), ".") {
 		backupFPath := c.TopoPaths.TopologyBakFileAbsPath()
 		err = utils.CreateFile(backupFPath, buf.String())
 		if err != nil {
@@ -73,16 +93,23 @@ func (c *CLab) GetTopology(topo, varsFile string) error {
 	// expand env vars if any
 	yamlFile, err := envsubst.Bytes(buf.Bytes())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = yaml.UnmarshalStrict(yamlFile, c.Config)
-	if err != nil {
-		return fmt.Errorf("%w\nConsult with release notes to see if any fields were changed/removed", err)
+
+	// merge the included topology files
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = defaultTopoDownloadTimeout
 	}
 
-	c.Config.Topology.ImportEnvs()
+	r := newIncludeResolver(c.TopoPaths.ClabTmpDir(), timeout)
 
-	return nil
+	yamlFile, err = r.resolveIncludes(yamlFile, c.TopoPaths.TopologyFilenameAbsPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve topology includes: %w", err)
+	}
+
+	return yamlFile, nil
 }
 
 func readTemplateVariables(topo, varsFile string) (interface{}, error) {
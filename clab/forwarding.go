@@ -0,0 +1,325 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	ipv4ForwardingSysctl = "net.ipv4.ip_forward"
+	ipv6ForwardingSysctl = "net.ipv6.conf.all.forwarding"
+
+	// forwardingLedgerFile is the name of the file in the clab temp dir recording the forwarding sysctls
+	// enabled by containerlab. The file is shared by all the labs of the host.
+	forwardingLedgerFile = "forwarding.json"
+	// forwardingLockFile is the file in the clab temp dir locked while the ledger is read and updated,
+	// so that the concurrent deployments and destructions don't lose each other's ledger entries.
+	forwardingLockFile = "forwarding.lock"
+)
+
+// sysctlDir is the directory of the sysctl files.
+var sysctlDir = "/proc/sys"
+
+// forwardingLedger maps the forwarding sysctls enabled by containerlab to their ledger entries.
+type forwardingLedger map[string]*forwardingLedgerEntry
+
+// forwardingLedgerEntry is a forwarding sysctl enabled by containerlab.
+type forwardingLedgerEntry struct {
+	// Previous is the value of the sysctl before containerlab enabled it.
+	Previous string `json:"previous"`
+	// Labs are the names of the labs that need the sysctl enabled.
+	Labs []string `json:"labs"`
+}
+
+// requiredForwardingSysctls returns the forwarding sysctls needed by the management network.
+// The forwarding is needed to reach the nodes from outside of the host and for the nodes
+// to reach outside of the host, which is only allowed when the external access is enabled.
+func requiredForwardingSysctls(mgmt *types.MgmtNet) []string {
+	if mgmt.ExternalAccess != nil && !*mgmt.ExternalAccess {
+		return nil
+	}
+
+	var sysctls []string
+
+	if mgmt.IPv4Subnet != "" {
+		sysctls = append(sysctls, ipv4ForwardingSysctl)
+	}
+
+	if mgmt.IPv6Subnet != "" {
+		sysctls = append(sysctls, ipv6ForwardingSysctl)
+	}
+
+	return sysctls
+}
+
+// CheckForwarding checks that the forwarding sysctls needed by the management network are enabled.
+// When the forwarding is enabled in the management network settings, the disabled sysctls are enabled
+// and recorded in the forwarding ledger to be restored when the last lab needing them is destroyed.
+// Otherwise, a warning with the commands enabling the sysctls is logged.
+func (c *CLab) CheckForwarding() error {
+	sysctls := requiredForwardingSysctls(c.Config.Mgmt)
+	if len(sysctls) == 0 {
+		return nil
+	}
+
+	if c.Config.Mgmt.EnableForwarding != nil && *c.Config.Mgmt.EnableForwarding {
+		return acquireForwarding(c.forwardingLedgerPath(), c.Config.Name, sysctls)
+	}
+
+	var disabled []string
+
+	for _, s := range sysctls {
+		v, err := readSysctl(s)
+		if err != nil {
+			log.Debugf("failed to read sysctl %s: %v", s, err)
+			continue
+		}
+
+		if v == "0" {
+			disabled = append(disabled, s)
+		}
+	}
+
+	if len(disabled) == 0 {
+		return nil
+	}
+
+	cmds := make([]string, 0, len(disabled))
+	for _, s := range disabled {
+		cmds = append(cmds, fmt.Sprintf("sysctl -w %s=1", s))
+	}
+
+	log.Warnf("The packet forwarding is disabled on the host (%s), the lab nodes won't be able to reach "+
+		"and be reached from outside of the host via the management network. "+
+		"Enable it with %q or set mgmt.enable-forwarding to true in the topology file",
+		strings.Join(disabled, ", "), strings.Join(cmds, " && "))
+
+	return nil
+}
+
+// RestoreForwarding releases the forwarding sysctls needed by the lab and restores the previous values
+// of the sysctls that containerlab enabled and no other lab needs anymore.
+func (c *CLab) RestoreForwarding() error {
+	return releaseForwarding(c.forwardingLedgerPath(), c.Config.Name)
+}
+
+func (c *CLab) forwardingLedgerPath() string {
+	return filepath.Join(c.TopoPaths.ClabTmpDir(), forwardingLedgerFile)
+}
+
+// acquireForwarding enables the disabled sysctls and records them in the ledger along with their previous values.
+// The lab is added to the users of the sysctls already recorded for other labs.
+// The sysctls enabled by a user are left out of the ledger, containerlab never restores them.
+func acquireForwarding(ledgerPath, lab string, sysctls []string) error {
+	unlock, err := lockForwardingLedger(ledgerPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ledger, err := loadForwardingLedger(ledgerPath)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sysctls {
+		v, err := readSysctl(s)
+		if err != nil {
+			return err
+		}
+
+		e, recorded := ledger[s]
+
+		// the sysctl enabled by a user is left alone
+		if v != "0" && !recorded {
+			continue
+		}
+
+		if v == "0" {
+			log.Infof("Enabling packet forwarding on the host: %s=1", s)
+
+			if err := writeSysctl(s, "1"); err != nil {
+				return fmt.Errorf("failed to enable the packet forwarding: %w", err)
+			}
+		}
+
+		if !recorded {
+			e = &forwardingLedgerEntry{Previous: v}
+			ledger[s] = e
+		}
+
+		if _, ok := utils.StringInSlice(e.Labs, lab); !ok {
+			e.Labs = append(e.Labs, lab)
+		}
+	}
+
+	return ledger.save(ledgerPath)
+}
+
+// releaseForwarding removes the lab from the users of the sysctls recorded in the ledger
+// and restores the previous values of the sysctls left without users.
+func releaseForwarding(ledgerPath, lab string) error {
+	if _, err := os.Stat(ledgerPath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	unlock, err := lockForwardingLedger(ledgerPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ledger, err := loadForwardingLedger(ledgerPath)
+	if err != nil {
+		return err
+	}
+
+	if len(ledger) == 0 {
+		return nil
+	}
+
+	sysctls := make([]string, 0, len(ledger))
+	for s := range ledger {
+		sysctls = append(sysctls, s)
+	}
+	sort.Strings(sysctls)
+
+	var errs []error
+
+	for _, s := range sysctls {
+		e := ledger[s]
+
+		labs := make([]string, 0, len(e.Labs))
+		for _, l := range e.Labs {
+			if l != lab {
+				labs = append(labs, l)
+			}
+		}
+
+		e.Labs = labs
+
+		if len(e.Labs) != 0 {
+			continue
+		}
+
+		log.Infof("Restoring the packet forwarding setting of the host: %s=%s", s, e.Previous)
+
+		if err := writeSysctl(s, e.Previous); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore sysctl %s: %w", s, err))
+			continue
+		}
+
+		delete(ledger, s)
+	}
+
+	if err := ledger.save(ledgerPath); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// lockForwardingLedger takes the lock of the ledger shared by the labs of the host,
+// the returned function releases it.
+func lockForwardingLedger(ledgerPath string) (func() error, error) {
+	dir := filepath.Dir(ledgerPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return utils.LockFile(filepath.Join(dir, forwardingLockFile))
+}
+
+// loadForwardingLedger reads the ledger file, a missing file is an empty ledger.
+func loadForwardingLedger(path string) (forwardingLedger, error) {
+	ledger := forwardingLedger{}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ledger, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &ledger); err != nil {
+		return nil, fmt.Errorf("failed to parse the forwarding ledger %s: %w", path, err)
+	}
+
+	return ledger, nil
+}
+
+// save writes the ledger file, the file is removed when the ledger is empty.
+// The file is written to a temp file renamed over the ledger, so that the ledger is never read half-written.
+func (l forwardingLedger) save(path string) error {
+	if len(l) == 0 {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// readSysctl reads the value of the sysctl with the dotted name, e.g. net.ipv4.ip_forward.
+func readSysctl(name string) (string, error) {
+	b, err := os.ReadFile(sysctlPath(name))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// writeSysctl sets the value of the sysctl with the dotted name.
+func writeSysctl(name, value string) error {
+	return os.WriteFile(sysctlPath(name), []byte(value), 0644)
+}
+
+func sysctlPath(name string) string {
+	return filepath.Join(sysctlDir, strings.ReplaceAll(name, ".", "/"))
+}
@@ -0,0 +1,256 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// setupSysctls points the sysctl functions to a temp dir with the given sysctl values.
+func setupSysctls(t *testing.T, values map[string]string) {
+	t.Helper()
+
+	orig := sysctlDir
+	sysctlDir = t.TempDir()
+
+	t.Cleanup(func() { sysctlDir = orig })
+
+	for name, v := range values {
+		p := sysctlPath(name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(v+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkSysctls(t *testing.T, want map[string]string) {
+	t.Helper()
+
+	for name, w := range want {
+		v, err := readSysctl(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != w {
+			t.Errorf("sysctl %s = %q, want %q", name, v, w)
+		}
+	}
+}
+
+func checkLedger(t *testing.T, path string, want forwardingLedger) {
+	t.Helper()
+
+	got, err := loadForwardingLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("ledger mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRequiredForwardingSysctls(t *testing.T) {
+	disabled := false
+
+	tests := map[string]struct {
+		mgmt *types.MgmtNet
+		want []string
+	}{
+		"dual stack": {
+			mgmt: &types.MgmtNet{IPv4Subnet: "172.20.20.0/24", IPv6Subnet: "3fff:172:20:20::/64"},
+			want: []string{ipv4ForwardingSysctl, ipv6ForwardingSysctl},
+		},
+		"ipv4 only": {
+			mgmt: &types.MgmtNet{IPv4Subnet: "172.20.20.0/24"},
+			want: []string{ipv4ForwardingSysctl},
+		},
+		"external access disabled": {
+			mgmt: &types.MgmtNet{IPv4Subnet: "172.20.20.0/24", ExternalAccess: &disabled},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := requiredForwardingSysctls(tt.mgmt)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("sysctls mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestForwardingTwoLabs(t *testing.T) {
+	setupSysctls(t, map[string]string{ipv4ForwardingSysctl: "0", ipv6ForwardingSysctl: "0"})
+
+	ledgerPath := filepath.Join(t.TempDir(), forwardingLedgerFile)
+
+	// lab1 needs the ipv4 forwarding only, lab2 needs both
+	if err := acquireForwarding(ledgerPath, "lab1", []string{ipv4ForwardingSysctl}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := acquireForwarding(ledgerPath, "lab2", []string{ipv4ForwardingSysctl, ipv6ForwardingSysctl}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkSysctls(t, map[string]string{ipv4ForwardingSysctl: "1", ipv6ForwardingSysctl: "1"})
+	checkLedger(t, ledgerPath, forwardingLedger{
+		ipv4ForwardingSysctl: {Previous: "0", Labs: []string{"lab1", "lab2"}},
+		ipv6ForwardingSysctl: {Previous: "0", Labs: []string{"lab2"}},
+	})
+
+	// the redeployment of a lab doesn't duplicate it in the ledger
+	if err := acquireForwarding(ledgerPath, "lab1", []string{ipv4ForwardingSysctl}); err != nil {
+		t.Fatal(err)
+	}
+
+	// lab2 is destroyed first, the ipv4 forwarding is still needed by lab1
+	if err := releaseForwarding(ledgerPath, "lab2"); err != nil {
+		t.Fatal(err)
+	}
+
+	checkSysctls(t, map[string]string{ipv4ForwardingSysctl: "1", ipv6ForwardingSysctl: "0"})
+	checkLedger(t, ledgerPath, forwardingLedger{
+		ipv4ForwardingSysctl: {Previous: "0", Labs: []string{"lab1"}},
+	})
+
+	if err := releaseForwarding(ledgerPath, "lab1"); err != nil {
+		t.Fatal(err)
+	}
+
+	checkSysctls(t, map[string]string{ipv4ForwardingSysctl: "0", ipv6ForwardingSysctl: "0"})
+
+	if _, err := os.Stat(ledgerPath); !os.IsNotExist(err) {
+		t.Errorf("ledger file exists after the last lab is destroyed, stat error: %v", err)
+	}
+}
+
+func TestForwardingEnabledByUser(t *testing.T) {
+	setupSysctls(t, map[string]string{ipv4ForwardingSysctl: "1", ipv6ForwardingSysctl: "0"})
+
+	ledgerPath := filepath.Join(t.TempDir(), forwardingLedgerFile)
+
+	sysctls := []string{ipv4ForwardingSysctl, ipv6ForwardingSysctl}
+
+	if err := acquireForwarding(ledgerPath, "lab1", sysctls); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := acquireForwarding(ledgerPath, "lab2", sysctls); err != nil {
+		t.Fatal(err)
+	}
+
+	// the ipv4 forwarding was enabled by the user and is not recorded
+	checkLedger(t, ledgerPath, forwardingLedger{
+		ipv6ForwardingSysctl: {Previous: "0", Labs: []string{"lab1", "lab2"}},
+	})
+
+	// destroying a lab that didn't acquire anything leaves the ledger intact
+	if err := releaseForwarding(ledgerPath, "lab3"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, lab := range []string{"lab1", "lab2"} {
+		if err := releaseForwarding(ledgerPath, lab); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkSysctls(t, map[string]string{ipv4ForwardingSysctl: "1", ipv6ForwardingSysctl: "0"})
+}
+
+func TestForwardingConcurrentLabs(t *testing.T) {
+	setupSysctls(t, map[string]string{ipv4ForwardingSysctl: "0"})
+
+	ledgerPath := filepath.Join(t.TempDir(), forwardingLedgerFile)
+
+	labs := []string{"lab1", "lab2", "lab3", "lab4", "lab5", "lab6", "lab7", "lab8"}
+
+	// run calls f for all the labs at once and fails on the first error
+	run := func(f func(lab string) error) {
+		t.Helper()
+
+		errs := make(chan error, len(labs))
+
+		var wg sync.WaitGroup
+		for _, lab := range labs {
+			wg.Add(1)
+
+			go func(lab string) {
+				defer wg.Done()
+				errs <- f(lab)
+			}(lab)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	run(func(lab string) error {
+		return acquireForwarding(ledgerPath, lab, []string{ipv4ForwardingSysctl})
+	})
+
+	ledger, err := loadForwardingLedger(ledgerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// none of the concurrently deployed labs is lost
+	got := append([]string(nil), ledger[ipv4ForwardingSysctl].Labs...)
+	sort.Strings(got)
+
+	if d := cmp.Diff(labs, got); d != "" {
+		t.Errorf("ledger labs mismatch (-want +got):\n%s", d)
+	}
+
+	// the sysctl is restored only after the last of the concurrently destroyed labs is gone
+	run(func(lab string) error {
+		if err := releaseForwarding(ledgerPath, lab); err != nil {
+			return err
+		}
+
+		l, err := loadForwardingLedger(ledgerPath)
+		if err != nil {
+			return err
+		}
+
+		v, err := readSysctl(ipv4ForwardingSysctl)
+		if err != nil {
+			return err
+		}
+
+		if len(l) != 0 && v != "1" {
+			return fmt.Errorf("sysctl restored while labs %q still need it", l[ipv4ForwardingSysctl].Labs)
+		}
+
+		return nil
+	})
+
+	checkSysctls(t, map[string]string{ipv4ForwardingSysctl: "0"})
+
+	if _, err := os.Stat(ledgerPath); !os.IsNotExist(err) {
+		t.Errorf("ledger file exists after the last lab is destroyed, stat error: %v", err)
+	}
+}
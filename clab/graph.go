@@ -23,6 +23,9 @@ import (
 	"github.com/srl-labs/containerlab/utils"
 )
 
+// disabledNodeGraphState is the state of the disabled nodes in the graph.
+const disabledNodeGraphState = "disabled"
+
 type GraphTopo struct {
 	Nodes []types.ContainerDetails `json:"nodes,omitempty"`
 	Links []Link                   `json:"links,omitempty"`
@@ -49,7 +52,8 @@ type noListFs struct {
 var g *gographviz.Graph
 
 // GenerateDotGraph generates a graph of the lab topology.
-func (c *CLab) GenerateDotGraph() error {
+// When showDisabled is set, the disabled nodes are added to the graph and greyed out.
+func (c *CLab) GenerateDotGraph(showDisabled bool) error {
 	log.Info("Generating lab graph...")
 	g = gographviz.NewGraph()
 	if err := g.SetName(c.TopoPaths.TopologyFilenameWithoutExt()); err != nil {
@@ -89,6 +93,23 @@ func (c *CLab) GenerateDotGraph() error {
 
 	}
 
+	if showDisabled {
+		for _, node := range c.DisabledNodes {
+			attr = map[string]string{
+				"color":     "grey",
+				"style":     "\"filled,dashed\"",
+				"fillcolor": "lightgrey",
+				"fontcolor": "grey",
+				"label":     node.Config().ShortName,
+				"xlabel":    node.Config().Kind,
+			}
+			if err := g.AddNode(c.TopoPaths.TopologyFilenameWithoutExt(),
+				node.Config().ShortName, attr); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Process the links inbetween Nodes
 	for _, link := range c.Links {
 		attr = make(map[string]string)
@@ -190,6 +211,16 @@ func (c *CLab) BuildGraphFromTopo(g *GraphTopo) {
 	}
 }
 
+// AddDisabledNodesToGraph adds the disabled nodes to the graph with the "disabled" state,
+// which makes the graph to render them greyed out.
+func (c *CLab) AddDisabledNodesToGraph(g *GraphTopo) {
+	for _, node := range c.DisabledNodes {
+		n := buildGraphNode(node)
+		n.State = disabledNodeGraphState
+		g.Nodes = append(g.Nodes, n)
+	}
+}
+
 func (c *CLab) BuildGraphFromDeployedLab(g *GraphTopo, containers []runtime.GenericContainer) {
 	containerNames := make(map[string]struct{})
 	for _, cont := range containers {
@@ -0,0 +1,274 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// GraphSchemaVersion is the version of the graph document schema.
+// It is bumped when the fields of the document are changed in a backward incompatible way.
+const GraphSchemaVersion = 1
+
+const (
+	// LinkStateUp is the state of the link which endpoints are all operationally up.
+	LinkStateUp = "up"
+	// LinkStateDown is the state of the link with at least one endpoint not operationally up.
+	LinkStateDown = "down"
+)
+
+// GraphDocument is the lab topology enriched with the state of the deployed lab,
+// serialized for the external tools by the graph command.
+// The state fields are null when the lab is not deployed or the state can't be retrieved.
+type GraphDocument struct {
+	SchemaVersion int          `json:"schema_version"`
+	Lab           GraphLab     `json:"lab"`
+	Nodes         []*GraphNode `json:"nodes"`
+	Links         []*GraphLink `json:"links"`
+}
+
+// GraphLab is the lab metadata of the graph document.
+type GraphLab struct {
+	Name         string    `json:"name"`
+	TopologyFile string    `json:"topology_file"`
+	Deployed     bool      `json:"deployed"`
+	Mgmt         GraphMgmt `json:"mgmt"`
+}
+
+// GraphMgmt is the management network of the lab.
+type GraphMgmt struct {
+	Network    string  `json:"network"`
+	Bridge     *string `json:"bridge"`
+	IPv4Subnet *string `json:"ipv4_subnet"`
+	IPv6Subnet *string `json:"ipv6_subnet"`
+}
+
+// GraphNode is a lab node of the graph document.
+type GraphNode struct {
+	Name   string            `json:"name"`
+	Kind   string            `json:"kind"`
+	Image  string            `json:"image"`
+	Group  string            `json:"group"`
+	Labels map[string]string `json:"labels"`
+	// MgmtIPv4Address and MgmtIPv6Address are the addresses assigned by the runtime for the deployed nodes
+	// and the addresses set in the topology otherwise.
+	MgmtIPv4Address *string `json:"mgmt_ipv4_address"`
+	MgmtIPv6Address *string `json:"mgmt_ipv6_address"`
+	// State is the container state reported by the runtime, e.g. running.
+	State *string `json:"state"`
+	// Status is the human-readable container status reported by the runtime.
+	Status *string `json:"status"`
+	// Health is one of healthy, unhealthy and starting for the containers with a health check.
+	Health *string `json:"health"`
+}
+
+// GraphLink is a link of the graph document.
+type GraphLink struct {
+	Type      string           `json:"type"`
+	MTU       int              `json:"mtu"`
+	Endpoints []*GraphEndpoint `json:"endpoints"`
+	// State is one of up and down when the operational state of all the link endpoints is retrieved.
+	State *string `json:"state"`
+}
+
+// GraphEndpoint is a link endpoint of the graph document.
+type GraphEndpoint struct {
+	Node      string `json:"node"`
+	Interface string `json:"interface"`
+	// NOSInterface is the name of the interface used by the network OS of the node.
+	NOSInterface string  `json:"nos_interface"`
+	MAC          *string `json:"mac"`
+}
+
+// endpointOperState returns the operational state of the link endpoint interface.
+// It is a variable to allow tests to stub the netns lookup.
+var endpointOperState = func(ep links.Endpoint) (string, error) {
+	var state string
+
+	err := ep.GetNode().ExecFunction(func(_ ns.NetNS) error {
+		l, err := utils.LinkByNameOrAlias(ep.GetIfaceName())
+		if err != nil {
+			return err
+		}
+
+		state = l.Attrs().OperState.String()
+
+		return nil
+	})
+
+	return state, err
+}
+
+// BuildGraphDocument builds the graph document of the lab from the resolved topology.
+// The containers of the deployed lab enrich the document with the node states and the link states,
+// the link states are read from the netns of the nodes, so their netns paths are expected to be set.
+// Without containers the document describes the topology only.
+func (c *CLab) BuildGraphDocument(containers []runtime.GenericContainer) *GraphDocument {
+	doc := &GraphDocument{
+		SchemaVersion: GraphSchemaVersion,
+		Lab: GraphLab{
+			Name:         c.Config.Name,
+			TopologyFile: c.TopoPaths.TopologyFilenameAbsPath(),
+			Deployed:     len(containers) > 0,
+			Mgmt: GraphMgmt{
+				Network:    c.Config.Mgmt.Network,
+				Bridge:     optionalString(c.Config.Mgmt.Bridge),
+				IPv4Subnet: optionalString(c.Config.Mgmt.IPv4Subnet),
+				IPv6Subnet: optionalString(c.Config.Mgmt.IPv6Subnet),
+			},
+		},
+		Nodes: make([]*GraphNode, 0, len(c.Nodes)),
+		Links: make([]*GraphLink, 0, len(c.Links)),
+	}
+
+	nodeContainers := map[string]*runtime.GenericContainer{}
+	for i := range containers {
+		nodeContainers[containers[i].Labels[labels.NodeName]] = &containers[i]
+	}
+
+	for name, n := range c.Nodes {
+		doc.Nodes = append(doc.Nodes, buildGraphDocumentNode(n, nodeContainers[name]))
+	}
+
+	sort.Slice(doc.Nodes, func(i, j int) bool {
+		return doc.Nodes[i].Name < doc.Nodes[j].Name
+	})
+
+	linkIdxs := make([]int, 0, len(c.Links))
+	for i := range c.Links {
+		linkIdxs = append(linkIdxs, i)
+	}
+	sort.Ints(linkIdxs)
+
+	for _, i := range linkIdxs {
+		doc.Links = append(doc.Links, c.buildGraphDocumentLink(c.Links[i], doc.Lab.Deployed))
+	}
+
+	return doc
+}
+
+// buildGraphDocumentNode builds the graph document node of the node n and its container cnt.
+// The container is nil for the nodes which are not deployed.
+func buildGraphDocumentNode(n nodes.Node, cnt *runtime.GenericContainer) *GraphNode {
+	cfg := n.Config()
+
+	gn := &GraphNode{
+		Name:            cfg.ShortName,
+		Kind:            cfg.Kind,
+		Image:           cfg.Image,
+		Group:           cfg.Group,
+		Labels:          cfg.Labels,
+		MgmtIPv4Address: optionalString(cfg.MgmtIPv4Address),
+		MgmtIPv6Address: optionalString(cfg.MgmtIPv6Address),
+	}
+
+	if gn.Labels == nil {
+		gn.Labels = map[string]string{}
+	}
+
+	if cnt == nil {
+		return gn
+	}
+
+	gn.MgmtIPv4Address = optionalString(cnt.NetworkSettings.IPv4addr)
+	gn.MgmtIPv6Address = optionalString(cnt.NetworkSettings.IPv6addr)
+	gn.State = optionalString(cnt.State)
+	gn.Status = optionalString(cnt.Status)
+	gn.Health = containerHealth(cnt.Status)
+
+	return gn
+}
+
+// buildGraphDocumentLink builds the graph document link of the link l.
+// The link state is retrieved for the deployed labs only.
+func (c *CLab) buildGraphDocumentLink(l links.Link, deployed bool) *GraphLink {
+	gl := &GraphLink{
+		Type: string(l.GetType()),
+		MTU:  l.GetMTU(),
+	}
+
+	for _, ep := range l.GetEndpoints() {
+		gep := &GraphEndpoint{
+			Node:         ep.GetNode().GetShortName(),
+			Interface:    ep.GetIfaceName(),
+			NOSInterface: ep.GetIfaceName(),
+		}
+
+		// the special nodes like host and mgmt-net are not part of the lab nodes
+		if n, ok := c.Nodes[gep.Node]; ok {
+			gep.NOSInterface = nosInterfaceName(n, ep)
+		}
+
+		if mac := ep.GetMac(); len(mac) > 0 {
+			gep.MAC = optionalString(mac.String())
+		}
+
+		gl.Endpoints = append(gl.Endpoints, gep)
+	}
+
+	if deployed {
+		gl.State = linkState(l)
+	}
+
+	return gl
+}
+
+// linkState returns the state of the link derived from the operational states of its endpoints,
+// or nil when the state of an endpoint can't be retrieved.
+func linkState(l links.Link) *string {
+	state := LinkStateUp
+
+	for _, ep := range l.GetEndpoints() {
+		s, err := endpointOperState(ep)
+		if err != nil {
+			log.Debugf("failed to get the state of endpoint %s: %v", ep, err)
+			return nil
+		}
+
+		// the veth interfaces of the containers report the unknown state when up
+		if s != "up" && s != "unknown" {
+			state = LinkStateDown
+		}
+	}
+
+	return &state
+}
+
+// containerHealth returns the health of the container extracted from its status,
+// e.g. "Up 2 minutes (healthy)". Nil is returned for the containers without a health check.
+func containerHealth(status string) *string {
+	var health string
+
+	switch {
+	case strings.Contains(status, "(healthy)"):
+		health = "healthy"
+	case strings.Contains(status, "(unhealthy)"):
+		health = "unhealthy"
+	case strings.Contains(status, "(health: starting)"):
+		health = "starting"
+	default:
+		return nil
+	}
+
+	return &health
+}
+
+// optionalString returns the pointer to s or nil when s is empty.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
@@ -0,0 +1,302 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/schemas"
+)
+
+const graphTopo = "test_data/topo21-graph.yml"
+
+// newGraphTestLab creates the lab of the graph test topology with the lab directory in a temporary directory.
+// The temporary directory is returned along with the lab.
+func newGraphTestLab(t *testing.T) (*CLab, string) {
+	t.Helper()
+
+	labBase := t.TempDir()
+	t.Setenv("CLAB_LABDIR_BASE", labBase)
+
+	c, err := NewContainerLab(WithTopoPath(graphTopo, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	return c, labBase
+}
+
+func TestGraphDocumentGolden(t *testing.T) {
+	c, labBase := newGraphTestLab(t)
+
+	b, err := json.MarshalIndent(c.BuildGraphDocument(nil), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topoDir, err := filepath.Abs(filepath.Dir(graphTopo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.NewReplacer(labBase, "$LAB_DIR", topoDir, "$TOPO_DIR").Replace(string(b)) + "\n"
+
+	want, err := os.ReadFile("test_data/graph-topo21.golden.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff(string(want), got); d != "" {
+		t.Errorf("graph document mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestGraphDocumentDeployed(t *testing.T) {
+	c, _ := newGraphTestLab(t)
+
+	origOperState := endpointOperState
+	t.Cleanup(func() { endpointOperState = origOperState })
+
+	endpointOperState = func(ep links.Endpoint) (string, error) {
+		switch ep.GetIfaceName() {
+		case "e1-1":
+			return "up", nil
+		case "e1-3-1":
+			return "down", nil
+		case "eth2":
+			return "", errors.New("link not found")
+		}
+
+		return "unknown", nil
+	}
+
+	containers := []runtime.GenericContainer{
+		{
+			State:  "running",
+			Status: "Up 2 minutes (healthy)",
+			Labels: map[string]string{labels.NodeName: "srl1"},
+			NetworkSettings: runtime.GenericMgmtIPs{
+				IPv4addr: "172.100.100.11",
+				IPv6addr: "3fff:172:100:100::11",
+			},
+		},
+	}
+
+	doc := c.BuildGraphDocument(containers)
+
+	if !doc.Lab.Deployed {
+		t.Error("lab is not reported as deployed")
+	}
+
+	nodes := map[string]*GraphNode{}
+	for _, n := range doc.Nodes {
+		nodes[n.Name] = n
+	}
+
+	srl1 := nodes["srl1"]
+	if srl1.State == nil || *srl1.State != "running" || srl1.Health == nil || *srl1.Health != "healthy" {
+		t.Errorf("got srl1 state %v and health %v, want running and healthy", srl1.State, srl1.Health)
+	}
+
+	if srl1.MgmtIPv6Address == nil || *srl1.MgmtIPv6Address != "3fff:172:100:100::11" {
+		t.Errorf("got srl1 mgmt IPv6 address %v, want the runtime assigned one", srl1.MgmtIPv6Address)
+	}
+
+	// l1 has no container
+	if nodes["l1"].State != nil {
+		t.Errorf("got l1 state %q, want null", *nodes["l1"].State)
+	}
+
+	if s := doc.Links[0].State; s == nil || *s != LinkStateUp {
+		t.Errorf("got first link state %v, want %q", s, LinkStateUp)
+	}
+
+	// the state of the second link endpoint of the second link can't be retrieved
+	if s := doc.Links[1].State; s != nil {
+		t.Errorf("got second link state %q, want null", *s)
+	}
+}
+
+func TestContainerHealth(t *testing.T) {
+	tests := map[string]*string{
+		"Up 2 minutes (healthy)":          optionalString("healthy"),
+		"Up 5 seconds (health: starting)": optionalString("starting"),
+		"Up About an hour (unhealthy)":    optionalString("unhealthy"),
+		"Up 3 hours":                      nil,
+		"Exited (137) 2 minutes ago":      nil,
+		"":                                nil,
+	}
+
+	for status, want := range tests {
+		if d := cmp.Diff(want, containerHealth(status)); d != "" {
+			t.Errorf("health of status %q mismatch (-want +got):\n%s", status, d)
+		}
+	}
+}
+
+// TestGraphDocumentSchema checks that the graph documents of the offline and the deployed lab
+// conform to the embedded JSON schema: all the required fields are present,
+// no fields are missing from the schema and the values have the types allowed by the schema.
+func TestGraphDocumentSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(schemas.Graph, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := newGraphTestLab(t)
+
+	origOperState := endpointOperState
+	t.Cleanup(func() { endpointOperState = origOperState })
+
+	endpointOperState = func(links.Endpoint) (string, error) { return "up", nil }
+
+	for name, containers := range map[string][]runtime.GenericContainer{
+		"offline": nil,
+		"deployed": {{
+			State:  "running",
+			Status: "Up 2 minutes (health: starting)",
+			Labels: map[string]string{labels.NodeName: "srl1"},
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := json.Marshal(c.BuildGraphDocument(containers))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var doc any
+			if err := json.Unmarshal(b, &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, err := range validateSchema(schema, schema, doc, "$") {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// validateSchema validates the decoded JSON value v by the path p against the schema s.
+// Only the subset of the JSON schema used by the graph schema is supported:
+// $ref, type, enum, const, properties, required, additionalProperties and items.
+func validateSchema(root, s map[string]any, v any, p string) []error {
+	if ref, ok := s["$ref"].(string); ok {
+		def := root["definitions"].(map[string]any)[strings.TrimPrefix(ref, "#/definitions/")]
+		return validateSchema(root, def.(map[string]any), v, p)
+	}
+
+	var errs []error
+
+	if enum, ok := s["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || e == v
+		}
+
+		if !found {
+			errs = append(errs, errors.New(p+": value is not in the enum"))
+		}
+	}
+
+	if c, ok := s["const"]; ok && c != v {
+		errs = append(errs, errors.New(p+": value doesn't match the const"))
+	}
+
+	if types, ok := s["type"]; ok {
+		allowed := map[string]bool{}
+
+		switch tt := types.(type) {
+		case string:
+			allowed[tt] = true
+		case []any:
+			for _, t := range tt {
+				allowed[t.(string)] = true
+			}
+		}
+
+		if !allowed[jsonType(v)] && !(jsonType(v) == "integer" && allowed["number"]) {
+			errs = append(errs, errors.New(p+": value of type "+jsonType(v)+" is not allowed"))
+		}
+	}
+
+	switch vv := v.(type) {
+	case map[string]any:
+		props, _ := s["properties"].(map[string]any)
+
+		required, _ := s["required"].([]any)
+		for _, r := range required {
+			if _, ok := vv[r.(string)]; !ok {
+				errs = append(errs, errors.New(p+": required field "+r.(string)+" is missing"))
+			}
+		}
+
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if ps, ok := props[k]; ok {
+				errs = append(errs, validateSchema(root, ps.(map[string]any), vv[k], p+"."+k)...)
+				continue
+			}
+
+			switch ap := s["additionalProperties"].(type) {
+			case map[string]any:
+				errs = append(errs, validateSchema(root, ap, vv[k], p+"."+k)...)
+			case bool:
+				if !ap {
+					errs = append(errs, errors.New(p+": field "+k+" is not defined in the schema"))
+				}
+			}
+		}
+	case []any:
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range vv {
+				errs = append(errs, validateSchema(root, items, item, p+"["+strconv.Itoa(i)+"]")...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// jsonType returns the JSON schema type name of the decoded JSON value v.
+func jsonType(v any) string {
+	switch vv := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if vv == float64(int64(vv)) {
+			return "integer"
+		}
+
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGraphDisabledNodes(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo13-disabled.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(c.TopoPaths.TopologyLabDir()) })
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	gtopo := &GraphTopo{}
+	c.BuildGraphFromTopo(gtopo)
+
+	states := map[string]string{}
+	for _, n := range gtopo.Nodes {
+		states[n.Name] = n.State
+	}
+
+	if _, ok := states["n2"]; ok {
+		t.Error("disabled node n2 is part of the graph")
+	}
+
+	c.AddDisabledNodesToGraph(gtopo)
+
+	states = map[string]string{}
+	for _, n := range gtopo.Nodes {
+		states[n.Name] = n.State
+	}
+
+	if states["n2"] != disabledNodeGraphState {
+		t.Errorf("got state %q for disabled node n2, want %q", states["n2"], disabledNodeGraphState)
+	}
+
+	if err := c.GenerateDotGraph(false); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(g.String(), "n2") {
+		t.Errorf("disabled node n2 is part of the dot graph:\n%s", g.String())
+	}
+
+	if err := c.GenerateDotGraph(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(g.String(), "fillcolor=lightgrey") || !strings.Contains(g.String(), "n2") {
+		t.Errorf("disabled node n2 is not greyed out in the dot graph:\n%s", g.String())
+	}
+}
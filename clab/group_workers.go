@@ -0,0 +1,51 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import "context"
+
+// groupLimiter limits the number of the nodes of a group deployed concurrently.
+// The groups without a limit are not limited by the group limiter,
+// the nodes of all groups are still limited by the global number of workers.
+type groupLimiter struct {
+	// slots are the semaphores of the limited groups keyed by the group name
+	slots map[string]chan struct{}
+}
+
+// newGroupLimiter returns the group limiter with the limits keyed by the group name.
+func newGroupLimiter(limits map[string]uint) *groupLimiter {
+	g := &groupLimiter{slots: make(map[string]chan struct{}, len(limits))}
+
+	for name, l := range limits {
+		if l > 0 {
+			g.slots[name] = make(chan struct{}, l)
+		}
+	}
+
+	return g
+}
+
+// acquire blocks until a node of the group can be deployed or the context is canceled.
+// It returns false when the context is canceled before the slot is acquired.
+func (g *groupLimiter) acquire(ctx context.Context, group string) bool {
+	s, ok := g.slots[group]
+	if !ok {
+		return true
+	}
+
+	select {
+	case s <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release frees the slot of the group acquired for a node.
+func (g *groupLimiter) release(group string) {
+	if s, ok := g.slots[group]; ok {
+		<-s
+	}
+}
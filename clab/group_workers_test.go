@@ -0,0 +1,148 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/clab/dependency_manager"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestGroupLimiter(t *testing.T) {
+	g := newGroupLimiter(map[string]uint{"spine": 1, "zero": 0})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if !g.acquire(ctx, "spine") {
+		t.Fatal("failed to acquire the free spine slot")
+	}
+
+	// the groups without a limit are never blocked
+	for i := 0; i < 3; i++ {
+		if !g.acquire(ctx, "leaf") || !g.acquire(ctx, "zero") {
+			t.Fatal("failed to acquire the slot of the unlimited group")
+		}
+	}
+
+	acquired := make(chan bool)
+	go func() { acquired <- g.acquire(ctx, "spine") }()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired the second spine slot while the group limit is 1")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	g.release("spine")
+
+	if !<-acquired {
+		t.Fatal("failed to acquire the released spine slot")
+	}
+
+	// the canceled context unblocks the waiting node
+	go func() { acquired <- g.acquire(ctx, "spine") }()
+	cancel()
+
+	if <-acquired {
+		t.Error("acquired the spine slot after the context is canceled")
+	}
+
+	g.release("leaf")
+}
+
+func TestScheduleNodesGroupWorkers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	const globalWorkers = 4
+
+	var (
+		mu         sync.Mutex
+		running    = map[string]int{}
+		maxRunning = map[string]int{}
+		total      int
+		maxTotal   int
+	)
+
+	// deploying records the number of the nodes deployed concurrently
+	deploying := func(group string) {
+		mu.Lock()
+		running[group]++
+		total++
+		if running[group] > maxRunning[group] {
+			maxRunning[group] = running[group]
+		}
+		if total > maxTotal {
+			maxTotal = total
+		}
+		mu.Unlock()
+
+		time.Sleep(30 * time.Millisecond)
+
+		mu.Lock()
+		running[group]--
+		total--
+		mu.Unlock()
+	}
+
+	nodeMap := map[string]nodes.Node{}
+	dm := dependency_manager.NewDependencyManager()
+
+	for group, count := range map[string]int{"spine": 3, "leaf": 8} {
+		for i := 1; i <= count; i++ {
+			name := fmt.Sprintf("%s%d", group, i)
+			group := group
+
+			n := mocknodes.NewMockNode(mockCtrl)
+			n.EXPECT().Config().Return(&types.NodeConfig{ShortName: name, Group: group}).AnyTimes()
+			n.EXPECT().GetShortName().Return(name).AnyTimes()
+			n.EXPECT().GetEndpoints().Return(nil).AnyTimes()
+			n.EXPECT().PreDeploy(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(context.Context, *nodes.PreDeployParams) error {
+					deploying(group)
+					return nil
+				})
+			n.EXPECT().Deploy(gomock.Any(), gomock.Any()).Return(nil)
+			n.EXPECT().DeployLinks(gomock.Any()).Return(nil)
+
+			nodeMap[name] = n
+			dm.AddNode(name)
+		}
+	}
+
+	c := &CLab{
+		Config: &Config{
+			Topology: &types.Topology{
+				Groups: map[string]*types.GroupDefinition{
+					"spine": {MaxWorkers: 1},
+				},
+			},
+		},
+		Nodes: nodeMap,
+	}
+
+	c.scheduleNodes(context.Background(), globalWorkers, nodeMap, dm).Wait()
+
+	if maxRunning["spine"] != 1 {
+		t.Errorf("got %d spine nodes deployed concurrently, want 1", maxRunning["spine"])
+	}
+
+	if maxRunning["leaf"] < 2 {
+		t.Errorf("got %d leaf nodes deployed concurrently, want the leaf nodes deployed in parallel",
+			maxRunning["leaf"])
+	}
+
+	if maxTotal > globalWorkers {
+		t.Errorf("got %d nodes deployed concurrently, want at most %d", maxTotal, globalWorkers)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// DefaultHealthcheckTimeout is the maximum time a node waits for another one to become healthy
+	// when neither the node nor the deploy command set the timeout.
+	DefaultHealthcheckTimeout = 5 * time.Minute
+	// defaultHealthcheckInterval is the interval between the checks of the container health.
+	defaultHealthcheckInterval = time.Second
+)
+
+// healthcheckTimeout returns the maximum time to wait for the node to become healthy or to exit.
+// The timeout of the node takes precedence over the one set for the lab.
+func (c *CLab) healthcheckTimeout(nodeName string) time.Duration {
+	if n, ok := c.Nodes[nodeName]; ok && n.Config().HealthcheckTimeout > 0 {
+		return n.Config().HealthcheckTimeout
+	}
+
+	if c.healthcheckTimeoutDefault > 0 {
+		return c.healthcheckTimeoutDefault
+	}
+
+	return DefaultHealthcheckTimeout
+}
+
+// WaitForHealthy blocks until the health check of the node container reports it healthy,
+// the timeout expires or the context is done. The health is polled every healthcheck interval.
+// The node container without a health check is considered healthy once it is running.
+func (c *CLab) WaitForHealthy(ctx context.Context, nodeName string, timeout time.Duration) error {
+	n, ok := c.Nodes[nodeName]
+	if !ok {
+		return fmt.Errorf("unable to find node %q", nodeName)
+	}
+
+	interval := c.healthcheckInterval
+	if interval <= 0 {
+		interval = defaultHealthcheckInterval
+	}
+
+	return runtime.WaitForContainerHealthy(ctx, n.GetRuntime(), n.Config().LongName, nodeName, timeout, interval)
+}
+
+// waitForHealthyDependencies blocks until the nodes the node waits for in the healthy phase
+// (wait-for: [<node>:healthy]) become healthy. The nodes that don't become healthy within their
+// healthcheck timeout are logged and not waited for anymore, so that the waiting node is deployed regardless.
+func (c *CLab) waitForHealthyDependencies(ctx context.Context, cfg *types.NodeConfig) {
+	for _, w := range cfg.WaitFor {
+		if w.Phase != types.WaitForPhaseHealthy {
+			continue
+		}
+
+		dep := w.Node
+
+		timeout := c.healthcheckTimeout(dep)
+
+		log.Infof("node %q waits up to %s for node %q to become healthy", cfg.ShortName, timeout, dep)
+
+		if err := c.WaitForHealthy(ctx, dep, timeout); err != nil {
+			log.Warnf("node %q stops waiting for node %q to become healthy and is deployed regardless: %v",
+				cfg.ShortName, dep, err)
+		}
+	}
+}
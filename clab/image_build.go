@@ -0,0 +1,145 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// imageBuild is an image built once for all the lab nodes using it.
+type imageBuild struct {
+	runtime runtime.ContainerRuntime
+	spec    *types.ImageBuild
+	// nodes are the names of the nodes using the image
+	nodes []string
+}
+
+// nodeImageBuild returns the build of the image of the node, or nil if the node doesn't use the built image,
+// like the kinds that don't create a container.
+func nodeImageBuild(ctx context.Context, n nodes.Node) *types.ImageBuild {
+	spec := n.Config().ImageBuild
+	if spec == nil {
+		return nil
+	}
+
+	for _, img := range n.GetImages(ctx) {
+		if img == spec.Tag {
+			return spec
+		}
+	}
+
+	return nil
+}
+
+// imageBuilds returns the unique images built for the lab nodes, identified by the runtime
+// and the build tag, sorted by the tag.
+func (c *CLab) imageBuilds(ctx context.Context) []*imageBuild {
+	builds := map[string]*imageBuild{}
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		n := c.Nodes[name]
+		spec := nodeImageBuild(ctx, n)
+		if spec == nil || n.GetRuntime() == nil {
+			continue
+		}
+
+		key := n.GetRuntime().GetName() + "/" + spec.Tag
+
+		b, ok := builds[key]
+		if !ok {
+			b = &imageBuild{runtime: n.GetRuntime(), spec: spec}
+			builds[key] = b
+		}
+
+		b.nodes = append(b.nodes, name)
+	}
+
+	res := make([]*imageBuild, 0, len(builds))
+	for _, b := range builds {
+		res = append(res, b)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].spec.Tag < res[j].spec.Tag
+	})
+
+	return res
+}
+
+// buildImages builds the unique images of the lab nodes from their build contexts before the nodes are created.
+// The built images are marked pulled in the configs of the nodes using them, so that the nodes don't pull them.
+func (c *CLab) buildImages(ctx context.Context) error {
+	for _, b := range c.imageBuilds(ctx) {
+		if err := b.runtime.BuildImage(ctx, b.spec); err != nil {
+			return fmt.Errorf("nodes %v: %w", b.nodes, err)
+		}
+
+		for _, name := range b.nodes {
+			markImagePulled(c.Nodes[name], b.spec.Tag)
+		}
+	}
+
+	return nil
+}
+
+// verifyImageBuilds checks that the build contexts of the node images exist and contain the Dockerfiles
+// and that the nodes building the images with the same tag build them from the same context.
+func (c *CLab) verifyImageBuilds() error {
+	tags := map[string]*types.ImageBuild{}
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		b := nodeImageBuild(context.Background(), c.Nodes[name])
+		if b == nil {
+			continue
+		}
+
+		if b.Tag == "" {
+			return fmt.Errorf("node %q: image-build requires either the tag or the node image to be set", name)
+		}
+
+		if b.Context == "" {
+			return fmt.Errorf("node %q: image-build context is not set", name)
+		}
+
+		if !utils.DirExists(b.Context) {
+			return fmt.Errorf("node %q: image-build context %q is not a directory", name, b.Context)
+		}
+
+		if !utils.FileExists(filepath.Join(b.Context, b.GetDockerfile())) {
+			return fmt.Errorf("node %q: dockerfile %q not found in the image-build context %q",
+				name, b.GetDockerfile(), b.Context)
+		}
+
+		if other, ok := tags[b.Tag]; ok && (other.Context != b.Context || other.GetDockerfile() != b.GetDockerfile()) {
+			return fmt.Errorf("node %q: image %s is built from different contexts or dockerfiles", name, b.Tag)
+		}
+
+		tags[b.Tag] = b
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestBuildImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	app := &types.ImageBuild{Context: "/src/app", Tag: "lab/app"}
+
+	cfgs := map[string]*types.NodeConfig{
+		"app1":   {ShortName: "app1", Image: "lab/app", ImageBuild: app},
+		"app2":   {ShortName: "app2", Image: "lab/app", ImageBuild: app},
+		"tool":   {ShortName: "tool", Image: "lab/tool", ImageBuild: &types.ImageBuild{Context: "/src/tool", Tag: "lab/tool"}},
+		"client": {ShortName: "client", Image: "alpine"},
+		// the nodes that don't create a container don't build the image
+		"br": {ShortName: "br", Image: "lab/br", ImageBuild: &types.ImageBuild{Context: "/src/br", Tag: "lab/br"}},
+	}
+
+	var builds []string
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().GetName().Return("docker").AnyTimes()
+	rt.EXPECT().BuildImage(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, spec *types.ImageBuild) error {
+			builds = append(builds, spec.Tag)
+			return nil
+		}).AnyTimes()
+
+	c := &CLab{Nodes: map[string]nodes.Node{}}
+
+	for name, cfg := range cfgs {
+		images := map[string]string{nodes.ImageKey: cfg.Image}
+		if name == "br" {
+			images = map[string]string{}
+		}
+
+		mn := mocknodes.NewMockNode(ctrl)
+		mn.EXPECT().Config().Return(cfg).AnyTimes()
+		mn.EXPECT().GetRuntime().Return(rt).AnyTimes()
+		mn.EXPECT().GetImages(gomock.Any()).Return(images).AnyTimes()
+
+		c.Nodes[name] = mn
+	}
+
+	if err := c.buildImages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]string{"lab/app", "lab/tool"}, builds); d != "" {
+		t.Errorf("built images mismatch (-want +got):\n%s", d)
+	}
+
+	for name, cfg := range cfgs {
+		built := cfg.PulledImages[cfg.Image]
+		if want := name != "client" && name != "br"; built != want {
+			t.Errorf("node %s image pulled mark is %v, want %v", name, built, want)
+		}
+	}
+
+	// the built images are not pulled
+	for _, p := range c.imagePulls(context.Background()) {
+		if p.image != "alpine" {
+			t.Errorf("built image %s is pulled", p.image)
+		}
+	}
+}
+
+func TestVerifyImageBuilds(t *testing.T) {
+	dir, other := t.TempDir(), t.TempDir()
+	for _, d := range []string{dir, other} {
+		if err := os.WriteFile(filepath.Join(d, "Dockerfile"), []byte("FROM alpine"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		builds  map[string]*types.ImageBuild
+		wantErr bool
+	}{
+		"valid": {
+			builds: map[string]*types.ImageBuild{
+				"n1": {Context: dir, Tag: "lab/app"},
+				"n2": {Context: dir, Tag: "lab/app", Dockerfile: "Dockerfile"},
+			},
+		},
+		"no tag": {
+			builds:  map[string]*types.ImageBuild{"n1": {Context: dir}},
+			wantErr: true,
+		},
+		"missing context": {
+			builds:  map[string]*types.ImageBuild{"n1": {Context: filepath.Join(dir, "missing"), Tag: "lab/app"}},
+			wantErr: true,
+		},
+		"missing dockerfile": {
+			builds:  map[string]*types.ImageBuild{"n1": {Context: dir, Dockerfile: "app.Dockerfile", Tag: "lab/app"}},
+			wantErr: true,
+		},
+		"same tag different contexts": {
+			builds: map[string]*types.ImageBuild{
+				"n1": {Context: dir, Tag: "lab/app"},
+				"n2": {Context: other, Tag: "lab/app"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c := &CLab{Nodes: map[string]nodes.Node{}}
+
+			for n, b := range tt.builds {
+				mn := mocknodes.NewMockNode(ctrl)
+				mn.EXPECT().Config().Return(&types.NodeConfig{ShortName: n, Image: b.Tag, ImageBuild: b}).AnyTimes()
+				mn.EXPECT().GetImages(gomock.Any()).Return(map[string]string{nodes.ImageKey: b.Tag}).AnyTimes()
+
+				c.Nodes[n] = mn
+			}
+
+			if err := c.verifyImageBuilds(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
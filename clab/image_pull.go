@@ -0,0 +1,184 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	goruntime "runtime"
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// imagePull is an image pulled once for all the lab nodes using it.
+type imagePull struct {
+	runtime  runtime.ContainerRuntime
+	image    string
+	platform string
+	policy   types.PullPolicyValue
+	// nodes are the image names as referenced by the nodes using the image, keyed by the node name
+	nodes map[string]string
+}
+
+// imagePulls returns the unique images of the lab nodes, identified by the runtime,
+// the canonical image name and the platform, sorted by the image name.
+// The images of the archives and of the nodes with the never pull policy are left to the nodes,
+// the images built from the build contexts are not pulled.
+// An image is pulled with the always policy if any of the nodes using it has that policy.
+func (c *CLab) imagePulls(ctx context.Context) []*imagePull {
+	pulls := map[string]*imagePull{}
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+
+	// the image name of the first node using the image is pulled
+	sort.Strings(names)
+
+	for _, name := range names {
+		n := c.Nodes[name]
+		cfg := n.Config()
+		if cfg.ImagePullPolicy == types.PullPolicyNever || n.GetRuntime() == nil {
+			continue
+		}
+
+		for _, img := range n.GetImages(ctx) {
+			if img == "" {
+				continue
+			}
+
+			if _, ok := utils.ImageArchivePath(img); ok {
+				continue
+			}
+
+			// the image built from the build context is not pulled
+			if cfg.ImageBuild != nil && img == cfg.Image {
+				continue
+			}
+
+			key := n.GetRuntime().GetName() + "/" + utils.GetCanonicalImageName(img) + "/" + cfg.Platform
+
+			p, ok := pulls[key]
+			if !ok {
+				p = &imagePull{
+					runtime:  n.GetRuntime(),
+					image:    img,
+					platform: cfg.Platform,
+					policy:   types.PullPolicyIfNotPresent,
+					nodes:    map[string]string{},
+				}
+				pulls[key] = p
+			}
+
+			if cfg.ImagePullPolicy == types.PullPolicyAlways {
+				p.policy = types.PullPolicyAlways
+			}
+
+			p.nodes[name] = img
+		}
+	}
+
+	res := make([]*imagePull, 0, len(pulls))
+	for _, p := range pulls {
+		res = append(res, p)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].image != res[j].image {
+			return res[i].image < res[j].image
+		}
+		return res[i].platform < res[j].platform
+	})
+
+	return res
+}
+
+// pullImages pulls the unique images of the lab nodes concurrently before the nodes are created,
+// by no more than the max workers at a time. The pulled images are marked in the configs of the nodes
+// using them, so that the nodes don't pull them again.
+// The image pull errors are collected to pullErrs with the names of the nodes using the image,
+// other errors are returned.
+func (c *CLab) pullImages(ctx context.Context, pullErrs runtime.ImagePullErrors) error {
+	pulls := c.imagePulls(ctx)
+	if len(pulls) == 0 {
+		return nil
+	}
+
+	workers := int(c.maxWorkers)
+	if workers <= 0 {
+		workers = goruntime.NumCPU()
+	}
+
+	if workers > len(pulls) {
+		workers = len(pulls)
+	}
+
+	log.Debugf("Pulling %d image(s) by %d worker(s)", len(pulls), workers)
+
+	errs := make([]error, len(pulls))
+	idx := make(chan int)
+	wg := new(sync.WaitGroup)
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range idx {
+				p := pulls[j]
+				errs[j] = p.runtime.PullImage(ctx, p.image, p.policy, p.platform)
+			}
+		}()
+	}
+
+	for j := range pulls {
+		idx <- j
+	}
+	close(idx)
+
+	wg.Wait()
+
+	var err error
+
+	for j, p := range pulls {
+		// the failed images are marked pulled too, as their errors are already collected
+		for name, img := range p.nodes {
+			markImagePulled(c.Nodes[name], img)
+		}
+
+		if errs[j] == nil {
+			continue
+		}
+
+		var pullErr *runtime.ImagePullError
+		if errors.As(errs[j], &pullErr) {
+			for name := range p.nodes {
+				pullErr.AddNode(name)
+			}
+		}
+
+		if !pullErrs.Add(errs[j]) {
+			err = errors.Join(err, errs[j])
+		}
+	}
+
+	return err
+}
+
+// markImagePulled marks the image pulled by the lab in the node config.
+func markImagePulled(n nodes.Node, image string) {
+	cfg := n.Config()
+	if cfg.PulledImages == nil {
+		cfg.PulledImages = map[string]bool{}
+	}
+
+	cfg.PulledImages[image] = true
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestPullImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cfgs := map[string]*types.NodeConfig{
+		"srl1":    {ShortName: "srl1", Image: "ghcr.io/nokia/srlinux"},
+		"srl2":    {ShortName: "srl2", Image: "ghcr.io/nokia/srlinux", ImagePullPolicy: types.PullPolicyAlways},
+		"client1": {ShortName: "client1", Image: "alpine"},
+		"client2": {ShortName: "client2", Image: "alpine:latest"},
+		"client3": {ShortName: "client3", Image: "alpine", Platform: "linux/arm64"},
+		"local":   {ShortName: "local", Image: "local/image", ImagePullPolicy: types.PullPolicyNever},
+		"archive": {ShortName: "archive", Image: "file://images/srl.tar"},
+		"sros":    {ShortName: "sros", Image: "vrnetlab/sros:missing"},
+	}
+
+	type pull struct {
+		Image    string
+		Policy   types.PullPolicyValue
+		Platform string
+	}
+
+	var (
+		m       sync.Mutex
+		pulls   []pull
+		running int
+		peak    int
+	)
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().GetName().Return("docker").AnyTimes()
+	rt.EXPECT().PullImage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, image string, policy types.PullPolicyValue, platform string) error {
+			m.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			m.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			m.Lock()
+			running--
+			pulls = append(pulls, pull{Image: image, Policy: policy, Platform: platform})
+			m.Unlock()
+
+			if image == "vrnetlab/sros:missing" {
+				return runtime.NewImagePullError("docker", image, errors.New("manifest unknown"))
+			}
+			return nil
+		}).AnyTimes()
+
+	c := &CLab{Nodes: map[string]nodes.Node{}, maxWorkers: 2}
+
+	for name, cfg := range cfgs {
+		mn := mocknodes.NewMockNode(ctrl)
+		mn.EXPECT().Config().Return(cfg).AnyTimes()
+		mn.EXPECT().GetRuntime().Return(rt).AnyTimes()
+		mn.EXPECT().GetImages(gomock.Any()).Return(map[string]string{nodes.ImageKey: cfg.Image}).AnyTimes()
+
+		c.Nodes[name] = mn
+	}
+
+	pullErrs := runtime.ImagePullErrors{}
+	if err := c.pullImages(context.Background(), pullErrs); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pulls are collected in the order of their completion
+	got := map[pull]int{}
+	for _, p := range pulls {
+		got[p]++
+	}
+
+	want := map[pull]int{
+		{Image: "alpine", Policy: types.PullPolicyIfNotPresent}:                          1,
+		{Image: "alpine", Policy: types.PullPolicyIfNotPresent, Platform: "linux/arm64"}: 1,
+		{Image: "ghcr.io/nokia/srlinux", Policy: types.PullPolicyAlways}:                 1,
+		{Image: "vrnetlab/sros:missing", Policy: types.PullPolicyIfNotPresent}:           1,
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("pulled images mismatch (-want +got):\n%s", d)
+	}
+
+	if peak > 2 {
+		t.Errorf("got %d concurrent pulls, want no more than 2", peak)
+	}
+
+	for name, cfg := range cfgs {
+		pulled := cfg.PulledImages[cfg.Image]
+		if wantPulled := name != "local" && name != "archive"; pulled != wantPulled {
+			t.Errorf("node %s image pulled mark is %v, want %v", name, pulled, wantPulled)
+		}
+	}
+
+	pullErr, ok := pullErrs["docker.io/vrnetlab/sros:missing"]
+	if !ok {
+		t.Fatalf("image pull error of vrnetlab/sros:missing is not collected: %v", pullErrs)
+	}
+
+	if d := cmp.Diff([]string{"sros"}, pullErr.Nodes); d != "" {
+		t.Errorf("image pull error nodes mismatch (-want +got):\n%s", d)
+	}
+}
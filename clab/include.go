@@ -0,0 +1,524 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/a8m/envsubst"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/utils"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// maxIncludeDepth is the maximum depth of the nested includes.
+	maxIncludeDepth = 10
+	// includesDir is the directory in the clab temp dir where the remote includes are stored.
+	includesDir = "includes"
+
+	includeKey  = "include"
+	topologyKey = "topology"
+)
+
+// topoFragment is a topology section of a topology file or of an included file
+// along with the files the nodes of the topology section are defined in.
+type topoFragment struct {
+	topology yaml.MapSlice
+	// nodeSources maps node names to the files the nodes are defined in.
+	nodeSources map[string]string
+}
+
+// includeResolver resolves the includes of the topology files.
+type includeResolver struct {
+	tmpDir  string
+	timeout time.Duration
+	// cache maps the remote includes to the local paths of the fetched files,
+	// so that every remote include is fetched once.
+	cache map[string]string
+}
+
+func newIncludeResolver(tmpDir string, timeout time.Duration) *includeResolver {
+	return &includeResolver{
+		tmpDir:  tmpDir,
+		timeout: timeout,
+		cache:   map[string]string{},
+	}
+}
+
+// resolveIncludes merges the topology sections of the files listed in the include section
+// of the topology into the topology section of the topology and removes the include section.
+// The topology is returned unchanged when it doesn't have the include section.
+func (r *includeResolver) resolveIncludes(content []byte, source string) ([]byte, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	if _, ok := mapSliceGet(doc, includeKey); !ok {
+		return content, nil
+	}
+
+	f, err := r.resolveDoc(doc, source, []string{source})
+	if err != nil {
+		return nil, err
+	}
+
+	doc = mapSliceDelete(doc, includeKey)
+	doc = mapSliceSet(doc, topologyKey, f.topology)
+
+	return yaml.Marshal(doc)
+}
+
+// resolveDoc merges the included files of the doc and the doc's own topology section
+// into a single topology fragment.
+// The included files are merged in the order they are listed and the doc's own topology section
+// is merged last, thus it takes precedence over the included files.
+func (r *includeResolver) resolveDoc(doc yaml.MapSlice, source string, chain []string) (*topoFragment, error) {
+	includes, err := includeList(doc, source)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &topoFragment{nodeSources: map[string]string{}}
+
+	for _, inc := range includes {
+		incFragment, err := r.loadFragment(inc, source, chain)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := f.merge(incFragment, false); err != nil {
+			return nil, err
+		}
+	}
+
+	own := &topoFragment{nodeSources: map[string]string{}}
+	if t, ok := mapSliceGet(doc, topologyKey); ok && t != nil {
+		ms, ok := t.(yaml.MapSlice)
+		if !ok {
+			return nil, fmt.Errorf("%s: topology section must be a map", source)
+		}
+		own.topology = ms
+	}
+
+	nodes, _ := mapSliceGet(own.topology, "nodes")
+	nodesMS, _ := nodes.(yaml.MapSlice)
+	for _, n := range nodesMS {
+		own.nodeSources[fmt.Sprint(n.Key)] = source
+	}
+
+	if err := f.merge(own, true); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// loadFragment fetches, parses and resolves the included file referenced by ref.
+// Relative refs are resolved against the location of the including file (parent).
+func (r *includeResolver) loadFragment(ref, parent string, chain []string) (*topoFragment, error) {
+	loc, err := includeLocation(ref, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(chain, loc) {
+		return nil, fmt.Errorf("include cycle detected: %s -> %s", strings.Join(chain, " -> "), loc)
+	}
+
+	if len(chain) > maxIncludeDepth {
+		return nil, fmt.Errorf("maximum include depth of %d exceeded: %s -> %s",
+			maxIncludeDepth, strings.Join(chain, " -> "), loc)
+	}
+
+	log.Debugf("including topology file %s into %s", loc, parent)
+
+	localPath, err := r.fetch(loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch included file %s: %w", loc, err)
+	}
+
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err = envsubst.Bytes(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse included file %s: %w", loc, err)
+	}
+
+	for _, item := range doc {
+		if k := fmt.Sprint(item.Key); k != includeKey && k != topologyKey {
+			return nil, fmt.Errorf("included file %s: only %q and %q sections are allowed, found %q",
+				loc, includeKey, topologyKey, k)
+		}
+	}
+
+	// copy the chain to not share the backing array between the sibling includes
+	nextChain := append(append(make([]string, 0, len(chain)+1), chain...), loc)
+
+	f, err := r.resolveDoc(doc, loc, nextChain)
+	if err != nil {
+		return nil, err
+	}
+
+	// relative paths in the included file are resolved against its own directory.
+	// The paths of the nested includes are absolute already, thus are not changed.
+	resolveFragmentPaths(f.topology, filepath.Dir(localPath))
+
+	return f, nil
+}
+
+// fetch returns the local path of the included file.
+// Remote files are downloaded (or cloned for github urls) to the clab temp directory once.
+func (r *includeResolver) fetch(loc string) (string, error) {
+	if !utils.IsHttpUri(loc) {
+		return loc, nil
+	}
+
+	if p, ok := r.cache[loc]; ok {
+		return p, nil
+	}
+
+	var p string
+	var err error
+
+	if utils.IsGitHubURL(loc) {
+		p, err = r.cloneGithub(loc)
+	} else {
+		p, err = r.download(loc)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	r.cache[loc] = p
+
+	return p, nil
+}
+
+// download downloads the included file to the includes directory in the clab temp dir.
+func (r *includeResolver) download(loc string) (string, error) {
+	u, err := url.Parse(loc)
+	if err != nil {
+		return "", err
+	}
+
+	// the hash of the url is used to avoid name clashes between the files with the same name
+	p := filepath.Join(r.tmpDir, includesDir,
+		fmt.Sprintf("%x-%s", sha256.Sum256([]byte(loc)), filepath.Base(u.Path)))
+
+	headers := map[string]string{}
+	if v := os.Getenv(topoAuthHeaderEnv); v != "" {
+		headers["Authorization"] = v
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	return p, utils.DownloadFile(ctx, loc, p, headers)
+}
+
+// cloneGithub clones the github repository of the included file to the includes directory
+// in the clab temp dir and returns the path to the included file in the cloned repository.
+func (r *includeResolver) cloneGithub(loc string) (string, error) {
+	u := utils.NewGithubURL()
+	if err := u.Parse(loc); err != nil {
+		return "", err
+	}
+
+	if u.FileName == "" {
+		return "", fmt.Errorf("included github url %s must point to a file", loc)
+	}
+
+	repoDir := filepath.Join(r.tmpDir, includesDir,
+		strings.TrimSuffix(strings.Join([]string{u.ProjectOwner, u.RepositoryName, u.GitBranch}, "-"), "-"))
+
+	// the repository might have been cloned for another included file already
+	for _, p := range r.cache {
+		if strings.HasPrefix(p, repoDir+string(filepath.Separator)) {
+			return filepath.Join(repoDir, u.FileName), nil
+		}
+	}
+
+	// remove the clone left from the previous runs to get the fresh copy of the repository
+	if err := os.RemoveAll(repoDir); err != nil {
+		return "", err
+	}
+
+	if err := utils.CloneGithubRepoToDir(u, repoDir); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(repoDir, u.FileName), nil
+}
+
+// merge merges the topology fragment inc into the fragment f.
+// Kinds and defaults are merged field by field with the fields of inc taking precedence.
+// Links of inc are appended to the links of f.
+// Nodes defined in both fragments are merged field by field when override is set,
+// otherwise such nodes are reported as an error.
+func (f *topoFragment) merge(inc *topoFragment, override bool) error {
+	for _, item := range inc.topology {
+		key := fmt.Sprint(item.Key)
+		existing, _ := mapSliceGet(f.topology, key)
+
+		switch key {
+		case "nodes":
+			nodes, _ := existing.(yaml.MapSlice)
+			incNodes, _ := item.Value.(yaml.MapSlice)
+
+			for _, n := range incNodes {
+				name := fmt.Sprint(n.Key)
+
+				src, exists := f.nodeSources[name]
+				if exists && !override {
+					return fmt.Errorf("node %q is defined in both %s and %s", name, src, inc.nodeSources[name])
+				}
+
+				existingNode, _ := mapSliceGet(nodes, name)
+				nodes = mapSliceSet(nodes, name, mergeEntries(existingNode, n.Value))
+				f.nodeSources[name] = inc.nodeSources[name]
+			}
+
+			f.topology = mapSliceSet(f.topology, key, nodes)
+
+		case "kinds":
+			kinds, _ := existing.(yaml.MapSlice)
+			incKinds, _ := item.Value.(yaml.MapSlice)
+
+			for _, k := range incKinds {
+				existingKind, _ := mapSliceGet(kinds, k.Key)
+				kinds = mapSliceSet(kinds, k.Key, mergeEntries(existingKind, k.Value))
+			}
+
+			f.topology = mapSliceSet(f.topology, key, kinds)
+
+		case "defaults":
+			f.topology = mapSliceSet(f.topology, key, mergeEntries(existing, item.Value))
+
+		case "links":
+			links, _ := existing.([]interface{})
+			incLinks, _ := item.Value.([]interface{})
+
+			f.topology = mapSliceSet(f.topology, key, append(links, incLinks...))
+
+		default:
+			f.topology = mapSliceSet(f.topology, key, item.Value)
+		}
+	}
+
+	return nil
+}
+
+// mergeEntries merges the fields of the over entry (e.g. a node definition) into the base entry.
+// The fields of the over entry take precedence.
+func mergeEntries(base, over interface{}) interface{} {
+	if over == nil {
+		return base
+	}
+
+	baseMS, ok := base.(yaml.MapSlice)
+	if !ok {
+		return over
+	}
+
+	overMS, ok := over.(yaml.MapSlice)
+	if !ok {
+		return over
+	}
+
+	merged := append(yaml.MapSlice{}, baseMS...)
+	for _, item := range overMS {
+		merged = mapSliceSet(merged, item.Key, item.Value)
+	}
+
+	return merged
+}
+
+// includeList returns the list of files included by the doc.
+func includeList(doc yaml.MapSlice, source string) ([]string, error) {
+	v, ok := mapSliceGet(doc, includeKey)
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	l, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: include section must be a list of files", source)
+	}
+
+	includes := make([]string, 0, len(l))
+	for _, i := range l {
+		s, ok := i.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%s: include section must be a list of files, got %v", source, i)
+		}
+
+		includes = append(includes, s)
+	}
+
+	return includes, nil
+}
+
+// includeLocation returns the location of the included file referenced by ref.
+// Relative references are resolved against the location of the including file.
+func includeLocation(ref, parent string) (string, error) {
+	switch {
+	case utils.IsHttpUri(ref):
+		return ref, nil
+
+	case utils.IsHttpUri(parent):
+		base, err := url.Parse(parent)
+		if err != nil {
+			return "", err
+		}
+
+		refURL, err := url.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+
+		return base.ResolveReference(refURL).String(), nil
+
+	default:
+		return utils.ResolvePath(ref, filepath.Dir(parent)), nil
+	}
+}
+
+// resolveFragmentPaths resolves the relative paths used in the defaults, kinds and nodes
+// of the topology fragment against the dir directory.
+func resolveFragmentPaths(topology yaml.MapSlice, dir string) {
+	for _, item := range topology {
+		switch item.Key {
+		case "defaults":
+			if entry, ok := item.Value.(yaml.MapSlice); ok {
+				resolveEntryPaths(entry, dir)
+			}
+
+		case "kinds", "nodes":
+			entries, _ := item.Value.(yaml.MapSlice)
+			for _, e := range entries {
+				if entry, ok := e.Value.(yaml.MapSlice); ok {
+					resolveEntryPaths(entry, dir)
+				}
+			}
+		}
+	}
+}
+
+// resolveEntryPaths resolves the relative paths of the node definition entry against the dir directory.
+func resolveEntryPaths(entry yaml.MapSlice, dir string) {
+	for i, item := range entry {
+		switch item.Key {
+		case "startup-config", "license":
+			// embedded startup-configs are multiline strings
+			if s, ok := item.Value.(string); ok && !strings.Contains(s, "\n") {
+				entry[i].Value = resolveFragmentPath(s, dir)
+			}
+
+		case "binds":
+			resolvePathList(item.Value, dir, func(s string) string {
+				// host path is a first element in a /hostpath:/remotepath(:options) string
+				elems := strings.SplitN(s, ":", 2)
+				elems[0] = resolveFragmentPath(elems[0], dir)
+
+				return strings.Join(elems, ":")
+			})
+
+		case "env-files":
+			resolvePathList(item.Value, dir, func(s string) string {
+				return resolveFragmentPath(s, dir)
+			})
+
+		case "extras":
+			extras, _ := item.Value.(yaml.MapSlice)
+			for _, e := range extras {
+				if e.Key == "srl-agents" || e.Key == "ceos-copy-to-flash" {
+					resolvePathList(e.Value, dir, func(s string) string {
+						return resolveFragmentPath(s, dir)
+					})
+				}
+			}
+		}
+	}
+}
+
+// resolvePathList applies the resolve function to the string elements of the list l in place.
+func resolvePathList(l interface{}, dir string, resolve func(string) string) {
+	list, _ := l.([]interface{})
+	for i, v := range list {
+		if s, ok := v.(string); ok {
+			list[i] = resolve(s)
+		}
+	}
+}
+
+// resolveFragmentPath joins the relative path p with the dir directory.
+// Absolute paths, home dir based paths, urls and paths starting with the clab variables are returned as is.
+func resolveFragmentPath(p, dir string) string {
+	switch {
+	case p == "",
+		filepath.IsAbs(p),
+		strings.HasPrefix(p, "~"),
+		strings.HasPrefix(p, clabDirVar),
+		strings.HasPrefix(p, nodeDirVar),
+		utils.IsHttpUri(p):
+		return p
+	}
+
+	return filepath.Join(dir, p)
+}
+
+// mapSliceGet returns the value of the key in the map slice.
+func mapSliceGet(ms yaml.MapSlice, key interface{}) (interface{}, bool) {
+	for _, item := range ms {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// mapSliceSet sets the value of the key in the map slice preserving the position of the existing key.
+func mapSliceSet(ms yaml.MapSlice, key, value interface{}) yaml.MapSlice {
+	for i, item := range ms {
+		if item.Key == key {
+			ms[i].Value = value
+			return ms
+		}
+	}
+
+	return append(ms, yaml.MapItem{Key: key, Value: value})
+}
+
+// mapSliceDelete deletes the key from the map slice.
+func mapSliceDelete(ms yaml.MapSlice, key interface{}) yaml.MapSlice {
+	res := make(yaml.MapSlice, 0, len(ms))
+	for _, item := range ms {
+		if item.Key != key {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
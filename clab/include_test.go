@@ -0,0 +1,438 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestResolveIncludes(t *testing.T) {
+	tests := map[string]struct {
+		// files to create in the test dir, the topology file is named topo.clab.yml
+		files map[string]string
+		// want is the expected topology, {{dir}} is replaced with the test dir
+		want    string
+		wantErr string
+	}{
+		"no include": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+topology:
+  nodes:
+    n1:
+      kind: linux
+`,
+			},
+			want: `
+name: test
+topology:
+  nodes:
+    n1:
+      kind: linux
+`,
+		},
+		"fragments merged": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include:
+  - spines.yml
+  - leaves.yml
+topology:
+  nodes:
+    client:
+      kind: linux
+  links:
+    - endpoints: ["leaf1:e1-1", "client:eth1"]
+`,
+				"spines.yml": `
+topology:
+  kinds:
+    nokia_srlinux:
+      image: ghcr.io/nokia/srlinux
+  nodes:
+    spine1:
+      kind: nokia_srlinux
+`,
+				"leaves.yml": `
+topology:
+  nodes:
+    leaf1:
+      kind: nokia_srlinux
+  links:
+    - endpoints: ["spine1:e1-1", "leaf1:e1-49"]
+`,
+			},
+			want: `
+name: test
+topology:
+  kinds:
+    nokia_srlinux:
+      image: ghcr.io/nokia/srlinux
+  nodes:
+    spine1:
+      kind: nokia_srlinux
+    leaf1:
+      kind: nokia_srlinux
+    client:
+      kind: linux
+  links:
+    - endpoints: ["spine1:e1-1", "leaf1:e1-49"]
+    - endpoints: ["leaf1:e1-1", "client:eth1"]
+`,
+		},
+		"parent wins": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include:
+  - fragment.yml
+topology:
+  defaults:
+    image: alpine:3
+  nodes:
+    n1:
+      image: alpine:edge
+`,
+				"fragment.yml": `
+topology:
+  defaults:
+    kind: linux
+    image: alpine:latest
+  nodes:
+    n1:
+      kind: linux
+      image: alpine:3.18
+`,
+			},
+			want: `
+name: test
+topology:
+  defaults:
+    kind: linux
+    image: alpine:3
+  nodes:
+    n1:
+      kind: linux
+      image: alpine:edge
+`,
+		},
+		"later fragment wins for kinds": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include: [a.yml, b.yml]
+`,
+				"a.yml": `
+topology:
+  kinds:
+    linux:
+      image: alpine:3
+      cmd: sleep infinity
+`,
+				"b.yml": `
+topology:
+  kinds:
+    linux:
+      image: alpine:edge
+`,
+			},
+			want: `
+name: test
+topology:
+  kinds:
+    linux:
+      image: alpine:edge
+      cmd: sleep infinity
+`,
+		},
+		"duplicate node": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include: [a.yml, b.yml]
+`,
+				"a.yml": `
+topology:
+  nodes:
+    n1:
+      kind: linux
+`,
+				"b.yml": `
+topology:
+  nodes:
+    n1:
+      kind: linux
+`,
+			},
+			wantErr: `node "n1" is defined in both {{dir}}/a.yml and {{dir}}/b.yml`,
+		},
+		"relative paths resolved against fragment dir": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include: [fragments/srl.yml]
+topology:
+  nodes:
+    n2:
+      startup-config: n2.cfg
+`,
+				"fragments/srl.yml": `
+topology:
+  kinds:
+    nokia_srlinux:
+      license: license.key
+  nodes:
+    n1:
+      startup-config: configs/n1.cfg
+      binds:
+        - data:/data:ro
+        - /abs:/abs
+        - __clabNodeDir__/x:/x
+      env-files: [envs/n1.env]
+      extras:
+        srl-agents: [agent.yml]
+    n3:
+      startup-config: |
+        inline config
+`,
+			},
+			want: `
+name: test
+topology:
+  kinds:
+    nokia_srlinux:
+      license: {{dir}}/fragments/license.key
+  nodes:
+    n1:
+      startup-config: {{dir}}/fragments/configs/n1.cfg
+      binds:
+        - {{dir}}/fragments/data:/data:ro
+        - /abs:/abs
+        - __clabNodeDir__/x:/x
+      env-files: [{{dir}}/fragments/envs/n1.env]
+      extras:
+        srl-agents: [{{dir}}/fragments/agent.yml]
+    n3:
+      startup-config: |
+        inline config
+    n2:
+      startup-config: n2.cfg
+`,
+		},
+		"nested include relative to fragment": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include: [fragments/a.yml]
+`,
+				"fragments/a.yml": `
+include: [nested/b.yml]
+topology:
+  nodes:
+    a:
+      kind: linux
+`,
+				"fragments/nested/b.yml": `
+topology:
+  nodes:
+    b:
+      startup-config: b.cfg
+`,
+			},
+			want: `
+name: test
+topology:
+  nodes:
+    b:
+      startup-config: {{dir}}/fragments/nested/b.cfg
+    a:
+      kind: linux
+`,
+		},
+		"include cycle": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include: [a.yml]
+`,
+				"a.yml": `
+include: [b.yml]
+`,
+				"b.yml": `
+include: [a.yml]
+`,
+			},
+			wantErr: "include cycle detected: {{dir}}/topo.clab.yml -> {{dir}}/a.yml -> {{dir}}/b.yml -> {{dir}}/a.yml",
+		},
+		"max depth exceeded": {
+			files: func() map[string]string {
+				files := map[string]string{
+					"topo.clab.yml": "name: test\ninclude: [f0.yml]\n",
+				}
+				for i := 0; i <= maxIncludeDepth; i++ {
+					files[fmt.Sprintf("f%d.yml", i)] = fmt.Sprintf("include: [f%d.yml]\n", i+1)
+				}
+				files[fmt.Sprintf("f%d.yml", maxIncludeDepth+1)] = "topology: {}\n"
+
+				return files
+			}(),
+			wantErr: fmt.Sprintf("maximum include depth of %d exceeded", maxIncludeDepth),
+		},
+		"unsupported section in fragment": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include: [a.yml]
+`,
+				"a.yml": `
+name: other
+topology: {}
+`,
+			},
+			wantErr: `only "include" and "topology" sections are allowed, found "name"`,
+		},
+		"include is not a list": {
+			files: map[string]string{
+				"topo.clab.yml": `
+name: test
+include: a.yml
+`,
+			},
+			wantErr: "include section must be a list of files",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for p, content := range tt.files {
+				fp := filepath.Join(dir, p)
+				if err := os.MkdirAll(filepath.Dir(fp), 0755); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(fp, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			topoFile := filepath.Join(dir, "topo.clab.yml")
+
+			r := newIncludeResolver(filepath.Join(dir, "tmp"), time.Second)
+
+			got, err := r.resolveIncludes([]byte(tt.files["topo.clab.yml"]), topoFile)
+			if tt.wantErr != "" {
+				wantErr := strings.ReplaceAll(tt.wantErr, "{{dir}}", dir)
+				if err == nil || !strings.Contains(err.Error(), wantErr) {
+					t.Fatalf("wanted error containing %q, got %v", wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertYAMLEqual(t, strings.ReplaceAll(tt.want, "{{dir}}", dir), string(got))
+		})
+	}
+}
+
+func TestResolveRemoteIncludes(t *testing.T) {
+	requests := map[string]int{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+
+		switch r.URL.Path {
+		case "/labs/a.yml":
+			fmt.Fprint(w, "include: [common.yml]\ntopology:\n  nodes:\n    a:\n      startup-config: a.cfg\n")
+		case "/labs/b.yml":
+			fmt.Fprint(w, "include: [common.yml]\ntopology:\n  nodes:\n    b:\n      kind: linux\n")
+		case "/labs/common.yml":
+			fmt.Fprint(w, "topology:\n  kinds:\n    linux:\n      image: alpine:3\n")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	tmpDir := filepath.Join(dir, "tmp")
+
+	topo := fmt.Sprintf("name: test\ninclude:\n  - %[1]s/labs/a.yml\n  - %[1]s/labs/b.yml\n", srv.URL)
+
+	r := newIncludeResolver(tmpDir, time.Second)
+
+	got, err := r.resolveIncludes([]byte(topo), filepath.Join(dir, "topo.clab.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// relative paths of the remote fragments are resolved against the download location
+	aPath := filepath.Dir(r.cache[srv.URL+"/labs/a.yml"])
+
+	want := fmt.Sprintf(`
+name: test
+topology:
+  kinds:
+    linux:
+      image: alpine:3
+  nodes:
+    a:
+      startup-config: %s/a.cfg
+    b:
+      kind: linux
+`, aPath)
+
+	assertYAMLEqual(t, want, string(got))
+
+	// the fragment included by both remote fragments is fetched once
+	if requests["/labs/common.yml"] != 1 {
+		t.Fatalf("wanted common.yml to be fetched once, fetched %d times", requests["/labs/common.yml"])
+	}
+
+	// missing remote fragment
+	_, err = r.resolveIncludes([]byte("include: ["+srv.URL+"/labs/missing.yml]\n"),
+		filepath.Join(dir, "topo.clab.yml"))
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found") {
+		t.Fatalf("wanted not found error, got %v", err)
+	}
+}
+
+// assertYAMLEqual compares the yaml documents ignoring the formatting differences.
+func assertYAMLEqual(t *testing.T, want, got string) {
+	t.Helper()
+
+	var wantDoc, gotDoc yaml.MapSlice
+
+	if err := yaml.Unmarshal([]byte(want), &wantDoc); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := yaml.Unmarshal([]byte(got), &gotDoc); err != nil {
+		t.Fatal(err)
+	}
+
+	wantB, _ := yaml.Marshal(wantDoc)
+	gotB, _ := yaml.Marshal(gotDoc)
+
+	if string(wantB) != string(gotB) {
+		t.Fatalf("topology mismatch\nwant:\n%s\ngot:\n%s", wantB, gotB)
+	}
+}
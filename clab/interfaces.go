@@ -0,0 +1,163 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// NodeInterface is an interface of a node listed in the interfaces file of the node.
+type NodeInterface struct {
+	// Name is the name of the kernel interface in the network namespace of the node.
+	Name string `json:"name"`
+	// Alias is the alias of the kernel interface set in the endpoint definition.
+	Alias string `json:"alias,omitempty"`
+	// NOSName is the name of the interface used by the network OS of the node.
+	NOSName string `json:"nos_name"`
+	MAC     string `json:"mac,omitempty"`
+	// Peer is the other endpoint of the link, e.g. srl2:e1-1.
+	Peer string `json:"peer,omitempty"`
+}
+
+// nosInterfaceName returns the name the network OS of the node uses for the interface of the endpoint.
+// The alias of the endpoint takes precedence over the kind-specific mapping of the interface name.
+func nosInterfaceName(n nodes.Node, ep links.Endpoint) string {
+	if a := ep.GetIfaceAlias(); a != "" {
+		return a
+	}
+
+	return n.NOSInterfaceName(ep.GetIfaceName())
+}
+
+// NodeInterfaces returns the interfaces of the node created for the links, sorted by the interface name.
+func NodeInterfaces(n nodes.Node) []*NodeInterface {
+	ifaces := make([]*NodeInterface, 0, len(n.GetEndpoints()))
+
+	for _, ep := range n.GetEndpoints() {
+		iface := &NodeInterface{
+			Name:    ep.GetIfaceName(),
+			Alias:   ep.GetIfaceAlias(),
+			NOSName: nosInterfaceName(n, ep),
+		}
+
+		if mac := ep.GetMac(); len(mac) > 0 {
+			iface.MAC = mac.String()
+		}
+
+		var l links.Link = ep.GetLink()
+		// the peer of a bond link endpoint is the other end of its member link
+		if b, ok := l.(*links.LinkBond); ok {
+			if m := b.MemberOf(ep); m != nil {
+				l = m
+			}
+		}
+
+		if l != nil {
+			for _, peer := range l.GetEndpoints() {
+				if peer != ep {
+					iface.Peer = peer.String()
+				}
+			}
+		}
+
+		ifaces = append(ifaces, iface)
+	}
+
+	sort.Slice(ifaces, func(i, j int) bool {
+		return ifaces[i].Name < ifaces[j].Name
+	})
+
+	return ifaces
+}
+
+// WriteInterfacesFiles writes the interfaces file with the kernel and the network OS names
+// of the link interfaces to the lab directory of every node running in a container.
+func (c *CLab) WriteInterfacesFiles() error {
+	var errs []error
+
+	for name, n := range c.Nodes {
+		// the bridge and the host nodes have no lab directory
+		if n.GetLinkEndpointType() != links.LinkEndpointTypeVeth || !utils.DirExists(n.Config().LabDir) {
+			continue
+		}
+
+		b, err := json.MarshalIndent(NodeInterfaces(n), "", "  ")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		err = utils.WriteFileAtomic(c.TopoPaths.NodeInterfacesFileAbsPath(name), append(b, '\n'), 0644)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NodeInterfaceCounters returns the statistics counters of the interfaces in the network namespace
+// of the deployed node.
+func (c *CLab) NodeInterfaceCounters(ctx context.Context, name string) ([]*links.InterfaceCounters, error) {
+	n, err := c.deployedNodeNetNS(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return links.GetInterfaceCounters(n)
+}
+
+// NodeInterfaceMTUs returns the MTUs of the interfaces in the network namespace of the deployed node.
+// When ifName is set, only the MTU of this interface is returned.
+func (c *CLab) NodeInterfaceMTUs(ctx context.Context, name, ifName string) ([]*links.InterfaceMTU, error) {
+	n, err := c.deployedNodeNetNS(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return links.GetInterfaceMTUs(n, ifName)
+}
+
+// SetNodeInterfaceMTU sets the MTU of the interface in the network namespace of the deployed node
+// and returns the previous MTU of the interface.
+func (c *CLab) SetNodeInterfaceMTU(ctx context.Context, name, ifName string, mtu int) (int, error) {
+	n, err := c.deployedNodeNetNS(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return links.SetInterfaceMTU(n, ifName, mtu)
+}
+
+// deployedNodeNetNS returns the node with the path to the network namespace of its container set,
+// so that the functions can be executed in the namespace of the deployed node.
+func (c *CLab) deployedNodeNetNS(ctx context.Context, name string) (nodes.Node, error) {
+	n, ok := c.Nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("node %q is not found in the topology", name)
+	}
+
+	// the bridge and the host nodes share the host network namespace
+	if n.GetLinkEndpointType() != links.LinkEndpointTypeVeth || n.Config().IsRootNamespaceBased {
+		return nil, fmt.Errorf("node %q doesn't have a network namespace of its own", name)
+	}
+
+	nsPath, err := n.GetRuntime().GetNSPath(ctx, n.Config().LongName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the network namespace of node %q: %w", name, err)
+	}
+
+	n.Config().NSPath = nsPath
+
+	return n, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNodeInterfaces(t *testing.T) {
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+	c, err := NewContainerLab(WithTopoPath("test_data/topo23-aliases.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := NodeInterfaces(c.Nodes["l1"])
+
+	// the generated MAC address is random
+	got[1].MAC = ""
+
+	want := []*NodeInterface{
+		{
+			Name:    "eth1",
+			Alias:   "e1-1",
+			NOSName: "e1-1",
+			MAC:     "00:1c:73:00:00:01",
+			Peer:    "l2:eth1",
+		},
+		{
+			Name:    "eth2",
+			NOSName: "eth2",
+			Peer:    "l2:eth2",
+		},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("interfaces mismatch (-want +got):\n%s", d)
+	}
+}
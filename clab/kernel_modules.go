@@ -0,0 +1,165 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// the kernel modules of the host, replaced in tests.
+var (
+	listKernelModules = utils.KernelModules
+	loadKernelModule  = utils.LoadKernelModule
+)
+
+// baseKernelModules are the kernel modules containerlab uses regardless of the lab links.
+var baseKernelModules = []string{"ip_tables", "ip6_tables"}
+
+// kernelModuleRequirement is a kernel module needed by the lab.
+type kernelModuleRequirement struct {
+	module string
+	// users are what the module is needed by, e.g. "macvlan links"
+	users []string
+}
+
+func (r *kernelModuleRequirement) usedBy() string {
+	return strings.Join(r.users, ", ")
+}
+
+// kernelModuleError is a kernel module which is not loaded and failed to load.
+type kernelModuleError struct {
+	req *kernelModuleRequirement
+	err error
+}
+
+func (e *kernelModuleError) Error() string {
+	return fmt.Sprintf("kernel module %q needed by %s is not loaded and failed to load: %v. "+
+		"Load it manually with 'modprobe %s'", e.req.module, e.req.usedBy(), e.err, e.req.module)
+}
+
+// linkKernelModules returns the kernel modules needed by the links of the lab ordered by the module name.
+func (c *CLab) linkKernelModules() []*kernelModuleRequirement {
+	reqs := map[string]*kernelModuleRequirement{}
+
+	for _, l := range c.Links {
+		lt := l.GetType()
+
+		for _, m := range lt.KernelModules() {
+			r, ok := reqs[m]
+			if !ok {
+				r = &kernelModuleRequirement{module: m}
+				reqs[m] = r
+			}
+
+			user := string(lt) + " links"
+			if _, found := utils.StringInSlice(r.users, user); !found {
+				r.users = append(r.users, user)
+			}
+		}
+	}
+
+	sorted := make([]*kernelModuleRequirement, 0, len(reqs))
+	for _, r := range reqs {
+		sort.Strings(r.users)
+		sorted = append(sorted, r)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].module < sorted[j].module })
+
+	return sorted
+}
+
+// ensureKernelModules loads the required kernel modules which are neither loaded nor built into the kernel.
+// The modules failing to load are returned.
+func ensureKernelModules(reqs []*kernelModuleRequirement) ([]*kernelModuleError, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	present, err := listKernelModules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the kernel modules: %w", err)
+	}
+
+	var failed []*kernelModuleError
+
+	for _, r := range reqs {
+		if _, ok := present[utils.NormalizeKernelModuleName(r.module)]; ok {
+			log.Debugf("kernel module %q is already loaded", r.module)
+			continue
+		}
+
+		log.Debugf("kernel module %q needed by %s is not loaded. Trying to load", r.module, r.usedBy())
+
+		if err := loadKernelModule(r.module); err != nil {
+			failed = append(failed, &kernelModuleError{req: r, err: err})
+			continue
+		}
+
+		log.Debugf("kernel module %q loaded successfully", r.module)
+	}
+
+	return failed, nil
+}
+
+// LoadKernelModules loads the kernel modules used by containerlab and needed by the lab links.
+// The failures to load the modules used by containerlab are only logged, while the failures
+// to load the modules needed by the links are returned, as the links can't be created without them.
+func (c *CLab) LoadKernelModules() error {
+	base := make([]*kernelModuleRequirement, 0, len(baseKernelModules))
+	for _, m := range baseKernelModules {
+		base = append(base, &kernelModuleRequirement{module: m, users: []string{"containerlab"}})
+	}
+
+	failed, err := ensureKernelModules(base)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range failed {
+		log.Warn(f)
+	}
+
+	failed, err = ensureKernelModules(c.linkKernelModules())
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0, len(failed))
+	for _, f := range failed {
+		errs = append(errs, f)
+	}
+
+	return errors.Join(errs...)
+}
+
+// preflightKernelModules loads the kernel modules needed by the lab links
+// and reports the modules which failed to load.
+func (c *CLab) preflightKernelModules(_ context.Context) ([]*PreflightFinding, error) {
+	failed, err := ensureKernelModules(c.linkKernelModules())
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*PreflightFinding, 0, len(failed))
+
+	for _, f := range failed {
+		findings = append(findings, &PreflightFinding{
+			Check: "kernel-modules",
+			Problem: fmt.Sprintf("kernel module %q needed by %s is not loaded and failed to load: %v",
+				f.req.module, f.req.usedBy(), f.err),
+			Suggestion: "modprobe " + f.req.module,
+		})
+	}
+
+	return findings, nil
+}
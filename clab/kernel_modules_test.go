@@ -0,0 +1,146 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/links"
+)
+
+// mockKernelModules replaces the host kernel modules with the present modules
+// and the loader failing for the modules in the failing set. The loaded modules are recorded.
+func mockKernelModules(t *testing.T, present []string, failing map[string]error) *[]string {
+	t.Helper()
+
+	var loaded []string
+
+	origList, origLoad := listKernelModules, loadKernelModule
+
+	listKernelModules = func() (map[string]struct{}, error) {
+		m := map[string]struct{}{}
+		for _, p := range present {
+			m[p] = struct{}{}
+		}
+
+		return m, nil
+	}
+
+	loadKernelModule = func(name string) error {
+		if err, ok := failing[name]; ok {
+			return err
+		}
+
+		loaded = append(loaded, name)
+
+		return nil
+	}
+
+	t.Cleanup(func() {
+		listKernelModules, loadKernelModule = origList, origLoad
+	})
+
+	return &loaded
+}
+
+func TestLinkKernelModules(t *testing.T) {
+	c := &CLab{Links: map[int]links.Link{
+		0: &links.LinkVEth{},
+		1: &links.LinkMacVlan{},
+		2: &links.LinkVxlan{},
+		3: &links.VxlanStitched{},
+		4: &links.LinkVEth{},
+	}}
+
+	var got []string
+	for _, r := range c.linkKernelModules() {
+		got = append(got, r.module+": "+r.usedBy())
+	}
+
+	want := []string{
+		"macvlan: macvlan links",
+		"veth: veth links, vxlan-stitch links",
+		"vxlan: vxlan links, vxlan-stitch links",
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("modules mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLoadKernelModules(t *testing.T) {
+	loaded := mockKernelModules(t, []string{"ip_tables", "veth"}, map[string]error{
+		"ip6_tables": errors.New("module not found"),
+		"macvlan":    errors.New("module not found"),
+	})
+
+	c := &CLab{Links: map[int]links.Link{
+		0: &links.LinkVEth{},
+		1: &links.LinkMacVlan{},
+		2: &links.LinkVxlan{},
+	}}
+
+	err := c.LoadKernelModules()
+
+	// the failure to load the module used by containerlab itself is not fatal
+	wantErr := `kernel module "macvlan" needed by macvlan links is not loaded and failed to load: ` +
+		`module not found. Load it manually with 'modprobe macvlan'`
+
+	if err == nil || err.Error() != wantErr {
+		t.Errorf("got error %v, want %q", err, wantErr)
+	}
+
+	if d := cmp.Diff([]string{"vxlan"}, *loaded); d != "" {
+		t.Errorf("loaded modules mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestPreflightKernelModules(t *testing.T) {
+	loaded := mockKernelModules(t, []string{"veth"}, map[string]error{
+		"vxlan": errors.New("modprobe binary is not found"),
+	})
+
+	c := &CLab{Links: map[int]links.Link{
+		0: &links.LinkVEth{},
+		1: &links.LinkMacVlan{},
+		2: &links.LinkVxlan{},
+	}}
+
+	findings, err := c.preflightKernelModules(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*PreflightFinding{{
+		Check:      "kernel-modules",
+		Problem:    `kernel module "vxlan" needed by vxlan links is not loaded and failed to load: modprobe binary is not found`,
+		Suggestion: "modprobe vxlan",
+	}}
+
+	if d := cmp.Diff(want, findings, cmp.AllowUnexported(PreflightFinding{})); d != "" {
+		t.Errorf("findings mismatch (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]string{"macvlan"}, *loaded); d != "" {
+		t.Errorf("loaded modules mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestPreflightKernelModulesNoLinks(t *testing.T) {
+	mockKernelModules(t, nil, nil)
+
+	listKernelModules = func() (map[string]struct{}, error) {
+		t.Fatal("the kernel modules are listed for a lab without links")
+		return nil, nil
+	}
+
+	findings, err := (&CLab{}).preflightKernelModules(context.Background())
+	if err != nil || len(findings) != 0 {
+		t.Errorf("got findings %v and error %v, want none", findings, err)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// labEnvPrefix is the prefix of the lab environment variable names.
+const labEnvPrefix = "CLAB"
+
+// LabEnvVar is a lab environment variable.
+type LabEnvVar struct {
+	Name  string
+	Value string
+}
+
+// LabEnv returns the environment variables of the deployed lab built from the lab metadata file.
+// The variables are the management addresses and the container names of the nodes
+// and the management subnets and gateways, e.g. CLAB_<LAB>_<NODE>_V4 or CLAB_<LAB>_MGMT_V4_SUBNET.
+func (c *CLab) LabEnv() ([]LabEnvVar, error) {
+	md, err := c.readLabMetadata()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("lab %q is not deployed, the lab metadata file %s is not found",
+				c.Config.Name, c.TopoPaths.LabMetadataFileAbsPath())
+		}
+
+		return nil, err
+	}
+
+	return labEnvVars(md)
+}
+
+// WriteLabEnvFile writes the lab environment variables to the .env file in the lab directory.
+// The variables colliding after the name sanitization are left out of the file and reported in the error.
+func (c *CLab) WriteLabEnvFile() error {
+	vars, err := c.LabEnv()
+	if len(vars) == 0 && err != nil {
+		return err
+	}
+
+	b := &strings.Builder{}
+	for _, v := range vars {
+		fmt.Fprintf(b, "%s=%s\n", v.Name, v.Value)
+	}
+
+	if werr := utils.WriteFileAtomic(c.TopoPaths.LabEnvFileAbsPath(), []byte(b.String()), 0644); werr != nil {
+		return werr
+	}
+
+	return err
+}
+
+// labEnvVars returns the lab environment variables ordered by name.
+// The variables of the different nodes or the management network which names collide
+// after the sanitization are left out and reported in the error, the other variables are returned.
+func labEnvVars(md *LabMetadata) ([]LabEnvVar, error) {
+	lab := sanitizeEnvName(md.Name)
+
+	// sources maps the variable name to the sources of the variable, i.e. the nodes or the mgmt network
+	sources := map[string][]string{}
+	values := map[string]string{}
+
+	add := func(source, name, value string) {
+		if value == "" {
+			return
+		}
+
+		name = labEnvPrefix + "_" + lab + "_" + name
+
+		sources[name] = append(sources[name], source)
+		values[name] = value
+	}
+
+	if md.Mgmt != nil {
+		add("management network", "MGMT_V4_SUBNET", md.Mgmt.IPv4Subnet)
+		add("management network", "MGMT_V4_GW", md.Mgmt.IPv4Gw)
+		add("management network", "MGMT_V6_SUBNET", md.Mgmt.IPv6Subnet)
+		add("management network", "MGMT_V6_GW", md.Mgmt.IPv6Gw)
+	}
+
+	for _, n := range md.Nodes {
+		source := fmt.Sprintf("node %q", n.Name)
+		node := sanitizeEnvName(n.Name)
+
+		add(source, node+"_V4", n.MgmtIPv4)
+		add(source, node+"_V6", n.MgmtIPv6)
+		add(source, node+"_CONTAINER", n.Container)
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vars := make([]LabEnvVar, 0, len(names))
+
+	var errs []error
+
+	for _, name := range names {
+		if len(sources[name]) > 1 {
+			errs = append(errs, fmt.Errorf("environment variable %s is set by %s, rename them to have distinct variable names",
+				name, strings.Join(sources[name], " and ")))
+
+			continue
+		}
+
+		vars = append(vars, LabEnvVar{Name: name, Value: values[name]})
+	}
+
+	return vars, errors.Join(errs...)
+}
+
+// sanitizeEnvName returns the name uppercased with the characters not allowed in the environment
+// variable names, such as dashes and dots, replaced with underscores.
+// The leading digits are kept, as the sanitized names are never used without the CLAB prefix.
+func sanitizeEnvName(name string) string {
+	b := strings.Builder{}
+
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+			continue
+		}
+
+		b.WriteByte('_')
+	}
+
+	return b.String()
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestSanitizeEnvName(t *testing.T) {
+	tests := map[string]string{
+		"srl1":         "SRL1",
+		"leaf-1":       "LEAF_1",
+		"host.example": "HOST_EXAMPLE",
+		"my_node":      "MY_NODE",
+		"1st-node":     "1ST_NODE",
+		"a--b":         "A__B",
+		"nöde":         "N_DE",
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := sanitizeEnvName(name); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLabEnvVars(t *testing.T) {
+	md := &LabMetadata{
+		Name: "my-lab.v2",
+		Mgmt: &types.MgmtNet{
+			IPv4Subnet: "172.20.20.0/24",
+			IPv4Gw:     "172.20.20.1",
+		},
+		Nodes: []*NodeMetadata{
+			{Name: "1st-node", Container: "clab-my-lab.v2-1st-node", MgmtIPv4: "172.20.20.2", MgmtIPv6: "3fff::2"},
+			{Name: "srl", Container: "clab-my-lab.v2-srl", MgmtIPv4: "172.20.20.3"},
+		},
+	}
+
+	got, err := labEnvVars(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []LabEnvVar{
+		{Name: "CLAB_MY_LAB_V2_1ST_NODE_CONTAINER", Value: "clab-my-lab.v2-1st-node"},
+		{Name: "CLAB_MY_LAB_V2_1ST_NODE_V4", Value: "172.20.20.2"},
+		{Name: "CLAB_MY_LAB_V2_1ST_NODE_V6", Value: "3fff::2"},
+		{Name: "CLAB_MY_LAB_V2_MGMT_V4_GW", Value: "172.20.20.1"},
+		{Name: "CLAB_MY_LAB_V2_MGMT_V4_SUBNET", Value: "172.20.20.0/24"},
+		{Name: "CLAB_MY_LAB_V2_SRL_CONTAINER", Value: "clab-my-lab.v2-srl"},
+		{Name: "CLAB_MY_LAB_V2_SRL_V4", Value: "172.20.20.3"},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("variables mismatch (-want +got):\n%s", d)
+	}
+
+	valid := regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+	for _, v := range got {
+		if !valid.MatchString(v.Name) {
+			t.Errorf("invalid environment variable name %q", v.Name)
+		}
+	}
+}
+
+func TestLabEnvVarsCollisions(t *testing.T) {
+	md := &LabMetadata{
+		Name: "lab",
+		Nodes: []*NodeMetadata{
+			{Name: "leaf-1", Container: "clab-lab-leaf-1", MgmtIPv4: "172.20.20.2"},
+			{Name: "leaf.1", Container: "clab-lab-leaf.1", MgmtIPv4: "172.20.20.3"},
+			{Name: "spine", Container: "clab-lab-spine", MgmtIPv4: "172.20.20.4"},
+		},
+	}
+
+	got, err := labEnvVars(md)
+	if err == nil {
+		t.Fatal("expected the collision error")
+	}
+
+	wantErr := `environment variable CLAB_LAB_LEAF_1_CONTAINER is set by node "leaf-1" and node "leaf.1", ` +
+		"rename them to have distinct variable names\n" +
+		`environment variable CLAB_LAB_LEAF_1_V4 is set by node "leaf-1" and node "leaf.1", ` +
+		"rename them to have distinct variable names"
+
+	if err.Error() != wantErr {
+		t.Errorf("got error %q, want %q", err, wantErr)
+	}
+
+	// the colliding variables are left out, the others are kept
+	want := []LabEnvVar{
+		{Name: "CLAB_LAB_SPINE_CONTAINER", Value: "clab-lab-spine"},
+		{Name: "CLAB_LAB_SPINE_V4", Value: "172.20.20.4"},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("variables mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// assignLicensePools assigns the licenses from the kind license pools to the enabled nodes of these kinds
+// which don't have the license set explicitly. The licenses set explicitly are excluded from the pools.
+// The assignments recorded in the lab metadata by the previous deployment are kept when possible.
+// The returned assignments are keyed by the node name.
+func (c *CLab) assignLicensePools(nodeNames []string) (map[string]string, error) {
+	topo := c.Config.Topology
+	topoDir := c.TopoPaths.TopologyFileDir()
+
+	// poolNodes are the names of the nodes to assign the pool licenses to, keyed by the kind name
+	poolNodes := map[string][]string{}
+	// explicit are the licenses set explicitly for the nodes
+	explicit := map[string]struct{}{}
+
+	for _, name := range nodeNames {
+		if l := topo.Nodes[name].GetLicense(); l != "" {
+			explicit[utils.ResolvePath(l, topoDir)] = struct{}{}
+			continue
+		}
+
+		kind := topo.GetNodeKind(name)
+		if len(topo.GetKind(kind).GetLicensePool()) == 0 || !topo.GetNodeEnabled(name) {
+			continue
+		}
+
+		poolNodes[kind] = append(poolNodes[kind], name)
+	}
+
+	if len(poolNodes) == 0 {
+		return nil, nil
+	}
+
+	previous := c.previousLicenseAssignments()
+	assignments := map[string]string{}
+
+	kinds := make([]string, 0, len(poolNodes))
+	for kind := range poolNodes {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		pool, err := expandLicensePool(topo.GetKind(kind).GetLicensePool(), topoDir)
+		if err != nil {
+			return nil, fmt.Errorf("kind %q license pool: %w", kind, err)
+		}
+
+		available := make([]string, 0, len(pool))
+		for _, l := range pool {
+			if _, ok := explicit[l]; !ok {
+				available = append(available, l)
+			}
+		}
+
+		kindAssignments, err := assignLicenses(poolNodes[kind], available, previous)
+		if err != nil {
+			return nil, fmt.Errorf("kind %q license pool: %w", kind, err)
+		}
+
+		for name, l := range kindAssignments {
+			log.Debugf("assigned license %s from the %q kind license pool to node %s", l, kind, name)
+			assignments[name] = l
+		}
+	}
+
+	return assignments, nil
+}
+
+// assignLicenses assigns a license from the pool to each of the nodes.
+// The previous assignments of the nodes are kept if their licenses are still in the pool,
+// the other nodes get the first unused licenses of the pool in the order of their names.
+func assignLicenses(nodeNames, pool []string, previous map[string]string) (map[string]string, error) {
+	if len(nodeNames) > len(pool) {
+		return nil, fmt.Errorf("%d nodes require a license, but the pool has %d available licenses",
+			len(nodeNames), len(pool))
+	}
+
+	inPool := make(map[string]struct{}, len(pool))
+	for _, l := range pool {
+		inPool[l] = struct{}{}
+	}
+
+	assignments := make(map[string]string, len(nodeNames))
+	used := map[string]struct{}{}
+
+	for _, name := range nodeNames {
+		l, ok := previous[name]
+		if !ok {
+			continue
+		}
+
+		if _, ok := inPool[l]; !ok {
+			continue
+		}
+
+		if _, ok := used[l]; ok {
+			continue
+		}
+
+		assignments[name] = l
+		used[l] = struct{}{}
+	}
+
+	sorted := append([]string(nil), nodeNames...)
+	sort.Strings(sorted)
+
+	next := 0
+
+	for _, name := range sorted {
+		if _, ok := assignments[name]; ok {
+			continue
+		}
+
+		for ; next < len(pool); next++ {
+			if _, ok := used[pool[next]]; !ok {
+				break
+			}
+		}
+
+		assignments[name] = pool[next]
+		used[pool[next]] = struct{}{}
+	}
+
+	return assignments, nil
+}
+
+// expandLicensePool resolves the paths of the license pool relative to the baseDir
+// and replaces the directories with the regular files they contain, hidden files are skipped.
+// The returned license paths are unique and keep the order of the pool.
+func expandLicensePool(pool types.LicensePool, baseDir string) ([]string, error) {
+	var licenses []string
+
+	seen := map[string]struct{}{}
+	add := func(l string) {
+		if _, ok := seen[l]; ok {
+			return
+		}
+
+		seen[l] = struct{}{}
+		licenses = append(licenses, l)
+	}
+
+	for _, p := range pool {
+		p = utils.ResolvePath(p, baseDir)
+
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if !fi.IsDir() {
+			add(p)
+			continue
+		}
+
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+
+		// os.ReadDir returns the entries sorted by the file name
+		for _, e := range entries {
+			if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+
+			add(filepath.Join(p, e.Name()))
+		}
+	}
+
+	return licenses, nil
+}
+
+// previousLicenseAssignments returns the license pool assignments recorded in the lab metadata file
+// by the previous deployment of the lab.
+func (c *CLab) previousLicenseAssignments() map[string]string {
+	md, err := c.readLabMetadata()
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Warnf("failed to read the lab metadata file, the licenses will be reassigned: %v", err)
+		}
+
+		return nil
+	}
+
+	return md.Licenses
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignLicenses(t *testing.T) {
+	tests := map[string]struct {
+		nodes    []string
+		pool     []string
+		previous map[string]string
+		want     map[string]string
+		wantErr  string
+	}{
+		"fresh assignment in the node names order": {
+			nodes: []string{"sr2", "sr1", "sr3"},
+			pool:  []string{"a.lic", "b.lic", "c.lic", "d.lic"},
+			want:  map[string]string{"sr1": "a.lic", "sr2": "b.lic", "sr3": "c.lic"},
+		},
+		"previous assignments are kept": {
+			nodes:    []string{"sr1", "sr2", "sr3"},
+			pool:     []string{"a.lic", "b.lic", "c.lic", "d.lic"},
+			previous: map[string]string{"sr2": "d.lic", "sr3": "a.lic", "removed": "b.lic"},
+			want:     map[string]string{"sr1": "b.lic", "sr2": "d.lic", "sr3": "a.lic"},
+		},
+		"previous license removed from the pool": {
+			nodes:    []string{"sr1", "sr2"},
+			pool:     []string{"b.lic", "c.lic"},
+			previous: map[string]string{"sr1": "a.lic", "sr2": "c.lic"},
+			want:     map[string]string{"sr1": "b.lic", "sr2": "c.lic"},
+		},
+		"pool exhausted": {
+			nodes:   []string{"sr1", "sr2", "sr3"},
+			pool:    []string{"a.lic", "b.lic"},
+			wantErr: "3 nodes require a license, but the pool has 2 available licenses",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := assignLicenses(tt.nodes, tt.pool, tt.previous)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("assignments mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestExpandLicensePool(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, f := range []string{"licenses/b.lic", "licenses/a.lic", "licenses/.hidden", "extra.lic"} {
+		writeTestFile(t, filepath.Join(dir, f))
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "licenses", "subdir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := expandLicensePool(types.LicensePool{"extra.lic", "licenses", "licenses/a.lic"}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "extra.lic"),
+		filepath.Join(dir, "licenses", "a.lic"),
+		filepath.Join(dir, "licenses", "b.lic"),
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("license pool mismatch (-want +got):\n%s", d)
+	}
+
+	_, err = expandLicensePool(types.LicensePool{"missing"}, dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLicensePoolInit(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, f := range []string{"licenses/a.lic", "licenses/b.lic", "licenses/c.lic"} {
+		writeTestFile(t, filepath.Join(dir, f))
+	}
+
+	topoPath := filepath.Join(dir, "pool.clab.yml")
+	writeTestTopology(t, topoPath, `name: pool
+topology:
+  kinds:
+    vr-sros:
+      image: vrnetlab/vr-sros:23.7.R1
+      license-pool: licenses
+  nodes:
+    sr1:
+      kind: vr-sros
+    sr2:
+      kind: vr-sros
+    sr3:
+      kind: vr-sros
+      license: licenses/a.lic
+`)
+
+	lic := func(name string) string { return filepath.Join(dir, "licenses", name) }
+
+	c, err := NewContainerLab(WithTopoPath(topoPath, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the explicit license of sr3 is excluded from the pool
+	assert.Equal(t, lic("b.lic"), c.Nodes["sr1"].Config().License)
+	assert.Equal(t, lic("c.lic"), c.Nodes["sr2"].Config().License)
+	assert.Equal(t, lic("a.lic"), c.Nodes["sr3"].Config().License)
+
+	// the assignments recorded by the previous deployment are kept
+	if err := os.MkdirAll(c.TopoPaths.TopologyLabDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(LabMetadata{Name: "pool", Licenses: map[string]string{
+		"sr1": lic("c.lic"),
+		"sr2": lic("b.lic"),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(c.TopoPaths.LabMetadataFileAbsPath(), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = NewContainerLab(WithTopoPath(topoPath, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, lic("c.lic"), c.Nodes["sr1"].Config().License)
+	assert.Equal(t, lic("b.lic"), c.Nodes["sr2"].Config().License)
+
+	// a single license is left in the pool for two nodes
+	writeTestTopology(t, topoPath, `name: pool
+topology:
+  kinds:
+    vr-sros:
+      image: vrnetlab/vr-sros:23.7.R1
+      license-pool: [licenses/a.lic, licenses/b.lic]
+  nodes:
+    sr1:
+      kind: vr-sros
+    sr2:
+      kind: vr-sros
+    sr3:
+      kind: vr-sros
+      license: licenses/a.lic
+`)
+
+	_, err = NewContainerLab(WithTopoPath(topoPath, ""))
+	assert.ErrorContains(t, err, `kind "vr-sros" license pool: 2 nodes require a license, but the pool has 1 available licenses`)
+}
+
+// writeTestFile creates the file by the path p along with its parent directories.
+func writeTestFile(t *testing.T, p string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(p, []byte(filepath.Base(p)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeTestTopology writes the topology file by the path p.
+func writeTestTopology(t *testing.T, p, topo string) {
+	t.Helper()
+
+	if err := os.WriteFile(p, []byte(topo), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
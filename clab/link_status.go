@@ -0,0 +1,353 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/nodes/state"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+// LinkStatus is the deployment status of a lab link.
+type LinkStatus string
+
+const (
+	// LinkStatusPending is the status of a link which nodes are not all deployed yet.
+	LinkStatusPending LinkStatus = "pending"
+	// LinkStatusDeployed is the status of a link created in the namespaces of all its nodes.
+	LinkStatusDeployed LinkStatus = "deployed"
+	// LinkStatusFailed is the status of a link which creation failed.
+	LinkStatusFailed LinkStatus = "failed"
+	// LinkStatusSkipped is the status of a link not created because some of its nodes were not deployed.
+	LinkStatusSkipped LinkStatus = "skipped"
+)
+
+// LinkState is the deployment status of a lab link.
+type LinkState struct {
+	// Index is the index of the link in the links section of the topology.
+	Index     int                  `json:"index"`
+	Type      string               `json:"type"`
+	Endpoints []*LinkStateEndpoint `json:"endpoints"`
+	MTU       int                  `json:"mtu,omitempty"`
+	Status    LinkStatus           `json:"status"`
+	// Error is the deployment error of a failed link or the reason a link was skipped.
+	Error string `json:"error,omitempty"`
+	// Drift describes how the running link differs from the deployed one, e.g. a removed interface.
+	// It is set by the liveness verification of a running lab only.
+	Drift string `json:"drift,omitempty"`
+}
+
+// LinkStateEndpoint is a link endpoint referenced by the node and the interface names used in the topology.
+type LinkStateEndpoint struct {
+	Node      string `json:"node"`
+	Interface string `json:"interface"`
+}
+
+func (e *LinkStateEndpoint) String() string {
+	if e.Interface == "" {
+		return e.Node
+	}
+
+	return e.Node + ":" + e.Interface
+}
+
+// linkStates tracks the deployment status of the lab links.
+// The status is updated concurrently by the workers deploying the nodes.
+type linkStates struct {
+	mu     sync.Mutex
+	states map[links.Link]*LinkState
+	// labNodes are the nodes deploying their links,
+	// the special nodes, like host or mgmt-net, are not deployed by the lab.
+	labNodes map[string]nodes.Node
+	// deployed is the set of the nodes that deployed their links
+	deployed map[string]struct{}
+	// failed is the set of the nodes that failed to deploy their links
+	failed map[string]struct{}
+}
+
+// newLinkStates returns the tracker of the links l keyed by their index, all links are pending.
+func newLinkStates(l map[int]links.Link, labNodes map[string]nodes.Node) *linkStates {
+	s := &linkStates{
+		states:   make(map[links.Link]*LinkState, len(l)),
+		labNodes: labNodes,
+		deployed: map[string]struct{}{},
+		failed:   map[string]struct{}{},
+	}
+
+	for idx, link := range l {
+		ls := &LinkState{
+			Index:  idx,
+			Type:   string(link.GetType()),
+			MTU:    link.GetMTU(),
+			Status: LinkStatusPending,
+		}
+
+		for _, ep := range link.GetEndpoints() {
+			ls.Endpoints = append(ls.Endpoints, &LinkStateEndpoint{
+				Node:      ep.GetNode().GetShortName(),
+				Interface: ep.GetIfaceName(),
+			})
+		}
+
+		s.states[link] = ls
+	}
+
+	return s
+}
+
+// linksDeployed records that the node n deployed its links and marks its links deployed
+// once all the nodes they connect deployed their links, since a link is created by the last of its nodes.
+func (s *linkStates) linksDeployed(n links.Node) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deployed[n.GetShortName()] = struct{}{}
+
+	for _, ep := range n.GetEndpoints() {
+		ls, ok := s.states[ep.GetLink()]
+		if !ok || ls.Status != LinkStatusPending {
+			continue
+		}
+
+		if s.allNodesDeployed(ep.GetLink()) {
+			ls.Status = LinkStatusDeployed
+		}
+	}
+}
+
+// allNodesDeployed returns true if all the lab nodes connected by the link l deployed their links.
+func (s *linkStates) allNodesDeployed(l links.Link) bool {
+	for _, ep := range l.GetEndpoints() {
+		name := ep.GetNode().GetShortName()
+		if _, ok := s.labNodes[name]; !ok {
+			continue
+		}
+
+		if _, ok := s.deployed[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// linkFailed records that the node n failed to deploy its links
+// and marks the link referenced by the deploy error err failed.
+// The links of the node not referenced by the error stay pending.
+func (s *linkStates) linkFailed(n links.Node, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failed[n.GetShortName()] = struct{}{}
+
+	var de *links.DeployError
+	if !errors.As(err, &de) {
+		return
+	}
+
+	if ls, ok := s.states[de.Link]; ok {
+		ls.Status = LinkStatusFailed
+		ls.Error = de.Err.Error()
+	}
+}
+
+// skipPending marks the links which are still pending skipped
+// with the names of their nodes that were not deployed as the reason.
+func (s *linkStates) skipPending() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for l, ls := range s.states {
+		if ls.Status != LinkStatusPending {
+			continue
+		}
+
+		var notDeployed, failed []string
+
+		for _, ep := range l.GetEndpoints() {
+			name := ep.GetNode().GetShortName()
+
+			switch _, f := s.failed[name]; {
+			case ep.GetNode().GetState() != state.Deployed:
+				notDeployed = append(notDeployed, name)
+			case f:
+				failed = append(failed, name)
+			}
+		}
+
+		ls.Status = LinkStatusSkipped
+
+		switch {
+		case len(notDeployed) > 0:
+			ls.Error = fmt.Sprintf("node(s) %s not deployed", strings.Join(notDeployed, ", "))
+		case len(failed) > 0:
+			ls.Error = fmt.Sprintf("links deployment of node(s) %s failed", strings.Join(failed, ", "))
+		default:
+			ls.Error = "links deployment not finished"
+		}
+	}
+}
+
+// list returns the copies of the link states ordered by the link index.
+func (s *linkStates) list() []*LinkState {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]*LinkState, 0, len(s.states))
+	for _, ls := range s.states {
+		cp := *ls
+		res = append(res, &cp)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Index < res[j].Index
+	})
+
+	return res
+}
+
+// SkipPendingLinks marks the links not created by the finished deployment skipped.
+func (c *CLab) SkipPendingLinks() {
+	c.linkStates.skipPending()
+}
+
+// LinkStates returns the deployment status of the lab links ordered by the link index.
+func (c *CLab) LinkStates() []*LinkState {
+	return c.linkStates.list()
+}
+
+// DeployedLinkStates returns the link states persisted in the lab metadata by the lab deployment.
+func (c *CLab) DeployedLinkStates() ([]*LinkState, error) {
+	md, err := c.readLabMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the lab metadata, is the lab %q deployed? %w", c.Config.Name, err)
+	}
+
+	if md.Links == nil {
+		return nil, fmt.Errorf("the lab %q was deployed without the link status tracking, redeploy the lab", c.Config.Name)
+	}
+
+	return md.Links, nil
+}
+
+// hostNetnsKinds are the kinds which nodes have their interfaces in the host netns.
+var hostNetnsKinds = map[string]struct{}{
+	"bridge":     {},
+	"ovs-bridge": {},
+	"host":       {},
+}
+
+// VerifyLinksLiveness sets the drift of the deployed links of the running lab which interfaces are missing or down.
+func (c *CLab) VerifyLinksLiveness(ctx context.Context, states []*LinkState) {
+	nsPaths := map[string]string{}
+
+	for name, n := range c.Nodes {
+		if _, ok := hostNetnsKinds[n.Config().Kind]; ok {
+			continue
+		}
+
+		// the nodes which containers are not running have no netns
+		nsp, err := n.GetRuntime().GetNSPath(ctx, n.Config().LongName)
+		if err != nil {
+			log.Debugf("failed to get the netns path of node %q: %v", name, err)
+		}
+
+		nsPaths[name] = nsp
+	}
+
+	verifyLinksLiveness(states, nsPaths)
+}
+
+// verifyLinksLiveness sets the drift of the deployed links which interfaces are missing or down.
+// The interfaces are looked up in the netns of their nodes referenced by the nsPaths map keyed by the node name,
+// an empty path means the node has no netns. The interfaces of the nodes not in the map,
+// like host or mgmt-net, are looked up in the current netns.
+func verifyLinksLiveness(states []*LinkState, nsPaths map[string]string) {
+	for _, ls := range states {
+		if ls.Status != LinkStatusDeployed {
+			continue
+		}
+
+		var drift []string
+
+		for _, ep := range ls.Endpoints {
+			// the remote endpoints of the vxlan links have no interface on this host
+			if ep.Interface == "" {
+				continue
+			}
+
+			nsPath, ok := nsPaths[ep.Node]
+			if ok && nsPath == "" {
+				drift = append(drift, fmt.Sprintf("%s: node is not running", ep))
+				continue
+			}
+
+			if err := interfaceLive(nsPath, ep.Interface); err != nil {
+				drift = append(drift, fmt.Sprintf("%s: %v", ep, err))
+			}
+		}
+
+		ls.Drift = strings.Join(drift, "; ")
+	}
+}
+
+// interfaceLive checks that the interface iface exists and is up in the netns at nsPath,
+// the current netns is used when nsPath is empty.
+// The interfaces with names longer than IFNAMSIZ are looked up by their alias.
+func interfaceLive(nsPath, iface string) error {
+	check := func(_ ns.NetNS) error {
+		l, err := utils.LinkByNameOrAlias(iface)
+		if err != nil {
+			return errors.New("interface not found")
+		}
+
+		// the veth interfaces report the unknown operational state when up
+		if l.Attrs().Flags&net.FlagUp == 0 || l.Attrs().OperState == netlink.OperDown {
+			return errors.New("interface is down")
+		}
+
+		return nil
+	}
+
+	if nsPath == "" {
+		return check(nil)
+	}
+
+	netns, err := ns.GetNS(nsPath)
+	if err != nil {
+		return errors.New("netns not found")
+	}
+	defer netns.Close()
+
+	return netns.Do(check)
+}
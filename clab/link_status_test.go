@@ -0,0 +1,174 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/internal/netnstest"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/nodes/state"
+)
+
+func TestLinkStates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// n4 failed to deploy, the other nodes are deployed
+	nodeStates := map[string]state.NodeState{
+		"n1": state.Deployed, "n2": state.Deployed, "n3": state.Deployed, "n4": state.Unknown,
+	}
+
+	mocks := map[string]*mocknodes.MockNode{}
+	for name, s := range nodeStates {
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().GetShortName().Return(name).AnyTimes()
+		n.EXPECT().GetState().Return(s).AnyTimes()
+		mocks[name] = n
+	}
+
+	endpoints := map[string][]links.Endpoint{}
+	labLinks := map[int]links.Link{}
+	link := func(idx int, a, aIf string, b links.Node, bIf string) links.Link {
+		l := links.NewLinkVEth()
+		l.MTU = 9500
+		for i, n := range []links.Node{mocks[a], b} {
+			ep := links.NewEndpointVeth(links.NewEndpointGeneric(n, []string{aIf, bIf}[i], l))
+			l.Endpoints = append(l.Endpoints, ep)
+			endpoints[n.GetShortName()] = append(endpoints[n.GetShortName()], ep)
+		}
+		labLinks[idx] = l
+		return l
+	}
+
+	link(0, "n1", "e1-1", mocks["n2"], "e1-1")
+	failed := link(1, "n2", "e1-2", mocks["n3"], "e1-1")
+	link(2, "n3", "e1-2", mocks["n4"], "e1-1")
+	link(3, "n1", "e1-2", links.GetHostLinkNode(), "n1-e1-2")
+	// the link of n3 not created after its other link failed
+	link(4, "n3", "e1-3", mocks["n1"], "e1-3")
+
+	for name, n := range mocks {
+		n.EXPECT().GetEndpoints().Return(endpoints[name]).AnyTimes()
+	}
+
+	labNodes := map[string]nodes.Node{}
+	for name, n := range mocks {
+		labNodes[name] = n
+	}
+
+	s := newLinkStates(labLinks, labNodes)
+
+	for _, l := range s.list() {
+		if l.Status != LinkStatusPending {
+			t.Fatalf("link %d is %s before the deployment, want pending", l.Index, l.Status)
+		}
+	}
+
+	// n1 deployed its links first, the links are created by its peers
+	s.linksDeployed(mocks["n1"])
+	for _, l := range s.list() {
+		if l.Status != LinkStatusPending && l.Index != 3 {
+			t.Fatalf("link %d is %s before its peer deployed the links, want pending", l.Index, l.Status)
+		}
+	}
+
+	s.linksDeployed(mocks["n2"])
+	s.linkFailed(mocks["n3"], fmt.Errorf("failed deploy links: %w",
+		&links.DeployError{Link: failed, Err: errors.New("file exists")}))
+	s.skipPending()
+
+	type result struct {
+		Index     int
+		Endpoints string
+		Status    LinkStatus
+		Error     string
+	}
+
+	var got []result
+	for _, l := range s.list() {
+		got = append(got, result{
+			Index:     l.Index,
+			Endpoints: fmt.Sprintf("%s %s", l.Endpoints[0], l.Endpoints[1]),
+			Status:    l.Status,
+			Error:     l.Error,
+		})
+	}
+
+	want := []result{
+		{Index: 0, Endpoints: "n1:e1-1 n2:e1-1", Status: LinkStatusDeployed},
+		{Index: 1, Endpoints: "n2:e1-2 n3:e1-1", Status: LinkStatusFailed, Error: "file exists"},
+		{Index: 2, Endpoints: "n3:e1-2 n4:e1-1", Status: LinkStatusSkipped, Error: "node(s) n4 not deployed"},
+		{Index: 3, Endpoints: "n1:e1-2 host:n1-e1-2", Status: LinkStatusDeployed},
+		{
+			Index: 4, Endpoints: "n3:e1-3 n1:e1-3", Status: LinkStatusSkipped,
+			Error: "links deployment of node(s) n3 failed",
+		},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("link states mismatch (-want +got):\n%s", d)
+	}
+
+	// the link states are copied, so the tracker is not changed by the callers
+	s.list()[0].Status = LinkStatusFailed
+	if st := s.list()[0].Status; st != LinkStatusDeployed {
+		t.Fatalf("link state changed by the caller to %s", st)
+	}
+}
+
+func TestVerifyLinksLiveness(t *testing.T) {
+	n1, n2 := netnstest.New(t), netnstest.New(t)
+
+	netnstest.AddVeth(t, n1, "e1-1", n2, "e1-1")
+	netnstest.SetLinksUp(t, n1, "e1-1")
+	netnstest.SetLinksUp(t, n2, "e1-1")
+	// e1-2 is left down
+	netnstest.AddVeth(t, n1, "e1-2", n2, "e1-2")
+
+	nsPaths := map[string]string{
+		"n1": n1.Path(),
+		"n2": n2.Path(),
+		// n3 container is not running
+		"n3": "",
+	}
+
+	eps := func(a, aIf, b, bIf string) []*LinkStateEndpoint {
+		return []*LinkStateEndpoint{{Node: a, Interface: aIf}, {Node: b, Interface: bIf}}
+	}
+
+	states := []*LinkState{
+		{Index: 0, Status: LinkStatusDeployed, Endpoints: eps("n1", "e1-1", "n2", "e1-1")},
+		{Index: 1, Status: LinkStatusDeployed, Endpoints: eps("n1", "e1-2", "n2", "e1-2")},
+		{Index: 2, Status: LinkStatusDeployed, Endpoints: eps("n1", "e1-3", "n2", "e1-1")},
+		{Index: 3, Status: LinkStatusDeployed, Endpoints: eps("n1", "e1-1", "n3", "e1-1")},
+		// the links which were not deployed are not verified
+		{Index: 4, Status: LinkStatusFailed, Endpoints: eps("n1", "e1-4", "n2", "e1-4")},
+	}
+
+	verifyLinksLiveness(states, nsPaths)
+
+	want := []string{
+		"",
+		"n1:e1-2: interface is down; n2:e1-2: interface is down",
+		"n1:e1-3: interface not found",
+		"n3:e1-1: node is not running",
+		"",
+	}
+
+	var got []string
+	for _, ls := range states {
+		got = append(got, ls.Drift)
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("link drifts mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes/state"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// endpointPresent returns true if the interface of the endpoint exists in the netns of its node.
+// It is a variable to allow tests to stub the netns lookup.
+var endpointPresent = func(ep links.Endpoint) (bool, error) {
+	var present bool
+
+	err := ep.GetNode().ExecFunction(func(_ ns.NetNS) error {
+		_, err := utils.LinkByNameOrAlias(ep.GetIfaceName())
+		present = err == nil
+
+		return nil
+	})
+
+	return present, err
+}
+
+// linkString returns the endpoints of the link joined the way the deployed links are logged.
+func linkString(l links.Link) string {
+	eps := make([]string, 0, len(l.GetEndpoints()))
+	for _, ep := range l.GetEndpoints() {
+		eps = append(eps, ep.String())
+	}
+
+	return strings.Join(eps, " <--> ")
+}
+
+// sortedLinkIndexes returns the indexes of the lab links in the order they are defined in the topology.
+func (c *CLab) sortedLinkIndexes() []int {
+	idx := make([]int, 0, len(c.Links))
+	for i := range c.Links {
+		idx = append(idx, i)
+	}
+
+	sort.Ints(idx)
+
+	return idx
+}
+
+// prepareLinkNodes sets the netns paths of the running lab nodes and marks the nodes deployed,
+// so that the links of the running lab can be removed and deployed without redeploying the nodes.
+func (c *CLab) prepareLinkNodes(ctx context.Context) error {
+	for name, n := range c.Nodes {
+		// the nodes with the interfaces in the host netns have no netns of their own
+		if _, ok := hostNetnsKinds[n.Config().Kind]; !ok {
+			nsp, err := n.GetRuntime().GetNSPath(ctx, n.Config().LongName)
+			if err != nil {
+				return fmt.Errorf("node %q is not running: %w", name, err)
+			}
+
+			n.Config().NSPath = nsp
+		}
+
+		n.SetState(state.Deployed)
+	}
+
+	return nil
+}
+
+// RemoveLinks removes the links of the running lab, the nodes and the management network are left intact.
+// All the links are attempted, the returned error joins the failures.
+func (c *CLab) RemoveLinks(ctx context.Context) error {
+	if err := c.prepareLinkNodes(ctx); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, i := range c.sortedLinkIndexes() {
+		l := c.Links[i]
+
+		log.Infof("Removing link: %s", linkString(l))
+
+		if err := l.Remove(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove link %s: %w", linkString(l), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeployLinks deploys the links of the running lab removed with RemoveLinks or otherwise.
+// The links which interfaces are all present are left as is, the partially present links
+// are removed before they are deployed again. All the links are attempted, the returned error joins the failures.
+func (c *CLab) DeployLinks(ctx context.Context) error {
+	if err := c.prepareLinkNodes(ctx); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, i := range c.sortedLinkIndexes() {
+		l := c.Links[i]
+
+		if err := c.redeployLink(ctx, l); err != nil {
+			errs = append(errs, fmt.Errorf("failed to deploy link %s: %w", linkString(l), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// redeployLink deploys the link unless its interfaces are all present.
+func (c *CLab) redeployLink(ctx context.Context, l links.Link) error {
+	var present, total int
+
+	for _, ep := range l.GetEndpoints() {
+		// the remote endpoints of the vxlan links have no interface on this host
+		if ep.GetIfaceName() == "" {
+			continue
+		}
+
+		total++
+
+		ok, err := endpointPresent(ep)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			present++
+		}
+	}
+
+	switch {
+	case total > 0 && present == total:
+		log.Infof("Link %s is already deployed", linkString(l))
+		return nil
+	case present > 0:
+		log.Debugf("Removing the partially present link %s before it is deployed", linkString(l))
+
+		if err := l.Remove(ctx); err != nil {
+			return err
+		}
+	}
+
+	return l.Deploy(ctx)
+}
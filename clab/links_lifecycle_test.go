@@ -0,0 +1,126 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// fakeEndpoint is the endpoint of the fake link, only its interface name is used.
+type fakeEndpoint struct {
+	links.Endpoint
+	iface string
+}
+
+func (e *fakeEndpoint) GetIfaceName() string { return e.iface }
+
+func (e *fakeEndpoint) String() string { return "node:" + e.iface }
+
+// fakeLink records the calls of its deploy and remove methods.
+type fakeLink struct {
+	links.Link
+	eps       []links.Endpoint
+	calls     *[]string
+	removeErr error
+}
+
+func newFakeLink(calls *[]string, ifaces ...string) *fakeLink {
+	l := &fakeLink{calls: calls}
+	for _, i := range ifaces {
+		l.eps = append(l.eps, &fakeEndpoint{iface: i})
+	}
+
+	return l
+}
+
+func (l *fakeLink) GetEndpoints() []links.Endpoint { return l.eps }
+
+func (l *fakeLink) Deploy(context.Context) error {
+	*l.calls = append(*l.calls, "deploy "+linkString(l))
+	return nil
+}
+
+func (l *fakeLink) Remove(context.Context) error {
+	*l.calls = append(*l.calls, "remove "+linkString(l))
+	return l.removeErr
+}
+
+func TestRemoveLinks(t *testing.T) {
+	var calls []string
+
+	failed := newFakeLink(&calls, "e1-2", "e2-2")
+	failed.removeErr = errors.New("device busy")
+
+	c := &CLab{
+		Nodes: map[string]nodes.Node{},
+		Links: map[int]links.Link{
+			2: newFakeLink(&calls, "e1-3", "e2-3"),
+			0: newFakeLink(&calls, "e1-1", "e2-1"),
+			1: failed,
+		},
+	}
+
+	err := c.RemoveLinks(context.Background())
+	if err == nil {
+		t.Fatal("the failed link removal is not reported")
+	}
+
+	// the links are all removed in the topology order despite the failure
+	want := []string{
+		"remove node:e1-1 <--> node:e2-1",
+		"remove node:e1-2 <--> node:e2-2",
+		"remove node:e1-3 <--> node:e2-3",
+	}
+	if d := cmp.Diff(want, calls); d != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestDeployLinks(t *testing.T) {
+	origEndpointPresent := endpointPresent
+	t.Cleanup(func() { endpointPresent = origEndpointPresent })
+
+	present := map[string]bool{"e1-1": true, "e2-1": true, "e1-3": true}
+
+	endpointPresent = func(ep links.Endpoint) (bool, error) {
+		return present[ep.GetIfaceName()], nil
+	}
+
+	var calls []string
+
+	c := &CLab{
+		Nodes: map[string]nodes.Node{},
+		Links: map[int]links.Link{
+			// the deployed link
+			0: newFakeLink(&calls, "e1-1", "e2-1"),
+			// the removed link
+			1: newFakeLink(&calls, "e1-2", "e2-2"),
+			// the partially removed link
+			2: newFakeLink(&calls, "e1-3", "e2-3"),
+			// the vxlan link with the remote endpoint
+			3: newFakeLink(&calls, "vx-1", ""),
+		},
+	}
+
+	if err := c.DeployLinks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"deploy node:e1-2 <--> node:e2-2",
+		"remove node:e1-3 <--> node:e2-3",
+		"deploy node:e1-3 <--> node:e2-3",
+		"deploy node:vx-1 <--> node:",
+	}
+	if d := cmp.Diff(want, calls); d != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", d)
+	}
+}
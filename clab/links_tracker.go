@@ -0,0 +1,91 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"sync"
+
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// linksTracker tracks the nodes that deployed their links
+// to find the nodes which links are all created.
+// A link is created once all the nodes it connects deployed their links.
+type linksTracker struct {
+	mu    sync.Mutex
+	nodes map[string]nodes.Node
+	// deployed is the set of the nodes that deployed their links
+	deployed map[string]struct{}
+	// completed is the set of the nodes which links are all created
+	completed map[string]struct{}
+}
+
+func newLinksTracker(n map[string]nodes.Node) *linksTracker {
+	return &linksTracker{
+		nodes:     n,
+		deployed:  map[string]struct{}{},
+		completed: map[string]struct{}{},
+	}
+}
+
+// linksDeployed records that the node n deployed its links and returns the nodes
+// which links got all created as a result. These can only be n itself and the nodes it is linked to.
+// Every node is returned once.
+func (t *linksTracker) linksDeployed(n nodes.Node) []nodes.Node {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.deployed[n.GetShortName()] = struct{}{}
+
+	candidates := []nodes.Node{n}
+	for _, ep := range n.GetEndpoints() {
+		for _, lep := range ep.GetLink().GetEndpoints() {
+			if peer, ok := t.nodes[lep.GetNode().GetShortName()]; ok {
+				candidates = append(candidates, peer)
+			}
+		}
+	}
+
+	var res []nodes.Node
+
+	for _, cn := range candidates {
+		name := cn.GetShortName()
+		if _, ok := t.completed[name]; ok {
+			continue
+		}
+
+		if !t.allLinksDeployed(cn) {
+			continue
+		}
+
+		t.completed[name] = struct{}{}
+		res = append(res, cn)
+	}
+
+	return res
+}
+
+// allLinksDeployed returns true if all the nodes connected by the links of n deployed their links.
+func (t *linksTracker) allLinksDeployed(n nodes.Node) bool {
+	if _, ok := t.deployed[n.GetShortName()]; !ok {
+		return false
+	}
+
+	for _, ep := range n.GetEndpoints() {
+		for _, lep := range ep.GetLink().GetEndpoints() {
+			name := lep.GetNode().GetShortName()
+			// special nodes, like host or mgmt-net, are not deployed by the lab
+			if _, ok := t.nodes[name]; !ok {
+				continue
+			}
+
+			if _, ok := t.deployed[name]; !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+func TestLinksTracker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mocks := map[string]*mocknodes.MockNode{}
+	for _, name := range []string{"n1", "n2", "n3", "host"} {
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().GetShortName().Return(name).AnyTimes()
+		mocks[name] = n
+	}
+
+	endpoints := map[string][]links.Endpoint{}
+	link := func(a, b string) {
+		l := links.NewLinkVEth()
+		for _, name := range []string{a, b} {
+			ep := links.NewEndpointVeth(links.NewEndpointGeneric(mocks[name], "eth1", l))
+			l.Endpoints = append(l.Endpoints, ep)
+			endpoints[name] = append(endpoints[name], ep)
+		}
+	}
+
+	// n1 -- n2 -- n3 -- host
+	link("n1", "n2")
+	link("n2", "n3")
+	link("n3", "host")
+
+	labNodes := map[string]nodes.Node{}
+	for _, name := range []string{"n1", "n2", "n3"} {
+		mocks[name].EXPECT().GetEndpoints().Return(endpoints[name]).AnyTimes()
+		labNodes[name] = mocks[name]
+	}
+
+	tr := newLinksTracker(labNodes)
+
+	names := func(ns []nodes.Node) []string {
+		var res []string
+		for _, n := range ns {
+			res = append(res, n.GetShortName())
+		}
+		return res
+	}
+
+	// the peers of n1 and n3 didn't deploy their links yet
+	if got := tr.linksDeployed(labNodes["n1"]); len(got) != 0 {
+		t.Fatalf("unexpected completed nodes %v", names(got))
+	}
+
+	if got := tr.linksDeployed(labNodes["n3"]); len(got) != 0 {
+		t.Fatalf("unexpected completed nodes %v", names(got))
+	}
+
+	// n2 completes the links of all nodes, the host node is not deployed by the lab
+	if d := cmp.Diff([]string{"n2", "n1", "n3"}, names(tr.linksDeployed(labNodes["n2"]))); d != "" {
+		t.Fatalf("completed nodes mismatch (-want +got):\n%s", d)
+	}
+
+	// nodes are completed only once
+	if got := tr.linksDeployed(labNodes["n2"]); len(got) != 0 {
+		t.Fatalf("unexpected completed nodes %v", names(got))
+	}
+}
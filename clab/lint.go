@@ -0,0 +1,249 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+const (
+	// LintSeverityError is the severity of the issues which make the lab fail to deploy.
+	LintSeverityError = "error"
+	// LintSeverityWarning is the severity of the issues which don't prevent the lab from being deployed.
+	LintSeverityWarning = "warning"
+
+	// mgmtIfaceName is the name of the management interface of the nodes attached to the management network.
+	mgmtIfaceName = "eth0"
+)
+
+// LintIssue is an issue found by the topology linter.
+type LintIssue struct {
+	// Rule is the name of the check or the lint rule which found the issue
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// Node is the name of the node the issue relates to, empty for the lab-wide issues
+	Node    string `json:"node,omitempty"`
+	Message string `json:"message"`
+}
+
+// Lint runs the checks of the topology definition and the lint rules and returns the issues found.
+// Unlike CheckTopologyDefinition, all the checks run to the end, so that all the issues are reported at once.
+// Lint resolves the links of the topology, it must not be called after ResolveLinks.
+func (c *CLab) Lint(ctx context.Context) []*LintIssue {
+	var issues []*LintIssue
+
+	add := func(rule, severity, node string, err error) {
+		// the errors joined by a check are reported as the separate issues
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				issues = append(issues, &LintIssue{Rule: rule, Severity: severity, Node: node, Message: e.Error()})
+			}
+
+			return
+		}
+
+		issues = append(issues, &LintIssue{Rule: rule, Severity: severity, Node: node, Message: err.Error()})
+	}
+
+	for _, err := range c.resolveLinksForLint() {
+		add("links", LintSeverityError, "", err)
+	}
+
+	checks := append(c.definitionChecks(), topologyCheck{
+		name:  "mgmt-addresses",
+		check: func() error { return c.verifyMgmtAddresses(ctx) },
+	})
+
+	for _, tc := range checks {
+		if err := tc.check(); err != nil {
+			add(tc.name, LintSeverityError, "", err)
+		}
+	}
+
+	for _, name := range c.sortedNodeNames() {
+		n := c.Nodes[name]
+
+		if err := n.CheckInterfaceName(); err != nil {
+			add("interface-names", LintSeverityError, name, err)
+		}
+
+		if err := lintNodeImage(ctx, n); err != nil {
+			add("no-image", LintSeverityError, name, err)
+		}
+
+		if err := lintReservedInterfaces(n); err != nil {
+			add("reserved-interfaces", LintSeverityError, name, err)
+		}
+	}
+
+	for _, rule := range []struct {
+		name string
+		fn   func() []error
+	}{
+		{"unused-kinds", c.lintUnusedKinds},
+		{"unused-groups", c.lintUnusedGroups},
+		{"unused-defaults", c.lintUnusedDefaults},
+	} {
+		for _, err := range rule.fn() {
+			add(rule.name, LintSeverityWarning, "", err)
+		}
+	}
+
+	return issues
+}
+
+// resolveLinksForLint resolves the links of the topology like ResolveLinks does,
+// but collects the errors of all the links instead of stopping at the first one.
+func (c *CLab) resolveLinksForLint() []error {
+	resolveParams := &links.ResolveParams{
+		Nodes:          c.GetLinkNodes(),
+		MgmtBridgeName: c.Config.Mgmt.Bridge,
+		NodesFilter:    c.nodeFilter,
+		DisabledNodes:  c.disabledNodeNames(),
+	}
+
+	var errs []error
+
+	for i, ld := range c.Config.Topology.Links {
+		l, err := ld.Link.Resolve(resolveParams)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("link %d: %w", i+1, err))
+			continue
+		}
+
+		if l == nil {
+			continue
+		}
+
+		c.Endpoints = append(c.Endpoints, l.GetEndpoints()...)
+		c.Links[i] = l
+	}
+
+	return errs
+}
+
+// sortedNodeNames returns the sorted names of the lab nodes.
+func (c *CLab) sortedNodeNames() []string {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// lintNodeImage checks that the node of a kind running a container has an image set,
+// the kinds without a container, e.g. bridge, don't report the image.
+func lintNodeImage(ctx context.Context, n nodes.Node) error {
+	if img, ok := n.GetImages(ctx)[nodes.ImageKey]; ok && img == "" {
+		return fmt.Errorf("node %q of kind %q has no image set on the node, kind or defaults level",
+			n.GetShortName(), n.Config().Kind)
+	}
+
+	return nil
+}
+
+// lintReservedInterfaces checks that the links of the node attached to the management network
+// don't use its management interface.
+func lintReservedInterfaces(n nodes.Node) error {
+	if n.GetLinkEndpointType() != links.LinkEndpointTypeVeth || !attachedToMgmtNet(n.Config()) {
+		return nil
+	}
+
+	for _, ep := range n.GetEndpoints() {
+		if ep.GetIfaceName() == mgmtIfaceName {
+			return fmt.Errorf("node %q uses the management interface %s in a link", n.GetShortName(), mgmtIfaceName)
+		}
+	}
+
+	return nil
+}
+
+// lintUnusedKinds reports the kind definitions that are not used by any node.
+func (c *CLab) lintUnusedKinds() []error {
+	used := map[string]bool{}
+	for name := range c.Config.Topology.Nodes {
+		used[c.Config.Topology.GetNodeKind(name)] = true
+	}
+
+	var errs []error
+
+	for _, kind := range sortedKeys(c.Config.Topology.GetKinds()) {
+		if !used[kind] {
+			errs = append(errs, fmt.Errorf("kind %q is defined, but no node uses it", kind))
+		}
+	}
+
+	return errs
+}
+
+// lintUnusedGroups reports the group definitions that are not used by any node.
+func (c *CLab) lintUnusedGroups() []error {
+	used := map[string]bool{}
+	for name := range c.Config.Topology.Nodes {
+		used[c.Config.Topology.GetNodeGroup(name)] = true
+	}
+
+	var errs []error
+
+	for _, group := range sortedKeys(c.Config.Topology.Groups) {
+		if !used[group] {
+			errs = append(errs, fmt.Errorf("group %q is defined, but no node belongs to it", group))
+		}
+	}
+
+	return errs
+}
+
+// lintUnusedDefaults reports the kind and the image set in the defaults that are overridden by all the nodes.
+func (c *CLab) lintUnusedDefaults() []error {
+	t := c.Config.Topology
+	defaults := t.GetDefaults()
+
+	if len(t.Nodes) == 0 {
+		return nil
+	}
+
+	kindUsed, imageUsed := false, false
+
+	for name, ndef := range t.Nodes {
+		if ndef.GetKind() == "" {
+			kindUsed = true
+		}
+
+		if ndef.GetImage() == "" && t.GetKind(t.GetNodeKind(name)).GetImage() == "" {
+			imageUsed = true
+		}
+	}
+
+	var errs []error
+
+	if defaults.GetKind() != "" && !kindUsed {
+		errs = append(errs, fmt.Errorf("default kind %q is overridden by all nodes", defaults.GetKind()))
+	}
+
+	if defaults.GetImage() != "" && !imageUsed {
+		errs = append(errs, fmt.Errorf("default image %q is overridden by all nodes", defaults.GetImage()))
+	}
+
+	return errs
+}
+
+// sortedKeys returns the sorted keys of the map.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/runtime/docker"
+)
+
+func TestLint(t *testing.T) {
+	tests := map[string]struct {
+		topo string
+		want []*LintIssue
+	}{
+		"issues": {
+			topo: "test_data/topo22-lint.yml",
+			want: []*LintIssue{
+				{
+					Rule:     "links",
+					Severity: LintSeverityError,
+					Message:  "link 2: unable to find node l4",
+				},
+				{
+					Rule:     "mgmt-addresses",
+					Severity: LintSeverityError,
+					Message:  "management address 172.100.100.11 is used by multiple nodes: l1, l2",
+				},
+				{
+					Rule:     "no-image",
+					Severity: LintSeverityError,
+					Node:     "l3",
+					Message:  `node "l3" of kind "linux" has no image set on the node, kind or defaults level`,
+				},
+				{
+					Rule:     "reserved-interfaces",
+					Severity: LintSeverityError,
+					Node:     "l1",
+					Message:  `node "l1" uses the management interface eth0 in a link`,
+				},
+				{
+					Rule:     "unused-kinds",
+					Severity: LintSeverityWarning,
+					Message:  `kind "nokia_srlinux" is defined, but no node uses it`,
+				},
+				{
+					Rule:     "unused-groups",
+					Severity: LintSeverityWarning,
+					Message:  `group "spine" is defined, but no node belongs to it`,
+				},
+				{
+					Rule:     "unused-defaults",
+					Severity: LintSeverityWarning,
+					Message:  `default kind "linux" is overridden by all nodes`,
+				},
+			},
+		},
+		"no issues": {
+			topo: "test_data/topo17-macs.yml",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+			c, err := NewContainerLab(
+				WithTopoPath(tc.topo, ""),
+				WithRuntime(docker.RuntimeName,
+					&runtime.RuntimeConfig{
+						VerifyLinkParams: links.NewVerifyLinkParams(),
+					},
+				),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tc.want, c.Lint(context.Background())); d != "" {
+				t.Errorf("lint issues mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// mgmtAddressing is the addressing of the management network the static management addresses
+// of the nodes are checked against. The empty subnet means no subnet is known for the address family,
+// the empty gateway means the first host address of the subnet is used as the gateway.
+type mgmtAddressing struct {
+	IPv4Subnet string
+	IPv4Gw     string
+	IPv6Subnet string
+	IPv6Gw     string
+}
+
+// verifyMgmtAddresses checks the static management addresses of the nodes attached to the management network.
+// The subnets of the existing management network, e.g. an external network reused by the lab,
+// take precedence over the configured ones, since the nodes are attached to the existing network as is.
+func (c *CLab) verifyMgmtAddresses(ctx context.Context) error {
+	mgmt := c.Config.Mgmt
+	addressing := mgmtAddressing{
+		IPv4Subnet: mgmt.IPv4Subnet,
+		IPv4Gw:     mgmt.IPv4Gw,
+		IPv6Subnet: mgmt.IPv6Subnet,
+		IPv6Gw:     mgmt.IPv6Gw,
+	}
+
+	if r := c.GlobalRuntime(); r != nil {
+		netInfo, err := r.InspectMgmtNet(ctx)
+		if err != nil {
+			log.Debugf("failed to inspect the management network %q, checking the static management "+
+				"addresses against the configured subnets: %v", mgmt.Network, err)
+		}
+
+		if netInfo != nil {
+			if netInfo.IPv4Subnet != "" {
+				addressing.IPv4Subnet = netInfo.IPv4Subnet
+				addressing.IPv4Gw = netInfo.IPv4Gateway
+			}
+			if netInfo.IPv6Subnet != "" {
+				addressing.IPv6Subnet = netInfo.IPv6Subnet
+				addressing.IPv6Gw = netInfo.IPv6Gateway
+			}
+		}
+
+		// the gateway set by a user is the gateway the network is created with
+		if mgmt.IPv4Gw != "" {
+			addressing.IPv4Gw = mgmt.IPv4Gw
+		}
+		if mgmt.IPv6Gw != "" {
+			addressing.IPv6Gw = mgmt.IPv6Gw
+		}
+	}
+
+	nodeCfgs := make(map[string]*types.NodeConfig, len(c.Nodes))
+	for name, n := range c.Nodes {
+		nodeCfgs[name] = n.Config()
+	}
+
+	return checkMgmtAddresses(nodeCfgs, addressing)
+}
+
+// checkMgmtAddresses checks that the static management addresses of the nodes keyed by the node name
+// are valid addresses of their family, are unique across the lab, belong to the management subnet
+// of their family and don't collide with the network, broadcast and gateway addresses.
+// The subnet checks are skipped with a warning for the addresses of the family without a known subnet.
+// All the violations are reported together, ordered by the node name.
+func checkMgmtAddresses(nodeCfgs map[string]*types.NodeConfig, addressing mgmtAddressing) error {
+	v4Subnet, err := parseMgmtSubnet(addressing.IPv4Subnet, "IPv4")
+	if err != nil {
+		return err
+	}
+
+	v6Subnet, err := parseMgmtSubnet(addressing.IPv6Subnet, "IPv6")
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(nodeCfgs))
+	for name := range nodeCfgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	// users maps the normalized address to the nodes using it
+	users := map[string][]string{}
+	var addrs []string
+
+	for _, name := range names {
+		cfg := nodeCfgs[name]
+
+		if !attachedToMgmtNet(cfg) {
+			continue
+		}
+
+		for _, a := range []struct {
+			addr    string
+			v6      bool
+			subnet  *net.IPNet
+			gateway string
+		}{
+			{cfg.MgmtIPv4Address, false, v4Subnet, addressing.IPv4Gw},
+			{cfg.MgmtIPv6Address, true, v6Subnet, addressing.IPv6Gw},
+		} {
+			if a.addr == "" {
+				continue
+			}
+
+			ip, err := checkMgmtAddress(a.addr, a.v6, a.subnet, a.gateway)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("node %q: %w", name, err))
+				continue
+			}
+
+			// the address of the family without a known subnet is left for the runtime to validate
+			if a.subnet == nil {
+				log.Warnf("node %q: no management subnet is configured or discovered for the management address %s, "+
+					"skipping its subnet checks", name, ip)
+			}
+
+			key := ip.String()
+			if _, ok := users[key]; !ok {
+				addrs = append(addrs, key)
+			}
+			users[key] = append(users[key], name)
+		}
+	}
+
+	for _, addr := range addrs {
+		if len(users[addr]) > 1 {
+			errs = append(errs, fmt.Errorf("management address %s is used by multiple nodes: %s",
+				addr, strings.Join(users[addr], ", ")))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseMgmtSubnet parses the management subnet of the family, the empty subnet is returned as nil.
+func parseMgmtSubnet(subnet, family string) (*net.IPNet, error) {
+	if subnet == "" {
+		return nil, nil
+	}
+
+	_, n, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid management %s subnet %q: %w", family, subnet, err)
+	}
+
+	if (n.IP.To4() == nil) != (family == "IPv6") {
+		return nil, fmt.Errorf("management %s subnet %q is not an %s subnet", family, subnet, family)
+	}
+
+	return n, nil
+}
+
+// checkMgmtAddress parses the static management address of the IPv4 or the IPv6 family
+// and checks it against the management subnet of the family and its gateway.
+// The empty gateway means the first host address of the subnet is used as the gateway.
+// Only the address format is checked when the subnet is nil.
+func checkMgmtAddress(addr string, v6 bool, subnet *net.IPNet, gateway string) (net.IP, error) {
+	family, field := "IPv4", "mgmt-ipv4"
+	if v6 {
+		family, field = "IPv6", "mgmt-ipv6"
+	}
+
+	switch {
+	case strings.Contains(addr, "%"):
+		return nil, fmt.Errorf("%s address %q has a zone, zoned addresses are not supported", field, addr)
+	case strings.Contains(addr, "/"):
+		return nil, fmt.Errorf("%s address %q has a prefix length, set the address without it", field, addr)
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("%s address %q is not a valid IP address", field, addr)
+	}
+
+	// the IPv4-mapped IPv6 addresses, e.g. ::ffff:10.0.0.1, are neither valid IPv4 nor IPv6 management addresses
+	isV4 := ip.To4() != nil && !strings.Contains(addr, ":")
+	if isV4 == v6 || (v6 && ip.To4() != nil) {
+		return nil, fmt.Errorf("%s address %q is not an %s address", field, addr, family)
+	}
+
+	// nothing is known about the subnet of the address to check it against
+	if subnet == nil {
+		return ip, nil
+	}
+
+	if !subnet.Contains(ip) {
+		return nil, fmt.Errorf("%s address %s is outside of the management subnet %s", field, ip, subnet)
+	}
+
+	if ip.Equal(subnet.IP) {
+		return nil, fmt.Errorf("%s address %s is the network address of the management subnet %s",
+			field, ip, subnet)
+	}
+
+	if !v6 && ip.Equal(broadcastAddr(subnet)) {
+		return nil, fmt.Errorf("%s address %s is the broadcast address of the management subnet %s",
+			field, ip, subnet)
+	}
+
+	gw := net.ParseIP(gateway)
+	if gw == nil {
+		gw = firstHostAddr(subnet)
+	}
+
+	if ip.Equal(gw) {
+		return nil, fmt.Errorf("%s address %s is the gateway address of the management network", field, ip)
+	}
+
+	return ip, nil
+}
+
+// broadcastAddr returns the broadcast address of the IPv4 subnet.
+func broadcastAddr(n *net.IPNet) net.IP {
+	ip := n.IP.To4()
+	b := make(net.IP, len(ip))
+	for i := range ip {
+		b[i] = ip[i] | ^n.Mask[i]
+	}
+
+	return b
+}
+
+// firstHostAddr returns the first host address of the subnet which is used as the gateway by default.
+func firstHostAddr(n *net.IPNet) net.IP {
+	ip := make(net.IP, len(n.IP))
+	copy(ip, n.IP)
+
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i]++
+		if ip[i] != 0 {
+			break
+		}
+	}
+
+	return ip
+}
+
+// attachedToMgmtNet returns true when the node is attached to the management network,
+// the nodes with the host, none and container network modes are not.
+func attachedToMgmtNet(cfg *types.NodeConfig) bool {
+	switch strings.SplitN(cfg.NetworkMode, ":", 2)[0] {
+	case "host", "none", "container":
+		return false
+	}
+
+	return true
+}
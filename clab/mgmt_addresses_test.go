@@ -0,0 +1,155 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestCheckMgmtAddresses(t *testing.T) {
+	defaultAddressing := mgmtAddressing{
+		IPv4Subnet: "172.20.20.0/24",
+		IPv6Subnet: "3fff:172:20:20::/64",
+	}
+
+	tests := map[string]struct {
+		nodes      map[string]*types.NodeConfig
+		addressing mgmtAddressing
+		wantErr    string
+	}{
+		"valid": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "172.20.20.11", MgmtIPv6Address: "3fff:172:20:20::11"},
+				"n2": {MgmtIPv4Address: "172.20.20.12"},
+				"n3": {},
+			},
+			addressing: defaultAddressing,
+		},
+		"duplicates": {
+			nodes: map[string]*types.NodeConfig{
+				"n3": {MgmtIPv4Address: "172.20.20.11"},
+				"n1": {MgmtIPv4Address: "172.20.20.11", MgmtIPv6Address: "3fff:172:20:20::11"},
+				// the same IPv6 address written differently
+				"n2": {MgmtIPv6Address: "3fff:172:20:20:0:0:0:11"},
+			},
+			addressing: defaultAddressing,
+			wantErr: "management address 172.20.20.11 is used by multiple nodes: n1, n3\n" +
+				"management address 3fff:172:20:20::11 is used by multiple nodes: n1, n2",
+		},
+		"outside of the subnets": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "10.0.0.1"},
+				"n2": {MgmtIPv6Address: "2001:db8::1"},
+			},
+			addressing: defaultAddressing,
+			wantErr: `node "n1": mgmt-ipv4 address 10.0.0.1 is outside of the management subnet 172.20.20.0/24` + "\n" +
+				`node "n2": mgmt-ipv6 address 2001:db8::1 is outside of the management subnet 3fff:172:20:20::/64`,
+		},
+		"reserved addresses": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "172.20.20.0"},
+				"n2": {MgmtIPv4Address: "172.20.20.255"},
+				"n3": {MgmtIPv4Address: "172.20.20.1"},
+				"n4": {MgmtIPv6Address: "3fff:172:20:20::1"},
+			},
+			addressing: defaultAddressing,
+			wantErr: `node "n1": mgmt-ipv4 address 172.20.20.0 is the network address of the management subnet 172.20.20.0/24` + "\n" +
+				`node "n2": mgmt-ipv4 address 172.20.20.255 is the broadcast address of the management subnet 172.20.20.0/24` + "\n" +
+				`node "n3": mgmt-ipv4 address 172.20.20.1 is the gateway address of the management network` + "\n" +
+				`node "n4": mgmt-ipv6 address 3fff:172:20:20::1 is the gateway address of the management network`,
+		},
+		"custom gateway": {
+			nodes: map[string]*types.NodeConfig{
+				// the first host address is not reserved when the gateway is set
+				"n1": {MgmtIPv4Address: "172.20.20.1"},
+				"n2": {MgmtIPv4Address: "172.20.20.254"},
+			},
+			addressing: mgmtAddressing{
+				IPv4Subnet: "172.20.20.0/24",
+				IPv4Gw:     "172.20.20.254",
+			},
+			wantErr: `node "n2": mgmt-ipv4 address 172.20.20.254 is the gateway address of the management network`,
+		},
+		"invalid formats": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "172.20.20.300"},
+				"n2": {MgmtIPv4Address: "172.20.20.11/24"},
+				"n3": {MgmtIPv6Address: "fe80::1%eth0"},
+				"n4": {MgmtIPv4Address: "3fff:172:20:20::11"},
+				"n5": {MgmtIPv6Address: "172.20.20.11"},
+				"n6": {MgmtIPv6Address: "::ffff:172.20.20.11"},
+				"n7": {MgmtIPv4Address: "::ffff:172.20.20.11"},
+			},
+			addressing: defaultAddressing,
+			wantErr: `node "n1": mgmt-ipv4 address "172.20.20.300" is not a valid IP address` + "\n" +
+				`node "n2": mgmt-ipv4 address "172.20.20.11/24" has a prefix length, set the address without it` + "\n" +
+				`node "n3": mgmt-ipv6 address "fe80::1%eth0" has a zone, zoned addresses are not supported` + "\n" +
+				`node "n4": mgmt-ipv4 address "3fff:172:20:20::11" is not an IPv4 address` + "\n" +
+				`node "n5": mgmt-ipv6 address "172.20.20.11" is not an IPv6 address` + "\n" +
+				`node "n6": mgmt-ipv6 address "::ffff:172.20.20.11" is not an IPv6 address` + "\n" +
+				`node "n7": mgmt-ipv4 address "::ffff:172.20.20.11" is not an IPv4 address`,
+		},
+		// the subnet checks are skipped for the addresses of the families without a known subnet
+		"no subnets": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "172.20.20.11"},
+				"n2": {MgmtIPv6Address: "3fff:172:20:20::11"},
+			},
+		},
+		"no IPv6 subnet": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "172.20.20.11", MgmtIPv6Address: "3fff:172:20:20::1"},
+				"n2": {MgmtIPv4Address: "172.20.20.1"},
+			},
+			addressing: mgmtAddressing{IPv4Subnet: "172.20.20.0/24"},
+			wantErr:    `node "n2": mgmt-ipv4 address 172.20.20.1 is the gateway address of the management network`,
+		},
+		"no subnets with invalid and duplicate addresses": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "172.20.20.11"},
+				"n2": {MgmtIPv4Address: "172.20.20.11"},
+				"n3": {MgmtIPv6Address: "172.20.20.12"},
+			},
+			wantErr: `node "n3": mgmt-ipv6 address "172.20.20.12" is not an IPv6 address` + "\n" +
+				"management address 172.20.20.11 is used by multiple nodes: n1, n2",
+		},
+		"no subnets without static addresses": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {},
+			},
+		},
+		"nodes outside of the management network": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "10.0.0.1", NetworkMode: "host"},
+				"n2": {MgmtIPv4Address: "10.0.0.1", NetworkMode: "container:n1"},
+			},
+			addressing: defaultAddressing,
+		},
+		"invalid subnet": {
+			nodes: map[string]*types.NodeConfig{
+				"n1": {MgmtIPv4Address: "172.20.20.11"},
+			},
+			addressing: mgmtAddressing{IPv4Subnet: "3fff:172:20:20::/64"},
+			wantErr:    `management IPv4 subnet "3fff:172:20:20::/64" is not an IPv4 subnet`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkMgmtAddresses(tt.nodes, tt.addressing)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("got no error, want %q", tt.wantErr)
+			case tt.wantErr != "" && err.Error() != tt.wantErr:
+				t.Fatalf("got error:\n%v\nwant:\n%s", err, tt.wantErr)
+			}
+		})
+	}
+}
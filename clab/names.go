@@ -0,0 +1,379 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// maxHostnameLen is the max length of a hostname label.
+	maxHostnameLen = 63
+	// maxNetnsNameLen is the max length of the netns symlink name which is the container name (NAME_MAX).
+	maxNetnsNameLen = 255
+	// maxIfaceNameLen is the max length of a network interface name (IFNAMSIZ without the terminating null).
+	maxIfaceNameLen = utils.IFNAMSIZ - 1
+	// nameHashLen is the length of the hash suffix of the shortened names.
+	nameHashLen = 8
+
+	// prefixHint is the remediation hint for the over-long container names.
+	prefixHint = "use a shorter lab or node name, or set the topology prefix to \"\" or \"__lab-name\""
+)
+
+// invalidHostnameCharsRe matches the characters that are not allowed in a hostname label.
+var invalidHostnameCharsRe = regexp.MustCompile(`[^a-z0-9-]`)
+
+// containerlessKinds are the kinds which nodes don't have containers created by containerlab.
+var containerlessKinds = map[string]struct{}{
+	"bridge":        {},
+	"ovs-bridge":    {},
+	"host":          {},
+	"ext-container": {},
+}
+
+// nameLengthViolation is an identifier derived from the lab and node names that exceeds its length limit.
+type nameLengthViolation struct {
+	// Node is the name of the node the identifier belongs to, empty for the lab-wide identifiers.
+	Node string
+	// Identifier is the identifier class, e.g. container name.
+	Identifier string
+	Value      string
+	Limit      int
+	Hint       string
+}
+
+// longName returns the container name of the node that follows the $prefix-$lab-$nodeName pattern.
+func (c *CLab) longName(nodeName string) string {
+	switch {
+	// when prefix is an empty string longName will match shortName/nodeName
+	case *c.Config.Prefix == "":
+		return nodeName
+	case *c.Config.Prefix == "__lab-name":
+		return fmt.Sprintf("%s-%s", c.Config.Name, nodeName)
+	}
+
+	return fmt.Sprintf("%s-%s-%s", *c.Config.Prefix, c.Config.Name, nodeName)
+}
+
+// canShortenName returns true if the container name of the node can be shortened.
+// The container names of the nodes sharing a network namespace are not shortened,
+// since the runtimes derive the name of the referenced container from the referencing node name.
+func (c *CLab) canShortenName(nodeName string) bool {
+	for name := range c.Config.Topology.Nodes {
+		netMode := strings.SplitN(c.Config.Topology.GetNodeNetworkMode(name), ":", 2)
+		if netMode[0] != "container" || len(netMode) != 2 {
+			continue
+		}
+
+		if name == nodeName || netMode[1] == nodeName {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hostname returns the hostname of the node. The node name is used as is,
+// unless the relaxed node names are enabled and the node name is not a valid hostname.
+func (c *CLab) hostname(nodeName string) string {
+	if !c.relaxedNodeNames || hostnameProblems(nodeName) == nil {
+		return nodeName
+	}
+
+	return relaxedHostname(nodeName)
+}
+
+// relaxedHostname maps the node name to a valid hostname by lowercasing it,
+// replacing every invalid character with a hyphen and trimming the leading and trailing hyphens.
+// The names which are mapped to an over-long hostname are shortened with a hash suffix.
+// The mapping depends on the node name only, so the hostname is stable across runs.
+func relaxedHostname(name string) string {
+	h := invalidHostnameCharsRe.ReplaceAllString(strings.ToLower(name), "-")
+	h = strings.Trim(h, "-")
+
+	if h == "" {
+		h = "node-" + fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:nameHashLen]
+	}
+
+	return shortenName(h, maxHostnameLen)
+}
+
+// hostnameProblems returns the RFC 1123 hostname label rules the name violates.
+// The length of the hostname is checked along with the other name lengths.
+func hostnameProblems(name string) []string {
+	var ps []string
+
+	if name == "" {
+		return []string{"is empty"}
+	}
+
+	if strings.ToLower(name) != name {
+		ps = append(ps, "contains uppercase letters")
+	}
+
+	if invalidHostnameCharsRe.MatchString(strings.ToLower(name)) {
+		ps = append(ps, "contains characters other than letters, digits and hyphens")
+	}
+
+	if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") {
+		ps = append(ps, "starts or ends with a hyphen")
+	}
+
+	return ps
+}
+
+// shortenName truncates the name to the max length keeping the name unique by adding a hash suffix.
+// The hash is computed over the full name, so the shortened name is stable across runs.
+func shortenName(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:nameHashLen]
+
+	return name[:max-nameHashLen-1] + "-" + hash
+}
+
+// nameLengthViolations returns the identifiers derived from the lab and node names exceeding their limits.
+func (c *CLab) nameLengthViolations() []nameLengthViolation {
+	var vs []nameLengthViolation
+
+	nodeNames := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	for _, name := range nodeNames {
+		cfg := c.Nodes[name].Config()
+		if _, ok := containerlessKinds[cfg.Kind]; ok {
+			continue
+		}
+
+		// the node config holds the shortened container name when the auto shortening is enabled
+		cName := cfg.LongName
+
+		hint := prefixHint + ", or deploy with --auto-shorten-names"
+		if c.autoShortenNames || !c.canShortenName(name) {
+			hint = prefixHint + ", the names of the nodes sharing a network namespace are not shortened"
+		}
+
+		// the netns symlink is named after the container
+		if len(cName) > maxNetnsNameLen {
+			vs = append(vs, nameLengthViolation{
+				Node:       name,
+				Identifier: "netns symlink name",
+				Value:      cName,
+				Limit:      maxNetnsNameLen,
+				Hint:       hint,
+			})
+		}
+
+		// the hostname is not set for the nodes using a network namespace of another container
+		if !strings.HasPrefix(cfg.NetworkMode, "container:") && len(cfg.GetHostname()) > maxHostnameLen {
+			vs = append(vs, nameLengthViolation{
+				Node:       name,
+				Identifier: "hostname",
+				Value:      cfg.GetHostname(),
+				Limit:      maxHostnameLen,
+				Hint:       "use a shorter node name",
+			})
+		}
+	}
+
+	// the endpoint interface names longer than IFNAMSIZ are set as interface aliases,
+	// but the management bridge is created with the configured name
+	if c.Config.Mgmt != nil && len(c.Config.Mgmt.Bridge) > maxIfaceNameLen {
+		vs = append(vs, nameLengthViolation{
+			Identifier: "management bridge name",
+			Value:      c.Config.Mgmt.Bridge,
+			Limit:      maxIfaceNameLen,
+			Hint:       "use a shorter bridge name in the mgmt section of the topology",
+		})
+	}
+
+	return vs
+}
+
+// verifyNameLengths checks that the identifiers derived from the lab and node names
+// do not exceed their length limits and reports all violations at once.
+func (c *CLab) verifyNameLengths() error {
+	vs := c.nameLengthViolations()
+	if len(vs) == 0 {
+		if c.autoShortenNames {
+			c.logShortenedNames()
+		}
+
+		return nil
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%d identifier(s) exceed their length limits:\n", len(vs))
+
+	for _, v := range vs {
+		if v.Node != "" {
+			fmt.Fprintf(sb, "  - node: %s\n", v.Node)
+			fmt.Fprintf(sb, "    %s: %s\n", v.Identifier, v.Value)
+		} else {
+			fmt.Fprintf(sb, "  - %s: %s\n", v.Identifier, v.Value)
+		}
+		fmt.Fprintf(sb, "    length: %d, limit: %d\n", len(v.Value), v.Limit)
+		fmt.Fprintf(sb, "    hint: %s\n", v.Hint)
+	}
+
+	return fmt.Errorf("%s", strings.TrimSuffix(sb.String(), "\n"))
+}
+
+// logShortenedNames logs the container names that were shortened to fit the limit.
+func (c *CLab) logShortenedNames() {
+	nodeNames := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	for _, name := range nodeNames {
+		cfg := c.Nodes[name].Config()
+		if _, ok := containerlessKinds[cfg.Kind]; ok {
+			continue
+		}
+
+		if cName := cfg.LongName; cName != c.longName(name) {
+			log.Infof("Container name of the node %q is shortened to %q", name, cName)
+		}
+	}
+}
+
+// nodeNameViolation is a node name that can't be used as the hostname of the node.
+type nodeNameViolation struct {
+	Node    string
+	Problem string
+	Hint    string
+}
+
+// nodeNameViolations returns the node names that are not valid hostnames
+// and the nodes which hostnames collide case-insensitively.
+// The nodes without the containers and the nodes sharing a network namespace of another container are skipped,
+// since their hostnames are not set.
+func (c *CLab) nodeNameViolations() []nodeNameViolation {
+	var vs []nodeNameViolation
+
+	nodeNames := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	// hostnames maps the lowercased hostnames to the names of the nodes using them
+	hostnames := map[string][]string{}
+
+	for _, name := range nodeNames {
+		cfg := c.Nodes[name].Config()
+		if _, ok := containerlessKinds[cfg.Kind]; ok {
+			continue
+		}
+
+		if strings.HasPrefix(cfg.NetworkMode, "container:") {
+			continue
+		}
+
+		if ps := hostnameProblems(cfg.GetHostname()); ps != nil {
+			vs = append(vs, nodeNameViolation{
+				Node:    name,
+				Problem: "not a valid hostname, the name " + strings.Join(ps, ", "),
+				Hint: fmt.Sprintf("use a lowercase name of letters, digits and hyphens, e.g. %q, "+
+					"or deploy with --relaxed-node-names", relaxedHostname(name)),
+			})
+		}
+
+		h := strings.ToLower(cfg.GetHostname())
+		hostnames[h] = append(hostnames[h], name)
+	}
+
+	collisions := make([]string, 0, len(hostnames))
+	for h, names := range hostnames {
+		if len(names) > 1 {
+			collisions = append(collisions, h)
+		}
+	}
+	sort.Strings(collisions)
+
+	for _, h := range collisions {
+		for _, name := range hostnames[h] {
+			vs = append(vs, nodeNameViolation{
+				Node: name,
+				Problem: fmt.Sprintf("hostname %q collides case-insensitively with the node(s) %s",
+					c.Nodes[name].Config().GetHostname(), strings.Join(otherNames(hostnames[h], name), ", ")),
+				Hint: "use node names that differ in more than the letter case and the invalid characters",
+			})
+		}
+	}
+
+	return vs
+}
+
+// otherNames returns the names without the name n.
+func otherNames(names []string, n string) []string {
+	var others []string
+
+	for _, name := range names {
+		if name != n {
+			others = append(others, name)
+		}
+	}
+
+	return others
+}
+
+// verifyNodeNames checks that the node names are valid hostnames which don't collide case-insensitively
+// and reports all violations at once.
+func (c *CLab) verifyNodeNames() error {
+	vs := c.nodeNameViolations()
+	if len(vs) == 0 {
+		if c.relaxedNodeNames {
+			c.logRelaxedHostnames()
+		}
+
+		return nil
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%d node name problem(s) found:\n", len(vs))
+
+	for _, v := range vs {
+		fmt.Fprintf(sb, "  - node: %s\n", v.Node)
+		fmt.Fprintf(sb, "    problem: %s\n", v.Problem)
+		fmt.Fprintf(sb, "    hint: %s\n", v.Hint)
+	}
+
+	return fmt.Errorf("%s", strings.TrimSuffix(sb.String(), "\n"))
+}
+
+// logRelaxedHostnames logs the hostnames that were derived from the node names.
+func (c *CLab) logRelaxedHostnames() {
+	nodeNames := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	for _, name := range nodeNames {
+		cfg := c.Nodes[name].Config()
+		if _, ok := containerlessKinds[cfg.Kind]; ok {
+			continue
+		}
+
+		if h := cfg.GetHostname(); h != name {
+			log.Infof("Hostname of the node %q is set to %q", name, h)
+		}
+	}
+}
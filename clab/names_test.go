@@ -0,0 +1,341 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestShortenName(t *testing.T) {
+	atLimit := strings.Repeat("a", maxNetnsNameLen)
+	if got := shortenName(atLimit, maxNetnsNameLen); got != atLimit {
+		t.Fatalf("name at the limit was shortened to %q", got)
+	}
+
+	overLimit := strings.Repeat("a", maxNetnsNameLen) + "1"
+	got := shortenName(overLimit, maxNetnsNameLen)
+
+	if len(got) != maxNetnsNameLen {
+		t.Fatalf("shortened name %q is %d characters long, want %d", got, len(got), maxNetnsNameLen)
+	}
+
+	if !regexp.MustCompile(`^a+-[0-9a-f]{8}$`).MatchString(got) {
+		t.Fatalf("unexpected shortened name %q", got)
+	}
+
+	if again := shortenName(overLimit, maxNetnsNameLen); again != got {
+		t.Fatalf("shortened name is not stable: %q != %q", again, got)
+	}
+
+	// names sharing the truncated part are kept unique by the hash
+	other := strings.Repeat("a", maxNetnsNameLen) + "2"
+	if shortenName(other, maxNetnsNameLen) == got {
+		t.Fatalf("names %q and %q are shortened to the same name", overLimit, other)
+	}
+}
+
+func TestNameLengthViolations(t *testing.T) {
+	// the container name of the node n follows the clab-lab-n pattern which adds 9 chars to the node name
+	const labPrefixLen = len("clab-lab-")
+
+	tests := map[string]struct {
+		prefix   string
+		nodeName string
+		// netMode is the network mode of the node
+		netMode     string
+		bridge      string
+		autoShorten bool
+		// want are the violated identifiers with their lengths
+		want []string
+	}{
+		"netns symlink name at limit": {
+			prefix:   "clab",
+			nodeName: strings.Repeat("n", maxNetnsNameLen-labPrefixLen),
+		},
+		"netns symlink name over limit": {
+			prefix:   "clab",
+			nodeName: strings.Repeat("n", maxNetnsNameLen-labPrefixLen+1),
+			want:     []string{"netns symlink name: 256"},
+		},
+		"netns symlink name over limit shortened": {
+			prefix:      "clab",
+			nodeName:    strings.Repeat("n", maxNetnsNameLen-labPrefixLen+1),
+			autoShorten: true,
+		},
+		"netns symlink name over limit sharing netns": {
+			prefix:      "clab",
+			nodeName:    strings.Repeat("n", maxNetnsNameLen-labPrefixLen+1),
+			netMode:     "container:other",
+			autoShorten: true,
+			want:        []string{"netns symlink name: 256"},
+		},
+		"container name over hostname limit": {
+			prefix:   "clab",
+			nodeName: strings.Repeat("n", maxHostnameLen-labPrefixLen+1),
+		},
+		"hostname at limit": {
+			prefix:   "",
+			nodeName: strings.Repeat("n", maxHostnameLen),
+		},
+		"hostname over limit": {
+			prefix:      "",
+			nodeName:    strings.Repeat("n", maxHostnameLen+1),
+			autoShorten: true,
+			want:        []string{"hostname: 64"},
+		},
+		"hostname over limit sharing netns": {
+			prefix:   "",
+			nodeName: strings.Repeat("n", maxHostnameLen+1),
+			netMode:  "container:other",
+		},
+		"bridge name at limit": {
+			prefix:   "clab",
+			nodeName: "n1",
+			bridge:   strings.Repeat("b", maxIfaceNameLen),
+		},
+		"bridge name over limit": {
+			prefix:   "clab",
+			nodeName: "n1",
+			bridge:   strings.Repeat("b", maxIfaceNameLen+1),
+			want:     []string{"management bridge name: 16"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			topo := types.NewTopology()
+			topo.Nodes[tc.nodeName] = &types.NodeDefinition{NetworkMode: tc.netMode}
+
+			c := &CLab{
+				Config: &Config{
+					Name:     "lab",
+					Prefix:   &tc.prefix,
+					Mgmt:     &types.MgmtNet{Bridge: tc.bridge},
+					Topology: topo,
+				},
+				Nodes:            map[string]nodes.Node{},
+				autoShortenNames: tc.autoShorten,
+			}
+
+			cfg := &types.NodeConfig{
+				ShortName:   tc.nodeName,
+				LongName:    c.longName(tc.nodeName),
+				Kind:        "linux",
+				NetworkMode: tc.netMode,
+			}
+
+			// the container names are shortened the same way createNodeCfg does
+			if c.autoShortenNames && c.canShortenName(tc.nodeName) {
+				cfg.LongName = shortenName(cfg.LongName, maxNetnsNameLen)
+			}
+
+			n := mocknodes.NewMockNode(ctrl)
+			n.EXPECT().Config().Return(cfg).AnyTimes()
+			c.Nodes[tc.nodeName] = n
+
+			var got []string
+			for _, v := range c.nameLengthViolations() {
+				got = append(got, fmt.Sprintf("%s: %d", v.Identifier, len(v.Value)))
+			}
+
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Fatalf("violations mismatch (-want +got):\n%s", d)
+			}
+
+			if err := c.verifyNameLengths(); (err != nil) != (len(tc.want) > 0) {
+				t.Fatalf("unexpected verification result: %v", err)
+			}
+		})
+	}
+}
+
+func TestHostnameProblems(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		want []string
+	}{
+		"valid":              {name: "leaf1"},
+		"valid with hyphens": {name: "dc1-leaf-1"},
+		"valid single char":  {name: "a"},
+		"valid digits only":  {name: "101"},
+		"empty":              {name: "", want: []string{"is empty"}},
+		"uppercase":          {name: "Leaf1", want: []string{"contains uppercase letters"}},
+		"underscore":         {name: "leaf_1", want: []string{"contains characters other than letters, digits and hyphens"}},
+		"dot":                {name: "leaf.1", want: []string{"contains characters other than letters, digits and hyphens"}},
+		"non-ascii":          {name: "léaf1", want: []string{"contains characters other than letters, digits and hyphens"}},
+		"leading hyphen":     {name: "-leaf1", want: []string{"starts or ends with a hyphen"}},
+		"trailing hyphen":    {name: "leaf1-", want: []string{"starts or ends with a hyphen"}},
+		"multiple problems": {
+			name: "Leaf_1-",
+			want: []string{
+				"contains uppercase letters",
+				"contains characters other than letters, digits and hyphens",
+				"starts or ends with a hyphen",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tc.want, hostnameProblems(tc.name)); d != "" {
+				t.Fatalf("problems mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRelaxedHostname(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		want string
+	}{
+		"uppercase":       {name: "Leaf1", want: "leaf1"},
+		"underscores":     {name: "some_node_name", want: "some-node-name"},
+		"dots":            {name: "leaf.1", want: "leaf-1"},
+		"trimmed hyphens": {name: "_leaf1_", want: "leaf1"},
+		"no valid chars":  {name: "___", want: "node-" + fmt.Sprintf("%x", sha256.Sum256([]byte("___")))[:nameHashLen]},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := relaxedHostname(tc.name)
+			if got != tc.want {
+				t.Fatalf("got hostname %q, want %q", got, tc.want)
+			}
+
+			if ps := hostnameProblems(got); ps != nil {
+				t.Fatalf("hostname %q is not valid: %v", got, ps)
+			}
+
+			if again := relaxedHostname(tc.name); again != got {
+				t.Fatalf("hostname is not stable: %q != %q", again, got)
+			}
+		})
+	}
+
+	long := relaxedHostname(strings.Repeat("N_", maxHostnameLen))
+	if len(long) != maxHostnameLen || hostnameProblems(long) != nil {
+		t.Fatalf("over-long name is mapped to the invalid hostname %q", long)
+	}
+}
+
+func TestNodeNameViolations(t *testing.T) {
+	tests := map[string]struct {
+		nodes   []string
+		kinds   map[string]string
+		netMode map[string]string
+		relaxed bool
+		// want are the nodes with their problems
+		want []string
+	}{
+		"valid names": {
+			nodes: []string{"leaf1", "leaf2", "spine-1"},
+		},
+		"invalid names reported at once": {
+			nodes: []string{"Leaf1", "leaf_2", "spine-1"},
+			want: []string{
+				"Leaf1: not a valid hostname, the name contains uppercase letters",
+				"leaf_2: not a valid hostname, the name contains characters other than letters, digits and hyphens",
+			},
+		},
+		"invalid names relaxed": {
+			nodes:   []string{"Leaf1", "leaf_2", "spine-1"},
+			relaxed: true,
+		},
+		"case-insensitive collision": {
+			nodes: []string{"Leaf1", "leaf1"},
+			want: []string{
+				"Leaf1: not a valid hostname, the name contains uppercase letters",
+				`Leaf1: hostname "Leaf1" collides case-insensitively with the node(s) leaf1`,
+				`leaf1: hostname "leaf1" collides case-insensitively with the node(s) Leaf1`,
+			},
+		},
+		"case-insensitive collision relaxed": {
+			nodes:   []string{"Leaf1", "leaf1"},
+			relaxed: true,
+			want: []string{
+				`Leaf1: hostname "leaf1" collides case-insensitively with the node(s) leaf1`,
+				`leaf1: hostname "leaf1" collides case-insensitively with the node(s) Leaf1`,
+			},
+		},
+		"relaxed mapping collision": {
+			nodes:   []string{"leaf-1", "leaf_1", "LEAF_1"},
+			relaxed: true,
+			want: []string{
+				`LEAF_1: hostname "leaf-1" collides case-insensitively with the node(s) leaf-1, leaf_1`,
+				`leaf-1: hostname "leaf-1" collides case-insensitively with the node(s) LEAF_1, leaf_1`,
+				`leaf_1: hostname "leaf-1" collides case-insensitively with the node(s) LEAF_1, leaf-1`,
+			},
+		},
+		"containerless kinds skipped": {
+			nodes: []string{"BR_1", "br_1", "leaf1"},
+			kinds: map[string]string{"BR_1": "bridge", "br_1": "ovs-bridge"},
+		},
+		"nodes sharing netns skipped": {
+			nodes:   []string{"Leaf1", "leaf1"},
+			netMode: map[string]string{"Leaf1": "container:leaf1"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			prefix := "clab"
+			c := &CLab{
+				Config: &Config{
+					Name:     "lab",
+					Prefix:   &prefix,
+					Topology: types.NewTopology(),
+				},
+				Nodes:            map[string]nodes.Node{},
+				relaxedNodeNames: tc.relaxed,
+			}
+
+			for _, nodeName := range tc.nodes {
+				kind := "linux"
+				if k, ok := tc.kinds[nodeName]; ok {
+					kind = k
+				}
+
+				cfg := &types.NodeConfig{
+					ShortName:   nodeName,
+					Hostname:    c.hostname(nodeName),
+					Kind:        kind,
+					NetworkMode: tc.netMode[nodeName],
+				}
+
+				n := mocknodes.NewMockNode(ctrl)
+				n.EXPECT().Config().Return(cfg).AnyTimes()
+				c.Nodes[nodeName] = n
+			}
+
+			var got []string
+			for _, v := range c.nodeNameViolations() {
+				got = append(got, fmt.Sprintf("%s: %s", v.Node, v.Problem))
+			}
+
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Fatalf("violations mismatch (-want +got):\n%s", d)
+			}
+
+			if err := c.verifyNodeNames(); (err != nil) != (len(tc.want) > 0) {
+				t.Fatalf("unexpected verification result: %v", err)
+			}
+		})
+	}
+}
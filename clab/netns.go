@@ -0,0 +1,77 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// staleNetnsSymlinks returns the container names of the lab nodes which netns symlinks are stale.
+// A symlink is stale when the container of the node no longer exists, e.g. after a crashed deployment
+// or when the container was removed bypassing containerlab. The symlink target is not checked,
+// since it may point to a network namespace of an unrelated process that reused the container PID.
+// Only symlinks are considered, the named network namespaces created by other tools are left intact.
+func (c *CLab) staleNetnsSymlinks(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var stale []string
+
+	for _, name := range names {
+		n := c.Nodes[name]
+		cName := n.Config().LongName
+
+		fi, err := os.Lstat(filepath.Join(utils.NetnsDir, cName))
+		if err != nil || fi.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		exists, err := containerExists(ctx, n, cName)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			stale = append(stale, cName)
+		}
+	}
+
+	return stale, nil
+}
+
+// containerExists returns true if the container named cName exists in the runtime of the node n.
+func containerExists(ctx context.Context, n nodes.Node, cName string) (bool, error) {
+	containers, err := n.GetRuntime().ListContainers(ctx, []*types.GenericFilter{
+		{
+			FilterType: "name",
+			Match:      cName,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// the name filter matches the containers which names contain cName
+	for _, cnt := range containers {
+		for _, cntName := range cnt.Names {
+			if strings.TrimPrefix(cntName, "/") == cName {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
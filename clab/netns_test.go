@@ -0,0 +1,77 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+func TestStaleNetnsSymlinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+	netnsDir := t.TempDir()
+
+	oldNetnsDir := utils.NetnsDir
+	utils.NetnsDir = netnsDir
+	t.Cleanup(func() { utils.NetnsDir = oldNetnsDir })
+
+	// the symlinks may point to a live netns of another process, so their targets are not checked
+	liveNs := filepath.Join(t.TempDir(), "ns")
+	if err := os.WriteFile(liveNs, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"clab-test-running", "clab-test-gone", "clab-other-node"} {
+		if err := os.Symlink(liveNs, filepath.Join(netnsDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a named netns created by another tool is a regular file
+	if err := os.WriteFile(filepath.Join(netnsDir, "clab-test-netns"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &CLab{Nodes: map[string]nodes.Node{}}
+
+	for _, name := range []string{"clab-test-running", "clab-test-gone", "clab-test-missing", "clab-test-netns"} {
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().Config().Return(&types.NodeConfig{LongName: name}).AnyTimes()
+		n.EXPECT().GetRuntime().Return(rt).AnyTimes()
+		c.Nodes[name] = n
+	}
+
+	nameFilter := func(name string) []*types.GenericFilter {
+		return []*types.GenericFilter{{FilterType: "name", Match: name}}
+	}
+
+	rt.EXPECT().ListContainers(gomock.Any(), nameFilter("clab-test-running")).Return(
+		[]runtime.GenericContainer{{Names: []string{"/clab-test-running"}}}, nil)
+	// the name filter matches the container which name only contains the node container name
+	rt.EXPECT().ListContainers(gomock.Any(), nameFilter("clab-test-gone")).Return(
+		[]runtime.GenericContainer{{Names: []string{"clab-test-gone2"}}}, nil)
+
+	stale, err := c.staleNetnsSymlinks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]string{"clab-test-gone"}, stale); d != "" {
+		t.Fatalf("stale symlinks mismatch (-want +got):\n%s", d)
+	}
+}
@@ -2,20 +2,129 @@ package clab
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime"
 )
 
+// CreateNetwork creates the mgmt network in the global runtime and in every runtime hosting at least one node,
+// or reuses the existing one. The mgmt networks of the runtimes must have the same subnets.
 func (c *CLab) CreateNetwork(ctx context.Context) error {
-	// create docker network or use existing one
-	if err := c.GlobalRuntime().CreateNet(ctx); err != nil {
-		return err
+	var ref *runtime.NetworkInfo
+	refRuntime := ""
+
+	names := c.nodeRuntimeNames()
+
+	for _, name := range names {
+		r := c.Runtimes[name]
+
+		if err := c.createRuntimeNetwork(ctx, r); err != nil {
+			return err
+		}
+
+		// a single runtime doesn't need the subnets consistency check
+		if len(names) < 2 {
+			continue
+		}
+
+		netInfo, err := r.InspectMgmtNet(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to inspect the management network of the %s runtime: %w", name, err)
+		}
+
+		if ref == nil {
+			ref, refRuntime = netInfo, name
+			continue
+		}
+
+		if netInfo.IPv4Subnet != ref.IPv4Subnet || netInfo.IPv6Subnet != ref.IPv6Subnet {
+			return fmt.Errorf("management network %q of the %s runtime has subnets %q, %q, "+
+				"but the one of the %s runtime has subnets %q, %q",
+				netInfo.Name, name, netInfo.IPv4Subnet, netInfo.IPv6Subnet,
+				refRuntime, ref.IPv4Subnet, ref.IPv6Subnet)
+		}
 	}
 
-	// save mgmt bridge name as a label
+	// save mgmt bridge name of the node runtime as a label
 	for _, n := range c.Nodes {
-		n.Config().Labels[labels.NodeMgmtNetBr] = c.GlobalRuntime().Mgmt().Bridge
+		if r := n.GetRuntime(); r != nil {
+			n.Config().Labels[labels.NodeMgmtNetBr] = r.Mgmt().Bridge
+		}
 	}
 
 	return nil
 }
+
+// nodeRuntimeNames returns the names of the global runtime and the runtimes hosting at least one node.
+// The global runtime goes first, followed by the other runtimes sorted by name.
+func (c *CLab) nodeRuntimeNames() []string {
+	var names []string
+
+	for name, r := range c.Runtimes {
+		if name == c.globalRuntime {
+			names = append(names, name)
+			continue
+		}
+
+		for _, n := range c.Nodes {
+			if n.GetRuntime() == r {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == c.globalRuntime) != (names[j] == c.globalRuntime) {
+			return names[i] == c.globalRuntime
+		}
+
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// createRuntimeNetwork creates the mgmt network in the runtime r or reuses the existing one.
+func (c *CLab) createRuntimeNetwork(ctx context.Context, r runtime.ContainerRuntime) error {
+	err := r.CreateNet(ctx)
+
+	var tuningErr *runtime.HostTuningError
+	switch {
+	case errors.As(err, &tuningErr):
+		if !c.ignoreHostTuningFailures {
+			log.Error(PreflightReport(hostTuningFindings(tuningErr)))
+			return fmt.Errorf("%w. Add '--ignore-host-tuning-failures' flag to the deploy command to proceed anyway", err)
+		}
+		log.Warn(err)
+	case err != nil:
+		return err
+	}
+
+	return nil
+}
+
+// hostTuningFindings converts the host tuning failures to the preflight findings.
+// The findings can't be resolved automatically.
+func hostTuningFindings(tuningErr *runtime.HostTuningError) []*PreflightFinding {
+	findings := make([]*PreflightFinding, 0, len(tuningErr.Failures))
+
+	for _, f := range tuningErr.Failures {
+		problem := fmt.Sprintf("failed to set %s on the management bridge %s: %v", f.Setting, tuningErr.Bridge, f.Err)
+		if tuningErr.Containerized {
+			problem += ". Containerlab runs in a container, make sure it is privileged and uses the host network namespace"
+		}
+
+		findings = append(findings, &PreflightFinding{
+			Check:      "host-tuning",
+			Problem:    problem,
+			Suggestion: f.Suggestion,
+		})
+	}
+
+	return findings
+}
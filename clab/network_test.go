@@ -0,0 +1,122 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// newNetworkTestNode returns a mock node hosted by the runtime r.
+func newNetworkTestNode(mockCtrl *gomock.Controller, name string, r runtime.ContainerRuntime) nodes.Node {
+	n := mocknodes.NewMockNode(mockCtrl)
+	n.EXPECT().GetRuntime().Return(r).AnyTimes()
+	n.EXPECT().Config().Return(&types.NodeConfig{
+		ShortName: name,
+		Labels:    map[string]string{},
+	}).AnyTimes()
+
+	return n
+}
+
+// newNetworkTestRuntime returns a mock runtime with the mgmt network using the bridge br and the subnet.
+func newNetworkTestRuntime(mockCtrl *gomock.Controller, br, subnet string) *mockruntime.MockContainerRuntime {
+	r := mockruntime.NewMockContainerRuntime(mockCtrl)
+	r.EXPECT().Mgmt().Return(&types.MgmtNet{Network: "clab", Bridge: br}).AnyTimes()
+	r.EXPECT().InspectMgmtNet(gomock.Any()).Return(&runtime.NetworkInfo{
+		Name:       "clab",
+		IPv4Subnet: subnet,
+	}, nil).AnyTimes()
+
+	return r
+}
+
+func TestCreateNetworkMultipleRuntimes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	docker := newNetworkTestRuntime(mockCtrl, "br-docker", "172.20.20.0/24")
+	podman := newNetworkTestRuntime(mockCtrl, "br-podman", "172.20.20.0/24")
+	// the runtime without nodes doesn't get the mgmt network
+	unused := mockruntime.NewMockContainerRuntime(mockCtrl)
+
+	// the network is created exactly once in every runtime, starting with the global one
+	gomock.InOrder(
+		docker.EXPECT().CreateNet(gomock.Any()).Return(nil).Times(1),
+		podman.EXPECT().CreateNet(gomock.Any()).Return(nil).Times(1),
+	)
+
+	c := &CLab{
+		globalRuntime: "docker",
+		Runtimes: map[string]runtime.ContainerRuntime{
+			"docker": docker,
+			"podman": podman,
+			"ignite": unused,
+		},
+		Nodes: map[string]nodes.Node{
+			"srl1": newNetworkTestNode(mockCtrl, "srl1", docker),
+			"l1":   newNetworkTestNode(mockCtrl, "l1", podman),
+			"l2":   newNetworkTestNode(mockCtrl, "l2", podman),
+		},
+	}
+
+	if err := c.CreateNetwork(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"srl1": "br-docker",
+		"l1":   "br-podman",
+		"l2":   "br-podman",
+	}
+
+	for name, br := range want {
+		if got := c.Nodes[name].Config().Labels[labels.NodeMgmtNetBr]; got != br {
+			t.Errorf("got mgmt bridge label %q of node %q, want %q", got, name, br)
+		}
+	}
+}
+
+func TestCreateNetworkSubnetMismatch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	docker := newNetworkTestRuntime(mockCtrl, "br-docker", "172.20.20.0/24")
+	podman := newNetworkTestRuntime(mockCtrl, "br-podman", "10.88.0.0/16")
+
+	docker.EXPECT().CreateNet(gomock.Any()).Return(nil).Times(1)
+	podman.EXPECT().CreateNet(gomock.Any()).Return(nil).Times(1)
+
+	c := &CLab{
+		globalRuntime: "docker",
+		Runtimes: map[string]runtime.ContainerRuntime{
+			"docker": docker,
+			"podman": podman,
+		},
+		Nodes: map[string]nodes.Node{
+			"srl1": newNetworkTestNode(mockCtrl, "srl1", docker),
+			"l1":   newNetworkTestNode(mockCtrl, "l1", podman),
+		},
+	}
+
+	err := c.CreateNetwork(context.Background())
+	if err == nil {
+		t.Fatal("no error for the mgmt networks with different subnets")
+	}
+
+	want := `management network "clab" of the podman runtime has subnets "10.88.0.0/16", ""`
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	errs "github.com/srl-labs/containerlab/errors"
+	"github.com/srl-labs/containerlab/types"
+	"golang.org/x/exp/slices"
+)
+
+// the node settings which can be overridden.
+const (
+	overrideImage      = "image"
+	overrideCmd        = "cmd"
+	overrideEntrypoint = "entrypoint"
+	overrideEnv        = "env"
+)
+
+// NodeOverride is a node setting overridden at deploy time without editing the topology file.
+type NodeOverride struct {
+	Node string
+	// Setting is one of image, cmd, entrypoint or env
+	Setting string
+	// EnvVar is the name of the environment variable set by the env override
+	EnvVar string
+	Value  string
+}
+
+// ParseNodeOverride parses the node override in the <node>.<setting>=<value> format,
+// where the setting is one of image, cmd or entrypoint. An environment variable
+// is overridden with <node>.env.<var>=<value>. The node name may contain dots.
+func ParseNodeOverride(s string) (*NodeOverride, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return nil, fmt.Errorf("%w: node override %q is not in the <node>.<setting>=<value> format",
+			errs.ErrIncorrectInput, s)
+	}
+
+	if i := strings.LastIndex(key, "."+overrideEnv+"."); i > 0 {
+		envVar := key[i+len(overrideEnv)+2:]
+		if envVar == "" {
+			return nil, fmt.Errorf("%w: node override %q has no environment variable name", errs.ErrIncorrectInput, s)
+		}
+
+		return &NodeOverride{Node: key[:i], Setting: overrideEnv, EnvVar: envVar, Value: value}, nil
+	}
+
+	for _, setting := range []string{overrideImage, overrideCmd, overrideEntrypoint} {
+		if node, found := strings.CutSuffix(key, "."+setting); found && node != "" {
+			return &NodeOverride{Node: node, Setting: setting, Value: value}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: node override %q sets an unsupported setting, one of image, cmd, entrypoint "+
+		"or env.<var> is supported", errs.ErrIncorrectInput, s)
+}
+
+// String returns the override in the format it is parsed from.
+func (o *NodeOverride) String() string {
+	if o.Setting == overrideEnv {
+		return fmt.Sprintf("%s.%s.%s=%s", o.Node, o.Setting, o.EnvVar, o.Value)
+	}
+
+	return fmt.Sprintf("%s.%s=%s", o.Node, o.Setting, o.Value)
+}
+
+// WithNodeOverrides option overrides the node settings defined in the topology file.
+// Since the overrides are applied to the nodes of the topology, it must be called after WithTopoPath.
+func WithNodeOverrides(overrides []*NodeOverride) ClabOption {
+	return func(c *CLab) error {
+		return c.applyNodeDefinitionOverrides(overrides)
+	}
+}
+
+// applyNodeDefinitionOverrides applies the image and env overrides to the node definitions,
+// so that they are taken into account by the node kinds when the nodes are initialized.
+// The cmd and entrypoint overrides are applied after the nodes are initialized,
+// since many kinds set their own command on init.
+func (c *CLab) applyNodeDefinitionOverrides(overrides []*NodeOverride) error {
+	for _, o := range overrides {
+		def, ok := c.Config.Topology.Nodes[o.Node]
+		if !ok && len(c.nodeFilter) > 0 && !slices.Contains(c.nodeFilter, o.Node) {
+			log.Debugf("Skipping the override %q of the node excluded by the node filter", o)
+			continue
+		}
+
+		if !ok {
+			return fmt.Errorf("%w: node %q of the override %q is not present in the topology",
+				errs.ErrIncorrectInput, o.Node, o)
+		}
+
+		// the node without any settings has no definition
+		if def == nil {
+			def = &types.NodeDefinition{}
+			c.Config.Topology.Nodes[o.Node] = def
+		}
+
+		log.Infof("Overriding the node %q setting: %s", o.Node, o)
+
+		switch o.Setting {
+		case overrideImage:
+			def.Image = o.Value
+		case overrideEnv:
+			if def.Env == nil {
+				def.Env = map[string]string{}
+			}
+
+			def.Env[o.EnvVar] = o.Value
+		}
+
+		c.nodeOverrides = append(c.nodeOverrides, o)
+	}
+
+	return nil
+}
+
+// applyNodeConfigOverrides applies the cmd and entrypoint overrides to the initialized node.
+func (c *CLab) applyNodeConfigOverrides(cfg *types.NodeConfig) {
+	for _, o := range c.nodeOverrides {
+		if o.Node != cfg.ShortName {
+			continue
+		}
+
+		switch o.Setting {
+		case overrideCmd:
+			cfg.Cmd = o.Value
+		case overrideEntrypoint:
+			cfg.Entrypoint = o.Value
+		}
+	}
+}
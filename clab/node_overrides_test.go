@@ -0,0 +1,126 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	errs "github.com/srl-labs/containerlab/errors"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestParseNodeOverride(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    *NodeOverride
+		wantErr bool
+	}{
+		"cmd": {
+			in:   "r1.cmd=sleep infinity",
+			want: &NodeOverride{Node: "r1", Setting: "cmd", Value: "sleep infinity"},
+		},
+		"entrypoint": {
+			in:   "r1.entrypoint=/bin/sh",
+			want: &NodeOverride{Node: "r1", Setting: "entrypoint", Value: "/bin/sh"},
+		},
+		"image with a dotted node name": {
+			in:   "leaf.1.image=alpine:3.18",
+			want: &NodeOverride{Node: "leaf.1", Setting: "image", Value: "alpine:3.18"},
+		},
+		"env with = in the value": {
+			in:   "r1.env.OPTS=a=b",
+			want: &NodeOverride{Node: "r1", Setting: "env", EnvVar: "OPTS", Value: "a=b"},
+		},
+		"empty value": {
+			in:   "r1.cmd=",
+			want: &NodeOverride{Node: "r1", Setting: "cmd"},
+		},
+		"no value":         {in: "r1.cmd", wantErr: true},
+		"unknown setting":  {in: "r1.memory=1Gb", wantErr: true},
+		"no node":          {in: ".cmd=sh", wantErr: true},
+		"no env var name":  {in: "r1.env.=1", wantErr: true},
+		"env without name": {in: "r1.env=FOO=1", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseNodeOverride(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, errs.ErrIncorrectInput) {
+					t.Errorf("got error %v, want incorrect input error", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("override mismatch (-want +got):\n%s", d)
+			}
+
+			if got.String() != tt.in {
+				t.Errorf("got string %q, want %q", got.String(), tt.in)
+			}
+		})
+	}
+}
+
+func TestApplyNodeOverrides(t *testing.T) {
+	c := &CLab{Config: &Config{Topology: &types.Topology{
+		Nodes: map[string]*types.NodeDefinition{
+			"r1": {Kind: "linux", Image: "alpine:3", Env: map[string]string{"KEEP": "1"}},
+			"r2": nil,
+		},
+	}}}
+
+	var overrides []*NodeOverride
+	for _, s := range []string{"r1.image=alpine:edge", "r1.env.DEBUG=1", "r1.cmd=sleep infinity", "r2.env.A=b"} {
+		o, err := ParseNodeOverride(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		overrides = append(overrides, o)
+	}
+
+	if err := c.applyNodeDefinitionOverrides(overrides); err != nil {
+		t.Fatal(err)
+	}
+
+	wantR1 := &types.NodeDefinition{Kind: "linux", Image: "alpine:edge", Env: map[string]string{"KEEP": "1", "DEBUG": "1"}}
+	if d := cmp.Diff(wantR1, c.Config.Topology.Nodes["r1"]); d != "" {
+		t.Errorf("r1 definition mismatch (-want +got):\n%s", d)
+	}
+
+	wantR2 := &types.NodeDefinition{Env: map[string]string{"A": "b"}}
+	if d := cmp.Diff(wantR2, c.Config.Topology.Nodes["r2"]); d != "" {
+		t.Errorf("r2 definition mismatch (-want +got):\n%s", d)
+	}
+
+	// the cmd set by the kind on init is overridden
+	cfg := &types.NodeConfig{ShortName: "r1", Cmd: "kind command", Entrypoint: "/entrypoint.sh"}
+	c.applyNodeConfigOverrides(cfg)
+
+	if cfg.Cmd != "sleep infinity" || cfg.Entrypoint != "/entrypoint.sh" {
+		t.Errorf("got cmd %q and entrypoint %q after the overrides", cfg.Cmd, cfg.Entrypoint)
+	}
+
+	err := c.applyNodeDefinitionOverrides([]*NodeOverride{{Node: "r3", Setting: "cmd"}})
+	if !errors.Is(err, errs.ErrIncorrectInput) {
+		t.Errorf("got error %v for the unknown node, want incorrect input error", err)
+	}
+
+	// the overrides of the nodes excluded by the node filter are skipped
+	c.nodeFilter = []string{"r1"}
+
+	if err := c.applyNodeDefinitionOverrides([]*NodeOverride{{Node: "r3", Setting: "cmd"}}); err != nil {
+		t.Errorf("got error %v for the node excluded by the node filter", err)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// The levels of the topology a node setting is supplied by, from the lowest to the highest precedence.
+const (
+	ProvenanceDefault = "default"
+	ProvenanceKind    = "kind"
+	ProvenanceNode    = "node"
+	// ProvenanceCLI is the level of the settings overridden at deploy time with the --set flag.
+	ProvenanceCLI = "cli"
+)
+
+// Provenance maps the node settings, named as they are written in the topology file,
+// to the level of the topology that supplied them. The entries of the env, labels and sysctls
+// maps are merged across the levels, so they are reported separately as <setting>.<key>.
+// The settings the node kind sets on init are not reported.
+type Provenance map[string]string
+
+// mergedSettings are the map settings of the node definition merged across the topology levels,
+// keyed by the setting name.
+var mergedSettings = map[string]func(*types.NodeDefinition) map[string]string{
+	"env":     func(d *types.NodeDefinition) map[string]string { return d.Env },
+	"labels":  func(d *types.NodeDefinition) map[string]string { return d.Labels },
+	"sysctls": func(d *types.NodeDefinition) map[string]string { return d.Sysctls },
+}
+
+// WithResolveOnly makes the lab initialize the nodes without side effects, i.e. no container runtimes
+// are initialized and the embedded and downloadable startup configs are neither written nor downloaded.
+// Such lab is used to resolve the node configs and can't be deployed.
+func WithResolveOnly() ClabOption {
+	return func(c *CLab) error {
+		c.resolveOnly = true
+		return nil
+	}
+}
+
+// ResolveNodeConfig returns the effective config of the node of the topology, i.e. the config with the
+// defaults, kind and node settings inherited and the node initialized by its kind the same way it is on deploy,
+// along with the provenance of the settings. The node is resolved without side effects
+// and the relative paths of the topology are resolved relative to the current directory.
+func ResolveNodeConfig(topo Config, nodeName string) (*types.NodeConfig, Provenance, error) {
+	c, err := NewContainerLab(WithResolveOnly())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.Config = &topo
+	if c.Config.Mgmt == nil {
+		c.Config.Mgmt = new(types.MgmtNet)
+	}
+
+	if c.Config.Topology == nil {
+		c.Config.Topology = types.NewTopology()
+	}
+
+	if err := c.initMgmtNetwork(); err != nil {
+		return nil, nil, err
+	}
+
+	c.TopoPaths = new(types.TopoPaths)
+
+	if err := c.parseTopology(); err != nil {
+		return nil, nil, err
+	}
+
+	return c.ResolveNodeConfig(nodeName)
+}
+
+// ResolveNodeConfig returns the effective config of the node of the lab and the provenance of its settings.
+// The disabled nodes are resolved as well.
+func (c *CLab) ResolveNodeConfig(nodeName string) (*types.NodeConfig, Provenance, error) {
+	n, ok := c.Nodes[nodeName]
+	if !ok {
+		n, ok = c.DisabledNodes[nodeName]
+	}
+
+	if !ok {
+		return nil, nil, fmt.Errorf("node %q is not present in the topology", nodeName)
+	}
+
+	return n.Config(), c.nodeProvenance(nodeName), nil
+}
+
+// nodeProvenance returns the levels of the topology that supplied the settings of the node.
+func (c *CLab) nodeProvenance(nodeName string) Provenance {
+	t := c.Config.Topology
+	p := Provenance{}
+
+	levels := []struct {
+		name string
+		def  *types.NodeDefinition
+	}{
+		{ProvenanceDefault, t.GetDefaults()},
+		{ProvenanceKind, t.GetKind(t.GetNodeKind(nodeName))},
+		{ProvenanceNode, t.Nodes[nodeName]},
+	}
+
+	// the settings of the higher levels override the ones of the lower levels
+	for _, l := range levels {
+		if l.def.GetKind() != "" {
+			p["kind"] = l.name
+		}
+
+		for _, f := range l.def.SetFields() {
+			if entries, ok := mergedSettings[f]; ok {
+				for k := range entries(l.def) {
+					p[f+"."+k] = l.name
+				}
+
+				continue
+			}
+
+			p[f] = l.name
+		}
+
+		// the exec entries are read from the topology file by the custom unmarshaler
+		if l.def != nil && (len(l.def.Exec) > 0 || len(l.def.ExecPhases) > 0) {
+			p["exec"] = l.name
+		}
+	}
+
+	for _, o := range c.nodeOverrides {
+		if o.Node != nodeName {
+			continue
+		}
+
+		if o.Setting == overrideEnv {
+			p[overrideEnv+"."+o.EnvVar] = ProvenanceCLI
+			continue
+		}
+
+		p[o.Setting] = ProvenanceCLI
+	}
+
+	return p
+}
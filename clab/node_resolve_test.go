@@ -0,0 +1,138 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestResolveNodeConfigMatchesDeploy(t *testing.T) {
+	topos := []string{
+		"../lab-examples/srl01/srl01.clab.yml",
+		"../lab-examples/clos01/clos01.clab.yml",
+		"test_data/topo1.yml",
+		"test_data/topo3.yml",
+		"test_data/topo24-env-precedence.yml",
+	}
+
+	for _, topo := range topos {
+		t.Run(topo, func(t *testing.T) {
+			// the nodes initialized the way they are on deploy
+			deployed, err := NewContainerLab(WithTopoPath(topo, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resolver, err := NewContainerLab(WithResolveOnly(), WithTopoPath(topo, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, n := range deployed.Nodes {
+				got, _, err := resolver.ResolveNodeConfig(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want, _ := json.Marshal(n.Config())
+				gotJSON, _ := json.Marshal(got)
+
+				if d := cmp.Diff(string(want), string(gotJSON)); d != "" {
+					t.Errorf("node %q config mismatch (-deploy +resolved):\n%s", name, d)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveNodeConfig(t *testing.T) {
+	topo := Config{
+		Name: "resolve",
+		Topology: &types.Topology{
+			Defaults: &types.NodeDefinition{
+				Kind: "linux",
+				Env:  map[string]string{"A": "default", "B": "default"},
+			},
+			Kinds: map[string]*types.NodeDefinition{
+				"linux": {Image: "alpine:3", Env: map[string]string{"B": "kind"}},
+			},
+			Nodes: map[string]*types.NodeDefinition{
+				"n1": {Env: map[string]string{"C": "node"}, Labels: map[string]string{"role": "client"}},
+				"n2": {Image: "alpine:edge", Enabled: new(bool)},
+			},
+		},
+	}
+
+	cfg, p, err := ResolveNodeConfig(topo, "n1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Image != "alpine:3" || cfg.Env["A"] != "default" || cfg.Env["B"] != "kind" || cfg.Env["C"] != "node" {
+		t.Errorf("got image %q and env %v", cfg.Image, cfg.Env)
+	}
+
+	wantP := Provenance{
+		"kind":        ProvenanceDefault,
+		"image":       ProvenanceKind,
+		"env.A":       ProvenanceDefault,
+		"env.B":       ProvenanceKind,
+		"env.C":       ProvenanceNode,
+		"labels.role": ProvenanceNode,
+	}
+	if d := cmp.Diff(wantP, p); d != "" {
+		t.Errorf("provenance mismatch (-want +got):\n%s", d)
+	}
+
+	// the disabled nodes are resolved as well
+	cfg, p, err = ResolveNodeConfig(topo, "n2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Image != "alpine:edge" || p["image"] != ProvenanceNode {
+		t.Errorf("got image %q supplied by %q", cfg.Image, p["image"])
+	}
+
+	if _, _, err := ResolveNodeConfig(topo, "n3"); err == nil {
+		t.Error("unknown node is resolved")
+	}
+}
+
+func TestNodeProvenanceOverrides(t *testing.T) {
+	c := &CLab{Config: &Config{Topology: &types.Topology{
+		Nodes: map[string]*types.NodeDefinition{
+			"n1": {Kind: "linux", Image: "alpine:3", Cmd: "sleep 1"},
+		},
+	}}}
+
+	var overrides []*NodeOverride
+	for _, s := range []string{"n1.image=alpine:edge", "n1.env.DEBUG=1", "n1.cmd=sleep infinity"} {
+		o, err := ParseNodeOverride(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		overrides = append(overrides, o)
+	}
+
+	if err := c.applyNodeDefinitionOverrides(overrides); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Provenance{
+		"kind":      ProvenanceNode,
+		"image":     ProvenanceCLI,
+		"cmd":       ProvenanceCLI,
+		"env.DEBUG": ProvenanceCLI,
+	}
+	if d := cmp.Diff(want, c.nodeProvenance("n1")); d != "" {
+		t.Errorf("provenance mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NodeTimings are the durations of the deployment phases of a lab node.
+// The durations of the phases the node didn't get through are empty.
+type NodeTimings struct {
+	Name string `json:"name"`
+	// PreDeploy is the duration of the node pre-deploy phase.
+	PreDeploy string `json:"pre-deploy,omitempty"`
+	// Deploy is the time it took to create and start the node container.
+	Deploy string `json:"deploy,omitempty"`
+	// StartupWait is the time the node waited to meet its startup-wait condition.
+	StartupWait string `json:"startup-wait,omitempty"`
+	// PostDeploy is the duration of the node post-deploy phase.
+	PostDeploy string `json:"post-deploy,omitempty"`
+	// TimeToHealthy is the time from the first phase of the node until it became healthy.
+	TimeToHealthy string `json:"time-to-healthy,omitempty"`
+	// Total is the time from the first phase of the node until the last one it entered.
+	Total string `json:"total"`
+
+	// total ranks the nodes by their deployment time
+	total time.Duration
+}
+
+// nodeTimings records the times the lab nodes enter their deployment phases.
+type nodeTimings struct {
+	mu     sync.Mutex
+	phases map[string]map[NodePhase]time.Time
+}
+
+func newNodeTimings() *nodeTimings {
+	return &nodeTimings{phases: map[string]map[NodePhase]time.Time{}}
+}
+
+// record records the time the node entered the phase, the phase entered again keeps its first time.
+func (t *nodeTimings) record(node string, phase NodePhase, at time.Time) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.phases[node]
+	if !ok {
+		p = map[NodePhase]time.Time{}
+		t.phases[node] = p
+	}
+
+	if _, ok := p[phase]; !ok {
+		p[phase] = at
+	}
+}
+
+// get returns the phase durations of the nodes ordered by the node name.
+func (t *nodeTimings) get() []*NodeTimings {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	res := make([]*NodeTimings, 0, len(t.phases))
+
+	for node, p := range t.phases {
+		res = append(res, phaseDurations(node, p))
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+// phaseDurations computes the phase durations of the node out of the times it entered the phases.
+func phaseDurations(node string, p map[NodePhase]time.Time) *NodeTimings {
+	nt := &NodeTimings{Name: node}
+
+	var first, last time.Time
+	for _, at := range p {
+		if first.IsZero() || at.Before(first) {
+			first = at
+		}
+
+		if at.After(last) {
+			last = at
+		}
+	}
+
+	// span returns the duration between the phases, empty if the node didn't enter any of them
+	span := func(from, to time.Time) string {
+		if from.IsZero() || to.IsZero() {
+			return ""
+		}
+
+		return to.Sub(from).Round(time.Millisecond).String()
+	}
+
+	// the container is created and started once the node waits for its startup condition or is created
+	deployed := p[NodePhaseStartupWait]
+	if deployed.IsZero() {
+		deployed = p[NodePhaseCreated]
+	}
+
+	nt.PreDeploy = span(p[NodePhasePreDeploy], p[NodePhaseDeploying])
+	nt.Deploy = span(p[NodePhaseDeploying], deployed)
+	nt.StartupWait = span(p[NodePhaseStartupWait], p[NodePhaseCreated])
+	nt.PostDeploy = span(p[NodePhasePostDeploy], p[NodePhaseHealthy])
+	nt.TimeToHealthy = span(first, p[NodePhaseHealthy])
+
+	nt.total = last.Sub(first)
+	nt.Total = nt.total.Round(time.Millisecond).String()
+
+	return nt
+}
+
+// NodeTimings returns the durations of the deployment phases of the lab nodes ordered by the node name.
+func (c *CLab) NodeTimings() []*NodeTimings {
+	return c.nodeTimings.get()
+}
+
+// SlowestNodes returns up to n nodes which took the longest to deploy, the slowest node first.
+func SlowestNodes(timings []*NodeTimings, n int) []*NodeTimings {
+	sorted := make([]*NodeTimings, len(timings))
+	copy(sorted, timings)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].total == sorted[j].total {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].total > sorted[j].total
+	})
+
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	return sorted
+}
+
+// DeployedNodeTimings returns the phase durations of the lab nodes recorded in the lab metadata file
+// by the lab deployment, keyed by the node name.
+func (c *CLab) DeployedNodeTimings() (map[string]*NodeTimings, error) {
+	md, err := c.readLabMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the lab metadata, is the lab %q deployed? %w", c.Config.Name, err)
+	}
+
+	timings := make(map[string]*NodeTimings, len(md.Timings))
+	for _, t := range md.Timings {
+		timings[t.Name] = t
+	}
+
+	return timings, nil
+}
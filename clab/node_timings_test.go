@@ -0,0 +1,99 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNodeTimings(t *testing.T) {
+	start := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	nt := newNodeTimings()
+
+	// srl1 waits for its startup condition and gets healthy
+	nt.record("srl1", NodePhasePreDeploy, at(0))
+	nt.record("srl1", NodePhaseDeploying, at(500*time.Millisecond))
+	nt.record("srl1", NodePhaseStartupWait, at(2*time.Second))
+	nt.record("srl1", NodePhaseCreated, at(32*time.Second))
+	nt.record("srl1", NodePhasePostDeploy, at(40*time.Second))
+	nt.record("srl1", NodePhaseHealthy, at(100*time.Second))
+	// the phase entered again keeps its first time
+	nt.record("srl1", NodePhasePreDeploy, at(time.Second))
+
+	// linux1 waits for srl1 and fails to create its container
+	nt.record("linux1", NodePhaseWaitingDeps, at(0))
+	nt.record("linux1", NodePhasePreDeploy, at(32*time.Second))
+	nt.record("linux1", NodePhaseDeploying, at(33*time.Second))
+	nt.record("linux1", NodePhaseFailed, at(35*time.Second))
+
+	want := []*NodeTimings{
+		{
+			Name:      "linux1",
+			PreDeploy: "1s",
+			Total:     "35s",
+		},
+		{
+			Name:          "srl1",
+			PreDeploy:     "500ms",
+			Deploy:        "1.5s",
+			StartupWait:   "30s",
+			PostDeploy:    "1m0s",
+			TimeToHealthy: "1m40s",
+			Total:         "1m40s",
+		},
+	}
+
+	if d := cmp.Diff(want, nt.get(), cmpopts.IgnoreUnexported(NodeTimings{})); d != "" {
+		t.Errorf("timings diff (-want +got):\n%s", d)
+	}
+}
+
+func TestSlowestNodes(t *testing.T) {
+	timings := []*NodeTimings{
+		{Name: "n1", total: 10 * time.Second},
+		{Name: "n2", total: 90 * time.Second},
+		{Name: "n3", total: 30 * time.Second},
+		{Name: "n4", total: 30 * time.Second},
+		{Name: "n5", total: time.Second},
+	}
+
+	tests := map[string]struct {
+		n    int
+		want []string
+	}{
+		"top 3": {
+			n:    3,
+			want: []string{"n2", "n3", "n4"},
+		},
+		"more than nodes": {
+			n:    10,
+			want: []string{"n2", "n3", "n4", "n1", "n5"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, nt := range SlowestNodes(timings, tt.n) {
+				got = append(got, nt.Name)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("slowest nodes diff (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	// the given timings keep their order
+	if timings[0].Name != "n1" {
+		t.Errorf("the timings were reordered, got %s first", timings[0].Name)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// CheckOOMKilled inspects the containers of the lab nodes and returns the errors
+// for the nodes which containers were killed by the OOM killer, keyed by the node name.
+// The containers which failed to be inspected are logged and skipped.
+func (c *CLab) CheckOOMKilled(ctx context.Context) map[string]error {
+	errs := map[string]error{}
+	mu := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+
+	wg.Add(len(c.Nodes))
+
+	for _, n := range c.Nodes {
+		go func(n nodes.Node) {
+			defer wg.Done()
+
+			cfg := n.Config()
+
+			killed, err := n.GetRuntime().IsContainerOOMKilled(ctx, cfg.LongName)
+			if err != nil {
+				log.Debugf("failed to check the OOM kill of node %s: %v", cfg.ShortName, err)
+				return
+			}
+
+			if !killed {
+				return
+			}
+
+			mu.Lock()
+			errs[cfg.ShortName] = oomKilledError(cfg)
+			mu.Unlock()
+		}(n)
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// oomKilledError returns the error naming the OOM killed node and its memory settings.
+func oomKilledError(cfg *types.NodeConfig) error {
+	memory := cfg.Memory
+	if memory == "" {
+		memory = "unlimited"
+	}
+
+	swap := cfg.MemorySwap
+	if swap == "" {
+		swap = "runtime default"
+	}
+
+	return fmt.Errorf("node %q was killed by the OOM killer (memory: %s, memory-swap: %s), "+
+		"the node may have restarted or stopped. Increase the node memory limit", cfg.ShortName, memory, swap)
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestCheckOOMKilled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cfgs := map[string]*types.NodeConfig{
+		"sros":   {ShortName: "sros", LongName: "clab-test-sros", Memory: "4GiB", MemorySwap: "4GiB"},
+		"client": {ShortName: "client", LongName: "clab-test-client"},
+		"srl":    {ShortName: "srl", LongName: "clab-test-srl", Memory: "2GiB"},
+		"gone":   {ShortName: "gone", LongName: "clab-test-gone"},
+	}
+
+	killed := map[string]bool{"clab-test-sros": true, "clab-test-client": true}
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().IsContainerOOMKilled(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, cID string) (bool, error) {
+			if cID == "clab-test-gone" {
+				return false, errors.New("no such container")
+			}
+			return killed[cID], nil
+		}).Times(len(cfgs))
+
+	c := &CLab{Nodes: map[string]nodes.Node{}}
+
+	for name, cfg := range cfgs {
+		mn := mocknodes.NewMockNode(ctrl)
+		mn.EXPECT().Config().Return(cfg).AnyTimes()
+		mn.EXPECT().GetRuntime().Return(rt).AnyTimes()
+
+		c.Nodes[name] = mn
+	}
+
+	errs := c.CheckOOMKilled(context.Background())
+
+	if len(errs) != 2 {
+		t.Fatalf("got %d OOM killed nodes, want 2: %v", len(errs), errs)
+	}
+
+	wantMsgs := map[string]string{
+		"sros":   "(memory: 4GiB, memory-swap: 4GiB)",
+		"client": "(memory: unlimited, memory-swap: runtime default)",
+	}
+
+	for name, want := range wantMsgs {
+		err, ok := errs[name]
+		if !ok {
+			t.Errorf("node %s is not reported as OOM killed", name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("node %s error %q doesn't contain %q", name, err, want)
+		}
+	}
+}
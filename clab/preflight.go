@@ -0,0 +1,295 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// PreflightFinding is a problem found by the preflight checks that are run before the lab is deployed.
+type PreflightFinding struct {
+	// Check is the name of the check that produced the finding.
+	Check string
+	// Problem describes what was found.
+	Problem string
+	// Suggestion is a command a user can run to resolve the finding manually.
+	Suggestion string
+	// safe is set for the findings which resolution removes nothing of a running lab,
+	// such findings are resolved without a user asking for it.
+	safe bool
+	// resolve resolves the finding using the containerlab cleanup functions.
+	resolve func(ctx context.Context) error
+}
+
+// Resolve resolves the finding.
+func (f *PreflightFinding) Resolve(ctx context.Context) error {
+	if f.resolve == nil {
+		log.Warnf("Preflight finding %q can't be resolved automatically: %s, suggestion: %s",
+			f.Check, f.Problem, f.Suggestion)
+
+		return nil
+	}
+
+	log.Infof("Resolving preflight finding %q: %s", f.Check, f.Problem)
+
+	return f.resolve(ctx)
+}
+
+// Preflight runs the checks for the leftovers of the previous deployments of a lab with the same name
+// and for the kernel modules needed by the lab links that would make the deployment fail halfway through.
+// The checks are run in the order the findings need to be resolved, i.e. containers are removed
+// before the management network they are attached to.
+func (c *CLab) Preflight(ctx context.Context) ([]*PreflightFinding, error) {
+	checks := []func(context.Context) ([]*PreflightFinding, error){
+		c.preflightLabContainers,
+		c.preflightNetnsSymlinks,
+		c.preflightMgmtNetwork,
+		c.preflightLabDir,
+		c.preflightKernelModules,
+	}
+
+	var findings []*PreflightFinding
+
+	for _, check := range checks {
+		f, err := check(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, f...)
+	}
+
+	return findings, nil
+}
+
+// ResolvePreflightFindings resolves the findings one by one in the order they were reported.
+func ResolvePreflightFindings(ctx context.Context, findings []*PreflightFinding) error {
+	var errs []error
+
+	for _, f := range findings {
+		if err := f.Resolve(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Check, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ResolveSafePreflightFindings resolves the findings that are safe to resolve
+// and returns the remaining ones.
+func ResolveSafePreflightFindings(ctx context.Context, findings []*PreflightFinding) ([]*PreflightFinding, error) {
+	var safe, remaining []*PreflightFinding
+
+	for _, f := range findings {
+		if f.safe {
+			safe = append(safe, f)
+			continue
+		}
+
+		remaining = append(remaining, f)
+	}
+
+	return remaining, ResolvePreflightFindings(ctx, safe)
+}
+
+// PreflightReport returns a consolidated report of the preflight findings with the suggested commands.
+func PreflightReport(findings []*PreflightFinding) string {
+	sb := &strings.Builder{}
+	sb.WriteString("preflight problems:\n")
+
+	for _, f := range findings {
+		fmt.Fprintf(sb, "  - check: %s\n", f.Check)
+		fmt.Fprintf(sb, "    problem: %s\n", f.Problem)
+		fmt.Fprintf(sb, "    suggestion: %s\n", f.Suggestion)
+	}
+
+	return sb.String()
+}
+
+// preflightLabContainers finds running or stopped containers labelled with the lab name
+// which names collide with the containers of the nodes selected for the deployment.
+// The containers of the nodes excluded by the node filter are left alone,
+// since they are part of the running lab the selected nodes are deployed into.
+func (c *CLab) preflightLabContainers(ctx context.Context) ([]*PreflightFinding, error) {
+	filter := []*types.GenericFilter{{
+		FilterType: "label", Match: c.Config.Name,
+		Field: labels.Containerlab, Operator: "=",
+	}}
+
+	// container names of the nodes being deployed
+	nodeContainers := make(map[string]struct{}, len(c.Nodes))
+	for _, n := range c.Nodes {
+		nodeContainers[n.Config().LongName] = struct{}{}
+	}
+
+	// sort runtime names to get a stable order of the findings
+	rtNames := make([]string, 0, len(c.Runtimes))
+	for n := range c.Runtimes {
+		rtNames = append(rtNames, n)
+	}
+	sort.Strings(rtNames)
+
+	var findings []*PreflightFinding
+
+	for _, rtName := range rtNames {
+		r := c.Runtimes[rtName]
+
+		containers, err := r.ListContainers(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		for _, cnt := range containers {
+			name := strings.TrimPrefix(cnt.Names[0], "/")
+			if _, ok := nodeContainers[name]; ok {
+				names = append(names, name)
+			}
+		}
+
+		if len(names) == 0 {
+			continue
+		}
+
+		sort.Strings(names)
+
+		findings = append(findings, &PreflightFinding{
+			Check:      "lab-containers",
+			Problem:    fmt.Sprintf("containers %q of the %q lab already exist", names, c.Config.Name),
+			Suggestion: fmt.Sprintf("containerlab destroy -t %s or %s rm -f %s", c.TopoPaths.TopologyFilenameAbsPath(), rtName, strings.Join(names, " ")),
+			resolve: func(ctx context.Context) error {
+				var errs []error
+				for _, n := range names {
+					if err := r.DeleteContainer(ctx, n); err != nil {
+						errs = append(errs, err)
+					}
+				}
+
+				return errors.Join(errs...)
+			},
+		})
+	}
+
+	return findings, nil
+}
+
+// preflightMgmtNetwork finds a containerlab-created management network
+// whose subnets or MTU differ from the ones defined in the topology.
+// Only the network created by this lab is removed by the resolution, the networks
+// shared with other labs, e.g. the default clab network, are reported and left alone.
+func (c *CLab) preflightMgmtNetwork(ctx context.Context) ([]*PreflightFinding, error) {
+	r := c.GlobalRuntime()
+
+	netInfo, err := r.InspectMgmtNet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// networks that were not created by containerlab are never reported
+	if netInfo == nil {
+		return nil, nil
+	}
+
+	if _, ok := netInfo.Labels[labels.Containerlab]; !ok {
+		return nil, nil
+	}
+
+	mgmt := c.Config.Mgmt
+
+	var diffs []string
+
+	if mgmt.IPv4Subnet != "" && netInfo.IPv4Subnet != "" && mgmt.IPv4Subnet != netInfo.IPv4Subnet {
+		diffs = append(diffs, fmt.Sprintf("ipv4-subnet %s != %s", netInfo.IPv4Subnet, mgmt.IPv4Subnet))
+	}
+
+	if mgmt.IPv6Subnet != "" && netInfo.IPv6Subnet != "" && mgmt.IPv6Subnet != netInfo.IPv6Subnet {
+		diffs = append(diffs, fmt.Sprintf("ipv6-subnet %s != %s", netInfo.IPv6Subnet, mgmt.IPv6Subnet))
+	}
+
+	if mgmt.MTU != 0 && netInfo.MTU != 0 && mgmt.MTU != netInfo.MTU {
+		diffs = append(diffs, fmt.Sprintf("mtu %d != %d", netInfo.MTU, mgmt.MTU))
+	}
+
+	if len(diffs) == 0 {
+		return nil, nil
+	}
+
+	f := &PreflightFinding{
+		Check: "mgmt-network",
+		Problem: fmt.Sprintf("existing management network %q differs from the topology: %s",
+			netInfo.Name, strings.Join(diffs, ", ")),
+		Suggestion: fmt.Sprintf("align the mgmt section of the topology with the %q network", netInfo.Name),
+	}
+
+	if netInfo.Labels[labels.LabName] == c.Config.Name {
+		f.Suggestion = fmt.Sprintf("%s network rm %s", r.GetName(), netInfo.Name)
+		f.resolve = r.DeleteNet
+	}
+
+	return []*PreflightFinding{f}, nil
+}
+
+// preflightLabDir finds an existing lab directory that doesn't contain the topology data file
+// or the dumped container specs and therefore was not created by a containerlab deployment of this lab.
+func (c *CLab) preflightLabDir(_ context.Context) ([]*PreflightFinding, error) {
+	labDir := c.TopoPaths.TopologyLabDir()
+
+	fi, err := os.Stat(labDir)
+	if err != nil || !fi.IsDir() || utils.FileExists(c.TopoPaths.TopoExportFile()) ||
+		utils.FileExists(c.TopoPaths.TopoExportMsgpackFile()) {
+		return nil, nil
+	}
+
+	// the deployment with the dumped container specs writes no topology data file
+	if hasContainerSpecs(labDir) {
+		return nil, nil
+	}
+
+	return []*PreflightFinding{{
+		Check:      "lab-dir",
+		Problem:    fmt.Sprintf("lab directory %s exists, but has no topology data file and is of unknown origin", labDir),
+		Suggestion: fmt.Sprintf("rm -rf %s", labDir),
+		resolve: func(_ context.Context) error {
+			return os.RemoveAll(labDir)
+		},
+	}}, nil
+}
+
+// preflightNetnsSymlinks finds netns symlinks of the lab nodes which containers no longer exist.
+// The stale symlinks would collide with the new ones, their removal is safe.
+func (c *CLab) preflightNetnsSymlinks(ctx context.Context) ([]*PreflightFinding, error) {
+	stale, err := c.staleNetnsSymlinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*PreflightFinding, 0, len(stale))
+
+	for _, name := range stale {
+		name := name
+		findings = append(findings, &PreflightFinding{
+			Check:      "netns-symlink",
+			Problem:    fmt.Sprintf("stale netns symlink %s exists", filepath.Join(utils.NetnsDir, name)),
+			Suggestion: fmt.Sprintf("rm %s", filepath.Join(utils.NetnsDir, name)),
+			safe:       true,
+			resolve: func(_ context.Context) error {
+				return utils.DeleteNetnsSymlink(name)
+			},
+		})
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,405 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// newPreflightTestLab returns a CLab instance with a mocked runtime and a lab directory in a temp dir.
+func newPreflightTestLab(t *testing.T, ctrl *gomock.Controller) (*CLab, *mockruntime.MockContainerRuntime) {
+	t.Helper()
+
+	labDir := filepath.Join(t.TempDir(), "clab-test")
+
+	tp, err := types.NewCaTopoPaths(labDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockRuntime := mockruntime.NewMockContainerRuntime(ctrl)
+	mockRuntime.EXPECT().GetName().Return("mock").AnyTimes()
+
+	c := &CLab{
+		Config: &Config{
+			Name: "test",
+			Mgmt: &types.MgmtNet{
+				Network:    "clab",
+				IPv4Subnet: "172.20.20.0/24",
+				IPv6Subnet: "2001:172:20:20::/64",
+				MTU:        1500,
+			},
+		},
+		TopoPaths:     tp,
+		Nodes:         map[string]nodes.Node{},
+		Runtimes:      map[string]runtime.ContainerRuntime{"mock": mockRuntime},
+		globalRuntime: "mock",
+	}
+
+	return c, mockRuntime
+}
+
+func TestPreflightLabContainers(t *testing.T) {
+	tests := map[string]struct {
+		containers   []runtime.GenericContainer
+		wantFindings int
+	}{
+		"no-containers": {
+			containers:   nil,
+			wantFindings: 0,
+		},
+		"leftover-containers": {
+			containers: []runtime.GenericContainer{
+				{Names: []string{"clab-test-node2"}},
+				{Names: []string{"/clab-test-node1"}},
+			},
+			wantFindings: 1,
+		},
+		// the node filter deploys into a running lab which other containers are kept
+		"containers-of-unselected-nodes": {
+			containers: []runtime.GenericContainer{
+				{Names: []string{"/clab-test-node3"}},
+			},
+			wantFindings: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c, mockRuntime := newPreflightTestLab(t, ctrl)
+			ctx := context.Background()
+
+			for _, name := range []string{"node1", "node2"} {
+				n := mocknodes.NewMockNode(ctrl)
+				n.EXPECT().Config().Return(&types.NodeConfig{LongName: "clab-test-" + name}).AnyTimes()
+				c.Nodes[name] = n
+			}
+
+			mockRuntime.EXPECT().ListContainers(gomock.Any(), gomock.Any()).Return(tt.containers, nil)
+
+			findings, err := c.preflightLabContainers(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(findings) != tt.wantFindings {
+				t.Fatalf("got %d findings, want %d", len(findings), tt.wantFindings)
+			}
+
+			if tt.wantFindings == 0 {
+				return
+			}
+
+			gomock.InOrder(
+				mockRuntime.EXPECT().DeleteContainer(gomock.Any(), "clab-test-node1").Return(nil),
+				mockRuntime.EXPECT().DeleteContainer(gomock.Any(), "clab-test-node2").Return(nil),
+			)
+
+			if err := ResolvePreflightFindings(ctx, findings); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestPreflightMgmtNetwork(t *testing.T) {
+	tests := map[string]struct {
+		netInfo      *runtime.NetworkInfo
+		wantFindings int
+		// wantDelete is set when the resolution deletes the network
+		wantDelete bool
+	}{
+		"no-network": {
+			netInfo:      nil,
+			wantFindings: 0,
+		},
+		"matching-network": {
+			netInfo: &runtime.NetworkInfo{
+				Name:       "clab",
+				IPv4Subnet: "172.20.20.0/24",
+				IPv6Subnet: "2001:172:20:20::/64",
+				MTU:        1500,
+				Labels:     map[string]string{"containerlab": ""},
+			},
+			wantFindings: 0,
+		},
+		"different-subnet": {
+			netInfo: &runtime.NetworkInfo{
+				Name:       "clab",
+				IPv4Subnet: "172.100.100.0/24",
+				IPv6Subnet: "2001:172:20:20::/64",
+				MTU:        1500,
+				Labels:     map[string]string{"containerlab": "", "clab-lab-name": "test"},
+			},
+			wantFindings: 1,
+			wantDelete:   true,
+		},
+		"different-mtu": {
+			netInfo: &runtime.NetworkInfo{
+				Name:       "clab",
+				IPv4Subnet: "172.20.20.0/24",
+				MTU:        9000,
+				Labels:     map[string]string{"containerlab": "", "clab-lab-name": "test"},
+			},
+			wantFindings: 1,
+			wantDelete:   true,
+		},
+		"different-subnet-of-other-lab": {
+			netInfo: &runtime.NetworkInfo{
+				Name:       "clab",
+				IPv4Subnet: "172.100.100.0/24",
+				Labels:     map[string]string{"containerlab": "", "clab-lab-name": "other"},
+			},
+			wantFindings: 1,
+		},
+		"different-subnet-of-shared-network": {
+			netInfo: &runtime.NetworkInfo{
+				Name:       "clab",
+				IPv4Subnet: "172.100.100.0/24",
+				Labels:     map[string]string{"containerlab": ""},
+			},
+			wantFindings: 1,
+		},
+		"foreign-network": {
+			netInfo: &runtime.NetworkInfo{
+				Name:       "clab",
+				IPv4Subnet: "10.0.0.0/24",
+			},
+			wantFindings: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c, mockRuntime := newPreflightTestLab(t, ctrl)
+			ctx := context.Background()
+
+			mockRuntime.EXPECT().InspectMgmtNet(gomock.Any()).Return(tt.netInfo, nil)
+
+			findings, err := c.preflightMgmtNetwork(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(findings) != tt.wantFindings {
+				t.Fatalf("got %d findings, want %d", len(findings), tt.wantFindings)
+			}
+
+			if tt.wantFindings == 0 {
+				return
+			}
+
+			// the network is never deleted unless it was created by this lab
+			if tt.wantDelete {
+				mockRuntime.EXPECT().DeleteNet(gomock.Any()).Return(nil)
+			}
+
+			if err := ResolvePreflightFindings(ctx, findings); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestPreflightLabDir(t *testing.T) {
+	tests := map[string]struct {
+		createDir      bool
+		createTopoData bool
+		wantFindings   int
+	}{
+		"no-lab-dir": {
+			wantFindings: 0,
+		},
+		"lab-dir-with-metadata": {
+			createDir:      true,
+			createTopoData: true,
+			wantFindings:   0,
+		},
+		"lab-dir-without-metadata": {
+			createDir:    true,
+			wantFindings: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c, _ := newPreflightTestLab(t, ctrl)
+			ctx := context.Background()
+
+			labDir := c.TopoPaths.TopologyLabDir()
+
+			if tt.createDir {
+				if err := os.MkdirAll(labDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.createTopoData {
+				if err := os.WriteFile(c.TopoPaths.TopoExportFile(), []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			findings, err := c.preflightLabDir(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(findings) != tt.wantFindings {
+				t.Fatalf("got %d findings, want %d", len(findings), tt.wantFindings)
+			}
+
+			if tt.wantFindings == 0 {
+				return
+			}
+
+			if err := ResolvePreflightFindings(ctx, findings); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := os.Stat(labDir); !os.IsNotExist(err) {
+				t.Errorf("lab directory %s was not removed", labDir)
+			}
+		})
+	}
+}
+
+func TestPreflightLabDirAfterDumpSpec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, _ := newPreflightTestLab(t, ctrl)
+	ctx := context.Background()
+
+	cfg := &types.NodeConfig{
+		ShortName: "r1",
+		LongName:  "clab-test-r1",
+		Image:     "alpine:3",
+		LabDir:    filepath.Join(c.TopoPaths.TopologyLabDir(), "r1"),
+	}
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().GetContainerSpec(gomock.Any(), cfg).Return(map[string]interface{}{"image": cfg.Image}, nil)
+
+	n := mocknodes.NewMockNode(ctrl)
+	n.EXPECT().Config().Return(cfg).AnyTimes()
+	n.EXPECT().GetRuntime().Return(rt)
+
+	// deploy --dump-spec writes the container specs to the lab directory without the topology data file
+	if err := c.dumpContainerSpec(ctx, n); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next deployment of the lab reuses the lab directory
+	findings, err := c.preflightLabDir(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 0 {
+		t.Fatalf("got %d findings for the lab directory with the dumped container specs: %s",
+			len(findings), findings[0].Problem)
+	}
+}
+
+func TestPreflightNetnsSymlinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mockRuntime := newPreflightTestLab(t, ctrl)
+	ctx := context.Background()
+
+	netnsDir := t.TempDir()
+
+	oldNetnsDir := utils.NetnsDir
+	utils.NetnsDir = netnsDir
+	t.Cleanup(func() { utils.NetnsDir = oldNetnsDir })
+
+	for _, name := range []string{"clab-test-running", "clab-test-stale"} {
+		if err := os.Symlink(filepath.Join(t.TempDir(), "ns"), filepath.Join(netnsDir, name)); err != nil {
+			t.Fatal(err)
+		}
+
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().Config().Return(&types.NodeConfig{LongName: name}).AnyTimes()
+		n.EXPECT().GetRuntime().Return(mockRuntime).AnyTimes()
+		c.Nodes[name] = n
+	}
+
+	mockRuntime.EXPECT().ListContainers(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, f []*types.GenericFilter) ([]runtime.GenericContainer, error) {
+			if f[0].Match == "clab-test-running" {
+				return []runtime.GenericContainer{{Names: []string{"/clab-test-running"}}}, nil
+			}
+
+			return nil, nil
+		}).Times(2)
+
+	findings, err := c.preflightNetnsSymlinks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+
+	// the stale symlinks are resolved without --reconfigure
+	remaining, err := ResolveSafePreflightFindings(ctx, findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(remaining) != 0 {
+		t.Fatalf("got %d remaining findings, want 0", len(remaining))
+	}
+
+	if _, err := os.Lstat(filepath.Join(netnsDir, "clab-test-stale")); !os.IsNotExist(err) {
+		t.Error("stale netns symlink was not removed")
+	}
+
+	if _, err := os.Lstat(filepath.Join(netnsDir, "clab-test-running")); err != nil {
+		t.Errorf("netns symlink of the running container was removed: %v", err)
+	}
+}
+
+func TestHostTuningFindings(t *testing.T) {
+	tuningErr := &runtime.HostTuningError{
+		Bridge:        "br-clab",
+		Containerized: true,
+	}
+	tuningErr.Add("tx-checksum-offload", "ethtool -K br-clab tx off", errors.New("operation not permitted"))
+
+	findings := hostTuningFindings(tuningErr)
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+
+	f := findings[0]
+	if f.Suggestion != "ethtool -K br-clab tx off" {
+		t.Errorf("got suggestion %q", f.Suggestion)
+	}
+
+	if !strings.Contains(f.Problem, "runs in a container") {
+		t.Errorf("problem %q doesn't mention the containerized environment", f.Problem)
+	}
+
+	// host tuning findings can't be resolved automatically
+	if err := ResolvePreflightFindings(context.Background(), findings); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/vishvananda/netlink"
+)
+
+// probeExecGrace is the time added to the probe timeout to let the exec of the ping command complete.
+const probeExecGrace = 2 * time.Second
+
+// pingRTTRegexp matches the round trip time in the ping output, e.g. "time=0.045 ms".
+var pingRTTRegexp = regexp.MustCompile(`time[=<]\s*([\d.]+)\s*ms`)
+
+// nodeIPs returns the global unicast IP addresses assigned to the interfaces of a node.
+// It is a variable to allow tests to stub the netns lookup.
+var nodeIPs = func(n nodes.Node) ([]*net.IPNet, error) {
+	var ips []*net.IPNet
+
+	err := n.ExecFunction(func(_ ns.NetNS) error {
+		addrs, err := netlink.AddrList(nil, netlink.FAMILY_ALL)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range addrs {
+			if a.IP.IsGlobalUnicast() {
+				ips = append(ips, a.IPNet)
+			}
+		}
+
+		return nil
+	})
+
+	return ips, err
+}
+
+// ReachabilityResult is the result of a reachability probe from the source to the target node.
+type ReachabilityResult struct {
+	Source  string        `json:"source"`
+	Target  string        `json:"target"`
+	Address string        `json:"address"`
+	Success bool          `json:"success"`
+	RTT     time.Duration `json:"rtt,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// ReachabilityMatrix holds the results of the reachability probes between all pairs of the lab nodes.
+type ReachabilityMatrix struct {
+	// Nodes is a sorted list of the probed nodes.
+	Nodes []string
+	// Results maps the source node to the target node and the probe result.
+	Results map[string]map[string]*ReachabilityResult
+}
+
+// Get returns the result of the probe from src to dst, nil if the probe was not run.
+func (m *ReachabilityMatrix) Get(src, dst string) *ReachabilityResult {
+	return m.Results[src][dst]
+}
+
+// List returns the probe results sorted by the source and target node names.
+func (m *ReachabilityMatrix) List() []*ReachabilityResult {
+	var res []*ReachabilityResult
+
+	for _, src := range m.Nodes {
+		for _, dst := range m.Nodes {
+			if r := m.Get(src, dst); r != nil {
+				res = append(res, r)
+			}
+		}
+	}
+
+	return res
+}
+
+// CheckReachability pings every node of the lab from every other node and returns the reachability matrix.
+// The nodes are pinged using their management addresses, or, when subnet is set,
+// using the addresses of the nodes that belong to the subnet.
+// Nodes without a container or without a matching address are not probed.
+// The probes are run by the number of workers concurrently, each probe is limited by probeTimeout.
+func (c *CLab) CheckReachability(ctx context.Context, subnet *net.IPNet, probeTimeout time.Duration,
+	workers uint,
+) (*ReachabilityMatrix, error) {
+	if workers == 0 {
+		return nil, errors.New("number of workers must be greater than zero")
+	}
+
+	addrs := map[string]net.IP{}
+
+	for name, n := range c.Nodes {
+		if err := n.UpdateConfigWithRuntimeInfo(ctx); err != nil {
+			log.Debugf("skipping node %s reachability check: %v", name, err)
+			continue
+		}
+
+		ip, err := nodeProbeAddress(n, subnet)
+		if err != nil {
+			log.Debugf("skipping node %s reachability check: %v", name, err)
+			continue
+		}
+
+		addrs[name] = ip
+	}
+
+	m := &ReachabilityMatrix{
+		Results: map[string]map[string]*ReachabilityResult{},
+	}
+
+	for name := range addrs {
+		m.Nodes = append(m.Nodes, name)
+		m.Results[name] = map[string]*ReachabilityResult{}
+	}
+
+	sort.Strings(m.Nodes)
+
+	probes := make(chan *ReachabilityResult)
+	mu := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+
+	wg.Add(int(workers))
+	for i := uint(0); i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for r := range probes {
+				c.probe(ctx, r, probeTimeout)
+
+				mu.Lock()
+				m.Results[r.Source][r.Target] = r
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, src := range m.Nodes {
+		for _, dst := range m.Nodes {
+			if src == dst {
+				continue
+			}
+
+			probes <- &ReachabilityResult{
+				Source:  src,
+				Target:  dst,
+				Address: addrs[dst].String(),
+			}
+		}
+	}
+
+	close(probes)
+	wg.Wait()
+
+	return m, ctx.Err()
+}
+
+// probe pings the target address of the result from its source node and populates the result.
+func (c *CLab) probe(ctx context.Context, r *ReachabilityResult, timeout time.Duration) {
+	// ping takes the timeout in whole seconds
+	wait := int(math.Ceil(timeout.Seconds()))
+	if wait < 1 {
+		wait = 1
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout+probeExecGrace)
+	defer cancel()
+
+	cmd := exec.NewExecCmdFromSlice([]string{"ping", "-c", "1", "-W", strconv.Itoa(wait), r.Address})
+
+	res, err := c.Nodes[r.Source].RunExec(ctx, cmd)
+	if err != nil {
+		r.Error = err.Error()
+		return
+	}
+
+	if res.GetReturnCode() != 0 {
+		r.Error = fmt.Sprintf("ping exited with code %d", res.GetReturnCode())
+		return
+	}
+
+	r.Success = true
+	r.RTT = parsePingRTT(res.GetStdOutString())
+}
+
+// nodeProbeAddress returns the address the node is probed with.
+// When subnet is not set, the management IPv4 address is preferred over the IPv6 one.
+// Otherwise the first node address that belongs to the subnet is returned.
+func nodeProbeAddress(n nodes.Node, subnet *net.IPNet) (net.IP, error) {
+	cfg := n.Config()
+
+	var mgmtIPs []net.IP
+	for _, a := range []string{cfg.MgmtIPv4Address, cfg.MgmtIPv6Address} {
+		if ip := net.ParseIP(a); ip != nil {
+			mgmtIPs = append(mgmtIPs, ip)
+		}
+	}
+
+	if subnet == nil {
+		if len(mgmtIPs) == 0 {
+			return nil, errors.New("node has no management address")
+		}
+
+		return mgmtIPs[0], nil
+	}
+
+	for _, ip := range mgmtIPs {
+		if subnet.Contains(ip) {
+			return ip, nil
+		}
+	}
+
+	ips, err := nodeIPs(n)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		if subnet.Contains(ip.IP) {
+			return ip.IP, nil
+		}
+	}
+
+	return nil, fmt.Errorf("node has no address in %s subnet", subnet)
+}
+
+// parsePingRTT returns the round trip time reported in the ping output, 0 if it is not found.
+func parsePingRTT(out string) time.Duration {
+	m := pingRTTRegexp.FindStringSubmatch(out)
+	if m == nil {
+		return 0
+	}
+
+	ms, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(ms * float64(time.Millisecond))
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestParsePingRTT(t *testing.T) {
+	tests := map[string]struct {
+		out  string
+		want time.Duration
+	}{
+		"iputils": {
+			out:  "64 bytes from 172.20.20.3: icmp_seq=1 ttl=64 time=0.045 ms",
+			want: 45 * time.Microsecond,
+		},
+		"busybox": {
+			out:  "64 bytes from 172.20.20.3: seq=0 ttl=64 time=1.250 ms",
+			want: 1250 * time.Microsecond,
+		},
+		"sub-millisecond": {
+			out:  "64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time<1 ms",
+			want: time.Millisecond,
+		},
+		"no-rtt": {
+			out: "1 packets transmitted, 0 received, 100% packet loss",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := parsePingRTT(tt.out); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckReachability(t *testing.T) {
+	tests := map[string]struct {
+		subnet string
+		// ips are the node addresses returned by the netns lookup
+		ips map[string][]string
+		// unreachable is a set of unreachable addresses
+		unreachable map[string]bool
+		// want maps "src->dst" to the probed address, addresses prefixed with "!" are expected to fail
+		want map[string]string
+	}{
+		"mgmt": {
+			unreachable: map[string]bool{"172.20.20.3": true},
+			want: map[string]string{
+				"n1->n2": "!172.20.20.3",
+				"n2->n1": "172.20.20.2",
+			},
+		},
+		"subnet": {
+			subnet: "10.0.0.0/24",
+			ips: map[string][]string{
+				"n1": {"192.168.0.1/24", "10.0.0.1/24"},
+				"n2": {"10.0.0.2/24"},
+			},
+			want: map[string]string{
+				"n1->n2": "10.0.0.2",
+				"n2->n1": "10.0.0.1",
+			},
+		},
+		"subnet-no-match": {
+			subnet: "10.0.0.0/24",
+			ips: map[string][]string{
+				"n1": {"10.0.0.1/24"},
+			},
+			want: map[string]string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			origNodeIPs := nodeIPs
+			t.Cleanup(func() { nodeIPs = origNodeIPs })
+
+			nodeIPs = func(n nodes.Node) ([]*net.IPNet, error) {
+				return mustParseCIDRs(t, tt.ips[n.GetShortName()]...), nil
+			}
+
+			c := &CLab{Nodes: map[string]nodes.Node{}}
+
+			mgmtIPs := map[string]string{"n1": "172.20.20.2", "n2": "172.20.20.3"}
+			for name, ip := range mgmtIPs {
+				n := mocknodes.NewMockNode(ctrl)
+				n.EXPECT().GetShortName().Return(name).AnyTimes()
+				n.EXPECT().Config().Return(&types.NodeConfig{ShortName: name, MgmtIPv4Address: ip}).AnyTimes()
+				n.EXPECT().UpdateConfigWithRuntimeInfo(gomock.Any()).Return(nil)
+				n.EXPECT().RunExec(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+						res := exec.NewExecResult(cmd)
+						args := cmd.GetCmd()
+						if tt.unreachable[args[len(args)-1]] {
+							res.SetReturnCode(1)
+						} else {
+							res.SetStdOut([]byte("64 bytes from x: icmp_seq=1 ttl=64 time=0.5 ms"))
+						}
+						return res, nil
+					}).AnyTimes()
+
+				c.Nodes[name] = n
+			}
+
+			// a node without a container is not probed
+			n3 := mocknodes.NewMockNode(ctrl)
+			n3.EXPECT().UpdateConfigWithRuntimeInfo(gomock.Any()).Return(errors.New("no container"))
+			c.Nodes["n3"] = n3
+
+			var subnet *net.IPNet
+			if tt.subnet != "" {
+				_, subnet, _ = net.ParseCIDR(tt.subnet)
+			}
+
+			m, err := c.CheckReachability(context.Background(), subnet, time.Second, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]string{}
+			for _, r := range m.List() {
+				addr := r.Address
+				if !r.Success {
+					addr = "!" + addr
+				}
+				got[r.Source+"->"+r.Target] = addr
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got results %v, want %v", got, tt.want)
+			}
+
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("probe %s: got %q, want %q", k, got[k], v)
+				}
+
+				if !strings.HasPrefix(v, "!") {
+					src, dst, _ := strings.Cut(k, "->")
+					if rtt := m.Get(src, dst).RTT; rtt != 500*time.Microsecond {
+						t.Errorf("probe %s: got rtt %v, want 500µs", k, rtt)
+					}
+				}
+			}
+		})
+	}
+}
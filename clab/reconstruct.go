@@ -0,0 +1,257 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// ReconstructTopology reconstructs a best-effort topology of the running lab from its containers.
+// The nodes are built from the containers labelled with the lab name, and the links between them
+// are found by matching the veth peers across the network namespaces of the containers.
+// The returned warnings describe the parts of the lab that couldn't be reconstructed.
+func (c *CLab) ReconstructTopology(ctx context.Context, labName string) (*Config, []string, error) {
+	containers, err := c.ListContainers(ctx, []*types.GenericFilter{{
+		FilterType: "label", Match: labName,
+		Field: labels.Containerlab, Operator: "=",
+	}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, nil, fmt.Errorf("no containers of the lab %q were found", labName)
+	}
+
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Names[0] < containers[j].Names[0] })
+
+	cfg := &Config{
+		Name: labName,
+		Mgmt: &types.MgmtNet{},
+		Topology: &types.Topology{
+			Nodes: make(map[string]*types.NodeDefinition),
+		},
+	}
+
+	var warnings []string
+
+	namespaces := map[string]ns.NetNS{}
+
+	defer func() {
+		for _, n := range namespaces {
+			n.Close()
+		}
+	}()
+
+	for i := range containers {
+		ctr := &containers[i]
+
+		var imageEnv []string
+
+		if img, err := c.GlobalRuntime().InspectImage(ctx, ctr.Image); err != nil {
+			log.Debugf("failed to inspect image %q: %v", ctr.Image, err)
+		} else {
+			imageEnv = img.Env
+		}
+
+		name, node := reconstructNode(ctr, imageEnv)
+
+		if node.Kind == "" {
+			node.Kind = "linux"
+			warnings = append(warnings, fmt.Sprintf("container %s has no %s label, the linux kind is used for node %s",
+				ctr.Names[0], labels.NodeKind, name))
+		}
+
+		cfg.Topology.Nodes[name] = node
+
+		if cfg.Prefix == nil {
+			cfg.Prefix = labPrefix(ctr.Names[0], labName, name)
+		}
+
+		reconstructMgmt(cfg.Mgmt, &ctr.NetworkSettings)
+
+		if ctr.Pid == 0 {
+			warnings = append(warnings, fmt.Sprintf("node %s is not running, its links can't be reconstructed", name))
+			continue
+		}
+
+		nsh, err := ns.GetNS(fmt.Sprintf("/proc/%d/ns/net", ctr.Pid))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to open the network namespace of node %s: %v", name, err))
+			continue
+		}
+
+		namespaces[name] = nsh
+	}
+
+	endpoints, err := links.DiscoverVethEndpoints(namespaces)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pairs, unmatched := links.MatchVethPeers(endpoints)
+
+	cfg.Topology.Links = reconstructLinks(pairs)
+
+	for _, e := range unmatched {
+		warnings = append(warnings, fmt.Sprintf("the peer of interface %s is not found in the lab containers", e))
+	}
+
+	return cfg, warnings, nil
+}
+
+// reconstructNode returns the name and the definition of the node running in the container.
+// The environment set by the image or by containerlab and the bind mounts of the files
+// generated by containerlab in the node lab directory are left out.
+func reconstructNode(ctr *runtime.GenericContainer, imageEnv []string) (string, *types.NodeDefinition) {
+	name := ctr.Labels[labels.NodeName]
+	if name == "" {
+		name = ctr.Names[0]
+	}
+
+	node := &types.NodeDefinition{
+		Kind:     ctr.Labels[labels.NodeKind],
+		Type:     ctr.Labels[labels.NodeType],
+		Group:    ctr.Labels[labels.NodeGroup],
+		Image:    ctr.Image,
+		MgmtIPv4: ctr.NetworkSettings.IPv4addr,
+		MgmtIPv6: ctr.NetworkSettings.IPv6addr,
+	}
+
+	imgEnv := make(map[string]struct{}, len(imageEnv))
+	for _, e := range imageEnv {
+		imgEnv[e] = struct{}{}
+	}
+
+	for _, e := range ctr.Env {
+		if _, ok := imgEnv[e]; ok {
+			continue
+		}
+
+		k, v, _ := strings.Cut(e, "=")
+		if k == types.CLAB_ENV_INTFS || strings.HasPrefix(k, "CLAB_LABEL_") {
+			continue
+		}
+
+		if node.Env == nil {
+			node.Env = map[string]string{}
+		}
+
+		node.Env[k] = v
+	}
+
+	labDir := ctr.Labels[labels.NodeLabDir]
+
+	for _, m := range ctr.Mounts {
+		var bind string
+
+		switch m.Type {
+		case "bind":
+			if labDir != "" && isSubPath(labDir, m.Source) {
+				continue
+			}
+
+			bind = m.Source + ":" + m.Destination
+		case "volume":
+			if m.Name == "" {
+				continue
+			}
+
+			bind = m.Name + ":" + m.Destination
+		default:
+			continue
+		}
+
+		if m.ReadOnly {
+			bind += ":ro"
+		}
+
+		node.Binds = append(node.Binds, bind)
+	}
+
+	sort.Strings(node.Binds)
+
+	return name, node
+}
+
+// isSubPath returns true if the path is the dir or is within the dir.
+func isSubPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// labPrefix returns the prefix of the lab container names, nil for the default prefix.
+func labPrefix(containerName, labName, nodeName string) *string {
+	var prefix string
+
+	switch {
+	case containerName == defaultPrefix+"-"+labName+"-"+nodeName:
+		return nil
+	case containerName == nodeName:
+		prefix = ""
+	case strings.HasSuffix(containerName, "-"+labName+"-"+nodeName):
+		prefix = strings.TrimSuffix(containerName, "-"+labName+"-"+nodeName)
+	default:
+		return nil
+	}
+
+	return &prefix
+}
+
+// reconstructMgmt sets the management network name and subnets from the addresses of a container.
+func reconstructMgmt(mgmt *types.MgmtNet, ips *runtime.GenericMgmtIPs) {
+	if mgmt.Network == "" {
+		mgmt.Network = ips.Network
+	}
+
+	if mgmt.IPv4Subnet == "" && ips.IPv4addr != "" {
+		mgmt.IPv4Subnet = subnetOf(ips.IPv4addr, ips.IPv4pLen)
+	}
+
+	if mgmt.IPv6Subnet == "" && ips.IPv6addr != "" {
+		mgmt.IPv6Subnet = subnetOf(ips.IPv6addr, ips.IPv6pLen)
+	}
+}
+
+// subnetOf returns the subnet of the address with the prefix length, empty for an invalid address.
+func subnetOf(addr string, plen int) string {
+	_, n, err := net.ParseCIDR(fmt.Sprintf("%s/%d", addr, plen))
+	if err != nil {
+		return ""
+	}
+
+	return n.String()
+}
+
+// reconstructLinks returns the link definitions of the veth pairs.
+func reconstructLinks(pairs []*links.VethPair) []*links.LinkDefinition {
+	defs := make([]*links.LinkDefinition, 0, len(pairs))
+
+	for _, p := range pairs {
+		l := &links.LinkVEthRaw{
+			Endpoints: []*links.EndpointRaw{
+				links.NewEndpointRaw(p.A.Node, p.A.Interface, ""),
+				links.NewEndpointRaw(p.B.Node, p.B.Interface, ""),
+			},
+		}
+
+		defs = append(defs, &links.LinkDefinition{Link: l.ToLinkBriefRaw()})
+	}
+
+	return defs
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestReconstructNode(t *testing.T) {
+	ctr := &runtime.GenericContainer{
+		Names: []string{"clab-lab1-srl1"},
+		Image: "ghcr.io/nokia/srlinux:23.10.1",
+		Labels: map[string]string{
+			labels.Containerlab: "lab1",
+			labels.NodeName:     "srl1",
+			labels.NodeKind:     "nokia_srlinux",
+			labels.NodeType:     "ixrd3",
+			labels.NodeGroup:    "spines",
+			labels.NodeLabDir:   "/labs/clab-lab1/srl1",
+		},
+		NetworkSettings: runtime.GenericMgmtIPs{
+			IPv4addr: "172.20.20.2", IPv4pLen: 24,
+			IPv6addr: "3fff:172:20:20::2", IPv6pLen: 64,
+		},
+		Env: []string{
+			"PATH=/usr/local/sbin:/usr/local/bin",
+			"SRLINUX=1",
+			"CLAB_INTFS=2",
+			"CLAB_LABEL_CLAB_NODE_NAME=srl1",
+			"MY_VAR=a=b",
+		},
+		Mounts: []runtime.ContainerMount{
+			{Type: "bind", Source: "/labs/clab-lab1/srl1/config", Destination: "/etc/opt/srlinux"},
+			{Type: "bind", Source: "/labs/clab-lab1/srl1", Destination: "/tmp/clab"},
+			{Type: "bind", Source: "/labs/clab-lab1/srl10/x", Destination: "/x"},
+			{Type: "bind", Source: "/home/user/scripts", Destination: "/scripts", ReadOnly: true},
+			{Type: "volume", Name: "data", Source: "/var/lib/docker/volumes/data/_data", Destination: "/data"},
+			{Type: "tmpfs", Destination: "/run"},
+		},
+	}
+
+	imageEnv := []string{"PATH=/usr/local/sbin:/usr/local/bin", "SRLINUX=1"}
+
+	name, node := reconstructNode(ctr, imageEnv)
+
+	if name != "srl1" {
+		t.Errorf("got node name %q, want srl1", name)
+	}
+
+	want := &types.NodeDefinition{
+		Kind:     "nokia_srlinux",
+		Type:     "ixrd3",
+		Group:    "spines",
+		Image:    "ghcr.io/nokia/srlinux:23.10.1",
+		MgmtIPv4: "172.20.20.2",
+		MgmtIPv6: "3fff:172:20:20::2",
+		Env:      map[string]string{"MY_VAR": "a=b"},
+		Binds: []string{
+			"/home/user/scripts:/scripts:ro",
+			"/labs/clab-lab1/srl10/x:/x",
+			"data:/data",
+		},
+	}
+
+	if d := cmp.Diff(want, node); d != "" {
+		t.Errorf("node mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLabPrefix(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := map[string]struct {
+		container string
+		want      *string
+	}{
+		"default prefix": {container: "clab-lab1-n1"},
+		"custom prefix":  {container: "my-pfx-lab1-n1", want: str("my-pfx")},
+		"empty prefix":   {container: "n1", want: str("")},
+		"unknown":        {container: "something-else"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tt.want, labPrefix(tt.container, "lab1", "n1")); d != "" {
+				t.Errorf("prefix mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestReconstructMgmt(t *testing.T) {
+	mgmt := &types.MgmtNet{}
+
+	reconstructMgmt(mgmt, &runtime.GenericMgmtIPs{Network: "clab"})
+	reconstructMgmt(mgmt, &runtime.GenericMgmtIPs{
+		Network:  "clab",
+		IPv4addr: "172.20.20.5", IPv4pLen: 24,
+		IPv6addr: "3fff:172:20:20::5", IPv6pLen: 64,
+	})
+
+	want := &types.MgmtNet{
+		Network:    "clab",
+		IPv4Subnet: "172.20.20.0/24",
+		IPv6Subnet: "3fff:172:20:20::/64",
+	}
+
+	if d := cmp.Diff(want, mgmt); d != "" {
+		t.Errorf("mgmt mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestReconstructLinks(t *testing.T) {
+	pairs := []*links.VethPair{
+		{
+			A: &links.VethEndpoint{Node: "n1", Interface: "e1-1"},
+			B: &links.VethEndpoint{Node: "n2", Interface: "e1-1"},
+		},
+	}
+
+	defs := reconstructLinks(pairs)
+	if len(defs) != 1 {
+		t.Fatalf("got %d links, want 1", len(defs))
+	}
+
+	brief, ok := defs[0].Link.(*links.LinkBriefRaw)
+	if !ok {
+		t.Fatalf("got link of type %T, want *links.LinkBriefRaw", defs[0].Link)
+	}
+
+	if d := cmp.Diff([]string{"n1:e1-1", "n2:e1-1"}, brief.Endpoints); d != "" {
+		t.Errorf("endpoints mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// CheckRuntimeVersions checks that the API versions of the lab runtimes meet the versions
+// required by containerlab and by the features the nodes hosted by the runtimes use,
+// so that the deployment fails before any of the lab resources are created.
+func (c *CLab) CheckRuntimeVersions(ctx context.Context) error {
+	var errs []error
+
+	for _, name := range c.nodeRuntimeNames() {
+		r := c.Runtimes[name]
+
+		var cfgs []*types.NodeConfig
+		for _, n := range c.Nodes {
+			if n.GetRuntime() == r {
+				cfgs = append(cfgs, n.Config())
+			}
+		}
+
+		if err := r.CheckVersion(ctx, cfgs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// shutdownNode asks the NOS of the node to shut down gracefully when the graceful shutdown is enabled.
+// The shutdown is given the shutdown timeout, after which the node is left to the runtime stop.
+// The shutdown errors are not fatal, as the container is stopped anyway,
+// and the kinds without a native shutdown mechanism are skipped silently.
+func (c *CLab) shutdownNode(ctx context.Context, n nodes.Node) {
+	if c.shutdownTimeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.shutdownTimeout)
+	defer cancel()
+
+	err := n.Shutdown(ctx)
+
+	switch {
+	case err == nil:
+		log.Infof("Node %q is shut down", n.Config().ShortName)
+	case errors.Is(err, nodes.ErrShutdownNotSupported):
+		log.Debugf("Node %q: %v", n.Config().ShortName, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Warnf("Node %q is not shut down within %s, stopping its container", n.Config().ShortName, c.shutdownTimeout)
+	default:
+		log.Warnf("Node %q failed to shut down gracefully, stopping its container: %v", n.Config().ShortName, err)
+	}
+}
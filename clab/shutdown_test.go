@@ -0,0 +1,115 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func newShutdownMockNode(ctrl *gomock.Controller, name string) *mocknodes.MockNode {
+	n := mocknodes.NewMockNode(ctrl)
+	n.EXPECT().Config().Return(&types.NodeConfig{
+		ShortName: name,
+		LongName:  "clab-test-" + name,
+	}).AnyTimes()
+
+	return n
+}
+
+// deleteWithLiveContext expects the node deletion with the context which is not done,
+// i.e. the shutdown timeout doesn't affect the container stop.
+func deleteWithLiveContext(t *testing.T) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			t.Errorf("node is deleted with the done context: %v", ctx.Err())
+		}
+		return nil
+	}
+}
+
+func TestDeleteNodesGracefulShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	shutdownTimeout := 200 * time.Millisecond
+
+	// the node is shut down before its container is deleted
+	n1 := newShutdownMockNode(ctrl, "node1")
+	gomock.InOrder(
+		n1.EXPECT().Shutdown(gomock.Any()).Return(nil),
+		n1.EXPECT().Delete(gomock.Any()).DoAndReturn(deleteWithLiveContext(t)),
+	)
+
+	// the node not shut down within the timeout is deleted once the timeout expires
+	n2 := newShutdownMockNode(ctrl, "node2")
+	gomock.InOrder(
+		n2.EXPECT().Shutdown(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		n2.EXPECT().Delete(gomock.Any()).DoAndReturn(deleteWithLiveContext(t)),
+	)
+
+	// the kind without the shutdown mechanism falls back to the container deletion
+	n3 := newShutdownMockNode(ctrl, "node3")
+	gomock.InOrder(
+		n3.EXPECT().Shutdown(gomock.Any()).Return(fmt.Errorf("%w for %q node kind", nodes.ErrShutdownNotSupported, "linux")),
+		n3.EXPECT().Delete(gomock.Any()).DoAndReturn(deleteWithLiveContext(t)),
+	)
+
+	// the failed shutdown doesn't prevent the container deletion
+	n4 := newShutdownMockNode(ctrl, "node4")
+	gomock.InOrder(
+		n4.EXPECT().Shutdown(gomock.Any()).Return(fmt.Errorf("powerdown failed")),
+		n4.EXPECT().Delete(gomock.Any()).DoAndReturn(deleteWithLiveContext(t)),
+	)
+
+	c := &CLab{
+		Nodes: map[string]nodes.Node{
+			"node1": n1,
+			"node2": n2,
+			"node3": n3,
+			"node4": n4,
+		},
+	}
+	if err := WithGracefulShutdown(shutdownTimeout)(c); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	c.DeleteNodes(context.Background(), 4, nil)
+
+	if elapsed := time.Since(start); elapsed < shutdownTimeout {
+		t.Errorf("nodes are deleted in %s, before the shutdown timeout %s expired", elapsed, shutdownTimeout)
+	}
+}
+
+func TestDeleteNodesWithoutGracefulShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	n1 := newShutdownMockNode(ctrl, "node1")
+	n1.EXPECT().Shutdown(gomock.Any()).Times(0)
+	n1.EXPECT().Delete(gomock.Any()).Return(nil)
+
+	c := &CLab{Nodes: map[string]nodes.Node{"node1": n1}}
+
+	c.DeleteNodes(context.Background(), 1, nil)
+}
+
+func TestWithGracefulShutdown(t *testing.T) {
+	c := &CLab{}
+	if err := WithGracefulShutdown(0)(c); err == nil {
+		t.Error("expected an error for the zero shutdown timeout")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"text/template"
 
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/types"
 )
 
@@ -19,6 +20,8 @@ type SSHConfigTmpl struct {
 type SSHConfigNodeTmpl struct {
 	Name     string
 	Username string
+	// SSHPort is the host port the ssh service exposed by the node is published on, zero when not exposed
+	SSHPort int
 }
 
 // tmplSshConfig is the SSH config template.
@@ -31,6 +34,17 @@ Host {{ .Name }}
 	{{- end }}
 	StrictHostKeyChecking=no 
 	UserKnownHostsFile=/dev/null
+{{- if .SSHPort }}
+
+Host {{ .Name }}-exposed
+	HostName localhost
+	Port {{ .SSHPort }}
+	{{-  if ne .Username ""}}
+	User {{ .Username }}
+	{{- end }}
+	StrictHostKeyChecking=no 
+	UserKnownHostsFile=/dev/null
+{{- end }}
 {{ end }}`
 
 // RemoveSSHConfig removes the lab specific ssh config file
@@ -58,6 +72,7 @@ func (c *CLab) AddSSHConfig(topoPaths *types.TopoPaths) error {
 		nodeData := SSHConfigNodeTmpl{
 			Name:     n.Config().LongName,
 			Username: NodeRegistryEntry.Credentials().GetUsername(),
+			SSHPort:  n.Config().ExposedPorts[nodes.ServiceSSH],
 		}
 		tmpl.Nodes = append(tmpl.Nodes, nodeData)
 	}
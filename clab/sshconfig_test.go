@@ -0,0 +1,50 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSSHConfigTemplate(t *testing.T) {
+	tmpl := &SSHConfigTmpl{
+		TopologyName: "lab",
+		Nodes: []SSHConfigNodeTmpl{
+			{Name: "clab-lab-srl1", Username: "admin", SSHPort: 49152},
+			{Name: "clab-lab-l1"},
+		},
+	}
+
+	var b strings.Builder
+	if err := template.Must(template.New("sshconfig").Parse(tmplSshConfig)).Execute(&b, tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	// the node exposing the ssh service gets the host entry of the exposed port
+	want := `# Containerlab SSH Config for the lab lab
+Host clab-lab-srl1
+	User admin
+	StrictHostKeyChecking=no 
+	UserKnownHostsFile=/dev/null
+
+Host clab-lab-srl1-exposed
+	HostName localhost
+	Port 49152
+	User admin
+	StrictHostKeyChecking=no 
+	UserKnownHostsFile=/dev/null
+
+Host clab-lab-l1
+	StrictHostKeyChecking=no 
+	UserKnownHostsFile=/dev/null
+`
+	if d := cmp.Diff(want, b.String()); d != "" {
+		t.Errorf("ssh config mismatch (-want +got):\n%s", d)
+	}
+}
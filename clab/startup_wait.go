@@ -0,0 +1,141 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// startupWaitInterval is the interval between the checks of the startup-wait condition.
+const startupWaitInterval = time.Second
+
+// waitForStartup blocks until the node meets its startup-wait condition or the condition timeout expires.
+// The node defining both the TCP port and the log line must meet both conditions.
+// Nothing is done when the node has no startup-wait condition.
+func waitForStartup(ctx context.Context, node nodes.Node) error {
+	cfg := node.Config()
+
+	sw := cfg.StartupWait
+	if !sw.IsSet() {
+		return nil
+	}
+
+	log.Infof("Waiting up to %s for node %q to start up", sw.Timeout, cfg.ShortName)
+
+	ctx, cancel := context.WithTimeout(ctx, sw.Timeout)
+	defer cancel()
+
+	if sw.LogLine != "" {
+		re, err := regexp.Compile(sw.LogLine)
+		if err != nil {
+			return err
+		}
+
+		if err := waitForLogLine(ctx, node.GetRuntime(), cfg.LongName, re); err != nil {
+			return err
+		}
+	}
+
+	if sw.TCPPort == 0 {
+		return nil
+	}
+
+	// the management address assigned by the runtime is known only once the container is inspected
+	if cfg.NetworkMode != "host" && cfg.MgmtIPv4Address == "" && cfg.MgmtIPv6Address == "" {
+		if err := node.UpdateConfigWithRuntimeInfo(ctx); err != nil {
+			return fmt.Errorf("failed to retrieve the management address of node %q: %w", cfg.ShortName, err)
+		}
+	}
+
+	host, err := startupWaitHost(cfg)
+	if err != nil {
+		return err
+	}
+
+	return waitForTCP(ctx, net.JoinHostPort(host, strconv.Itoa(sw.TCPPort)), startupWaitInterval)
+}
+
+// startupWaitHost returns the address of the node the startup-wait condition is checked against.
+func startupWaitHost(cfg *types.NodeConfig) (string, error) {
+	switch {
+	case cfg.NetworkMode == "host":
+		return "127.0.0.1", nil
+	case cfg.MgmtIPv4Address != "":
+		return cfg.MgmtIPv4Address, nil
+	case cfg.MgmtIPv6Address != "":
+		return cfg.MgmtIPv6Address, nil
+	}
+
+	return "", fmt.Errorf("node %q has no management address to check the startup-wait condition against", cfg.ShortName)
+}
+
+// waitForTCP polls the tcp address addr every interval until it accepts a connection or ctx is done.
+func waitForTCP(ctx context.Context, addr string, interval time.Duration) error {
+	var d net.Dialer
+
+	for {
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn.Close()
+		}
+
+		log.Debugf("%s doesn't accept connections yet: %v", addr, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s didn't accept connections: %w", addr, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// waitForLogLine follows the logs of the container contName until a line matches re or ctx is done.
+func waitForLogLine(ctx context.Context, r runtime.ContainerRuntime, contName string, re *regexp.Regexp) error {
+	rc, err := r.GetContainerLogs(ctx, contName, runtime.LogOptions{Follow: true})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// the log stream is closed on timeout, so that the scan doesn't block on the silent container
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			rc.Close()
+		case <-done:
+		}
+	}()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		if re.Match(scanner.Bytes()) {
+			return nil
+		}
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("logs of %s didn't match %q: %w", contName, re, ctx.Err())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("logs of %s ended without matching %q", contName, re)
+}
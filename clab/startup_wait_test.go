@@ -0,0 +1,217 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestWaitForStartup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	port := l.Addr().(*net.TCPAddr).Port
+
+	cfg := &types.NodeConfig{
+		ShortName:       "srl1",
+		MgmtIPv4Address: "127.0.0.1",
+		StartupWait:     &types.StartupWait{TCPPort: port, Timeout: 5 * time.Second},
+	}
+
+	node := mocknodes.NewMockNode(ctrl)
+	node.EXPECT().Config().Return(cfg).AnyTimes()
+
+	if err := waitForStartup(context.Background(), node); err != nil {
+		t.Fatalf("got error for the listening port: %v", err)
+	}
+
+	// the closed port doesn't meet the condition until the timeout expires
+	l.Close()
+
+	cfg.StartupWait.Timeout = 100 * time.Millisecond
+
+	err = waitForStartup(context.Background(), node)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v for the closed port, want %v", err, context.DeadlineExceeded)
+	}
+
+	// the node without the condition isn't waited for
+	node = mocknodes.NewMockNode(ctrl)
+	node.EXPECT().Config().Return(&types.NodeConfig{ShortName: "l1"}).AnyTimes()
+
+	if err := waitForStartup(context.Background(), node); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForStartupDynamicAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// the node has no static management address, the runtime assigns it when the container is created
+	cfg := &types.NodeConfig{
+		ShortName:   "srl1",
+		StartupWait: &types.StartupWait{TCPPort: l.Addr().(*net.TCPAddr).Port, Timeout: 5 * time.Second},
+	}
+
+	node := mocknodes.NewMockNode(ctrl)
+	node.EXPECT().Config().Return(cfg).AnyTimes()
+	node.EXPECT().UpdateConfigWithRuntimeInfo(gomock.Any()).DoAndReturn(func(context.Context) error {
+		cfg.MgmtIPv4Address = "127.0.0.1"
+		return nil
+	})
+
+	if err := waitForStartup(context.Background(), node); err != nil {
+		t.Fatalf("got error for the dynamically addressed node: %v", err)
+	}
+}
+
+func TestWaitForStartupLogLine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := map[string]struct {
+		logs    string
+		wantErr bool
+	}{
+		"matching line": {
+			logs: "booting\nSystem is ready on port 57400\nrunning\n",
+		},
+		"no matching line": {
+			logs:    "booting\nstill booting\n",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rt := mockruntime.NewMockContainerRuntime(ctrl)
+			rt.EXPECT().GetContainerLogs(gomock.Any(), "clab-lab-srl1", runtime.LogOptions{Follow: true}).
+				Return(io.NopCloser(strings.NewReader(tt.logs)), nil)
+
+			node := mocknodes.NewMockNode(ctrl)
+			node.EXPECT().Config().Return(&types.NodeConfig{
+				ShortName:   "srl1",
+				LongName:    "clab-lab-srl1",
+				StartupWait: &types.StartupWait{LogLine: `ready on port \d+`, Timeout: 5 * time.Second},
+			}).AnyTimes()
+			node.EXPECT().GetRuntime().Return(rt)
+
+			err := waitForStartup(context.Background(), node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForLogLineTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// the container that logs nothing keeps the stream open
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().GetContainerLogs(gomock.Any(), "clab-lab-srl1", gomock.Any()).Return(pr, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := waitForLogLine(ctx, rt, "clab-lab-srl1", regexp.MustCompile("ready"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v for the silent container, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWaitForTCPDelayedListener(t *testing.T) {
+	// reserve a free port and release it, so that it starts accepting connections later
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := l.Addr().String()
+	l.Close()
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+		defer l.Close()
+
+		time.Sleep(5 * time.Second)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := waitForTCP(ctx, addr, 10*time.Millisecond); err != nil {
+		t.Fatalf("got error %v, want the connection to %s", err, addr)
+	}
+}
+
+func TestStartupWaitHost(t *testing.T) {
+	tests := map[string]struct {
+		cfg     *types.NodeConfig
+		want    string
+		wantErr bool
+	}{
+		"ipv4": {
+			cfg:  &types.NodeConfig{MgmtIPv4Address: "172.20.20.2", MgmtIPv6Address: "3fff:172:20:20::2"},
+			want: "172.20.20.2",
+		},
+		"ipv6 only": {
+			cfg:  &types.NodeConfig{MgmtIPv6Address: "3fff:172:20:20::2"},
+			want: "3fff:172:20:20::2",
+		},
+		"host network mode": {
+			cfg:  &types.NodeConfig{NetworkMode: "host"},
+			want: "127.0.0.1",
+		},
+		"no address": {
+			cfg:     &types.NodeConfig{NetworkMode: "container:srl1"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := startupWaitHost(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got host %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const localtimePath = "/etc/localtime"
+
+// zoneinfoDir is the host zoneinfo database the node time zones are checked against.
+// It is a var to allow the tests to use a fake database.
+var zoneinfoDir = "/usr/share/zoneinfo"
+
+// zoneinfoFile returns the path to the host zoneinfo file of the time zone tz.
+func zoneinfoFile(tz string) (string, error) {
+	if filepath.IsAbs(tz) || strings.Contains(tz, "..") {
+		return "", fmt.Errorf("invalid timezone %q", tz)
+	}
+
+	p := filepath.Join(zoneinfoDir, tz)
+
+	fi, err := os.Stat(p)
+	if err != nil || !fi.Mode().IsRegular() {
+		return "", fmt.Errorf("unknown timezone %q, no such zone in the %s database", tz, zoneinfoDir)
+	}
+
+	return p, nil
+}
+
+// processTimezone checks the time zone of the node against the host zoneinfo database.
+// The nodes of the linux-like kinds get the TZ env var set and the zoneinfo file of the time zone
+// bind mounted to /etc/localtime, unless the user has set them explicitly.
+func processTimezone(nodeCfg *types.NodeConfig) error {
+	if nodeCfg.Timezone == "" {
+		return nil
+	}
+
+	zf, err := zoneinfoFile(nodeCfg.Timezone)
+	if err != nil {
+		return fmt.Errorf("node %q: %w", nodeCfg.ShortName, err)
+	}
+
+	if _, ok := nodes.TimezoneKinds[nodeCfg.Kind]; !ok {
+		return nil
+	}
+
+	if nodeCfg.Env == nil {
+		nodeCfg.Env = map[string]string{}
+	}
+
+	if _, ok := nodeCfg.Env["TZ"]; !ok {
+		nodeCfg.Env["TZ"] = nodeCfg.Timezone
+	}
+
+	for _, b := range nodeCfg.Binds {
+		if parts := strings.Split(b, ":"); len(parts) > 1 && parts[1] == localtimePath {
+			return nil
+		}
+	}
+
+	nodeCfg.Binds = append(nodeCfg.Binds, zf+":"+localtimePath+":ro")
+
+	return nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// DefaultTmpMaxAge is the age after which the entries of the clab temp dir are pruned
+	// when a topology is loaded.
+	DefaultTmpMaxAge = 7 * 24 * time.Hour
+	// TmpMaxAgeEnv is the env var overriding the default max age of the clab temp dir entries,
+	// zero disables the pruning.
+	TmpMaxAgeEnv = "CLAB_TMP_MAX_AGE"
+	// tmpLockFile is the file in the clab temp dir locked while the dir is pruned,
+	// so that the concurrent clab invocations don't prune it at the same time.
+	tmpLockFile = ".lock"
+	// defaultTmpPruneTimeout is the timeout of listing the deployed labs when the clab temp dir is pruned.
+	defaultTmpPruneTimeout = 30 * time.Second
+)
+
+// tmpMaxAge is the max age of the clab temp dir entries set with SetTmpMaxAge.
+var tmpMaxAge *time.Duration
+
+// SetTmpMaxAge sets the max age of the clab temp dir entries pruned when a topology is loaded,
+// overriding the max age set with the CLAB_TMP_MAX_AGE env var. Zero disables the pruning.
+func SetTmpMaxAge(d time.Duration) {
+	tmpMaxAge = &d
+}
+
+// TmpMaxAge returns the max age of the clab temp dir entries pruned when a topology is loaded.
+// The max age set with SetTmpMaxAge takes precedence over the CLAB_TMP_MAX_AGE env var,
+// DefaultTmpMaxAge is used when neither is set.
+func TmpMaxAge() (time.Duration, error) {
+	d := DefaultTmpMaxAge
+
+	switch {
+	case tmpMaxAge != nil:
+		d = *tmpMaxAge
+	case os.Getenv(TmpMaxAgeEnv) != "":
+		var err error
+
+		d, err = time.ParseDuration(os.Getenv(TmpMaxAgeEnv))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s value: %w", TmpMaxAgeEnv, err)
+		}
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("the max age of the clab temp dir entries must not be negative, got %s", d)
+	}
+
+	return d, nil
+}
+
+// tmpDirRefs are the references of the deployed labs to the entries of the clab temp dir.
+type tmpDirRefs struct {
+	// paths are the files used by the deployed labs, e.g. the topology files read from stdin or downloaded.
+	paths map[string]struct{}
+	// labs are the names of the deployed labs, the startup configs downloaded for the lab nodes
+	// are prefixed with the lab name.
+	labs map[string]struct{}
+}
+
+func newTmpDirRefs() *tmpDirRefs {
+	return &tmpDirRefs{
+		paths: map[string]struct{}{},
+		labs:  map[string]struct{}{},
+	}
+}
+
+// references returns true if the entry of the clab temp dir is used by a deployed lab,
+// i.e. it is or it contains a file used by the lab or it is named after the lab.
+func (r *tmpDirRefs) references(p string) bool {
+	for ref := range r.paths {
+		if ref == p || strings.HasPrefix(ref, p+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	base := filepath.Base(p)
+	for lab := range r.labs {
+		if strings.HasPrefix(base, lab+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tmpDirReferences returns the references of the labs deployed on the host to the entries of the clab temp dir.
+// The labs are found by their containers, the topology files they were deployed from are read
+// from the container labels and from the lab metadata.
+func (c *CLab) tmpDirReferences(ctx context.Context) (*tmpDirRefs, error) {
+	if len(c.Runtimes) == 0 {
+		return nil, errors.New("no container runtime to list the deployed labs with")
+	}
+
+	cnts, err := c.ListContainers(ctx, types.FilterFromLabelStrings([]string{labels.Containerlab}))
+	if err != nil {
+		return nil, err
+	}
+
+	refs := newTmpDirRefs()
+	labDirs := map[string]struct{}{}
+
+	for _, cnt := range cnts {
+		if lab := cnt.Labels[labels.Containerlab]; lab != "" {
+			refs.labs[lab] = struct{}{}
+		}
+
+		if f := cnt.Labels[labels.TopoFile]; f != "" {
+			refs.paths[filepath.Clean(f)] = struct{}{}
+		}
+
+		// the lab directory is the parent of the node directory
+		if d := cnt.Labels[labels.NodeLabDir]; d != "" {
+			labDirs[filepath.Dir(d)] = struct{}{}
+		}
+	}
+
+	for d := range labDirs {
+		md, err := readLabMetadataFile(types.LabMetadataFilePath(d))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Debugf("failed to read the metadata of the lab in %s: %v", d, err)
+			}
+
+			continue
+		}
+
+		if md.Topology != nil && md.Topology.Source != "" {
+			refs.paths[filepath.Clean(md.Topology.Source)] = struct{}{}
+		}
+	}
+
+	return refs, nil
+}
+
+// PruneTmpDir removes the entries of the clab temp dir not modified within the max age and not used
+// by the labs deployed on the host, and returns their paths sorted. All the unused entries are removed
+// when the max age is zero. The remote topology includes are pruned one by one, the forwarding ledger
+// and its lock are kept. With the dry run the entries are returned without being removed.
+func (c *CLab) PruneTmpDir(ctx context.Context, maxAge time.Duration, dryRun bool) ([]string, error) {
+	return c.pruneTmpDir(ctx, c.TopoPaths.ClabTmpDir(), maxAge, dryRun)
+}
+
+// pruneTmpDir prunes the clab temp dir at the root.
+func (c *CLab) pruneTmpDir(ctx context.Context, root string, maxAge time.Duration, dryRun bool) ([]string, error) {
+	if !utils.DirExists(root) {
+		return nil, nil
+	}
+
+	refs, err := c.tmpDirReferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the clab temp dir entries used by the deployed labs: %w", err)
+	}
+
+	unlock, err := utils.LockFile(filepath.Join(root, tmpLockFile))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	keep := func(p string) bool {
+		switch filepath.Base(p) {
+		case tmpLockFile, forwardingLedgerFile, forwardingLockFile, includesDir:
+			return true
+		}
+
+		return refs.references(p)
+	}
+
+	pruned, err := utils.PruneDir(root, maxAge, keep, dryRun)
+	if err != nil {
+		return pruned, err
+	}
+
+	includes := filepath.Join(root, includesDir)
+	if fi, err := os.Lstat(includes); err == nil && fi.IsDir() {
+		p, err := utils.PruneDir(includes, maxAge, refs.references, dryRun)
+		pruned = append(pruned, p...)
+
+		if err != nil {
+			return pruned, err
+		}
+	}
+
+	sort.Strings(pruned)
+
+	return pruned, nil
+}
+
+// pruneTmpDirOnLoad prunes the aged entries of the clab temp dir when the topology is loaded.
+// The failures are logged only, since the pruning doesn't affect the command being run.
+func (c *CLab) pruneTmpDirOnLoad() {
+	maxAge, err := TmpMaxAge()
+	if err != nil {
+		log.Warnf("The clab temp dir is not pruned: %v", err)
+		return
+	}
+
+	// the labs using the temp dir entries can't be found without a runtime
+	if maxAge == 0 || len(c.Runtimes) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTmpPruneTimeout)
+	defer cancel()
+
+	pruned, err := c.PruneTmpDir(ctx, maxAge, false)
+	if err != nil {
+		log.Debugf("Failed to prune the clab temp dir: %v", err)
+	}
+
+	if len(pruned) > 0 {
+		log.Debugf("Pruned %d entries of the clab temp dir older than %s: %s",
+			len(pruned), maxAge, strings.Join(pruned, ", "))
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestPruneTmpDir(t *testing.T) {
+	root := filepath.Join(t.TempDir(), ".clab")
+	labDir := filepath.Join(t.TempDir(), "clab-lab2")
+
+	old, recent := 10*24*time.Hour, time.Hour
+
+	entries := map[string]time.Duration{
+		// the startup config downloaded for the node of the deployed lab
+		"lab1-srl1-startup.cfg": old,
+		// the topology read from stdin the deployed lab is labeled with
+		"topo-1.clab.yml": old,
+		// the topology read from stdin recorded in the metadata of the deployed lab
+		"topo-2.clab.yml": old,
+		// the leftovers of the destroyed lab
+		"gone-srl1-startup.cfg": old,
+		"topo-3.clab.yml":       old,
+		"recent.cfg":            recent,
+		forwardingLedgerFile:    old,
+		forwardingLockFile:      old,
+		filepath.Join(includesDir, "1234-old.clab.yml"):    old,
+		filepath.Join(includesDir, "5678-recent.clab.yml"): recent,
+		// the topology downloaded over http(s) the deployed lab is labeled with
+		filepath.Join(downloadedTopoDirPrefix+"1234", "lab3.clab.yml"): old,
+		// the topology downloaded for the destroyed lab
+		filepath.Join(downloadedTopoDirPrefix+"5678", "gone.clab.yml"): old,
+	}
+
+	for name, age := range entries {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.MkdirAll(labDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := json.Marshal(LabMetadata{
+		Name:     "lab2",
+		Topology: &TopologyBackups{Source: filepath.Join(root, "topo-2.clab.yml")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(types.LabMetadataFilePath(labDir), md, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := gomock.NewController(t)
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().ListContainers(gomock.Any(), gomock.Any()).Return([]runtime.GenericContainer{
+		{
+			Names: []string{"clab-lab1-srl1"},
+			Labels: map[string]string{
+				labels.Containerlab: "lab1",
+				labels.TopoFile:     filepath.Join(root, "topo-1.clab.yml"),
+			},
+		},
+		{
+			Names: []string{"clab-lab2-srl1"},
+			Labels: map[string]string{
+				labels.Containerlab: "lab2",
+				labels.TopoFile:     "/labs/lab2.clab.yml",
+				labels.NodeLabDir:   filepath.Join(labDir, "srl1"),
+			},
+		},
+		{
+			Names: []string{"clab-lab3-srl1"},
+			Labels: map[string]string{
+				labels.Containerlab: "lab3",
+				labels.TopoFile:     filepath.Join(root, downloadedTopoDirPrefix+"1234", "lab3.clab.yml"),
+			},
+		},
+	}, nil).AnyTimes()
+
+	c := &CLab{Runtimes: map[string]runtime.ContainerRuntime{"docker": rt}}
+
+	tests := map[string]struct {
+		maxAge     time.Duration
+		wantPruned []string
+	}{
+		"aged entries": {
+			maxAge: DefaultTmpMaxAge,
+			wantPruned: []string{
+				"gone-srl1-startup.cfg",
+				filepath.Join(includesDir, "1234-old.clab.yml"),
+				"topo-3.clab.yml",
+			},
+		},
+		"all entries": {
+			wantPruned: []string{
+				"gone-srl1-startup.cfg",
+				filepath.Join(includesDir, "1234-old.clab.yml"),
+				filepath.Join(includesDir, "5678-recent.clab.yml"),
+				"recent.cfg",
+				"topo-3.clab.yml",
+				downloadedTopoDirPrefix + "5678",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pruned, err := c.pruneTmpDir(context.Background(), root, tt.maxAge, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := make([]string, 0, len(tt.wantPruned))
+			for _, n := range tt.wantPruned {
+				want = append(want, filepath.Join(root, n))
+			}
+
+			if d := cmp.Diff(want, pruned); d != "" {
+				t.Errorf("pruned entries mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	// the entries are removed without the dry run
+	pruned, err := c.pruneTmpDir(context.Background(), root, DefaultTmpMaxAge, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range pruned {
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			t.Errorf("pruned entry %s still exists", p)
+		}
+	}
+
+	for _, n := range []string{"lab1-srl1-startup.cfg", "topo-2.clab.yml", forwardingLedgerFile, forwardingLockFile, "recent.cfg",
+		filepath.Join(downloadedTopoDirPrefix+"1234", "lab3.clab.yml"),
+	} {
+		if _, err := os.Lstat(filepath.Join(root, n)); err != nil {
+			t.Errorf("kept entry %s: %v", n, err)
+		}
+	}
+}
+
+func TestTmpMaxAge(t *testing.T) {
+	defer func() { tmpMaxAge = nil }()
+
+	tests := map[string]struct {
+		env     string
+		set     *time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		"default":      {want: DefaultTmpMaxAge},
+		"env":          {env: "24h", want: 24 * time.Hour},
+		"env disabled": {env: "0", want: 0},
+		"invalid env":  {env: "week", wantErr: true},
+		"negative env": {env: "-1h", wantErr: true},
+		"set":          {env: "24h", set: new(time.Duration), want: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(TmpMaxAgeEnv, tt.env)
+
+			tmpMaxAge = tt.set
+
+			got, err := TmpMaxAge()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got max age %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// topologyBackups is the number of the previous topology copies kept in the lab directory.
+const topologyBackups = 3
+
+// TopologyBackup is a copy of the topology written to the lab directory.
+type TopologyBackup struct {
+	// File is the name of the copy in the lab directory.
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+}
+
+// TopologyBackups are the copies of the topology the lab was deployed from.
+type TopologyBackups struct {
+	// Source is the path of the topology file the lab was deployed from.
+	Source string `json:"source"`
+	// Original is the copy of the topology file as is.
+	Original *TopologyBackup `json:"original"`
+	// Resolved is the topology rendered from the template with the included files merged and normalized.
+	Resolved *TopologyBackup `json:"resolved"`
+}
+
+// ResolvedTopology returns the topology rendered from the template with the included files merged.
+// The topology is normalized, so the comments and the formatting of the topology file don't change it.
+func (c *CLab) ResolvedTopology() ([]byte, error) {
+	var t map[string]interface{}
+	if err := yaml.Unmarshal(c.resolvedTopology, &t); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(t)
+}
+
+// BackupTopology copies the original and the resolved topology to the lab directory,
+// rotating the previous copies when their content changed. The copies are referenced by the lab metadata.
+func (c *CLab) BackupTopology() error {
+	resolved, err := c.ResolvedTopology()
+	if err != nil {
+		return fmt.Errorf("failed to resolve the topology: %w", err)
+	}
+
+	original, err := os.ReadFile(c.TopoPaths.TopologyFilenameAbsPath())
+	if err != nil {
+		return err
+	}
+
+	originalHash, err := backupTopologyFile(c.TopoPaths.OriginalTopologyFileAbsPath(), original)
+	if err != nil {
+		return err
+	}
+
+	resolvedHash, err := backupTopologyFile(c.TopoPaths.ResolvedTopologyFileAbsPath(), resolved)
+	if err != nil {
+		return err
+	}
+
+	c.topologyBackups = &TopologyBackups{
+		Source:   c.TopoPaths.TopologyFilenameAbsPath(),
+		Original: &TopologyBackup{File: filepath.Base(c.TopoPaths.OriginalTopologyFileAbsPath()), SHA256: originalHash},
+		Resolved: &TopologyBackup{File: filepath.Base(c.TopoPaths.ResolvedTopologyFileAbsPath()), SHA256: resolvedHash},
+	}
+
+	return nil
+}
+
+// backupTopologyFile writes the topology copy to p, rotating the previous copy when its content differs.
+// The hash of the content is returned.
+func backupTopologyFile(p string, content []byte) (string, error) {
+	prev, err := os.ReadFile(p)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	if err == nil && !bytes.Equal(prev, content) {
+		err := utils.RotateFile(p, topologyBackups, func(n int) string {
+			return types.BackupFilePath(p, n)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := utils.WriteFileAtomic(p, content, 0644); err != nil {
+		return "", err
+	}
+
+	return contentHash(content), nil
+}
+
+// TopologyChanged returns true when the resolved topology differs from the one the lab was deployed from.
+// False is returned when the lab was deployed without the topology backups.
+func (c *CLab) TopologyChanged() (bool, error) {
+	md, err := c.readLabMetadata()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if md.Topology == nil || md.Topology.Resolved == nil {
+		return false, nil
+	}
+
+	resolved, err := c.ResolvedTopology()
+	if err != nil {
+		return false, err
+	}
+
+	return contentHash(resolved) != md.Topology.Resolved.SHA256, nil
+}
+
+// nodeConfigDirs are the directories of the node directories holding the node configs,
+// which are kept by the lab directory removal with the kept configs.
+var nodeConfigDirs = []string{"config", "flash", "tftpboot"}
+
+// RemoveLabDir removes the lab directory. The topology backups are kept unless all is set,
+// so that the topology of a previously deployed lab can still be recovered.
+// With keepConfigs the config directories of the lab nodes are kept as well,
+// so that the configs edited by the user survive the lab redeployment.
+func (c *CLab) RemoveLabDir(all, keepConfigs bool) error {
+	labDir := c.TopoPaths.TopologyLabDir()
+
+	if all && !keepConfigs {
+		return os.RemoveAll(labDir)
+	}
+
+	entries, err := os.ReadDir(labDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	nodeDirs := map[string]struct{}{}
+	if keepConfigs {
+		for _, n := range c.Nodes {
+			nodeDirs[filepath.Base(n.Config().LabDir)] = struct{}{}
+		}
+	}
+
+	kept, keptConfigs := 0, 0
+
+	for _, e := range entries {
+		if !all && c.isTopologyBackup(e.Name()) {
+			kept++
+			continue
+		}
+
+		if _, ok := nodeDirs[e.Name()]; ok && e.IsDir() {
+			ok, err := removeNodeDirKeepConfigs(filepath.Join(labDir, e.Name()))
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				keptConfigs++
+			}
+
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(labDir, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	if kept > 0 {
+		log.Infof("Kept %d topology backup(s) in %s, use --cleanup-all to remove them", kept, labDir)
+	}
+
+	if keptConfigs > 0 {
+		log.Infof("Kept the config directories of %d node(s) in %s", keptConfigs, labDir)
+	}
+
+	return nil
+}
+
+// removeNodeDirKeepConfigs removes the contents of the node directory except the config directories.
+// It returns true when any of the config directories is kept.
+func removeNodeDirKeepConfigs(nodeDir string) (bool, error) {
+	entries, err := os.ReadDir(nodeDir)
+	if err != nil {
+		return false, err
+	}
+
+	kept := false
+
+	for _, e := range entries {
+		if _, ok := utils.StringInSlice(nodeConfigDirs, e.Name()); ok && e.IsDir() {
+			kept = true
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(nodeDir, e.Name())); err != nil {
+			return false, err
+		}
+	}
+
+	return kept, nil
+}
+
+// isTopologyBackup returns true when the file of the lab directory is a topology copy or its rotated backup.
+func (c *CLab) isTopologyBackup(name string) bool {
+	for _, p := range []string{
+		c.TopoPaths.OriginalTopologyFileAbsPath(),
+		c.TopoPaths.ResolvedTopologyFileAbsPath(),
+	} {
+		if name == filepath.Base(p) {
+			return true
+		}
+
+		if ok, _ := filepath.Match(filepath.Base(types.BackupFileGlob(p)), name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contentHash returns the hex encoded SHA256 hash of the content.
+func contentHash(content []byte) string {
+	h := sha256.Sum256(content)
+
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+const backupTestTopo = `name: backup
+topology:
+  nodes:
+    l1:
+      kind: linux
+      image: alpine:3
+`
+
+// newBackupTestLab writes the topology to the topology file in the directory dir
+// and creates the lab with its lab directory.
+func newBackupTestLab(t *testing.T, dir, topo string) *CLab {
+	t.Helper()
+
+	topoFile := filepath.Join(dir, "backup.clab.yml")
+	if err := os.WriteFile(topoFile, []byte(topo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(topoFile, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(c.TopoPaths.TopologyLabDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestBackupTopologyRotation(t *testing.T) {
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+	dir := t.TempDir()
+
+	c := newBackupTestLab(t, dir, backupTestTopo)
+	if err := c.BackupTopology(); err != nil {
+		t.Fatal(err)
+	}
+
+	original := c.TopoPaths.OriginalTopologyFileAbsPath()
+	resolved := c.TopoPaths.ResolvedTopologyFileAbsPath()
+
+	b, err := os.ReadFile(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != backupTestTopo {
+		t.Errorf("got original topology copy %q, want %q", b, backupTestTopo)
+	}
+
+	if c.topologyBackups.Original.SHA256 != contentHash(b) {
+		t.Errorf("got original topology hash %s, want %s", c.topologyBackups.Original.SHA256, contentHash(b))
+	}
+
+	// the unchanged topology is not rotated
+	if err := c.BackupTopology(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(types.BackupFilePath(resolved, 1)); err == nil {
+		t.Error("the unchanged resolved topology is rotated")
+	}
+
+	// every change rotates the copies, keeping at most topologyBackups previous copies
+	for i := 2; i <= topologyBackups+2; i++ {
+		topo := backupTestTopo + fmt.Sprintf("    l%d:\n      kind: linux\n      image: alpine:3\n", i)
+
+		c := newBackupTestLab(t, dir, topo)
+		if err := c.BackupTopology(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, p := range []string{original, resolved} {
+		for n := 1; n <= topologyBackups; n++ {
+			if _, err := os.Stat(types.BackupFilePath(p, n)); err != nil {
+				t.Errorf("backup %d of %s is missing: %v", n, filepath.Base(p), err)
+			}
+		}
+
+		if _, err := os.Stat(types.BackupFilePath(p, topologyBackups+1)); err == nil {
+			t.Errorf("got more than %d backups of %s", topologyBackups, filepath.Base(p))
+		}
+	}
+
+	// the first backup is the copy of the previous topology which has node l4
+	b, err = os.ReadFile(types.BackupFilePath(original, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := backupTestTopo + "    l4:\n      kind: linux\n      image: alpine:3\n"; string(b) != want {
+		t.Errorf("got the first backup %q, want %q", b, want)
+	}
+}
+
+func TestTopologyChanged(t *testing.T) {
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+	dir := t.TempDir()
+
+	c := newBackupTestLab(t, dir, backupTestTopo)
+
+	// the lab deployed without the topology backups is never reported as changed
+	changed, err := c.TopologyChanged()
+	if err != nil || changed {
+		t.Fatalf("got changed %v and error %v for the lab without the metadata, want false and no error", changed, err)
+	}
+
+	if err := c.BackupTopology(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.WriteLabMetadata(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		topo string
+		want bool
+	}{
+		"unchanged": {
+			topo: backupTestTopo,
+			want: false,
+		},
+		"comments and formatting": {
+			topo: "# the backup lab\nname:   backup\ntopology:\n  nodes:\n    l1: {kind: linux, image: alpine:3}\n",
+			want: false,
+		},
+		"changed": {
+			topo: backupTestTopo + "      env:\n        FOO: bar\n",
+			want: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newBackupTestLab(t, dir, tt.topo)
+
+			changed, err := c.TopologyChanged()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if changed != tt.want {
+				t.Errorf("got changed %v, want %v", changed, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveLabDir(t *testing.T) {
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+	c := newBackupTestLab(t, t.TempDir(), backupTestTopo)
+	labDir := c.TopoPaths.TopologyLabDir()
+
+	files := map[string]bool{
+		// the value is true for the files kept by the cleanup
+		c.TopoPaths.OriginalTopologyFileAbsPath():                          true,
+		c.TopoPaths.ResolvedTopologyFileAbsPath():                          true,
+		types.BackupFilePath(c.TopoPaths.ResolvedTopologyFileAbsPath(), 2): true,
+		c.TopoPaths.LabMetadataFileAbsPath():                               false,
+		filepath.Join(labDir, "l1", "config.json"):                         false,
+	}
+
+	for p := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.RemoveLabDir(false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for p, kept := range files {
+		if _, err := os.Stat(p); (err == nil) != kept {
+			t.Errorf("got %s kept %v, want %v", p, err == nil, kept)
+		}
+	}
+
+	if err := c.RemoveLabDir(true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(labDir); err == nil {
+		t.Error("the lab directory is not removed with the topology backups")
+	}
+}
+
+func TestRemoveLabDirKeepConfigs(t *testing.T) {
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+	c := newBackupTestLab(t, t.TempDir(), backupTestTopo)
+	labDir := c.TopoPaths.TopologyLabDir()
+
+	files := map[string]bool{
+		// the value is true for the files kept by the cleanup
+		c.TopoPaths.ResolvedTopologyFileAbsPath():              false,
+		c.TopoPaths.LabMetadataFileAbsPath():                   false,
+		filepath.Join(labDir, "l1", "config", "startup.cfg"):   true,
+		filepath.Join(labDir, "l1", "flash", "startup-config"): true,
+		filepath.Join(labDir, "l1", "tftpboot", "config.txt"):  true,
+		filepath.Join(labDir, "l1", "interfaces.json"):         false,
+		filepath.Join(labDir, "l1", "tls", "l1.pem"):           false,
+		filepath.Join(labDir, "unknown", "config", "a.cfg"):    false,
+	}
+
+	for p := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.RemoveLabDir(true, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for p, kept := range files {
+		if _, err := os.Stat(p); (err == nil) != kept {
+			t.Errorf("got %s kept %v, want %v", p, err == nil, kept)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+// TopologySummary is the overview of a topology file found by ScanTopologies.
+type TopologySummary struct {
+	// Path is the path to the topology file relative to the scanned directory.
+	Path  string `json:"path"`
+	Name  string `json:"name,omitempty"`
+	Nodes int    `json:"nodes"`
+	Links int    `json:"links"`
+	// Error is the error of parsing the topology file, the other fields are empty when it is set.
+	Error string `json:"error,omitempty"`
+}
+
+// ScanTopologies recursively finds the *.clab.yml and *.clab.yaml topology files in the dir
+// and parses them without deploying the labs. The hidden directories are not scanned.
+// A file which fails to parse is reported with the error in its summary.
+func ScanTopologies(dir string, timeout time.Duration) ([]*TopologySummary, error) {
+	var summaries []*TopologySummary
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the scanned directory itself must be readable
+			if p == dir {
+				return err
+			}
+
+			summaries = append(summaries, &TopologySummary{Path: relPath(dir, p), Error: err.Error()})
+
+			return nil
+		}
+
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !isTopologyFileName(d.Name()) {
+			return nil
+		}
+
+		s := &TopologySummary{Path: relPath(dir, p)}
+
+		cfg, err := parseTopologyFile(p, timeout)
+		if err != nil {
+			s.Error = err.Error()
+		} else {
+			s.Name = cfg.Name
+			s.Nodes = len(cfg.Topology.Nodes)
+			s.Links = len(cfg.Topology.Links)
+		}
+
+		summaries = append(summaries, s)
+
+		return nil
+	})
+
+	return summaries, err
+}
+
+// isTopologyFileName returns true for the names of the topology files, the hidden files are skipped.
+func isTopologyFileName(name string) bool {
+	return !strings.HasPrefix(name, ".") &&
+		(strings.HasSuffix(name, ".clab.yml") || strings.HasSuffix(name, ".clab.yaml"))
+}
+
+// relPath returns the path relative to the dir, or the path itself when it is not within the dir.
+func relPath(dir, p string) string {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return p
+	}
+
+	return rel
+}
+
+// parseTopologyFile parses the topology file the way it is parsed on deploy,
+// but without writing the rendered topology next to the file.
+func parseTopologyFile(file string, timeout time.Duration) (*Config, error) {
+	c := &CLab{
+		Config:                &Config{Topology: types.NewTopology()},
+		timeout:               timeout,
+		skipRenderedTopoWrite: true,
+	}
+
+	var err error
+
+	c.TopoPaths, err = types.NewTopoPaths(file)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := c.renderTopology("")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.UnmarshalStrict(b, c.Config); err != nil {
+		return nil, err
+	}
+
+	return c.Config, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScanTopologies(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"lab1.clab.yml": `name: lab1
+topology:
+  nodes:
+    n1:
+      kind: linux
+    n2:
+      kind: linux
+  links:
+    - endpoints: ["n1:eth1", "n2:eth1"]
+`,
+		"labs/lab2.clab.yaml": `name: lab2
+topology:
+  nodes:
+    n1:
+      kind: linux
+`,
+		"labs/broken.clab.yml": `name: broken
+topology:
+  nodez: {}
+`,
+		"labs/notes.yml":            "name: not-a-topology\n",
+		".git/hidden.clab.yml":      "name: hidden\n",
+		"labs/.lab2.clab.yaml.bak":  "name: backup\n",
+		"labs/.hidden-lab.clab.yml": "name: hidden\n",
+	}
+
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := ScanTopologies(dir, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("got %d topologies, want 3", len(got))
+	}
+
+	// the parse error is reported without aborting the scan
+	if got[0].Path != "lab1.clab.yml" || got[1].Path != "labs/broken.clab.yml" || got[1].Error == "" {
+		t.Errorf("unexpected scan results: %+v %+v", got[0], got[1])
+	}
+
+	got[1].Error = ""
+
+	want := []*TopologySummary{
+		{Path: "lab1.clab.yml", Name: "lab1", Nodes: 2, Links: 1},
+		{Path: "labs/broken.clab.yml"},
+		{Path: "labs/lab2.clab.yaml", Name: "lab2", Nodes: 1},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("topologies mismatch (-want +got):\n%s", d)
+	}
+
+	// the rendered topology is not written next to the scanned files
+	if _, err := os.Stat(filepath.Join(dir, ".lab1.clab.yml.bak")); err == nil {
+		t.Error("the rendered topology is written to the scanned directory")
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// LabUsage is the host resources used by the lab.
+// The values which couldn't be probed are nil and reported as "n/a".
+type LabUsage struct {
+	Nodes []*NodeUsage `json:"nodes"`
+	// ImagesSizeBytes is the total size of the unique images used by the lab nodes.
+	ImagesSizeBytes *int64 `json:"images-size-bytes"`
+	UniqueImages    int    `json:"unique-images"`
+	// Veths is the number of the veth links created for the lab.
+	Veths    int          `json:"veths"`
+	MgmtIPv4 *SubnetUsage `json:"mgmt-ipv4"`
+}
+
+// NodeUsage is the resource usage of a lab node container.
+type NodeUsage struct {
+	Name        string   `json:"name"`
+	Image       string   `json:"image"`
+	MemoryBytes *uint64  `json:"memory-bytes"`
+	CPUPercent  *float64 `json:"cpu-percent"`
+}
+
+// SubnetUsage is the number of the addresses of the subnet assigned to the lab nodes
+// out of the usable host addresses of the subnet.
+type SubnetUsage struct {
+	Subnet   string `json:"subnet"`
+	Used     int    `json:"used"`
+	Capacity int    `json:"capacity"`
+}
+
+// LabMetadata is the content of the lab metadata file written to the lab directory after the deployment.
+type LabMetadata struct {
+	Name       string    `json:"name"`
+	DeployedAt time.Time `json:"deployed-at"`
+	Usage      *LabUsage `json:"usage"`
+	// Licenses are the license pool files assigned to the nodes, keyed by the node name.
+	// The assignment is kept across the lab redeployments.
+	Licenses map[string]string `json:"licenses,omitempty"`
+	// Topology references the copies of the topology the lab was deployed from.
+	Topology *TopologyBackups `json:"topology,omitempty"`
+	// Mgmt is the management network of the lab with the gateways assigned by the runtime.
+	Mgmt *types.MgmtNet `json:"mgmt,omitempty"`
+	// Nodes are the containers of the lab nodes and their management addresses, ordered by the node name.
+	Nodes []*NodeMetadata `json:"nodes,omitempty"`
+	// Links are the deployment statuses of the lab links, ordered by the link index.
+	// Nil for the labs deployed by the versions without the link status tracking.
+	Links []*LinkState `json:"links"`
+	// Timings are the durations of the deployment phases of the lab nodes, ordered by the node name.
+	Timings []*NodeTimings `json:"timings,omitempty"`
+}
+
+// NodeMetadata is the container and the management addresses of a deployed lab node.
+type NodeMetadata struct {
+	Name      string `json:"name"`
+	Container string `json:"container"`
+	MgmtIPv4  string `json:"mgmt-ipv4,omitempty"`
+	MgmtIPv6  string `json:"mgmt-ipv6,omitempty"`
+}
+
+// CollectLabUsage collects the resource usage of the lab nodes, the size of the images they use,
+// the number of the veth links and the management subnet utilization.
+// The failed probes are logged and their values are left unset.
+func (c *CLab) CollectLabUsage(ctx context.Context) *LabUsage {
+	u := &LabUsage{
+		Nodes:    make([]*NodeUsage, 0, len(c.Nodes)),
+		MgmtIPv4: c.mgmtIPv4Usage(),
+	}
+
+	for _, l := range c.Links {
+		switch l.GetType() {
+		case links.LinkTypeVEth:
+			u.Veths++
+		case links.LinkTypeBond:
+			u.Veths += len(l.(*links.LinkBond).Members)
+		}
+	}
+
+	var wg sync.WaitGroup
+	mu := new(sync.Mutex)
+
+	wg.Add(len(c.Nodes))
+	for _, n := range c.Nodes {
+		go func(n nodes.Node) {
+			defer wg.Done()
+
+			nu := &NodeUsage{
+				Name:  n.Config().ShortName,
+				Image: n.Config().Image,
+			}
+
+			stats, err := n.GetRuntime().GetContainerStats(ctx, n.Config().LongName)
+			if err != nil {
+				log.Debugf("failed to get resource usage of node %s: %v", nu.Name, err)
+			} else {
+				nu.MemoryBytes = &stats.MemoryUsage
+				nu.CPUPercent = &stats.CPUPercent
+			}
+
+			mu.Lock()
+			u.Nodes = append(u.Nodes, nu)
+			mu.Unlock()
+		}(n)
+	}
+	wg.Wait()
+
+	sort.Slice(u.Nodes, func(i, j int) bool { return u.Nodes[i].Name < u.Nodes[j].Name })
+
+	u.ImagesSizeBytes, u.UniqueImages = c.imagesSize(ctx)
+
+	return u
+}
+
+// imagesSize returns the total size of the images used by the lab nodes and the number of the unique images.
+// Images referenced by different names but having the same ID are counted once.
+// The size is nil if any of the images failed to be inspected.
+func (c *CLab) imagesSize(ctx context.Context) (*int64, int) {
+	sizes := map[string]int64{}
+	// inspected holds the image names already inspected
+	inspected := map[string]struct{}{}
+	failed := false
+
+	for _, n := range c.Nodes {
+		image := n.Config().Image
+		if image == "" {
+			continue
+		}
+
+		if _, ok := inspected[image]; ok {
+			continue
+		}
+
+		inspected[image] = struct{}{}
+
+		info, err := n.GetRuntime().InspectImage(ctx, image)
+		if err != nil {
+			log.Debugf("failed to inspect image %s: %v", image, err)
+			failed = true
+
+			continue
+		}
+
+		sizes[info.ID] = info.Size
+	}
+
+	if failed {
+		return nil, len(sizes)
+	}
+
+	var total int64
+	for _, s := range sizes {
+		total += s
+	}
+
+	return &total, len(sizes)
+}
+
+// mgmtIPv4Usage returns the number of the lab nodes management addresses in the management IPv4 subnet.
+// Nil is returned when the lab has no IPv4 management subnet.
+func (c *CLab) mgmtIPv4Usage() *SubnetUsage {
+	if c.Config == nil || c.Config.Mgmt == nil {
+		return nil
+	}
+
+	_, subnet, err := net.ParseCIDR(c.Config.Mgmt.IPv4Subnet)
+	if err != nil {
+		return nil
+	}
+
+	ones, bits := subnet.Mask.Size()
+
+	su := &SubnetUsage{
+		Subnet: subnet.String(),
+		// network and broadcast addresses are not usable
+		Capacity: 1<<(bits-ones) - 2,
+	}
+
+	if su.Capacity < 0 {
+		su.Capacity = 0
+	}
+
+	for _, n := range c.Nodes {
+		ip := net.ParseIP(n.Config().MgmtIPv4Address)
+		if ip != nil && subnet.Contains(ip) {
+			su.Used++
+		}
+	}
+
+	return su
+}
+
+// WriteLabMetadata writes the lab metadata file with the lab resource usage to the lab directory.
+func (c *CLab) WriteLabMetadata(u *LabUsage) error {
+	b, err := json.MarshalIndent(LabMetadata{
+		Name:       c.Config.Name,
+		DeployedAt: time.Now(),
+		Usage:      u,
+		Licenses:   c.licenseAssignments,
+		Topology:   c.topologyBackups,
+		Mgmt:       c.Config.Mgmt,
+		Nodes:      c.nodesMetadata(),
+		Links:      c.LinkStates(),
+		Timings:    c.NodeTimings(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(c.TopoPaths.LabMetadataFileAbsPath(), append(b, '\n'), 0644)
+}
+
+// nodesMetadata returns the metadata of the lab nodes ordered by the node name.
+func (c *CLab) nodesMetadata() []*NodeMetadata {
+	md := make([]*NodeMetadata, 0, len(c.Nodes))
+
+	for _, name := range c.sortedNodeNames() {
+		cfg := c.Nodes[name].Config()
+
+		md = append(md, &NodeMetadata{
+			Name:      cfg.ShortName,
+			Container: cfg.LongName,
+			MgmtIPv4:  cfg.MgmtIPv4Address,
+			MgmtIPv6:  cfg.MgmtIPv6Address,
+		})
+	}
+
+	return md
+}
+
+// readLabMetadata reads the lab metadata file written by the previous deployment of the lab.
+func (c *CLab) readLabMetadata() (*LabMetadata, error) {
+	return readLabMetadataFile(c.TopoPaths.LabMetadataFileAbsPath())
+}
+
+// readLabMetadataFile reads the lab metadata file at the path.
+func readLabMetadataFile(path string) (*LabMetadata, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &LabMetadata{}
+	if err := json.Unmarshal(b, md); err != nil {
+		return nil, fmt.Errorf("failed to parse the lab metadata file: %w", err)
+	}
+
+	return md, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestCollectLabUsage(t *testing.T) {
+	type node struct {
+		image string
+		ip    string
+		stats *runtime.ContainerStats
+	}
+
+	tests := map[string]struct {
+		nodes map[string]node
+		// images maps the image names to the inspect results, missing images fail to be inspected
+		images     map[string]*runtime.ImageInfo
+		wantImages *int64
+		wantUnique int
+		wantUsed   int
+	}{
+		"shared-images": {
+			nodes: map[string]node{
+				"srl1":    {image: "srl:23.10", ip: "172.20.20.2", stats: &runtime.ContainerStats{MemoryUsage: 100, CPUPercent: 1.5}},
+				"srl2":    {image: "srl:23.10", ip: "172.20.20.3", stats: &runtime.ContainerStats{MemoryUsage: 200, CPUPercent: 2.5}},
+				"srl3":    {image: "srl:latest", ip: "172.20.20.4", stats: &runtime.ContainerStats{MemoryUsage: 300}},
+				"client":  {image: "alpine", ip: "10.0.0.2", stats: &runtime.ContainerStats{MemoryUsage: 5}},
+				"failing": {image: "alpine"},
+			},
+			images: map[string]*runtime.ImageInfo{
+				// srl:latest is another name of srl:23.10 image, its size is counted once
+				"srl:23.10":  {ID: "sha256:srl", Size: 1000},
+				"srl:latest": {ID: "sha256:srl", Size: 1000},
+				"alpine":     {ID: "sha256:alpine", Size: 10},
+			},
+			wantImages: int64Ptr(1010),
+			wantUnique: 2,
+			wantUsed:   3,
+		},
+		"image-inspect-failure": {
+			nodes: map[string]node{
+				"srl1":   {image: "srl:23.10", ip: "172.20.20.2"},
+				"client": {image: "alpine", ip: "172.20.20.3"},
+				// nodes without image are not inspected
+				"br": {},
+			},
+			images: map[string]*runtime.ImageInfo{
+				"alpine": {ID: "sha256:alpine", Size: 10},
+			},
+			wantUnique: 1,
+			wantUsed:   2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			images := map[string]struct{}{}
+			for _, n := range tt.nodes {
+				if n.image != "" {
+					images[n.image] = struct{}{}
+				}
+			}
+
+			rt := mockruntime.NewMockContainerRuntime(ctrl)
+			rt.EXPECT().GetContainerStats(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, cID string) (*runtime.ContainerStats, error) {
+					if s := tt.nodes[cID].stats; s != nil {
+						return s, nil
+					}
+					return nil, errors.New("no stats")
+				}).AnyTimes()
+			// every image is inspected once
+			rt.EXPECT().InspectImage(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, image string) (*runtime.ImageInfo, error) {
+					if info, ok := tt.images[image]; ok {
+						return info, nil
+					}
+					return nil, errors.New("no such image")
+				}).Times(len(images))
+
+			c := &CLab{
+				Config: &Config{Mgmt: &types.MgmtNet{IPv4Subnet: "172.20.20.0/24"}},
+				Nodes:  map[string]nodes.Node{},
+				Links: map[int]links.Link{
+					0: &links.LinkVEth{},
+					1: &links.LinkVEth{},
+					2: &links.LinkMacVlan{},
+				},
+			}
+
+			for name, n := range tt.nodes {
+				mn := mocknodes.NewMockNode(ctrl)
+				mn.EXPECT().Config().Return(&types.NodeConfig{
+					ShortName:       name,
+					LongName:        name,
+					Image:           n.image,
+					MgmtIPv4Address: n.ip,
+				}).AnyTimes()
+				mn.EXPECT().GetRuntime().Return(rt).AnyTimes()
+
+				c.Nodes[name] = mn
+			}
+
+			u := c.CollectLabUsage(context.Background())
+
+			if len(u.Nodes) != len(tt.nodes) {
+				t.Fatalf("got %d nodes, want %d", len(u.Nodes), len(tt.nodes))
+			}
+
+			for i, nu := range u.Nodes {
+				if i > 0 && u.Nodes[i-1].Name > nu.Name {
+					t.Errorf("nodes are not sorted: %s before %s", u.Nodes[i-1].Name, nu.Name)
+				}
+
+				want := tt.nodes[nu.Name].stats
+				switch {
+				case want == nil && (nu.MemoryBytes != nil || nu.CPUPercent != nil):
+					t.Errorf("node %s: got usage for the failed stats probe", nu.Name)
+				case want != nil && (nu.MemoryBytes == nil || *nu.MemoryBytes != want.MemoryUsage ||
+					nu.CPUPercent == nil || *nu.CPUPercent != want.CPUPercent):
+					t.Errorf("node %s: got usage %v/%v, want %d/%v", nu.Name, nu.MemoryBytes, nu.CPUPercent,
+						want.MemoryUsage, want.CPUPercent)
+				}
+			}
+
+			switch {
+			case tt.wantImages == nil && u.ImagesSizeBytes != nil:
+				t.Errorf("got images size %d, want n/a", *u.ImagesSizeBytes)
+			case tt.wantImages != nil && (u.ImagesSizeBytes == nil || *u.ImagesSizeBytes != *tt.wantImages):
+				t.Errorf("got images size %v, want %d", u.ImagesSizeBytes, *tt.wantImages)
+			}
+
+			if u.UniqueImages != tt.wantUnique {
+				t.Errorf("got %d unique images, want %d", u.UniqueImages, tt.wantUnique)
+			}
+
+			if u.Veths != 2 {
+				t.Errorf("got %d veths, want 2", u.Veths)
+			}
+
+			want := SubnetUsage{Subnet: "172.20.20.0/24", Used: tt.wantUsed, Capacity: 254}
+			if u.MgmtIPv4 == nil || *u.MgmtIPv4 != want {
+				t.Errorf("got mgmt subnet usage %+v, want %+v", u.MgmtIPv4, want)
+			}
+		})
+	}
+}
+
+func TestMgmtIPv4Usage(t *testing.T) {
+	tests := map[string]struct {
+		subnet string
+		want   *SubnetUsage
+	}{
+		"slash-28": {
+			subnet: "10.0.0.0/28",
+			want:   &SubnetUsage{Subnet: "10.0.0.0/28", Capacity: 14},
+		},
+		"slash-32": {
+			subnet: "10.0.0.1/32",
+			want:   &SubnetUsage{Subnet: "10.0.0.1/32"},
+		},
+		"ipv6-only": {},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CLab{Config: &Config{Mgmt: &types.MgmtNet{IPv4Subnet: tt.subnet}}}
+
+			got := c.mgmtIPv4Usage()
+
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/vishvananda/netlink"
+)
+
+// endpointIPs returns the global unicast IP addresses assigned to the interface of a node.
+// It is a variable to allow tests to stub the netns lookup.
+var endpointIPs = func(n nodes.Node, ifName string) ([]*net.IPNet, error) {
+	var ips []*net.IPNet
+
+	err := n.ExecFunction(func(_ ns.NetNS) error {
+		l, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return err
+		}
+
+		addrs, err := netlink.AddrList(l, netlink.FAMILY_ALL)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range addrs {
+			if a.IP.IsGlobalUnicast() {
+				ips = append(ips, a.IPNet)
+			}
+		}
+
+		return nil
+	})
+
+	return ips, err
+}
+
+// VerifyLinksConnectivity pings the remote endpoint of every veth link from the local endpoint
+// when both endpoints have IP addresses assigned. Links without IP addresses, and links
+// connected to nodes that don't support exec are skipped.
+// The returned error joins all connectivity failures.
+func (c *CLab) VerifyLinksConnectivity(ctx context.Context) error {
+	var errs []error
+
+	for _, l := range c.Links {
+		if l.GetType() != links.LinkTypeVEth {
+			continue
+		}
+
+		if err := c.verifyLinkConnectivity(ctx, l); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyLinkConnectivity pings the address of the second link endpoint from the first one.
+func (c *CLab) verifyLinkConnectivity(ctx context.Context, l links.Link) error {
+	eps := l.GetEndpoints()
+	if len(eps) != 2 {
+		return nil
+	}
+
+	src, dst := eps[0], eps[1]
+	linkName := fmt.Sprintf("%s:%s <-> %s:%s", src.GetNode().GetShortName(), src.GetIfaceName(),
+		dst.GetNode().GetShortName(), dst.GetIfaceName())
+
+	srcNode, ok := c.Nodes[src.GetNode().GetShortName()]
+	if !ok {
+		return nil
+	}
+
+	dstNode, ok := c.Nodes[dst.GetNode().GetShortName()]
+	if !ok {
+		return nil
+	}
+
+	srcIPs, err := endpointIPs(srcNode, src.GetIfaceName())
+	if err != nil {
+		log.Debugf("skipping connectivity check for link %s: %v", linkName, err)
+		return nil
+	}
+
+	dstIPs, err := endpointIPs(dstNode, dst.GetIfaceName())
+	if err != nil {
+		log.Debugf("skipping connectivity check for link %s: %v", linkName, err)
+		return nil
+	}
+
+	target := pingTarget(srcIPs, dstIPs)
+	if target == nil {
+		log.Debugf("skipping connectivity check for link %s: endpoints have no IP addresses of the same family", linkName)
+		return nil
+	}
+
+	cmd := exec.NewExecCmdFromSlice([]string{"ping", "-c", "3", "-W", "1", "-I", src.GetIfaceName(), target.String()})
+
+	res, err := srcNode.RunExec(ctx, cmd)
+	if err != nil {
+		log.Debugf("skipping connectivity check for link %s: %v", linkName, err)
+		return nil
+	}
+
+	if res.GetReturnCode() != 0 {
+		return fmt.Errorf("link %s: %s can't reach %s: %s", linkName, src.GetNode().GetShortName(), target,
+			res.GetStdErrString())
+	}
+
+	log.Infof("link %s: connectivity verified", linkName)
+
+	return nil
+}
+
+// pingTarget returns the first destination IP that has the same address family as one of the source IPs.
+// Destination IPs that share the subnet with the source IPs are preferred.
+func pingTarget(srcIPs, dstIPs []*net.IPNet) net.IP {
+	var sameFamily net.IP
+
+	for _, s := range srcIPs {
+		for _, d := range dstIPs {
+			if (s.IP.To4() == nil) != (d.IP.To4() == nil) {
+				continue
+			}
+
+			if s.Contains(d.IP) {
+				return d.IP
+			}
+
+			if sameFamily == nil {
+				sameFamily = d.IP
+			}
+		}
+	}
+
+	return sameFamily
+}
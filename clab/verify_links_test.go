@@ -0,0 +1,158 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+func mustParseCIDRs(t *testing.T, cidrs ...string) []*net.IPNet {
+	t.Helper()
+
+	res := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		ip, n, err := net.ParseCIDR(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n.IP = ip
+		res = append(res, n)
+	}
+
+	return res
+}
+
+func TestPingTarget(t *testing.T) {
+	tests := map[string]struct {
+		src  []string
+		dst  []string
+		want string
+	}{
+		"same-subnet": {
+			src:  []string{"10.0.0.1/30"},
+			dst:  []string{"192.168.0.1/24", "10.0.0.2/30"},
+			want: "10.0.0.2",
+		},
+		"different-subnet-same-family": {
+			src:  []string{"10.0.0.1/30"},
+			dst:  []string{"192.168.0.1/24"},
+			want: "192.168.0.1",
+		},
+		"ipv6": {
+			src:  []string{"2001:db8::1/64"},
+			dst:  []string{"10.0.0.2/30", "2001:db8::2/64"},
+			want: "2001:db8::2",
+		},
+		"different-family": {
+			src: []string{"10.0.0.1/30"},
+			dst: []string{"2001:db8::2/64"},
+		},
+		"no-dst-ips": {
+			src: []string{"10.0.0.1/30"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := pingTarget(mustParseCIDRs(t, tt.src...), mustParseCIDRs(t, tt.dst...))
+
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("got %s, want no target", got)
+				}
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyLinksConnectivity(t *testing.T) {
+	tests := map[string]struct {
+		ips        map[string][]string
+		returnCode int
+		wantExec   bool
+		wantErr    bool
+	}{
+		"reachable": {
+			ips: map[string][]string{
+				"node1:eth1": {"10.0.0.1/30"},
+				"node2:eth1": {"10.0.0.2/30"},
+			},
+			wantExec: true,
+		},
+		"unreachable": {
+			ips: map[string][]string{
+				"node1:eth1": {"10.0.0.1/30"},
+				"node2:eth1": {"10.0.0.2/30"},
+			},
+			returnCode: 1,
+			wantExec:   true,
+			wantErr:    true,
+		},
+		"no-ips": {
+			ips: map[string][]string{
+				"node1:eth1": {"10.0.0.1/30"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			origEndpointIPs := endpointIPs
+			t.Cleanup(func() { endpointIPs = origEndpointIPs })
+
+			endpointIPs = func(n nodes.Node, ifName string) ([]*net.IPNet, error) {
+				return mustParseCIDRs(t, tt.ips[n.GetShortName()+":"+ifName]...), nil
+			}
+
+			node1 := mocknodes.NewMockNode(ctrl)
+			node1.EXPECT().GetShortName().Return("node1").AnyTimes()
+			node2 := mocknodes.NewMockNode(ctrl)
+			node2.EXPECT().GetShortName().Return("node2").AnyTimes()
+
+			l := links.NewLinkVEth()
+			l.Endpoints = append(l.Endpoints,
+				links.NewEndpointVeth(links.NewEndpointGeneric(node1, "eth1", l)),
+				links.NewEndpointVeth(links.NewEndpointGeneric(node2, "eth1", l)),
+			)
+
+			c := &CLab{
+				Nodes: map[string]nodes.Node{"node1": node1, "node2": node2},
+				Links: map[int]links.Link{0: l},
+			}
+
+			if tt.wantExec {
+				node1.EXPECT().RunExec(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+						if got := cmd.GetCmdString(); got != "ping -c 3 -W 1 -I eth1 10.0.0.2" {
+							t.Errorf("unexpected ping command %q", got)
+						}
+						res := exec.NewExecResult(cmd)
+						res.SetReturnCode(tt.returnCode)
+						return res, nil
+					})
+			}
+
+			err := c.VerifyLinksConnectivity(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
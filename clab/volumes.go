@@ -0,0 +1,95 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// nodeVolumes returns the names of the named volumes used in the binds of the node.
+func nodeVolumes(n nodes.Node) []string {
+	var vols []string
+
+	for _, bind := range n.Config().Binds {
+		b, err := types.NewBind(bind)
+		if err != nil || !b.IsVolume() {
+			continue
+		}
+
+		vols = append(vols, b.Src())
+	}
+
+	return vols
+}
+
+// CreateVolumes creates the named volumes used in the binds of the lab nodes.
+// The volumes are labeled with the lab name, so that they can be removed on destroy.
+// Existing volumes are used as is.
+func (c *CLab) CreateVolumes(ctx context.Context) error {
+	for _, n := range c.Nodes {
+		for _, v := range nodeVolumes(n) {
+			err := n.GetRuntime().CreateVolume(ctx, v, map[string]string{
+				labels.Containerlab: c.Config.Name,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create volume %q for node %q: %w", v, n.Config().ShortName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteVolumes deletes the named volumes used in the binds of the lab nodes.
+// Only the volumes created by containerlab for this lab are deleted,
+// the volumes that existed before the lab was deployed are kept.
+func (c *CLab) DeleteVolumes(ctx context.Context) {
+	// lab volumes grouped by the runtime of the nodes using them
+	used := map[runtime.ContainerRuntime]map[string]struct{}{}
+
+	for _, n := range c.Nodes {
+		rt := n.GetRuntime()
+
+		for _, v := range nodeVolumes(n) {
+			if used[rt] == nil {
+				used[rt] = map[string]struct{}{}
+			}
+
+			used[rt][v] = struct{}{}
+		}
+	}
+
+	filter := []*types.GenericFilter{{
+		FilterType: "label", Field: labels.Containerlab,
+		Operator: "=", Match: c.Config.Name,
+	}}
+
+	for rt, vols := range used {
+		labVols, err := rt.ListVolumes(ctx, filter)
+		if err != nil {
+			log.Errorf("failed to list the volumes of lab %s: %v", c.Config.Name, err)
+			continue
+		}
+
+		for _, v := range labVols {
+			if _, ok := vols[v]; !ok {
+				continue
+			}
+
+			log.Infof("Removing volume %s", v)
+
+			if err := rt.DeleteVolume(ctx, v); err != nil {
+				log.Errorf("failed to remove volume %s: %v", v, err)
+			}
+		}
+	}
+}
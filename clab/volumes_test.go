@@ -0,0 +1,91 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func newVolumesLab(ctrl *gomock.Controller, rt runtime.ContainerRuntime, binds map[string][]string) *CLab {
+	c := &CLab{
+		Config: &Config{Name: "vols"},
+		Nodes:  map[string]nodes.Node{},
+	}
+
+	for name, b := range binds {
+		n := mocknodes.NewMockNode(ctrl)
+		n.EXPECT().Config().Return(&types.NodeConfig{ShortName: name, Binds: b}).AnyTimes()
+		n.EXPECT().GetRuntime().Return(rt).AnyTimes()
+
+		c.Nodes[name] = n
+	}
+
+	return c
+}
+
+func TestCreateVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+	c := newVolumesLab(ctrl, rt, map[string][]string{
+		"n1": {"/opt/configs:/configs", "n1-data:/data:rw"},
+		"n2": {"shared:/shared"},
+	})
+
+	lbls := map[string]string{labels.Containerlab: "vols"}
+	rt.EXPECT().CreateVolume(gomock.Any(), "n1-data", lbls).Return(nil)
+	rt.EXPECT().CreateVolume(gomock.Any(), "shared", lbls).Return(nil)
+
+	if err := c.CreateVolumes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+	c := newVolumesLab(ctrl, rt, map[string][]string{
+		"n1": {"/opt/configs:/configs", "n1-data:/data"},
+		// shared volume existed before the lab was deployed, so it has no lab label
+		"n2": {"shared:/shared", "n2-data:/data"},
+		"n3": {"/opt/n3:/data"},
+	})
+
+	// stale is a volume of the lab that is no longer used by the lab nodes
+	rt.EXPECT().ListVolumes(gomock.Any(), []*types.GenericFilter{{
+		FilterType: "label", Field: labels.Containerlab,
+		Operator: "=", Match: "vols",
+	}}).Return([]string{"n1-data", "n2-data", "stale"}, nil)
+
+	rt.EXPECT().DeleteVolume(gomock.Any(), "n1-data").Return(nil)
+	rt.EXPECT().DeleteVolume(gomock.Any(), "n2-data").Return(nil)
+
+	c.DeleteVolumes(context.Background())
+}
+
+func TestDeleteVolumesWithoutVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// the runtime is not called when no named volumes are used
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+	c := newVolumesLab(ctrl, rt, map[string][]string{
+		"n1": {"/opt/configs:/configs"},
+	})
+
+	c.DeleteVolumes(context.Background())
+}
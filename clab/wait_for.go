@@ -0,0 +1,125 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/dependency_manager"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// nodeWaiters returns the sorted names of the lab nodes waiting for the node in the phase.
+func (c *CLab) nodeWaiters(nodeName string, phase types.WaitForPhase) []string {
+	var waiters []string
+
+	for name, n := range c.Nodes {
+		for _, w := range n.Config().WaitFor {
+			if w.Node == nodeName && w.Phase == phase {
+				waiters = append(waiters, name)
+				break
+			}
+		}
+	}
+
+	sort.Strings(waiters)
+
+	return waiters
+}
+
+// signalWaitedPhases signals the dependency manager once the created node reaches the configured
+// and the exit phases, if other nodes wait for it in these phases. The node waited for in the configured
+// phase is post-deployed right away, before the rest of the lab is created.
+// The wait for the node to exit is limited by the healthcheck timeout of the node.
+// The nodes whose containers are not created, as only the container specs are dumped,
+// reach the phases at once.
+func (c *CLab) signalWaitedPhases(ctx context.Context, node nodes.Node, dm dependency_manager.DependencyManager) {
+	name := node.Config().ShortName
+
+	if waiters := c.nodeWaiters(name, types.WaitForPhaseConfigured); len(waiters) > 0 {
+		if !c.dumpSpec && !c.skipPostDeploy {
+			log.Infof("node %q is post-deployed before nodes %s waiting for it to be configured",
+				name, strings.Join(waiters, ", "))
+
+			if err := node.UpdateConfigWithRuntimeInfo(ctx); err != nil {
+				log.Errorf("failed to update node runtime information for node %s: %v", name, err)
+			}
+
+			if err := c.PostDeployNode(ctx, node); err != nil {
+				log.Warnf("nodes %s waiting for node %q to be configured are deployed regardless: %v",
+					strings.Join(waiters, ", "), name, err)
+			}
+		}
+
+		dm.SignalDone(name, dependency_manager.NodeStateConfigured)
+	}
+
+	waiters := c.nodeWaiters(name, types.WaitForPhaseExit)
+	if len(waiters) == 0 {
+		return
+	}
+
+	if c.dumpSpec {
+		dm.SignalDone(name, dependency_manager.NodeStateExited)
+		return
+	}
+
+	interval := c.healthcheckInterval
+	if interval <= 0 {
+		interval = defaultHealthcheckInterval
+	}
+
+	timeout := c.healthcheckTimeout(name)
+
+	log.Infof("nodes %s wait up to %s for node %q to exit", strings.Join(waiters, ", "), timeout, name)
+
+	go func() {
+		// the waiting nodes are released on timeout and cancellation too, so that they don't block the deployment
+		if err := runtime.WaitForContainerExited(ctx, node.GetRuntime(), node.Config().LongName,
+			strings.Join(waiters, ", "), timeout, interval); err != nil {
+			log.Warnf("nodes %s stop waiting for node %q to exit: %v", strings.Join(waiters, ", "), name, err)
+		}
+
+		dm.SignalDone(name, dependency_manager.NodeStateExited)
+	}()
+}
+
+// PostDeployNode runs the post-deploy phase of the node, unless it has already run,
+// e.g. for the node other nodes wait for in the configured phase.
+func (c *CLab) PostDeployNode(ctx context.Context, node nodes.Node) error {
+	name := node.Config().ShortName
+
+	c.postDeployedMu.Lock()
+	if c.postDeployed[name] {
+		c.postDeployedMu.Unlock()
+		return nil
+	}
+
+	if c.postDeployed == nil {
+		c.postDeployed = map[string]bool{}
+	}
+
+	c.postDeployed[name] = true
+	c.postDeployedMu.Unlock()
+
+	c.NotifyNodePhase(name, NodePhasePostDeploy, nil)
+
+	if err := node.PostDeploy(ctx, &nodes.PostDeployParams{Nodes: c.Nodes}); err != nil {
+		err = fmt.Errorf("failed post-deploy phase: %w", err)
+		c.NotifyNodePhase(name, NodePhaseFailed, err)
+
+		return err
+	}
+
+	c.NotifyNodePhase(name, NodePhaseHealthy, nil)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab/dependency_manager"
+	"github.com/srl-labs/containerlab/mocks"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestNodeWaiters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	c := &CLab{Nodes: map[string]nodes.Node{}}
+
+	for name, waitFor := range map[string][]string{
+		"router":  nil,
+		"client1": {"router:configured", "job:exit"},
+		"client2": {"router:configured", "router"},
+		"client3": {"router"},
+	} {
+		cfg := &types.NodeConfig{ShortName: name}
+		for _, w := range waitFor {
+			cfg.WaitFor = append(cfg.WaitFor, types.ParseWaitFor(w))
+		}
+
+		mn := mocknodes.NewMockNode(ctrl)
+		mn.EXPECT().Config().Return(cfg).AnyTimes()
+		c.Nodes[name] = mn
+	}
+
+	if d := cmp.Diff([]string{"client1", "client2"}, c.nodeWaiters("router", types.WaitForPhaseConfigured)); d != "" {
+		t.Errorf("configured waiters mismatch (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]string{"client1"}, c.nodeWaiters("job", types.WaitForPhaseExit)); d != "" {
+		t.Errorf("exit waiters mismatch (-want +got):\n%s", d)
+	}
+
+	if got := c.nodeWaiters("router", types.WaitForPhaseExit); len(got) != 0 {
+		t.Errorf("got exit waiters %v, want none", got)
+	}
+}
+
+func TestSignalWaitedPhasesDumpSpec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	router := mocknodes.NewMockNode(ctrl)
+	router.EXPECT().Config().Return(&types.NodeConfig{ShortName: "router"}).AnyTimes()
+
+	client := mocknodes.NewMockNode(ctrl)
+	client.EXPECT().Config().Return(&types.NodeConfig{
+		ShortName: "client",
+		WaitFor:   []types.WaitFor{types.ParseWaitFor("router:configured"), types.ParseWaitFor("router:exit")},
+	}).AnyTimes()
+
+	c := &CLab{Nodes: map[string]nodes.Node{"router": router, "client": client}, dumpSpec: true}
+
+	// the containers are not created, so the router is neither post-deployed nor waited for to exit
+	dm := mocks.NewMockDependencyManager(ctrl)
+	dm.EXPECT().SignalDone("router", dependency_manager.NodeStateConfigured)
+	dm.EXPECT().SignalDone("router", dependency_manager.NodeStateExited)
+
+	c.signalWaitedPhases(context.Background(), router, dm)
+}
+
+func TestPostDeployNodeOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	n := mocknodes.NewMockNode(ctrl)
+	n.EXPECT().Config().Return(&types.NodeConfig{ShortName: "router"}).AnyTimes()
+	n.EXPECT().PostDeploy(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	c := &CLab{Nodes: map[string]nodes.Node{"router": n}}
+
+	for i := 0; i < 2; i++ {
+		if err := c.PostDeployNode(context.Background(), n); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	// configDiffNodes is the list of nodes to detect the configuration drift of.
+	configDiffNodes []string
+	// configDiffExitCode makes the command exit with a non-zero code when a drift is detected.
+	configDiffExitCode bool
+)
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "compare the running configs of the lab nodes with the startup configs rendered from the topology",
+	Long: "re-render the nodes' startup configs from the topology and compare them with the running configs " +
+		"retrieved from the nodes\nreference: https://containerlab.dev/cmd/config/diff/",
+	PreRunE:      sudoCheck,
+	SilenceUsage: true,
+	RunE:         configDiffFn,
+}
+
+func init() {
+	configCmd.AddCommand(configDiffCmd)
+	configDiffCmd.Flags().StringSliceVarP(&configDiffNodes, "node", "", []string{},
+		"comma separated list of nodes to compare the configs of. All nodes if not set")
+	configDiffCmd.Flags().BoolVarP(&configDiffExitCode, "exit-code", "", false,
+		"exit with a non-zero code when a configuration drift is detected")
+}
+
+func configDiffFn(_ *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments: %s", args)
+	}
+
+	c, err := clab.NewContainerLab(
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithNodeFilter(configDiffNodes),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results := c.DiffConfigs(ctx)
+
+	drifted := 0
+
+	for _, r := range results {
+		if r.Status != clab.ConfigDrifted {
+			continue
+		}
+
+		drifted++
+
+		fmt.Print(r.Diff)
+	}
+
+	printConfigDiffSummary(results)
+
+	if drifted > 0 && configDiffExitCode {
+		return fmt.Errorf("configuration drift detected on %d node(s)", drifted)
+	}
+
+	return nil
+}
+
+// printConfigDiffSummary prints the config diff statuses of the nodes as a table.
+func printConfigDiffSummary(results []*clab.NodeConfigDiff) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{"Node", "Status", "Details"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	counts := map[string]int{}
+	rows := make([][]string, 0, len(results))
+
+	for _, r := range results {
+		counts[r.Status]++
+		rows = append(rows, []string{r.Node, r.Status, r.Reason})
+	}
+
+	table.AppendBulk(rows)
+	table.SetFooter([]string{
+		"", "",
+		fmt.Sprintf("%d drifted, %d clean, %d unsupported, %d failed",
+			counts[clab.ConfigDrifted], counts[clab.ConfigClean], counts[clab.ConfigUnsupported], counts[clab.ConfigFailed]),
+	})
+	table.Render()
+}
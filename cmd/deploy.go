@@ -5,14 +5,20 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/cert"
@@ -22,6 +28,7 @@ import (
 	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
 	"github.com/tklauser/numcpus"
 )
@@ -29,6 +36,8 @@ import (
 const (
 	// file name of a topology export data.
 	defaultExportTemplateFPath = "/etc/containerlab/templates/export/auto.tmpl"
+	// notAvailable is displayed for the values which failed to be collected.
+	notAvailable = "n/a"
 )
 
 // name of the container management network.
@@ -41,20 +50,68 @@ var mgmtIPv6Subnet net.IPNet
 // reconfigure flag.
 var reconfigure bool
 
+// keep-configs flag.
+var keepConfigs bool
+
 // max-workers flag.
 var maxWorkers uint
 
 // skipPostDeploy flag.
 var skipPostDeploy bool
 
+// healthcheck-timeout flag.
+var healthcheckTimeout time.Duration
+
+// force-build flag.
+var forceBuild bool
+
 // template file for topology data export.
 var exportTemplate string
 
+// format of the topology data export.
+var exportFormat string
+
+// additional targets of the topology data export: file paths, .gz file paths or http(s) URLs.
+var exportTargets []string
+
+// headers of the requests publishing the topology data export to the http(s) targets.
+var exportHeaders []string
+
+// exportStrict fails the deployment when the export can't be written to any of the export targets.
+var exportStrict bool
+
 var deployFormat string
 
 // subset of nodes to work with.
 var nodeFilter []string
 
+// ignore-host-tuning-failures flag.
+var ignoreHostTuningFailures bool
+
+// verify-links flag.
+var verifyLinks bool
+
+// progress flag.
+var deployProgress bool
+
+// usage flag.
+var deployUsage bool
+
+// auto-shorten-names flag.
+var autoShortenNames bool
+
+// relaxed-node-names flag, shared by the deploy and lint commands.
+var relaxedNodeNames bool
+
+// node settings overrides set with the --set flag.
+var nodeOverrides []string
+
+// dump-spec flag.
+var dumpSpec bool
+
+// pull-retries flag.
+var pullRetries int
+
 // deployCmd represents the deploy command.
 var deployCmd = &cobra.Command{
 	Use:          "deploy",
@@ -75,19 +132,72 @@ func init() {
 	deployCmd.Flags().StringVarP(&deployFormat, "format", "f", "table", "output format. One of [table, json]")
 	deployCmd.Flags().BoolVarP(&reconfigure, "reconfigure", "c", false,
 		"regenerate configuration artifacts and overwrite previous ones if any")
+	deployCmd.Flags().BoolVarP(&keepConfigs, "keep-configs", "", false,
+		"keep the config, flash and tftpboot directories of the nodes when reconfiguring")
+	deployCmd.Flags().BoolVarP(&cleanupAll, "cleanup-all", "", false,
+		"remove the topology backups along with the lab directory when reconfiguring")
 	deployCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0,
-		"limit the maximum number of workers creating nodes and virtual wires")
+		"limit the maximum number of workers pulling images, creating nodes and virtual wires")
 	deployCmd.Flags().BoolVarP(&skipPostDeploy, "skip-post-deploy", "", false, "skip post deploy action")
+	deployCmd.Flags().DurationVarP(&healthcheckTimeout, "healthcheck-timeout", "", clab.DefaultHealthcheckTimeout,
+		"maximum time to wait for a node to become healthy, used for the nodes without the healthcheck-timeout set")
+	deployCmd.Flags().BoolVarP(&forceBuild, "force-build", "", false,
+		"rebuild the node images built from the build contexts even if they are up to date")
 	deployCmd.Flags().StringVarP(&exportTemplate, "export-template", "",
 		defaultExportTemplateFPath, "template file for topology data export")
+	deployCmd.Flags().StringVarP(&exportFormat, "export-format", "", clab.ExportFormatJSON,
+		"format of the topology data export. One of ["+strings.Join(clab.ExportFormats, ", ")+"]")
+	deployCmd.Flags().StringSliceVarP(&exportTargets, "export-target", "", nil,
+		"additional targets the topology data export is written to: a file path, a .gz file path "+
+			"or an http(s) URL the export is POSTed to, can be repeated")
+	deployCmd.Flags().StringArrayVarP(&exportHeaders, "export-header", "", nil,
+		"header of the requests to the http(s) export targets in the 'Name: value' format, "+
+			"environment variables in the value are expanded, can be repeated")
+	deployCmd.Flags().BoolVarP(&exportStrict, "export-strict", "", false,
+		"fail the deployment when the topology data export can't be written to an export target")
 	deployCmd.Flags().StringSliceVarP(&nodeFilter, "node-filter", "", []string{},
 		"comma separated list of nodes to include")
+	deployCmd.Flags().BoolVarP(&ignoreHostTuningFailures, "ignore-host-tuning-failures", "", false,
+		"proceed with the deployment when the management bridge tuning fails")
+	deployCmd.Flags().BoolVarP(&verifyLinks, "verify-links", "", false,
+		"verify connectivity of the veth links which endpoints have IP addresses assigned")
+	deployCmd.Flags().BoolVarP(&deployProgress, "progress", "", false,
+		"display the live deployment status of the nodes")
+	deployCmd.Flags().BoolVarP(&deployUsage, "usage", "", false,
+		"print the host resources used by the lab after the deployment")
+	deployCmd.Flags().BoolVarP(&showResources, "show-resources", "", false,
+		"show the configured memory and cpu limits of the nodes along with their current usage")
+	deployCmd.Flags().BoolVarP(&autoShortenNames, "auto-shorten-names", "", false,
+		"shorten the container names exceeding the length limit with a hash suffix")
+	deployCmd.Flags().BoolVarP(&relaxedNodeNames, "relaxed-node-names", "", false,
+		"allow the node names that are not valid hostnames, the hostnames are derived from such names")
+	deployCmd.Flags().BoolVarP(&dumpSpec, "dump-spec", "", false,
+		"write the container specs of the nodes to their lab directories without creating the containers")
+	deployCmd.Flags().StringArrayVarP(&nodeOverrides, "set", "", nil,
+		"override a node setting defined in the topology, e.g. --set r1.cmd='sleep infinity'. "+
+			"One of <node>.image, <node>.cmd, <node>.entrypoint or <node>.env.<var>, can be repeated")
+	deployCmd.Flags().IntVarP(&pullRetries, "pull-retries", "", runtime.DefaultPullRetries,
+		"number of times an image pull failed with a network or a registry server error is retried, 0 disables the retries")
 }
 
 // deployFn function runs deploy sub command.
-func deployFn(_ *cobra.Command, _ []string) error {
+func deployFn(cmd *cobra.Command, _ []string) error {
 	var err error
 
+	if _, ok := utils.StringInSlice(clab.ExportFormats, exportFormat); !ok {
+		return fmt.Errorf("unknown export format %q, one of [%s] is expected",
+			exportFormat, strings.Join(clab.ExportFormats, ", "))
+	}
+
+	exportPublisher, err := clab.NewExportPublisher(exportTargets, exportHeaders, exportFormat, exportStrict)
+	if err != nil {
+		return err
+	}
+
+	if keepConfigs && !reconfigure {
+		return fmt.Errorf("the --keep-configs flag can only be used with the --reconfigure flag")
+	}
+
 	log.Infof("Containerlab v%s started", version)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -95,8 +205,14 @@ func deployFn(_ *cobra.Command, _ []string) error {
 
 	setupCTRLCHandler(cancel)
 
+	// the timeout set in the topology settings is used unless the --timeout flag is set
+	timeoutOpt := clab.WithDefaultTimeout(timeout)
+	if cmd.Flags().Changed("timeout") {
+		timeoutOpt = clab.WithTimeout(timeout)
+	}
+
 	opts := []clab.ClabOption{
-		clab.WithTimeout(timeout),
+		timeoutOpt,
 		clab.WithTopoPath(topo, varsFile),
 		clab.WithNodeFilter(nodeFilter),
 		clab.WithRuntime(rt,
@@ -104,11 +220,69 @@ func deployFn(_ *cobra.Command, _ []string) error {
 				Debug:            debug,
 				Timeout:          timeout,
 				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+				PullRetries:      deployPullRetries(),
 			},
 		),
 		clab.WithDebug(debug),
+		clab.WithVersion(version),
+		clab.WithMaxWorkers(maxWorkers),
+		clab.WithHealthcheckTimeout(healthcheckTimeout),
 	}
 
+	overrides, err := parseNodeOverrides(nodeOverrides)
+	if err != nil {
+		return err
+	}
+
+	if len(overrides) > 0 {
+		opts = append(opts, clab.WithNodeOverrides(overrides))
+	}
+
+	if ignoreHostTuningFailures {
+		opts = append(opts, clab.WithIgnoreHostTuningFailures())
+	}
+
+	if autoShortenNames {
+		opts = append(opts, clab.WithAutoShortenNames())
+	}
+
+	if forceBuild {
+		opts = append(opts, clab.WithForceBuild())
+	}
+
+	if skipPostDeploy {
+		opts = append(opts, clab.WithSkipPostDeploy())
+	}
+
+	if relaxedNodeNames {
+		opts = append(opts, clab.WithRelaxedNodeNames())
+	}
+
+	if dumpSpec {
+		opts = append(opts, clab.WithDumpSpec())
+	}
+
+	// deploy report collects the nodes deployment results for the deploy-report.json file
+	report := clab.NewDeployReport()
+	opts = append(opts, clab.WithLifecycleHook(report.HandleEvent))
+
+	// the live status is only drawn to a terminal,
+	// otherwise the logs are printed as usual and followed by the deployment summary
+	var progress *progressRenderer
+	if deployProgress {
+		progress = newProgressRenderer(os.Stderr, isTerminal(os.Stderr))
+		opts = append(opts, clab.WithLifecycleHook(progress.handle))
+	}
+
+	stopProgress := func() {
+		if progress != nil {
+			progress.stop()
+			progress = nil
+		}
+	}
+	defer stopProgress()
+
 	c, err := clab.NewContainerLab(opts...)
 	if err != nil {
 		return err
@@ -119,6 +293,14 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if progress != nil {
+		for name := range c.Nodes {
+			progress.addNodes(name)
+		}
+
+		progress.handle(clab.LifecycleEvent{Type: clab.LifecycleEventLinks, LinksTotal: len(c.Links)})
+	}
+
 	c.SetClabIntfsEnvVar()
 
 	setFlags(c.Config)
@@ -127,17 +309,53 @@ func deployFn(_ *cobra.Command, _ []string) error {
 	// dispatch a version check that will run in background
 	vCh := getLatestClabVersion(ctx)
 
+	// an engine too old for the features used by the lab would fail the deployment halfway through
+	if err := c.CheckRuntimeVersions(ctx); err != nil {
+		return err
+	}
+
+	// check for the leftovers of the previous deployments of the lab
+	// that would make the deployment fail halfway through
+	findings, err := c.Preflight(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the findings which resolution is safe, e.g. stale netns symlinks, are resolved right away
+	findings, err = clab.ResolveSafePreflightFindings(ctx, findings)
+	if err != nil {
+		return err
+	}
+
+	if len(findings) != 0 {
+		if !reconfigure {
+			log.Error(clab.PreflightReport(findings))
+			return fmt.Errorf("preflight checks found %d problem(s). Resolve them manually or add '--reconfigure' flag to the deploy command to resolve them automatically", len(findings))
+		}
+
+		if err := clab.ResolvePreflightFindings(ctx, findings); err != nil {
+			return err
+		}
+	}
+
 	if reconfigure {
 		_ = destroyLab(ctx, c)
 		log.Infof("Removing %s directory...", c.TopoPaths.TopologyLabDir())
-		if err := os.RemoveAll(c.TopoPaths.TopologyLabDir()); err != nil {
+		if err := c.RemoveLabDir(cleanupAll, keepConfigs); err != nil {
 			return err
 		}
 	}
 
-	// create management network or use existing one
-	if err = c.CreateNetwork(ctx); err != nil {
-		return err
+	// no host resources are set up when only the container specs are dumped
+	if !dumpSpec {
+		// create management network or use existing one
+		if err = c.CreateNetwork(ctx); err != nil {
+			return err
+		}
+
+		if err = c.CheckForwarding(); err != nil {
+			return err
+		}
 	}
 
 	err = links.SetMgmtNetUnderlayingBridge(c.Config.Mgmt.Bridge)
@@ -164,11 +382,20 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	// in an similar fashion, create an empty topology data file
-	topoDataFPath := c.TopoPaths.TopoExportFile()
-	topoDataF, err := os.Create(topoDataFPath)
-	if err != nil {
-		return err
+	// in an similar fashion, create an empty topology data file,
+	// which is not written when only the container specs are dumped
+	var topoDataF *os.File
+
+	if !dumpSpec {
+		topoDataFPath := c.TopoPaths.TopoExportFile()
+		if exportFormat == clab.ExportFormatMsgpack {
+			topoDataFPath = c.TopoPaths.TopoExportMsgpackFile()
+		}
+
+		topoDataF, err = os.Create(topoDataFPath)
+		if err != nil {
+			return err
+		}
 	}
 
 	if err := certificateAuthoritySetup(c); err != nil {
@@ -184,6 +411,13 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	// create the named volumes used in the node binds
+	if !dumpSpec {
+		if err := c.CreateVolumes(ctx); err != nil {
+			return err
+		}
+	}
+
 	// determine the number of node and link worker
 	nodeWorkers, _, err := countWorkers(uint(len(c.Nodes)), uint(len(c.Links)), maxWorkers)
 	if err != nil {
@@ -208,10 +442,33 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	// the entries of the link endpoint addresses are opt-in
+	if c.Config.Settings.GetLinkHosts() {
+		for _, n := range c.Nodes {
+			for _, e := range links.HostsEntries(n.GetEndpoints()) {
+				log.Debugf("Adding link /etc/hosts entry %s", e)
+				extraHosts = append(extraHosts, e)
+			}
+		}
+	}
+
+	// execCollection collects the results of the nodes exec commands run in all lifecycle phases
+	execCollection := exec.NewExecCollection()
+
 	for _, n := range c.Nodes {
 		n.Config().ExtraHosts = extraHosts
+		n.Config().ExecResults = execCollection
+	}
+
+	if progress != nil {
+		if err := progress.start(c.TopoPaths.DeployLogFileAbsPath()); err != nil {
+			return err
+		}
 	}
 
+	// the boot logs are captured until the deployment is finished
+	defer c.StopBootLogs()
+
 	dm := dependency_manager.NewDependencyManager()
 
 	nodesWg, err := c.CreateNodes(ctx, nodeWorkers, dm)
@@ -223,6 +480,15 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		nodesWg.Wait()
 	}
 
+	// no containers are created when only the container specs are dumped
+	if dumpSpec {
+		log.Info("Container specs are written to the lab directories of the nodes, no containers are created")
+		return nil
+	}
+
+	// the links of the nodes that failed to deploy are never created
+	c.SkipPendingLinks()
+
 	log.Debug("containers created, retrieving state and IP addresses...")
 	// updating nodes with runtime information such as IP addresses assigned by the runtime dynamically
 	for _, n := range c.Nodes {
@@ -232,11 +498,15 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	if err := c.WriteInterfacesFiles(); err != nil {
+		log.Errorf("failed to write the interfaces files: %v", err)
+	}
+
 	if err := c.GenerateInventories(); err != nil {
 		return err
 	}
 
-	if err := c.GenerateExports(ctx, topoDataF, exportTemplate); err != nil {
+	if err := c.GenerateExports(ctx, topoDataF, exportFormat, exportTemplate, nil); err != nil {
 		return err
 	}
 
@@ -248,8 +518,8 @@ func deployFn(_ *cobra.Command, _ []string) error {
 			go func(node nodes.Node, wg *sync.WaitGroup) {
 				defer wg.Done()
 
-				err := node.PostDeploy(ctx, &nodes.PostDeployParams{Nodes: c.Nodes})
-				if err != nil {
+				// the nodes waited for in the configured phase are already post-deployed
+				if err := c.PostDeployNode(ctx, node); err != nil {
 					log.Errorf("failed to run postdeploy task for node %s: %v", node.Config().ShortName, err)
 				}
 			}(node, wg)
@@ -257,6 +527,55 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		wg.Wait()
 	}
 
+	// the nodes killed by the OOM killer during the deployment may have restarted silently or stopped
+	for name, err := range c.CheckOOMKilled(ctx) {
+		log.Error(err)
+		c.NotifyNodePhase(name, clab.NodePhaseFailed, err)
+	}
+
+	stopProgress()
+
+	// keep the topology the lab was deployed from in the lab directory
+	if err := c.BackupTopology(); err != nil {
+		log.Errorf("failed to back up the topology to the lab directory: %v", err)
+	}
+
+	log.Debug("collecting the lab resource usage")
+	usage := c.CollectLabUsage(ctx)
+
+	if err := c.WriteLabMetadata(usage); err != nil {
+		log.Errorf("failed to write the lab metadata file: %v", err)
+	}
+
+	if err := c.WriteLabEnvFile(); err != nil {
+		log.Errorf("failed to write the lab environment variables file: %v", err)
+	}
+
+	// re-export the topology data with the resource usage of the deployed lab
+	if err := rewriteFile(topoDataF); err != nil {
+		return err
+	}
+
+	// the export is rendered once for the lab directory file and the export targets
+	var topoData bytes.Buffer
+	if err := c.GenerateExports(ctx, &topoData, exportFormat, exportTemplate, usage); err != nil {
+		return err
+	}
+
+	if _, err := topoDataF.Write(topoData.Bytes()); err != nil {
+		return err
+	}
+
+	if len(exportTargets) > 0 {
+		exports, err := exportPublisher.Publish(ctx, topoData.Bytes())
+		report.SetExports(exports)
+
+		// the export failures only fail the deployment with --export-strict
+		if err != nil {
+			return fmt.Errorf("failed to write the topology data export: %w", err)
+		}
+	}
+
 	containers, err := c.ListNodesContainers(ctx)
 	if err != nil {
 		return err
@@ -264,7 +583,7 @@ func deployFn(_ *cobra.Command, _ []string) error {
 
 	// generate graph of the lab topology
 	if graph {
-		if err = c.GenerateDotGraph(); err != nil {
+		if err = c.GenerateDotGraph(false); err != nil {
 			log.Error(err)
 		}
 	}
@@ -281,33 +600,177 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		log.Errorf("failed to create ssh config file: %v", err)
 	}
 
-	// execute commands specified for nodes with `exec` node parameter
-	execCollection := exec.NewExecCollection()
+	// execute the post-healthy phase commands specified for nodes with `exec` node parameter,
+	// the commands of the other phases were executed during the nodes deployment
 	for _, n := range c.Nodes {
-		for _, e := range n.Config().Exec {
-			exec, err := exec.NewExecCmdFromString(e)
-			if err != nil {
-				log.Warnf("Failed to parse the command string: %s, %v", e, err)
-			}
-
-			res, err := n.RunExec(ctx, exec)
-			if err != nil {
-				// kinds which do not support exec functionality are skipped
-				continue
-			}
-
-			execCollection.Add(n.Config().ShortName, res)
-		}
+		runtime.RunExecPhase(ctx, n.Config(), types.ExecPhasePostHealthy, n.RunExec)
 	}
 
 	// write to log
 	execCollection.Log()
 
+	if err := c.WriteExecResults(execCollection); err != nil {
+		log.Errorf("failed to write the exec results: %v", err)
+	}
+
+	report.Complete(c, containers, execCollection)
+	if err := c.WriteDeployReport(report); err != nil {
+		log.Errorf("failed to write the deploy report: %v", err)
+	}
+
+	if verifyLinks {
+		log.Info("Verifying links connectivity")
+		if err := c.VerifyLinksConnectivity(ctx); err != nil {
+			log.Errorf("links connectivity verification failed:\n%v", err)
+		}
+	}
+
 	// log new version availability info if ready
 	newVerNotification(vCh)
 
 	// print table summary
-	return printContainerInspect(containers, deployFormat)
+	if err := printContainerInspect(ctx, c, containers, deployFormat, nil, showResources, false); err != nil {
+		return err
+	}
+
+	if utils.FileExists(c.TopoPaths.LabEnvFileAbsPath()) {
+		log.Infof("The lab environment variables are written to %s, load them into the shell with: "+
+			"eval \"$(containerlab env -t %s)\"", c.TopoPaths.LabEnvFileAbsPath(), topo)
+	}
+
+	// the usage table would break the json output, the usage is available in the lab metadata file
+	if deployUsage && deployFormat == "table" {
+		printLabUsage(usage)
+	}
+
+	// the phase durations of all nodes are available in the lab metadata file
+	if deployFormat == "table" {
+		printSlowestNodes(os.Stdout, clab.SlowestNodes(c.NodeTimings(), slowestNodesCount))
+	}
+
+	// the lab stays deployed, but the exec commands asserting their return codes fail the deployment
+	if unexpected := execCollection.Unexpected(); len(unexpected) != 0 {
+		return fmt.Errorf("%d exec command(s) returned unexpected return codes:\n%s",
+			len(unexpected), strings.Join(unexpected, "\n"))
+	}
+
+	return nil
+}
+
+// rewriteFile truncates the file f and moves its offset to the beginning.
+func rewriteFile(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err := f.Seek(0, io.SeekStart)
+
+	return err
+}
+
+// printLabUsage prints the lab resource usage as a table.
+func printLabUsage(u *clab.LabUsage) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{"Node", "Image", "Memory", "CPU"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := make([][]string, 0, len(u.Nodes))
+
+	for _, n := range u.Nodes {
+		mem, cpu := notAvailable, notAvailable
+
+		if n.MemoryBytes != nil {
+			mem = humanize.IBytes(*n.MemoryBytes)
+		}
+
+		if n.CPUPercent != nil {
+			cpu = fmt.Sprintf("%.2f%%", *n.CPUPercent)
+		}
+
+		rows = append(rows, []string{n.Name, n.Image, mem, cpu})
+	}
+
+	table.AppendBulk(rows)
+
+	images := notAvailable
+	if u.ImagesSizeBytes != nil {
+		images = humanize.IBytes(uint64(*u.ImagesSizeBytes))
+	}
+
+	mgmt := notAvailable
+	if u.MgmtIPv4 != nil {
+		mgmt = fmt.Sprintf("%d/%d (%s)", u.MgmtIPv4.Used, u.MgmtIPv4.Capacity, u.MgmtIPv4.Subnet)
+	}
+
+	table.SetFooter([]string{
+		fmt.Sprintf("images: %s (%d unique)", images, u.UniqueImages),
+		fmt.Sprintf("veths: %d", u.Veths), "mgmt ipv4", mgmt,
+	})
+
+	table.Render()
+}
+
+// deployPullRetries returns the image pull retries of the runtime config for the --pull-retries flag,
+// the runtime config treats zero as the default number of retries and a negative value as no retries.
+func deployPullRetries() int {
+	if pullRetries <= 0 {
+		return -1
+	}
+
+	return pullRetries
+}
+
+// parseNodeOverrides parses the node overrides set with the --set flag.
+func parseNodeOverrides(overrides []string) ([]*clab.NodeOverride, error) {
+	res := make([]*clab.NodeOverride, 0, len(overrides))
+
+	for _, o := range overrides {
+		override, err := clab.ParseNodeOverride(o)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, override)
+	}
+
+	return res, nil
+}
+
+// slowestNodesCount is the number of the slowest nodes displayed after the deployment.
+const slowestNodesCount = 5
+
+// printSlowestNodes prints the phase durations of the nodes which took the longest to deploy as a table.
+func printSlowestNodes(w io.Writer, timings []*clab.NodeTimings) {
+	if len(timings) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "Slowest nodes:")
+
+	table := tablewriter.NewWriter(w)
+
+	table.SetHeader([]string{"Node", "Pre-deploy", "Deploy", "Startup wait", "Post-deploy", "Time to healthy", "Total"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := make([][]string, 0, len(timings))
+
+	for _, t := range timings {
+		row := []string{t.Name}
+		for _, d := range []string{t.PreDeploy, t.Deploy, t.StartupWait, t.PostDeploy, t.TimeToHealthy, t.Total} {
+			if d == "" {
+				d = notAvailable
+			}
+			row = append(row, d)
+		}
+
+		rows = append(rows, row)
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
 }
 
 // certificateAuthoritySetup sets up the certificate authority parameters.
@@ -0,0 +1,286 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab"
+	"golang.org/x/term"
+)
+
+// nodePhaseRank orders the node phases, so that the events of the earlier phases
+// received after the later ones don't move the node back.
+var nodePhaseRank = map[clab.NodePhase]int{
+	clab.NodePhaseWaitingDeps: 1,
+	clab.NodePhasePreDeploy:   2,
+	clab.NodePhaseDeploying:   3,
+	clab.NodePhaseStartupWait: 4,
+	clab.NodePhaseCreated:     5,
+	clab.NodePhasePostDeploy:  6,
+	clab.NodePhaseHealthy:     7,
+	clab.NodePhaseFailed:      8,
+}
+
+// pendingPhase is displayed for the nodes no event was received for.
+const pendingPhase = "pending"
+
+type nodeProgress struct {
+	phase clab.NodePhase
+	err   error
+	// since is the time the node entered its current phase
+	since time.Time
+}
+
+// progressRenderer renders the deployment status of the lab nodes out of the lifecycle events.
+// When live is set, the status is redrawn in place on every event,
+// otherwise only the final summary is printed.
+type progressRenderer struct {
+	mu      sync.Mutex
+	out     io.Writer
+	live    bool
+	started bool
+	// begin is the time the deployment started
+	begin time.Time
+	// now returns the current time, it is a field to make the elapsed time deterministic in tests
+	now func() time.Time
+
+	nodes         map[string]*nodeProgress
+	linksDeployed int
+	linksTotal    int
+
+	// lines is the number of the lines drawn by the last render
+	lines int
+	// logOut is the log output replaced while the live status is displayed
+	logOut io.Writer
+	// logFile receives the log messages while the live status is displayed
+	logFile *os.File
+}
+
+func newProgressRenderer(out io.Writer, live bool) *progressRenderer {
+	return &progressRenderer{
+		out:   out,
+		live:  live,
+		begin: time.Now(),
+		now:   time.Now,
+		nodes: map[string]*nodeProgress{},
+	}
+}
+
+// addNodes adds the nodes to the status, so that the nodes are displayed before any event is received for them.
+func (r *progressRenderer) addNodes(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range names {
+		if _, ok := r.nodes[name]; !ok {
+			r.nodes[name] = &nodeProgress{since: r.begin}
+		}
+	}
+}
+
+// isTerminal returns true if w is a terminal, the live status is only drawn to the terminals.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// start marks the beginning of the nodes deployment.
+// When the live status is displayed, the log output is redirected to the logPath file,
+// so that the log messages don't break the live status and are not lost.
+func (r *progressRenderer) start(logPath string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.started = true
+
+	if !r.live {
+		return nil
+	}
+
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	r.logFile = f
+	r.logOut = log.StandardLogger().Out
+	log.SetOutput(f)
+
+	r.render()
+
+	return nil
+}
+
+// stop restores the log output and prints the final summary.
+// Nothing is printed if the nodes deployment wasn't started.
+func (r *progressRenderer) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started {
+		return
+	}
+
+	r.started = false
+
+	r.clear()
+
+	if r.logFile != nil {
+		log.SetOutput(r.logOut)
+		r.logFile.Close()
+
+		log.Infof("Deployment logs were written to %s", r.logFile.Name())
+
+		r.logFile = nil
+	}
+
+	r.writeSummary(r.out)
+}
+
+// handle updates the status with the lifecycle event. It is registered as the lab lifecycle hook.
+func (r *progressRenderer) handle(ev clab.LifecycleEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch ev.Type {
+	case clab.LifecycleEventNodePhase:
+		n, ok := r.nodes[ev.Node]
+		if !ok {
+			n = &nodeProgress{}
+			r.nodes[ev.Node] = n
+		}
+
+		// phases may be skipped, but a node never moves back, and the failed node stays failed
+		if n.phase == clab.NodePhaseFailed || nodePhaseRank[ev.Phase] <= nodePhaseRank[n.phase] {
+			return
+		}
+
+		n.phase = ev.Phase
+		n.err = ev.Err
+		n.since = ev.Time
+
+	case clab.LifecycleEventLinks:
+		r.linksTotal = ev.LinksTotal
+		if ev.LinksDeployed > r.linksDeployed {
+			r.linksDeployed = ev.LinksDeployed
+		}
+
+	default:
+		return
+	}
+
+	if r.live && r.logFile != nil {
+		r.render()
+	}
+}
+
+// render redraws the live status in place of the previously drawn one.
+func (r *progressRenderer) render() {
+	r.clear()
+
+	names := r.sortedNodes()
+
+	var b strings.Builder
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		n := r.nodes[name]
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", name, phaseName(n.phase),
+			r.now().Sub(n.since).Round(time.Second))
+	}
+	tw.Flush()
+
+	fmt.Fprintf(&b, "  links: %d/%d deployed, elapsed %s\n", r.linksDeployed, r.linksTotal,
+		r.now().Sub(r.begin).Round(time.Second))
+
+	fmt.Fprint(r.out, b.String())
+	r.lines = len(names) + 1
+}
+
+// clear removes the previously drawn live status.
+func (r *progressRenderer) clear() {
+	if r.lines == 0 {
+		return
+	}
+
+	// move the cursor up and clear the screen below it
+	fmt.Fprintf(r.out, "\x1b[%dA\x1b[J", r.lines)
+	r.lines = 0
+}
+
+// writeSummary writes the static deployment summary to w.
+func (r *progressRenderer) writeSummary(w io.Writer) {
+	names := r.sortedNodes()
+
+	counts := map[string]int{}
+	for _, name := range names {
+		counts[phaseName(r.nodes[name].phase)]++
+	}
+
+	var phases []string
+	for _, p := range []string{
+		string(clab.NodePhaseHealthy), string(clab.NodePhaseCreated), string(clab.NodePhaseFailed),
+	} {
+		if counts[p] > 0 {
+			phases = append(phases, fmt.Sprintf("%d %s", counts[p], p))
+			delete(counts, p)
+		}
+	}
+
+	// nodes stuck in an intermediate phase
+	if len(counts) > 0 {
+		other := 0
+		for _, c := range counts {
+			other += c
+		}
+
+		phases = append(phases, fmt.Sprintf("%d incomplete", other))
+	}
+
+	fmt.Fprintf(w, "Deployment summary: %d node(s): %s; links: %d/%d deployed\n",
+		len(names), strings.Join(phases, ", "), r.linksDeployed, r.linksTotal)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		n := r.nodes[name]
+
+		line := fmt.Sprintf("  %s\t%s", name, phaseName(n.phase))
+		if n.err != nil {
+			line += "\t" + n.err.Error()
+		}
+
+		fmt.Fprintln(tw, line)
+	}
+	tw.Flush()
+}
+
+func (r *progressRenderer) sortedNodes() []string {
+	names := make([]string, 0, len(r.nodes))
+	for name := range r.nodes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func phaseName(p clab.NodePhase) string {
+	if p == "" {
+		return pendingPhase
+	}
+
+	return string(p)
+}
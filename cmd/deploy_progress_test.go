@@ -0,0 +1,135 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srl-labs/containerlab/clab"
+)
+
+func nodeEvent(node string, phase clab.NodePhase, err error) clab.LifecycleEvent {
+	return clab.LifecycleEvent{Type: clab.LifecycleEventNodePhase, Node: node, Phase: phase, Err: err}
+}
+
+func linksEvent(deployed, total int) clab.LifecycleEvent {
+	return clab.LifecycleEvent{Type: clab.LifecycleEventLinks, LinksDeployed: deployed, LinksTotal: total}
+}
+
+func TestProgressRendererSummary(t *testing.T) {
+	events := []clab.LifecycleEvent{
+		linksEvent(0, 3),
+		nodeEvent("srl1", clab.NodePhaseWaitingDeps, nil),
+		nodeEvent("srl2", clab.NodePhaseWaitingDeps, nil),
+		nodeEvent("client", clab.NodePhaseWaitingDeps, nil),
+		nodeEvent("srl1", clab.NodePhasePreDeploy, nil),
+		nodeEvent("srl1", clab.NodePhaseDeploying, nil),
+		nodeEvent("srl2", clab.NodePhasePreDeploy, nil),
+		nodeEvent("srl2", clab.NodePhaseFailed, errors.New("failed deploy phase: image not found")),
+		nodeEvent("srl1", clab.NodePhaseCreated, nil),
+		linksEvent(1, 3),
+		// client skips the pre-deploy and deploying phases
+		nodeEvent("client", clab.NodePhaseCreated, nil),
+		linksEvent(2, 3),
+		// out of order links event doesn't decrease the deployed links count
+		linksEvent(1, 3),
+		nodeEvent("srl1", clab.NodePhasePostDeploy, nil),
+		nodeEvent("srl2", clab.NodePhasePostDeploy, nil),
+		nodeEvent("client", clab.NodePhasePostDeploy, nil),
+		nodeEvent("srl1", clab.NodePhaseHealthy, nil),
+		// failed node stays failed
+		nodeEvent("srl2", clab.NodePhaseHealthy, nil),
+		// late event of an earlier phase doesn't move the node back
+		nodeEvent("srl1", clab.NodePhaseDeploying, nil),
+	}
+
+	var out bytes.Buffer
+
+	r := newProgressRenderer(&out, false)
+	r.addNodes("client", "srl1", "srl2", "srl3")
+
+	if err := r.start(filepath.Join(t.TempDir(), "deploy.log")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ev := range events {
+		r.handle(ev)
+	}
+
+	// nothing is drawn until the summary when the live status is off
+	if out.Len() != 0 {
+		t.Fatalf("unexpected output before the summary: %q", out.String())
+	}
+
+	r.stop()
+
+	want := `Deployment summary: 4 node(s): 1 healthy, 1 failed, 2 incomplete; links: 2/3 deployed
+  client  post-deploy
+  srl1    healthy
+  srl2    failed  failed deploy phase: image not found
+  srl3    pending
+`
+
+	if got := out.String(); got != want {
+		t.Fatalf("summary mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	// summary is printed once
+	r.stop()
+
+	if got := out.String(); got != want {
+		t.Fatalf("summary printed twice:\n%s", got)
+	}
+}
+
+func TestProgressRendererLive(t *testing.T) {
+	var out bytes.Buffer
+
+	r := newProgressRenderer(&out, true)
+
+	now := r.begin
+	r.now = func() time.Time { return now }
+
+	r.addNodes("n1", "n2")
+
+	logPath := filepath.Join(t.TempDir(), "deploy.log")
+	if err := r.start(logPath); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(2 * time.Second)
+	r.handle(clab.LifecycleEvent{
+		Type: clab.LifecycleEventNodePhase, Node: "n1",
+		Phase: clab.NodePhaseDeploying, Time: now,
+	})
+
+	now = now.Add(3 * time.Second)
+	r.stop()
+
+	got := out.String()
+
+	// the status drawn last before the summary
+	wantStatus := "\x1b[3A\x1b[J" +
+		"  n1  deploying  0s\n" +
+		"  n2  pending    2s\n" +
+		"  links: 0/0 deployed, elapsed 2s\n"
+
+	if !strings.Contains(got, wantStatus) {
+		t.Fatalf("live status not found in the output:\n%q", got)
+	}
+
+	// the live status is cleared before the summary is printed
+	if !strings.HasSuffix(got, "\x1b[3A\x1b[J"+
+		"Deployment summary: 2 node(s): 2 incomplete; links: 0/0 deployed\n"+
+		"  n1  deploying\n"+
+		"  n2  pending\n") {
+		t.Fatalf("summary not found at the end of the output:\n%q", got)
+	}
+}
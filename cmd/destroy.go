@@ -7,7 +7,7 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -17,12 +17,19 @@ import (
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/runtime/ignite"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 )
 
 var (
 	cleanup     bool
+	cleanupAll  bool
 	graceful    bool
 	keepMgmtNet bool
+	keepVolumes bool
+	// fromLabel makes destroy find the topology file of the lab in the labels of its containers.
+	fromLabel bool
+	// shutdownTimeout is the time the NOS of a node is given to shut down with the graceful destroy.
+	shutdownTimeout time.Duration
 )
 
 // destroyCmd represents the destroy command.
@@ -37,15 +44,26 @@ var destroyCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(destroyCmd)
-	destroyCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", false, "delete lab directory")
+	destroyCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", false,
+		"delete lab directory, the topology backups are kept")
+	destroyCmd.Flags().BoolVarP(&cleanupAll, "cleanup-all", "", false,
+		"delete lab directory including the topology backups")
 	destroyCmd.Flags().BoolVarP(&graceful, "graceful", "", false,
 		"attempt to stop containers before removing")
+	destroyCmd.Flags().DurationVarP(&shutdownTimeout, "shutdown-timeout", "", 2*time.Minute,
+		"time the NOS of a node is given to shut down with --graceful before its container is stopped")
 	destroyCmd.Flags().BoolVarP(&all, "all", "a", false, "destroy all containerlab labs")
+	destroyCmd.Flags().BoolVarP(&fromLabel, "from-label", "", false,
+		"destroy the lab set with --name using the topology file recorded in the labels of its containers")
 	destroyCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0,
 		"limit the maximum number of workers deleting nodes")
 	destroyCmd.Flags().BoolVarP(&keepMgmtNet, "keep-mgmt-net", "", false, "do not remove the management network")
+	destroyCmd.Flags().BoolVarP(&keepVolumes, "keep-volumes", "", false,
+		"do not remove the named volumes created for the lab nodes")
 	destroyCmd.Flags().StringSliceVarP(&nodeFilter, "node-filter", "", []string{},
 		"comma separated list of nodes to include")
+	destroyCmd.Flags().BoolVarP(&autoShortenNames, "auto-shorten-names", "", false,
+		"destroy a lab deployed with the container names shortened by --auto-shorten-names")
 }
 
 func destroyFn(_ *cobra.Command, _ []string) error {
@@ -56,10 +74,18 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 
 	topos := map[string]struct{}{}
 
+	if fromLabel && all {
+		return fmt.Errorf("--from-label and --all flags are mutually exclusive")
+	}
+
+	if fromLabel && name == "" {
+		return fmt.Errorf("--from-label requires the lab name set with --name")
+	}
+
 	switch {
-	case !all:
+	case !all && !fromLabel:
 		topos[topo] = struct{}{}
-	case all:
+	default:
 		// only WithRuntime option is needed to list all containers of a lab
 		inspectAllOpts := []clab.ClabOption{
 			clab.WithRuntime(rt,
@@ -67,6 +93,7 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Socket:           runtimeSocket,
 				},
 			),
 			clab.WithTimeout(timeout),
@@ -76,23 +103,44 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 		if err != nil {
 			return err
 		}
-		// list all containerlab containers
+		// list all containerlab containers or the containers of the named lab
 		filter := []*types.GenericFilter{{
 			FilterType: "label", Match: c.Config.Name,
 			Field: labels.Containerlab, Operator: "exists",
 		}}
+		if fromLabel {
+			filter = []*types.GenericFilter{{
+				FilterType: "label", Match: name,
+				Field: labels.Containerlab, Operator: "=",
+			}}
+		}
+
 		containers, err := c.ListContainers(ctx, filter)
 		if err != nil {
 			return err
 		}
 
 		if len(containers) == 0 {
+			if fromLabel {
+				return fmt.Errorf("no containers of lab %q were found", name)
+			}
 			return fmt.Errorf("no containerlab labs were found")
 		}
 		// get unique topo files from all labs
 		for i := range containers {
 			topos[containers[i].Labels[labels.TopoFile]] = struct{}{}
 		}
+
+		// the lab which topology file is lost is destroyed using the labels of its containers
+		if fromLabel && !topoFilesExist(topos) {
+			log.Infof("Topology file of lab %s is not found, destroying the lab using the labels of its containers", name)
+
+			if cleanup || cleanupAll {
+				log.Warn("The lab directory is not removed when the lab is destroyed without its topology file")
+			}
+
+			return c.DestroyFromLabels(ctx, name, keepMgmtNet)
+		}
 	}
 
 	log.Debugf("We got the following topos struct for destroy: %+v", topos)
@@ -106,15 +154,26 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Socket:           runtimeSocket,
 				},
 			),
 			clab.WithDebug(debug),
+			// destroy only needs the mgmt bridge name, its tuning failures are irrelevant
+			clab.WithIgnoreHostTuningFailures(),
 		}
 
 		if keepMgmtNet {
 			opts = append(opts, clab.WithKeepMgmtNet())
 		}
 
+		if graceful {
+			opts = append(opts, clab.WithGracefulShutdown(shutdownTimeout))
+		}
+
+		if autoShortenNames {
+			opts = append(opts, clab.WithAutoShortenNames())
+		}
+
 		log.Debugf("going through extracted topos for destroy, got a topo file %v and generated opts list %+v", topo, opts)
 		nc, err := clab.NewContainerLab(opts...)
 		if err != nil {
@@ -138,6 +197,10 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 			return err
 		}
 
+		// disabled nodes are not deployed, but they might have been deployed
+		// before they were disabled, in that case they are removed as well
+		nc.AddDeployedDisabledNodes(ctx)
+
 		labs = append(labs, nc)
 	}
 
@@ -149,8 +212,13 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 			errs = append(errs, err)
 		}
 
-		if cleanup {
-			err = os.RemoveAll(clab.TopoPaths.TopologyLabDir())
+		// volumes are removed once the containers using them are gone
+		if !keepVolumes {
+			clab.DeleteVolumes(ctx)
+		}
+
+		if cleanup || cleanupAll {
+			err = clab.RemoveLabDir(cleanupAll, false)
 			if err != nil {
 				log.Errorf("error deleting lab directory: %v", err)
 			}
@@ -164,6 +232,17 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// topoFilesExist returns true if all the given topology files exist.
+func topoFilesExist(topos map[string]struct{}) bool {
+	for t := range topos {
+		if t == "" || !utils.FileExists(t) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func destroyLab(ctx context.Context, c *clab.CLab) (err error) {
 	containers, err := c.ListNodesContainersIgnoreNotFound(ctx)
 	if err != nil {
@@ -228,6 +307,13 @@ func destroyLab(ctx context.Context, c *clab.CLab) (err error) {
 		}
 	}
 
+	// the forwarding is restored only when the whole lab is destroyed
+	if len(nodeFilter) == 0 {
+		if err = c.RestoreForwarding(); err != nil {
+			log.Errorf("failed to restore the packet forwarding settings: %v", err)
+		}
+	}
+
 	// delete container network namespaces symlinks
 	for _, node := range c.Nodes {
 		err = node.DeleteNetnsSymlink()
@@ -30,6 +30,7 @@ var disableTxOffloadCmd = &cobra.Command{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Socket:           runtimeSocket,
 				},
 			),
 			clab.WithDebug(debug),
@@ -0,0 +1,69 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// envCmd represents the env command.
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "print the lab environment variables",
+	Long: "print the export statements of the environment variables with the management addresses and the container names\n" +
+		"of the lab nodes. Load them into the shell with: eval \"$(containerlab env -t <topology>)\"\n" +
+		"reference: https://containerlab.dev/cmd/env/",
+	RunE: envFn,
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+}
+
+func envFn(_ *cobra.Command, _ []string) error {
+	if topo == "" {
+		return fmt.Errorf("provide a path to the topology file with --topo flag")
+	}
+
+	// the variables are built from the lab metadata file, the containers are not queried
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	vars, err := c.LabEnv()
+	if len(vars) == 0 && err != nil {
+		return err
+	}
+
+	if err != nil {
+		log.Warn(err)
+	}
+
+	for _, v := range vars {
+		fmt.Printf("export %s=%s\n", v.Name, v.Value)
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
@@ -22,6 +23,8 @@ var (
 	labelsFilter []string
 	execFormat   string
 	execCommands []string
+	// execExpectedRCs are the return codes the exec commands are expected to exit with.
+	execExpectedRCs []int
 )
 
 // execCmd represents the exec command.
@@ -51,6 +54,7 @@ func execFn(_ *cobra.Command, _ []string) error {
 				Debug:            debug,
 				Timeout:          timeout,
 				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
 			},
 		),
 		clab.WithDebug(debug),
@@ -92,13 +96,9 @@ func execFn(_ *cobra.Command, _ []string) error {
 	resultCollection := exec.NewExecCollection()
 
 	// build execs from the string input
-	var execCmds []*exec.ExecCmd
-	for _, execCmdStr := range execCommands {
-		execCmd, err := exec.NewExecCmdFromString(execCmdStr)
-		if err != nil {
-			return err
-		}
-		execCmds = append(execCmds, execCmd)
+	execCmds, err := execCmdsFromStrings(execCommands, execExpectedRCs)
+	if err != nil {
+		return err
 	}
 
 	// run the exec commands on all the containers matching the filter
@@ -109,9 +109,14 @@ func execFn(_ *cobra.Command, _ []string) error {
 			execResult, err := cnt.RunExec(ctx, execCmd)
 			if err != nil {
 				// skip nodes that do not support exec
-				if err == exec.ErrRunExecNotSupported {
+				if errors.Is(err, exec.ErrRunExecNotSupported) {
 					continue
 				}
+
+				// the command that could not be executed is reported as failed
+				execResult = exec.NewExecResult(execCmd)
+				execResult.SetReturnCode(-1)
+				execResult.SetStdErr([]byte(err.Error()))
 			}
 			resultCollection.Add(cnt.Names[0], execResult)
 		}
@@ -129,7 +134,46 @@ func execFn(_ *cobra.Command, _ []string) error {
 		fmt.Println(out)
 	}
 
-	return err
+	if unexpected := resultCollection.Unexpected(); len(unexpected) != 0 {
+		return fmt.Errorf("%d command(s) returned unexpected return codes:\n%s",
+			len(unexpected), strings.Join(unexpected, "\n"))
+	}
+
+	return nil
+}
+
+// execCmdsFromStrings builds the exec commands expected to exit with the return codes.
+// A single return code is expected from all the commands, otherwise the return codes
+// are matched to the commands by their position. The commands are expected to exit with zero by default.
+func execCmdsFromStrings(cmds []string, rcs []int) ([]*exec.ExecCmd, error) {
+	if len(rcs) > 1 && len(rcs) != len(cmds) {
+		return nil, fmt.Errorf("got %d expected return codes for %d commands, "+
+			"set either a single return code for all the commands or one for each command", len(rcs), len(cmds))
+	}
+
+	execCmds := make([]*exec.ExecCmd, 0, len(cmds))
+
+	for i, c := range cmds {
+		execCmd, err := exec.NewExecCmdFromString(c)
+		if err != nil {
+			return nil, err
+		}
+
+		rc := 0
+		switch len(rcs) {
+		case 0:
+		case 1:
+			rc = rcs[0]
+		default:
+			rc = rcs[i]
+		}
+
+		execCmd.SetExpectedReturnCode(rc)
+
+		execCmds = append(execCmds, execCmd)
+	}
+
+	return execCmds, nil
 }
 
 func init() {
@@ -137,4 +181,6 @@ func init() {
 	execCmd.Flags().StringArrayVarP(&execCommands, "cmd", "", []string{}, "command to execute")
 	execCmd.Flags().StringSliceVarP(&labelsFilter, "label", "", []string{}, "labels to filter container subset")
 	execCmd.Flags().StringVarP(&execFormat, "format", "f", "plain", "output format. One of [json, plain]")
+	execCmd.Flags().IntSliceVarP(&execExpectedRCs, "expected-rc", "", []int{},
+		"return codes the commands are expected to exit with, a single value applies to all the commands")
 }
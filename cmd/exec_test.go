@@ -0,0 +1,54 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExecCmdsFromStrings(t *testing.T) {
+	cmds := []string{"ping -c1 10.0.0.2", "test -f /tmp/blocked"}
+
+	tests := map[string]struct {
+		rcs     []int
+		want    []int
+		wantErr bool
+	}{
+		"default":        {want: []int{0, 0}},
+		"single":         {rcs: []int{1}, want: []int{1, 1}},
+		"per command":    {rcs: []int{0, 1}, want: []int{0, 1}},
+		"count mismatch": {rcs: []int{0, 1, 2}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := execCmdsFromStrings(cmds, tt.rcs)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var rcs []int
+			for i, c := range got {
+				if c.GetCmdString() != cmds[i] {
+					t.Errorf("got command %q, want %q", c.GetCmdString(), cmds[i])
+				}
+				rcs = append(rcs, *c.ExpectedReturnCode)
+			}
+
+			if d := cmp.Diff(tt.want, rcs); d != "" {
+				t.Errorf("expected return codes mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"fmt"
 	"html/template"
 	"sort"
 
@@ -32,6 +33,8 @@ var (
 	mermaid          bool
 	mermaidDirection string
 	staticDir        string
+	showDisabled     bool
+	graphFormat      string
 )
 
 // graphCmd represents the graph command.
@@ -45,6 +48,10 @@ var graphCmd = &cobra.Command{
 func graphFn(_ *cobra.Command, _ []string) error {
 	var err error
 
+	if graphFormat != "html" && graphFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use html or json", graphFormat)
+	}
+
 	opts := []clab.ClabOption{
 		clab.WithTimeout(timeout),
 		clab.WithTopoPath(topo, varsFile),
@@ -54,6 +61,7 @@ func graphFn(_ *cobra.Command, _ []string) error {
 				Debug:            debug,
 				Timeout:          timeout,
 				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
 			},
 		),
 		clab.WithDebug(debug),
@@ -69,7 +77,7 @@ func graphFn(_ *cobra.Command, _ []string) error {
 	}
 
 	if dot {
-		return c.GenerateDotGraph()
+		return c.GenerateDotGraph(showDisabled)
 	}
 
 	if mermaid {
@@ -99,6 +107,26 @@ func graphFn(_ *cobra.Command, _ []string) error {
 		log.Debugf("found %d containers", len(containers))
 	}
 
+	if graphFormat == "json" {
+		// populating the nspath for the nodes to read the link states
+		for _, n := range c.Nodes {
+			nsp, err := n.GetRuntime().GetNSPath(ctx, n.Config().LongName)
+			if err != nil {
+				continue
+			}
+			n.Config().NSPath = nsp
+		}
+
+		b, err := json.MarshalIndent(c.BuildGraphDocument(containers), "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+
+		return nil
+	}
+
 	switch {
 	case len(containers) == 0:
 		c.BuildGraphFromTopo(&gtopo)
@@ -106,6 +134,10 @@ func graphFn(_ *cobra.Command, _ []string) error {
 		c.BuildGraphFromDeployedLab(&gtopo, containers)
 	}
 
+	if showDisabled {
+		c.AddDisabledNodesToGraph(&gtopo)
+	}
+
 	sort.Slice(gtopo.Nodes, func(i, j int) bool {
 		return gtopo.Nodes[i].Name < gtopo.Nodes[j].Name
 	})
@@ -143,7 +175,9 @@ func init() {
 		"use only information from topo file when building graph")
 	graphCmd.Flags().BoolVarP(&dot, "dot", "", false, "generate dot file")
 	graphCmd.Flags().BoolVarP(&mermaid, "mermaid", "", false, "print mermaid flowchart to stdout")
-	graphCmd.MarkFlagsMutuallyExclusive("dot", "mermaid")
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "", "html",
+		"output format of the graph served by the HTTP server or printed to stdout. One of [html, json]")
+	graphCmd.MarkFlagsMutuallyExclusive("dot", "mermaid", "format")
 	graphCmd.Flags().StringVarP(&mermaidDirection, "mermaid-direction", "", "TD", "specify direction of mermaid dirgram")
 	graphCmd.Flags().StringVarP(&tmpl, "template", "", defaultGraphTemplatePath,
 		"Go html template used to generate the graph")
@@ -151,4 +185,6 @@ func init() {
 		"Serve static files from the specified directory")
 	graphCmd.Flags().StringSliceVarP(&nodeFilter, "node-filter", "", []string{},
 		"comma separated list of nodes to include")
+	graphCmd.Flags().BoolVarP(&showDisabled, "show-disabled", "", false,
+		"show disabled nodes greyed out in the graph")
 }
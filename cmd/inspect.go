@@ -7,24 +7,37 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/olekukonko/tablewriter"
+	"github.com/dustin/go-humanize"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
 	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 )
 
 var (
-	inspectFormat string
-	details       bool
-	all           bool
+	inspectFormat      string
+	inspectColumnNames []string
+	details            bool
+	all                bool
+	// showResources adds the resource limits and usage of the nodes to the inspect and deploy output
+	showResources bool
+	// showStats adds the live CPU and memory usage of the nodes to the inspect output
+	showStats bool
 )
 
 // inspectCmd represents the inspect command.
@@ -41,8 +54,14 @@ func init() {
 	rootCmd.AddCommand(inspectCmd)
 
 	inspectCmd.Flags().BoolVarP(&details, "details", "", false, "print all details of lab containers")
-	inspectCmd.Flags().StringVarP(&inspectFormat, "format", "f", "table", "output format. One of [table, json]")
+	inspectCmd.Flags().StringVarP(&inspectFormat, "format", "f", "table", "output format. One of [table, json, csv]")
+	inspectCmd.Flags().StringSliceVarP(&inspectColumnNames, "columns", "", nil,
+		"comma separated columns of the table and csv output. Any of "+columnNames(inspectColumns))
 	inspectCmd.Flags().BoolVarP(&all, "all", "a", false, "show all deployed containerlab labs")
+	inspectCmd.Flags().BoolVarP(&showResources, "show-resources", "", false,
+		"show the configured memory and cpu limits of the nodes along with their current usage")
+	inspectCmd.Flags().BoolVarP(&showStats, "stats", "", false,
+		"show the live cpu and memory usage of the node containers along with their memory limit")
 }
 
 func inspectFn(_ *cobra.Command, _ []string) error {
@@ -57,6 +76,7 @@ func inspectFn(_ *cobra.Command, _ []string) error {
 				Debug:            debug,
 				Timeout:          timeout,
 				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
 			},
 		),
 		clab.WithDebug(debug),
@@ -89,6 +109,16 @@ func inspectFn(_ *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to list containers: %s", err)
 		}
+
+		changed, err := c.TopologyChanged()
+		if err != nil {
+			log.Debugf("failed to compare the topology with the deployed one: %v", err)
+		}
+
+		if changed {
+			log.Warnf("Topology file %s has changed since the lab was deployed, the deployed topology is saved in %s",
+				topo, c.TopoPaths.ResolvedTopologyFileAbsPath())
+		}
 	} else {
 		var glabels []*types.GenericFilter
 		// or when just the name is given
@@ -116,7 +146,13 @@ func inspectFn(_ *cobra.Command, _ []string) error {
 		return nil
 	}
 	if details {
-		b, err := json.MarshalIndent(containers, "", "  ")
+		var v any = containers
+		// the phase durations of the nodes are only known for the lab deployed from the topology file
+		if topo != "" {
+			v = withDeployTimings(c, containers)
+		}
+
+		b, err := json.MarshalIndent(v, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal containers struct: %v", err)
 		}
@@ -124,38 +160,151 @@ func inspectFn(_ *cobra.Command, _ []string) error {
 		return nil
 	}
 
-	err = printContainerInspect(containers, inspectFormat)
+	err = printContainerInspect(ctx, c, containers, inspectFormat, inspectColumnNames, showResources, showStats)
 	return err
 }
 
-func toTableData(det []types.ContainerDetails) [][]string {
-	tabData := make([][]string, 0, len(det))
-	for i := range det {
-		d := &det[i]
+// containerWithTimings is the container details extended with the phase durations of the node deployment.
+type containerWithTimings struct {
+	runtime.GenericContainer
+	DeployTimings *clab.NodeTimings `json:",omitempty"`
+}
 
-		if all {
-			tabData = append(tabData, []string{
-				fmt.Sprintf("%d", i+1), d.LabPath,
-				d.LabName, d.Name, d.ContainerID, d.Image, d.Kind, d.State, d.IPv4Address, d.IPv6Address,
-			})
-			continue
-		}
-		tabData = append(tabData, []string{
-			fmt.Sprintf("%d", i+1), d.Name, d.ContainerID,
-			d.Image, d.Kind, d.State, d.IPv4Address, d.IPv6Address,
+// withDeployTimings adds the phase durations of the node deployment recorded in the lab metadata file
+// to the details of the node containers.
+func withDeployTimings(c *clab.CLab, containers []runtime.GenericContainer) []containerWithTimings {
+	timings, err := c.DeployedNodeTimings()
+	if err != nil {
+		log.Debugf("the deployment timings of the nodes are not available: %v", err)
+	}
+
+	res := make([]containerWithTimings, 0, len(containers))
+	for _, cont := range containers {
+		res = append(res, containerWithTimings{
+			GenericContainer: cont,
+			DeployTimings:    timings[cont.Labels[labels.NodeName]],
 		})
 	}
-	return tabData
+
+	return res
+}
+
+// inspectColumns are the columns of the inspect table and csv output.
+var inspectColumns = []outputColumn[types.ContainerDetails]{
+	{name: "topo-path", header: "Topo Path", value: func(d *types.ContainerDetails) string { return d.LabPath }},
+	{name: "lab-name", header: "Lab Name", value: func(d *types.ContainerDetails) string { return d.LabName }},
+	{name: "name", header: "Name", value: func(d *types.ContainerDetails) string { return d.Name }},
+	{name: "container-id", header: "Container ID", value: func(d *types.ContainerDetails) string { return d.ContainerID }},
+	{name: "image", header: "Image", value: func(d *types.ContainerDetails) string { return d.Image }},
+	{name: "kind", header: "Kind", value: func(d *types.ContainerDetails) string { return d.Kind }},
+	{name: "state", header: "State", value: containerState},
+	{name: "health", header: "Health", value: func(d *types.ContainerDetails) string { return d.Health }},
+	{name: "ipv4", header: "IPv4 Address", value: func(d *types.ContainerDetails) string { return d.IPv4Address }},
+	{name: "ipv6", header: "IPv6 Address", value: func(d *types.ContainerDetails) string { return d.IPv6Address }},
+	{name: "owner", header: "Owner", value: func(d *types.ContainerDetails) string { return d.Owner }},
+	{name: "clab-version", header: "Clab Version", value: func(d *types.ContainerDetails) string { return d.ClabVersion }},
+	{name: "exposed", header: "Exposed Ports", value: func(d *types.ContainerDetails) string { return d.ExposedPorts }},
+	{name: "uptime", header: "Uptime", value: func(d *types.ContainerDetails) string { return containerUptime(d.Status) }},
+	{name: "memory-limit", header: "Memory Limit", value: func(d *types.ContainerDetails) string { return d.MemoryLimit }},
+	{name: "memory-usage", header: "Memory Usage", value: func(d *types.ContainerDetails) string { return d.MemoryUsage }},
+	{name: "cpu-limit", header: "CPU Limit", value: func(d *types.ContainerDetails) string { return d.CPULimit }},
+	{name: "cpu-usage", header: "CPU Usage", value: func(d *types.ContainerDetails) string { return d.CPUUsage }},
+	{name: "memory-usage-limit", header: "Mem Usage / Limit", value: func(d *types.ContainerDetails) string { return d.MemoryUsageLimit }},
+	{name: "exit-code", header: "Exit Code", value: containerExitCode},
+	{name: "restarts", header: "Restarts", value: func(d *types.ContainerDetails) string { return strconv.Itoa(d.RestartCount) }},
+	{name: "started-at", header: "Started At", value: func(d *types.ContainerDetails) string { return d.StartedAt }},
+	{name: "finished-at", header: "Finished At", value: func(d *types.ContainerDetails) string { return d.FinishedAt }},
 }
 
-func printContainerInspect(containers []runtime.GenericContainer, format string) error {
+// resourceColumns are the columns of the resource limits and usage of the nodes,
+// displayed in addition to the default columns with the --show-resources flag.
+var resourceColumns = []string{"memory-limit", "memory-usage", "cpu-limit", "cpu-usage"}
+
+// statsColumns are the columns of the live resource usage of the node containers,
+// displayed in addition to the default columns with the --stats flag.
+var statsColumns = []string{"cpu-usage", "memory-usage-limit"}
+
+// statsNotSupported is displayed for the resource usage of the nodes which don't run in a container.
+const statsNotSupported = "-"
+
+// defaultInspectColumns returns the names of the columns displayed when the columns are not selected.
+// The labs of the containers are only displayed when the containers of all labs are inspected
+// and the health only when at least one of the containers has a health check.
+func defaultInspectColumns(allLabs bool, contDetails []types.ContainerDetails) []string {
+	cols := []string{"name", "container-id", "image", "kind", "state"}
+	if hasHealthCheck(contDetails) {
+		cols = append(cols, "health")
+	}
+
+	cols = append(cols, "ipv4", "ipv6")
+
+	if allLabs {
+		cols = append([]string{"topo-path", "lab-name"}, cols...)
+	}
+
+	return cols
+}
+
+// hasHealthCheck returns true if at least one of the containers has a health check.
+func hasHealthCheck(contDetails []types.ContainerDetails) bool {
+	for i := range contDetails {
+		if h := contDetails[i].Health; h != "" && h != runtime.HealthNone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containerState returns the state of the container flagged with the number of restarts
+// when the container has been restarted by the runtime, e.g. "running (5 restarts)" for a crash-looping node.
+func containerState(d *types.ContainerDetails) string {
+	switch d.RestartCount {
+	case 0:
+		return d.State
+	case 1:
+		return d.State + " (1 restart)"
+	}
+
+	return fmt.Sprintf("%s (%d restarts)", d.State, d.RestartCount)
+}
+
+// containerExitCode returns the exit code of the container, empty for the running containers.
+func containerExitCode(d *types.ContainerDetails) string {
+	if d.ExitCode == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*d.ExitCode)
+}
+
+// containerUptime returns the uptime from the container status reported by the runtime,
+// e.g. "5 minutes" for "Up 5 minutes (healthy)". It is empty for the containers that are not running.
+func containerUptime(status string) string {
+	uptime, ok := strings.CutPrefix(status, "Up ")
+	if !ok {
+		return ""
+	}
+
+	if i := strings.Index(uptime, " ("); i >= 0 {
+		uptime = uptime[:i]
+	}
+
+	return uptime
+}
+
+// toContainerDetails converts the containers to the container details
+// sorted by the lab name and the container name in the natural order.
+func toContainerDetails(containers []runtime.GenericContainer) []types.ContainerDetails {
 	contDetails := make([]types.ContainerDetails, 0, len(containers))
 
+	now := time.Now()
+
+	// get topo file path relative of the cwd
+	cwd, _ := os.Getwd()
+
 	// Gather details of each container
 	for _, cont := range containers {
-
-		// get topo file path relative of the cwd
-		cwd, _ := os.Getwd()
 		path, _ := filepath.Rel(cwd, cont.Labels[labels.TopoFile])
 
 		cdet := &types.ContainerDetails{
@@ -163,8 +312,12 @@ func printContainerInspect(containers []runtime.GenericContainer, format string)
 			LabPath:     path,
 			Image:       cont.Image,
 			State:       cont.State,
+			Status:      cont.Status,
 			IPv4Address: cont.GetContainerIPv4(),
 			IPv6Address: cont.GetContainerIPv6(),
+			Owner:       cont.Labels[labels.Owner],
+			ClabVersion: cont.Labels[labels.Version],
+			Health:      cont.Health,
 		}
 		cdet.ContainerID = cont.ShortID
 
@@ -180,55 +333,228 @@ func printContainerInspect(containers []runtime.GenericContainer, format string)
 			cdet.Kind = kind
 		}
 
+		if port, ok := cont.Labels[labels.NodeConsolePort]; ok {
+			cdet.ConsolePort = port
+		}
+
+		if ports, ok := cont.Labels[labels.NodeExposedPorts]; ok {
+			cdet.ExposedPorts = ports
+		}
+
+		setContainerRunState(cdet, &cont, now)
+
 		contDetails = append(contDetails, *cdet)
 	}
 
-	sort.Slice(contDetails, func(i, j int) bool {
+	sort.SliceStable(contDetails, func(i, j int) bool {
 		if contDetails[i].LabName == contDetails[j].LabName {
-			return contDetails[i].Name < contDetails[j].Name
+			return utils.NaturalLess(contDetails[i].Name, contDetails[j].Name)
 		}
-		return contDetails[i].LabName < contDetails[j].LabName
+		return utils.NaturalLess(contDetails[i].LabName, contDetails[j].LabName)
 	})
 
-	resultData := &types.LabData{Containers: contDetails}
+	return contDetails
+}
 
-	switch format {
-	case "json":
-		b, err := json.MarshalIndent(resultData, "", "  ")
+// setContainerRunState sets the restart count, the creation and the start time of the container to the container details.
+// The uptime at the time now is only set for the running containers, while the exit code
+// and the finish time of the last run are only set for the containers that are not running.
+func setContainerRunState(cdet *types.ContainerDetails, cont *runtime.GenericContainer, now time.Time) {
+	cdet.RestartCount = cont.RestartCount
+
+	if !cont.Created.IsZero() {
+		cdet.Created = cont.Created.Format(time.RFC3339)
+	}
+
+	if !cont.StartedAt.IsZero() {
+		cdet.StartedAt = cont.StartedAt.Format(time.RFC3339)
+	}
+
+	if cont.State == "running" && !cont.StartedAt.IsZero() {
+		cdet.Uptime = now.Sub(cont.StartedAt).Truncate(time.Second).String()
+	}
+
+	if cont.State == "running" || cont.StartedAt.IsZero() {
+		return
+	}
+
+	exitCode := cont.ExitCode
+	cdet.ExitCode = &exitCode
+
+	if !cont.FinishedAt.IsZero() {
+		cdet.FinishedAt = cont.FinishedAt.Format(time.RFC3339)
+	}
+}
+
+func printContainerInspect(ctx context.Context, c *clab.CLab, containers []runtime.GenericContainer,
+	format string, columns []string, showResources, showStats bool,
+) error {
+	contDetails := toContainerDetails(containers)
+
+	selected := false
+	for _, col := range columns {
+		col = strings.TrimSpace(col)
+		_, isResource := utils.StringInSlice(resourceColumns, col)
+		_, isStats := utils.StringInSlice(statsColumns, col)
+
+		if isResource || isStats {
+			selected = true
+		}
+	}
+
+	if showResources || showStats || selected {
+		addContainerResources(ctx, c, containers, contDetails)
+	}
+
+	// the json output has all the fields, the columns can't be selected for it
+	if (showResources || showStats) && len(columns) == 0 && format != "json" {
+		columns = defaultInspectColumns(all, contDetails)
+
+		if showResources {
+			columns = append(columns, resourceColumns...)
+		}
+
+		if showStats {
+			for _, col := range statsColumns {
+				if _, ok := utils.StringInSlice(columns, col); !ok {
+					columns = append(columns, col)
+				}
+			}
+		}
+	}
+
+	return writeContainerDetails(os.Stdout, contDetails, format, columns, all)
+}
+
+// addContainerResources sets the resource limits configured for the lab nodes and the current
+// resource usage of the containers to the container details. The limits are only known for the nodes
+// of the lab parsed from the topology file, the limits and the usage which are unknown are set to "n/a".
+func addContainerResources(ctx context.Context, c *clab.CLab, containers []runtime.GenericContainer,
+	contDetails []types.ContainerDetails,
+) {
+	// the lab nodes by the names of their containers
+	contNodes := make(map[string]nodes.Node, len(containers))
+	for _, cont := range containers {
+		if n, ok := c.Nodes[cont.Labels[labels.NodeName]]; ok && len(cont.Names) > 0 {
+			contNodes[cont.Names[0]] = n
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(contDetails))
+
+	for i := range contDetails {
+		go func(d *types.ContainerDetails) {
+			defer wg.Done()
+
+			n := contNodes[d.Name]
+
+			d.MemoryLimit, d.CPULimit = notAvailable, notAvailable
+			if n != nil {
+				d.MemoryLimit, d.CPULimit = nodeResourceLimits(n.Config())
+			}
+
+			var stats *runtime.ContainerStats
+			var err error
+
+			if n != nil {
+				stats, err = n.GetContainerStats(ctx)
+			} else {
+				stats, err = c.GlobalRuntime().GetContainerStats(ctx, d.Name)
+			}
+
+			setContainerUsage(d, stats, err)
+		}(&contDetails[i])
+	}
+
+	wg.Wait()
+}
+
+// setContainerUsage sets the resource usage of the container to the container details.
+// The usage is set to "-" for the nodes that don't run in a container and to "n/a"
+// when it failed to be retrieved.
+func setContainerUsage(d *types.ContainerDetails, stats *runtime.ContainerStats, err error) {
+	switch {
+	case errors.Is(err, runtime.ErrStatsNotSupported):
+		d.MemoryUsage, d.MemoryUsageLimit, d.CPUUsage = statsNotSupported, statsNotSupported, statsNotSupported
+
+		return
+	case err != nil:
+		log.Debugf("failed to get resource usage of container %s: %v", d.Name, err)
+		d.MemoryUsage, d.MemoryUsageLimit, d.CPUUsage = notAvailable, notAvailable, notAvailable
+
+		return
+	}
+
+	d.MemoryUsage = humanize.IBytes(stats.MemoryUsage)
+	d.CPUUsage = fmt.Sprintf("%.2f%%", stats.CPUPercent)
+
+	d.MemoryUsageLimit = d.MemoryUsage
+	if stats.MemoryLimit > 0 {
+		d.MemoryUsageLimit += " / " + humanize.IBytes(stats.MemoryLimit)
+	}
+}
+
+// nodeResourceLimits returns the memory and cpu limits configured for the node.
+// The limits which are not set are empty.
+func nodeResourceLimits(cfg *types.NodeConfig) (memory, cpu string) {
+	if cfg.CPU > 0 {
+		cpu = strconv.FormatFloat(cfg.CPU, 'f', -1, 64)
+	}
+
+	if cfg.CPUSet != "" {
+		cpuset := "cpuset " + cfg.CPUSet
+		if cpu != "" {
+			cpu += " (" + cpuset + ")"
+		} else {
+			cpu = cpuset
+		}
+	}
+
+	return cfg.Memory, cpu
+}
+
+// writeContainerDetails writes the container details to w in the format.
+// The columns select the fields of the table and csv formats, empty columns select the default ones.
+func writeContainerDetails(w io.Writer, contDetails []types.ContainerDetails, format string,
+	columns []string, allLabs bool,
+) error {
+	if format == "json" {
+		if len(columns) != 0 {
+			return fmt.Errorf("columns can't be selected for the json format")
+		}
+
+		b, err := json.MarshalIndent(&types.LabData{Containers: contDetails}, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal container details: %v", err)
 		}
-		fmt.Println(string(b))
-		return nil
 
+		_, err = fmt.Fprintln(w, string(b))
+
+		return err
+	}
+
+	if len(columns) == 0 {
+		columns = defaultInspectColumns(allLabs, contDetails)
+	}
+
+	cols, err := selectColumns(inspectColumns, columns)
+	if err != nil {
+		return err
+	}
+
+	switch format {
 	case "table":
-		tabData := toTableData(contDetails)
-		table := tablewriter.NewWriter(os.Stdout)
-		header := []string{
-			"Lab Name",
-			"Name",
-			"Container ID",
-			"Image",
-			"Kind",
-			"State",
-			"IPv4 Address",
-			"IPv6 Address",
-		}
-		if all {
-			table.SetHeader(append([]string{"#", "Topo Path"}, header...))
-		} else {
-			table.SetHeader(append([]string{"#"}, header[1:]...))
-		}
-		table.SetAutoFormatHeaders(false)
-		table.SetAutoWrapText(false)
 		// merge cells with lab name and topo file path
-		table.SetAutoMergeCellsByColumnIndex([]int{1, 2})
-		table.AppendBulk(tabData)
-		table.Render()
+		renderTable(w, cols, contDetails, "topo-path", "lab-name")
 
 		return nil
+	case "csv":
+		return writeCSV(w, cols, contDetails)
 	}
-	return nil
+
+	return fmt.Errorf("unknown output format %q, one of [table, json, csv] is expected", format)
 }
 
 type TokenFileResults struct {
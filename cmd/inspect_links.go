@@ -0,0 +1,157 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// inspectLinksFormat is the output format of the inspect links command.
+var inspectLinksFormat string
+
+var inspectLinksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "inspect the lab links",
+	Long: "show the deployment status of the lab links and verify that the links of a running lab are up\n" +
+		"reference: https://containerlab.dev/cmd/inspect/links/",
+	PreRunE:      sudoCheck,
+	SilenceUsage: true,
+	RunE:         inspectLinksFn,
+}
+
+func init() {
+	inspectCmd.AddCommand(inspectLinksCmd)
+	inspectLinksCmd.Flags().StringVarP(&inspectLinksFormat, "format", "f", "table",
+		"output format. One of [table, json]")
+}
+
+func inspectLinksFn(_ *cobra.Command, _ []string) error {
+	if inspectLinksFormat != "table" && inspectLinksFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use table or json", inspectLinksFormat)
+	}
+
+	if topo == "" {
+		return fmt.Errorf("provide a path to the topology file with --topo flag")
+	}
+
+	c, err := clab.NewContainerLab(
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	)
+	if err != nil {
+		return err
+	}
+
+	states, err := c.DeployedLinkStates()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	containers, err := c.ListNodesContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %s", err)
+	}
+
+	// the liveness of the links is verified for a running lab only
+	if len(containers) > 0 {
+		c.VerifyLinksLiveness(ctx, states)
+	}
+
+	if inspectLinksFormat == "json" {
+		b, err := json.MarshalIndent(states, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	printLinkStates(os.Stdout, states)
+
+	return nil
+}
+
+// linkStateRow returns the table row of the link state ls.
+func linkStateRow(ls *clab.LinkState) []string {
+	eps := make([]string, 2)
+	for i, ep := range ls.Endpoints {
+		if i < len(eps) {
+			eps[i] = ep.String()
+		}
+	}
+
+	mtu := ""
+	if ls.MTU > 0 {
+		mtu = strconv.Itoa(ls.MTU)
+	}
+
+	status := string(ls.Status)
+	details := ls.Error
+
+	if ls.Drift != "" {
+		status += " (drift)"
+		details = ls.Drift
+	}
+
+	return []string{strconv.Itoa(ls.Index), eps[0], eps[1], ls.Type, mtu, status, details}
+}
+
+// printLinkStates prints the link states as a table with the summary of the statuses in the footer.
+func printLinkStates(w io.Writer, states []*clab.LinkState) {
+	table := tablewriter.NewWriter(w)
+
+	table.SetHeader([]string{"#", "Endpoint A", "Endpoint B", "Type", "MTU", "Status", "Details"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	counts := map[clab.LinkStatus]int{}
+	drifted := 0
+	rows := make([][]string, 0, len(states))
+
+	for _, ls := range states {
+		counts[ls.Status]++
+		if ls.Drift != "" {
+			drifted++
+		}
+
+		rows = append(rows, linkStateRow(ls))
+	}
+
+	summary := []string{
+		fmt.Sprintf("%d deployed", counts[clab.LinkStatusDeployed]),
+		fmt.Sprintf("%d failed", counts[clab.LinkStatusFailed]),
+		fmt.Sprintf("%d skipped", counts[clab.LinkStatusSkipped]),
+		fmt.Sprintf("%d drifted", drifted),
+	}
+
+	table.AppendBulk(rows)
+	table.SetFooter([]string{"", "", "", "", "", "", strings.Join(summary, ", ")})
+	table.Render()
+}
@@ -0,0 +1,323 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// inspectTestDetails are the container details with the values that need quoting in csv.
+var inspectTestDetails = []types.ContainerDetails{
+	{
+		LabName:     "lab1",
+		LabPath:     "lab1.clab.yml",
+		Name:        "clab-lab1-srl1",
+		ContainerID: "0123456789ab",
+		Image:       "ghcr.io/nokia/srlinux:23.10",
+		Kind:        "nokia_srlinux",
+		State:       "running",
+		Status:      "Up 5 minutes (healthy)",
+		IPv4Address: "172.20.20.2/24",
+		IPv6Address: "3fff:172:20:20::2/64",
+		Owner:       "admin",
+	},
+	{
+		LabName:     "lab1",
+		LabPath:     "lab1.clab.yml",
+		Name:        `clab-lab1-client,"edge"`,
+		ContainerID: "ba9876543210",
+		Image:       "alpine:3",
+		Kind:        "linux",
+		State:       "exited",
+		Status:      "Exited (0) 2 hours ago",
+		IPv4Address: "N/A",
+		IPv6Address: "N/A",
+		Owner:       "doe, john",
+	},
+}
+
+func TestWriteContainerDetails(t *testing.T) {
+	tests := map[string]struct {
+		format  string
+		columns []string
+		allLabs bool
+		golden  string
+	}{
+		"csv default columns": {
+			format: "csv",
+			golden: "inspect-default.csv",
+		},
+		"csv all labs": {
+			format:  "csv",
+			allLabs: true,
+			golden:  "inspect-all.csv",
+		},
+		"csv column subset": {
+			format:  "csv",
+			columns: []string{"owner", "name", "uptime", "ipv4"},
+			golden:  "inspect-columns.csv",
+		},
+		"table column subset": {
+			format:  "table",
+			columns: []string{"name", "kind", "uptime"},
+			golden:  "inspect-columns.table",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			err := writeContainerDetails(&b, inspectTestDetails, tt.format, tt.columns, tt.allLabs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := os.ReadFile(filepath.Join("test_data", tt.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(string(want), b.String()); d != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestWriteContainerDetailsErrors(t *testing.T) {
+	var b bytes.Buffer
+
+	err := writeContainerDetails(&b, inspectTestDetails, "csv", []string{"name", "uptme"}, false)
+	want := `unknown column "uptme", available columns: topo-path, lab-name, name, container-id, image, ` +
+		`kind, state, health, ipv4, ipv6, owner, clab-version, uptime, memory-limit, memory-usage, cpu-limit, cpu-usage, ` +
+		`exit-code, restarts, started-at, finished-at`
+
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+
+	err = writeContainerDetails(&b, inspectTestDetails, "json", []string{"name"}, false)
+	if err == nil {
+		t.Error("no error for the columns selected for the json format")
+	}
+
+	err = writeContainerDetails(&b, inspectTestDetails, "yaml", nil, false)
+	if err == nil || !strings.Contains(err.Error(), `unknown output format "yaml"`) {
+		t.Errorf("got error %v for the unknown format", err)
+	}
+}
+
+func TestContainerUptime(t *testing.T) {
+	tests := map[string]string{
+		"Up 5 minutes":                 "5 minutes",
+		"Up About an hour (unhealthy)": "About an hour",
+		"Up 2 days (Paused)":           "2 days",
+		"Exited (137) 10 seconds ago":  "",
+		"Created":                      "",
+		"":                             "",
+	}
+
+	for status, want := range tests {
+		if got := containerUptime(status); got != want {
+			t.Errorf("containerUptime(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestSetContainerRunState(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 59, 0, 0, time.UTC)
+	started := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(5 * time.Second)
+	now := started.Add(90*time.Minute + 1500*time.Millisecond)
+	exitCode := 137
+
+	tests := map[string]struct {
+		cont *runtime.GenericContainer
+		want *types.ContainerDetails
+	}{
+		"running": {
+			cont: &runtime.GenericContainer{State: "running", Created: created, StartedAt: started},
+			want: &types.ContainerDetails{
+				Created: "2024-03-01T09:59:00Z", StartedAt: "2024-03-01T10:00:00Z", Uptime: "1h30m1s",
+			},
+		},
+		"crash-looping": {
+			cont: &runtime.GenericContainer{
+				State: "restarting", ExitCode: 137, RestartCount: 12,
+				StartedAt: started, FinishedAt: finished,
+			},
+			want: &types.ContainerDetails{
+				ExitCode: &exitCode, RestartCount: 12,
+				StartedAt: "2024-03-01T10:00:00Z", FinishedAt: "2024-03-01T10:00:05Z",
+			},
+		},
+		"never started": {
+			cont: &runtime.GenericContainer{State: "created"},
+			want: &types.ContainerDetails{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := &types.ContainerDetails{}
+			setContainerRunState(got, tt.cont, now)
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("container details mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestContainerStateAndExitCode(t *testing.T) {
+	exitCode := 1
+
+	tests := map[string]struct {
+		details      *types.ContainerDetails
+		wantState    string
+		wantExitCode string
+	}{
+		"running": {
+			details:   &types.ContainerDetails{State: "running"},
+			wantState: "running",
+		},
+		"restarted once": {
+			details:   &types.ContainerDetails{State: "running", RestartCount: 1},
+			wantState: "running (1 restart)",
+		},
+		"crash-looping": {
+			details:      &types.ContainerDetails{State: "restarting", RestartCount: 7, ExitCode: &exitCode},
+			wantState:    "restarting (7 restarts)",
+			wantExitCode: "1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := containerState(tt.details); got != tt.wantState {
+				t.Errorf("got state %q, want %q", got, tt.wantState)
+			}
+
+			if got := containerExitCode(tt.details); got != tt.wantExitCode {
+				t.Errorf("got exit code %q, want %q", got, tt.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestNodeResourceLimits(t *testing.T) {
+	tests := map[string]struct {
+		cfg        *types.NodeConfig
+		wantMemory string
+		wantCPU    string
+	}{
+		"no limits": {
+			cfg: &types.NodeConfig{},
+		},
+		"memory and cpu": {
+			cfg:        &types.NodeConfig{Memory: "1Gb", CPU: 1.5},
+			wantMemory: "1Gb",
+			wantCPU:    "1.5",
+		},
+		"cpuset only": {
+			cfg:     &types.NodeConfig{CPUSet: "0-3"},
+			wantCPU: "cpuset 0-3",
+		},
+		"cpu and cpuset": {
+			cfg:     &types.NodeConfig{CPU: 2, CPUSet: "0,1"},
+			wantCPU: "2 (cpuset 0,1)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			memory, cpu := nodeResourceLimits(tt.cfg)
+			if memory != tt.wantMemory || cpu != tt.wantCPU {
+				t.Errorf("got limits %q and %q, want %q and %q", memory, cpu, tt.wantMemory, tt.wantCPU)
+			}
+		})
+	}
+}
+
+func TestSetContainerUsage(t *testing.T) {
+	tests := map[string]struct {
+		stats *runtime.ContainerStats
+		err   error
+		want  types.ContainerDetails
+	}{
+		"usage with limit": {
+			stats: &runtime.ContainerStats{MemoryUsage: 512 << 20, MemoryLimit: 2 << 30, CPUPercent: 3.456},
+			want:  types.ContainerDetails{MemoryUsage: "512 MiB", MemoryUsageLimit: "512 MiB / 2.0 GiB", CPUUsage: "3.46%"},
+		},
+		"usage without limit": {
+			stats: &runtime.ContainerStats{MemoryUsage: 512 << 20},
+			want:  types.ContainerDetails{MemoryUsage: "512 MiB", MemoryUsageLimit: "512 MiB", CPUUsage: "0.00%"},
+		},
+		"not supported": {
+			err:  fmt.Errorf("%w for \"bridge\" node kind", runtime.ErrStatsNotSupported),
+			want: types.ContainerDetails{MemoryUsage: "-", MemoryUsageLimit: "-", CPUUsage: "-"},
+		},
+		"failed": {
+			err:  errors.New("container not found"),
+			want: types.ContainerDetails{MemoryUsage: "n/a", MemoryUsageLimit: "n/a", CPUUsage: "n/a"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := types.ContainerDetails{}
+			setContainerUsage(&got, tt.stats, tt.err)
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("container details diff (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestDefaultInspectColumns(t *testing.T) {
+	noHealthCheck := []types.ContainerDetails{{Health: runtime.HealthNone}, {}}
+	healthCheck := []types.ContainerDetails{{Health: runtime.HealthNone}, {Health: runtime.HealthUnhealthy}}
+
+	tests := map[string]struct {
+		allLabs     bool
+		contDetails []types.ContainerDetails
+		want        []string
+	}{
+		"no health check": {
+			contDetails: noHealthCheck,
+			want:        []string{"name", "container-id", "image", "kind", "state", "ipv4", "ipv6"},
+		},
+		"health check": {
+			contDetails: healthCheck,
+			want:        []string{"name", "container-id", "image", "kind", "state", "health", "ipv4", "ipv6"},
+		},
+		"all labs": {
+			allLabs:     true,
+			contDetails: noHealthCheck,
+			want:        []string{"topo-path", "lab-name", "name", "container-id", "image", "kind", "state", "ipv4", "ipv6"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tt.want, defaultInspectColumns(tt.allLabs, tt.contDetails)); d != "" {
+				t.Errorf("columns diff (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var lintFormat string
+
+// lintCmd represents the lint command.
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "lint the topology file",
+	Long: "check the topology file for errors and the best practice violations without deploying the lab\n" +
+		"reference: https://containerlab.dev/cmd/lint/",
+	RunE: lintFn,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "table", "output format. One of [table, json]")
+	lintCmd.Flags().BoolVarP(&relaxedNodeNames, "relaxed-node-names", "", false,
+		"allow the node names that are not valid hostnames, the hostnames are derived from such names")
+}
+
+func lintFn(_ *cobra.Command, _ []string) error {
+	if lintFormat != "table" && lintFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use table or json", lintFormat)
+	}
+
+	if topo == "" {
+		return fmt.Errorf("provide a path to the topology file with --topo flag")
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	if relaxedNodeNames {
+		opts = append(opts, clab.WithRelaxedNodeNames())
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	issues := c.Lint(ctx)
+
+	var errCount, warnCount int
+	for _, i := range issues {
+		if i.Severity == clab.LintSeverityError {
+			errCount++
+			continue
+		}
+		warnCount++
+	}
+
+	if lintFormat == "json" {
+		if issues == nil {
+			issues = []*clab.LintIssue{}
+		}
+
+		b, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+	} else if len(issues) > 0 {
+		printLintIssues(issues)
+	}
+
+	if errCount > 0 {
+		return fmt.Errorf("found %d error(s) and %d warning(s) in topology %s", errCount, warnCount, topo)
+	}
+
+	log.Infof("Found no errors and %d warning(s) in topology %s", warnCount, topo)
+
+	return nil
+}
+
+// printLintIssues prints the lint issues as a table.
+func printLintIssues(issues []*clab.LintIssue) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{"Severity", "Rule", "Node", "Message"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := make([][]string, 0, len(issues))
+	for _, i := range issues {
+		rows = append(rows, []string{i.Severity, i.Rule, i.Node, i.Message})
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
+}
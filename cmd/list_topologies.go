@@ -0,0 +1,102 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+)
+
+var listTopologiesFormat string
+
+// listTopologiesCmd represents the list-topologies command.
+var listTopologiesCmd = &cobra.Command{
+	Use:   "list-topologies [dir]",
+	Short: "list the topology files in a directory",
+	Long: "recursively find the topology files in a directory and list the labs they define without deploying them\n" +
+		"reference: https://containerlab.dev/cmd/list-topologies/",
+	Args: cobra.MaximumNArgs(1),
+	RunE: listTopologiesFn,
+}
+
+func init() {
+	rootCmd.AddCommand(listTopologiesCmd)
+	listTopologiesCmd.Flags().StringVarP(&listTopologiesFormat, "format", "f", "table",
+		"output format. One of [table, json]")
+}
+
+func listTopologiesFn(_ *cobra.Command, args []string) error {
+	if listTopologiesFormat != "table" && listTopologiesFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use table or json", listTopologiesFormat)
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	summaries, err := clab.ScanTopologies(dir, timeout)
+	if err != nil {
+		return err
+	}
+
+	// the files failing to parse are reported, but don't fail the listing
+	for _, s := range summaries {
+		if s.Error != "" {
+			log.Warnf("failed to parse topology file %s: %s", s.Path, s.Error)
+		}
+	}
+
+	if listTopologiesFormat == "json" {
+		if summaries == nil {
+			summaries = []*clab.TopologySummary{}
+		}
+
+		b, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	if len(summaries) == 0 {
+		log.Infof("no topology files found in %s", dir)
+		return nil
+	}
+
+	printTopologySummaries(os.Stdout, summaries)
+
+	return nil
+}
+
+// printTopologySummaries prints the topologies parsed successfully as a table.
+func printTopologySummaries(w io.Writer, summaries []*clab.TopologySummary) {
+	table := tablewriter.NewWriter(w)
+
+	table.SetHeader([]string{"Topology", "Name", "Nodes", "Links"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	for _, s := range summaries {
+		if s.Error != "" {
+			continue
+		}
+
+		table.Append([]string{s.Path, s.Name, strconv.Itoa(s.Nodes), strconv.Itoa(s.Links)})
+	}
+
+	table.Render()
+}
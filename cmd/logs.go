@@ -0,0 +1,108 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	// logsFollow keeps streaming the new logs of the node.
+	logsFollow bool
+	// logsTail is the number of the last log lines to show.
+	logsTail int
+	// logsSince shows the logs produced within the duration.
+	logsSince time.Duration
+)
+
+// logsCmd represents the logs command.
+var logsCmd = &cobra.Command{
+	Use:   "logs <node>",
+	Short: "show the logs of a lab node",
+	Long: "show the stdout and stderr of the lab node container regardless of the runtime it is running with\n" +
+		"reference: https://containerlab.dev/cmd/logs/",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: sudoCheck,
+	RunE:    logsFn,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep streaming the new logs of the node")
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "", 0, "number of the last log lines to show, all lines are shown when 0")
+	logsCmd.Flags().DurationVarP(&logsSince, "since", "", 0, "show the logs produced within the duration, e.g. 10m")
+}
+
+func logsFn(_ *cobra.Command, args []string) error {
+	if logsTail < 0 {
+		return fmt.Errorf("invalid --tail value %d, must not be negative", logsTail)
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	// the node is referred to by its name in the topology or by its container name
+	contName := args[0]
+	if n, ok := c.Nodes[contName]; ok {
+		contName = n.Config().LongName
+	}
+
+	r, err := c.GetNodeRuntime(contName)
+	if err != nil {
+		return err
+	}
+
+	// the followed logs are streamed until interrupted
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	logOpts := runtime.LogOptions{
+		Follow: logsFollow,
+		Tail:   logsTail,
+	}
+
+	if logsSince > 0 {
+		logOpts.Since = time.Now().Add(-logsSince)
+	}
+
+	rc, err := r.GetContainerLogs(ctx, contName, logOpts)
+	if err != nil {
+		return fmt.Errorf("failed to get the logs of %s: %w", contName, err)
+	}
+	defer rc.Close()
+
+	if _, err := io.Copy(os.Stdout, rc); err != nil && !errors.Is(err, context.Canceled) && ctx.Err() == nil {
+		return fmt.Errorf("failed to read the logs of %s: %w", contName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// outputColumn is a column of the tabular command output that can be selected with the --columns flag.
+type outputColumn[T any] struct {
+	// name is the column name used by the --columns flag and by the csv header row
+	name string
+	// header is the column header of the table output
+	header string
+	value  func(*T) string
+}
+
+// selectColumns returns the columns with the given names in the order of the names.
+func selectColumns[T any](available []outputColumn[T], names []string) ([]outputColumn[T], error) {
+	cols := make([]outputColumn[T], 0, len(names))
+
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+
+		found := false
+
+		for _, col := range available {
+			if col.name == n {
+				cols = append(cols, col)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown column %q, available columns: %s", n, columnNames(available))
+		}
+	}
+
+	return cols, nil
+}
+
+// columnNames returns the comma separated names of the columns.
+func columnNames[T any](cols []outputColumn[T]) string {
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, col.name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// writeCSV writes the rows as csv with the header row of the column names.
+// The values containing commas, quotes or newlines are quoted.
+func writeCSV[T any](w io.Writer, cols []outputColumn[T], rows []T) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, 0, len(cols))
+	for _, col := range cols {
+		header = append(header, col.name)
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i := range rows {
+		record := make([]string, 0, len(cols))
+		for _, col := range cols {
+			record = append(record, col.value(&rows[i]))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// renderTable renders the rows as a table with the row number column followed by the columns.
+// The cells of the columns in mergeCols are merged when they have the same values in the adjacent rows.
+func renderTable[T any](w io.Writer, cols []outputColumn[T], rows []T, mergeCols ...string) {
+	table := tablewriter.NewWriter(w)
+
+	header := []string{"#"}
+	for _, col := range cols {
+		header = append(header, col.header)
+	}
+
+	var merge []int
+
+	for i, col := range cols {
+		for _, m := range mergeCols {
+			if col.name == m {
+				// the first column is the row number
+				merge = append(merge, i+1)
+			}
+		}
+	}
+
+	data := make([][]string, 0, len(rows))
+	for i := range rows {
+		row := []string{strconv.Itoa(i + 1)}
+		for _, col := range cols {
+			row = append(row, col.value(&rows[i]))
+		}
+
+		data = append(data, row)
+	}
+
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	if len(merge) != 0 {
+		table.SetAutoMergeCellsByColumnIndex(merge)
+	}
+
+	table.AppendBulk(data)
+	table.Render()
+}
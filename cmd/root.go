@@ -6,13 +6,13 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
 	"github.com/srl-labs/containerlab/utils"
 )
 
@@ -21,6 +21,8 @@ var (
 	debug      bool
 	timeout    time.Duration
 	logLevel   string
+	// tmpMaxAge is the max age of the clab temp dir entries pruned when a topology is loaded.
+	tmpMaxAge time.Duration
 )
 
 // path to the topology file.
@@ -30,6 +32,8 @@ var (
 	varsFile string
 	graph    bool
 	rt       string
+	// runtimeSocket is the path or the URI of the container runtime API socket.
+	runtimeSocket string
 )
 
 // lab name.
@@ -61,8 +65,13 @@ func init() {
 	rootCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "", 120*time.Second,
 		"timeout for external API requests (e.g. container runtimes), e.g: 30s, 1m, 2m30s")
 	rootCmd.PersistentFlags().StringVarP(&rt, "runtime", "r", "", "container runtime")
+	rootCmd.PersistentFlags().StringVarP(&runtimeSocket, "runtime-socket", "", "",
+		"path or URI of the container runtime API socket, overrides the runtime default and the topology settings")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "", "info",
 		"logging level; one of [trace, debug, info, warning, error, fatal]")
+	rootCmd.PersistentFlags().DurationVarP(&tmpMaxAge, "tmp-max-age", "", clab.DefaultTmpMaxAge,
+		"max age of the clab temp dir entries pruned when a topology is loaded, 0 disables the pruning. "+
+			"Overrides the "+clab.TmpMaxAgeEnv+" env var")
 }
 
 func sudoCheck(_ *cobra.Command, _ []string) error {
@@ -90,6 +99,11 @@ func preRunFn(cmd *cobra.Command, _ []string) error {
 	// setting output to stderr, so that json outputs can be parsed
 	log.SetOutput(os.Stderr)
 
+	// the flag takes precedence over the env var only when it is set
+	if cmd.Flags().Changed("tmp-max-age") {
+		clab.SetTmpMaxAge(tmpMaxAge)
+	}
+
 	return getTopoFilePath(cmd)
 }
 
@@ -100,7 +114,9 @@ func preRunFn(cmd *cobra.Command, _ []string) error {
 func getTopoFilePath(cmd *cobra.Command) error {
 	// set commands which may use topo file find functionality, the rest don't need it
 	if !(cmd.Name() == "deploy" || cmd.Name() == "destroy" || cmd.Name() == "inspect" ||
-		cmd.Name() == "save" || cmd.Name() == "graph" || cmd.Name() == "exec") {
+		cmd.Name() == "save" || cmd.Name() == "graph" || cmd.Name() == "exec" ||
+		cmd.Name() == "render" || cmd.Name() == "reachability" || cmd.Name() == "console" ||
+		cmd.Name() == "diagnostics" || cmd.Name() == "destroy-all" || cmd.Name() == "deploy-all") {
 		return nil
 	}
 
@@ -141,7 +157,9 @@ func getTopoFilePath(cmd *cobra.Command) error {
 			}
 
 		default:
-			return fmt.Errorf("unsupported git repository: %s", topo)
+			// other http(s) urls are treated as links to the topology files
+			// which are downloaded when the lab is instantiated
+			log.Debugf("topology file will be downloaded from %s", topo)
 		}
 	}
 
@@ -37,6 +37,7 @@ Refer to the https://containerlab.dev/cmd/save/ documentation to see the exact c
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Socket:           runtimeSocket,
 				},
 			),
 			clab.WithDebug(debug),
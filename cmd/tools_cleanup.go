@@ -0,0 +1,89 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	// cleanupTmp selects the clab temp dir to be cleaned up.
+	cleanupTmp bool
+	// cleanupDryRun lists the entries to be removed without removing them.
+	cleanupDryRun bool
+)
+
+// cleanupCmd represents the tools cleanup command.
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "remove the leftovers of the labs from the host",
+	Long: "remove the files kept in the clab temp directory that are not used by the labs deployed on the host\n" +
+		"reference: https://containerlab.dev/cmd/tools/cleanup/",
+	PreRunE: sudoCheck,
+	RunE:    cleanupFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(cleanupCmd)
+	cleanupCmd.Flags().BoolVarP(&cleanupTmp, "tmp", "", false,
+		"remove the entries of the clab temp directory not used by the deployed labs regardless of their age")
+	cleanupCmd.Flags().BoolVarP(&cleanupDryRun, "dry-run", "", false,
+		"list the entries to be removed without removing them")
+}
+
+func cleanupFn(_ *cobra.Command, _ []string) error {
+	if !cleanupTmp {
+		return errors.New("nothing to clean up, select the clab temp directory with the --tmp flag")
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	pruned, err := c.PruneTmpDir(ctx, 0, cleanupDryRun)
+
+	for _, p := range pruned {
+		if cleanupDryRun {
+			fmt.Println(p)
+			continue
+		}
+
+		log.Infof("Removed %s", p)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(pruned) == 0 {
+		log.Info("The clab temp directory has nothing to clean up")
+	}
+
+	return nil
+}
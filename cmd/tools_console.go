@@ -0,0 +1,118 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/internal/console"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"golang.org/x/term"
+)
+
+// consoleCmd represents the tools console command.
+var consoleCmd = &cobra.Command{
+	Use:   "console <node>",
+	Short: "connect to the serial console of a node",
+	Long: "open an interactive session to the serial console of a node published with the console.expose option\n" +
+		"reference: https://containerlab.dev/cmd/tools/console/",
+	Args: cobra.ExactArgs(1),
+	RunE: consoleFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(consoleCmd)
+}
+
+func consoleFn(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	// the lab name is taken from the topology file when it is not set explicitly
+	if name == "" {
+		opts = append(opts, clab.WithTopoPath(topo, varsFile))
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	labName := name
+	if labName == "" {
+		labName = c.Config.Name
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filters := types.FilterFromLabelStrings([]string{
+		fmt.Sprintf("%s=%s", labels.Containerlab, labName),
+		fmt.Sprintf("%s=%s", labels.NodeName, nodeName),
+	})
+
+	cnts, err := c.ListContainers(ctx, filters)
+	if err != nil {
+		return err
+	}
+
+	if len(cnts) == 0 {
+		return fmt.Errorf("node %q of lab %q is not running", nodeName, labName)
+	}
+
+	kind := cnts[0].Labels[labels.NodeKind]
+	if _, ok := nodes.ConsolePorts[kind]; !ok {
+		return fmt.Errorf("node %q: console access is not supported by the %q kind", nodeName, kind)
+	}
+
+	port, ok := cnts[0].Labels[labels.NodeConsolePort]
+	if !ok {
+		return fmt.Errorf("node %q: console is not exposed, set console.expose to true for the node and redeploy the lab",
+			nodeName)
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", port), timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the console of node %q: %w", nodeName, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Connected to the console of %s. Escape character is '^]'.\r\n", nodeName)
+
+	// raw terminal mode passes the control characters to the console
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		defer term.Restore(fd, state)
+	}
+
+	err = console.NewSession(conn, os.Stdin, os.Stdout).Run()
+
+	fmt.Fprint(os.Stderr, "\r\nConnection closed.\r\n")
+
+	return err
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	countersNode   string
+	countersFormat string
+)
+
+// countersCmd represents the tools counters command.
+var countersCmd = &cobra.Command{
+	Use:   "counters",
+	Short: "show interface counters of a lab node",
+	Long: "show the packet, byte, error and drop counters of the interfaces of a lab node\n" +
+		"reference: https://containerlab.dev/cmd/tools/counters/",
+	PreRunE: sudoCheck,
+	RunE:    countersFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(countersCmd)
+	countersCmd.Flags().StringVarP(&countersNode, "node", "n", "", "name of the node to show the counters of")
+	countersCmd.Flags().StringVarP(&countersFormat, "format", "f", "table", "output format. One of [table, json]")
+
+	countersCmd.MarkFlagRequired("node")
+}
+
+func countersFn(_ *cobra.Command, _ []string) error {
+	if countersFormat != "table" && countersFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use table or json", countersFormat)
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	counters, err := c.NodeInterfaceCounters(ctx, countersNode)
+	if err != nil {
+		return err
+	}
+
+	if countersFormat == "json" {
+		b, err := json.MarshalIndent(counters, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	printInterfaceCounters(counters)
+
+	return nil
+}
+
+func printInterfaceCounters(counters []*links.InterfaceCounters) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{
+		"Interface",
+		"RX Packets", "RX Bytes", "RX Errors", "RX Drops",
+		"TX Packets", "TX Bytes", "TX Errors", "TX Drops",
+	})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := make([][]string, 0, len(counters))
+
+	for _, c := range counters {
+		row := []string{c.Interface}
+		for _, v := range []uint64{
+			c.RxPackets, c.RxBytes, c.RxErrors, c.RxDropped,
+			c.TxPackets, c.TxBytes, c.TxErrors, c.TxDropped,
+		} {
+			row = append(row, strconv.FormatUint(v, 10))
+		}
+
+		rows = append(rows, row)
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
+}
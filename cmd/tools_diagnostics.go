@@ -0,0 +1,85 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// diagnosticsCmd represents the tools diagnostics command.
+var diagnosticsCmd = &cobra.Command{
+	Use:   "diagnostics",
+	Short: "collect diagnostics bundles (show tech) from the lab nodes",
+	Long: "run the kind-specific diagnostics command on the lab nodes and store the resulting bundles in the nodes' lab directories\n" +
+		"reference: https://containerlab.dev/cmd/tools/diagnostics/",
+	PreRunE: sudoCheck,
+	RunE:    diagnosticsFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(diagnosticsCmd)
+	diagnosticsCmd.Flags().StringSliceVarP(&nodeFilter, "node", "", []string{},
+		"comma separated list of nodes to collect the diagnostics from. All nodes if not set")
+}
+
+func diagnosticsFn(_ *cobra.Command, _ []string) error {
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithNodeFilter(nodeFilter),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var failed int
+	mu := new(sync.Mutex)
+
+	wg.Add(len(c.Nodes))
+	for _, node := range c.Nodes {
+		go func(node nodes.Node) {
+			defer wg.Done()
+
+			err := node.CollectDiagnostics(ctx)
+			if err != nil {
+				log.Errorf("err: %v", err)
+
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("failed to collect diagnostics from %d node(s)", failed)
+	}
+
+	return nil
+}
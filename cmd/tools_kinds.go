@@ -0,0 +1,112 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func init() {
+	toolsCmd.AddCommand(kindsCmd)
+	kindsCmd.AddCommand(kindsDescribeCmd)
+}
+
+// kindsCmd represents the tools kinds command.
+var kindsCmd = &cobra.Command{
+	Use:   "kinds",
+	Short: "node kinds operations",
+}
+
+// kindsDescribeCmd represents the tools kinds describe command.
+var kindsDescribeCmd = &cobra.Command{
+	Use:   "describe <kind>",
+	Short: "print the node definition fields supported by a kind",
+	Long: "print the support matrix of the node definition fields of a kind, the ignored fields have no effect\n" +
+		"on the nodes of the kind and the forbidden fields fail the topology validation,\n" +
+		"the sysctls the kind sets on its nodes are listed after the matrix\n" +
+		"reference: https://containerlab.dev/cmd/tools/kinds/describe/",
+	Args: cobra.ExactArgs(1),
+	RunE: kindsDescribeFn,
+}
+
+func kindsDescribeFn(_ *cobra.Command, args []string) error {
+	c, err := clab.NewContainerLab()
+	if err != nil {
+		return err
+	}
+
+	entry := c.Reg.Kind(strings.ToLower(args[0]))
+	if entry == nil {
+		return fmt.Errorf("kind %q is not supported. Supported kinds are %q",
+			args[0], strings.Join(c.Reg.GetRegisteredNodeKindNames(), ", "))
+	}
+
+	fmt.Printf("Kind %s (%s)\n", args[0], strings.Join(entry.KindNames(), ", "))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Field", "Support"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(kindFieldsRows(entry.Fields()))
+	table.Render()
+
+	n, err := c.Reg.NewNodeOfKind(strings.ToLower(args[0]))
+	if err != nil {
+		return err
+	}
+
+	if sysctls := n.DefaultSysctls(); len(sysctls) > 0 {
+		fmt.Println("Sysctls required by the kind:")
+
+		keys := make([]string, 0, len(sysctls))
+		for k := range sysctls {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			fmt.Printf("  %s=%s\n", k, sysctls[k])
+		}
+	}
+
+	return nil
+}
+
+// kindFieldsRows returns the table rows of the support matrix of a kind. Every node definition field
+// is followed by the values of the field which support is declared separately.
+func kindFieldsRows(fields nodes.KindFields) [][]string {
+	var rows [][]string
+
+	for _, f := range types.NodeDefinitionFields() {
+		if f == "kind" {
+			continue
+		}
+
+		rows = append(rows, []string{f, string(fields.Support(f))})
+
+		var values []string
+		for k := range fields {
+			if strings.HasPrefix(k, f+"=") {
+				values = append(values, k)
+			}
+		}
+		sort.Strings(values)
+
+		for _, v := range values {
+			rows = append(rows, []string{v, string(fields[v])})
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// linkCmd represents the tools link command.
+var linkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "link operations on a running lab",
+}
+
+// linkDestroyAllCmd represents the tools link destroy-all command.
+var linkDestroyAllCmd = &cobra.Command{
+	Use:   "destroy-all",
+	Short: "remove all links of a running lab leaving its nodes running",
+	Long: "remove all links of a running lab, the nodes and the management network are left intact\n" +
+		"reference: https://containerlab.dev/cmd/tools/link/",
+	PreRunE: sudoCheck,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return linkAllFn(func(ctx context.Context, c *clab.CLab) error {
+			if err := c.RemoveLinks(ctx); err != nil {
+				return err
+			}
+
+			log.Infof("Removed %d links of lab %s", len(c.Links), c.Config.Name)
+
+			return nil
+		})
+	},
+}
+
+// linkDeployAllCmd represents the tools link deploy-all command.
+var linkDeployAllCmd = &cobra.Command{
+	Use:   "deploy-all",
+	Short: "deploy all links of a running lab",
+	Long: "deploy the links of a running lab removed with the destroy-all command, the deployed links are left as is\n" +
+		"reference: https://containerlab.dev/cmd/tools/link/",
+	PreRunE: sudoCheck,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return linkAllFn(func(ctx context.Context, c *clab.CLab) error {
+			if err := c.DeployLinks(ctx); err != nil {
+				return err
+			}
+
+			log.Infof("Deployed %d links of lab %s", len(c.Links), c.Config.Name)
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	toolsCmd.AddCommand(linkCmd)
+	linkCmd.AddCommand(linkDestroyAllCmd)
+	linkCmd.AddCommand(linkDeployAllCmd)
+}
+
+// linkAllFn resolves the links of the running lab and runs the operation on them.
+func linkAllFn(op func(ctx context.Context, c *clab.CLab) error) error {
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// the running lab network is looked up to populate the mgmt bridge name the mgmt-net links use
+	if err := c.CreateNetwork(ctx); err != nil {
+		return err
+	}
+
+	if err := links.SetMgmtNetUnderlayingBridge(c.Config.Mgmt.Bridge); err != nil {
+		return err
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		return err
+	}
+
+	return op(ctx, c)
+}
@@ -0,0 +1,290 @@
+// Copyright 2023 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/internal/tc"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+var (
+	mirrorSrc       string
+	mirrorDst       string
+	mirrorDirection string
+	mirrorNode      string
+)
+
+func init() {
+	toolsCmd.AddCommand(mirrorCmd)
+
+	mirrorCmd.AddCommand(mirrorAddCmd)
+	mirrorAddCmd.Flags().StringVarP(&mirrorSrc, "src", "", "",
+		"mirrored interface in the format of <container-name>:<interface-name>")
+	mirrorAddCmd.Flags().StringVarP(&mirrorDst, "dst", "", "",
+		"interface receiving the mirrored traffic in the format of <container-name>:<interface-name>")
+	mirrorAddCmd.Flags().StringVarP(&mirrorDirection, "direction", "", string(tc.MirrorBoth),
+		"direction of the mirrored traffic. One of [both, rx, tx]")
+
+	mirrorAddCmd.MarkFlagRequired("src")
+	mirrorAddCmd.MarkFlagRequired("dst")
+
+	mirrorCmd.AddCommand(mirrorDelCmd)
+	mirrorDelCmd.Flags().StringVarP(&mirrorSrc, "src", "", "",
+		"mirrored interface in the format of <container-name>:<interface-name>")
+
+	mirrorDelCmd.MarkFlagRequired("src")
+
+	mirrorCmd.AddCommand(mirrorListCmd)
+	mirrorListCmd.Flags().StringVarP(&mirrorNode, "node", "n", "",
+		"container to list the mirrors of, all containerlab containers are listed if not set")
+}
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror",
+	Short: "traffic mirroring operations",
+}
+
+var mirrorAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "mirror traffic of an interface to another interface",
+	Long: `The traffic of the source interface is mirrored with the tc mirred action.
+When the destination interface is in another container and is not connected to the source container,
+it is created as a veth interface connecting the destination container with the source container.`,
+	RunE: mirrorAddFn,
+}
+
+var mirrorDelCmd = &cobra.Command{
+	Use:   "del",
+	Short: "remove the mirrors of an interface",
+	RunE:  mirrorDelFn,
+}
+
+var mirrorListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the traffic mirrors",
+	RunE:  mirrorListFn,
+}
+
+// initMirrorRuntime returns the initialized container runtime.
+func initMirrorRuntime() (runtime.ContainerRuntime, error) {
+	_, rinit, err := clab.RuntimeInitializer(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rinit()
+
+	err = r.Init(
+		runtime.WithConfig(
+			&runtime.RuntimeConfig{
+				Timeout: timeout,
+				Socket:  runtimeSocket,
+			},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// containerNS returns the network namespace of the container. Must be closed after use.
+func containerNS(ctx context.Context, r runtime.ContainerRuntime, cName string) (ns.NetNS, error) {
+	nsPath, err := r.GetNSPath(ctx, cName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network namespace of %s: %w", cName, err)
+	}
+
+	nsh, err := ns.GetNS(nsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open network namespace of %s: %w", cName, err)
+	}
+
+	return nsh, nil
+}
+
+// mirrorEndpoint returns the mirror endpoint for the <container-name>:<interface-name> string.
+// The namespace of the returned endpoint must be closed after use.
+func mirrorEndpoint(ctx context.Context, r runtime.ContainerRuntime, s string) (*tc.MirrorEndpoint, error) {
+	arr := strings.SplitN(s, ":", 2)
+	if len(arr) != 2 || arr[0] == "" || arr[1] == "" {
+		return nil, fmt.Errorf("malformed endpoint %q, expected <container-name>:<interface-name>", s)
+	}
+
+	nsh, err := containerNS(ctx, r, arr[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &tc.MirrorEndpoint{
+		Node:  arr[0],
+		Iface: arr[1],
+		NS:    nsh,
+	}, nil
+}
+
+func mirrorAddFn(_ *cobra.Command, _ []string) error {
+	dir, err := tc.ParseMirrorDirection(mirrorDirection)
+	if err != nil {
+		return err
+	}
+
+	r, err := initMirrorRuntime()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src, err := mirrorEndpoint(ctx, r, mirrorSrc)
+	if err != nil {
+		return err
+	}
+	defer src.NS.Close()
+
+	dst, err := mirrorEndpoint(ctx, r, mirrorDst)
+	if err != nil {
+		return err
+	}
+	defer dst.NS.Close()
+
+	if err := tc.AddMirror(src, dst, dir); err != nil {
+		return err
+	}
+
+	log.Infof("Traffic of %s (%s) is mirrored to %s", src, dir, dst)
+
+	return nil
+}
+
+func mirrorDelFn(_ *cobra.Command, _ []string) error {
+	r, err := initMirrorRuntime()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src, err := mirrorEndpoint(ctx, r, mirrorSrc)
+	if err != nil {
+		return err
+	}
+	defer src.NS.Close()
+
+	n, err := tc.DeleteMirrors(src)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		log.Infof("No mirrors of %s found", src)
+		return nil
+	}
+
+	log.Infof("Removed %d mirror(s) of %s", n, src)
+
+	return nil
+}
+
+func mirrorListFn(_ *cobra.Command, _ []string) error {
+	r, err := initMirrorRuntime()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cNames := []string{mirrorNode}
+
+	if mirrorNode == "" {
+		containers, err := r.ListContainers(ctx, []*types.GenericFilter{{
+			FilterType: "label",
+			Field:      labels.Containerlab, Operator: "exists",
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to list containers: %w", err)
+		}
+
+		cNames = cNames[:0]
+		for _, cnt := range containers {
+			if len(cnt.Names) > 0 {
+				cNames = append(cNames, strings.TrimPrefix(cnt.Names[0], "/"))
+			}
+		}
+	}
+
+	var mirrors []tc.Mirror
+
+	for _, cName := range cNames {
+		nsh, err := containerNS(ctx, r, cName)
+		if err != nil {
+			// the containers without a network namespace, e.g. stopped ones, have no mirrors
+			if mirrorNode == "" {
+				log.Debugf("skipping container %s: %v", cName, err)
+				continue
+			}
+			return err
+		}
+
+		ms, err := tc.ListMirrors(cName, nsh)
+		nsh.Close()
+		if err != nil {
+			return err
+		}
+
+		mirrors = append(mirrors, ms...)
+	}
+
+	if len(mirrors) == 0 {
+		log.Info("no mirrors found")
+		return nil
+	}
+
+	printMirrors(mirrors)
+
+	return nil
+}
+
+func printMirrors(mirrors []tc.Mirror) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{
+		"Source",
+		"Direction",
+		"Target",
+		"Destination",
+	})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := make([][]string, 0, len(mirrors))
+
+	for _, m := range mirrors {
+		dst := m.Destination
+		if dst == "" {
+			dst = "N/A"
+		}
+
+		rows = append(rows, []string{m.Source, string(m.Direction), m.Target, dst})
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
+}
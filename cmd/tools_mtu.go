@@ -0,0 +1,145 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	mtuNode      string
+	mtuInterface string
+	mtuValue     int
+	mtuFormat    string
+)
+
+// mtuCmd represents the tools mtu command.
+var mtuCmd = &cobra.Command{
+	Use:   "mtu",
+	Short: "show or set the MTU of the interfaces of a lab node",
+	Long: "show the MTU of the interfaces of a lab node or set the MTU of a node interface with the --set flag\n" +
+		"reference: https://containerlab.dev/cmd/tools/mtu/",
+	PreRunE: validateMTUInput,
+	RunE:    mtuFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(mtuCmd)
+	mtuCmd.Flags().StringVarP(&mtuNode, "node", "n", "", "name of the node to show or set the MTU of")
+	mtuCmd.Flags().StringVarP(&mtuInterface, "interface", "i", "",
+		"name of the interface to show or set the MTU of, all interfaces are shown when not set")
+	mtuCmd.Flags().IntVarP(&mtuValue, "set", "", 0, "MTU value to set on the interface")
+	mtuCmd.Flags().StringVarP(&mtuFormat, "format", "f", "table", "output format. One of [table, json]")
+
+	mtuCmd.MarkFlagRequired("node")
+}
+
+func validateMTUInput(cmd *cobra.Command, args []string) error {
+	if err := sudoCheck(cmd, args); err != nil {
+		return err
+	}
+
+	if mtuFormat != "table" && mtuFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use table or json", mtuFormat)
+	}
+
+	if !cmd.Flags().Changed("set") {
+		return nil
+	}
+
+	if mtuInterface == "" {
+		return fmt.Errorf("the interface to set the MTU on must be set with the --interface flag")
+	}
+
+	return links.ValidateMTU(mtuValue)
+}
+
+func mtuFn(cmd *cobra.Command, _ []string) error {
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if cmd.Flags().Changed("set") {
+		prev, err := c.SetNodeInterfaceMTU(ctx, mtuNode, mtuInterface, mtuValue)
+		if err != nil {
+			return err
+		}
+
+		if prev == mtuValue {
+			log.Infof("MTU of interface %s of node %s is already %d", mtuInterface, mtuNode, mtuValue)
+			return nil
+		}
+
+		log.Infof("Changed MTU of interface %s of node %s from %d to %d", mtuInterface, mtuNode, prev, mtuValue)
+
+		return nil
+	}
+
+	mtus, err := c.NodeInterfaceMTUs(ctx, mtuNode, mtuInterface)
+	if err != nil {
+		return err
+	}
+
+	if mtuFormat == "json" {
+		b, err := json.MarshalIndent(mtus, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	printInterfaceMTUs(mtus)
+
+	return nil
+}
+
+func printInterfaceMTUs(mtus []*links.InterfaceMTU) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{"Interface", "MTU"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := make([][]string, 0, len(mtus))
+
+	for _, m := range mtus {
+		rows = append(rows, []string{m.Interface, strconv.Itoa(m.MTU)})
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
+}
@@ -89,6 +89,7 @@ func netemSetFn(_ *cobra.Command, _ []string) error {
 		runtime.WithConfig(
 			&runtime.RuntimeConfig{
 				Timeout: timeout,
+				Socket:  runtimeSocket,
 			},
 		),
 	)
@@ -232,6 +233,7 @@ func netemShowFn(_ *cobra.Command, _ []string) error {
 		runtime.WithConfig(
 			&runtime.RuntimeConfig{
 				Timeout: timeout,
+				Socket:  runtimeSocket,
 			},
 		),
 	)
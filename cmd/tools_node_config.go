@@ -0,0 +1,166 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+// nodeConfigSettings maps the node config fields which names differ from the topology file settings
+// to the settings they are resolved from.
+var nodeConfigSettings = map[string]string{
+	"networkmode":       "network-mode",
+	"cpuset":            "cpu-set",
+	"mgmt-ipv4-address": "mgmt-ipv4",
+	"mgmt-ipv6-address": "mgmt-ipv6",
+	"portbindings":      "ports",
+	"portset":           "ports",
+}
+
+// mergedNodeConfigSettings are the map settings which entries are supplied by the topology levels separately.
+var mergedNodeConfigSettings = map[string]struct{}{
+	"env":     {},
+	"labels":  {},
+	"sysctls": {},
+}
+
+// nodeConfigCmd represents the tools node-config command.
+var nodeConfigCmd = &cobra.Command{
+	Use:   "node-config <node>",
+	Short: "print the effective config of a node",
+	Long: "print the config of a node resolved from the defaults, kind and node settings of the topology\n" +
+		"without deploying the lab, the settings are commented with the topology level that supplied them\n" +
+		"reference: https://containerlab.dev/cmd/tools/node-config/",
+	Args: cobra.ExactArgs(1),
+	RunE: nodeConfigFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(nodeConfigCmd)
+
+	nodeConfigCmd.Flags().StringArrayVarP(&nodeOverrides, "set", "", nil,
+		"override a node setting as the deploy command does, <node>.<setting>=<value>")
+}
+
+func nodeConfigFn(_ *cobra.Command, args []string) error {
+	if topo == "" {
+		return fmt.Errorf("provide a path to the topology file with --topo flag")
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithResolveOnly(),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithDebug(debug),
+	}
+
+	overrides, err := parseNodeOverrides(nodeOverrides)
+	if err != nil {
+		return err
+	}
+
+	if len(overrides) > 0 {
+		opts = append(opts, clab.WithNodeOverrides(overrides))
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	cfg, provenance, err := c.ResolveNodeConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	b, err := nodeConfigYAML(cfg, provenance)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(b)
+
+	return err
+}
+
+// nodeConfigYAML renders the node config as YAML with the settings supplied by the topology
+// commented with the level of the topology that supplied them.
+func nodeConfigYAML(cfg *types.NodeConfig, provenance clab.Provenance) ([]byte, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// the fields are kept in the order of the node config
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+
+	for _, f := range fields {
+		// the unset fields without the omitempty json tag are skipped
+		if f.Value == nil {
+			continue
+		}
+
+		name := fmt.Sprint(f.Key)
+
+		setting := name
+		if s, ok := nodeConfigSettings[name]; ok {
+			setting = s
+		}
+
+		// the map entries are sorted by the json encoding
+		entries, isMap := f.Value.(yaml.MapSlice)
+		if _, merged := mergedNodeConfigSettings[setting]; merged && isMap {
+			fmt.Fprintf(buf, "%s:\n", name)
+
+			for _, e := range entries {
+				k := fmt.Sprint(e.Key)
+				if err := writeYAMLField(buf, "  ", k, e.Value, provenance[setting+"."+k]); err != nil {
+					return nil, err
+				}
+			}
+
+			continue
+		}
+
+		if err := writeYAMLField(buf, "", name, f.Value, provenance[setting]); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeYAMLField writes the field as YAML indented with indent, the first line of the field
+// is commented with the level of the topology that supplied it, if any.
+func writeYAMLField(buf *bytes.Buffer, indent, key string, value interface{}, level string) error {
+	b, err := yaml.Marshal(yaml.MapSlice{{Key: key, Value: value}})
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if level != "" {
+		lines[0] += " # " + level
+	}
+
+	for _, l := range lines {
+		fmt.Fprintf(buf, "%s%s\n", indent, l)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestNodeConfigYAML(t *testing.T) {
+	cfg := &types.NodeConfig{
+		ShortName:   "n1",
+		Kind:        "linux",
+		Image:       "alpine:3",
+		Cmd:         "sleep infinity",
+		Env:         map[string]string{"B": "kind", "A": "node"},
+		NetworkMode: "host",
+	}
+
+	provenance := clab.Provenance{
+		"kind":         clab.ProvenanceDefault,
+		"image":        clab.ProvenanceKind,
+		"cmd":          clab.ProvenanceCLI,
+		"env.A":        clab.ProvenanceNode,
+		"env.B":        clab.ProvenanceKind,
+		"network-mode": clab.ProvenanceNode,
+	}
+
+	got, err := nodeConfigYAML(cfg, provenance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `shortname: n1
+kind: linux # default
+image: alpine:3 # kind
+cmd: sleep infinity # cli
+env:
+  A: node # node
+  B: kind # kind
+networkmode: host # node
+IsRootNamespaceBased: false
+SkipUniquenessCheck: false
+`
+	if d := cmp.Diff(want, string(got)); d != "" {
+		t.Errorf("node config mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/internal/sweep"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	pingSweepSubnet string
+	pingSweepWait   time.Duration
+	pingSweepFormat string
+)
+
+// pingSweepCmd represents the tools ping-sweep command.
+var pingSweepCmd = &cobra.Command{
+	Use:   "ping-sweep",
+	Short: "find the addresses in use in the management subnet",
+	Long: "send ARP requests for every address of the management subnet out of the management bridge " +
+		"and list the responding addresses\nreference: https://containerlab.dev/cmd/tools/ping-sweep/",
+	PreRunE: sudoCheck,
+	RunE:    pingSweepFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(pingSweepCmd)
+	pingSweepCmd.Flags().StringVarP(&pingSweepSubnet, "subnet", "", "",
+		"IPv4 subnet to sweep. Defaults to the management IPv4 subnet of the topology")
+	pingSweepCmd.Flags().DurationVarP(&pingSweepWait, "wait", "", 2*time.Second,
+		"time to wait for the replies after the requests are sent")
+	pingSweepCmd.Flags().StringVarP(&pingSweepFormat, "format", "f", "table",
+		"output format. One of [table, json]")
+}
+
+func pingSweepFn(_ *cobra.Command, _ []string) error {
+	if pingSweepFormat != "table" && pingSweepFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use table or json", pingSweepFormat)
+	}
+
+	c, err := clab.NewContainerLab(
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	)
+	if err != nil {
+		return err
+	}
+
+	mgmt := c.Config.Mgmt
+
+	subnetStr := pingSweepSubnet
+	if subnetStr == "" {
+		subnetStr = mgmt.IPv4Subnet
+	}
+
+	if subnetStr == "" {
+		return fmt.Errorf("management network %q has no IPv4 subnet, set the subnet with the --subnet flag",
+			mgmt.Network)
+	}
+
+	_, subnet, err := net.ParseCIDR(subnetStr)
+	if err != nil {
+		return fmt.Errorf("invalid subnet %q: %w", subnetStr, err)
+	}
+
+	// the bridge name is detected by the runtime only when the management network exists
+	if mgmt.Bridge == "" {
+		return fmt.Errorf("management network %q doesn't exist, nothing to sweep", mgmt.Network)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log.Infof("Sweeping subnet %s from bridge %s", subnet, mgmt.Bridge)
+
+	hosts, err := sweep.Sweep(ctx, mgmt.Bridge, subnet, pingSweepWait)
+	if err != nil {
+		return err
+	}
+
+	if pingSweepFormat == "json" {
+		b, err := json.MarshalIndent(hosts, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	printPingSweepHosts(hosts)
+
+	return nil
+}
+
+// printPingSweepHosts prints the responding hosts as a table.
+func printPingSweepHosts(hosts []*sweep.Host) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{"IPv4 Address", "MAC Address", "Bridge"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := make([][]string, 0, len(hosts))
+
+	for _, h := range hosts {
+		rows = append(rows, []string{h.IP.String(), h.MAC.String(), strconv.FormatBool(h.Local)})
+	}
+
+	table.AppendBulk(rows)
+	table.SetFooter([]string{"", "", fmt.Sprintf("%d addresses in use", len(hosts))})
+	table.Render()
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	reachabilitySubnet       string
+	reachabilityProbeTimeout time.Duration
+	reachabilityWorkers      uint
+	reachabilityFormat       string
+)
+
+// reachabilityCmd represents the tools reachability command.
+var reachabilityCmd = &cobra.Command{
+	Use:   "reachability",
+	Short: "check reachability between all nodes of a lab",
+	Long: "ping every node of a running lab from every other node and print the reachability matrix\n" +
+		"reference: https://containerlab.dev/cmd/tools/reachability/",
+	PreRunE: sudoCheck,
+	RunE:    reachabilityFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(reachabilityCmd)
+	reachabilityCmd.Flags().StringVarP(&reachabilitySubnet, "subnet", "", "",
+		"probe the node addresses from this subnet instead of the management addresses")
+	reachabilityCmd.Flags().DurationVarP(&reachabilityProbeTimeout, "probe-timeout", "", time.Second,
+		"timeout of a single probe")
+	reachabilityCmd.Flags().UintVarP(&reachabilityWorkers, "max-workers", "", 10,
+		"maximum number of concurrent probes")
+	reachabilityCmd.Flags().StringVarP(&reachabilityFormat, "format", "f", "table",
+		"output format. One of [table, json]")
+	reachabilityCmd.Flags().StringSliceVarP(&nodeFilter, "node-filter", "", []string{},
+		"comma separated list of nodes to include")
+}
+
+func reachabilityFn(_ *cobra.Command, _ []string) error {
+	if reachabilityFormat != "table" && reachabilityFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use table or json", reachabilityFormat)
+	}
+
+	var subnet *net.IPNet
+	if reachabilitySubnet != "" {
+		var err error
+		_, subnet, err = net.ParseCIDR(reachabilitySubnet)
+		if err != nil {
+			return fmt.Errorf("invalid subnet %q: %w", reachabilitySubnet, err)
+		}
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithNodeFilter(nodeFilter),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m, err := c.CheckReachability(ctx, subnet, reachabilityProbeTimeout, reachabilityWorkers)
+	if err != nil {
+		return err
+	}
+
+	if len(m.Nodes) == 0 {
+		return fmt.Errorf("no running nodes with probe addresses found in lab %s", c.Config.Name)
+	}
+
+	if reachabilityFormat == "json" {
+		b, err := json.MarshalIndent(m.List(), "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	printReachabilityMatrix(m)
+
+	return nil
+}
+
+// printReachabilityMatrix prints the matrix with the source nodes as rows and the target nodes as columns.
+func printReachabilityMatrix(m *clab.ReachabilityMatrix) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader(append([]string{"Source \\ Target"}, m.Nodes...))
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+
+	rows := [][]string{}
+
+	for _, src := range m.Nodes {
+		row := []string{src}
+
+		for _, dst := range m.Nodes {
+			r := m.Get(src, dst)
+
+			switch {
+			case src == dst:
+				row = append(row, "-")
+			case r == nil:
+				row = append(row, "N/A")
+			case r.Success:
+				row = append(row, fmt.Sprintf("ok %.2fms", float64(r.RTT)/float64(time.Millisecond)))
+			default:
+				row = append(row, "fail")
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+)
+
+// renderCmd represents the tools render command.
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "render the topology file",
+	Long: "render the topology file with the template rendered, env vars expanded and included files merged\n" +
+		"reference: https://containerlab.dev/cmd/tools/render/",
+	RunE: renderFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(renderCmd)
+}
+
+func renderFn(cmd *cobra.Command, _ []string) error {
+	out, err := clab.RenderTopology(topo, varsFile, timeout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), string(out))
+
+	return nil
+}
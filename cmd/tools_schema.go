@@ -0,0 +1,45 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/schemas"
+)
+
+// documentSchemas are the JSON schemas of the documents produced by containerlab, keyed by the document name.
+var documentSchemas = map[string][]byte{
+	"graph":         schemas.Graph,
+	"topology-data": schemas.TopologyData,
+}
+
+// schemaCmd represents the tools schema command.
+var schemaCmd = &cobra.Command{
+	Use:   "schema <document>",
+	Short: "print the JSON schema of a document produced by containerlab",
+	Long: "print the JSON schema of a document produced by containerlab, e.g. the graph document\n" +
+		"reference: https://containerlab.dev/cmd/tools/schema/",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"graph", "topology-data"},
+	RunE:      schemaFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(schemaCmd)
+}
+
+func schemaFn(_ *cobra.Command, args []string) error {
+	s, ok := documentSchemas[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown document %q, the schemas are available for: graph, topology-data", args[0])
+	}
+
+	_, err := os.Stdout.Write(s)
+
+	return err
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	fromRunningLab    string
+	fromRunningOutput string
+)
+
+// topoCmd represents the tools topo command.
+var topoCmd = &cobra.Command{
+	Use:   "topo",
+	Short: "topology file tools",
+}
+
+// fromRunningCmd represents the tools topo from-running command.
+var fromRunningCmd = &cobra.Command{
+	Use:   "from-running",
+	Short: "reconstruct the topology file of a running lab",
+	Long: "reconstruct a best-effort topology file from the containers of a running lab\n" +
+		"reference: https://containerlab.dev/cmd/tools/topo/from-running/",
+	PreRunE: sudoCheck,
+	RunE:    fromRunningFn,
+}
+
+func init() {
+	toolsCmd.AddCommand(topoCmd)
+	topoCmd.AddCommand(fromRunningCmd)
+
+	fromRunningCmd.Flags().StringVarP(&fromRunningLab, "name", "", "", "name of the running lab")
+	fromRunningCmd.Flags().StringVarP(&fromRunningOutput, "output", "o", "",
+		"path to the file to write the topology to. Printed to stdout if not set")
+
+	fromRunningCmd.MarkFlagRequired("name")
+}
+
+func fromRunningFn(_ *cobra.Command, _ []string) error {
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Socket:           runtimeSocket,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cfg, warnings, err := c.ReconstructTopology(ctx, fromRunningLab)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range warnings {
+		log.Warn(w)
+	}
+
+	b, err := marshalReconstructedTopo(cfg, warnings)
+	if err != nil {
+		return err
+	}
+
+	if fromRunningOutput == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+
+	log.Infof("Writing the reconstructed topology to %s", fromRunningOutput)
+
+	return saveTopoFile(fromRunningOutput, b)
+}
+
+// reconstructedTopo is the layout of the reconstructed topology file.
+type reconstructedTopo struct {
+	Name     string          `yaml:"name"`
+	Prefix   *string         `yaml:"prefix,omitempty"`
+	Mgmt     *types.MgmtNet  `yaml:"mgmt,omitempty"`
+	Topology *types.Topology `yaml:"topology"`
+}
+
+// marshalReconstructedTopo returns the topology file of the reconstructed lab.
+// The file starts with the comments marking it as reconstructed and listing the warnings.
+func marshalReconstructedTopo(cfg *clab.Config, warnings []string) ([]byte, error) {
+	t := reconstructedTopo{
+		Name:     cfg.Name,
+		Prefix:   cfg.Prefix,
+		Mgmt:     cfg.Mgmt,
+		Topology: cfg.Topology,
+	}
+
+	if t.Mgmt != nil && *t.Mgmt == (types.MgmtNet{}) {
+		t.Mgmt = nil
+	}
+
+	b, err := yaml.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "# This topology was reconstructed from the running containers of the lab %q\n", cfg.Name)
+	buf.WriteString("# by containerlab tools topo from-running. The reconstruction is best-effort,\n")
+	buf.WriteString("# review the topology before deploying it.\n")
+
+	if len(warnings) > 0 {
+		buf.WriteString("# Warnings:\n")
+
+		for _, w := range warnings {
+			fmt.Fprintf(buf, "#   - %s\n", w)
+		}
+	}
+
+	buf.Write(b)
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+func TestMarshalReconstructedTopo(t *testing.T) {
+	prefix := "lab"
+
+	cfg := &clab.Config{
+		Name:   "lab1",
+		Prefix: &prefix,
+		Mgmt:   &types.MgmtNet{Network: "clab", IPv4Subnet: "172.20.20.0/24"},
+		Topology: &types.Topology{
+			Nodes: map[string]*types.NodeDefinition{
+				"srl1": {
+					Kind:     "nokia_srlinux",
+					Image:    "ghcr.io/nokia/srlinux",
+					MgmtIPv4: "172.20.20.2",
+				},
+				"client1": {
+					Kind:  "linux",
+					Image: "alpine:3",
+					Binds: []string{"/home/user/scripts:/scripts:ro"},
+					Env:   map[string]string{"MY_VAR": "1"},
+				},
+			},
+			Links: []*links.LinkDefinition{
+				{Link: (&links.LinkVEthRaw{Endpoints: []*links.EndpointRaw{
+					links.NewEndpointRaw("client1", "eth1", ""),
+					links.NewEndpointRaw("srl1", "e1-1", ""),
+				}}).ToLinkBriefRaw()},
+			},
+		},
+	}
+
+	warnings := []string{"the peer of interface client1:eth2 is not found in the lab containers"}
+
+	got, err := marshalReconstructedTopo(cfg, warnings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("test_data/from-running.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff(string(want), string(got)); d != "" {
+		t.Errorf("topology mismatch (-want +got):\n%s", d)
+	}
+
+	// the reconstructed topology is a valid topology file
+	parsed := &clab.Config{}
+	if err := yaml.Unmarshal(got, parsed); err != nil {
+		t.Fatalf("failed to parse the reconstructed topology: %v", err)
+	}
+
+	if len(parsed.Topology.Nodes) != 2 || len(parsed.Topology.Links) != 1 {
+		t.Errorf("got %d nodes and %d links, want 2 nodes and 1 link",
+			len(parsed.Topology.Nodes), len(parsed.Topology.Links))
+	}
+}
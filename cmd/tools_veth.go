@@ -65,6 +65,7 @@ var vethCreateCmd = &cobra.Command{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Socket:           runtimeSocket,
 				},
 			),
 			clab.WithDebug(debug),
@@ -0,0 +1,221 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package console implements a minimal telnet client used to access the serial consoles of the lab nodes.
+package console
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+)
+
+// EscapeChar is the input character that terminates the console session (Ctrl-]).
+const EscapeChar byte = 0x1d
+
+// telnet commands and options, see RFC 854, 857 and 858.
+const (
+	cmdSE   byte = 240
+	cmdSB   byte = 250
+	cmdWILL byte = 251
+	cmdWONT byte = 252
+	cmdDO   byte = 253
+	cmdDONT byte = 254
+	cmdIAC  byte = 255
+
+	optEcho byte = 1
+	optSGA  byte = 3
+)
+
+// Session bridges the local terminal with the telnet server of the node console.
+type Session struct {
+	conn net.Conn
+	in   io.Reader
+	out  io.Writer
+}
+
+// NewSession returns a console session that reads the user input from in
+// and writes the console output to out.
+func NewSession(conn net.Conn, in io.Reader, out io.Writer) *Session {
+	return &Session{
+		conn: conn,
+		in:   in,
+		out:  out,
+	}
+}
+
+// Run copies the user input to the console and the console output to the user
+// until the escape character is read from the input, the input is exhausted
+// or the console closes the connection. The connection is closed when Run returns.
+func (s *Session) Run() error {
+	errCh := make(chan error, 2)
+
+	go func() { errCh <- s.readConsole() }()
+	go func() { errCh <- s.writeConsole() }()
+
+	err := <-errCh
+
+	s.conn.Close()
+
+	return err
+}
+
+// readConsole copies the console output to the user stripping the telnet commands
+// and answers the option negotiation requests of the telnet server.
+func (s *Session) readConsole() error {
+	f := &telnetFilter{}
+	buf := make([]byte, 4096)
+
+	for {
+		n, err := s.conn.Read(buf)
+		if n > 0 {
+			data, replies := f.filter(buf[:n])
+
+			if len(replies) > 0 {
+				if _, werr := s.conn.Write(replies); werr != nil {
+					return werr
+				}
+			}
+
+			if _, werr := s.out.Write(data); werr != nil {
+				return werr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeConsole copies the user input to the console until the escape character is read.
+func (s *Session) writeConsole() error {
+	buf := make([]byte, 1024)
+
+	for {
+		n, err := s.in.Read(buf)
+		if n > 0 {
+			data := buf[:n]
+
+			escaped := false
+			if i := bytes.IndexByte(data, EscapeChar); i >= 0 {
+				data = data[:i]
+				escaped = true
+			}
+
+			// IAC byte in the data stream is sent as a doubled IAC
+			data = bytes.ReplaceAll(data, []byte{cmdIAC}, []byte{cmdIAC, cmdIAC})
+
+			if _, werr := s.conn.Write(data); werr != nil {
+				return werr
+			}
+
+			if escaped {
+				return nil
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// telnet filter parser states.
+const (
+	stateData = iota
+	stateIAC
+	stateOption
+	stateSB
+	stateSBIAC
+)
+
+// telnetFilter separates the data from the telnet commands in the stream received from the telnet server.
+// The filter keeps its state between the calls, so commands split between the reads are handled.
+type telnetFilter struct {
+	state int
+	// cmd is the negotiation command awaiting its option
+	cmd byte
+}
+
+// filter returns the data bytes of the received chunk and the replies to the negotiation requests.
+// The server is allowed to echo and suppress go-ahead, all other options are refused.
+func (f *telnetFilter) filter(chunk []byte) (data, replies []byte) {
+	for _, b := range chunk {
+		switch f.state {
+		case stateData:
+			if b == cmdIAC {
+				f.state = stateIAC
+				continue
+			}
+
+			data = append(data, b)
+
+		case stateIAC:
+			switch b {
+			case cmdIAC:
+				// escaped 255 data byte
+				data = append(data, b)
+				f.state = stateData
+			case cmdWILL, cmdWONT, cmdDO, cmdDONT:
+				f.cmd = b
+				f.state = stateOption
+			case cmdSB:
+				f.state = stateSB
+			default:
+				// other commands (NOP, GA, etc.) carry no data
+				f.state = stateData
+			}
+
+		case stateOption:
+			replies = append(replies, negotiationReply(f.cmd, b)...)
+			f.state = stateData
+
+		case stateSB:
+			if b == cmdIAC {
+				f.state = stateSBIAC
+			}
+
+		case stateSBIAC:
+			if b == cmdSE {
+				f.state = stateData
+			} else {
+				f.state = stateSB
+			}
+		}
+	}
+
+	return data, replies
+}
+
+// negotiationReply returns the reply to the option negotiation command of the server.
+func negotiationReply(cmd, opt byte) []byte {
+	switch cmd {
+	case cmdWILL:
+		if opt == optEcho || opt == optSGA {
+			return []byte{cmdIAC, cmdDO, opt}
+		}
+
+		return []byte{cmdIAC, cmdDONT, opt}
+
+	case cmdDO:
+		if opt == optSGA {
+			return []byte{cmdIAC, cmdWILL, opt}
+		}
+
+		return []byte{cmdIAC, cmdWONT, opt}
+	}
+
+	// WONT and DONT are not acknowledged to avoid negotiation loops
+	return nil
+}
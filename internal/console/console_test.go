@@ -0,0 +1,214 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package console
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTelnetFilter(t *testing.T) {
+	tests := map[string]struct {
+		chunks      [][]byte
+		wantData    []byte
+		wantReplies []byte
+	}{
+		"plain data": {
+			chunks:   [][]byte{[]byte("login: ")},
+			wantData: []byte("login: "),
+		},
+		"negotiation": {
+			chunks: [][]byte{{
+				cmdIAC, cmdWILL, optEcho,
+				cmdIAC, cmdWILL, optSGA,
+				cmdIAC, cmdDO, 24, // terminal type
+				'o', 'k',
+			}},
+			wantData: []byte("ok"),
+			wantReplies: []byte{
+				cmdIAC, cmdDO, optEcho,
+				cmdIAC, cmdDO, optSGA,
+				cmdIAC, cmdWONT, 24,
+			},
+		},
+		"escaped iac": {
+			chunks:   [][]byte{{'a', cmdIAC, cmdIAC, 'b'}},
+			wantData: []byte{'a', cmdIAC, 'b'},
+		},
+		"subnegotiation": {
+			chunks:   [][]byte{{'a', cmdIAC, cmdSB, 24, 1, cmdIAC, cmdSE, 'b'}},
+			wantData: []byte("ab"),
+		},
+		"command split between chunks": {
+			chunks:      [][]byte{{'a', cmdIAC}, {cmdWILL}, {optEcho, 'b'}},
+			wantData:    []byte("ab"),
+			wantReplies: []byte{cmdIAC, cmdDO, optEcho},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &telnetFilter{}
+
+			var data, replies []byte
+			for _, c := range tt.chunks {
+				d, r := f.filter(c)
+				data = append(data, d...)
+				replies = append(replies, r...)
+			}
+
+			if !bytes.Equal(data, tt.wantData) {
+				t.Errorf("got data %q, want %q", data, tt.wantData)
+			}
+
+			if !bytes.Equal(replies, tt.wantReplies) {
+				t.Errorf("got replies %v, want %v", replies, tt.wantReplies)
+			}
+		})
+	}
+}
+
+// echoServer starts a TCP server echoing the received bytes back.
+// The bytes received over the connection are sent to the returned channel when the connection is closed.
+func echoServer(t *testing.T) (string, <-chan []byte) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	received := make(chan []byte, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(io.MultiWriter(conn, &buf), conn)
+
+		received <- buf.Bytes()
+	}()
+
+	return l.Addr().String(), received
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestSession(t *testing.T) {
+	addr, received := echoServer(t)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	out := &syncBuffer{}
+
+	done := make(chan error, 1)
+	go func() { done <- NewSession(conn, pr, out).Run() }()
+
+	// the 255 byte is sent doubled and echoed back as a single data byte
+	if _, err := pw.Write([]byte{'h', 'i', cmdIAC}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{'h', 'i', cmdIAC}
+	deadline := time.Now().Add(5 * time.Second)
+	for !bytes.Equal(out.Bytes(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("got output %q, want %q", out.Bytes(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the input after the escape character is not sent to the console
+	if _, err := pw.Write([]byte{'!', EscapeChar, 'x'}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("session returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("session didn't terminate on the escape character")
+	}
+
+	select {
+	case got := <-received:
+		if want := []byte{'h', 'i', cmdIAC, cmdIAC, '!'}; !bytes.Equal(got, want) {
+			t.Fatalf("server received %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server connection wasn't closed")
+	}
+}
+
+func TestSessionServerClose(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		conn.Write([]byte("bye\r\n"))
+		conn.Close()
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	out := &syncBuffer{}
+
+	if err := NewSession(conn, pr, out).Run(); err != nil {
+		t.Fatalf("session returned error: %v", err)
+	}
+
+	if got := string(out.Bytes()); got != "bye\r\n" {
+		t.Fatalf("got output %q, want %q", got, "bye\r\n")
+	}
+}
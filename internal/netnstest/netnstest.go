@@ -0,0 +1,140 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package netnstest provides the network namespace sandboxes for the tests
+// that create the network interfaces.
+package netnstest
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// skipUnlessRoot skips the test when it is not run as root, as the namespaces can't be created then.
+func skipUnlessRoot(t *testing.T) {
+	t.Helper()
+
+	if os.Geteuid() != 0 {
+		t.Skip("test requires root privileges")
+	}
+}
+
+// New returns a new network namespace that is removed when the test finishes.
+// The test is skipped when the namespace can't be created, e.g. when not running as root.
+func New(t *testing.T) ns.NetNS {
+	t.Helper()
+
+	skipUnlessRoot(t)
+
+	// namespaces are per thread, so the goroutine must stay on the same thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origNS, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer origNS.Close()
+
+	newNS, err := netns.New()
+	if err != nil {
+		t.Skipf("failed to create a network namespace: %v", err)
+	}
+
+	if err := netns.Set(origNS); err != nil {
+		t.Fatalf("failed to restore the original network namespace: %v", err)
+	}
+
+	// the namespace exists as long as its handle is open
+	nsh, err := ns.GetNS(fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(newNS)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		nsh.Close()
+		newNS.Close()
+	})
+
+	return nsh
+}
+
+// Run runs f in a new network namespace that is removed when f returns.
+// Unlike the functions run by ns.NetNS.Do, f runs in the test goroutine, so it can fail the test.
+// The test is skipped when the namespace can't be created, e.g. when not running as root.
+func Run(t *testing.T, f func()) {
+	t.Helper()
+
+	skipUnlessRoot(t)
+
+	// namespaces are per thread, so the goroutine must stay on the same thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origNS, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer origNS.Close()
+
+	newNS, err := netns.New()
+	if err != nil {
+		t.Skipf("failed to create a network namespace: %v", err)
+	}
+	defer newNS.Close()
+
+	defer func() {
+		if err := netns.Set(origNS); err != nil {
+			t.Fatalf("failed to restore the original network namespace: %v", err)
+		}
+	}()
+
+	f()
+}
+
+// AddVeth creates a veth pair in the namespace a with the peer in the namespace b.
+// Both ends are left down.
+func AddVeth(t *testing.T, a ns.NetNS, name string, b ns.NetNS, peer string) {
+	t.Helper()
+
+	err := a.Do(func(_ ns.NetNS) error {
+		return netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs:     netlink.LinkAttrs{Name: name},
+			PeerName:      peer,
+			PeerNamespace: netlink.NsFd(b.Fd()),
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// SetLinksUp sets the named interfaces of the namespace up.
+func SetLinksUp(t *testing.T, n ns.NetNS, names ...string) {
+	t.Helper()
+
+	err := n.Do(func(_ ns.NetNS) error {
+		for _, name := range names {
+			l, err := netlink.LinkByName(name)
+			if err != nil {
+				return err
+			}
+
+			if err := netlink.LinkSetUp(l); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package sweep discovers the addresses in use on a bridge by sweeping its subnet with the ARP requests.
+package sweep
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// MaxHosts is the maximum number of the host addresses of a subnet that can be swept.
+	MaxHosts = 4096
+	// arpFrameLen is the length of the ethernet frame carrying an IPv4 ARP packet.
+	arpFrameLen = 42
+	// ethPARP is ETH_P_ARP in network byte order.
+	ethPARP = 0x0608
+	// readTimeout is the receive timeout of the ARP socket, it bounds the reaction to the context cancellation.
+	readTimeout = 100 * time.Millisecond
+)
+
+// ErrTooManyHosts is returned when the swept subnet has more than MaxHosts host addresses.
+var ErrTooManyHosts = errors.New("too many host addresses in the subnet")
+
+// Host is an address in use on the bridge.
+type Host struct {
+	IP  net.IP           `json:"ip"`
+	MAC net.HardwareAddr `json:"mac"`
+	// Local is true for the addresses of the bridge interface itself.
+	Local bool `json:"local"`
+}
+
+// Sweep sends an ARP request for every host address of the IPv4 subnet out of the bridge interface
+// and returns the hosts that replied within the wait duration along with the bridge own addresses.
+// The hosts are sorted by the address.
+func Sweep(ctx context.Context, bridge string, subnet *net.IPNet, wait time.Duration) ([]*Host, error) {
+	targets, err := SubnetHosts(subnet)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := netlink.LinkByName(bridge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the bridge %s: %w", bridge, err)
+	}
+
+	srcMAC := link.Attrs().HardwareAddr
+
+	addrs, err := netlink.AddrList(link, netlink.FAMILY_V4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the addresses of the bridge %s: %w", bridge, err)
+	}
+
+	found := newHostSet()
+	// the requests are sent from the bridge address in the subnet,
+	// without one the requests are sent as the ARP probes with the unspecified sender address
+	srcIP := net.IPv4zero.To4()
+
+	for _, a := range addrs {
+		if subnet.Contains(a.IP) {
+			srcIP = a.IP.To4()
+			found.add(&Host{IP: srcIP, MAC: srcMAC, Local: true})
+		}
+	}
+
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, ethPARP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the ARP socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	if err := unix.Bind(fd, &unix.SockaddrLinklayer{Protocol: ethPARP, Ifindex: link.Attrs().Index}); err != nil {
+		return nil, fmt.Errorf("failed to bind the ARP socket to the bridge %s: %w", bridge, err)
+	}
+
+	tv := unix.NsecToTimeval(readTimeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		receiveReplies(ctx, fd, subnet, found)
+	}()
+
+	dst := &unix.SockaddrLinklayer{
+		Protocol: ethPARP,
+		Ifindex:  link.Attrs().Index,
+		Halen:    6,
+		Addr:     [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+
+	for _, ip := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := unix.Sendto(fd, arpRequest(srcMAC, srcIP, ip), 0, dst); err != nil {
+			return nil, fmt.Errorf("failed to send the ARP request for %s: %w", ip, err)
+		}
+	}
+
+	select {
+	case <-time.After(wait):
+	case <-ctx.Done():
+	}
+
+	cancel()
+	<-done
+
+	return found.sorted(), nil
+}
+
+// receiveReplies reads the ARP replies from the socket fd until the context is canceled
+// and records the senders from the subnet.
+func receiveReplies(ctx context.Context, fd int, subnet *net.IPNet, found *hostSet) {
+	buf := make([]byte, 1500)
+
+	for ctx.Err() == nil {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			// the read timeout lets the context cancellation be noticed
+			continue
+		}
+
+		if h := parseARPReply(buf[:n]); h != nil && subnet.Contains(h.IP) {
+			found.add(h)
+		}
+	}
+}
+
+// SubnetHosts returns the host addresses of the IPv4 subnet.
+// The network and the broadcast addresses are excluded unless the subnet is a /31 or a /32.
+func SubnetHosts(subnet *net.IPNet) ([]net.IP, error) {
+	base := subnet.IP.To4()
+	if base == nil {
+		return nil, fmt.Errorf("subnet %s is not an IPv4 subnet", subnet)
+	}
+
+	ones, bits := subnet.Mask.Size()
+	size := uint64(1) << uint(bits-ones)
+
+	first, last := uint64(0), size-1
+	if size > 2 {
+		first, last = 1, size-2
+	}
+
+	if last-first+1 > MaxHosts {
+		return nil, fmt.Errorf("%w: subnet %s has %d host addresses, at most %d are allowed",
+			ErrTooManyHosts, subnet, last-first+1, MaxHosts)
+	}
+
+	start := binary.BigEndian.Uint32(base.Mask(subnet.Mask))
+	hosts := make([]net.IP, 0, last-first+1)
+
+	for i := first; i <= last; i++ {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, start+uint32(i))
+		hosts = append(hosts, ip)
+	}
+
+	return hosts, nil
+}
+
+// arpRequest returns the broadcast ethernet frame with the ARP request for the target address.
+func arpRequest(srcMAC net.HardwareAddr, srcIP, target net.IP) []byte {
+	f := make([]byte, arpFrameLen)
+
+	// ethernet header
+	copy(f[0:6], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	copy(f[6:12], srcMAC)
+	binary.BigEndian.PutUint16(f[12:14], unix.ETH_P_ARP)
+
+	// ARP packet: ethernet hardware, IPv4 protocol, request operation
+	binary.BigEndian.PutUint16(f[14:16], 1)
+	binary.BigEndian.PutUint16(f[16:18], unix.ETH_P_IP)
+	f[18], f[19] = 6, 4
+	binary.BigEndian.PutUint16(f[20:22], 1)
+	copy(f[22:28], srcMAC)
+	copy(f[28:32], srcIP.To4())
+	// the target hardware address is left zeroed
+	copy(f[38:42], target.To4())
+
+	return f
+}
+
+// parseARPReply returns the sender of the ARP reply carried by the ethernet frame f
+// or nil if f is not an IPv4 ARP reply.
+func parseARPReply(f []byte) *Host {
+	if len(f) < arpFrameLen ||
+		binary.BigEndian.Uint16(f[12:14]) != unix.ETH_P_ARP ||
+		binary.BigEndian.Uint16(f[16:18]) != unix.ETH_P_IP ||
+		binary.BigEndian.Uint16(f[20:22]) != 2 {
+		return nil
+	}
+
+	return &Host{
+		IP:  net.IP(bytes.Clone(f[28:32])),
+		MAC: net.HardwareAddr(bytes.Clone(f[22:28])),
+	}
+}
+
+// hostSet is a set of the hosts keyed by the address safe for the concurrent use.
+type hostSet struct {
+	mu    sync.Mutex
+	hosts map[string]*Host
+}
+
+func newHostSet() *hostSet {
+	return &hostSet{hosts: map[string]*Host{}}
+}
+
+// add records the host unless its address is already recorded.
+func (s *hostSet) add(h *Host) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.hosts[h.IP.String()]; !ok {
+		s.hosts[h.IP.String()] = h
+	}
+}
+
+// sorted returns the recorded hosts sorted by the address.
+func (s *hostSet) sorted() []*Host {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hosts := make([]*Host, 0, len(s.hosts))
+	for _, h := range s.hosts {
+		hosts = append(hosts, h)
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return bytes.Compare(hosts[i].IP.To4(), hosts[j].IP.To4()) < 0
+	})
+
+	return hosts
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sweep
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestSubnetHosts(t *testing.T) {
+	tests := map[string]struct {
+		subnet  string
+		count   int
+		first   string
+		last    string
+		wantErr error
+	}{
+		"slash 24": {
+			subnet: "172.20.20.0/24",
+			count:  254,
+			first:  "172.20.20.1",
+			last:   "172.20.20.254",
+		},
+		"address within the subnet": {
+			subnet: "10.0.0.77/29",
+			count:  6,
+			first:  "10.0.0.73",
+			last:   "10.0.0.78",
+		},
+		"slash 31": {
+			subnet: "10.0.0.0/31",
+			count:  2,
+			first:  "10.0.0.0",
+			last:   "10.0.0.1",
+		},
+		"slash 32": {
+			subnet: "10.0.0.5/32",
+			count:  1,
+			first:  "10.0.0.5",
+			last:   "10.0.0.5",
+		},
+		"largest allowed": {
+			subnet: "10.0.0.0/20",
+			count:  4094,
+			first:  "10.0.0.1",
+			last:   "10.0.15.254",
+		},
+		"too large": {
+			subnet:  "10.0.0.0/16",
+			wantErr: ErrTooManyHosts,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, subnet, err := net.ParseCIDR(tt.subnet)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := SubnetHosts(subnet)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != tt.count {
+				t.Fatalf("got %d hosts, want %d", len(got), tt.count)
+			}
+
+			if got[0].String() != tt.first || got[len(got)-1].String() != tt.last {
+				t.Errorf("got hosts range %s - %s, want %s - %s", got[0], got[len(got)-1], tt.first, tt.last)
+			}
+		})
+	}
+
+	_, v6, _ := net.ParseCIDR("3fff:172:20:20::/64")
+	if _, err := SubnetHosts(v6); err == nil {
+		t.Error("expected an error for the IPv6 subnet")
+	}
+}
+
+func TestARPRequestReply(t *testing.T) {
+	srcMAC, _ := net.ParseMAC("aa:c1:ab:00:00:01")
+	peerMAC, _ := net.ParseMAC("aa:c1:ab:00:00:02")
+	srcIP := net.ParseIP("172.20.20.1")
+	target := net.ParseIP("172.20.20.2")
+
+	req := arpRequest(srcMAC, srcIP, target)
+
+	if len(req) != arpFrameLen {
+		t.Fatalf("got request frame length %d, want %d", len(req), arpFrameLen)
+	}
+
+	// a request is not a reply
+	if h := parseARPReply(req); h != nil {
+		t.Fatalf("parsed the request as a reply: %+v", h)
+	}
+
+	// turn the request into the reply of the target
+	reply := append([]byte(nil), req...)
+	copy(reply[0:6], srcMAC)
+	copy(reply[6:12], peerMAC)
+	reply[21] = 2
+	copy(reply[22:28], peerMAC)
+	copy(reply[28:32], target.To4())
+	copy(reply[32:38], srcMAC)
+	copy(reply[38:42], srcIP.To4())
+
+	h := parseARPReply(reply)
+	if h == nil {
+		t.Fatal("failed to parse the reply")
+	}
+
+	if !h.IP.Equal(target) || h.MAC.String() != peerMAC.String() || h.Local {
+		t.Errorf("got host %s %s local=%v, want %s %s local=false", h.IP, h.MAC, h.Local, target, peerMAC)
+	}
+
+	if h := parseARPReply(reply[:30]); h != nil {
+		t.Errorf("parsed the truncated reply: %+v", h)
+	}
+}
+
+func TestHostSetSorted(t *testing.T) {
+	s := newHostSet()
+
+	for _, ip := range []string{"172.20.20.10", "172.20.20.2", "172.20.20.10", "172.20.20.1"} {
+		s.add(&Host{IP: net.ParseIP(ip).To4()})
+	}
+
+	got := s.sorted()
+	want := []string{"172.20.20.1", "172.20.20.2", "172.20.20.10"}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d hosts, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if got[i].IP.String() != want[i] {
+			t.Errorf("host %d: got %s, want %s", i, got[i].IP, want[i])
+		}
+	}
+}
@@ -0,0 +1,583 @@
+package tc
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/florianl/go-tc"
+	"github.com/florianl/go-tc/core"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// MirrorDirection is the direction of the mirrored traffic relative to the source interface.
+type MirrorDirection string
+
+const (
+	MirrorBoth MirrorDirection = "both"
+	MirrorRx   MirrorDirection = "rx"
+	MirrorTx   MirrorDirection = "tx"
+)
+
+const (
+	// mirrorCookie tags the mirred actions created by containerlab, it is displayed by tc filter show.
+	mirrorCookie = "clab-mirror"
+	// mirrorAliasPrefix is the alias prefix of the helper veth interfaces connecting
+	// the source namespace with the mirror destination.
+	mirrorAliasPrefix = "clab-mirror:"
+	// mirrorHelperPrefix is the name prefix of the helper veth interfaces.
+	mirrorHelperPrefix = "mir-"
+	// mirrorPrio is the lowest priority of the mirror filters. The filters with the mirred mirror action
+	// starting from this priority are the mirrors created by containerlab.
+	mirrorPrio = 0xc000
+	// ethPAll is ETH_P_ALL in network byte order.
+	ethPAll = 0x0300
+	// tcaEgressMirror is the mirred action mirroring the packets to the egress of the target interface.
+	tcaEgressMirror = 2
+	// tcU32Terminal marks the u32 selector as terminal, so that its actions are executed on match.
+	tcU32Terminal = 1
+)
+
+// mirrorParents are the clsact parents of the mirror filters per direction.
+var mirrorParents = map[MirrorDirection]uint32{
+	MirrorRx: core.BuildHandle(tc.HandleRoot, tc.HandleMinIngress),
+	MirrorTx: core.BuildHandle(tc.HandleRoot, tc.HandleMinEgress),
+}
+
+// ParseMirrorDirection returns the mirror direction for the given string.
+func ParseMirrorDirection(s string) (MirrorDirection, error) {
+	switch d := MirrorDirection(s); d {
+	case MirrorBoth, MirrorRx, MirrorTx:
+		return d, nil
+	}
+
+	return "", fmt.Errorf("invalid mirror direction %q, expected one of [both, rx, tx]", s)
+}
+
+// MirrorEndpoint is an interface of a node in the network namespace of the node.
+type MirrorEndpoint struct {
+	Node  string
+	Iface string
+	NS    ns.NetNS
+}
+
+func (e *MirrorEndpoint) String() string {
+	return e.Node + ":" + e.Iface
+}
+
+// Mirror is a mirror of the traffic of an interface configured in a node namespace.
+type Mirror struct {
+	// Source is the mirrored interface.
+	Source string
+	// Direction is the direction of the mirrored traffic, rx or tx.
+	Direction MirrorDirection
+	// Target is the interface in the source namespace the traffic is mirrored to.
+	Target string
+	// Destination is the node:interface the helper veth target is connected to,
+	// empty when the target is an existing interface.
+	Destination string
+
+	ifindex int
+	target  int
+	parent  uint32
+	prio    uint16
+}
+
+// AddMirror mirrors the traffic of the src interface in the given direction to the dst interface.
+// When the dst interface is in another namespace and is not connected to the src namespace by a veth,
+// the dst interface is created as a helper veth connecting the dst namespace with the src namespace.
+func AddMirror(src, dst *MirrorEndpoint, dir MirrorDirection) error {
+	srcH, err := netlink.NewHandleAt(netns.NsHandle(src.NS.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to open netlink handle in the namespace of %s: %w", src.Node, err)
+	}
+	defer srcH.Close()
+
+	srcLink, err := srcH.LinkByName(src.Iface)
+	if err != nil {
+		return fmt.Errorf("interface %s not found in the namespace of %s: %w", src.Iface, src.Node, err)
+	}
+
+	target, helper, err := mirrorTarget(srcH, srcLink, src, dst)
+	if err != nil {
+		return err
+	}
+
+	tcnl, err := NewTC(int(src.NS.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to open tc socket in the namespace of %s: %w", src.Node, err)
+	}
+	defer tcnl.Close()
+
+	err = addMirrorFilters(srcH, tcnl, srcLink, target, dir)
+	if err != nil && helper {
+		// the helper veth is removed along with its peer
+		_ = srcH.LinkDel(target)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to mirror %s to %s: %w", src, dst, err)
+	}
+
+	return nil
+}
+
+// mirrorTarget returns the interface in the src namespace the traffic is mirrored to
+// and whether it is a helper veth created for the mirror.
+func mirrorTarget(srcH *netlink.Handle, srcLink netlink.Link, src, dst *MirrorEndpoint) (netlink.Link, bool, error) {
+	same, err := sameNetNS(src.NS, dst.NS)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if same {
+		l, err := srcH.LinkByName(dst.Iface)
+		if err != nil {
+			return nil, false, fmt.Errorf("interface %s not found in the namespace of %s: %w", dst.Iface, dst.Node, err)
+		}
+
+		if l.Attrs().Index == srcLink.Attrs().Index {
+			return nil, false, fmt.Errorf("interface %s can't be mirrored to itself", src)
+		}
+
+		return l, false, nil
+	}
+
+	dstH, err := netlink.NewHandleAt(netns.NsHandle(dst.NS.Fd()))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open netlink handle in the namespace of %s: %w", dst.Node, err)
+	}
+	defer dstH.Close()
+
+	l, err := dstH.LinkByName(dst.Iface)
+	switch {
+	case err == nil:
+		// the existing veth connecting both namespaces is used as is
+		if peer := vethPeer(dstH, l, src.NS); peer != 0 {
+			target, err := srcH.LinkByIndex(peer)
+			if err != nil {
+				return nil, false, fmt.Errorf("peer of the interface %s not found in the namespace of %s: %w", dst, src.Node, err)
+			}
+
+			return target, false, nil
+		}
+
+		return nil, false, fmt.Errorf("interface %s exists in the namespace of %s, but is not connected to %s",
+			dst.Iface, dst.Node, src.Node)
+	case !errors.As(err, &netlink.LinkNotFoundError{}):
+		return nil, false, fmt.Errorf("failed to get interface %s in the namespace of %s: %w", dst.Iface, dst.Node, err)
+	}
+
+	target, err := addHelperVeth(srcH, dstH, srcLink, src, dst)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return target, true, nil
+}
+
+// vethPeer returns the index of the peer of the veth interface l if the peer is in the namespace nsh.
+func vethPeer(h *netlink.Handle, l netlink.Link, nsh ns.NetNS) int {
+	if l.Type() != "veth" {
+		return 0
+	}
+
+	id, err := h.GetNetNsIdByFd(int(nsh.Fd()))
+	if err != nil || id < 0 || l.Attrs().NetNsID != id {
+		return 0
+	}
+
+	return l.Attrs().ParentIndex
+}
+
+// addHelperVeth creates a veth interface connecting the src namespace with the dst namespace.
+// The dst side of the veth is named after the dst interface,
+// the src side is tagged with the dst endpoint in its alias.
+func addHelperVeth(srcH, dstH *netlink.Handle, srcLink netlink.Link, src, dst *MirrorEndpoint) (netlink.Link, error) {
+	name := helperName(dst)
+
+	veth := &netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{
+			Name: name,
+			// the mirrored frames exceeding the MTU of the veth are dropped
+			MTU: srcLink.Attrs().MTU,
+		},
+		PeerName:      dst.Iface,
+		PeerNamespace: netlink.NsFd(dst.NS.Fd()),
+	}
+
+	if err := srcH.LinkAdd(veth); err != nil {
+		return nil, fmt.Errorf("failed to create veth %s:%s - %s: %w", src.Node, name, dst, err)
+	}
+
+	target, err := setupHelperVeth(srcH, dstH, name, dst)
+	if err != nil {
+		_ = srcH.LinkDel(veth)
+		return nil, err
+	}
+
+	return target, nil
+}
+
+func setupHelperVeth(srcH, dstH *netlink.Handle, name string, dst *MirrorEndpoint) (netlink.Link, error) {
+	target, err := srcH.LinkByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := srcH.LinkSetAlias(target, mirrorAliasPrefix+dst.String()); err != nil {
+		return nil, fmt.Errorf("failed to set alias of the interface %s: %w", name, err)
+	}
+
+	if err := srcH.LinkSetUp(target); err != nil {
+		return nil, fmt.Errorf("failed to set interface %s up: %w", name, err)
+	}
+
+	peer, err := dstH.LinkByName(dst.Iface)
+	if err != nil {
+		return nil, fmt.Errorf("interface %s not found in the namespace of %s: %w", dst.Iface, dst.Node, err)
+	}
+
+	if err := dstH.LinkSetUp(peer); err != nil {
+		return nil, fmt.Errorf("failed to set interface %s up in the namespace of %s: %w", dst.Iface, dst.Node, err)
+	}
+
+	return target, nil
+}
+
+// helperName returns the name of the helper veth interface for the dst endpoint.
+func helperName(dst *MirrorEndpoint) string {
+	return fmt.Sprintf("%s%x", mirrorHelperPrefix, sha256.Sum256([]byte(dst.String())))[:len(mirrorHelperPrefix)+8]
+}
+
+// sameNetNS returns true if both handles refer to the same network namespace.
+func sameNetNS(a, b ns.NetNS) (bool, error) {
+	var sa, sb unix.Stat_t
+
+	if err := unix.Fstat(int(a.Fd()), &sa); err != nil {
+		return false, fmt.Errorf("failed to stat namespace %s: %w", a.Path(), err)
+	}
+
+	if err := unix.Fstat(int(b.Fd()), &sb); err != nil {
+		return false, fmt.Errorf("failed to stat namespace %s: %w", b.Path(), err)
+	}
+
+	return sa.Dev == sb.Dev && sa.Ino == sb.Ino, nil
+}
+
+// addMirrorFilters adds the u32 filters matching all packets with the mirred action
+// to the clsact qdisc of the src interface.
+func addMirrorFilters(h *netlink.Handle, tcnl *tc.Tc, src, target netlink.Link, dir MirrorDirection) error {
+	ifindex := uint32(src.Attrs().Index)
+
+	qdisc := tc.Object{
+		Msg: tc.Msg{
+			Family:  unix.AF_UNSPEC,
+			Ifindex: ifindex,
+			Handle:  core.BuildHandle(tc.HandleRoot, 0),
+			Parent:  tc.HandleIngress,
+		},
+		Attribute: tc.Attribute{
+			Kind: "clsact",
+		},
+	}
+
+	if err := tcnl.Qdisc().Replace(&qdisc); err != nil {
+		return fmt.Errorf("failed to add clsact qdisc: %w", err)
+	}
+
+	// the u32 filters of the ingress and egress parents of the clsact qdisc share their hash tables,
+	// so the priorities must be unique across both parents for the filters to be dumped per parent
+	filters := map[MirrorDirection][]mirrorFilter{}
+	prios := map[uint16]bool{}
+
+	for _, d := range []MirrorDirection{MirrorRx, MirrorTx} {
+		fs, ps, err := mirrorFilters(h, src, mirrorParents[d])
+		if err != nil {
+			return fmt.Errorf("failed to get %s filters: %w", d, err)
+		}
+
+		filters[d] = fs
+		for p := range ps {
+			prios[p] = true
+		}
+	}
+
+	for _, d := range []MirrorDirection{MirrorRx, MirrorTx} {
+		if dir != MirrorBoth && dir != d {
+			continue
+		}
+
+		for _, f := range filters[d] {
+			if f.target == target.Attrs().Index {
+				return fmt.Errorf("%s traffic is already mirrored to the interface %s", d, target.Attrs().Name)
+			}
+		}
+
+		prio, err := freeMirrorPrio(prios)
+		if err != nil {
+			return fmt.Errorf("%s traffic: %w", d, err)
+		}
+
+		cookie := []byte(mirrorCookie)
+		actions := []*tc.Action{
+			{
+				Kind:   "mirred",
+				Cookie: &cookie,
+				Mirred: &tc.Mirred{
+					Parms: &tc.MirredParam{
+						Action:  tc.ActPipe,
+						Eaction: tcaEgressMirror,
+						IfIndex: uint32(target.Attrs().Index),
+					},
+				},
+			},
+		}
+
+		filter := tc.Object{
+			Msg: tc.Msg{
+				Family:  unix.AF_UNSPEC,
+				Ifindex: ifindex,
+				Parent:  mirrorParents[d],
+				Info:    core.BuildHandle(uint32(prio), ethPAll),
+			},
+			Attribute: tc.Attribute{
+				Kind: "u32",
+				U32: &tc.U32{
+					// match u32 0 0 matches all packets
+					Sel: &tc.U32Sel{
+						Flags: tcU32Terminal,
+						NKeys: 1,
+						Keys:  []tc.U32Key{{}},
+					},
+					Actions: &actions,
+				},
+			},
+		}
+
+		if err := tcnl.Filter().Add(&filter); err != nil {
+			return fmt.Errorf("failed to add %s filter: %w", d, err)
+		}
+
+		prios[prio] = true
+	}
+
+	return nil
+}
+
+// mirrorFilter is a filter created by containerlab mirroring the traffic to the target interface.
+type mirrorFilter struct {
+	prio   uint16
+	target int
+}
+
+// mirrorFilters returns the mirror filters of the link attached to the clsact parent
+// along with the priorities used by all filters of the parent.
+// The filters are dumped with netlink, since go-tc fails to decode the action attributes added by recent kernels.
+func mirrorFilters(h *netlink.Handle, l netlink.Link, parent uint32) ([]mirrorFilter, map[uint16]bool, error) {
+	fs, err := h.FilterList(l, parent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mirrors []mirrorFilter
+	prios := map[uint16]bool{}
+
+	for _, f := range fs {
+		prios[f.Attrs().Priority] = true
+
+		u, ok := f.(*netlink.U32)
+		if !ok || f.Attrs().Priority < mirrorPrio {
+			continue
+		}
+
+		for _, a := range u.Actions {
+			if m, ok := a.(*netlink.MirredAction); ok && m.MirredAction == netlink.TCA_EGRESS_MIRROR {
+				mirrors = append(mirrors, mirrorFilter{prio: f.Attrs().Priority, target: m.Ifindex})
+			}
+		}
+	}
+
+	return mirrors, prios, nil
+}
+
+// freeMirrorPrio returns the priority for a new mirror filter. Each mirror filter has its own priority,
+// so that the mirror is removed by removing the filters of its priority.
+func freeMirrorPrio(used map[uint16]bool) (uint16, error) {
+	for prio := uint32(mirrorPrio); prio <= 0xffff; prio++ {
+		if !used[uint16(prio)] {
+			return uint16(prio), nil
+		}
+	}
+
+	return 0, errors.New("no free filter priority")
+}
+
+// ListMirrors returns the mirrors configured in the network namespace of the node.
+func ListMirrors(node string, nsh ns.NetNS) ([]Mirror, error) {
+	h, err := netlink.NewHandleAt(netns.NsHandle(nsh.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open netlink handle in the namespace of %s: %w", node, err)
+	}
+	defer h.Close()
+
+	return listMirrors(h, node)
+}
+
+func listMirrors(h *netlink.Handle, node string) ([]Mirror, error) {
+	ls, err := h.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interfaces in the namespace of %s: %w", node, err)
+	}
+
+	byIndex := map[int]netlink.Link{}
+	for _, l := range ls {
+		byIndex[l.Attrs().Index] = l
+	}
+
+	var mirrors []Mirror
+
+	for _, l := range ls {
+		if !hasClsact(h, l) {
+			continue
+		}
+
+		for _, d := range []MirrorDirection{MirrorRx, MirrorTx} {
+			filters, _, err := mirrorFilters(h, l, mirrorParents[d])
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s filters of the interface %s in the namespace of %s: %w",
+					d, l.Attrs().Name, node, err)
+			}
+
+			for _, f := range filters {
+				m := Mirror{
+					Source:    node + ":" + l.Attrs().Name,
+					Direction: d,
+					ifindex:   l.Attrs().Index,
+					target:    f.target,
+					parent:    mirrorParents[d],
+					prio:      f.prio,
+				}
+
+				if tl, ok := byIndex[f.target]; ok {
+					m.Target = tl.Attrs().Name
+
+					if strings.HasPrefix(tl.Attrs().Alias, mirrorAliasPrefix) {
+						m.Destination = strings.TrimPrefix(tl.Attrs().Alias, mirrorAliasPrefix)
+					}
+				}
+
+				mirrors = append(mirrors, m)
+			}
+		}
+	}
+
+	sort.Slice(mirrors, func(i, j int) bool {
+		if mirrors[i].Source != mirrors[j].Source {
+			return mirrors[i].Source < mirrors[j].Source
+		}
+		if mirrors[i].Direction != mirrors[j].Direction {
+			return mirrors[i].Direction < mirrors[j].Direction
+		}
+		return mirrors[i].Target < mirrors[j].Target
+	})
+
+	return mirrors, nil
+}
+
+// hasClsact returns true if the link has the clsact qdisc the mirror filters are attached to.
+func hasClsact(h *netlink.Handle, l netlink.Link) bool {
+	qdiscs, err := h.QdiscList(l)
+	if err != nil {
+		return false
+	}
+
+	for _, q := range qdiscs {
+		if q.Type() == "clsact" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DeleteMirrors removes the mirrors of the src interface and the helper veth interfaces
+// that are no longer used by any mirror. The number of the removed mirrors is returned.
+func DeleteMirrors(src *MirrorEndpoint) (int, error) {
+	h, err := netlink.NewHandleAt(netns.NsHandle(src.NS.Fd()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open netlink handle in the namespace of %s: %w", src.Node, err)
+	}
+	defer h.Close()
+
+	if _, err := h.LinkByName(src.Iface); err != nil {
+		return 0, fmt.Errorf("interface %s not found in the namespace of %s: %w", src.Iface, src.Node, err)
+	}
+
+	mirrors, err := listMirrors(h, src.Node)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+
+	for _, m := range mirrors {
+		if m.Source != src.String() {
+			continue
+		}
+
+		// the zero handle removes all filters of the mirror priority
+		err := h.FilterDel(&netlink.U32{
+			FilterAttrs: netlink.FilterAttrs{
+				LinkIndex: m.ifindex,
+				Parent:    m.parent,
+				Priority:  m.prio,
+				Protocol:  unix.ETH_P_ALL,
+			},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete %s mirror of %s: %w", m.Direction, m.Source, err)
+		}
+
+		deleted++
+	}
+
+	return deleted, deleteUnusedHelpers(h, src.Node)
+}
+
+// deleteUnusedHelpers removes the helper veth interfaces which are not a target of any mirror.
+func deleteUnusedHelpers(h *netlink.Handle, node string) error {
+	mirrors, err := listMirrors(h, node)
+	if err != nil {
+		return err
+	}
+
+	used := map[int]bool{}
+	for _, m := range mirrors {
+		used[m.target] = true
+	}
+
+	ls, err := h.LinkList()
+	if err != nil {
+		return fmt.Errorf("failed to list interfaces in the namespace of %s: %w", node, err)
+	}
+
+	for _, l := range ls {
+		if !strings.HasPrefix(l.Attrs().Alias, mirrorAliasPrefix) || used[l.Attrs().Index] {
+			continue
+		}
+
+		// the peer of the veth in the destination namespace is removed along with it
+		if err := h.LinkDel(l); err != nil {
+			return fmt.Errorf("failed to delete helper interface %s in the namespace of %s: %w",
+				l.Attrs().Name, node, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package tc
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/srl-labs/containerlab/internal/netnstest"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// packetSocket opens a packet socket bound to the interface in the namespace.
+func packetSocket(t *testing.T, nsh ns.NetNS, iface string) (int, int) {
+	t.Helper()
+
+	var fd, ifindex int
+
+	err := nsh.Do(func(_ ns.NetNS) error {
+		l, err := net.InterfaceByName(iface)
+		if err != nil {
+			return err
+		}
+		ifindex = l.Index
+
+		fd, err = unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, int(htons(unix.ETH_P_ALL)))
+		if err != nil {
+			return err
+		}
+
+		return unix.Bind(fd, &unix.SockaddrLinklayer{Protocol: htons(unix.ETH_P_ALL), Ifindex: ifindex})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { unix.Close(fd) })
+
+	tv := unix.NsecToTimeval((100 * time.Millisecond).Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		t.Fatal(err)
+	}
+
+	return fd, ifindex
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+// sendFrame sends an ethernet frame with the payload from the interface of the packet socket.
+func sendFrame(t *testing.T, fd, ifindex int, payload string) {
+	t.Helper()
+
+	frame := []byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // dst MAC
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x01, // src MAC
+		0x88, 0xb5, // local experimental ethertype
+	}
+	frame = append(frame, payload...)
+
+	err := unix.Sendto(fd, frame, 0, &unix.SockaddrLinklayer{Ifindex: ifindex, Halen: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// received returns true if a frame with the payload is received by the packet socket within a few seconds.
+func received(fd int, payload string) bool {
+	buf := make([]byte, 2048)
+	deadline := time.Now().Add(3 * time.Second)
+
+	for time.Now().Before(deadline) {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			continue
+		}
+
+		if bytes.Contains(buf[:n], []byte(payload)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestMirror(t *testing.T) {
+	node := netnstest.New(t)
+	analyzer := netnstest.New(t)
+
+	// e1-1 is the mirrored interface of the node connected to its peer p1
+	netnstest.AddVeth(t, node, "e1-1", node, "p1")
+	netnstest.SetLinksUp(t, node, "e1-1", "p1")
+
+	src := &MirrorEndpoint{Node: "node", Iface: "e1-1", NS: node}
+	dst := &MirrorEndpoint{Node: "analyzer", Iface: "eth1", NS: analyzer}
+
+	if err := AddMirror(src, dst, MirrorBoth); err != nil {
+		t.Fatal(err)
+	}
+
+	dstFd, _ := packetSocket(t, analyzer, "eth1")
+	peerFd, peerIdx := packetSocket(t, node, "p1")
+	srcFd, srcIdx := packetSocket(t, node, "e1-1")
+
+	// frames sent by the peer are received by e1-1
+	sendFrame(t, peerFd, peerIdx, "clab-mirror-rx")
+	if !received(dstFd, "clab-mirror-rx") {
+		t.Error("received traffic was not mirrored to the destination interface")
+	}
+
+	sendFrame(t, srcFd, srcIdx, "clab-mirror-tx")
+	if !received(dstFd, "clab-mirror-tx") {
+		t.Error("transmitted traffic was not mirrored to the destination interface")
+	}
+
+	mirrors, err := ListMirrors("node", node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range mirrors {
+		got = append(got, strings.Join([]string{m.Source, string(m.Direction), m.Destination}, " "))
+	}
+
+	want := []string{"node:e1-1 rx analyzer:eth1", "node:e1-1 tx analyzer:eth1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got mirrors %q, want %q", got, want)
+	}
+
+	if err := AddMirror(src, dst, MirrorRx); err == nil {
+		t.Fatal("expected an error when mirroring to the same destination twice")
+	}
+
+	n, err := DeleteMirrors(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 2 {
+		t.Fatalf("deleted %d mirrors, want 2", n)
+	}
+
+	// the helper veth is removed along with the destination interface
+	err = analyzer.Do(func(_ ns.NetNS) error {
+		_, err := netlink.LinkByName("eth1")
+		return err
+	})
+	if err == nil {
+		t.Fatal("destination interface of the helper veth was not removed")
+	}
+
+	if mirrors, err := ListMirrors("node", node); err != nil || len(mirrors) != 0 {
+		t.Fatalf("unexpected mirrors after delete: %v, %v", mirrors, err)
+	}
+}
+
+func TestMirrorAdjacent(t *testing.T) {
+	node := netnstest.New(t)
+	analyzer := netnstest.New(t)
+
+	netnstest.AddVeth(t, node, "e1-1", node, "p1")
+	netnstest.SetLinksUp(t, node, "e1-1", "p1")
+	// e1-2 of the node is connected to eth2 of the analyzer
+	netnstest.AddVeth(t, node, "e1-2", analyzer, "eth2")
+	netnstest.SetLinksUp(t, node, "e1-2")
+	netnstest.SetLinksUp(t, analyzer, "eth2")
+
+	src := &MirrorEndpoint{Node: "node", Iface: "e1-1", NS: node}
+
+	if err := AddMirror(src, &MirrorEndpoint{Node: "analyzer", Iface: "eth2", NS: analyzer}, MirrorRx); err != nil {
+		t.Fatal(err)
+	}
+
+	mirrors, err := ListMirrors("node", node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mirrors) != 1 || mirrors[0].Target != "e1-2" || mirrors[0].Destination != "" {
+		t.Fatalf("unexpected mirrors %+v", mirrors)
+	}
+
+	dstFd, _ := packetSocket(t, analyzer, "eth2")
+	peerFd, peerIdx := packetSocket(t, node, "p1")
+
+	sendFrame(t, peerFd, peerIdx, "clab-mirror-adjacent")
+	if !received(dstFd, "clab-mirror-adjacent") {
+		t.Error("received traffic was not mirrored to the adjacent interface")
+	}
+
+	if _, err := DeleteMirrors(src); err != nil {
+		t.Fatal(err)
+	}
+
+	// the existing link is not removed
+	err = node.Do(func(_ ns.NetNS) error {
+		_, err := netlink.LinkByName("e1-2")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("adjacent interface was removed: %v", err)
+	}
+}
+
+func TestMirrorErrors(t *testing.T) {
+	node := netnstest.New(t)
+	analyzer := netnstest.New(t)
+
+	netnstest.AddVeth(t, node, "e1-1", node, "p1")
+	netnstest.SetLinksUp(t, node, "e1-1", "p1")
+
+	err := analyzer.Do(func(_ ns.NetNS) error {
+		return netlink.LinkAdd(&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "dummy1"}})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		src     *MirrorEndpoint
+		dst     *MirrorEndpoint
+		wantErr string
+	}{
+		"missing source interface": {
+			src:     &MirrorEndpoint{Node: "node", Iface: "e1-9", NS: node},
+			dst:     &MirrorEndpoint{Node: "analyzer", Iface: "eth1", NS: analyzer},
+			wantErr: "interface e1-9 not found in the namespace of node",
+		},
+		"destination not adjacent": {
+			src:     &MirrorEndpoint{Node: "node", Iface: "e1-1", NS: node},
+			dst:     &MirrorEndpoint{Node: "analyzer", Iface: "dummy1", NS: analyzer},
+			wantErr: "interface dummy1 exists in the namespace of analyzer, but is not connected to node",
+		},
+		"missing destination in the same namespace": {
+			src:     &MirrorEndpoint{Node: "node", Iface: "e1-1", NS: node},
+			dst:     &MirrorEndpoint{Node: "node", Iface: "e1-9", NS: node},
+			wantErr: "interface e1-9 not found in the namespace of node",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := AddMirror(tc.src, tc.dst, MirrorBoth)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Fatalf("wanted error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
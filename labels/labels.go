@@ -10,4 +10,17 @@ const (
 	NodeLabDir    = "clab-node-lab-dir"
 	TopoFile      = "clab-topo-file"
 	NodeMgmtNetBr = "clab-mgmt-net-bridge"
+	// NodeConsolePort is the host port the node serial console is published on.
+	NodeConsolePort = "clab-node-console-port"
+	// NodeExposedPorts are the host ports the services exposed by the node are published on,
+	// in the comma separated <service>:<host port> format.
+	NodeExposedPorts = "clab-node-exposed-ports"
+	// Owner is the name of the user who deployed the lab.
+	Owner = "clab-owner"
+	// Version is the containerlab version that deployed the node.
+	Version = "clab-version"
+	// LabName is the name of the lab that created the management network.
+	LabName = "clab-lab-name"
+	// ImageBuildContextHash is the hash of the build context of the image built by containerlab.
+	ImageBuildContextHash = "clab-image-build-context-hash"
 )
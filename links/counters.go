@@ -0,0 +1,68 @@
+package links
+
+import (
+	"sort"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+)
+
+// InterfaceCounters are the statistics counters of a network interface.
+type InterfaceCounters struct {
+	Interface string `json:"interface"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+// GetInterfaceCounters returns the counters of the interfaces in the network namespace of the node,
+// sorted by the interface name.
+func GetInterfaceCounters(n Node) ([]*InterfaceCounters, error) {
+	var counters []*InterfaceCounters
+
+	err := n.ExecFunction(func(_ ns.NetNS) error {
+		links, err := netlink.LinkList()
+		if err != nil {
+			return err
+		}
+
+		for _, l := range links {
+			counters = append(counters, linkCounters(l))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(counters, func(i, j int) bool {
+		return counters[i].Interface < counters[j].Interface
+	})
+
+	return counters, nil
+}
+
+// linkCounters returns the counters of the link. The counters are zero when the link has no statistics.
+func linkCounters(l netlink.Link) *InterfaceCounters {
+	attrs := l.Attrs()
+	c := &InterfaceCounters{Interface: attrs.Name}
+
+	if s := attrs.Statistics; s != nil {
+		c.RxPackets = s.RxPackets
+		c.RxBytes = s.RxBytes
+		c.RxErrors = s.RxErrors
+		c.RxDropped = s.RxDropped
+		c.TxPackets = s.TxPackets
+		c.TxBytes = s.TxBytes
+		c.TxErrors = s.TxErrors
+		c.TxDropped = s.TxDropped
+	}
+
+	return c
+}
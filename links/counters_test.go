@@ -0,0 +1,69 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vishvananda/netlink"
+)
+
+// currentNSNode is a fake node running the functions in the current network namespace.
+type currentNSNode struct {
+	*fakeNode
+}
+
+func (currentNSNode) ExecFunction(f func(ns.NetNS) error) error {
+	return f(nil)
+}
+
+func TestLinkCounters(t *testing.T) {
+	l := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{
+		Name: "e1-1",
+		Statistics: &netlink.LinkStatistics{
+			RxPackets: 10, RxBytes: 1000, RxErrors: 1, RxDropped: 2,
+			TxPackets: 20, TxBytes: 2000, TxErrors: 3, TxDropped: 4,
+			Multicast: 5,
+		},
+	}}
+
+	want := &InterfaceCounters{
+		Interface: "e1-1",
+		RxPackets: 10, RxBytes: 1000, RxErrors: 1, RxDropped: 2,
+		TxPackets: 20, TxBytes: 2000, TxErrors: 3, TxDropped: 4,
+	}
+
+	if d := cmp.Diff(want, linkCounters(l)); d != "" {
+		t.Errorf("counters mismatch (-want +got):\n%s", d)
+	}
+
+	// the link without statistics has zero counters
+	l.Statistics = nil
+
+	if d := cmp.Diff(&InterfaceCounters{Interface: "e1-1"}, linkCounters(l)); d != "" {
+		t.Errorf("counters of the link without statistics mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestGetInterfaceCounters(t *testing.T) {
+	counters, err := GetInterfaceCounters(currentNSNode{newFakeNode("n1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+
+	for i, c := range counters {
+		if i > 0 && counters[i-1].Interface > c.Interface {
+			t.Errorf("interfaces are not sorted: %s goes before %s", counters[i-1].Interface, c.Interface)
+		}
+
+		if c.Interface == "lo" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Error("counters of the loopback interface are not found")
+	}
+}
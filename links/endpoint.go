@@ -3,6 +3,8 @@ package links
 import (
 	"fmt"
 	"net"
+	"net/netip"
+	"strings"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	log "github.com/sirupsen/logrus"
@@ -20,8 +22,15 @@ const (
 type Endpoint interface {
 	GetNode() Node
 	GetIfaceName() string
+	// GetIfaceAlias returns the name the network OS of the node uses for the interface,
+	// empty when the endpoint has no alias set.
+	GetIfaceAlias() string
 	GetRandIfaceName() string
 	GetMac() net.HardwareAddr
+	// GetIPv4 and GetIPv6 return the addresses assigned to the interface,
+	// the returned prefixes are invalid when no address is set.
+	GetIPv4() netip.Prefix
+	GetIPv6() netip.Prefix
 	String() string
 	// GetLink retrieves the link that the endpoint is assigned to
 	GetLink() Link
@@ -37,9 +46,14 @@ type Endpoint interface {
 type EndpointGeneric struct {
 	Node      Node
 	IfaceName string
+	// Alias is the alias of the kernel interface, e.g. the interface name used by the network OS.
+	Alias string
 	// Link is the link this endpoint belongs to.
-	Link     Link
-	MAC      net.HardwareAddr
+	Link Link
+	MAC  net.HardwareAddr
+	// IPv4 and IPv6 are the addresses assigned to the interface.
+	IPv4     netip.Prefix
+	IPv6     netip.Prefix
 	randName string
 }
 
@@ -62,10 +76,22 @@ func (e *EndpointGeneric) GetIfaceName() string {
 	return e.IfaceName
 }
 
+func (e *EndpointGeneric) GetIfaceAlias() string {
+	return e.Alias
+}
+
 func (e *EndpointGeneric) GetMac() net.HardwareAddr {
 	return e.MAC
 }
 
+func (e *EndpointGeneric) GetIPv4() netip.Prefix {
+	return e.IPv4
+}
+
+func (e *EndpointGeneric) GetIPv6() netip.Prefix {
+	return e.IPv6
+}
+
 func (e *EndpointGeneric) GetLink() Link {
 	return e.Link
 }
@@ -84,13 +110,32 @@ func (e *EndpointGeneric) Remove() error {
 			// interface is not present, all good
 			return nil
 		case err != nil:
-			return err
+			return &interfaceRemovalError{endpoint: e.String(), err: err}
 		}
 		log.Debugf("Removing interface %q from namespace %q", e.GetIfaceName(), e.GetNode().GetShortName())
-		return netlink.LinkDel(brSideEp)
+		if err := netlink.LinkDel(brSideEp); err != nil {
+			return &interfaceRemovalError{endpoint: e.String(), err: err}
+		}
+		return nil
 	})
 }
 
+// interfaceRemovalError is the error of an endpoint interface that can't be looked up or deleted.
+// It tells the failed removals apart from the failures to enter the namespace of the endpoint node,
+// which is gone together with its interfaces once the node container is removed.
+type interfaceRemovalError struct {
+	endpoint string
+	err      error
+}
+
+func (e *interfaceRemovalError) Error() string {
+	return fmt.Sprintf("failed to remove interface of endpoint %s: %v", e.endpoint, e.err)
+}
+
+func (e *interfaceRemovalError) Unwrap() error {
+	return e.err
+}
+
 // HasSameNodeAndInterface returns true if the given endpoint has the same node and interface name
 // as the `ept` endpoint.
 func (e *EndpointGeneric) HasSameNodeAndInterface(ept Endpoint) bool {
@@ -114,6 +159,10 @@ func CheckEndpointUniqueness(e Endpoint) error {
 		if e.HasSameNodeAndInterface(ept) {
 			return fmt.Errorf("duplicate endpoint %s", e)
 		}
+		// the alias must not be shared with the other interfaces of the node, be it their name or alias
+		if a := e.GetIfaceAlias(); a != "" && (a == ept.GetIfaceName() || a == ept.GetIfaceAlias()) {
+			return fmt.Errorf("alias %q of endpoint %s is used by endpoint %s", a, e, ept)
+		}
 	}
 
 	return nil
@@ -147,3 +196,34 @@ func CheckEndpointDoesNotExistYet(e Endpoint) error {
 		return fmt.Errorf("interface %s is defined via topology but does already exist: %v", e.String(), err)
 	})
 }
+
+// HostsEntries returns the /etc/hosts entries in the <name>:<ip> form
+// for the addresses assigned to the endpoints.
+// The entries are named <node>-<interface>, with the characters not allowed in the host names,
+// e.g. the slashes of ethernet-1/1, replaced by dashes.
+func HostsEntries(eps []Endpoint) []string {
+	var entries []string
+
+	for _, ep := range eps {
+		name := hostsEntryName(ep.GetNode().GetShortName() + "-" + ep.GetIfaceName())
+
+		for _, p := range []netip.Prefix{ep.GetIPv4(), ep.GetIPv6()} {
+			if p.IsValid() {
+				entries = append(entries, name+":"+p.Addr().String())
+			}
+		}
+	}
+
+	return entries
+}
+
+// hostsEntryName replaces the characters other than the letters, digits, dots and dashes with dashes.
+func hostsEntryName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		}
+		return '-'
+	}, s)
+}
@@ -2,7 +2,7 @@ package links
 
 import (
 	"fmt"
-	"net"
+	"net/netip"
 
 	"github.com/srl-labs/containerlab/utils"
 )
@@ -13,6 +13,13 @@ type EndpointRaw struct {
 	Node  string `yaml:"node"`
 	Iface string `yaml:"interface"`
 	MAC   string `yaml:"mac,omitempty"`
+	// Alias is the name the network OS of the node uses for the interface, e.g. e1-1,
+	// it is set as the alias of the kernel interface.
+	Alias string `yaml:"alias,omitempty"`
+	// IPv4 and IPv6 are the addresses with the prefix length, e.g. 192.168.0.1/24,
+	// assigned to the interface.
+	IPv4 string `yaml:"ipv4,omitempty"`
+	IPv6 string `yaml:"ipv6,omitempty"`
 }
 
 // NewEndpointRaw creates a new EndpointRaw struct.
@@ -38,6 +45,16 @@ func (er *EndpointRaw) Resolve(params *ResolveParams, l Link) (Endpoint, error)
 
 	genericEndpoint := NewEndpointGeneric(node, er.Iface, l)
 
+	if er.Alias != "" {
+		// the long interface names are set as the kernel interface alias already
+		if len(er.Iface) > 15 {
+			return nil, fmt.Errorf("endpoint %s:%s: the alias %q can't be set for the interface "+
+				"with a name longer than 15 characters", er.Node, er.Iface, er.Alias)
+		}
+
+		genericEndpoint.Alias = er.Alias
+	}
+
 	var err error
 	if er.MAC == "" {
 		// if mac is not present generate one
@@ -46,12 +63,27 @@ func (er *EndpointRaw) Resolve(params *ResolveParams, l Link) (Endpoint, error)
 			return nil, err
 		}
 	} else {
-		// if MAC is present, set it
-		m, err := net.ParseMAC(er.MAC)
+		// if MAC is present, validate it and store it in the normalized form
+		m, err := utils.ParseInterfaceMAC(er.MAC)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("endpoint %s:%s: %w", er.Node, er.Iface, err)
 		}
 		genericEndpoint.MAC = m
+		er.MAC = m.String()
+	}
+
+	if er.IPv4 != "" {
+		genericEndpoint.IPv4, err = parseEndpointIP(er.IPv4, false)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s:%s: %w", er.Node, er.Iface, err)
+		}
+	}
+
+	if er.IPv6 != "" {
+		genericEndpoint.IPv6, err = parseEndpointIP(er.IPv6, true)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s:%s: %w", er.Node, er.Iface, err)
+		}
 	}
 
 	var e Endpoint
@@ -72,3 +104,22 @@ func (er *EndpointRaw) Resolve(params *ResolveParams, l Link) (Endpoint, error)
 
 	return e, nil
 }
+
+// parseEndpointIP parses the endpoint address with the prefix length
+// and checks that it belongs to the expected address family.
+func parseEndpointIP(s string, v6 bool) (netip.Prefix, error) {
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid ip address %q, an address with a prefix length is expected: %w", s, err)
+	}
+
+	if p.Addr().Is6() != v6 {
+		family := "ipv4"
+		if v6 {
+			family = "ipv6"
+		}
+		return netip.Prefix{}, fmt.Errorf("%q is not an %s address", s, family)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,178 @@
+package links
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEndpointAlias(t *testing.T) {
+	tests := map[string]struct {
+		endpoints []*EndpointRaw
+		// wantErr is the expected substring of the resolve or the verify error
+		wantErr string
+	}{
+		"aliases": {
+			endpoints: []*EndpointRaw{
+				{Node: "srl1", Iface: "eth1", Alias: "e1-1"},
+				{Node: "srl1", Iface: "eth2", Alias: "e1-2"},
+				{Node: "srl1", Iface: "eth3"},
+			},
+		},
+		"alias of long interface name": {
+			endpoints: []*EndpointRaw{
+				{Node: "srl1", Iface: "ethernet-1-1-very-long", Alias: "e1-1"},
+			},
+			wantErr: `the alias "e1-1" can't be set for the interface with a name longer than 15 characters`,
+		},
+		"duplicate aliases": {
+			endpoints: []*EndpointRaw{
+				{Node: "srl1", Iface: "eth1", Alias: "e1-1"},
+				{Node: "srl1", Iface: "eth2", Alias: "e1-1"},
+			},
+			wantErr: `alias "e1-1" of endpoint srl1:eth1 is used by endpoint srl1:eth2`,
+		},
+		"alias matching interface name": {
+			endpoints: []*EndpointRaw{
+				{Node: "srl1", Iface: "eth1", Alias: "eth2"},
+				{Node: "srl1", Iface: "eth2"},
+			},
+			wantErr: `alias "eth2" of endpoint srl1:eth1 is used by endpoint srl1:eth2`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := newFakeNode("srl1")
+			params := &ResolveParams{Nodes: map[string]Node{"srl1": n}}
+
+			var errs []string
+
+			for _, er := range tt.endpoints {
+				ep, err := er.Resolve(params, nil)
+				if err != nil {
+					errs = append(errs, err.Error())
+					continue
+				}
+
+				if ep.GetIfaceAlias() != er.Alias {
+					t.Errorf("got alias %q of endpoint %s, want %q", ep.GetIfaceAlias(), ep, er.Alias)
+				}
+			}
+
+			for _, ep := range n.GetEndpoints() {
+				if err := ep.Verify(&VerifyLinkParams{}); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+
+			got := strings.Join(errs, "\n")
+
+			switch {
+			case tt.wantErr == "" && got != "":
+				t.Fatalf("unexpected error: %s", got)
+			case !strings.Contains(got, tt.wantErr):
+				t.Fatalf("got error %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEndpointIPs(t *testing.T) {
+	tests := map[string]struct {
+		endpoint *EndpointRaw
+		wantIPv4 string
+		wantIPv6 string
+		wantErr  string
+	}{
+		"no addresses": {
+			endpoint: &EndpointRaw{Node: "srl1", Iface: "eth1"},
+		},
+		"both addresses": {
+			endpoint: &EndpointRaw{Node: "srl1", Iface: "eth1", IPv4: "192.168.0.1/30", IPv6: "2001:db8::1/64"},
+			wantIPv4: "192.168.0.1/30",
+			wantIPv6: "2001:db8::1/64",
+		},
+		"no prefix length": {
+			endpoint: &EndpointRaw{Node: "srl1", Iface: "eth1", IPv4: "192.168.0.1"},
+			wantErr:  `endpoint srl1:eth1: invalid ip address "192.168.0.1"`,
+		},
+		"ipv6 address as ipv4": {
+			endpoint: &EndpointRaw{Node: "srl1", Iface: "eth1", IPv4: "2001:db8::1/64"},
+			wantErr:  `endpoint srl1:eth1: "2001:db8::1/64" is not an ipv4 address`,
+		},
+		"ipv4 address as ipv6": {
+			endpoint: &EndpointRaw{Node: "srl1", Iface: "eth1", IPv6: "192.168.0.1/30"},
+			wantErr:  `endpoint srl1:eth1: "192.168.0.1/30" is not an ipv6 address`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := &ResolveParams{Nodes: map[string]Node{"srl1": newFakeNode("srl1")}}
+
+			ep, err := tt.endpoint.Resolve(params, nil)
+
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := prefixString(ep.GetIPv4()); got != tt.wantIPv4 {
+				t.Errorf("got ipv4 %q, want %q", got, tt.wantIPv4)
+			}
+
+			if got := prefixString(ep.GetIPv6()); got != tt.wantIPv6 {
+				t.Errorf("got ipv6 %q, want %q", got, tt.wantIPv6)
+			}
+		})
+	}
+}
+
+// prefixString returns the string form of the valid prefix and an empty string otherwise.
+func prefixString(p netip.Prefix) string {
+	if !p.IsValid() {
+		return ""
+	}
+
+	return p.String()
+}
+
+func TestHostsEntries(t *testing.T) {
+	params := &ResolveParams{Nodes: map[string]Node{
+		"srl1": newFakeNode("srl1"),
+		"srl2": newFakeNode("srl2"),
+	}}
+
+	var eps []Endpoint
+
+	for _, er := range []*EndpointRaw{
+		{Node: "srl1", Iface: "ethernet-1/1", IPv4: "192.168.0.1/30", IPv6: "2001:db8::1/64"},
+		{Node: "srl2", Iface: "e1-1", IPv4: "192.168.0.2/30"},
+		{Node: "srl2", Iface: "e1-2"},
+	} {
+		ep, err := er.Resolve(params, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		eps = append(eps, ep)
+	}
+
+	want := []string{
+		"srl1-ethernet-1-1:192.168.0.1",
+		"srl1-ethernet-1-1:2001:db8::1",
+		"srl2-e1-1:192.168.0.2",
+	}
+
+	if d := cmp.Diff(want, HostsEntries(eps)); d != "" {
+		t.Errorf("HostsEntries() diff (-want +got):\n%s", d)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/netip"
 	"strings"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/internal/slices"
 	"github.com/srl-labs/containerlab/nodes/state"
 	"github.com/vishvananda/netlink"
@@ -26,10 +28,12 @@ const (
 
 // LinkCommonParams represents the common parameters for all link types.
 type LinkCommonParams struct {
-	MTU             int                    `yaml:"mtu,omitempty"`
-	Labels          map[string]string      `yaml:"labels,omitempty"`
-	Vars            map[string]interface{} `yaml:"vars,omitempty"`
-	DeploymentState LinkDeploymentState
+	MTU     int                    `yaml:"mtu,omitempty"`
+	Labels  map[string]string      `yaml:"labels,omitempty"`
+	Vars    map[string]interface{} `yaml:"vars,omitempty"`
+	Enabled *bool                  `yaml:"enabled,omitempty"`
+	// DeploymentState is the runtime state of the link, it is not part of the topology file
+	DeploymentState LinkDeploymentState `yaml:"-"`
 }
 
 // GetMTU returns the MTU of the link.
@@ -53,6 +57,7 @@ const (
 	LinkTypeHost        LinkType = "host"
 	LinkTypeVxlan       LinkType = "vxlan"
 	LinkTypeVxlanStitch LinkType = "vxlan-stitch"
+	LinkTypeBond        LinkType = "bond"
 
 	// LinkTypeBrief is a link definition where link types
 	// are encoded in the endpoint definition as string and allow users
@@ -60,6 +65,23 @@ const (
 	LinkTypeBrief LinkType = "brief"
 )
 
+// linkTypeKernelModules are the kernel modules needed to create the links of a type.
+// A new link type relying on a kernel module declares the module here.
+var linkTypeKernelModules = map[LinkType][]string{
+	LinkTypeVEth:        {"veth"},
+	LinkTypeMgmtNet:     {"veth"},
+	LinkTypeHost:        {"veth"},
+	LinkTypeMacVLan:     {"macvlan"},
+	LinkTypeVxlan:       {"vxlan"},
+	LinkTypeVxlanStitch: {"vxlan", "veth"},
+	LinkTypeBond:        {"veth", "bonding"},
+}
+
+// KernelModules returns the kernel modules needed to create the links of the type.
+func (lt LinkType) KernelModules() []string {
+	return linkTypeKernelModules[lt]
+}
+
 // parseLinkType parses a string representation of a link type into a LinkDefinitionType.
 func parseLinkType(s string) (LinkType, error) {
 	switch strings.TrimSpace(strings.ToLower(s)) {
@@ -84,6 +106,9 @@ func parseLinkType(s string) (LinkType, error) {
 	case string(LinkTypeVxlanStitch):
 		return LinkTypeVxlanStitch, nil
 
+	case string(LinkTypeBond):
+		return LinkTypeBond, nil
+
 	default:
 		return "", fmt.Errorf("unable to parse %q as LinkType", s)
 	}
@@ -197,6 +222,17 @@ func (ld *LinkDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error
 		l.LinkVxlanRaw.LinkType = LinkTypeVxlanStitch
 		ld.Link = &l.LinkVxlanRaw
 
+	case LinkTypeBond:
+		var l struct {
+			Type        string `yaml:"type"`
+			LinkBondRaw `yaml:",inline"`
+		}
+		err := unmarshal(&l)
+		if err != nil {
+			return err
+		}
+		ld.Link = &l.LinkBondRaw
+
 	case LinkTypeBrief:
 		// brief link's endpoint format
 		var l struct {
@@ -276,6 +312,15 @@ func (r *LinkDefinition) MarshalYAML() (interface{}, error) {
 			Type:         string(LinkTypeMacVLan),
 		}
 		return x, nil
+	case LinkTypeBond:
+		x := struct {
+			Type        string `yaml:"type"`
+			LinkBondRaw `yaml:",inline"`
+		}{
+			LinkBondRaw: *r.Link.(*LinkBondRaw),
+			Type:        string(LinkTypeBond),
+		}
+		return x, nil
 	case LinkTypeBrief:
 		return r.Link, nil
 	}
@@ -307,6 +352,25 @@ type Link interface {
 	GetMTU() int
 }
 
+// DeployError is an error returned when a link fails to deploy, it references the failed link.
+type DeployError struct {
+	Link Link
+	Err  error
+}
+
+func (e *DeployError) Error() string {
+	eps := make([]string, 0, len(e.Link.GetEndpoints()))
+	for _, ep := range e.Link.GetEndpoints() {
+		eps = append(eps, ep.String())
+	}
+
+	return fmt.Sprintf("link %s: %v", strings.Join(eps, " <--> "), e.Err)
+}
+
+func (e *DeployError) Unwrap() error {
+	return e.Err
+}
+
 func extractHostNodeInterfaceData(lb *LinkBriefRaw, specialEPIndex int) (host, hostIf, node, nodeIf string) {
 	// the index of the node is the specialEndpointIndex +1  modulo 2
 	nodeindex := (specialEPIndex + 1) % 2
@@ -362,7 +426,7 @@ const (
 	LinkEndpointTypeHost   = "host"
 )
 
-// SetNameMACAndUpInterface is a helper function that will bind interface name and Mac
+// SetNameMACAndUpInterface is a helper function that will bind interface name, Mac and ip addresses
 // and return a function that can run in the netns.Do() call for execution in a network namespace.
 func SetNameMACAndUpInterface(l netlink.Link, endpt Endpoint) func(ns.NetNS) error {
 	return func(_ ns.NetNS) error {
@@ -384,6 +448,13 @@ func SetNameMACAndUpInterface(l netlink.Link, endpt Endpoint) func(ns.NetNS) err
 			}
 		}
 
+		// the alias tracks the name of the interface used by the network OS
+		if a := endpt.GetIfaceAlias(); a != "" {
+			if err := netlink.LinkSetAlias(l, a); err != nil {
+				return fmt.Errorf("failed to set alias %q of %q: %v", a, endpt.GetIfaceName(), err)
+			}
+		}
+
 		// lets set the MAC address if provided
 		if len(endpt.GetMac()) == 6 {
 			err := netlink.LinkSetHardwareAddr(l, endpt.GetMac())
@@ -392,6 +463,22 @@ func SetNameMACAndUpInterface(l netlink.Link, endpt Endpoint) func(ns.NetNS) err
 			}
 		}
 
+		// assign the ip addresses if provided
+		for _, p := range []netip.Prefix{endpt.GetIPv4(), endpt.GetIPv6()} {
+			if !p.IsValid() {
+				continue
+			}
+
+			addr, err := netlink.ParseAddr(p.String())
+			if err != nil {
+				return err
+			}
+
+			if err := netlink.AddrAdd(l, addr); err != nil {
+				return fmt.Errorf("failed to add address %s to %q: %v", p, endpt.GetIfaceName(), err)
+			}
+		}
+
 		// bring the given link up
 		if err := netlink.LinkSetUp(l); err != nil {
 			return fmt.Errorf("failed to set %q up: %v",
@@ -411,6 +498,9 @@ type ResolveParams struct {
 	// list of node shortnames that user
 	// passed as a node filter
 	NodesFilter []string
+	// list of node shortnames that are disabled in the topology,
+	// links touching these nodes are disabled as well
+	DisabledNodes []string
 	// for the tools command we need to overwrite the
 	// veth interface name on the host side. So this can
 	// be set and will thereby overwrite the general interface
@@ -446,3 +536,28 @@ func isInFilter(params *ResolveParams, endpoints []*EndpointRaw) bool {
 
 	return true
 }
+
+// isDisabled returns true if the link is disabled by a user
+// or if any of its endpoints belongs to a disabled node.
+// Links that touch disabled nodes are disabled automatically.
+func isDisabled(params *ResolveParams, lc *LinkCommonParams, endpoints []*EndpointRaw) bool {
+	eps := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		eps = append(eps, e.Node+":"+e.Iface)
+	}
+	linkName := strings.Join(eps, " <-> ")
+
+	if lc.Enabled != nil && !*lc.Enabled {
+		log.Debugf("link %s is disabled, skipping", linkName)
+		return true
+	}
+
+	for _, e := range endpoints {
+		if slices.Contains(params.DisabledNodes, e.Node) {
+			log.Infof("link %s is disabled since node %q is disabled", linkName, e.Node)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,347 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes/state"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// MaxBondMembers is the maximum number of the member veth links of a bond link.
+	MaxBondMembers = 16
+	// DefaultBondMode is the mode of the bond devices when the mode is not set.
+	DefaultBondMode = "802.3ad"
+)
+
+// trailingNumberRe matches the number ending the interface name, e.g. 1 of e1-1.
+var trailingNumberRe = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// LinkBondRaw is the raw (string) representation of a bond link as defined in the topology file.
+// A bond link is a set of the parallel veth links between two nodes,
+// the member interfaces are bundled by the NOS itself or, for the sides with the bond name set,
+// by the bond device created in the namespace of the node.
+type LinkBondRaw struct {
+	LinkCommonParams `yaml:",inline"`
+	// Members is the number of the member links, the member interfaces of the endpoints
+	// are numbered starting from the endpoint interface.
+	Members int `yaml:"members,omitempty"`
+	// Mode is the mode of the bond devices, e.g. 802.3ad or active-backup.
+	Mode      string             `yaml:"mode,omitempty"`
+	Endpoints []*BondEndpointRaw `yaml:"endpoints"`
+}
+
+// BondEndpointRaw is the raw representation of a bond link endpoint.
+type BondEndpointRaw struct {
+	Node string `yaml:"node"`
+	// Iface is the first member interface, the interfaces of the next members
+	// are named by incrementing its trailing number, e.g. e1-1, e1-2.
+	Iface string `yaml:"interface,omitempty"`
+	// Ifaces are the member interfaces listed explicitly.
+	Ifaces []string `yaml:"interfaces,omitempty"`
+	// Bond is the name of the bond device created with the member interfaces enslaved,
+	// it is set for the linux and host endpoints, the NOS endpoints bundle the members themselves.
+	Bond string `yaml:"bond,omitempty"`
+}
+
+func (*LinkBondRaw) GetType() LinkType {
+	return LinkTypeBond
+}
+
+// memberIfaces returns the member interfaces of the endpoint.
+func (e *BondEndpointRaw) memberIfaces(members int) ([]string, error) {
+	switch {
+	case len(e.Ifaces) > 0 && e.Iface != "":
+		return nil, fmt.Errorf("endpoint %s: only one of interface and interfaces can be set", e.Node)
+
+	case len(e.Ifaces) > 0:
+		if members != 0 && members != len(e.Ifaces) {
+			return nil, fmt.Errorf("endpoint %s: %d interfaces are listed for %d members", e.Node, len(e.Ifaces), members)
+		}
+
+		return e.Ifaces, nil
+
+	case e.Iface != "":
+		if members == 0 {
+			return nil, fmt.Errorf("endpoint %s: the number of members must be set when the interfaces are not listed", e.Node)
+		}
+
+		m := trailingNumberRe.FindStringSubmatch(e.Iface)
+		if m == nil {
+			return nil, fmt.Errorf("endpoint %s: interface %q doesn't end with a number to number the members from", e.Node, e.Iface)
+		}
+
+		first, err := strconv.Atoi(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", e.Node, err)
+		}
+
+		ifaces := make([]string, 0, members)
+		for i := 0; i < members; i++ {
+			ifaces = append(ifaces, m[1]+strconv.Itoa(first+i))
+		}
+
+		return ifaces, nil
+
+	default:
+		return nil, fmt.Errorf("endpoint %s: interface or interfaces must be set", e.Node)
+	}
+}
+
+// Resolve resolves the raw bond link definition into a LinkBond
+// with a veth link per member.
+func (r *LinkBondRaw) Resolve(params *ResolveParams) (Link, error) {
+	if len(r.Endpoints) != 2 {
+		return nil, fmt.Errorf("bond link must have exactly 2 endpoints, got %d", len(r.Endpoints))
+	}
+
+	if r.Members < 0 || r.Members > MaxBondMembers {
+		return nil, fmt.Errorf("bond link members must be between 1 and %d, got %d", MaxBondMembers, r.Members)
+	}
+
+	mode := r.Mode
+	if mode == "" {
+		mode = DefaultBondMode
+	}
+
+	if netlink.StringToBondMode(mode) == netlink.BOND_MODE_UNKNOWN {
+		return nil, fmt.Errorf("unknown bond mode %q", mode)
+	}
+
+	memberIfaces := make([][]string, 0, len(r.Endpoints))
+	nodeEps := make([]*EndpointRaw, 0, len(r.Endpoints))
+
+	for _, e := range r.Endpoints {
+		ifaces, err := e.memberIfaces(r.Members)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ifaces) > MaxBondMembers {
+			return nil, fmt.Errorf("endpoint %s: bond link members must be between 1 and %d, got %d",
+				e.Node, MaxBondMembers, len(ifaces))
+		}
+
+		if e.Bond != "" && len(e.Bond) > 15 {
+			return nil, fmt.Errorf("endpoint %s: bond name %q is longer than 15 characters", e.Node, e.Bond)
+		}
+
+		memberIfaces = append(memberIfaces, ifaces)
+		nodeEps = append(nodeEps, &EndpointRaw{Node: e.Node, Iface: e.Bond})
+	}
+
+	if len(memberIfaces[0]) != len(memberIfaces[1]) {
+		return nil, fmt.Errorf("bond link endpoints have a different number of interfaces: %d and %d",
+			len(memberIfaces[0]), len(memberIfaces[1]))
+	}
+
+	if !isInFilter(params, nodeEps) || isDisabled(params, &r.LinkCommonParams, nodeEps) {
+		return nil, nil
+	}
+
+	l := NewLinkBond()
+	l.LinkCommonParams = r.LinkCommonParams
+	l.Mode = mode
+
+	// set default link mtu if MTU is unset
+	if l.MTU == 0 {
+		l.MTU = DefaultLinkMTU
+	}
+
+	for i := range memberIfaces[0] {
+		m := NewLinkVEth()
+		m.LinkCommonParams = l.LinkCommonParams
+
+		for j, e := range r.Endpoints {
+			ep, err := NewEndpointRaw(e.Node, memberIfaces[j][i], "").Resolve(params, l)
+			if err != nil {
+				return nil, err
+			}
+
+			m.Endpoints = append(m.Endpoints, ep)
+		}
+
+		l.Members = append(l.Members, m)
+	}
+
+	for i, e := range r.Endpoints {
+		node := params.Nodes[e.Node]
+
+		if e.Bond != "" {
+			l.Bonds = append(l.Bonds, &BondDevice{
+				Node:    node,
+				Name:    e.Bond,
+				Members: memberIfaces[i],
+			})
+		}
+
+		// the members are deployed by the bond link
+		node.AddLink(l)
+	}
+
+	return l, nil
+}
+
+// BondDevice is a bond device created in the namespace of a node with the member interfaces enslaved.
+type BondDevice struct {
+	Node    Node
+	Name    string
+	Members []string
+}
+
+// LinkBond is a bond link, a set of the parallel veth links between two nodes
+// with the bond devices created on the sides with the bond name set.
+type LinkBond struct {
+	LinkCommonParams
+	Mode    string
+	Members []*LinkVEth
+	Bonds   []*BondDevice
+
+	deployMutex sync.Mutex
+}
+
+func NewLinkBond() *LinkBond {
+	return &LinkBond{}
+}
+
+func (*LinkBond) GetType() LinkType {
+	return LinkTypeBond
+}
+
+// GetEndpoints returns the endpoints of all member links.
+func (l *LinkBond) GetEndpoints() []Endpoint {
+	eps := make([]Endpoint, 0, 2*len(l.Members))
+	for _, m := range l.Members {
+		eps = append(eps, m.GetEndpoints()...)
+	}
+
+	return eps
+}
+
+// MemberOf returns the member link of the endpoint ep, nil if ep is not an endpoint of the bond link.
+func (l *LinkBond) MemberOf(ep Endpoint) *LinkVEth {
+	for _, m := range l.Members {
+		for _, mep := range m.Endpoints {
+			if mep == ep {
+				return m
+			}
+		}
+	}
+
+	return nil
+}
+
+// Deploy deploys the member links and creates the bond devices once the nodes of both sides are deployed.
+func (l *LinkBond) Deploy(ctx context.Context) error {
+	l.deployMutex.Lock()
+	defer l.deployMutex.Unlock()
+	if l.DeploymentState == LinkDeploymentStateDeployed {
+		return nil
+	}
+
+	for _, ep := range l.GetEndpoints() {
+		if ep.GetNode().GetState() != state.Deployed {
+			return nil
+		}
+	}
+
+	for _, m := range l.Members {
+		if err := m.Deploy(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range l.Bonds {
+		log.Infof("Creating bond %s:%s with members %v", b.Node.GetShortName(), b.Name, b.Members)
+
+		if err := b.Node.ExecFunction(b.create(l.Mode, l.MTU)); err != nil {
+			return fmt.Errorf("failed to create bond %s:%s: %w", b.Node.GetShortName(), b.Name, err)
+		}
+	}
+
+	l.DeploymentState = LinkDeploymentStateDeployed
+
+	return nil
+}
+
+// create returns the function creating the bond device with the member interfaces enslaved
+// run in the namespace of the node.
+func (b *BondDevice) create(mode string, mtu int) func(ns.NetNS) error {
+	return func(_ ns.NetNS) error {
+		bond := netlink.NewLinkBond(netlink.LinkAttrs{Name: b.Name, MTU: mtu})
+		bond.Mode = netlink.StringToBondMode(mode)
+
+		if err := netlink.LinkAdd(bond); err != nil {
+			return err
+		}
+
+		for _, name := range b.Members {
+			m, err := utils.LinkByNameOrAlias(name)
+			if err != nil {
+				return err
+			}
+
+			// the interfaces must be down to be enslaved
+			if err := netlink.LinkSetDown(m); err != nil {
+				return err
+			}
+
+			if err := netlink.LinkSetBondSlave(m, bond); err != nil {
+				return fmt.Errorf("failed to enslave %q: %w", name, err)
+			}
+
+			if err := netlink.LinkSetUp(m); err != nil {
+				return err
+			}
+		}
+
+		return netlink.LinkSetUp(bond)
+	}
+}
+
+// remove returns the function removing the bond device run in the namespace of the node.
+func (b *BondDevice) remove(_ ns.NetNS) error {
+	bond, err := netlink.LinkByName(b.Name)
+	if _, notfound := err.(netlink.LinkNotFoundError); notfound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Removing bond %q from namespace %q", b.Name, b.Node.GetShortName())
+
+	return netlink.LinkDel(bond)
+}
+
+// Remove removes the bond devices and the member links.
+func (l *LinkBond) Remove(ctx context.Context) error {
+	l.deployMutex.Lock()
+	defer l.deployMutex.Unlock()
+	if l.DeploymentState == LinkDeploymentStateRemoved {
+		return nil
+	}
+
+	for _, b := range l.Bonds {
+		if err := b.Node.ExecFunction(b.remove); err != nil {
+			log.Debug(err)
+		}
+	}
+
+	for _, m := range l.Members {
+		if err := m.Remove(ctx); err != nil {
+			return err
+		}
+	}
+
+	l.DeploymentState = LinkDeploymentStateRemoved
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package links
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/internal/netnstest"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestLinkBondRaw_Resolve(t *testing.T) {
+	tests := map[string]struct {
+		raw *LinkBondRaw
+		// want are the member links as the node:interface pairs
+		want      []string
+		wantBonds []string
+		wantErr   string
+	}{
+		"numbered members": {
+			raw: &LinkBondRaw{
+				Members: 3,
+				Endpoints: []*BondEndpointRaw{
+					{Node: "srl1", Iface: "e1-1"},
+					{Node: "linux1", Iface: "eth1", Bond: "bond0"},
+				},
+			},
+			want:      []string{"srl1:e1-1 linux1:eth1", "srl1:e1-2 linux1:eth2", "srl1:e1-3 linux1:eth3"},
+			wantBonds: []string{"linux1:bond0"},
+		},
+		"listed members": {
+			raw: &LinkBondRaw{
+				Endpoints: []*BondEndpointRaw{
+					{Node: "srl1", Ifaces: []string{"e1-1", "e1-5"}},
+					{Node: "linux1", Ifaces: []string{"eth1", "eth2"}},
+				},
+			},
+			want: []string{"srl1:e1-1 linux1:eth1", "srl1:e1-5 linux1:eth2"},
+		},
+		"too many members": {
+			raw: &LinkBondRaw{
+				Members: 17,
+				Endpoints: []*BondEndpointRaw{
+					{Node: "srl1", Iface: "e1-1"},
+					{Node: "linux1", Iface: "eth1"},
+				},
+			},
+			wantErr: "bond link members must be between 1 and 16, got 17",
+		},
+		"unknown mode": {
+			raw: &LinkBondRaw{
+				Members: 2,
+				Mode:    "lacp",
+				Endpoints: []*BondEndpointRaw{
+					{Node: "srl1", Iface: "e1-1"},
+					{Node: "linux1", Iface: "eth1"},
+				},
+			},
+			wantErr: `unknown bond mode "lacp"`,
+		},
+		"members count mismatch": {
+			raw: &LinkBondRaw{
+				Members: 3,
+				Endpoints: []*BondEndpointRaw{
+					{Node: "srl1", Iface: "e1-1"},
+					{Node: "linux1", Ifaces: []string{"eth1", "eth2"}},
+				},
+			},
+			wantErr: "endpoint linux1: 2 interfaces are listed for 3 members",
+		},
+		"no members count": {
+			raw: &LinkBondRaw{
+				Endpoints: []*BondEndpointRaw{
+					{Node: "srl1", Iface: "e1-1"},
+					{Node: "linux1", Iface: "eth1"},
+				},
+			},
+			wantErr: "endpoint srl1: the number of members must be set when the interfaces are not listed",
+		},
+		"interface without number": {
+			raw: &LinkBondRaw{
+				Members: 2,
+				Endpoints: []*BondEndpointRaw{
+					{Node: "srl1", Iface: "mgmt"},
+					{Node: "linux1", Iface: "eth1"},
+				},
+			},
+			wantErr: `endpoint srl1: interface "mgmt" doesn't end with a number`,
+		},
+		"one endpoint": {
+			raw: &LinkBondRaw{
+				Members:   2,
+				Endpoints: []*BondEndpointRaw{{Node: "srl1", Iface: "e1-1"}},
+			},
+			wantErr: "bond link must have exactly 2 endpoints, got 1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := &ResolveParams{Nodes: map[string]Node{
+				"srl1":   newFakeNode("srl1"),
+				"linux1": newFakeNode("linux1"),
+			}}
+
+			l, err := tt.raw.Resolve(params)
+
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			bl := l.(*LinkBond)
+
+			var got []string
+			for _, m := range bl.Members {
+				got = append(got, m.Endpoints[0].String()+" "+m.Endpoints[1].String())
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("members diff (-want +got):\n%s", d)
+			}
+
+			var gotBonds []string
+			for _, b := range bl.Bonds {
+				gotBonds = append(gotBonds, b.Node.GetShortName()+":"+b.Name)
+			}
+
+			if d := cmp.Diff(tt.wantBonds, gotBonds); d != "" {
+				t.Errorf("bonds diff (-want +got):\n%s", d)
+			}
+
+			if bl.Mode != DefaultBondMode {
+				t.Errorf("got mode %q, want %q", bl.Mode, DefaultBondMode)
+			}
+
+			if n := len(params.Nodes["srl1"].GetEndpoints()); n != len(tt.want) {
+				t.Errorf("got %d endpoints of srl1, want %d", n, len(tt.want))
+			}
+		})
+	}
+}
+
+// netnsNode is a deployed node which interfaces are in the network namespace nspath.
+type netnsNode struct {
+	GenericLinkNode
+}
+
+func (*netnsNode) GetLinkEndpointType() LinkEndpointType {
+	return LinkEndpointTypeVeth
+}
+
+func TestLinkBondDeployRemove(t *testing.T) {
+	netnstest.Run(t, func() {
+		nsA := netnstest.New(t)
+		nsB := netnstest.New(t)
+
+		params := &ResolveParams{Nodes: map[string]Node{
+			"srl1":   &netnsNode{GenericLinkNode{shortname: "srl1", nspath: nsA.Path()}},
+			"linux1": &netnsNode{GenericLinkNode{shortname: "linux1", nspath: nsB.Path()}},
+		}}
+
+		raw := &LinkBondRaw{
+			Members: 2,
+			Mode:    "active-backup",
+			Endpoints: []*BondEndpointRaw{
+				{Node: "srl1", Iface: "e1-1"},
+				{Node: "linux1", Iface: "eth1", Bond: "bond0"},
+			},
+		}
+
+		l, err := raw.Resolve(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+
+		if err := l.Deploy(ctx); err != nil {
+			if errors.Is(err, unix.EOPNOTSUPP) {
+				t.Skipf("bond devices are not supported: %v", err)
+			}
+			t.Fatal(err)
+		}
+
+		// the members of the linux side are enslaved to the bond
+		err = nsB.Do(func(_ ns.NetNS) error {
+			bond, err := netlink.LinkByName("bond0")
+			if err != nil {
+				return err
+			}
+
+			if m := bond.(*netlink.Bond).Mode; m != netlink.BOND_MODE_ACTIVE_BACKUP {
+				t.Errorf("got bond mode %s, want active-backup", m)
+			}
+
+			for _, name := range []string{"eth1", "eth2"} {
+				m, err := netlink.LinkByName(name)
+				if err != nil {
+					return err
+				}
+
+				if m.Attrs().MasterIndex != bond.Attrs().Index {
+					t.Errorf("%s is not enslaved to bond0", name)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the NOS side only gets the member interfaces
+		err = nsA.Do(func(_ ns.NetNS) error {
+			for _, name := range []string{"e1-1", "e1-2"} {
+				m, err := netlink.LinkByName(name)
+				if err != nil {
+					return err
+				}
+
+				if m.Attrs().MasterIndex != 0 {
+					t.Errorf("%s is enslaved on the NOS side", name)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := l.Remove(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		for n, h := range map[string]ns.NetNS{"srl1": nsA, "linux1": nsB} {
+			err := h.Do(func(_ ns.NetNS) error {
+				ls, err := netlink.LinkList()
+				if err != nil {
+					return err
+				}
+
+				for _, l := range ls {
+					if l.Attrs().Name != "lo" {
+						t.Errorf("interface %s of %s is not removed", l.Attrs().Name, n)
+					}
+				}
+
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
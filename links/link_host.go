@@ -57,6 +57,10 @@ func (r *LinkHostRaw) Resolve(params *ResolveParams) (Link, error) {
 		return nil, nil
 	}
 
+	if isDisabled(params, &r.LinkCommonParams, []*EndpointRaw{r.Endpoint}) {
+		return nil, nil
+	}
+
 	link := &LinkVEth{
 		LinkCommonParams: r.LinkCommonParams,
 	}
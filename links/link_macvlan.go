@@ -2,6 +2,7 @@ package links
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -64,6 +65,10 @@ func (r *LinkMacVlanRaw) Resolve(params *ResolveParams) (Link, error) {
 		return nil, nil
 	}
 
+	if isDisabled(params, &r.LinkCommonParams, []*EndpointRaw{r.Endpoint}) {
+		return nil, nil
+	}
+
 	// create the MacVlan Link
 	link := &LinkMacVlan{
 		LinkCommonParams: r.LinkCommonParams,
@@ -161,19 +166,70 @@ func (l *LinkMacVlan) Deploy(ctx context.Context) error {
 	return err
 }
 
+// Remove deletes the macvlan interface of the node. The interface which node namespace was already
+// torn down by the runtime is gone with the namespace, so it is skipped.
+// In the passthru mode the parent interface is verified to be released by the link.
 func (l *LinkMacVlan) Remove(_ context.Context) error {
 	// check Deployment state, if the Link was already
 	// removed via e.g. the peer node
 	if l.DeploymentState == LinkDeploymentStateRemoved {
 		return nil
 	}
+
+	var errs []error
+
 	// trigger link removal via the NodeEndpoint
 	err := l.NodeEndpoint.Remove()
-	if err != nil {
-		log.Debug(err)
+
+	var rmErr *interfaceRemovalError
+	switch {
+	case err == nil:
+	case errors.As(err, &rmErr):
+		errs = append(errs, err)
+	default:
+		// the namespace of the node can't be entered, the macvlan interface is gone with it
+		log.Debugf("skipping removal of endpoint %s: %v", l.NodeEndpoint, err)
 	}
+
+	// a deployment that failed before the interface was moved to the node namespace
+	// leaves it with the random name in the root namespace
+	if err := removeRootNSInterface(l.NodeEndpoint.GetRandIfaceName()); err != nil {
+		errs = append(errs, err)
+	}
+
+	if l.Mode == MacVlanModePassthru {
+		if err := l.verifyParentReleased(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// adjust the Deployment status to reflect the removal
 	l.DeploymentState = LinkDeploymentStateRemoved
+
+	return errors.Join(errs...)
+}
+
+// verifyParentReleased checks that no macvlan interface in the root namespace uses the parent interface,
+// a passthru parent can't be used by another macvlan link until its macvlan interface is deleted.
+func (l *LinkMacVlan) verifyParentReleased() error {
+	parent, err := utils.LinkByNameOrAlias(l.HostEndpoint.GetIfaceName())
+	if err != nil {
+		// the parent interface is gone, nothing holds it
+		return nil
+	}
+
+	ls, err := netlink.LinkList()
+	if err != nil {
+		return err
+	}
+
+	for _, ln := range ls {
+		if ln.Type() == "macvlan" && ln.Attrs().ParentIndex == parent.Attrs().Index {
+			return fmt.Errorf("parent interface %s is still used by macvlan interface %s",
+				l.HostEndpoint.GetIfaceName(), ln.Attrs().Name)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,126 @@
+package links
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/srl-labs/containerlab/internal/netnstest"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestLinkMacVlanRemove(t *testing.T) {
+	tests := map[string]struct {
+		mode string
+		// nsGone makes the node namespace unavailable before the link is removed
+		nsGone bool
+	}{
+		"bridge":              {mode: MacVlanModeBridge},
+		"passthru":            {mode: MacVlanModePassthru},
+		"node namespace gone": {mode: MacVlanModeBridge, nsGone: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			netnstest.Run(t, func() {
+				nsA := netnstest.New(t)
+
+				// the parent interface of the macvlan link
+				parent := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "parent0"}, PeerName: "parent1"}
+				if err := netlink.LinkAdd(parent); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := netlink.LinkSetUp(parent); err != nil {
+					t.Fatal(err)
+				}
+
+				node := &netnsNode{GenericLinkNode{shortname: "srl1", nspath: nsA.Path()}}
+				params := &ResolveParams{Nodes: map[string]Node{"srl1": node}}
+
+				raw := &LinkMacVlanRaw{
+					HostInterface: "parent0",
+					Endpoint:      NewEndpointRaw("srl1", "e1-1", ""),
+					Mode:          tt.mode,
+				}
+
+				rl, err := raw.Resolve(params)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				l := rl.(*LinkMacVlan)
+
+				ctx := context.Background()
+
+				if err := l.Deploy(ctx); err != nil {
+					if errors.Is(err, unix.EOPNOTSUPP) {
+						t.Skipf("macvlan devices are not supported: %v", err)
+					}
+					t.Fatal(err)
+				}
+
+				if d := nsInterfaces(t, nsA); len(d) != 1 || d[0] != "e1-1" {
+					t.Fatalf("got interfaces %v of srl1, want [e1-1]", d)
+				}
+
+				if tt.nsGone {
+					node.nspath = "/proc/0/ns/net"
+				}
+
+				if err := l.Remove(ctx); err != nil {
+					t.Fatalf("remove failed: %v", err)
+				}
+
+				if !tt.nsGone {
+					if ifs := nsInterfaces(t, nsA); len(ifs) != 0 {
+						t.Errorf("interfaces %v of srl1 are not removed", ifs)
+					}
+				}
+
+				if l.DeploymentState != LinkDeploymentStateRemoved {
+					t.Errorf("got deployment state %v, want removed", l.DeploymentState)
+				}
+			})
+		})
+	}
+}
+
+func TestLinkMacVlanVerifyParentReleased(t *testing.T) {
+	netnstest.Run(t, func() {
+		parent := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "parent0"}, PeerName: "parent1"}
+		if err := netlink.LinkAdd(parent); err != nil {
+			t.Fatal(err)
+		}
+
+		l := &LinkMacVlan{Mode: MacVlanModePassthru}
+		l.HostEndpoint = &EndpointMacVlan{
+			EndpointGeneric: *NewEndpointGeneric(&netnsNode{}, "parent0", l),
+		}
+
+		if err := l.verifyParentReleased(); err != nil {
+			t.Fatalf("unused parent is reported as used: %v", err)
+		}
+
+		pl, err := netlink.LinkByName("parent0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mv := &netlink.Macvlan{
+			LinkAttrs: netlink.LinkAttrs{Name: "mv0", ParentIndex: pl.Attrs().Index},
+			Mode:      netlink.MACVLAN_MODE_PASSTHRU,
+		}
+		if err := netlink.LinkAdd(mv); err != nil {
+			if errors.Is(err, unix.EOPNOTSUPP) {
+				t.Skipf("macvlan devices are not supported: %v", err)
+			}
+			t.Fatal(err)
+		}
+
+		if err := l.verifyParentReleased(); err == nil {
+			t.Error("parent used by the macvlan interface is reported as released")
+		}
+	})
+}
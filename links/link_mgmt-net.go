@@ -38,6 +38,10 @@ func (r *LinkMgmtNetRaw) Resolve(params *ResolveParams) (Link, error) {
 		return nil, nil
 	}
 
+	if isDisabled(params, &r.LinkCommonParams, []*EndpointRaw{r.Endpoint}) {
+		return nil, nil
+	}
+
 	// create the LinkMgmtNet struct
 	link := &LinkVEth{
 		LinkCommonParams: r.LinkCommonParams,
@@ -2,6 +2,7 @@ package links
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -45,6 +46,10 @@ func (r *LinkVEthRaw) Resolve(params *ResolveParams) (Link, error) {
 		return nil, nil
 	}
 
+	if isDisabled(params, &r.LinkCommonParams, r.Endpoints) {
+		return nil, nil
+	}
+
 	// create LinkVEth struct
 	l := NewLinkVEth()
 	l.LinkCommonParams = r.LinkCommonParams
@@ -172,19 +177,57 @@ func (l *LinkVEth) Deploy(ctx context.Context) error {
 	return nil
 }
 
+// Remove deletes the veth pair of the link. The endpoint interfaces that are already gone,
+// because the other side removed the pair or the namespace of the node was deleted with its container,
+// are skipped. The link is marked as removed, so that it can be deployed again.
 func (l *LinkVEth) Remove(_ context.Context) error {
 	l.deployMutex.Lock()
 	defer l.deployMutex.Unlock()
 	if l.DeploymentState == LinkDeploymentStateRemoved {
 		return nil
 	}
+
+	var errs []error
 	for _, ep := range l.GetEndpoints() {
 		err := ep.Remove()
-		if err != nil {
-			log.Debug(err)
+
+		var rmErr *interfaceRemovalError
+		switch {
+		case err == nil:
+		case errors.As(err, &rmErr):
+			errs = append(errs, err)
+		default:
+			// the namespace of the node can't be entered, its interfaces are gone with it
+			log.Debugf("skipping removal of endpoint %s: %v", ep, err)
+		}
+	}
+
+	// a deployment that failed before the interfaces were moved to the node namespaces
+	// leaves them with the random names in the root namespace
+	for _, ep := range l.GetEndpoints() {
+		if err := removeRootNSInterface(ep.GetRandIfaceName()); err != nil {
+			errs = append(errs, err)
 		}
 	}
+
 	l.DeploymentState = LinkDeploymentStateRemoved
+
+	return errors.Join(errs...)
+}
+
+// removeRootNSInterface deletes the interface of the given name from the root namespace if it exists.
+func removeRootNSInterface(name string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		// interface is not present, all good
+		return nil
+	}
+
+	log.Debugf("Removing interface %q left in the root namespace", name)
+	if err := netlink.LinkDel(link); err != nil {
+		return fmt.Errorf("failed to remove interface %q from the root namespace: %w", name, err)
+	}
+
 	return nil
 }
 
@@ -6,7 +6,9 @@ import (
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/internal/netnstest"
 	"github.com/srl-labs/containerlab/nodes/state"
+	"github.com/srl-labs/containerlab/utils"
 	"github.com/vishvananda/netlink"
 )
 
@@ -165,6 +167,58 @@ func TestLinkVEthRaw_Resolve(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "disabled link",
+			fields: fields{
+				LinkCommonParams: LinkCommonParams{
+					Enabled: utils.BoolPointer(false),
+				},
+				Endpoints: []*EndpointRaw{
+					{
+						Node:  "node1",
+						Iface: "eth1",
+					},
+					{
+						Node:  "node2",
+						Iface: "eth2",
+					},
+				},
+			},
+			args: args{
+				params: &ResolveParams{
+					Nodes: map[string]Node{
+						"node1": fn1,
+						"node2": fn2,
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "link to a disabled node",
+			fields: fields{
+				Endpoints: []*EndpointRaw{
+					{
+						Node:  "node1",
+						Iface: "eth1",
+					},
+					{
+						Node:  "node3",
+						Iface: "eth2",
+					},
+				},
+			},
+			args: args{
+				params: &ResolveParams{
+					Nodes: map[string]Node{
+						"node1": fn1,
+						"node2": fn2,
+					},
+					DisabledNodes: []string{"node3"},
+				},
+			},
+			want: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -178,6 +232,12 @@ func TestLinkVEthRaw_Resolve(t *testing.T) {
 				t.Errorf("LinkVEthRaw.Resolve() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("LinkVEthRaw.Resolve() = %v, want disabled link to be skipped", got)
+				}
+				return
+			}
 			l := got.(*LinkVEth)
 			if d := cmp.Diff(l.LinkCommonParams, tt.want.LinkCommonParams); d != "" {
 				t.Errorf("LinkVEthRaw.Resolve() LinkCommonParams diff = %s", d)
@@ -196,6 +256,167 @@ func TestLinkVEthRaw_Resolve(t *testing.T) {
 	}
 }
 
+// nsInterfaces returns the names of the interfaces of the namespace except the loopback one.
+func nsInterfaces(t *testing.T, h ns.NetNS) []string {
+	t.Helper()
+
+	var names []string
+	err := h.Do(func(_ ns.NetNS) error {
+		ls, err := netlink.LinkList()
+		if err != nil {
+			return err
+		}
+
+		for _, l := range ls {
+			if l.Attrs().Name != "lo" {
+				names = append(names, l.Attrs().Name)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return names
+}
+
+func TestLinkVEthRemove(t *testing.T) {
+	tests := map[string]struct {
+		// prepare is run after the link is deployed, before it is removed
+		prepare func(t *testing.T, l *LinkVEth, nsB ns.NetNS)
+	}{
+		"both present": {},
+		"pair removed by the other side": {
+			prepare: func(t *testing.T, _ *LinkVEth, nsB ns.NetNS) {
+				err := nsB.Do(func(_ ns.NetNS) error {
+					eth1, err := netlink.LinkByName("eth1")
+					if err != nil {
+						return err
+					}
+					return netlink.LinkDel(eth1)
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		"node namespace gone": {
+			prepare: func(_ *testing.T, l *LinkVEth, _ ns.NetNS) {
+				l.Endpoints[1].GetNode().(*netnsNode).nspath = "/proc/0/ns/net"
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			netnstest.Run(t, func() {
+				nsA := netnstest.New(t)
+				nsB := netnstest.New(t)
+
+				params := &ResolveParams{Nodes: map[string]Node{
+					"srl1":   &netnsNode{GenericLinkNode{shortname: "srl1", nspath: nsA.Path()}},
+					"linux1": &netnsNode{GenericLinkNode{shortname: "linux1", nspath: nsB.Path()}},
+				}}
+
+				raw := &LinkVEthRaw{Endpoints: []*EndpointRaw{
+					NewEndpointRaw("srl1", "e1-1", ""),
+					NewEndpointRaw("linux1", "eth1", ""),
+				}}
+
+				rl, err := raw.Resolve(params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				l := rl.(*LinkVEth)
+
+				ctx := context.Background()
+
+				if err := l.Deploy(ctx); err != nil {
+					t.Fatal(err)
+				}
+
+				if tt.prepare != nil {
+					tt.prepare(t, l, nsB)
+				}
+
+				if err := l.Remove(ctx); err != nil {
+					t.Fatalf("remove failed: %v", err)
+				}
+
+				for n, h := range map[string]ns.NetNS{"srl1": nsA, "linux1": nsB} {
+					if ifs := nsInterfaces(t, h); len(ifs) != 0 {
+						t.Errorf("interfaces %v of %s are not removed", ifs, n)
+					}
+				}
+
+				if l.DeploymentState != LinkDeploymentStateRemoved {
+					t.Errorf("got deployment state %v, want removed", l.DeploymentState)
+				}
+			})
+		})
+	}
+}
+
+func TestLinkVEthRedeploy(t *testing.T) {
+	netnstest.Run(t, func() {
+		nsA := netnstest.New(t)
+		nsB := netnstest.New(t)
+
+		params := &ResolveParams{Nodes: map[string]Node{
+			"srl1":   &netnsNode{GenericLinkNode{shortname: "srl1", nspath: nsA.Path()}},
+			"linux1": &netnsNode{GenericLinkNode{shortname: "linux1", nspath: nsB.Path()}},
+		}}
+
+		raw := &LinkVEthRaw{Endpoints: []*EndpointRaw{
+			NewEndpointRaw("srl1", "e1-1", ""),
+			NewEndpointRaw("linux1", "eth1", ""),
+		}}
+
+		l, err := raw.Resolve(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+
+		// the removed link is deployed again, as it happens when a lab is reconfigured
+		for i := 0; i < 2; i++ {
+			if err := l.Deploy(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff([]string{"eth1"}, nsInterfaces(t, nsB)); d != "" {
+				t.Fatalf("interfaces of linux1 diff (-want +got):\n%s", d)
+			}
+
+			if err := l.Remove(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the interfaces left in the root namespace by a failed deployment are removed
+		veth := &netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: l.GetEndpoints()[0].GetRandIfaceName()},
+			PeerName:  l.GetEndpoints()[1].GetRandIfaceName(),
+		}
+		if err := netlink.LinkAdd(veth); err != nil {
+			t.Fatal(err)
+		}
+
+		l.(*LinkVEth).DeploymentState = LinkDeploymentStateNotDeployed
+
+		if err := l.Remove(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := netlink.LinkByName(veth.Name); err == nil {
+			t.Errorf("interface %s is left in the root namespace", veth.Name)
+		}
+	})
+}
+
 // fakeNode is a fake implementation of Node for testing.
 type fakeNode struct {
 	Name      string
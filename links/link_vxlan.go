@@ -31,6 +31,10 @@ type LinkVxlanRaw struct {
 }
 
 func (lr *LinkVxlanRaw) Resolve(params *ResolveParams) (Link, error) {
+	if isDisabled(params, &lr.LinkCommonParams, []*EndpointRaw{&lr.Endpoint}) {
+		return nil, nil
+	}
+
 	switch lr.LinkType {
 	case LinkTypeVxlan:
 		return lr.resolveVxlan(params, false)
@@ -165,9 +169,9 @@ func (lr *LinkVxlanRaw) resolveVxlan(params *ResolveParams, stitched bool) (*Lin
 		}
 	} else {
 		// if a MAC is set, parse and use it
-		hwaddr, err := net.ParseMAC(lr.Endpoint.MAC)
+		hwaddr, err := utils.ParseInterfaceMAC(lr.Endpoint.MAC)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("vxlan endpoint %s:%s: %w", lr.Endpoint.Node, lr.Endpoint.Iface, err)
 		}
 		link.remoteEndpoint.MAC = hwaddr
 	}
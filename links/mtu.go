@@ -0,0 +1,105 @@
+package links
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// MinMTU is the minimal MTU of an interface, the minimal IPv4 datagram size.
+	MinMTU = 68
+	// MaxMTU is the maximal MTU of an interface.
+	MaxMTU = 65535
+)
+
+// InterfaceMTU is the MTU of a network interface.
+type InterfaceMTU struct {
+	Interface string `json:"interface"`
+	MTU       int    `json:"mtu"`
+}
+
+// ValidateMTU checks that the mtu is in the range of the valid interface MTUs.
+func ValidateMTU(mtu int) error {
+	if mtu < MinMTU || mtu > MaxMTU {
+		return fmt.Errorf("MTU %d is out of the allowed range [%d, %d]", mtu, MinMTU, MaxMTU)
+	}
+
+	return nil
+}
+
+// GetInterfaceMTUs returns the MTUs of the interfaces in the network namespace of the node,
+// sorted by the interface name. When ifName is set, only the MTU of this interface is returned.
+func GetInterfaceMTUs(n Node, ifName string) ([]*InterfaceMTU, error) {
+	var mtus []*InterfaceMTU
+
+	err := n.ExecFunction(func(_ ns.NetNS) error {
+		if ifName != "" {
+			l, err := netlink.LinkByName(ifName)
+			if err != nil {
+				return fmt.Errorf("failed to find interface %q: %w", ifName, err)
+			}
+
+			mtus = append(mtus, &InterfaceMTU{Interface: ifName, MTU: l.Attrs().MTU})
+
+			return nil
+		}
+
+		links, err := netlink.LinkList()
+		if err != nil {
+			return err
+		}
+
+		for _, l := range links {
+			mtus = append(mtus, &InterfaceMTU{Interface: l.Attrs().Name, MTU: l.Attrs().MTU})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(mtus, func(i, j int) bool {
+		return mtus[i].Interface < mtus[j].Interface
+	})
+
+	return mtus, nil
+}
+
+// SetInterfaceMTU sets the MTU of the interface in the network namespace of the node
+// and returns the previous MTU of the interface.
+func SetInterfaceMTU(n Node, ifName string, mtu int) (int, error) {
+	if err := ValidateMTU(mtu); err != nil {
+		return 0, err
+	}
+
+	var prev int
+
+	err := n.ExecFunction(func(_ ns.NetNS) error {
+		l, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return fmt.Errorf("failed to find interface %q: %w", ifName, err)
+		}
+
+		prev = l.Attrs().MTU
+
+		if prev == mtu {
+			return nil
+		}
+
+		// the kernel rejects the values outside of the MTU range of the interface driver
+		if err := netlink.LinkSetMTU(l, mtu); err != nil {
+			return fmt.Errorf("failed to set MTU %d on interface %q: %w", mtu, ifName, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return prev, nil
+}
@@ -0,0 +1,56 @@
+package links
+
+import (
+	"testing"
+)
+
+func TestValidateMTU(t *testing.T) {
+	tests := map[int]bool{
+		MinMTU - 1: false,
+		MinMTU:     true,
+		1500:       true,
+		9500:       true,
+		MaxMTU:     true,
+		MaxMTU + 1: false,
+		-1:         false,
+	}
+
+	for mtu, valid := range tests {
+		if err := ValidateMTU(mtu); (err == nil) != valid {
+			t.Errorf("MTU %d: got error %v, want valid %v", mtu, err, valid)
+		}
+	}
+}
+
+func TestGetInterfaceMTUs(t *testing.T) {
+	mtus, err := GetInterfaceMTUs(currentNSNode{newFakeNode("n1")}, "lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mtus) != 1 || mtus[0].Interface != "lo" || mtus[0].MTU <= 0 {
+		t.Errorf("got MTUs %+v, want the MTU of the loopback interface", mtus)
+	}
+
+	all, err := GetInterfaceMTUs(currentNSNode{newFakeNode("n1")}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Interface > all[i].Interface {
+			t.Errorf("interfaces are not sorted: %s goes before %s", all[i-1].Interface, all[i].Interface)
+		}
+	}
+
+	if _, err := GetInterfaceMTUs(currentNSNode{newFakeNode("n1")}, "no-such-if0"); err == nil {
+		t.Error("expected an error for the missing interface")
+	}
+}
+
+func TestSetInterfaceMTUValidation(t *testing.T) {
+	// the invalid MTU is rejected before the node namespace is entered
+	if _, err := SetInterfaceMTU(newFakeNode("n1"), "eth1", MinMTU-1); err == nil {
+		t.Error("expected an error for the invalid MTU")
+	}
+}
@@ -0,0 +1,160 @@
+package links
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+)
+
+// VethEndpoint is one end of a veth pair found in the network namespace of a node.
+type VethEndpoint struct {
+	Node      string
+	Interface string
+	// Index is the interface index in the namespace of the node.
+	Index int
+	// PeerIndex is the interface index of the peer in the namespace of the peer.
+	PeerIndex int
+	// PeerNode is the node owning the namespace of the peer, empty when it is not known.
+	PeerNode string
+	// PeerOutside is set when the peer is in a namespace of none of the nodes, e.g. the host namespace.
+	PeerOutside bool
+}
+
+func (e *VethEndpoint) String() string {
+	return e.Node + ":" + e.Interface
+}
+
+// VethPair is a pair of the veth endpoints connecting two nodes.
+type VethPair struct {
+	A, B *VethEndpoint
+}
+
+// DiscoverVethEndpoints lists the veth interfaces in the network namespaces of the nodes.
+// The interface indexes are only unique within a namespace, so the node owning the peer of an interface
+// is found by the id the namespace of the node has in the namespace of the interface.
+// The management interface eth0 is skipped.
+func DiscoverVethEndpoints(namespaces map[string]ns.NetNS) ([]*VethEndpoint, error) {
+	nodes := make([]string, 0, len(namespaces))
+	for n := range namespaces {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	var endpoints []*VethEndpoint
+
+	for _, node := range nodes {
+		err := namespaces[node].Do(func(_ ns.NetNS) error {
+			// ids of the namespaces of the other nodes as seen from this namespace
+			nsIDs := map[int]string{}
+
+			for _, other := range nodes {
+				if other == node {
+					continue
+				}
+
+				id, err := netlink.GetNetNsIdByFd(int(namespaces[other].Fd()))
+				if err != nil || id < 0 {
+					continue
+				}
+
+				nsIDs[id] = other
+			}
+
+			// the peer namespaces can only be told apart when the ids of all the namespaces are known
+			allIDs := len(nsIDs) == len(nodes)-1
+
+			ls, err := netlink.LinkList()
+			if err != nil {
+				return err
+			}
+
+			for _, l := range ls {
+				attrs := l.Attrs()
+				if l.Type() != "veth" || attrs.Name == "eth0" {
+					continue
+				}
+
+				ep := &VethEndpoint{
+					Node:      node,
+					Interface: attrs.Name,
+					Index:     attrs.Index,
+					PeerIndex: attrs.ParentIndex,
+				}
+
+				peer, ok := nsIDs[attrs.NetNsID]
+
+				switch {
+				// the peer in the same namespace has no namespace id
+				case attrs.NetNsID < 0:
+					ep.PeerNode = node
+				case ok:
+					ep.PeerNode = peer
+				case allIDs:
+					ep.PeerOutside = true
+				}
+
+				endpoints = append(endpoints, ep)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the interfaces of node %q: %w", node, err)
+		}
+	}
+
+	return endpoints, nil
+}
+
+// MatchVethPeers pairs the veth endpoints of different nodes that are the peers of each other.
+// Two endpoints are peers when the peer index of each of them is the index of the other one,
+// and the peer node, when known, is the node of the other one. The endpoints with the peer
+// outside of the nodes' namespaces are never matched.
+// The endpoints with no peer or with several peer candidates are returned as unmatched.
+// The pairs are sorted by the first endpoint, which is the lower of the two.
+func MatchVethPeers(endpoints []*VethEndpoint) (pairs []*VethPair, unmatched []*VethEndpoint) {
+	isPeer := func(a, b *VethEndpoint) bool {
+		return a.Node != b.Node && !a.PeerOutside && !b.PeerOutside &&
+			a.PeerIndex == b.Index && b.PeerIndex == a.Index &&
+			(a.PeerNode == "" || a.PeerNode == b.Node) &&
+			(b.PeerNode == "" || b.PeerNode == a.Node)
+	}
+
+	candidates := make(map[*VethEndpoint][]*VethEndpoint, len(endpoints))
+
+	for _, a := range endpoints {
+		for _, b := range endpoints {
+			if isPeer(a, b) {
+				candidates[a] = append(candidates[a], b)
+			}
+		}
+	}
+
+	for _, a := range endpoints {
+		c := candidates[a]
+		if len(c) != 1 || len(candidates[c[0]]) != 1 {
+			unmatched = append(unmatched, a)
+			continue
+		}
+
+		b := c[0]
+		if endpointLess(a, b) {
+			pairs = append(pairs, &VethPair{A: a, B: b})
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool { return endpointLess(pairs[i].A, pairs[j].A) })
+	sort.Slice(unmatched, func(i, j int) bool { return endpointLess(unmatched[i], unmatched[j]) })
+
+	return pairs, unmatched
+}
+
+func endpointLess(a, b *VethEndpoint) bool {
+	if a.Node != b.Node {
+		return a.Node < b.Node
+	}
+
+	return a.Interface < b.Interface
+}
@@ -0,0 +1,136 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/internal/netnstest"
+)
+
+func TestDiscoverVethLinks(t *testing.T) {
+	namespaces := map[string]ns.NetNS{}
+	for _, n := range []string{"n1", "n2", "n3", "n4"} {
+		namespaces[n] = netnstest.New(t)
+	}
+
+	// the interface indexes start from the same value in every namespace,
+	// so the peers can't be told apart by the indexes only
+	netnstest.AddVeth(t, namespaces["n1"], "e1-1", namespaces["n2"], "e1-1")
+	netnstest.AddVeth(t, namespaces["n1"], "e1-2", namespaces["n3"], "e1-1")
+	netnstest.AddVeth(t, namespaces["n2"], "e1-2", namespaces["n3"], "e1-2")
+	netnstest.AddVeth(t, namespaces["n3"], "e1-3", namespaces["n4"], "e1-1")
+	netnstest.AddVeth(t, namespaces["n4"], "e1-2", namespaces["n4"], "e1-3")
+
+	// the management interface and the interface connected to a namespace outside of the lab
+	outside := netnstest.New(t)
+	netnstest.AddVeth(t, namespaces["n4"], "eth0", outside, "veth-n4")
+	netnstest.AddVeth(t, namespaces["n2"], "e1-3", outside, "veth-n2")
+
+	endpoints, err := DiscoverVethEndpoints(namespaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, unmatched := MatchVethPeers(endpoints)
+
+	var got []string
+	for _, p := range pairs {
+		got = append(got, p.A.String()+" - "+p.B.String())
+	}
+
+	want := []string{
+		"n1:e1-1 - n2:e1-1",
+		"n1:e1-2 - n3:e1-1",
+		"n2:e1-2 - n3:e1-2",
+		"n3:e1-3 - n4:e1-1",
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("links mismatch (-want +got):\n%s", d)
+	}
+
+	var gotUnmatched []string
+	for _, e := range unmatched {
+		gotUnmatched = append(gotUnmatched, e.String())
+	}
+
+	// the loop within a single node is not a link between the nodes
+	wantUnmatched := []string{"n2:e1-3", "n4:e1-2", "n4:e1-3"}
+
+	if d := cmp.Diff(wantUnmatched, gotUnmatched); d != "" {
+		t.Errorf("unmatched endpoints mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestMatchVethPeers(t *testing.T) {
+	tests := map[string]struct {
+		endpoints     []*VethEndpoint
+		wantPairs     []string
+		wantUnmatched []string
+	}{
+		"peer nodes known": {
+			endpoints: []*VethEndpoint{
+				{Node: "n1", Interface: "e1", Index: 2, PeerIndex: 2, PeerNode: "n2"},
+				{Node: "n2", Interface: "e1", Index: 2, PeerIndex: 2, PeerNode: "n1"},
+				{Node: "n3", Interface: "e1", Index: 2, PeerIndex: 2, PeerNode: "n4"},
+				{Node: "n4", Interface: "e1", Index: 2, PeerIndex: 2, PeerNode: "n3"},
+			},
+			wantPairs: []string{"n1:e1-n2:e1", "n3:e1-n4:e1"},
+		},
+		"peer nodes unknown and unique indexes": {
+			endpoints: []*VethEndpoint{
+				{Node: "n2", Interface: "e1", Index: 12, PeerIndex: 11},
+				{Node: "n1", Interface: "e1", Index: 11, PeerIndex: 12},
+				{Node: "n1", Interface: "e2", Index: 13, PeerIndex: 14},
+				{Node: "n3", Interface: "e1", Index: 14, PeerIndex: 13},
+			},
+			wantPairs: []string{"n1:e1-n2:e1", "n1:e2-n3:e1"},
+		},
+		"ambiguous indexes": {
+			endpoints: []*VethEndpoint{
+				{Node: "n1", Interface: "e1", Index: 2, PeerIndex: 2},
+				{Node: "n2", Interface: "e1", Index: 2, PeerIndex: 2},
+				{Node: "n3", Interface: "e1", Index: 2, PeerIndex: 2},
+			},
+			wantUnmatched: []string{"n1:e1", "n2:e1", "n3:e1"},
+		},
+		"peer outside of the lab": {
+			endpoints: []*VethEndpoint{
+				{Node: "n1", Interface: "e1", Index: 2, PeerIndex: 7},
+			},
+			wantUnmatched: []string{"n1:e1"},
+		},
+		"peer outside of the lab with colliding indexes": {
+			endpoints: []*VethEndpoint{
+				{Node: "n1", Interface: "e1", Index: 2, PeerIndex: 3, PeerOutside: true},
+				{Node: "n2", Interface: "e1", Index: 3, PeerIndex: 2, PeerNode: "n2"},
+			},
+			wantUnmatched: []string{"n1:e1", "n2:e1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pairs, unmatched := MatchVethPeers(tt.endpoints)
+
+			var gotPairs []string
+			for _, p := range pairs {
+				gotPairs = append(gotPairs, p.A.String()+"-"+p.B.String())
+			}
+
+			var gotUnmatched []string
+			for _, e := range unmatched {
+				gotUnmatched = append(gotUnmatched, e.String())
+			}
+
+			if d := cmp.Diff(tt.wantPairs, gotPairs); d != "" {
+				t.Errorf("pairs mismatch (-want +got):\n%s", d)
+			}
+
+			if d := cmp.Diff(tt.wantUnmatched, gotUnmatched); d != "" {
+				t.Errorf("unmatched mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
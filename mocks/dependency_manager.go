@@ -48,6 +48,20 @@ func (mr *MockDependencyManagerMockRecorder) AddDependency(dependee, depender in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockDependencyManager)(nil).AddDependency), dependee, depender)
 }
 
+// AddStateDependency mocks base method.
+func (m *MockDependencyManager) AddStateDependency(dependee, depender string, state dependency_manager.NodeState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStateDependency", dependee, depender, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddStateDependency indicates an expected call of AddStateDependency.
+func (mr *MockDependencyManagerMockRecorder) AddStateDependency(dependee, depender, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStateDependency", reflect.TypeOf((*MockDependencyManager)(nil).AddStateDependency), dependee, depender, state)
+}
+
 // AddNode mocks base method.
 func (m *MockDependencyManager) AddNode(name string) {
 	m.ctrl.T.Helper()
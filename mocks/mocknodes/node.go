@@ -108,6 +108,20 @@ func (mr *MockNodeMockRecorder) CheckInterfaceName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInterfaceName", reflect.TypeOf((*MockNode)(nil).CheckInterfaceName))
 }
 
+// CollectDiagnostics mocks base method.
+func (m *MockNode) CollectDiagnostics(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectDiagnostics", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CollectDiagnostics indicates an expected call of CollectDiagnostics.
+func (mr *MockNodeMockRecorder) CollectDiagnostics(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectDiagnostics", reflect.TypeOf((*MockNode)(nil).CollectDiagnostics), arg0)
+}
+
 // Config mocks base method.
 func (m *MockNode) Config() *types.NodeConfig {
 	m.ctrl.T.Helper()
@@ -122,6 +136,20 @@ func (mr *MockNodeMockRecorder) Config() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockNode)(nil).Config))
 }
 
+// DefaultSysctls mocks base method.
+func (m *MockNode) DefaultSysctls() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultSysctls")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// DefaultSysctls indicates an expected call of DefaultSysctls.
+func (mr *MockNodeMockRecorder) DefaultSysctls() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultSysctls", reflect.TypeOf((*MockNode)(nil).DefaultSysctls))
+}
+
 // Delete mocks base method.
 func (m *MockNode) Delete(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -206,6 +234,36 @@ func (mr *MockNodeMockRecorder) GenerateConfig(dst, templ interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateConfig", reflect.TypeOf((*MockNode)(nil).GenerateConfig), dst, templ)
 }
 
+// GetConfigPair mocks base method.
+func (m *MockNode) GetConfigPair(arg0 context.Context) (*nodes.ConfigPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigPair", arg0)
+	ret0, _ := ret[0].(*nodes.ConfigPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigPair indicates an expected call of GetConfigPair.
+func (mr *MockNodeMockRecorder) GetConfigPair(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigPair", reflect.TypeOf((*MockNode)(nil).GetConfigPair), arg0)
+}
+
+// GetContainerStats mocks base method.
+func (m *MockNode) GetContainerStats(ctx context.Context) (*runtime.ContainerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContainerStats", ctx)
+	ret0, _ := ret[0].(*runtime.ContainerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContainerStats indicates an expected call of GetContainerStats.
+func (mr *MockNodeMockRecorder) GetContainerStats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerStats", reflect.TypeOf((*MockNode)(nil).GetContainerStats), ctx)
+}
+
 // GetContainers mocks base method.
 func (m *MockNode) GetContainers(ctx context.Context) ([]runtime.GenericContainer, error) {
 	m.ctrl.T.Helper()
@@ -324,6 +382,20 @@ func (mr *MockNodeMockRecorder) Init(arg0 interface{}, arg1 ...interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockNode)(nil).Init), varargs...)
 }
 
+// NOSInterfaceName mocks base method.
+func (m *MockNode) NOSInterfaceName(ifName string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NOSInterfaceName", ifName)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NOSInterfaceName indicates an expected call of NOSInterfaceName.
+func (mr *MockNodeMockRecorder) NOSInterfaceName(ifName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NOSInterfaceName", reflect.TypeOf((*MockNode)(nil).NOSInterfaceName), ifName)
+}
+
 // PostDeploy mocks base method.
 func (m *MockNode) PostDeploy(ctx context.Context, params *nodes.PostDeployParams) error {
 	m.ctrl.T.Helper()
@@ -393,6 +465,20 @@ func (mr *MockNodeMockRecorder) SetState(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetState", reflect.TypeOf((*MockNode)(nil).SetState), arg0)
 }
 
+// Shutdown mocks base method.
+func (m *MockNode) Shutdown(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shutdown", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockNodeMockRecorder) Shutdown(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockNode)(nil).Shutdown), arg0)
+}
+
 // UpdateConfigWithRuntimeInfo mocks base method.
 func (m *MockNode) UpdateConfigWithRuntimeInfo(arg0 context.Context) error {
 	m.ctrl.T.Helper()
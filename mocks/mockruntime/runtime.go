@@ -6,6 +6,7 @@ package mockruntime
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -38,6 +39,34 @@ func (m *MockContainerRuntime) EXPECT() *MockContainerRuntimeMockRecorder {
 	return m.recorder
 }
 
+// BuildImage mocks base method.
+func (m *MockContainerRuntime) BuildImage(ctx context.Context, spec *types.ImageBuild) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildImage", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuildImage indicates an expected call of BuildImage.
+func (mr *MockContainerRuntimeMockRecorder) BuildImage(ctx, spec interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildImage", reflect.TypeOf((*MockContainerRuntime)(nil).BuildImage), ctx, spec)
+}
+
+// CheckVersion mocks base method.
+func (m *MockContainerRuntime) CheckVersion(ctx context.Context, nodes []*types.NodeConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckVersion", ctx, nodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckVersion indicates an expected call of CheckVersion.
+func (mr *MockContainerRuntimeMockRecorder) CheckVersion(ctx, nodes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckVersion", reflect.TypeOf((*MockContainerRuntime)(nil).CheckVersion), ctx, nodes)
+}
+
 // Config mocks base method.
 func (m *MockContainerRuntime) Config() runtime.RuntimeConfig {
 	m.ctrl.T.Helper()
@@ -52,6 +81,20 @@ func (mr *MockContainerRuntimeMockRecorder) Config() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockContainerRuntime)(nil).Config))
 }
 
+// CopyFromContainer mocks base method.
+func (m *MockContainerRuntime) CopyFromContainer(ctx context.Context, cID, srcPath, dstPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFromContainer", ctx, cID, srcPath, dstPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyFromContainer indicates an expected call of CopyFromContainer.
+func (mr *MockContainerRuntimeMockRecorder) CopyFromContainer(ctx, cID, srcPath, dstPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFromContainer", reflect.TypeOf((*MockContainerRuntime)(nil).CopyFromContainer), ctx, cID, srcPath, dstPath)
+}
+
 // CreateContainer mocks base method.
 func (m *MockContainerRuntime) CreateContainer(arg0 context.Context, arg1 *types.NodeConfig) (string, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +124,20 @@ func (mr *MockContainerRuntimeMockRecorder) CreateNet(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNet", reflect.TypeOf((*MockContainerRuntime)(nil).CreateNet), arg0)
 }
 
+// CreateVolume mocks base method.
+func (m *MockContainerRuntime) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVolume", ctx, name, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVolume indicates an expected call of CreateVolume.
+func (mr *MockContainerRuntimeMockRecorder) CreateVolume(ctx, name, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockContainerRuntime)(nil).CreateVolume), ctx, name, labels)
+}
+
 // DeleteContainer mocks base method.
 func (m *MockContainerRuntime) DeleteContainer(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -109,6 +166,20 @@ func (mr *MockContainerRuntimeMockRecorder) DeleteNet(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNet", reflect.TypeOf((*MockContainerRuntime)(nil).DeleteNet), arg0)
 }
 
+// DeleteVolume mocks base method.
+func (m *MockContainerRuntime) DeleteVolume(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolume", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVolume indicates an expected call of DeleteVolume.
+func (mr *MockContainerRuntimeMockRecorder) DeleteVolume(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockContainerRuntime)(nil).DeleteVolume), ctx, name)
+}
+
 // Exec mocks base method.
 func (m *MockContainerRuntime) Exec(ctx context.Context, cID string, execCmd *exec.ExecCmd) (*exec.ExecResult, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +209,51 @@ func (mr *MockContainerRuntimeMockRecorder) ExecNotWait(ctx, cID, execCmd interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecNotWait", reflect.TypeOf((*MockContainerRuntime)(nil).ExecNotWait), ctx, cID, execCmd)
 }
 
+// GetContainerLogs mocks base method.
+func (m *MockContainerRuntime) GetContainerLogs(ctx context.Context, cID string, opts runtime.LogOptions) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContainerLogs", ctx, cID, opts)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContainerLogs indicates an expected call of GetContainerLogs.
+func (mr *MockContainerRuntimeMockRecorder) GetContainerLogs(ctx, cID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerLogs", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerLogs), ctx, cID, opts)
+}
+
+// GetContainerSpec mocks base method.
+func (m *MockContainerRuntime) GetContainerSpec(arg0 context.Context, arg1 *types.NodeConfig) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContainerSpec", arg0, arg1)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContainerSpec indicates an expected call of GetContainerSpec.
+func (mr *MockContainerRuntimeMockRecorder) GetContainerSpec(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerSpec", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerSpec), arg0, arg1)
+}
+
+// GetContainerStats mocks base method.
+func (m *MockContainerRuntime) GetContainerStats(ctx context.Context, cID string) (*runtime.ContainerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContainerStats", ctx, cID)
+	ret0, _ := ret[0].(*runtime.ContainerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContainerStats indicates an expected call of GetContainerStats.
+func (mr *MockContainerRuntimeMockRecorder) GetContainerStats(ctx, cID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerStats", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerStats), ctx, cID)
+}
+
 // GetContainerStatus mocks base method.
 func (m *MockContainerRuntime) GetContainerStatus(ctx context.Context, cID string) runtime.ContainerStatus {
 	m.ctrl.T.Helper()
@@ -196,6 +312,20 @@ func (mr *MockContainerRuntimeMockRecorder) GetName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockContainerRuntime)(nil).GetName))
 }
 
+// ImportImage mocks base method.
+func (m *MockContainerRuntime) ImportImage(ctx context.Context, tarPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportImage", ctx, tarPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportImage indicates an expected call of ImportImage.
+func (mr *MockContainerRuntimeMockRecorder) ImportImage(ctx, tarPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportImage", reflect.TypeOf((*MockContainerRuntime)(nil).ImportImage), ctx, tarPath)
+}
+
 // Init mocks base method.
 func (m *MockContainerRuntime) Init(arg0 ...runtime.RuntimeOption) error {
 	m.ctrl.T.Helper()
@@ -214,6 +344,51 @@ func (mr *MockContainerRuntimeMockRecorder) Init(arg0 ...interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockContainerRuntime)(nil).Init), arg0...)
 }
 
+// InspectImage mocks base method.
+func (m *MockContainerRuntime) InspectImage(ctx context.Context, imageName string) (*runtime.ImageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectImage", ctx, imageName)
+	ret0, _ := ret[0].(*runtime.ImageInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectImage indicates an expected call of InspectImage.
+func (mr *MockContainerRuntimeMockRecorder) InspectImage(ctx, imageName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectImage", reflect.TypeOf((*MockContainerRuntime)(nil).InspectImage), ctx, imageName)
+}
+
+// InspectMgmtNet mocks base method.
+func (m *MockContainerRuntime) InspectMgmtNet(arg0 context.Context) (*runtime.NetworkInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectMgmtNet", arg0)
+	ret0, _ := ret[0].(*runtime.NetworkInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectMgmtNet indicates an expected call of InspectMgmtNet.
+func (mr *MockContainerRuntimeMockRecorder) InspectMgmtNet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectMgmtNet", reflect.TypeOf((*MockContainerRuntime)(nil).InspectMgmtNet), arg0)
+}
+
+// IsContainerOOMKilled mocks base method.
+func (m *MockContainerRuntime) IsContainerOOMKilled(ctx context.Context, cID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsContainerOOMKilled", ctx, cID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsContainerOOMKilled indicates an expected call of IsContainerOOMKilled.
+func (mr *MockContainerRuntimeMockRecorder) IsContainerOOMKilled(ctx, cID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsContainerOOMKilled", reflect.TypeOf((*MockContainerRuntime)(nil).IsContainerOOMKilled), ctx, cID)
+}
+
 // ListContainers mocks base method.
 func (m *MockContainerRuntime) ListContainers(arg0 context.Context, arg1 []*types.GenericFilter) ([]runtime.GenericContainer, error) {
 	m.ctrl.T.Helper()
@@ -229,6 +404,21 @@ func (mr *MockContainerRuntimeMockRecorder) ListContainers(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContainers", reflect.TypeOf((*MockContainerRuntime)(nil).ListContainers), arg0, arg1)
 }
 
+// ListVolumes mocks base method.
+func (m *MockContainerRuntime) ListVolumes(ctx context.Context, filters []*types.GenericFilter) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumes", ctx, filters)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumes indicates an expected call of ListVolumes.
+func (mr *MockContainerRuntimeMockRecorder) ListVolumes(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockContainerRuntime)(nil).ListVolumes), ctx, filters)
+}
+
 // Mgmt mocks base method.
 func (m *MockContainerRuntime) Mgmt() *types.MgmtNet {
 	m.ctrl.T.Helper()
@@ -258,17 +448,17 @@ func (mr *MockContainerRuntimeMockRecorder) PauseContainer(arg0, arg1 interface{
 }
 
 // PullImage mocks base method.
-func (m *MockContainerRuntime) PullImage(arg0 context.Context, arg1 string, arg2 types.PullPolicyValue) error {
+func (m *MockContainerRuntime) PullImage(arg0 context.Context, arg1 string, arg2 types.PullPolicyValue, arg3 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PullImage", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "PullImage", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PullImage indicates an expected call of PullImage.
-func (mr *MockContainerRuntimeMockRecorder) PullImage(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) PullImage(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullImage", reflect.TypeOf((*MockContainerRuntime)(nil).PullImage), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullImage", reflect.TypeOf((*MockContainerRuntime)(nil).PullImage), arg0, arg1, arg2, arg3)
 }
 
 // StartContainer mocks base method.
@@ -287,17 +477,17 @@ func (mr *MockContainerRuntimeMockRecorder) StartContainer(arg0, arg1, arg2 inte
 }
 
 // StopContainer mocks base method.
-func (m *MockContainerRuntime) StopContainer(arg0 context.Context, arg1 string) error {
+func (m *MockContainerRuntime) StopContainer(ctx context.Context, cID, signal string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StopContainer", arg0, arg1)
+	ret := m.ctrl.Call(m, "StopContainer", ctx, cID, signal)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StopContainer indicates an expected call of StopContainer.
-func (mr *MockContainerRuntimeMockRecorder) StopContainer(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) StopContainer(ctx, cID, signal interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockContainerRuntime)(nil).StopContainer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockContainerRuntime)(nil).StopContainer), ctx, cID, signal)
 }
 
 // UnpauseContainer mocks base method.
@@ -18,7 +18,7 @@ var Kindnames = []string{"border0"}
 func Register(r *nodes.NodeRegistry) {
 	r.Register(Kindnames, func() nodes.Node {
 		return new(border0)
-	}, nil)
+	}, nil, nodes.DefaultKindFields)
 }
 
 type border0 struct {
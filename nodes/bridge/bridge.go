@@ -34,7 +34,7 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(bridge)
-	}, nil)
+	}, nil, nodes.NonContainerKindFields)
 }
 
 type bridge struct {
@@ -121,6 +121,11 @@ func (*bridge) UpdateConfigWithRuntimeInfo(_ context.Context) error { return nil
 // GetContainers is a noop for bridges.
 func (*bridge) GetContainers(_ context.Context) ([]runtime.GenericContainer, error) { return nil, nil }
 
+// GetContainerStats returns runtime.ErrStatsNotSupported, the bridge is not a container.
+func (b *bridge) GetContainerStats(_ context.Context) (*runtime.ContainerStats, error) {
+	return nil, fmt.Errorf("%w for %q node kind", runtime.ErrStatsNotSupported, b.Cfg.Kind)
+}
+
 // RunExec is a noop for bridge kind.
 func (b *bridge) RunExec(_ context.Context, _ *cExec.ExecCmd) (*cExec.ExecResult, error) {
 	log.Warnf("Exec operation is not implemented for kind %q", b.Config().Kind)
@@ -35,7 +35,8 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(c8000)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.DefaultKindFields)
+	nodes.SetSwapDisabledPerKind(kindnames)
 }
 
 type c8000 struct {
@@ -10,7 +10,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net"
 	"os"
 	"path"
 	"path/filepath"
@@ -47,14 +46,30 @@ var (
 
 	saveCmd = "Cli -p 15 -c wr"
 
+	// shutdownCmd powers the system down, the container exits with the init process.
+	shutdownCmd = `Cli -p 15 -c "reload power now"`
+
+	// diagnosticsFile is the path in the container the show tech-support output is saved to.
+	diagnosticsFile = "/tmp/show-tech-support.txt"
+
 	defaultCredentials = nodes.NewCredentials("admin", "admin")
+	// exposedServices are the container ports of the services ceos nodes can expose on the host.
+	exposedServices = map[string]int{
+		nodes.ServiceSSH:     22,
+		nodes.ServiceGNMI:    6030,
+		nodes.ServiceNETCONF: 830,
+		nodes.ServiceHTTP:    80,
+		nodes.ServiceHTTPS:   443,
+	}
 )
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(ceos)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.DefaultKindFields)
+	nodes.SetSwapDisabledPerKind(kindnames)
+	nodes.SetExposedServicesPerKind(kindnames, exposedServices)
 }
 
 type ceos struct {
@@ -80,7 +95,7 @@ func (n *ceos) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		o(n)
 	}
 
-	n.Cfg.Env = utils.MergeStringMaps(ceosEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, ceosEnv)
 
 	// the node.Cmd should be aligned with the environment.
 	// prepending original Cmd with if-wait.sh script to make sure that interfaces are available
@@ -136,6 +151,26 @@ func (n *ceos) SaveConfig(ctx context.Context) error {
 	return nil
 }
 
+// Shutdown powers down cEOS and waits until the node container is stopped.
+func (n *ceos) Shutdown(ctx context.Context) error {
+	return n.ShutdownWithCmd(ctx, shutdownCmd)
+}
+
+func (n *ceos) CollectDiagnostics(ctx context.Context) error {
+	cmd := exec.NewExecCmdFromSlice([]string{
+		"bash", "-c", "Cli -p 15 -c 'show tech-support' > " + diagnosticsFile,
+	})
+
+	dst, err := n.SaveDiagnostics(ctx, cmd, diagnosticsFile)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("saved cEOS show tech-support from %s node to %s\n", n.Cfg.ShortName, dst)
+
+	return nil
+}
+
 func (n *ceos) createCEOSFiles(_ context.Context) error {
 	nodeCfg := n.Config()
 	// generate config directory
@@ -186,11 +221,11 @@ func (n *ceos) createCEOSFiles(_ context.Context) error {
 	}
 
 	// sysmac is a system mac that is +1 to Ma0 mac
-	m, err := net.ParseMAC(nodeCfg.MacAddress)
+	m, err := utils.ParseInterfaceMAC(nodeCfg.MacAddress)
 	if err != nil {
 		return err
 	}
-	m[5] = m[5] + 1
+	m = utils.IncrementMAC(m)
 
 	sysMacPath := path.Join(nodeCfg.LabDir, "flash", "system_mac_address")
 
@@ -0,0 +1,79 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ceos
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// runningCfgCmd retrieves the running config.
+var runningCfgCmd = []string{"Cli", "-p", "15", "-c", "show running-config"}
+
+// secretRe matches the secrets which are stored hashed with a random salt in the running config.
+var secretRe = regexp.MustCompile(`\b(secret|password)( sha512| [0-9])? \S+`)
+
+// GetConfigPair returns the startup config rendered from the topology and the running config of the node.
+func (n *ceos) GetConfigPair(ctx context.Context) (*nodes.ConfigPair, error) {
+	if err := setMgmtInterface(n.Cfg); err != nil {
+		return nil, err
+	}
+
+	templ := cfgTemplate
+
+	if n.Cfg.StartupConfig != "" {
+		c, err := os.ReadFile(n.Cfg.StartupConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		templ = string(c)
+	}
+
+	return n.NewConfigPair(ctx, templ, exec.NewExecCmdFromSlice(runningCfgCmd), normalizeConfig)
+}
+
+// normalizeConfig normalizes the EOS CLI config for the comparison.
+// The comments with the device details and the separators are removed, the secrets are masked
+// and the top-level sections are sorted, as their order is irrelevant.
+func normalizeConfig(cfg string) (string, error) {
+	var sections []string
+
+	var section strings.Builder
+
+	for _, line := range strings.Split(cfg, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "end" || strings.HasPrefix(trimmed, "!") {
+			continue
+		}
+
+		line = secretRe.ReplaceAllString(line, "$1 <masked>")
+
+		// an unindented line starts a new section
+		if !strings.HasPrefix(line, " ") && section.Len() > 0 {
+			sections = append(sections, section.String())
+			section.Reset()
+		}
+
+		section.WriteString(line)
+		section.WriteByte('\n')
+	}
+
+	if section.Len() > 0 {
+		sections = append(sections, section.String())
+	}
+
+	sort.Strings(sections)
+
+	return strings.Join(sections, ""), nil
+}
@@ -0,0 +1,47 @@
+package ceos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeConfig(t *testing.T) {
+	want, err := os.ReadFile(filepath.Join("test_data", "config_diff", "normalized"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the startup config and the running config differ in the comments, secret hashes and ordering only
+	for _, f := range []string{"startup-config", "running-config"} {
+		t.Run(f, func(t *testing.T) {
+			cfg, err := os.ReadFile(filepath.Join("test_data", "config_diff", f))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := normalizeConfig(string(cfg))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(string(want), got); d != "" {
+				t.Fatalf("normalized config mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestNormalizeConfigDrift(t *testing.T) {
+	startup := "interface Ethernet1\n   ip address 192.168.0.1/30\n!\nend\n"
+	running := "interface Ethernet1\n   ip address 192.168.0.5/30\n!\nend\n"
+
+	a, _ := normalizeConfig(startup)
+	b, _ := normalizeConfig(running)
+
+	if a == b {
+		t.Fatal("the changed interface address was normalized away")
+	}
+}
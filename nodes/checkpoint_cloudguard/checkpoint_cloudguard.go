@@ -22,7 +22,8 @@ var (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(CheckpointCloudguard)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.DefaultKindFields)
+	nodes.SetSwapDisabledPerKind(kindnames)
 }
 
 type CheckpointCloudguard struct {
@@ -47,10 +48,10 @@ func (n *CheckpointCloudguard) Init(cfg *types.NodeConfig, opts ...nodes.NodeOpt
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
-	n.Cfg.Env = utils.MergeStringMaps(defEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, defEnv)
 
 	n.Cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
-		n.Cfg.Env["USERNAME"], n.Cfg.Env["PASSWORD"], n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
+		n.Cfg.Env["USERNAME"], n.Cfg.Env["PASSWORD"], n.Cfg.GetHostname(), n.Cfg.Env["CONNECTION_MODE"])
 
 	return nil
 }
@@ -0,0 +1,24 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import "errors"
+
+// ErrConfigDiffNotSupported is returned by the nodes which running config
+// can't be compared with the startup config rendered from the topology.
+var ErrConfigDiffNotSupported = errors.New("config diff is not supported")
+
+// ConfigPair is a pair of the node configs compared by the config drift detection.
+// Both configs are normalized by the node kind, e.g. the timestamps are removed
+// and the sections which order is irrelevant are sorted.
+type ConfigPair struct {
+	// Rendered is the startup config rendered from the topology.
+	Rendered string
+	// Running is the running config retrieved from the node.
+	Running string
+}
+
+// ConfigNormalizer is a kind-specific function normalizing a node config for the comparison.
+type ConfigNormalizer func(cfg string) (string, error)
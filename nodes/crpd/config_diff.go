@@ -0,0 +1,53 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package crpd
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// GetConfigPair returns the startup config rendered from the topology and the running config of the node.
+func (s *crpd) GetConfigPair(ctx context.Context) (*nodes.ConfigPair, error) {
+	templ := defaultCfgTemplate
+
+	if s.Cfg.StartupConfig != "" {
+		c, err := os.ReadFile(s.Cfg.StartupConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		templ = string(c)
+	}
+
+	cmd, _ := exec.NewExecCmdFromString(saveCmd)
+
+	return s.NewConfigPair(ctx, templ, cmd, normalizeConfig)
+}
+
+// normalizeConfig normalizes the Junos config for the comparison.
+// The commit timestamps, the version statement set by the device and the secret data markers are removed.
+// The order of the Junos config statements is preserved, as it is the order the device displays them in.
+func normalizeConfig(cfg string) (string, error) {
+	var sb strings.Builder
+
+	for _, line := range strings.Split(cfg, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		line = strings.TrimSuffix(line, " ## SECRET-DATA")
+
+		if line == "" || strings.HasPrefix(line, "## ") || strings.HasPrefix(line, "version ") {
+			continue
+		}
+
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+
+	return sb.String(), nil
+}
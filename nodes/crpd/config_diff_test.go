@@ -0,0 +1,29 @@
+package crpd
+
+import "testing"
+
+func TestNormalizeConfig(t *testing.T) {
+	running := `## Last commit: 2023-10-02 09:15:43 UTC by root
+version 20200609.165031.6_builder.r1115480;
+system {
+    root-authentication {
+        encrypted-password "$6$lB5c6$Zeud8c6Ih"; ## SECRET-DATA
+    }
+}
+`
+	want := `system {
+    root-authentication {
+        encrypted-password "$6$lB5c6$Zeud8c6Ih";
+    }
+}
+`
+
+	got, err := normalizeConfig(running)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != want {
+		t.Fatalf("got normalized config:\n%s\nwant:\n%s", got, want)
+	}
+}
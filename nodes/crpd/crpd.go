@@ -24,6 +24,12 @@ const (
 
 var (
 	kindnames = []string{"crpd", "juniper_crpd"}
+	// kindFields are the node definition fields supported by crpd, it consumes the license
+	// and is a linux-like kind getting the time zone.
+	kindFields = nodes.DefaultKindFields.With(nodes.KindFields{
+		"license":  nodes.FieldSupported,
+		"timezone": nodes.FieldSupported,
+	})
 	//go:embed crpd.cfg
 	defaultCfgTemplate string
 
@@ -34,13 +40,24 @@ var (
 
 	saveCmd       = "cli show conf"
 	sshRestartCmd = "service ssh restart"
+
+	// diagnosticsFile is the path in the container the support information is saved to.
+	diagnosticsFile = "/var/tmp/support-information.txt"
+	// exposedServices are the container ports of the services crpd nodes can expose on the host.
+	exposedServices = map[string]int{
+		nodes.ServiceSSH:     22,
+		nodes.ServiceNETCONF: 830,
+	}
 )
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(crpd)
-	}, defaultCredentials)
+	}, defaultCredentials, kindFields)
+	nodes.SetSwapDisabledPerKind(kindnames)
+	nodes.SetTimezonePerKind(kindnames)
+	nodes.SetExposedServicesPerKind(kindnames, exposedServices)
 }
 
 type crpd struct {
@@ -114,6 +131,21 @@ func (s *crpd) SaveConfig(ctx context.Context) error {
 	return nil
 }
 
+func (s *crpd) CollectDiagnostics(ctx context.Context) error {
+	cmd := exec.NewExecCmdFromSlice([]string{
+		"cli", "-c", "request support information | save " + diagnosticsFile,
+	})
+
+	dst, err := s.SaveDiagnostics(ctx, cmd, diagnosticsFile)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("saved cRPD support information from %s node to %s\n", s.Cfg.ShortName, dst)
+
+	return nil
+}
+
 func createCRPDFiles(node nodes.Node) error {
 	nodeCfg := node.Config()
 	// create config and logs directory that will be bind mounted to crpd
@@ -18,6 +18,12 @@ var (
 	kindnames                 = []string{"cvx", "cumulus_cvx"}
 	defaultCvxKernelImageRef  = "docker.io/networkop/kernel:4.19"
 	defaultIgniteSandboxImage = "networkop/ignite:dev"
+	// kindFields are the node definition fields supported by cvx, which runs with the ignite runtime
+	// by default and consumes the sandbox and kernel images.
+	kindFields = nodes.DefaultKindFields.With(nodes.KindFields{
+		"sandbox": nodes.FieldSupported,
+		"kernel":  nodes.FieldSupported,
+	})
 )
 
 var memoryReqs = map[string]string{
@@ -29,7 +35,7 @@ var memoryReqs = map[string]string{
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(cvx)
-	}, nil)
+	}, nil, kindFields)
 	nodes.SetNonDefaultRuntimePerKind(kindnames, ignite.RuntimeName)
 }
 
@@ -7,12 +7,14 @@ package nodes
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 	"sync"
 	"text/template"
+	"time"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/hairyhenderson/gomplate/v3"
@@ -84,6 +86,43 @@ func (d *DefaultNode) SaveConfig(_ context.Context) error {
 	return nil
 }
 
+// Shutdown returns ErrShutdownNotSupported, the kinds with a native shutdown mechanism implement their own method.
+func (d *DefaultNode) Shutdown(_ context.Context) error {
+	return fmt.Errorf("%w for %q node kind", ErrShutdownNotSupported, d.Cfg.Kind)
+}
+
+func (d *DefaultNode) CollectDiagnostics(_ context.Context) error {
+	// nodes should have the diagnostics collection defined on their respective structs.
+	// By default CollectDiagnostics is a noop.
+	log.Debugf("Diagnostics collection is currently not supported for %q node kind", d.Cfg.Kind)
+	return nil
+}
+
+// SaveDiagnostics runs the diagnostics command cmd in the node container and copies the bundle
+// that the command stores by the srcPath to the diagnostics directory of the node.
+// The path of the copied bundle is returned.
+func (d *DefaultNode) SaveDiagnostics(ctx context.Context, cmd *exec.ExecCmd, srcPath string) (string, error) {
+	execResult, err := d.RunExec(ctx, cmd)
+	if err != nil {
+		return "", fmt.Errorf("node %q: failed to run diagnostics command: %w", d.Cfg.ShortName, err)
+	}
+
+	if execResult.GetReturnCode() != 0 {
+		return "", fmt.Errorf("node %q: diagnostics command exited with code %d: %s",
+			d.Cfg.ShortName, execResult.GetReturnCode(), execResult.GetStdErrString())
+	}
+
+	dst := filepath.Join(d.Cfg.LabDir, diagnosticsDir,
+		time.Now().Format("20060102-150405")+"-"+filepath.Base(srcPath))
+
+	err = d.GetRuntime().CopyFromContainer(ctx, d.OverwriteNode.GetContainerName(), srcPath, dst)
+	if err != nil {
+		return "", fmt.Errorf("node %q: failed to copy diagnostics bundle: %w", d.Cfg.ShortName, err)
+	}
+
+	return dst, nil
+}
+
 // CheckDeploymentConditions wraps individual functions that check if a node
 // satisfies deployment requirements.
 func (d *DefaultNode) CheckDeploymentConditions(ctx context.Context) error {
@@ -115,14 +154,73 @@ func (d *DefaultNode) PullImage(ctx context.Context) error {
 		if imageName == "" {
 			return fmt.Errorf("missing required %q attribute for node %q", imageKey, d.Cfg.ShortName)
 		}
-		err := d.Runtime.PullImage(ctx, imageName, d.Config().ImagePullPolicy)
+		if tarPath, ok := utils.ImageArchivePath(imageName); ok {
+			if imageKey != ImageKey {
+				return fmt.Errorf("image archive %q can't be used as the %q attribute of node %q, only the %q attribute supports image archives",
+					imageName, imageKey, d.Cfg.ShortName, ImageKey)
+			}
+			img, err := d.importImage(ctx, tarPath)
+			if err != nil {
+				return fmt.Errorf("node %q: %w", d.Cfg.ShortName, err)
+			}
+			// the node container is created from the imported image
+			d.Cfg.Image = img
+			continue
+		}
+		if d.Cfg.PulledImages[imageName] {
+			log.Debugf("Image %s of node %q is pulled by the lab, skip pulling", imageName, d.Cfg.ShortName)
+			continue
+		}
+		err := d.Runtime.PullImage(ctx, imageName, d.Config().ImagePullPolicy, d.Config().Platform)
 		if err != nil {
+			// attach the node name to the image pull error
+			// so that the image problems can be reported per node
+			var pullErr *runtime.ImagePullError
+			if errors.As(err, &pullErr) {
+				pullErr.AddNode(d.Cfg.ShortName)
+			}
 			return err
 		}
 	}
 	return nil
 }
 
+// importImage imports the images of the image archive unless they are present locally,
+// and returns the first image of the archive the node uses.
+// The archive is imported regardless of the local images when the pull policy is always.
+func (d *DefaultNode) importImage(ctx context.Context, tarPath string) (string, error) {
+	tags, err := utils.ImageArchiveTags(tarPath)
+	if err != nil {
+		return "", err
+	}
+
+	if d.Cfg.ImagePullPolicy != types.PullPolicyAlways && d.imagesPresent(ctx, tags) {
+		log.Debugf("Images %v of archive %s present, skip importing", tags, tarPath)
+		return tags[0], nil
+	}
+
+	if d.Cfg.ImagePullPolicy == types.PullPolicyNever {
+		return "", fmt.Errorf("images %v of archive %s not found locally, and image-pull-policy=%s prevents containerlab from importing them",
+			tags, tarPath, d.Cfg.ImagePullPolicy)
+	}
+
+	if err := d.Runtime.ImportImage(ctx, tarPath); err != nil {
+		return "", err
+	}
+
+	return tags[0], nil
+}
+
+// imagesPresent returns true if all the images are present locally.
+func (d *DefaultNode) imagesPresent(ctx context.Context, images []string) bool {
+	for _, img := range images {
+		if _, err := d.Runtime.InspectImage(ctx, img); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
 func (d *DefaultNode) VerifyHostRequirements() error {
 	return d.HostRequirements.Verify(d.Cfg.Kind, d.Cfg.ShortName)
 }
@@ -158,6 +256,11 @@ func (d *DefaultNode) GetImages(_ context.Context) map[string]string {
 	}
 }
 
+// DefaultSysctls returns no sysctls, the kinds requiring sysctls define the method on their respective structs.
+func (*DefaultNode) DefaultSysctls() map[string]string {
+	return nil
+}
+
 func (d *DefaultNode) GetContainers(ctx context.Context) ([]runtime.GenericContainer, error) {
 	cnts, err := d.Runtime.ListContainers(ctx, []*types.GenericFilter{
 		{
@@ -177,6 +280,11 @@ func (d *DefaultNode) GetContainers(ctx context.Context) ([]runtime.GenericConta
 	return cnts, err
 }
 
+// GetContainerStats returns the current resource usage of the node container reported by the runtime.
+func (d *DefaultNode) GetContainerStats(ctx context.Context) (*runtime.ContainerStats, error) {
+	return d.Runtime.GetContainerStats(ctx, d.OverwriteNode.GetContainerName())
+}
+
 func (d *DefaultNode) UpdateConfigWithRuntimeInfo(ctx context.Context) error {
 	cnts, err := d.OverwriteNode.GetContainers(ctx)
 	if err != nil {
@@ -216,6 +324,12 @@ func (*DefaultNode) CheckInterfaceName() error {
 	return nil
 }
 
+// NOSInterfaceName returns the name of the interface used by the network OS of the node.
+// The default node uses the interface names from the topology file as is.
+func (*DefaultNode) NOSInterfaceName(ifName string) string {
+	return ifName
+}
+
 // VerifyStartupConfig verifies that startup config files exists on disks.
 func (d *DefaultNode) VerifyStartupConfig(topoDir string) error {
 	cfg := d.Config().StartupConfig
@@ -251,34 +365,91 @@ func (d *DefaultNode) GenerateConfig(dst, templ string) error {
 
 	log.Debugf("generating config for node %s from file %s", d.Cfg.ShortName, d.Cfg.StartupConfig)
 
+	dstBytes, err := d.RenderConfig(templ)
+	if err != nil {
+		return err
+	}
+	log.Debugf("node '%s' generated config: %s", d.Cfg.ShortName, dstBytes.String())
+
+	// an empty config is never written, as the node would boot with no config at all
+	if len(bytes.TrimSpace(dstBytes.Bytes())) == 0 {
+		return fmt.Errorf("node %q: generated startup config is empty", d.Cfg.ShortName)
+	}
+
+	// keep the previous rendering as a numbered backup
+	err = utils.RotateFile(dst, d.Cfg.StartupConfigBackups, func(n int) string {
+		return types.BackupFilePath(dst, n)
+	})
+	if err != nil {
+		return fmt.Errorf("node %q: failed to back up the startup config %s: %w", d.Cfg.ShortName, dst, err)
+	}
+
+	// the config is written to a temp file first and renamed to dst,
+	// so that dst is never left partially written
+	return utils.WriteFileAtomic(dst, dstBytes.Bytes(), 0644)
+}
+
+// RenderConfig renders the config template templ with the node config without writing it.
+func (d *DefaultNode) RenderConfig(templ string) (*bytes.Buffer, error) {
 	// gomplate overrides the built-in *slice* function. You can still use *coll.Slice*
 	gfuncs := gomplate.CreateFuncs(context.Background(), new(data.Data))
 	delete(gfuncs, "slice")
 	tpl, err := template.New(filepath.Base(d.Cfg.StartupConfig)).Funcs(gfuncs).Parse(templ)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	dstBytes := new(bytes.Buffer)
+	buf := new(bytes.Buffer)
 
-	err = tpl.Execute(dstBytes, d.Cfg)
+	err = tpl.Execute(buf, d.Cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	log.Debugf("node '%s' generated config: %s", d.Cfg.ShortName, dstBytes.String())
 
-	f, err := os.Create(dst)
+	return buf, nil
+}
+
+// GetConfigPair returns ErrConfigDiffNotSupported, as the running config can only be retrieved
+// by the kind-specific implementations which use the NewConfigPair helper.
+func (d *DefaultNode) GetConfigPair(_ context.Context) (*ConfigPair, error) {
+	return nil, fmt.Errorf("%w for %q node kind", ErrConfigDiffNotSupported, d.Cfg.Kind)
+}
+
+// NewConfigPair renders the startup config template templ and retrieves the running config
+// of the node with the command cmd. Both configs are normalized with the kind's normalize function.
+func (d *DefaultNode) NewConfigPair(ctx context.Context, templ string, cmd *exec.ExecCmd,
+	normalize ConfigNormalizer,
+) (*ConfigPair, error) {
+	if d.Cfg.SuppressStartupConfig {
+		return nil, fmt.Errorf("%w: startup config generation is suppressed", ErrConfigDiffNotSupported)
+	}
+
+	rendered, err := d.RenderConfig(templ)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to render the startup config: %w", err)
 	}
 
-	_, err = f.Write(dstBytes.Bytes())
+	execResult, err := d.RunExec(ctx, cmd)
 	if err != nil {
-		f.Close()
-		return err
+		return nil, fmt.Errorf("failed to retrieve the running config: %w", err)
+	}
+
+	if execResult.GetReturnCode() != 0 || execResult.GetStdErrString() != "" {
+		return nil, fmt.Errorf("failed to retrieve the running config with %q: rc=%d, stderr: %s",
+			cmd.GetCmdString(), execResult.GetReturnCode(), execResult.GetStdErrString())
+	}
+
+	p := &ConfigPair{}
+
+	if p.Rendered, err = normalize(rendered.String()); err != nil {
+		return nil, fmt.Errorf("failed to normalize the rendered startup config: %w", err)
 	}
 
-	return f.Close()
+	if p.Running, err = normalize(execResult.GetStdOutString()); err != nil {
+		return nil, fmt.Errorf("failed to normalize the running config: %w", err)
+	}
+
+	return p, nil
 }
 
 // NodeOverwrites is an interface that every node implementation implements.
@@ -381,7 +552,12 @@ func (d *DefaultNode) VerifyLicenseFileExists(_ context.Context) error {
 
 // LoadOrGenerateCertificate loads a certificate using a certificate storage provider
 // provided in certInfra or generates a new one if it does not exist.
+// The externally issued certificate set for the node is used instead of the generated one.
 func (d *DefaultNode) LoadOrGenerateCertificate(certInfra *cert.Cert, topoName string) (nodeCert *cert.Certificate, err error) {
+	if d.Cfg.TLS.IsSet() {
+		return d.loadExternalCertificate(certInfra)
+	}
+
 	// early return if certificate generation is not required
 	if d.Cfg.Certificate == nil || !*d.Cfg.Certificate.Issue {
 		return nil, nil
@@ -425,6 +601,35 @@ func (d *DefaultNode) LoadOrGenerateCertificate(certInfra *cert.Cert, topoName s
 	return nodeCert, nil
 }
 
+// loadExternalCertificate loads the externally issued tls material of the node
+// and stores the certificate and its key in the node tls directory in place of the generated ones.
+// The CA certificate, when provided, is set as the trust anchor of the node.
+func (d *DefaultNode) loadExternalCertificate(certInfra *cert.Cert) (*cert.Certificate, error) {
+	log.Debugf("using the externally issued certificate %s for node %s", d.Cfg.TLS.Cert, d.Cfg.ShortName)
+
+	nodeCert, err := cert.NewCertificateFromExternalFiles(d.Cfg.TLS.Cert, d.Cfg.TLS.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Cfg.TLS.CA != "" {
+		ca, err := cert.LoadExternalCACert(d.Cfg.TLS.CA)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Cfg.TLSAnchor = string(ca)
+	}
+
+	if certInfra != nil {
+		if err := certInfra.StoreNodeCert(d.Cfg.ShortName, nodeCert); err != nil {
+			return nil, err
+		}
+	}
+
+	return nodeCert, nil
+}
+
 func (d *DefaultNode) AddLinkToContainer(_ context.Context, link netlink.Link, f func(ns.NetNS) error) error {
 	// retrieve the namespace handle
 	netns, err := ns.GetNS(d.Cfg.NSPath)
@@ -491,7 +696,7 @@ func (d *DefaultNode) DeployLinks(ctx context.Context) error {
 	for _, l := range d.Links {
 		err := l.Deploy(ctx)
 		if err != nil {
-			return err
+			return &links.DeployError{Link: l, Err: err}
 		}
 	}
 
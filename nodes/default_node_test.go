@@ -1,10 +1,16 @@
 package nodes
 
 import (
+	"archive/tar"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
 )
 
@@ -62,3 +68,188 @@ func TestGenerateConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateConfigKeepsPreviousRendering(t *testing.T) {
+	tests := map[string]struct {
+		templ   string
+		enforce  bool
+		suppress bool
+		// wantErr is set when the config is expected to fail rendering
+		wantErr bool
+		// want is the expected content of the config file
+		want string
+		// wantBackups is the expected content of the numbered backups
+		wantBackups []string
+	}{
+		"failing template": {
+			templ:       "{{ .NoSuchField }}",
+			enforce:     true,
+			wantErr:     true,
+			want:        "previous",
+			wantBackups: []string{"older"},
+		},
+		"empty output": {
+			templ:       "{{ if false }}config{{ end }}\n",
+			enforce:     true,
+			wantErr:     true,
+			want:        "previous",
+			wantBackups: []string{"older"},
+		},
+		"enforce rotates": {
+			templ:       "new",
+			enforce:     true,
+			want:        "new",
+			wantBackups: []string{"previous", "older"},
+		},
+		"suppress never touches": {
+			templ:       "new",
+			enforce:     true,
+			suppress:    true,
+			want:        "previous",
+			wantBackups: []string{"older"},
+		},
+		"existing config kept": {
+			templ:       "new",
+			want:        "previous",
+			wantBackups: []string{"older"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "config")
+
+			if err := os.WriteFile(dst, []byte("previous"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(types.BackupFilePath(dst, 1), []byte("older"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			n := &DefaultNode{
+				Cfg: &types.NodeConfig{
+					ShortName:             "node1",
+					EnforceStartupConfig:  tc.enforce,
+					SuppressStartupConfig: tc.suppress,
+					StartupConfigBackups:  2,
+				},
+			}
+
+			err := n.GenerateConfig(dst, tc.templ)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("got error %v, wanted error: %v", err, tc.wantErr)
+			}
+
+			cnt, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(cnt) != tc.want {
+				t.Errorf("got config %q, wanted %q", cnt, tc.want)
+			}
+
+			for i, want := range tc.wantBackups {
+				cnt, err := os.ReadFile(types.BackupFilePath(dst, i+1))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(cnt) != want {
+					t.Errorf("got backup %d %q, wanted %q", i+1, cnt, want)
+				}
+			}
+
+			if _, err := os.Stat(types.BackupFilePath(dst, len(tc.wantBackups)+1)); err == nil {
+				t.Errorf("unexpected backup %d created", len(tc.wantBackups)+1)
+			}
+		})
+	}
+}
+
+func TestImportImage(t *testing.T) {
+	manifest := `[{"Config":"blobs/sha256/a","RepoTags":["alpine:3","alpine:latest"]}]`
+
+	tarPath := filepath.Join(t.TempDir(), "alpine.tar")
+	f, err := os.Create(tarPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	if err := tw.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0644, Size: int64(len(manifest))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(manifest)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	notFound := errors.New("image not found")
+
+	tests := map[string]struct {
+		policy     types.PullPolicyValue
+		present    bool
+		wantImport bool
+		wantErr    bool
+	}{
+		"present": {
+			policy:  types.PullPolicyIfNotPresent,
+			present: true,
+		},
+		"missing": {
+			policy:     types.PullPolicyIfNotPresent,
+			wantImport: true,
+		},
+		"always": {
+			policy:     types.PullPolicyAlways,
+			present:    true,
+			wantImport: true,
+		},
+		"never-missing": {
+			policy:  types.PullPolicyNever,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+			inspectErr := notFound
+			if tc.present {
+				inspectErr = nil
+			}
+			rt.EXPECT().InspectImage(gomock.Any(), gomock.Any()).Return(&runtime.ImageInfo{}, inspectErr).AnyTimes()
+
+			if tc.wantImport {
+				rt.EXPECT().ImportImage(gomock.Any(), tarPath).Return(nil)
+			}
+
+			d := &DefaultNode{
+				Cfg:     &types.NodeConfig{ShortName: "n1", ImagePullPolicy: tc.policy},
+				Runtime: rt,
+			}
+
+			img, err := d.importImage(context.Background(), tarPath)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got image %q", img)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the node uses the first image of the archive
+			if img != "alpine:3" {
+				t.Fatalf("got image %q, want %q", img, "alpine:3")
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// SetKindEnv merges the env vars a kind sets by default into the env vars of the node config.
+// The kind env vars have the lowest precedence, the later ones take precedence over the earlier ones,
+// and the env vars of the topology, merged with the defaults < kinds < nodes precedence,
+// take precedence over all of them, so that any of the kind env vars can be overridden by the user.
+func SetKindEnv(cfg *types.NodeConfig, kindEnv ...map[string]string) {
+	cfg.Env = utils.MergeStringMaps(append(kindEnv, cfg.Env)...)
+}
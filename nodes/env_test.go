@@ -0,0 +1,44 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestSetKindEnv(t *testing.T) {
+	tests := map[string]struct {
+		env     map[string]string
+		kindEnv []map[string]string
+		want    map[string]string
+	}{
+		"no topology env": {
+			kindEnv: []map[string]string{{"USERNAME": "admin", "PASSWORD": "admin"}},
+			want:    map[string]string{"USERNAME": "admin", "PASSWORD": "admin"},
+		},
+		"topology env overrides kind env": {
+			env:     map[string]string{"PASSWORD": "secret", "FOO": "bar"},
+			kindEnv: []map[string]string{{"USERNAME": "admin", "PASSWORD": "admin"}},
+			want:    map[string]string{"USERNAME": "admin", "PASSWORD": "secret", "FOO": "bar"},
+		},
+		"later kind env overrides earlier": {
+			kindEnv: []map[string]string{
+				{"XR_INTERFACES": "", "XR_EVERY_BOOT_CONFIG": "/cfg"},
+				{"XR_INTERFACES": "linux:eth1"},
+			},
+			want: map[string]string{"XR_INTERFACES": "linux:eth1", "XR_EVERY_BOOT_CONFIG": "/cfg"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &types.NodeConfig{Env: tt.env}
+			SetKindEnv(cfg, tt.kindEnv...)
+
+			if d := cmp.Diff(tt.want, cfg.Env); d != "" {
+				t.Errorf("env mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -22,7 +22,7 @@ var kindnames = []string{"ext-container"}
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(extcont)
-	}, nil)
+	}, nil, nodes.NonContainerKindFields)
 }
 
 type extcont struct {
@@ -7,6 +7,7 @@ package host
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -27,7 +28,7 @@ var kindnames = []string{"host"}
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(host)
-	}, nil)
+	}, nil, nodes.NonContainerKindFields)
 }
 
 type host struct {
@@ -81,7 +82,7 @@ func getOSRelease() string {
 }
 
 // GetContainers returns a basic skeleton of a container to enable graphing of hosts kinds.
-func (*host) GetContainers(_ context.Context) ([]runtime.GenericContainer, error) {
+func (n *host) GetContainers(_ context.Context) ([]runtime.GenericContainer, error) {
 	image := getOSRelease()
 
 	return []runtime.GenericContainer{
@@ -93,6 +94,7 @@ func (*host) GetContainers(_ context.Context) ([]runtime.GenericContainer, error
 			Image:   image,
 			Labels: map[string]string{
 				labels.NodeKind: kindnames[0],
+				labels.NodeName: n.Cfg.ShortName,
 			},
 			Status: "running",
 			NetworkSettings: runtime.GenericMgmtIPs{
@@ -107,6 +109,11 @@ func (*host) GetContainers(_ context.Context) ([]runtime.GenericContainer, error
 	}, nil
 }
 
+// GetContainerStats returns runtime.ErrStatsNotSupported, the host is not a container.
+func (n *host) GetContainerStats(_ context.Context) (*runtime.ContainerStats, error) {
+	return nil, fmt.Errorf("%w for %q node kind", runtime.ErrStatsNotSupported, n.Cfg.Kind)
+}
+
 // RunExec runs commands on the container host.
 func (*host) RunExec(ctx context.Context, e *cExec.ExecCmd) (*cExec.ExecResult, error) {
 	// retireve the command with its arguments
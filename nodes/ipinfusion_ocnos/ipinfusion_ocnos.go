@@ -28,7 +28,8 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(IPInfusionOcNOS)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.DefaultKindFields)
+	nodes.SetSwapDisabledPerKind(kindnames)
 }
 
 type IPInfusionOcNOS struct {
@@ -53,10 +54,10 @@ func (n *IPInfusionOcNOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption)
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
-	n.Cfg.Env = utils.MergeStringMaps(defEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, defEnv)
 
 	n.Cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
-		n.Cfg.Env["USERNAME"], n.Cfg.Env["PASSWORD"], n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
+		n.Cfg.Env["USERNAME"], n.Cfg.Env["PASSWORD"], n.Cfg.GetHostname(), n.Cfg.Env["CONNECTION_MODE"])
 
 	return nil
 }
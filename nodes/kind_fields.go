@@ -0,0 +1,108 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import "strings"
+
+// FieldSupport is the level of support of a node definition field by a node kind.
+type FieldSupport string
+
+const (
+	// FieldSupported marks the fields honored by the kind.
+	FieldSupported FieldSupport = "supported"
+	// FieldIgnored marks the fields that have no effect on the nodes of the kind.
+	FieldIgnored FieldSupport = "ignored"
+	// FieldForbidden marks the fields that break the nodes of the kind.
+	FieldForbidden FieldSupport = "forbidden"
+)
+
+// KindFields is the support matrix of the node definition fields declared by a kind on its registration,
+// keyed by the field name as it is written in the topology file. A single value of a field is declared
+// with the field=value key, e.g. network-mode=host. The fields missing in the matrix are supported.
+type KindFields map[string]FieldSupport
+
+var (
+	// DefaultKindFields is the support matrix of the container based kinds.
+	// The license and the ntp servers are consumed by the few kinds that declare them supported,
+	// the time zone is injected into the linux-like kinds only (see TimezoneKinds)
+	// and the sandbox and kernel images are used by the ignite runtime.
+	DefaultKindFields = KindFields{
+		"license":     FieldIgnored,
+		"ntp-servers": FieldIgnored,
+		"timezone":    FieldIgnored,
+		"sandbox":     FieldIgnored,
+		"kernel":      FieldIgnored,
+	}
+
+	// VRKindFields is the support matrix of the vrnetlab based kinds.
+	// The VM data interfaces are connected to the interfaces of the container network namespace,
+	// so the container can't share the network namespace of the host or another container.
+	VRKindFields = DefaultKindFields.With(KindFields{
+		"network-mode": FieldForbidden,
+	})
+
+	// NonContainerKindFields is the support matrix of the kinds that don't create a container,
+	// like the bridges and the host itself.
+	NonContainerKindFields = DefaultKindFields.With(KindFields{
+		"image":             FieldIgnored,
+		"image-pull-policy": FieldIgnored,
+		"image-arch-map":    FieldIgnored,
+		"image-build":       FieldIgnored,
+		"startup-config":    FieldIgnored,
+		"entrypoint":        FieldIgnored,
+		"cmd":               FieldIgnored,
+		"binds":             FieldIgnored,
+		"ports":             FieldIgnored,
+		"expose":            FieldIgnored,
+		"env":               FieldIgnored,
+		"env-files":         FieldIgnored,
+		"user":              FieldIgnored,
+		"mgmt-ipv4":         FieldIgnored,
+		"mgmt-ipv6":         FieldIgnored,
+		"network-mode":      FieldIgnored,
+		"cpu":               FieldIgnored,
+		"cpu-set":           FieldIgnored,
+		"memory":            FieldIgnored,
+		// there is no container health check to wait for
+		"healthcheck-timeout": FieldIgnored,
+		"healthcheck":         FieldIgnored,
+	})
+)
+
+// Support returns the support level of the given field, the fields missing in the matrix are supported.
+func (k KindFields) Support(field string) FieldSupport {
+	if s, ok := k[field]; ok {
+		return s
+	}
+
+	return FieldSupported
+}
+
+// ValueSupport returns the support level of the given value of the field,
+// which falls back to the support level of the field when the value is not declared in the matrix.
+func (k KindFields) ValueSupport(field, value string) FieldSupport {
+	if value != "" {
+		if s, ok := k[field+"="+strings.ToLower(value)]; ok {
+			return s
+		}
+	}
+
+	return k.Support(field)
+}
+
+// With returns a copy of the matrix with the support levels of the given fields overridden.
+func (k KindFields) With(overrides KindFields) KindFields {
+	m := make(KindFields, len(k)+len(overrides))
+
+	for f, s := range k {
+		m[f] = s
+	}
+
+	for f, s := range overrides {
+		m[f] = s
+	}
+
+	return m
+}
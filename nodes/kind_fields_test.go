@@ -0,0 +1,36 @@
+package nodes
+
+import "testing"
+
+func TestKindFieldsSupport(t *testing.T) {
+	fields := DefaultKindFields.With(KindFields{
+		"license":           FieldSupported,
+		"network-mode=host": FieldForbidden,
+	})
+
+	tests := map[string]struct {
+		field string
+		value string
+		want  FieldSupport
+	}{
+		"undeclared":           {field: "image", value: "alpine", want: FieldSupported},
+		"ignored by default":   {field: "ntp-servers", want: FieldIgnored},
+		"overridden":           {field: "license", value: "license.key", want: FieldSupported},
+		"forbidden value":      {field: "network-mode", value: "host", want: FieldForbidden},
+		"forbidden value case": {field: "network-mode", value: "Host", want: FieldForbidden},
+		"supported value":      {field: "network-mode", value: "none", want: FieldSupported},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := fields.ValueSupport(tt.field, tt.value); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// the overrides don't change the matrix they are applied to
+	if got := DefaultKindFields.Support("license"); got != FieldIgnored {
+		t.Errorf("default license support changed to %q", got)
+	}
+}
@@ -14,16 +14,32 @@ import (
 	"github.com/srl-labs/containerlab/nodes/state"
 	"github.com/srl-labs/containerlab/runtime/ignite"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 	"github.com/weaveworks/ignite/pkg/operations"
 )
 
-var kindnames = []string{"linux"}
+var (
+	kindnames = []string{"linux"}
+	// kindFields are the node definition fields supported by linux, which gets the time zone
+	// and consumes the sandbox and kernel images when run with the ignite runtime.
+	kindFields = nodes.DefaultKindFields.With(nodes.KindFields{
+		"timezone": nodes.FieldSupported,
+		"sandbox":  nodes.FieldSupported,
+		"kernel":   nodes.FieldSupported,
+	})
+	// exposedServices are the container ports of the services linux nodes can expose on the host.
+	exposedServices = map[string]int{
+		nodes.ServiceSSH: 22,
+	}
+)
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(linux)
-	}, nil)
+	}, nil, kindFields)
+	nodes.SetTimezonePerKind(kindnames)
+	nodes.SetExposedServicesPerKind(kindnames, exposedServices)
 }
 
 type linux struct {
@@ -31,6 +47,11 @@ type linux struct {
 	vmChans *operations.VMChannels
 }
 
+// DefaultSysctls enables ipv6 on all linux node interfaces.
+func (*linux) DefaultSysctls() map[string]string {
+	return map[string]string{"net.ipv6.conf.all.disable_ipv6": "0"}
+}
+
 func (n *linux) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Init DefaultNode
 	n.DefaultNode = *nodes.NewDefaultNode(n)
@@ -40,10 +61,10 @@ func (n *linux) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		o(n)
 	}
 
-	// make ipv6 enabled on all linux node interfaces
-	// but not for the nodes with host network mode, as this is not supported on gh action runners
-	if cfg.Sysctls != nil && n.Config().NetworkMode != "host" {
-		cfg.Sysctls["net.ipv6.conf.all.disable_ipv6"] = "0"
+	// linux containers often run several processes without an init system,
+	// thus an init process reaping the zombie processes is injected unless set explicitly
+	if cfg.Init == nil {
+		cfg.Init = utils.BoolPointer(true)
 	}
 
 	return nil
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/srl-labs/containerlab/cert"
@@ -24,16 +25,43 @@ import (
 const (
 	// default connection mode for vrnetlab based containers.
 	VrDefConnMode = "tc"
+	// port of the qemu serial console telnet server in vrnetlab based containers.
+	VrConsolePort = 5000
+	// directory in the node's lab dir where the diagnostics bundles are stored.
+	diagnosticsDir = "diagnostics"
 	// keys for the map returned by GetImages.
 	ImageKey   = "image"
 	KernelKey  = "kernel"
 	SandboxKey = "sandbox"
 )
 
+// names of the well-known NOS services the kinds map to their container ports for the expose shorthand.
+const (
+	ServiceSSH     = "ssh"
+	ServiceGNMI    = "gnmi"
+	ServiceNETCONF = "netconf"
+	ServiceHTTP    = "http"
+	ServiceHTTPS   = "https"
+)
+
 var (
 	// a map of node kinds overriding the default global runtime.
 	NonDefaultRuntimes = map[string]string{}
 
+	// a map of node kinds providing the serial console to the container port of the console.
+	ConsolePorts = map[string]int{}
+
+	// a map of node kinds to the container ports of the services the nodes of the kind can expose,
+	// keyed by the service name.
+	ExposedServices = map[string]map[string]int{}
+
+	// a set of node kinds which containers don't swap when a memory limit is set and memory-swap is not,
+	// so that a NOS exceeding its memory limit fails fast instead of thrashing.
+	SwapDisabledKinds = map[string]struct{}{}
+
+	// a set of linux-like node kinds which containers get the time zone via the TZ env var and the /etc/localtime file.
+	TimezoneKinds = map[string]struct{}{}
+
 	// ErrCommandExecError is an error returned when a command is failed to execute on a given node.
 	ErrCommandExecError = errors.New("command execution error")
 	// ErrContainersNotFound indicated that for a given node no containers where found in the runtime.
@@ -51,6 +79,63 @@ func SetNonDefaultRuntimePerKind(kindnames []string, runtime string) error {
 	return nil
 }
 
+// SetConsolePortPerKind sets the container port of the serial console for kinds that provide it (see vrnetlab kinds).
+func SetConsolePortPerKind(kindnames []string, port int) error {
+	for _, kindname := range kindnames {
+		if _, exists := ConsolePorts[kindname]; exists {
+			return fmt.Errorf("console port for kind with the name '%s' exists already", kindname)
+		}
+		ConsolePorts[kindname] = port
+	}
+	return nil
+}
+
+// SetExposedServicesPerKind sets the container ports of the services the nodes of the kinds can expose
+// on the host with the expose shorthand (see srl kind).
+func SetExposedServicesPerKind(kindnames []string, services map[string]int) error {
+	for _, kindname := range kindnames {
+		if _, exists := ExposedServices[kindname]; exists {
+			return fmt.Errorf("exposed services for kind with the name '%s' exist already", kindname)
+		}
+		ExposedServices[kindname] = services
+	}
+	return nil
+}
+
+// KindExposedServices returns the sorted names of the services the nodes of the kind can expose.
+func KindExposedServices(kindname string) []string {
+	services := make([]string, 0, len(ExposedServices[kindname]))
+	for s := range ExposedServices[kindname] {
+		services = append(services, s)
+	}
+
+	sort.Strings(services)
+
+	return services
+}
+
+// SetSwapDisabledPerKind disables the swap by default for kinds running a NOS (see vrnetlab kinds).
+func SetSwapDisabledPerKind(kindnames []string) error {
+	for _, kindname := range kindnames {
+		if _, exists := SwapDisabledKinds[kindname]; exists {
+			return fmt.Errorf("swap setting for kind with the name '%s' exists already", kindname)
+		}
+		SwapDisabledKinds[kindname] = struct{}{}
+	}
+	return nil
+}
+
+// SetTimezonePerKind enables the time zone injection for linux-like kinds (see linux kind).
+func SetTimezonePerKind(kindnames []string) error {
+	for _, kindname := range kindnames {
+		if _, exists := TimezoneKinds[kindname]; exists {
+			return fmt.Errorf("timezone setting for kind with the name '%s' exists already", kindname)
+		}
+		TimezoneKinds[kindname] = struct{}{}
+	}
+	return nil
+}
+
 type PreDeployParams struct {
 	Cert         *cert.Cert
 	TopologyName string
@@ -71,6 +156,9 @@ type Node interface {
 	Init(*types.NodeConfig, ...NodeOption) error
 	// GetContainers returns a pointer to GenericContainer that the node uses.
 	GetContainers(ctx context.Context) ([]runtime.GenericContainer, error)
+	// GetContainerStats returns the current resource usage of the node container.
+	// runtime.ErrStatsNotSupported is returned by the kinds that don't run in a container.
+	GetContainerStats(ctx context.Context) (*runtime.ContainerStats, error)
 	DeleteNetnsSymlink() (err error)
 	Config() *types.NodeConfig // Config returns the nodes configuration
 	// CheckDeploymentConditions checks if node-scoped deployment conditions are met.
@@ -82,13 +170,27 @@ type Node interface {
 	WithRuntime(runtime.ContainerRuntime) // WithRuntime provides the runtime for the node
 	// CheckInterfaceName checks if a name of the interface referenced in the topology file is correct for this node
 	CheckInterfaceName() error
+	// NOSInterfaceName returns the name the network OS of the node uses for the interface named in the topology file
+	NOSInterfaceName(ifName string) string
 	// VerifyStartupConfig checks for existence of the referenced file and maybe performs additional config checks
 	VerifyStartupConfig(topoDir string) error
+	// CollectDiagnostics runs the kind-specific diagnostics (show tech) command
+	// and stores the resulting bundle in the node's lab directory
+	CollectDiagnostics(context.Context) error
+	// GetConfigPair returns the startup config rendered from the topology and the running config
+	// of the node normalized for the config drift detection.
+	// ErrConfigDiffNotSupported is returned by the kinds that don't support the config drift detection.
+	GetConfigPair(context.Context) (*ConfigPair, error)
 	SaveConfig(context.Context) error            // SaveConfig saves the nodes configuration to an external file
 	Delete(context.Context) error                // Delete triggers the deletion of this node
 	GetImages(context.Context) map[string]string // GetImages returns the images used for this kind
 	GetRuntime() runtime.ContainerRuntime        // GetRuntime returns the nodes assigned runtime
 	GenerateConfig(dst, templ string) error      // Generate the nodes configuration
+	// DefaultSysctls returns the sysctls the kind requires, they are merged into the node sysctls with SetKindSysctls.
+	DefaultSysctls() map[string]string
+	// Shutdown asks the NOS of the node to shut down gracefully and waits until it is down or the context is done.
+	// ErrShutdownNotSupported is returned by the kinds without a native shutdown mechanism.
+	Shutdown(context.Context) error
 	// UpdateConfigWithRuntimeInfo updates node config with runtime info like IP addresses assigned by runtime
 	UpdateConfigWithRuntimeInfo(context.Context) error
 	// RunExec execute a single command for a given node.
@@ -20,9 +20,10 @@ func NewNodeRegistry() *NodeRegistry {
 	}
 }
 
-// Register registers the node' init function for all provided names.
-func (r *NodeRegistry) Register(names []string, initf Initializer, credentials *Credentials) error {
-	newEntry := newRegistryEntry(names, initf, credentials)
+// Register registers the node' init function for all provided names
+// along with the support matrix of the node definition fields of the kind.
+func (r *NodeRegistry) Register(names []string, initf Initializer, credentials *Credentials, fields KindFields) error {
+	newEntry := newRegistryEntry(names, initf, credentials, fields)
 	return r.addEntry(newEntry)
 }
 
@@ -71,6 +72,7 @@ type NodeRegistryEntry struct {
 	nodeKindNames []string
 	initFunction  Initializer
 	credentials   *Credentials
+	fields        KindFields
 }
 
 // Credentials returns entry's credentials.
@@ -82,11 +84,32 @@ func (e *NodeRegistryEntry) Credentials() *Credentials {
 	return e.credentials
 }
 
-func newRegistryEntry(nodeKindNames []string, initFunction Initializer, credentials *Credentials) *NodeRegistryEntry {
+// KindNames returns the names the entry's kind is registered with.
+func (e *NodeRegistryEntry) KindNames() []string {
+	if e == nil {
+		return nil
+	}
+
+	return e.nodeKindNames
+}
+
+// Fields returns the support matrix of the node definition fields of the entry's kind.
+func (e *NodeRegistryEntry) Fields() KindFields {
+	if e == nil {
+		return nil
+	}
+
+	return e.fields
+}
+
+func newRegistryEntry(nodeKindNames []string, initFunction Initializer, credentials *Credentials,
+	fields KindFields,
+) *NodeRegistryEntry {
 	return &NodeRegistryEntry{
 		nodeKindNames: nodeKindNames,
 		initFunction:  initFunction,
 		credentials:   credentials,
+		fields:        fields,
 	}
 }
 
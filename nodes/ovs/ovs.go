@@ -27,7 +27,7 @@ var kindnames = []string{"ovs-bridge"}
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(ovs)
-	}, nil)
+	}, nil, nodes.DefaultKindFields)
 }
 
 type ovs struct {
@@ -101,6 +101,11 @@ func (*ovs) UpdateConfigWithRuntimeInfo(_ context.Context) error { return nil }
 // GetContainers is a noop for bridges.
 func (*ovs) GetContainers(_ context.Context) ([]runtime.GenericContainer, error) { return nil, nil }
 
+// GetContainerStats returns runtime.ErrStatsNotSupported, the ovs bridge is not a container.
+func (n *ovs) GetContainerStats(_ context.Context) (*runtime.ContainerStats, error) {
+	return nil, fmt.Errorf("%w for %q node kind", runtime.ErrStatsNotSupported, n.Cfg.Kind)
+}
+
 // RunExec is noop for ovs kind.
 func (n *ovs) RunExec(_ context.Context, _ *cExec.ExecCmd) (*cExec.ExecResult, error) {
 	log.Warnf("Exec operation is not implemented for kind %q", n.Config().Kind)
@@ -20,13 +20,18 @@ var kindnames = []string{"rare"}
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(rare)
-	}, nil)
+	}, nil, nodes.DefaultKindFields)
 }
 
 type rare struct {
 	nodes.DefaultNode
 }
 
+// DefaultSysctls disables ipv6 on all rare node interfaces, as ipv6 is handled by rare/freertr.
+func (*rare) DefaultSysctls() map[string]string {
+	return map[string]string{"net.ipv6.conf.all.disable_ipv6": "1"}
+}
+
 func (n *rare) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Init DefaultNode
 	n.DefaultNode = *nodes.NewDefaultNode(n)
@@ -36,10 +41,6 @@ func (n *rare) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		o(n)
 	}
 
-	// make ipv6 disabled on all rare node interfaces unconditionally
-	// as ipv6 will be handled by rare/freertr
-	cfg.Sysctls["net.ipv6.conf.all.disable_ipv6"] = "1"
-
 	n.Cfg.Binds = append(n.Cfg.Binds,
 		fmt.Sprint(filepath.Join(n.Cfg.LabDir, "run"), ":/rtr/run"),
 	)
@@ -0,0 +1,132 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// ErrShutdownNotSupported is returned by the nodes which NOS can't be asked to shut down gracefully,
+// the containers of such nodes are stopped by the runtime right away.
+var ErrShutdownNotSupported = errors.New("graceful shutdown is not supported")
+
+const (
+	// address of the qemu monitor telnet server in the network namespace of vrnetlab based containers.
+	vrMonitorAddr = "127.0.0.1:4000"
+	// interval of the checks whether the container of a node being shut down is stopped.
+	shutdownPollInterval = 2 * time.Second
+)
+
+// VRNode is the base of the vrnetlab based nodes which run the NOS in a qemu VM inside the container.
+type VRNode struct {
+	DefaultNode
+}
+
+// Shutdown powers down the VM of the node via the qemu monitor
+// and waits until qemu exits or the context is done.
+func (n *VRNode) Shutdown(ctx context.Context) error {
+	nsPath, err := n.GetRuntime().GetNSPath(ctx, n.OverwriteNode.GetContainerName())
+	if err != nil {
+		return err
+	}
+
+	conn, err := dialInNetns(ctx, nsPath, vrMonitorAddr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the qemu monitor of the %s VM: %w", n.Cfg.ShortName, err)
+	}
+	defer conn.Close()
+
+	log.Infof("Shutting down the %s VM", n.Cfg.ShortName)
+
+	return vrPowerdown(ctx, conn)
+}
+
+// dialInNetns opens the TCP connection to addr from the network namespace nsPath.
+func dialInNetns(ctx context.Context, nsPath, addr string) (net.Conn, error) {
+	netns, err := ns.GetNS(nsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer netns.Close()
+
+	var conn net.Conn
+	// the socket stays in the namespace it was created in once the function returns
+	err = netns.Do(func(_ ns.NetNS) error {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", addr)
+		return err
+	})
+
+	return conn, err
+}
+
+// vrPowerdown sends the ACPI power button event to the VM over the qemu monitor connection
+// and waits until qemu closes the connection on exit or the context is done.
+func vrPowerdown(ctx context.Context, conn net.Conn) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	// closing the connection unblocks the pending reads when the context is done
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if _, err := conn.Write([]byte("system_powerdown\n")); err != nil {
+		return fmt.Errorf("failed to send the powerdown command: %w", err)
+	}
+
+	// the monitor output is drained until qemu exits and closes the connection
+	_, err := io.Copy(io.Discard, conn)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// ShutdownWithCmd runs the shutdown command of the NOS in the container of the node
+// and waits until the container is stopped or the context is done.
+func (d *DefaultNode) ShutdownWithCmd(ctx context.Context, cmd string) error {
+	execCmd, err := exec.NewExecCmdFromString(cmd)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Shutting down the %s node", d.Cfg.ShortName)
+
+	// the command is not waited for, as the container exits while it runs
+	if err := d.RunExecNotWait(ctx, execCmd); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if d.GetRuntime().GetContainerStatus(ctx, d.OverwriteNode.GetContainerName()) != runtime.Running {
+				log.Debugf("The %s node is down", d.Cfg.ShortName)
+				return nil
+			}
+		}
+	}
+}
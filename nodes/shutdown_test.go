@@ -0,0 +1,60 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestVrPowerdown(t *testing.T) {
+	client, monitor := net.Pipe()
+	defer client.Close()
+
+	cmds := make(chan string, 1)
+
+	// the monitor reads the command and closes the connection as qemu exits
+	go func() {
+		r := bufio.NewReader(monitor)
+		cmd, _ := r.ReadString('\n')
+		cmds <- cmd
+		monitor.Close()
+	}()
+
+	if err := vrPowerdown(context.Background(), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cmd := <-cmds; cmd != "system_powerdown\n" {
+		t.Fatalf("got monitor command %q, want system_powerdown", cmd)
+	}
+}
+
+func TestVrPowerdownTimeout(t *testing.T) {
+	client, monitor := net.Pipe()
+	defer client.Close()
+	defer monitor.Close()
+
+	// the monitor accepts the command, but qemu never exits
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			if _, err := monitor.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := vrPowerdown(ctx, client); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -21,7 +21,8 @@ var kindnames = []string{"sonic-vs"}
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(sonic)
-	}, nil)
+	}, nil, nodes.DefaultKindFields)
+	nodes.SetSwapDisabledPerKind(kindnames)
 }
 
 type sonic struct {
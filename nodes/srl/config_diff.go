@@ -0,0 +1,113 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package srl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// runningCfgCmd retrieves the running config in the JSON format.
+var runningCfgCmd = []string{"sr_cli", "-d", "info from running / | as json"}
+
+var (
+	// keyModuleRe matches the YANG module prefix of the JSON keys, e.g. srl_nokia-interfaces:interface.
+	keyModuleRe = regexp.MustCompile(`^[A-Za-z_][\w.-]*:`)
+	// valueModuleRe matches the SR Linux YANG module prefix of the identity values, e.g. srl_nokia-common:bgp.
+	valueModuleRe = regexp.MustCompile(`^srl_nokia-[\w-]+:`)
+)
+
+// GetConfigPair returns the JSON startup config rendered from the topology and the running config of the node.
+// The CLI-formatted startup config is applied over the default config and can't be compared with the running config.
+func (s *srl) GetConfigPair(ctx context.Context) (*nodes.ConfigPair, error) {
+	if s.Cfg.StartupConfig == "" {
+		return nil, fmt.Errorf("%w: no startup-config is defined", nodes.ErrConfigDiffNotSupported)
+	}
+
+	c, err := os.ReadFile(s.Cfg.StartupConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if x := bytes.TrimLeft(c, " \t\r\n"); len(x) == 0 || x[0] != '{' {
+		return nil, fmt.Errorf("%w: startup-config %s is in the CLI format",
+			nodes.ErrConfigDiffNotSupported, s.Cfg.StartupConfig)
+	}
+
+	return s.NewConfigPair(ctx, string(c), exec.NewExecCmdFromSlice(runningCfgCmd), normalizeConfig)
+}
+
+// normalizeConfig normalizes the JSON config of SR Linux for the comparison.
+// The preamble with the creation timestamp is removed, the YANG module prefixes are stripped,
+// the object keys and the list entries are sorted.
+func normalizeConfig(cfg string) (string, error) {
+	var v any
+	if err := json.Unmarshal([]byte(cfg), &v); err != nil {
+		return "", err
+	}
+
+	if m, ok := v.(map[string]any); ok {
+		delete(m, "_preamble")
+	}
+
+	b, err := json.MarshalIndent(normalizeJSONValue(v), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b) + "\n", nil
+}
+
+// normalizeJSONValue strips the YANG module prefixes from the keys and the values of v
+// and sorts the lists of objects. The keys of the objects are sorted by the JSON encoder.
+func normalizeJSONValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[keyModuleRe.ReplaceAllString(k, "")] = normalizeJSONValue(val)
+		}
+
+		return m
+	case []any:
+		l := make([]any, len(v))
+		for i, val := range v {
+			l[i] = normalizeJSONValue(val)
+		}
+
+		// the lists of objects are keyed YANG lists which order is irrelevant,
+		// the leaf-lists keep their order
+		sort.SliceStable(l, func(i, j int) bool {
+			_, iok := l[i].(map[string]any)
+			_, jok := l[j].(map[string]any)
+			if !iok || !jok {
+				return false
+			}
+
+			return jsonString(l[i]) < jsonString(l[j])
+		})
+
+		return l
+	case string:
+		return valueModuleRe.ReplaceAllString(v, "")
+	}
+
+	return v
+}
+
+// jsonString returns the compact JSON encoding of v.
+func jsonString(v any) string {
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
@@ -0,0 +1,41 @@
+package srl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeConfig(t *testing.T) {
+	want, err := os.ReadFile(filepath.Join("test_data", "config_diff", "normalized.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the startup config and the running config differ in the preamble, module prefixes and ordering only
+	for _, f := range []string{"startup.json", "running.json"} {
+		t.Run(f, func(t *testing.T) {
+			cfg, err := os.ReadFile(filepath.Join("test_data", "config_diff", f))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := normalizeConfig(string(cfg))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(string(want), got); d != "" {
+				t.Fatalf("normalized config mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestNormalizeConfigInvalid(t *testing.T) {
+	if _, err := normalizeConfig("set / system name host-name srl1"); err == nil {
+		t.Fatal("expected an error for the CLI-formatted config")
+	}
+}
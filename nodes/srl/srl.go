@@ -63,6 +63,12 @@ set / system gnmi-server unix-socket admin-state enable
 set / system dns network-instance mgmt
 set / system dns server-list [ {{ range $dnsserver := .DNSServers}}{{$dnsserver}} {{ end }}]
 {{- end }}
+{{- if .NTPServers }}
+set / system ntp admin-state enable network-instance mgmt
+{{- range $ntpserver := .NTPServers }}
+set / system ntp server {{ $ntpserver }}
+{{- end }}
+{{- end }}
 set / system json-rpc-server admin-state enable network-instance mgmt http admin-state enable
 set / system json-rpc-server admin-state enable network-instance mgmt https admin-state enable tls-profile clab-profile
 set / system snmp community public
@@ -100,6 +106,13 @@ commit save`
 
 var (
 	KindNames = []string{"srl", "nokia_srlinux"}
+	// kindFields are the node definition fields supported by srl, it consumes the license and
+	// the ntp servers, and can't run in the network namespace of the host.
+	kindFields = nodes.DefaultKindFields.With(nodes.KindFields{
+		"license":           nodes.FieldSupported,
+		"ntp-servers":       nodes.FieldSupported,
+		"network-mode=host": nodes.FieldForbidden,
+	})
 	srlSysctl = map[string]string{
 		"net.ipv4.ip_forward":              "0",
 		"net.ipv6.conf.all.disable_ipv6":   "0",
@@ -139,6 +152,8 @@ var (
 	mgmtServerRdyCmd = `/opt/srlinux/bin/sr_cli -d "info from state system app-management application mgmt_server state | grep running"`
 	// readyForConfigCmd checks the output of a file on srlinux which will be populated once the mgmt server is ready to accept config.
 	readyForConfigCmd = "cat /etc/opt/srlinux/devices/app_ephemeral.mgmt_server.ready_for_config"
+	// shutdownCmd halts the NOS, the container exits once the chassis is halted.
+	shutdownCmd = `/opt/srlinux/bin/sr_cli -d "tools platform chassis reboot halt"`
 
 	srlCfgTpl, _ = template.New("srl-tls-profile").
 			Funcs(gomplate.CreateFuncs(context.Background(), new(data.Data))).
@@ -149,13 +164,26 @@ var (
 		Minor:    10,
 		Revision: 0,
 	}
+
+	// ethernetIfRe matches the ethernet interface names like e1-1 and e1-1-1.
+	ethernetIfRe = regexp.MustCompile(`^e\d+-\d+(-\d+)?$`)
+	// exposedServices are the container ports of the services srl nodes can expose on the host.
+	exposedServices = map[string]int{
+		nodes.ServiceSSH:     22,
+		nodes.ServiceGNMI:    57400,
+		nodes.ServiceNETCONF: 830,
+		nodes.ServiceHTTP:    80,
+		nodes.ServiceHTTPS:   443,
+	}
 )
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	r.Register(KindNames, func() nodes.Node {
 		return new(srl)
-	}, defaultCredentials)
+	}, defaultCredentials, kindFields)
+	nodes.SetSwapDisabledPerKind(KindNames)
+	nodes.SetExposedServicesPerKind(KindNames, exposedServices)
 }
 
 type srl struct {
@@ -173,6 +201,12 @@ type srl struct {
 	swVersion *SrlVersion
 }
 
+// DefaultSysctls returns the sysctls SR Linux requires, the kernel forwarding and the ipv6 autoconfiguration
+// are disabled as they are handled by the NOS.
+func (*srl) DefaultSysctls() map[string]string {
+	return srlSysctl
+}
+
 func (s *srl) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Init DefaultNode
 	s.DefaultNode = *nodes.NewDefaultNode(s)
@@ -211,15 +245,12 @@ func (s *srl) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		s.Cfg.Cmd = "sudo bash -c 'touch /.dockerenv && /opt/srlinux/bin/sr_linux'"
 	}
 
-	s.Cfg.Env = utils.MergeStringMaps(srlEnv, s.Cfg.Env)
+	nodes.SetKindEnv(s.Cfg, srlEnv)
 
 	// if user was not initialized to a value, use root
 	if s.Cfg.User == "" {
 		s.Cfg.User = "0:0"
 	}
-	for k, v := range srlSysctl {
-		s.Cfg.Sysctls[k] = v
-	}
 
 	if s.Cfg.License != "" {
 		// we mount a fixed path node.Labdir/license.key as the license referenced in topo file will be copied to that path
@@ -362,6 +393,11 @@ func (s *srl) SaveConfig(ctx context.Context) error {
 	return nil
 }
 
+// Shutdown halts SR Linux and waits until the node container is stopped.
+func (s *srl) Shutdown(ctx context.Context) error {
+	return s.ShutdownWithCmd(ctx, shutdownCmd)
+}
+
 // Ready returns when the node boot sequence reached the stage when it is ready to accept config commands
 // returns an error if not ready by the expiry of the timer readyTimeout.
 func (s *srl) Ready(ctx context.Context) error {
@@ -569,6 +605,7 @@ type srlTemplateData struct {
 	MgmtMTU    int
 	MgmtIPMTU  int
 	DNSServers []string
+	NTPServers []string
 }
 
 // tplIFace template interface struct.
@@ -596,6 +633,7 @@ func (n *srl) addDefaultConfig(ctx context.Context) error {
 		MgmtMTU:    0,
 		MgmtIPMTU:  0,
 		DNSServers: n.Config().DNS.Servers,
+		NTPServers: n.Config().NTPServers,
 	}
 
 	n.filterSSHPubKeys()
@@ -829,6 +867,16 @@ func (s *srl) CheckInterfaceName() error {
 	return nil
 }
 
+// NOSInterfaceName returns the SR Linux name of the interface named in the topology file,
+// e.g. ethernet-1/1 for e1-1 and ethernet-1/1/1 for the breakout interface e1-1-1.
+func (*srl) NOSInterfaceName(ifName string) string {
+	if !ethernetIfRe.MatchString(ifName) {
+		return ifName
+	}
+
+	return "ethernet-" + strings.ReplaceAll(strings.TrimPrefix(ifName, "e"), "-", "/")
+}
+
 // createRepoFiles creates apt/ym repository files
 // to enable srl nodes to install ndk apps.
 func (s *srl) createRepoFiles() error {
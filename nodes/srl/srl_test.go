@@ -0,0 +1,56 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package srl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNOSInterfaceName(t *testing.T) {
+	tests := map[string]string{
+		"e1-1":    "ethernet-1/1",
+		"e2-34":   "ethernet-2/34",
+		"e1-3-2":  "ethernet-1/3/2",
+		"mgmt0":   "mgmt0",
+		"eth1":    "eth1",
+		"e1-1-x1": "e1-1-x1",
+	}
+
+	n := &srl{}
+
+	for ifName, want := range tests {
+		if got := n.NOSInterfaceName(ifName); got != want {
+			t.Errorf("NOSInterfaceName(%q) = %q, want %q", ifName, got, want)
+		}
+	}
+}
+
+func TestConfigTemplateNTPServers(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := srlCfgTpl.Execute(buf, srlTemplateData{NTPServers: []string{"192.0.2.1", "ntp.example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"set / system ntp admin-state enable network-instance mgmt\n",
+		"set / system ntp server 192.0.2.1\n",
+		"set / system ntp server ntp.example.com\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("rendered config doesn't contain %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := srlCfgTpl.Execute(buf, srlTemplateData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "system ntp") {
+		t.Errorf("rendered config contains ntp settings without ntp servers:\n%s", buf.String())
+	}
+}
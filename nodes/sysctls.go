@@ -0,0 +1,43 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// SetKindSysctls merges the sysctls a kind requires, as returned by the DefaultSysctls method of the node,
+// into the sysctls of the node config. The kind sysctls take precedence over the sysctls of the topology,
+// the overridden values are warned about. The nodes in the host network namespace get no sysctls,
+// since the namespaced kernel parameters of the host can't be set by a container.
+func SetKindSysctls(cfg *types.NodeConfig, kindSysctls map[string]string) {
+	if len(kindSysctls) == 0 || cfg.NetworkMode == "host" {
+		return
+	}
+
+	if cfg.Sysctls == nil {
+		cfg.Sysctls = map[string]string{}
+	}
+
+	keys := make([]string, 0, len(kindSysctls))
+	for k := range kindSysctls {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := kindSysctls[k]
+
+		if uv, ok := cfg.Sysctls[k]; ok && uv != v {
+			log.Warnf("Node %q: sysctl %s=%s is overridden with %s required by the %s kind",
+				cfg.ShortName, k, uv, v, cfg.Kind)
+		}
+
+		cfg.Sysctls[k] = v
+	}
+}
@@ -0,0 +1,48 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestSetKindSysctls(t *testing.T) {
+	tests := map[string]struct {
+		sysctls     map[string]string
+		networkMode string
+		kindSysctls map[string]string
+		want        map[string]string
+	}{
+		"no topology sysctls": {
+			kindSysctls: map[string]string{"net.ipv6.conf.all.disable_ipv6": "0"},
+			want:        map[string]string{"net.ipv6.conf.all.disable_ipv6": "0"},
+		},
+		"kind sysctls override topology sysctls": {
+			sysctls:     map[string]string{"net.ipv6.conf.all.disable_ipv6": "1", "net.ipv4.ip_forward": "1"},
+			kindSysctls: map[string]string{"net.ipv6.conf.all.disable_ipv6": "0"},
+			want:        map[string]string{"net.ipv6.conf.all.disable_ipv6": "0", "net.ipv4.ip_forward": "1"},
+		},
+		"no kind sysctls": {
+			sysctls: map[string]string{"net.ipv4.ip_forward": "1"},
+			want:    map[string]string{"net.ipv4.ip_forward": "1"},
+		},
+		"host network mode": {
+			sysctls:     map[string]string{"net.ipv4.ip_forward": "1"},
+			networkMode: "host",
+			kindSysctls: map[string]string{"net.ipv6.conf.all.disable_ipv6": "0"},
+			want:        map[string]string{"net.ipv4.ip_forward": "1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &types.NodeConfig{Sysctls: tt.sysctls, NetworkMode: tt.networkMode}
+			SetKindSysctls(cfg, tt.kindSysctls)
+
+			if d := cmp.Diff(tt.want, cfg.Sysctls); d != "" {
+				t.Errorf("sysctls mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -32,11 +32,13 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(vrCsr)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.VRKindFields)
+	nodes.SetConsolePortPerKind(kindnames, nodes.VrConsolePort)
+	nodes.SetSwapDisabledPerKind(kindnames)
 }
 
 type vrCsr struct {
-	nodes.DefaultNode
+	nodes.VRNode
 }
 
 func (n *vrCsr) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
@@ -57,7 +59,7 @@ func (n *vrCsr) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
-	n.Cfg.Env = utils.MergeStringMaps(defEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, defEnv)
 
 	// mount config dir to support startup-config functionality
 	n.Cfg.Binds = append(n.Cfg.Binds, fmt.Sprint(path.Join(n.Cfg.LabDir, configDirName), ":/config"))
@@ -68,7 +70,7 @@ func (n *vrCsr) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	}
 
 	n.Cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
-		n.Cfg.Env["USERNAME"], n.Cfg.Env["PASSWORD"], n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
+		n.Cfg.Env["USERNAME"], n.Cfg.Env["PASSWORD"], n.Cfg.GetHostname(), n.Cfg.Env["CONNECTION_MODE"])
 
 	return nil
 }
@@ -28,11 +28,13 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(vrFtosv)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.VRKindFields)
+	nodes.SetConsolePortPerKind(kindnames, nodes.VrConsolePort)
+	nodes.SetSwapDisabledPerKind(kindnames)
 }
 
 type vrFtosv struct {
-	nodes.DefaultNode
+	nodes.VRNode
 }
 
 func (n *vrFtosv) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
@@ -53,7 +55,7 @@ func (n *vrFtosv) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
-	n.Cfg.Env = utils.MergeStringMaps(defEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, defEnv)
 
 	// mount config dir to support startup-config functionality
 	n.Cfg.Binds = append(n.Cfg.Binds, fmt.Sprint(path.Join(n.Cfg.LabDir, configDirName), ":/config"))
@@ -64,7 +66,7 @@ func (n *vrFtosv) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	}
 
 	n.Cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
-		defaultCredentials.GetUsername(), defaultCredentials.GetPassword(), n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
+		defaultCredentials.GetUsername(), defaultCredentials.GetPassword(), n.Cfg.GetHostname(), n.Cfg.Env["CONNECTION_MODE"])
 
 	return nil
 }
@@ -28,11 +28,13 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(vrNXOS)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.VRKindFields)
+	nodes.SetConsolePortPerKind(kindnames, nodes.VrConsolePort)
+	nodes.SetSwapDisabledPerKind(kindnames)
 }
 
 type vrNXOS struct {
-	nodes.DefaultNode
+	nodes.VRNode
 }
 
 func (n *vrNXOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
@@ -55,13 +57,13 @@ func (n *vrNXOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
-	n.Cfg.Env = utils.MergeStringMaps(defEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, defEnv)
 
 	// mount config dir to support startup-config functionality
 	n.Cfg.Binds = append(n.Cfg.Binds, fmt.Sprint(path.Join(n.Cfg.LabDir, configDirName), ":/config"))
 
 	n.Cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
-		defaultCredentials.GetUsername(), defaultCredentials.GetPassword(), n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
+		defaultCredentials.GetUsername(), defaultCredentials.GetPassword(), n.Cfg.GetHostname(), n.Cfg.Env["CONNECTION_MODE"])
 
 	return nil
 }
@@ -30,6 +30,16 @@ import (
 var (
 	kindnames          = []string{"vr-sros", "vr-nokia_sros"}
 	defaultCredentials = nodes.NewCredentials("admin", "admin")
+	// kindFields are the node definition fields supported by vr-sros, which consumes the license.
+	kindFields = nodes.VRKindFields.With(nodes.KindFields{
+		"license": nodes.FieldSupported,
+	})
+	// exposedServices are the container ports of the services vr-sros nodes can expose on the host.
+	exposedServices = map[string]int{
+		nodes.ServiceSSH:     22,
+		nodes.ServiceNETCONF: 830,
+		nodes.ServiceGNMI:    57400,
+	}
 )
 
 const (
@@ -44,11 +54,14 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(vrSROS)
-	}, defaultCredentials)
+	}, defaultCredentials, kindFields)
+	nodes.SetConsolePortPerKind(kindnames, nodes.VrConsolePort)
+	nodes.SetSwapDisabledPerKind(kindnames)
+	nodes.SetExposedServicesPerKind(kindnames, exposedServices)
 }
 
 type vrSROS struct {
-	nodes.DefaultNode
+	nodes.VRNode
 }
 
 func (s *vrSROS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
@@ -72,7 +85,7 @@ func (s *vrSROS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V4_ADDR": s.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": s.Mgmt.IPv6Subnet,
 	}
-	s.Cfg.Env = utils.MergeStringMaps(defEnv, s.Cfg.Env)
+	nodes.SetKindEnv(s.Cfg, defEnv)
 
 	// mount tftpboot dir
 	s.Cfg.Binds = append(s.Cfg.Binds, fmt.Sprint(path.Join(s.Cfg.LabDir, "tftpboot"), ":/tftpboot"))
@@ -82,7 +95,7 @@ func (s *vrSROS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	}
 
 	s.Cfg.Cmd = fmt.Sprintf("--trace --connection-mode %s --hostname %s --variant \"%s\"", s.Cfg.Env["CONNECTION_MODE"],
-		s.Cfg.ShortName,
+		s.Cfg.GetHostname(),
 		s.Cfg.NodeType,
 	)
 
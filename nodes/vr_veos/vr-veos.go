@@ -19,6 +19,12 @@ import (
 var (
 	kindnames          = []string{"vr-veos", "vr-arista_veos"}
 	defaultCredentials = nodes.NewCredentials("admin", "admin")
+	// exposedServices are the container ports of the services vr-veos nodes can expose on the host.
+	exposedServices = map[string]int{
+		nodes.ServiceSSH:     22,
+		nodes.ServiceNETCONF: 830,
+		nodes.ServiceGNMI:    6030,
+	}
 )
 
 const (
@@ -32,11 +38,14 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(vrVEOS)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.VRKindFields)
+	nodes.SetConsolePortPerKind(kindnames, nodes.VrConsolePort)
+	nodes.SetSwapDisabledPerKind(kindnames)
+	nodes.SetExposedServicesPerKind(kindnames, exposedServices)
 }
 
 type vrVEOS struct {
-	nodes.DefaultNode
+	nodes.VRNode
 }
 
 func (n *vrVEOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
@@ -57,7 +66,7 @@ func (n *vrVEOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
-	n.Cfg.Env = utils.MergeStringMaps(defEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, defEnv)
 
 	// mount config dir to support startup-config functionality
 	n.Cfg.Binds = append(n.Cfg.Binds, fmt.Sprint(path.Join(n.Cfg.LabDir, configDirName), ":/config"))
@@ -68,7 +77,7 @@ func (n *vrVEOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	}
 
 	n.Cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
-		defaultCredentials.GetUsername(), defaultCredentials.GetPassword(), n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
+		defaultCredentials.GetUsername(), defaultCredentials.GetPassword(), n.Cfg.GetHostname(), n.Cfg.Env["CONNECTION_MODE"])
 
 	return nil
 }
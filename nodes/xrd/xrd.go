@@ -30,6 +30,12 @@ var (
 
 	//go:embed xrd.cfg
 	cfgTemplate string
+	// exposedServices are the container ports of the services xrd nodes can expose on the host.
+	exposedServices = map[string]int{
+		nodes.ServiceSSH:     22,
+		nodes.ServiceNETCONF: 830,
+		nodes.ServiceGNMI:    57400,
+	}
 )
 
 const (
@@ -40,7 +46,9 @@ const (
 func Register(r *nodes.NodeRegistry) {
 	r.Register(kindnames, func() nodes.Node {
 		return new(xrd)
-	}, defaultCredentials)
+	}, defaultCredentials, nodes.DefaultKindFields)
+	nodes.SetSwapDisabledPerKind(kindnames)
+	nodes.SetExposedServicesPerKind(kindnames, exposedServices)
 }
 
 type xrd struct {
@@ -138,7 +146,7 @@ func (n *xrd) genInterfacesEnv() {
 
 	interfaceEnv := map[string]string{"XR_INTERFACES": interfaceEnvVar}
 
-	n.Cfg.Env = utils.MergeStringMaps(xrdEnv, interfaceEnv, n.Cfg.Env)
+	nodes.SetKindEnv(n.Cfg, xrdEnv, interfaceEnv)
 }
 
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
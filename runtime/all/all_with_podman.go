@@ -4,6 +4,8 @@
 package all
 
 import (
+	_ "github.com/srl-labs/containerlab/runtime/containerd"
+	_ "github.com/srl-labs/containerlab/runtime/cri"
 	_ "github.com/srl-labs/containerlab/runtime/docker"
 	_ "github.com/srl-labs/containerlab/runtime/ignite"
 	_ "github.com/srl-labs/containerlab/runtime/podman"
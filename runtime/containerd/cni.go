@@ -0,0 +1,449 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/containerd"
+	gocni "github.com/containerd/go-cni"
+	"github.com/docker/go-connections/nat"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// cniBinDir is the directory of the CNI plugin binaries setting up the mgmt network.
+	cniBinDir = "/opt/cni/bin"
+	// cniVersion is the CNI spec version of the mgmt network config.
+	cniVersion = "1.0.0"
+	// mgmtIfName is the name of the mgmt interface of the containers.
+	mgmtIfName = "eth0"
+)
+
+// the labels the management addresses, the published ports and the start time of a container are kept in,
+// since containerd doesn't keep the network settings of its containers.
+const (
+	mgmtNetLabel    = "clab-containerd-mgmt-net"
+	mgmtIPv4Label   = "clab-containerd-mgmt-ipv4"
+	mgmtIPv4GwLabel = "clab-containerd-mgmt-ipv4-gw"
+	mgmtIPv6Label   = "clab-containerd-mgmt-ipv6"
+	mgmtIPv6GwLabel = "clab-containerd-mgmt-ipv6-gw"
+	portsLabel      = "clab-containerd-ports"
+	startedAtLabel  = "clab-containerd-started-at"
+	// runtimeLabelPrefix is the prefix of the labels set by the runtime, they are not reported with the container labels.
+	runtimeLabelPrefix = "clab-containerd-"
+)
+
+// mgmtBridgeName returns the name of the bridge backing the mgmt network,
+// the name is truncated to the maximum interface name length.
+func mgmtBridgeName(network string) string {
+	name := "br-" + network
+	if len(name) > utils.IFNAMSIZ-1 {
+		name = name[:utils.IFNAMSIZ-1]
+	}
+
+	return name
+}
+
+// cniConfList returns the CNI config list of the mgmt network: a bridge with the host-local IPAM
+// allocating the addresses from the mgmt subnets and the portmap plugin publishing the ports of the containers.
+func cniConfList(mgmt *types.MgmtNet) ([]byte, error) {
+	var ranges [][]map[string]string
+
+	for _, s := range []struct{ subnet, gw, rng string }{
+		{mgmt.IPv4Subnet, mgmt.IPv4Gw, mgmt.IPv4Range},
+		{mgmt.IPv6Subnet, mgmt.IPv6Gw, mgmt.IPv6Range},
+	} {
+		if s.subnet == "" {
+			continue
+		}
+
+		r := map[string]string{"subnet": s.subnet}
+		if s.gw != "" {
+			r["gateway"] = s.gw
+		}
+
+		if s.rng != "" {
+			first, last, err := rangeBounds(s.rng)
+			if err != nil {
+				return nil, err
+			}
+
+			r["rangeStart"] = first
+			r["rangeEnd"] = last
+		}
+
+		ranges = append(ranges, []map[string]string{r})
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("mgmt network %q has no subnets", mgmt.Network)
+	}
+
+	bridge := map[string]interface{}{
+		"type":         "bridge",
+		"bridge":       mgmt.Bridge,
+		"isGateway":    true,
+		"ipMasq":       mgmt.ExternalAccess == nil || *mgmt.ExternalAccess,
+		"hairpinMode":  true,
+		"capabilities": map[string]bool{"ips": true, "mac": true},
+		"ipam": map[string]interface{}{
+			"type":   "host-local",
+			"ranges": ranges,
+			"routes": defaultRoutes(mgmt),
+		},
+	}
+
+	if mgmt.MTU > 0 {
+		bridge["mtu"] = mgmt.MTU
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"cniVersion": cniVersion,
+		"name":       mgmt.Network,
+		"plugins": []interface{}{
+			bridge,
+			map[string]interface{}{
+				"type":         "portmap",
+				"capabilities": map[string]bool{"portMappings": true},
+			},
+		},
+	})
+}
+
+// defaultRoutes returns the default routes of the address families of the mgmt network.
+func defaultRoutes(mgmt *types.MgmtNet) []map[string]string {
+	var routes []map[string]string
+
+	if mgmt.IPv4Subnet != "" {
+		routes = append(routes, map[string]string{"dst": "0.0.0.0/0"})
+	}
+
+	if mgmt.IPv6Subnet != "" {
+		routes = append(routes, map[string]string{"dst": "::/0"})
+	}
+
+	return routes
+}
+
+// rangeBounds returns the first and the last usable addresses of the range prefix.
+// The network address of an IPv4 range and its broadcast address are excluded.
+func rangeBounds(prefix string) (string, string, error) {
+	_, ipnet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid range %q: %w", prefix, err)
+	}
+
+	first := make(net.IP, len(ipnet.IP))
+	last := make(net.IP, len(ipnet.IP))
+
+	for i := range ipnet.IP {
+		first[i] = ipnet.IP[i]
+		last[i] = ipnet.IP[i] | ^ipnet.Mask[i]
+	}
+
+	if ipnet.IP.To4() != nil {
+		first[len(first)-1]++
+		last[len(last)-1]--
+	}
+
+	return first.String(), last.String(), nil
+}
+
+// cni returns the CNI instance setting up the mgmt network of the containers.
+func (r *ContainerdRuntime) cni() (gocni.CNI, error) {
+	confList, err := cniConfList(r.mgmt)
+	if err != nil {
+		return nil, err
+	}
+
+	return gocni.New(
+		gocni.WithMinNetworkCount(1),
+		gocni.WithPluginDir([]string{cniBinDir}),
+		gocni.WithInterfacePrefix("eth"),
+		gocni.WithConfListBytes(confList),
+	)
+}
+
+// CreateNet checks the mgmt network config, the bridge of the network is created by the CNI bridge plugin
+// when the first container is attached to the network.
+func (r *ContainerdRuntime) CreateNet(_ context.Context) error {
+	log.Debugf("Trying to create a management network with params %+v", r.mgmt)
+
+	if r.mgmt.Bridge == "" {
+		r.mgmt.Bridge = mgmtBridgeName(r.mgmt.Network)
+	}
+
+	_, err := r.cni()
+
+	return err
+}
+
+// DeleteNet deletes the bridge of the mgmt network, unless the network is kept.
+func (r *ContainerdRuntime) DeleteNet(_ context.Context) error {
+	// Skip if "keep mgmt" is set
+	log.Debugf("Method DeleteNet was called with runtime inputs %+v and net settings %+v", r, r.mgmt)
+	if r.config.KeepMgmtNet {
+		return nil
+	}
+
+	br, err := netlink.LinkByName(r.mgmt.Bridge)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+
+		return err
+	}
+
+	log.Debugf("trying to delete mgmt network bridge %v", r.mgmt.Bridge)
+
+	if err := netlink.LinkDel(br); err != nil {
+		return fmt.Errorf("error while trying to remove a mgmt network bridge %s: %w", r.mgmt.Bridge, err)
+	}
+
+	return nil
+}
+
+// InspectMgmtNet returns the details of the mgmt network with an existing bridge.
+// Nil is returned if the bridge doesn't exist.
+func (r *ContainerdRuntime) InspectMgmtNet(_ context.Context) (*runtime.NetworkInfo, error) {
+	br, err := netlink.LinkByName(r.mgmt.Bridge)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	netInfo := &runtime.NetworkInfo{
+		Name: r.mgmt.Network,
+		MTU:  br.Attrs().MTU,
+	}
+
+	addrs, err := netlink.AddrList(br, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range addrs {
+		// the gateway address is assigned to the bridge from the network subnet
+		subnet := &net.IPNet{IP: a.IP.Mask(a.Mask), Mask: a.Mask}
+
+		if a.IP.To4() != nil {
+			netInfo.IPv4Subnet = subnet.String()
+			netInfo.IPv4Gateway = a.IP.String()
+
+			continue
+		}
+
+		if a.IP.IsGlobalUnicast() {
+			netInfo.IPv6Subnet = subnet.String()
+			netInfo.IPv6Gateway = a.IP.String()
+		}
+	}
+
+	return netInfo, nil
+}
+
+// cniNamespaceOpts returns the CNI options of the node: the static mgmt addresses, the MAC address
+// and the published ports.
+func cniNamespaceOpts(node *types.NodeConfig) []gocni.NamespaceOpts {
+	var opts []gocni.NamespaceOpts
+
+	var ips []string
+	for _, ip := range []string{node.MgmtIPv4Address, node.MgmtIPv6Address} {
+		if ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+
+	if len(ips) > 0 {
+		opts = append(opts, gocni.WithCapability("ips", ips))
+	}
+
+	if node.MacAddress != "" {
+		opts = append(opts, gocni.WithCapability("mac", node.MacAddress))
+	}
+
+	if pm := portMappings(node.PortBindings); len(pm) > 0 {
+		opts = append(opts, gocni.WithCapabilityPortMap(pm))
+	}
+
+	return opts
+}
+
+// portMappings returns the CNI port mappings of the published ports.
+func portMappings(bindings nat.PortMap) []gocni.PortMapping {
+	var pm []gocni.PortMapping
+
+	for port, bs := range bindings {
+		for _, b := range bs {
+			hostPort, err := strconv.Atoi(b.HostPort)
+			if err != nil {
+				log.Warnf("invalid host port %q of the published port %s: %v", b.HostPort, port, err)
+				continue
+			}
+
+			pm = append(pm, gocni.PortMapping{
+				HostPort:      int32(hostPort),
+				ContainerPort: int32(port.Int()),
+				Protocol:      port.Proto(),
+				HostIP:        b.HostIP,
+			})
+		}
+	}
+
+	return pm
+}
+
+// attachMgmtNet attaches the netns of the container task with the pid to the mgmt network
+// and keeps the assigned addresses and the published ports in the container labels.
+func (r *ContainerdRuntime) attachMgmtNet(ctx context.Context, cont containerd.Container, node *types.NodeConfig,
+	pid uint32,
+) error {
+	cni, err := r.cni()
+	if err != nil {
+		return err
+	}
+
+	nsPath := fmt.Sprintf("/proc/%d/ns/net", pid)
+
+	res, err := cni.Setup(ctx, cont.ID(), nsPath, cniNamespaceOpts(node)...)
+	if err != nil {
+		return err
+	}
+
+	labels := mgmtLabels(res, r.mgmt.Network)
+
+	ports := make([]*types.GenericPortBinding, 0)
+	for _, m := range portMappings(node.PortBindings) {
+		ports = append(ports, &types.GenericPortBinding{
+			HostIP:        m.HostIP,
+			HostPort:      int(m.HostPort),
+			ContainerPort: int(m.ContainerPort),
+			Protocol:      m.Protocol,
+		})
+	}
+
+	b, err := json.Marshal(ports)
+	if err != nil {
+		return err
+	}
+
+	labels[portsLabel] = string(b)
+
+	if _, err := cont.SetLabels(ctx, labels); err != nil {
+		return err
+	}
+
+	// TX checksum disabling will be done here since the mgmt bridge
+	// doesn't exist before the first container is attached to it
+	if err := utils.EthtoolTXOff(r.mgmt.Bridge); err != nil {
+		log.Warnf("failed to disable TX checksum offload for interface %q: %v", r.mgmt.Bridge, err)
+	}
+
+	return nil
+}
+
+// mgmtLabels returns the labels with the mgmt addresses assigned by CNI in the prefix format.
+func mgmtLabels(res *gocni.Result, network string) map[string]string {
+	labels := map[string]string{mgmtNetLabel: network}
+
+	for _, raw := range res.Raw() {
+		for _, ipc := range raw.IPs {
+			gw := ""
+			if ipc.Gateway != nil {
+				gw = ipc.Gateway.String()
+			}
+
+			if ipc.Address.IP.To4() != nil {
+				labels[mgmtIPv4Label] = ipc.Address.String()
+				labels[mgmtIPv4GwLabel] = gw
+
+				continue
+			}
+
+			labels[mgmtIPv6Label] = ipc.Address.String()
+			labels[mgmtIPv6GwLabel] = gw
+		}
+	}
+
+	return labels
+}
+
+// detachMgmtNet detaches the container from the mgmt network releasing its addresses and published ports.
+// The errors are logged only, since the container is removed anyway.
+func (r *ContainerdRuntime) detachMgmtNet(ctx context.Context, cont containerd.Container, task containerd.Task) {
+	labels, err := cont.Labels(ctx)
+	if err != nil {
+		log.Debugf("failed to get the labels of container %q: %v", cont.ID(), err)
+		return
+	}
+
+	if _, ok := labels[mgmtNetLabel]; !ok {
+		return
+	}
+
+	cni, err := r.cni()
+	if err != nil {
+		log.Warnf("failed to detach container %q from the mgmt network: %v", cont.ID(), err)
+		return
+	}
+
+	// the netns of a stopped container is gone, the plugins release the addresses and the ports anyway
+	nsPath := ""
+	if status, err := task.Status(ctx); err == nil && status.Status != containerd.Stopped && task.Pid() != 0 {
+		nsPath = fmt.Sprintf("/proc/%d/ns/net", task.Pid())
+	}
+
+	if err := cni.Remove(ctx, cont.ID(), nsPath); err != nil {
+		log.Warnf("failed to detach container %q from the mgmt network: %v", cont.ID(), err)
+	}
+}
+
+// mgmtIPs returns the mgmt network settings of a container kept in its labels.
+func mgmtIPs(labels map[string]string) runtime.GenericMgmtIPs {
+	ips := runtime.GenericMgmtIPs{
+		Network: labels[mgmtNetLabel],
+		IPv4Gw:  labels[mgmtIPv4GwLabel],
+		IPv6Gw:  labels[mgmtIPv6GwLabel],
+	}
+
+	if ip, ipnet, err := net.ParseCIDR(labels[mgmtIPv4Label]); err == nil {
+		ips.IPv4addr = ip.String()
+		ips.IPv4pLen, _ = ipnet.Mask.Size()
+	}
+
+	if ip, ipnet, err := net.ParseCIDR(labels[mgmtIPv6Label]); err == nil {
+		ips.IPv6addr = ip.String()
+		ips.IPv6pLen, _ = ipnet.Mask.Size()
+	}
+
+	return ips
+}
+
+// userLabels returns the labels of a container without the labels set by the runtime.
+func userLabels(labels map[string]string) map[string]string {
+	res := make(map[string]string, len(labels))
+
+	for k, v := range labels {
+		if !strings.HasPrefix(k, runtimeLabelPrefix) {
+			res[k] = v
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,570 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/platforms"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	RuntimeName    = "containerd"
+	defaultTimeout = 120 * time.Second
+	// defaultSocket is the path of the containerd API socket used when the socket is not set by a user.
+	defaultSocket = "/run/containerd/containerd.sock"
+	// containerdNamespace is the containerd namespace the lab containers and images are managed in.
+	containerdNamespace = "clab"
+	// certsDir is the directory containerd resolver reads the registry TLS material from.
+	certsDir = "/etc/containerd/certs.d"
+	// stateDir keeps the files containerd doesn't manage, e.g. the hosts files and the logs of the containers.
+	stateDir = "/var/lib/containerlab/containerd"
+)
+
+// versionRequirements are the containerd versions required by containerlab and by the features it uses.
+var versionRequirements = []runtime.VersionRequirement{
+	// the v1.7 client talks to the containerd 1.6 API and newer
+	{MinVersion: "1.6.0"},
+}
+
+type ContainerdRuntime struct {
+	config *runtime.RuntimeConfig
+	mgmt   *types.MgmtNet
+	client *containerd.Client
+}
+
+func init() {
+	runtime.Register(RuntimeName, func() runtime.ContainerRuntime {
+		return &ContainerdRuntime{
+			config: &runtime.RuntimeConfig{},
+			mgmt:   &types.MgmtNet{},
+		}
+	})
+}
+
+// Init is used to initialize our runtime struct by calling all methods received from the caller
+// and connects to the containerd socket.
+func (r *ContainerdRuntime) Init(opts ...runtime.RuntimeOption) error {
+	for _, f := range opts {
+		f(r)
+	}
+
+	socket := defaultSocket
+	if r.config.Socket != "" {
+		socket = strings.TrimPrefix(r.config.Socket, "unix://")
+	}
+
+	var err error
+	r.client, err = containerd.New(socket, containerd.WithDefaultNamespace(containerdNamespace))
+	if err != nil {
+		return fmt.Errorf("failed to connect to containerd socket %s: %w", socket, err)
+	}
+
+	r.config.VerifyLinkParams = links.NewVerifyLinkParams()
+	r.config.VerifyLinkParams.RunBridgeExistsCheck = false
+
+	return nil
+}
+
+func (r *ContainerdRuntime) Mgmt() *types.MgmtNet { return r.mgmt }
+
+func (r *ContainerdRuntime) WithConfig(cfg *runtime.RuntimeConfig) {
+	log.Debugf("Containerd method WithConfig was called with cfg params: %+v", cfg)
+	// Check for nil pointers on input
+	if cfg == nil {
+		log.Errorf("Method WithConfig has received a nil pointer")
+		return
+	}
+	r.config = cfg
+	if r.config.Timeout <= 0 {
+		r.config.Timeout = defaultTimeout
+	}
+}
+
+// WithMgmtNet assigns struct mgmt net parameters to the runtime struct.
+func (r *ContainerdRuntime) WithMgmtNet(net *types.MgmtNet) {
+	// Check for nil pointers on input
+	if net == nil {
+		log.Errorf("Method WithMgmtNet has received a nil pointer")
+		return
+	}
+	log.Debugf("Containerd method WithMgmtNet was called with net params: %+v", net)
+	r.mgmt = net
+	if r.mgmt.Bridge == "" && r.mgmt.Network != "" {
+		r.mgmt.Bridge = mgmtBridgeName(r.mgmt.Network)
+	}
+}
+
+// WithKeepMgmtNet defines that we shouldn't delete mgmt network(s).
+func (r *ContainerdRuntime) WithKeepMgmtNet() {
+	r.config.KeepMgmtNet = true
+}
+
+// Config returns the runtime configuration options.
+func (r *ContainerdRuntime) Config() runtime.RuntimeConfig {
+	return *r.config
+}
+
+// GetName returns runtime name as a string.
+func (*ContainerdRuntime) GetName() string {
+	return RuntimeName
+}
+
+// CheckVersion checks the containerd version against the versions
+// required by containerlab and by the features used by the nodes.
+func (r *ContainerdRuntime) CheckVersion(ctx context.Context, nodes []*types.NodeConfig) error {
+	nctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	v, err := r.client.Version(nctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the containerd version: %w", err)
+	}
+
+	log.Debugf("containerd version %s, revision %s", v.Version, v.Revision)
+
+	return runtime.CheckVersionRequirements(RuntimeName, strings.TrimPrefix(v.Version, "v"),
+		"containerd "+v.Version, versionRequirements, nodes)
+}
+
+// CreateContainer creates a containerd container with its spec and snapshot, but does not start it.
+// The container is started by StartContainer which creates its task.
+func (r *ContainerdRuntime) CreateContainer(ctx context.Context, node *types.NodeConfig) (string, error) {
+	log.Infof("Creating container: %q", node.ShortName)
+
+	img, err := r.localImage(ctx, node.Image, node.Platform)
+	if err != nil {
+		return "", fmt.Errorf("image %s of node %q: %w", node.Image, node.ShortName, err)
+	}
+
+	if err := writeContainerFiles(node); err != nil {
+		return "", err
+	}
+
+	specOpts, err := r.specOpts(ctx, node, img)
+	if err != nil {
+		return "", fmt.Errorf("error while trying to create a container spec for node %q: %w", node.LongName, err)
+	}
+
+	cont, err := r.client.NewContainer(ctx, node.LongName,
+		containerd.WithImage(img),
+		containerd.WithNewSnapshot(node.LongName, img),
+		containerd.WithContainerLabels(node.Labels),
+		containerd.WithNewSpec(specOpts...),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	log.Debugf("Created container %q", cont.ID())
+
+	return cont.ID(), nil
+}
+
+// StartContainer creates the task of the container created by CreateContainer and starts it.
+// The container is attached to the mgmt network before its process starts.
+func (r *ContainerdRuntime) StartContainer(ctx context.Context, cID string, node runtime.Node) (interface{}, error) {
+	cfg := node.Config()
+
+	cont, err := r.client.LoadContainer(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Start container: %q", cfg.LongName)
+
+	task, err := cont.NewTask(ctx, cio.LogFile(containerFile(cID, logFile)))
+	if err != nil {
+		return nil, fmt.Errorf("error while creating a task of container %q: %w", cfg.LongName, err)
+	}
+
+	// only the containers with their own netns are attached to the mgmt network
+	if usesMgmtNet(cfg.NetworkMode) {
+		if err := r.attachMgmtNet(ctx, cont, cfg, task.Pid()); err != nil {
+			if _, dErr := task.Delete(ctx, containerd.WithProcessKill); dErr != nil {
+				log.Debugf("failed to delete task of container %q: %v", cfg.LongName, dErr)
+			}
+
+			return nil, fmt.Errorf("error while attaching container %q to the mgmt network: %w", cfg.LongName, err)
+		}
+	}
+
+	if err := task.Start(ctx); err != nil {
+		return nil, fmt.Errorf("error while starting a container %q: %w", cfg.LongName, err)
+	}
+
+	if _, err := cont.SetLabels(ctx, map[string]string{
+		startedAtLabel: time.Now().Format(time.RFC3339Nano),
+	}); err != nil {
+		log.Debugf("failed to set the start time label of container %q: %v", cfg.LongName, err)
+	}
+
+	log.Debugf("Container started: %q", cfg.LongName)
+
+	return nil, r.postStartActions(ctx, cID, cfg)
+}
+
+// postStartActions performs misc. tasks that are needed after the container starts.
+func (r *ContainerdRuntime) postStartActions(ctx context.Context, cID string, cfg *types.NodeConfig) error {
+	var err error
+	cfg.NSPath, err = r.GetNSPath(ctx, cID)
+	if err != nil {
+		return err
+	}
+
+	return utils.LinkContainerNS(cfg.NSPath, cfg.LongName)
+}
+
+// task returns the task of the container cID.
+func (r *ContainerdRuntime) task(ctx context.Context, cID string) (containerd.Container, containerd.Task, error) {
+	cont, err := r.client.LoadContainer(ctx, cID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	task, err := cont.Task(ctx, nil)
+	if err != nil {
+		return cont, nil, err
+	}
+
+	return cont, task, nil
+}
+
+// StopContainer sends the signal, SIGTERM when empty, to the process of the container and kills it
+// if it doesn't exit within the runtime timeout. The stopped task is kept to report the exit status.
+func (r *ContainerdRuntime) StopContainer(ctx context.Context, cID, signal string) error {
+	sig, err := runtime.ParseStopSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	_, task, err := r.task(ctx, cID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.killTask(ctx, task, sig); err == nil || sig == syscall.SIGKILL {
+		return err
+	}
+
+	return r.killTask(ctx, task, syscall.SIGKILL)
+}
+
+// killTask sends the signal to the process of the task and waits for it to exit within the runtime timeout.
+func (r *ContainerdRuntime) killTask(ctx context.Context, task containerd.Task, sig syscall.Signal) error {
+	status, err := task.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Status == containerd.Stopped {
+		return nil
+	}
+
+	exitC, err := task.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := task.Kill(ctx, sig); err != nil {
+		return err
+	}
+
+	select {
+	case <-exitC:
+		return nil
+	case <-time.After(r.config.Timeout):
+		return fmt.Errorf("container %s did not exit within %s after %s", task.ID(), r.config.Timeout, sig)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PauseContainer pauses the process of the container.
+func (r *ContainerdRuntime) PauseContainer(ctx context.Context, cID string) error {
+	_, task, err := r.task(ctx, cID)
+	if err != nil {
+		return err
+	}
+
+	return task.Pause(ctx)
+}
+
+// UnpauseContainer resumes the process of the paused container.
+func (r *ContainerdRuntime) UnpauseContainer(ctx context.Context, cID string) error {
+	_, task, err := r.task(ctx, cID)
+	if err != nil {
+		return err
+	}
+
+	return task.Resume(ctx)
+}
+
+// DeleteContainer detaches the container from the mgmt network and removes its task, snapshot and files.
+// With the graceful shutdown the container process is terminated and waited for before being killed.
+func (r *ContainerdRuntime) DeleteContainer(ctx context.Context, cID string) error {
+	cont, task, err := r.task(ctx, cID)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+
+	if cont == nil {
+		return err
+	}
+
+	if task != nil {
+		if r.config.GracefulShutdown {
+			log.Infof("Stopping container: %s", cID)
+
+			if err := r.killTask(ctx, task, syscall.SIGTERM); err != nil {
+				log.Errorf("could not stop container %q: %v", cID, err)
+			}
+		}
+
+		r.detachMgmtNet(ctx, cont, task)
+
+		log.Debugf("Removing task of container: %s", cID)
+
+		if _, err := task.Delete(ctx, containerd.WithProcessKill); err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	log.Debugf("Removing container: %s", cID)
+
+	if err := cont.Delete(ctx, containerd.WithSnapshotCleanup); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(containerDir(cID)); err != nil {
+		log.Warnf("failed to remove the files of container %q: %v", cID, err)
+	}
+
+	log.Infof("Removed container: %s", cID)
+
+	return nil
+}
+
+// GetNSPath returns the netns path of the container using the pid of its task.
+func (r *ContainerdRuntime) GetNSPath(ctx context.Context, cID string) (string, error) {
+	_, task, err := r.task(ctx, cID)
+	if err != nil {
+		return "", err
+	}
+
+	if task.Pid() == 0 {
+		return "", fmt.Errorf("container %s is not running", cID)
+	}
+
+	return "/proc/" + strconv.Itoa(int(task.Pid())) + "/ns/net", nil
+}
+
+// GetHostsPath returns fs path to a file which is mounted as /etc/hosts into a given container.
+func (*ContainerdRuntime) GetHostsPath(_ context.Context, cID string) (string, error) {
+	hostsPath := containerFile(cID, hostsFile)
+	if !utils.FileExists(hostsPath) {
+		return "", fmt.Errorf("hosts file of container %s not found", cID)
+	}
+
+	log.Debugf("Method GetHostsPath was called with a resulting path %q", hostsPath)
+
+	return hostsPath, nil
+}
+
+// GetContainerStatus retrieves the ContainerStatus of the named container.
+func (r *ContainerdRuntime) GetContainerStatus(ctx context.Context, cID string) runtime.ContainerStatus {
+	cont, task, err := r.task(ctx, cID)
+	if cont == nil {
+		return runtime.NotFound
+	}
+
+	// the container without a task is created, but was never started
+	if err != nil {
+		return runtime.Stopped
+	}
+
+	status, err := task.Status(ctx)
+	if err != nil {
+		return runtime.NotFound
+	}
+
+	if status.Status == containerd.Running {
+		return runtime.Running
+	}
+
+	return runtime.Stopped
+}
+
+// Exec executes cmd on container identified with id and returns stdout, stderr bytes and an error.
+func (r *ContainerdRuntime) Exec(ctx context.Context, cID string, execCmd *exec.ExecCmd) (*exec.ExecResult, error) {
+	var stdout, stderr bytes.Buffer
+
+	p, err := r.execProcess(ctx, cID, execCmd, cio.NewCreator(cio.WithStreams(nil, &stdout, &stderr)))
+	if err != nil {
+		log.Errorf("failed to create exec in container %q: %v", cID, err)
+		return nil, err
+	}
+	defer p.Delete(ctx)
+
+	exitC, err := p.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.Start(ctx); err != nil {
+		log.Errorf("failed to start exec in container %q: %v", cID, err)
+		return nil, err
+	}
+
+	status := <-exitC
+
+	code, _, err := status.Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// the output is copied until the process closes its streams
+	p.IO().Wait()
+
+	log.Debugf("Exec in the container %q got stdout %q and stderr %q", cID, stdout.Bytes(), stderr.Bytes())
+
+	execResult := exec.NewExecResult(execCmd)
+	execResult.SetStdOut(stdout.Bytes())
+	execResult.SetStdErr(stderr.Bytes())
+	execResult.SetReturnCode(int(code))
+
+	return execResult, nil
+}
+
+// ExecNotWait executes cmd on container identified with id but doesn't wait for output nor attaches stdout/err.
+func (r *ContainerdRuntime) ExecNotWait(ctx context.Context, cID string, execCmd *exec.ExecCmd) error {
+	p, err := r.execProcess(ctx, cID, execCmd, cio.NullIO)
+	if err != nil {
+		log.Errorf("failed to create exec in container %q: %v", cID, err)
+		return err
+	}
+
+	return p.Start(ctx)
+}
+
+// execProcess creates the exec process running cmd in the container with the process spec of the container.
+func (r *ContainerdRuntime) execProcess(ctx context.Context, cID string, execCmd *exec.ExecCmd,
+	ioCreator cio.Creator,
+) (containerd.Process, error) {
+	cont, task, err := r.task(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := cont.Spec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pspec := spec.Process
+	pspec.Terminal = false
+	pspec.Args = execCmd.GetCmd()
+
+	return task.Exec(ctx, "exec-"+uuid.NewString()[:8], pspec, ioCreator)
+}
+
+// CopyFromContainer copies the file by srcPath in the container to the dstPath on the host.
+// The file is read from the root filesystem of the running container.
+func (r *ContainerdRuntime) CopyFromContainer(ctx context.Context, cID, srcPath, dstPath string) error {
+	_, task, err := r.task(ctx, cID)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	src := filepath.Join("/proc", strconv.Itoa(int(task.Pid())), "root", srcPath)
+
+	fi, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	if err := utils.CopyFile(src, dstPath, fi.Mode()); err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	return nil
+}
+
+// IsContainerOOMKilled reports whether the container was killed by the OOM killer.
+// containerd doesn't keep the OOM kills in the task status, so they are read from the cgroup memory events.
+func (r *ContainerdRuntime) IsContainerOOMKilled(ctx context.Context, cID string) (bool, error) {
+	_, task, err := r.task(ctx, cID)
+	if err != nil {
+		return false, err
+	}
+
+	m, err := r.taskMetrics(ctx, task)
+	if err != nil {
+		return false, err
+	}
+
+	return m.oomKills > 0, nil
+}
+
+// localImage returns the local image by its name, the image of the platform, if set, is selected.
+func (r *ContainerdRuntime) localImage(ctx context.Context, imageName, platform string) (containerd.Image, error) {
+	i, err := r.client.ImageService().Get(ctx, utils.GetCanonicalImageName(imageName))
+	if err != nil {
+		return nil, err
+	}
+
+	if platform == "" {
+		return containerd.NewImage(r.client, i), nil
+	}
+
+	p, err := utils.ParsePlatform(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return containerd.NewImageWithPlatform(r.client, i, platforms.Only(*p)), nil
+}
+
+// InspectImage returns the details of the local container image.
+func (r *ContainerdRuntime) InspectImage(ctx context.Context, imageName string) (*runtime.ImageInfo, error) {
+	img, err := r.localImage(ctx, imageName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := img.Size(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := img.Spec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtime.ImageInfo{
+		ID:   img.Target().Digest.String(),
+		Size: size,
+		Env:  spec.Config.Env,
+	}, nil
+}
@@ -0,0 +1,199 @@
+package containerd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestBuildFilterString(t *testing.T) {
+	tests := map[string]struct {
+		filters []*types.GenericFilter
+		want    string
+	}{
+		"no-filters": {},
+		"name": {
+			filters: []*types.GenericFilter{
+				{FilterType: "name", Match: "clab-lab-node1"},
+			},
+			want: `id=="clab-lab-node1"`,
+		},
+		"labels": {
+			filters: []*types.GenericFilter{
+				{FilterType: "label", Field: "containerlab", Operator: "=", Match: "lab"},
+				{FilterType: "label", Field: "clab-node-kind", Operator: "!=", Match: "linux"},
+				{FilterType: "label", Field: "clab-topo-file", Operator: "exists"},
+			},
+			want: `labels."containerlab"=="lab",labels."clab-node-kind"!="linux",labels."clab-topo-file"`,
+		},
+		"unsupported-operator": {
+			filters: []*types.GenericFilter{
+				{FilterType: "label", Field: "containerlab", Operator: ">", Match: "lab"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := buildFilterString(tt.filters); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"containerlab": "lab"}
+
+	tests := map[string]struct {
+		filters []*types.GenericFilter
+		want    bool
+	}{
+		"no-filters": {want: true},
+		"equal": {
+			filters: []*types.GenericFilter{{FilterType: "label", Field: "containerlab", Operator: "=", Match: "lab"}},
+			want:    true,
+		},
+		"equal-other-value": {
+			filters: []*types.GenericFilter{{FilterType: "label", Field: "containerlab", Operator: "=", Match: "lab2"}},
+		},
+		"not-equal": {
+			filters: []*types.GenericFilter{{FilterType: "label", Field: "containerlab", Operator: "!=", Match: "lab2"}},
+			want:    true,
+		},
+		"missing-label": {
+			filters: []*types.GenericFilter{{FilterType: "label", Field: "clab-node-name", Operator: "exists"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := matchLabels(labels, tt.filters); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessArgs(t *testing.T) {
+	tests := map[string]struct {
+		imgEntrypoint []string
+		imgCmd        []string
+		entrypoint    string
+		cmd           string
+		want          []string
+		wantErr       bool
+	}{
+		"image-defaults": {
+			imgEntrypoint: []string{"/docker-entrypoint.sh"},
+			imgCmd:        []string{"nginx", "-g", "daemon off;"},
+			want:          []string{"/docker-entrypoint.sh", "nginx", "-g", "daemon off;"},
+		},
+		"cmd-override": {
+			imgEntrypoint: []string{"/docker-entrypoint.sh"},
+			imgCmd:        []string{"nginx"},
+			cmd:           "sleep 'infinity'",
+			want:          []string{"/docker-entrypoint.sh", "sleep", "infinity"},
+		},
+		"entrypoint-override-drops-image-cmd": {
+			imgEntrypoint: []string{"/docker-entrypoint.sh"},
+			imgCmd:        []string{"nginx"},
+			entrypoint:    "/bin/sh",
+			want:          []string{"/bin/sh"},
+		},
+		"no-command": {
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := processArgs(tt.imgEntrypoint, tt.imgCmd, tt.entrypoint, tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("process args mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRangeBounds(t *testing.T) {
+	tests := map[string]struct {
+		prefix    string
+		wantFirst string
+		wantLast  string
+	}{
+		"ipv4": {
+			prefix:    "172.20.20.0/24",
+			wantFirst: "172.20.20.1",
+			wantLast:  "172.20.20.254",
+		},
+		"ipv6": {
+			prefix:    "3fff:172:20:20::/64",
+			wantFirst: "3fff:172:20:20::",
+			wantLast:  "3fff:172:20:20:ffff:ffff:ffff:ffff",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			first, last, err := rangeBounds(tt.prefix)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if first != tt.wantFirst || last != tt.wantLast {
+				t.Errorf("got %s-%s, want %s-%s", first, last, tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestTailOffset(t *testing.T) {
+	tests := map[string]struct {
+		logs string
+		n    int
+		want string
+	}{
+		"fewer-lines": {
+			logs: "a\nb\n",
+			n:    5,
+			want: "a\nb\n",
+		},
+		"last-line": {
+			logs: "a\nb\nc\n",
+			n:    1,
+			want: "c\n",
+		},
+		"unterminated-last-line": {
+			logs: "a\nb\nc",
+			n:    2,
+			want: "b\nc",
+		},
+		"long-logs": {
+			logs: strings.Repeat("0123456789\n", 1000),
+			n:    2,
+			want: "0123456789\n0123456789\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := strings.NewReader(tt.logs)
+
+			offset, err := tailOffset(r, tt.n)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := tt.logs[offset:]; got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// ListContainers lists all containers using the provided filters.
+func (r *ContainerdRuntime) ListContainers(ctx context.Context, gfilters []*types.GenericFilter) ([]runtime.GenericContainer, error) {
+	ctrs, err := r.client.Containers(ctx, buildFilterString(gfilters))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.produceGenericContainerList(ctx, ctrs)
+}
+
+// buildFilterString returns the containerd filter of the generic filters.
+// The comma separated filters are all matched, while the filters passed separately are alternatives.
+func buildFilterString(gFilters []*types.GenericFilter) string {
+	filters := make([]string, 0, len(gFilters))
+
+	for _, gF := range gFilters {
+		var filterStr string
+
+		switch {
+		case gF.FilterType == "name":
+			// the containers are named by their ids
+			filterStr = "id==" + strconv.Quote(gF.Match)
+		case gF.Operator == "exists":
+			filterStr = "labels." + strconv.Quote(gF.Field)
+		case gF.Operator == "=" || gF.Operator == "!=":
+			op := gF.Operator
+			if op == "=" {
+				op = "=="
+			}
+
+			filterStr = "labels." + strconv.Quote(gF.Field) + op + strconv.Quote(gF.Match)
+		default:
+			log.Warnf("received a filter with unsupported match type: %+v", gF)
+			continue
+		}
+
+		log.Debugf("produced a filterStr %q from inputs %+v", filterStr, gF)
+
+		filters = append(filters, filterStr)
+	}
+
+	return strings.Join(filters, ",")
+}
+
+// containerState maps the containerd task status to the docker container state.
+func containerState(s containerd.ProcessStatus) string {
+	switch s {
+	case containerd.Running:
+		return "running"
+	case containerd.Created:
+		return "created"
+	case containerd.Stopped:
+		return "exited"
+	case containerd.Paused, containerd.Pausing:
+		return "paused"
+	}
+
+	return "unknown"
+}
+
+// produceGenericContainerList transforms the containerd containers to the generic container format.
+// The mgmt addresses and the published ports are read from the labels set when the container was started.
+func (r *ContainerdRuntime) produceGenericContainerList(ctx context.Context,
+	ctrs []containerd.Container,
+) ([]runtime.GenericContainer, error) {
+	result := make([]runtime.GenericContainer, 0, len(ctrs))
+
+	for _, c := range ctrs {
+		info, err := c.Info(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("container %q cannot be found: %w", c.ID(), err)
+		}
+
+		ctr := runtime.GenericContainer{
+			Names:           []string{info.ID},
+			ID:              info.ID,
+			ShortID:         info.ID,
+			Image:           info.Image,
+			State:           "created",
+			Status:          "Created",
+			Labels:          userLabels(info.Labels),
+			NetworkSettings: mgmtIPs(info.Labels),
+		}
+
+		if len(ctr.ShortID) > 12 {
+			ctr.ShortID = ctr.ShortID[:12]
+		}
+
+		if err := json.Unmarshal([]byte(info.Labels[portsLabel]), &ctr.Ports); err != nil {
+			ctr.Ports = []*types.GenericPortBinding{}
+		}
+
+		if t, err := time.Parse(time.RFC3339Nano, info.Labels[startedAtLabel]); err == nil {
+			ctr.StartedAt = t
+		}
+
+		if err := r.setTaskState(ctx, c, &ctr); err != nil {
+			return nil, err
+		}
+
+		spec, err := c.Spec(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if spec.Process != nil {
+			ctr.Env = spec.Process.Env
+		}
+
+		for _, m := range spec.Mounts {
+			// the hosts and resolv.conf files managed by the runtime are not reported
+			if m.Type != "bind" || strings.HasPrefix(m.Source, stateDir+"/containers/") {
+				continue
+			}
+
+			ctr.Mounts = append(ctr.Mounts, runtime.ContainerMount{
+				Source:      m.Source,
+				Destination: m.Destination,
+				Type:        "bind",
+				ReadOnly:    hasOption(m.Options, "ro"),
+			})
+		}
+
+		ctr.SetRuntime(r)
+
+		result = append(result, ctr)
+	}
+
+	return result, nil
+}
+
+// setTaskState sets the state, the pid and the exit status of the container from its task.
+// The container without a task was created, but was never started.
+func (*ContainerdRuntime) setTaskState(ctx context.Context, c containerd.Container, ctr *runtime.GenericContainer) error {
+	task, err := c.Task(ctx, nil)
+	if errdefs.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	status, err := task.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctr.State = containerState(status.Status)
+	ctr.Pid = int(task.Pid())
+
+	switch status.Status {
+	case containerd.Running:
+		ctr.Status = "Up"
+		if !ctr.StartedAt.IsZero() {
+			ctr.Status = "Up since " + ctr.StartedAt.Format(time.RFC3339)
+		}
+	case containerd.Stopped:
+		ctr.Pid = 0
+		ctr.ExitCode = int(status.ExitStatus)
+		ctr.FinishedAt = status.ExitTime
+		ctr.Status = fmt.Sprintf("Exited (%d)", status.ExitStatus)
+	default:
+		ctr.Status = strings.ToUpper(ctr.State[:1]) + ctr.State[1:]
+	}
+
+	return nil
+}
+
+// hasOption returns true if the mount options contain the option o.
+func hasOption(opts []string, o string) bool {
+	for _, opt := range opts {
+		if opt == o {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// logPollInterval is the interval the followed log file is checked for the new logs at.
+const logPollInterval = 200 * time.Millisecond
+
+// GetContainerLogs returns the stdout and stderr of the container interleaved in a single stream.
+// The output of the container task is written to the log file of the container,
+// the file has no timestamps, so the logs can't be filtered by time.
+func (r *ContainerdRuntime) GetContainerLogs(ctx context.Context, cID string, opts runtime.LogOptions) (io.ReadCloser, error) {
+	if _, err := r.client.LoadContainer(ctx, cID); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(containerFile(cID, logFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.Since.IsZero() {
+		log.Warnf("the logs of %s containers have no timestamps, showing all logs of container %s", RuntimeName, cID)
+	}
+
+	if opts.Tail > 0 {
+		offset, err := tailOffset(f, opts.Tail)
+		if err == nil {
+			_, err = f.Seek(offset, io.SeekStart)
+		}
+
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	if !opts.Follow {
+		return f, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &followedLogs{f: f, ctx: ctx, cancel: cancel}, nil
+}
+
+// tailOffset returns the offset of the last n lines of the file, the last line may be not terminated.
+func tailOffset(rs io.ReadSeeker, n int) (int64, error) {
+	const chunkSize = 4096
+
+	end, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, chunkSize)
+	pos := end
+	lines := 0
+
+	for pos > 0 {
+		size := int64(chunkSize)
+		if pos < size {
+			size = pos
+		}
+
+		pos -= size
+
+		if _, err := rs.Seek(pos, io.SeekStart); err != nil {
+			return 0, err
+		}
+
+		if _, err := io.ReadFull(rs, buf[:size]); err != nil {
+			return 0, err
+		}
+
+		for i := size - 1; i >= 0; i-- {
+			if buf[i] != '\n' {
+				continue
+			}
+
+			// the newline terminating the last line doesn't start a line
+			if pos+i == end-1 {
+				continue
+			}
+
+			lines++
+			if lines == n {
+				return pos + i + 1, nil
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// followedLogs is the logs stream following the log file of a container,
+// the stream ends when the context is cancelled or the stream is closed.
+type followedLogs struct {
+	f      *os.File
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (l *followedLogs) Read(p []byte) (int, error) {
+	for {
+		n, err := l.f.Read(p)
+		if n > 0 || !errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		select {
+		case <-l.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(logPollInterval):
+		}
+	}
+}
+
+func (l *followedLogs) Close() error {
+	l.cancel()
+
+	return l.f.Close()
+}
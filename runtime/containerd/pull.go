@@ -0,0 +1,234 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/containerd/containerd/remotes/docker/config"
+	"github.com/distribution/reference"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	dockerRuntime "github.com/srl-labs/containerlab/runtime/docker"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// dockerHubHost is the registry host the docker hub images are pulled from.
+	dockerHubHost = "registry-1.docker.io"
+	// dockerV1IndexAuthKey is a key under which credentials for dockerhub images are stored.
+	dockerV1IndexAuthKey = "https://index.docker.io/v1/"
+)
+
+// PullImage pulls the container image using the provided image pull policy value, mirroring the docker runtime.
+// When the platform is set, the image variant of that platform is pulled,
+// and the local image of a different platform is considered absent.
+func (r *ContainerdRuntime) PullImage(ctx context.Context, imageName string, pullPolicy types.PullPolicyValue,
+	platform string,
+) error {
+	log.Debugf("Looking up %s image", imageName)
+
+	canonicalImageName := utils.GetCanonicalImageName(imageName)
+
+	exists, err := r.imageExists(ctx, canonicalImageName, platform)
+	if err != nil {
+		return err
+	}
+
+	switch pullPolicy {
+	case types.PullPolicyNever:
+		if !exists {
+			// image not found but pull policy = never
+			return fmt.Errorf("image %s not found locally, and image-pull-policy=%s prevents containerlab from pulling it", imageName, pullPolicy)
+		}
+		// image present, all good
+		log.Debugf("Image %s present, skip pulling", imageName)
+		return nil
+	case types.PullPolicyIfNotPresent:
+		if exists {
+			// pull policy == IfNotPresent and image is present
+			log.Debugf("Image %s present, skip pulling", imageName)
+			return nil
+		}
+	}
+
+	if err := r.installRegistryTLS(canonicalImageName); err != nil {
+		return err
+	}
+
+	opts := []containerd.RemoteOpt{
+		containerd.WithPullUnpack,
+		containerd.WithResolver(docker.NewResolver(docker.ResolverOptions{
+			Hosts: config.ConfigureHosts(ctx, config.HostOptions{
+				HostDir:     config.HostDirFromRoot(certsDir),
+				Credentials: registryCredentials,
+			}),
+		})),
+	}
+
+	if platform != "" {
+		opts = append(opts, containerd.WithPlatform(platform))
+	}
+
+	log.Infof("Pulling %s image", canonicalImageName)
+
+	if _, err := r.client.Pull(ctx, canonicalImageName, opts...); err != nil {
+		return r.imagePullError(ctx, imageName, err)
+	}
+
+	log.Infof("Done pulling %s", canonicalImageName)
+
+	return nil
+}
+
+// ImportImage loads the images of the image archive created with `docker save`
+// and unpacks them, so that the containers can be created from them.
+func (r *ContainerdRuntime) ImportImage(ctx context.Context, tarPath string) error {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	log.Infof("Importing images from %s", tarPath)
+
+	imgs, err := r.client.Import(ctx, f)
+	if err != nil {
+		return fmt.Errorf("failed to import image archive %q: %w", tarPath, err)
+	}
+
+	for _, i := range imgs {
+		if err := containerd.NewImage(r.client, i).Unpack(ctx, containerd.DefaultSnapshotter); err != nil {
+			return fmt.Errorf("failed to unpack image %s of archive %q: %w", i.Name, tarPath, err)
+		}
+	}
+
+	log.Infof("Done importing %s", tarPath)
+
+	return nil
+}
+
+// BuildImage is not supported, containerd has no means to build the images from a Dockerfile.
+func (*ContainerdRuntime) BuildImage(_ context.Context, spec *types.ImageBuild) error {
+	return fmt.Errorf("building image %s is not supported by the containerd runtime", spec.Tag)
+}
+
+// imageExists returns true if the image is present locally and is unpacked for the platform,
+// the host platform is used when the platform is not set.
+func (r *ContainerdRuntime) imageExists(ctx context.Context, imageName, platform string) (bool, error) {
+	img, err := r.localImage(ctx, imageName, platform)
+	if errdefs.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// the image of a different platform has no content of the requested one
+	unpacked, err := img.IsUnpacked(ctx, containerd.DefaultSnapshotter)
+	if err != nil {
+		log.Debugf("Image %s present, but not for platform %q: %v", imageName, platform, err)
+		return false, nil
+	}
+
+	return unpacked, nil
+}
+
+// installRegistryTLS installs the client TLS material of the mTLS-protected registry of the image
+// to the containerd certs directory, so that the resolver uses it when pulling from the registry.
+func (r *ContainerdRuntime) installRegistryTLS(image string) error {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+
+	registry := reference.Domain(ref)
+
+	tc, ok := r.config.RegistryTLS[registry]
+	if !ok {
+		return nil
+	}
+
+	log.Debugf("Installing registry %s TLS material to %s", registry, certsDir)
+
+	return runtime.InstallRegistryTLS(certsDir, registry, tc)
+}
+
+// registryCredentials returns the credentials of the registry host stored in the default docker config file.
+// Empty credentials are returned if the config file is not found.
+func registryCredentials(host string) (string, string, error) {
+	dockerConfig, err := dockerRuntime.GetDockerConfig("")
+	if err != nil {
+		log.Debug("docker config file not found")
+		return "", "", nil
+	}
+
+	keys := []string{host}
+	// the docker hub credentials are stored under the docker.io domain or the v1 index key
+	if host == dockerHubHost {
+		keys = []string{"docker.io", dockerV1IndexAuthKey}
+	}
+
+	for _, k := range keys {
+		auth, ok := dockerConfig.Auths[k]
+		if !ok || auth.Auth == "" {
+			continue
+		}
+
+		return decodeAuth(auth.Auth)
+	}
+
+	return "", "", nil
+}
+
+// decodeAuth decodes the base64 encoded user:password auth string of the docker config file.
+func decodeAuth(auth string) (string, string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return "", "", err
+	}
+
+	user, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", errors.New("unexpected auth string")
+	}
+
+	return strings.TrimSpace(user), strings.TrimSpace(password), nil
+}
+
+// imagePullError wraps the error returned by the image pull into runtime.ImagePullError
+// and for the not found images tries to find a similar image available locally.
+func (r *ContainerdRuntime) imagePullError(ctx context.Context, imageName string, err error) error {
+	pullErr := runtime.NewImagePullError(RuntimeName, imageName, err)
+
+	if pullErr.Class != runtime.ImagePullErrorNotFound {
+		return pullErr
+	}
+
+	imgs, lErr := r.client.ImageService().List(ctx)
+	if lErr != nil {
+		log.Debugf("failed to list local images: %v", lErr)
+		return pullErr
+	}
+
+	localImages := make([]string, 0, len(imgs))
+	for _, img := range imgs {
+		localImages = append(localImages, img.Name)
+	}
+
+	pullErr.LocalMatch = runtime.ClosestImageName(imageName, localImages)
+
+	return pullErr
+}
@@ -0,0 +1,465 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	"github.com/containerd/containerd/pkg/cap"
+	"github.com/dustin/go-humanize"
+	"github.com/google/shlex"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// the files of a container kept in its directory in the state dir.
+const (
+	hostsFile      = "hosts"
+	resolvConfFile = "resolv.conf"
+	logFile        = "container.log"
+)
+
+// the resolv.conf files of the host, systemd-resolved keeps the upstream servers
+// in its own file, since the stub resolver on the loopback address is not reachable from a container netns.
+const (
+	hostResolvConf     = "/etc/resolv.conf"
+	resolvedResolvConf = "/run/systemd/resolve/resolv.conf"
+)
+
+var errInvalidBind = errors.New("invalid bind mount provided")
+
+// containerDir returns the directory of the container files in the state dir.
+func containerDir(cID string) string {
+	return filepath.Join(stateDir, "containers", cID)
+}
+
+// containerFile returns the path of the named container file in the container directory.
+func containerFile(cID, name string) string {
+	return filepath.Join(containerDir(cID), name)
+}
+
+// usesMgmtNet returns true if the container with the network mode is attached to the mgmt network.
+func usesMgmtNet(networkMode string) bool {
+	mode, _, _ := strings.Cut(networkMode, ":")
+
+	return mode != "none" && mode != "host" && mode != "container"
+}
+
+// writeContainerFiles writes the hosts and resolv.conf files of the node to the container directory,
+// the files are bind mounted into the container.
+func writeContainerFiles(node *types.NodeConfig) error {
+	dir := containerDir(node.LongName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create the directory of container %q: %w", node.LongName, err)
+	}
+
+	hosts := hostsContent(node.GetHostname(), node.ExtraHosts)
+	if err := os.WriteFile(filepath.Join(dir, hostsFile), []byte(hosts), 0644); err != nil {
+		return err
+	}
+
+	resolvConf, err := resolvConfContent(node.DNS)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, resolvConfFile), []byte(resolvConf), 0644)
+}
+
+// hostsContent returns the /etc/hosts content of a container with the hostname and the extra hosts
+// in the host:ip format.
+func hostsContent(hostname string, extraHosts []string) string {
+	var sb strings.Builder
+
+	sb.WriteString("127.0.0.1\tlocalhost\n")
+	sb.WriteString("::1\tlocalhost ip6-localhost ip6-loopback\n")
+
+	if hostname != "" {
+		sb.WriteString("127.0.1.1\t" + hostname + "\n")
+	}
+
+	for _, h := range extraHosts {
+		// the IPv6 address of an extra host contains colons too
+		name, ip, ok := strings.Cut(h, ":")
+		if !ok {
+			continue
+		}
+
+		sb.WriteString(ip + "\t" + name + "\n")
+	}
+
+	return sb.String()
+}
+
+// resolvConfContent returns the resolv.conf content of a container with the DNS settings of the node.
+// Without the DNS servers set the resolv.conf of the host is used.
+func resolvConfContent(dns *types.DNSConfig) (string, error) {
+	var sb strings.Builder
+
+	if dns == nil || len(dns.Servers) == 0 {
+		src := hostResolvConf
+		if utils.FileExists(resolvedResolvConf) {
+			src = resolvedResolvConf
+		}
+
+		b, err := os.ReadFile(src)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the host resolv.conf: %w", err)
+		}
+
+		sb.Write(b)
+	}
+
+	if dns == nil {
+		return sb.String(), nil
+	}
+
+	for _, s := range dns.Servers {
+		sb.WriteString("nameserver " + s + "\n")
+	}
+
+	if len(dns.Search) > 0 {
+		sb.WriteString("search " + strings.Join(dns.Search, " ") + "\n")
+	}
+
+	if len(dns.Options) > 0 {
+		sb.WriteString("options " + strings.Join(dns.Options, " ") + "\n")
+	}
+
+	return sb.String(), nil
+}
+
+// GetContainerSpec returns the OCI runtime spec CreateContainer creates the container of the node with.
+func (r *ContainerdRuntime) GetContainerSpec(ctx context.Context, node *types.NodeConfig) (interface{}, error) {
+	img, err := r.localImage(ctx, node.Image, node.Platform)
+	if err != nil {
+		return nil, fmt.Errorf("image %s of node %q: %w", node.Image, node.ShortName, err)
+	}
+
+	specOpts, err := r.specOpts(ctx, node, img)
+	if err != nil {
+		return nil, fmt.Errorf("error while trying to create a container spec for node %q: %w", node.LongName, err)
+	}
+
+	// the spec generation requires the namespace set in the context, unlike the client methods
+	spec, err := oci.GenerateSpec(namespaces.WithNamespace(ctx, containerdNamespace), r.client, &containers.Container{
+		ID:     node.LongName,
+		Image:  img.Name(),
+		Labels: node.Labels,
+	}, specOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// specOpts returns the OCI spec options of the node container created from the image img.
+func (r *ContainerdRuntime) specOpts(ctx context.Context, node *types.NodeConfig, img containerd.Image) ([]oci.SpecOpts, error) {
+	imgSpec, err := img.Spec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := processArgs(imgSpec.Config.Entrypoint, imgSpec.Config.Cmd, node.Entrypoint, node.Cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts, err := r.convertMounts(node.Binds)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts = append(mounts,
+		bindMount(containerFile(node.LongName, hostsFile), "/etc/hosts", nil),
+		bindMount(containerFile(node.LongName, resolvConfFile), "/etc/resolv.conf", nil),
+	)
+
+	opts := []oci.SpecOpts{
+		oci.WithImageConfig(img),
+		oci.WithProcessArgs(args...),
+		oci.WithEnv(utils.ConvertEnvs(node.Env)),
+		oci.WithMounts(mounts),
+		withPrivileged,
+		withSysctls(node.Sysctls),
+	}
+
+	if node.User != "" {
+		opts = append(opts, oci.WithUser(node.User))
+	}
+
+	resOpts, err := resourceOpts(node)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, resOpts...)
+
+	netOpts, err := r.networkModeOpts(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(opts, netOpts...), nil
+}
+
+// processArgs returns the args of the container process the same way docker composes them:
+// the entrypoint and the cmd of the node override the ones of the image,
+// and the image cmd is not used when the entrypoint is overridden.
+func processArgs(imgEntrypoint, imgCmd []string, entrypoint, cmd string) ([]string, error) {
+	ep := imgEntrypoint
+	c := imgCmd
+
+	if entrypoint != "" {
+		var err error
+		if ep, err = shlex.Split(entrypoint); err != nil {
+			return nil, err
+		}
+
+		c = nil
+	}
+
+	if cmd != "" {
+		var err error
+		if c, err = shlex.Split(cmd); err != nil {
+			return nil, err
+		}
+	}
+
+	args := append(append([]string{}, ep...), c...)
+	if len(args) == 0 {
+		return nil, errors.New("no command specified by the image nor by the node")
+	}
+
+	return args, nil
+}
+
+// bindMount returns the bind mount of the src path to the dst path with the options.
+func bindMount(src, dst string, opts []string) specs.Mount {
+	return specs.Mount{
+		Destination: dst,
+		Type:        "bind",
+		Source:      src,
+		Options:     append([]string{"rbind"}, opts...),
+	}
+}
+
+// convertMounts takes a list of filesystem mount binds in docker/clab format (src:dest:options)
+// and converts it into an opencontainers spec format.
+// The binds with a named volume as a source are mounted from the volume directory,
+// the volume is created if it doesn't exist.
+func (r *ContainerdRuntime) convertMounts(binds []string) ([]specs.Mount, error) {
+	mounts := make([]specs.Mount, 0, len(binds))
+
+	for _, bind := range binds {
+		b, err := types.NewBind(bind)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidBind, bind)
+		}
+
+		var opts []string
+		if b.Mode() != "" {
+			opts = strings.Split(b.Mode(), ",")
+		}
+
+		src := b.Src()
+		if b.IsVolume() {
+			if src, err = r.volumeDataDir(src); err != nil {
+				return nil, err
+			}
+		}
+
+		mounts = append(mounts, bindMount(src, b.Dst(), opts))
+	}
+
+	log.Debugf("convertMounts method received binds %v and produced %+v as a result", binds, mounts)
+
+	return mounts, nil
+}
+
+// withPrivileged makes the container privileged the same way docker does:
+// all capabilities and host devices are granted and the kernel filesystems are writable.
+func withPrivileged(ctx context.Context, client oci.Client, c *containers.Container, s *oci.Spec) error {
+	opts := []oci.SpecOpts{
+		oci.WithCapabilities(cap.Known()),
+		oci.WithHostDevices,
+		oci.WithAllDevicesAllowed,
+		oci.WithMaskedPaths(nil),
+		oci.WithReadonlyPaths(nil),
+		oci.WithApparmorProfile(""),
+	}
+
+	for _, o := range opts {
+		if err := o(ctx, client, c, s); err != nil {
+			return err
+		}
+	}
+
+	for i, m := range s.Mounts {
+		if m.Destination != "/sys" && m.Destination != "/sys/fs/cgroup" {
+			continue
+		}
+
+		var mopts []string
+		for _, o := range m.Options {
+			if o != "ro" {
+				mopts = append(mopts, o)
+			}
+		}
+
+		s.Mounts[i].Options = append(mopts, "rw")
+	}
+
+	return nil
+}
+
+// withSysctls sets the namespaced kernel parameters of the container.
+func withSysctls(sysctls map[string]string) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		if len(sysctls) == 0 {
+			return nil
+		}
+
+		if s.Linux == nil {
+			s.Linux = &specs.Linux{}
+		}
+
+		if s.Linux.Sysctl == nil {
+			s.Linux.Sysctl = map[string]string{}
+		}
+
+		for k, v := range sysctls {
+			s.Linux.Sysctl[k] = v
+		}
+
+		return nil
+	}
+}
+
+// resourceOpts translates the resource limits of the node to the OCI spec options.
+func resourceOpts(node *types.NodeConfig) ([]oci.SpecOpts, error) {
+	var opts []oci.SpecOpts
+
+	if node.Memory != "" {
+		mem, err := humanize.ParseBytes(node.Memory)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, oci.WithMemoryLimit(mem))
+	}
+
+	if node.MemorySwap != "" {
+		swap, err := runtime.ParseMemorySwap(node.MemorySwap)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, oci.WithMemorySwap(swap))
+	}
+
+	if node.CPU != 0 {
+		opts = append(opts, oci.WithCPUCFS(int64(node.CPU*100000), 100000))
+	}
+
+	if node.CPUSet != "" {
+		opts = append(opts, oci.WithCPUs(node.CPUSet))
+	}
+
+	if node.CgroupParent != "" {
+		opts = append(opts, oci.WithCgroup(filepath.Join(node.CgroupParent, node.LongName)))
+	}
+
+	if node.OomScoreAdj != nil || node.OomKillDisable {
+		opts = append(opts, withOOM(node.OomScoreAdj, node.OomKillDisable))
+	}
+
+	return opts, nil
+}
+
+// withOOM sets the OOM score adjustment of the container process and disables the OOM killer
+// of the container when disable is set.
+func withOOM(scoreAdj *int, disable bool) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		if scoreAdj != nil && s.Process != nil {
+			s.Process.OOMScoreAdj = scoreAdj
+		}
+
+		if !disable {
+			return nil
+		}
+
+		if s.Linux == nil {
+			s.Linux = &specs.Linux{}
+		}
+
+		if s.Linux.Resources == nil {
+			s.Linux.Resources = &specs.LinuxResources{}
+		}
+
+		if s.Linux.Resources.Memory == nil {
+			s.Linux.Resources.Memory = &specs.LinuxMemory{}
+		}
+
+		s.Linux.Resources.Memory.DisableOOMKiller = &disable
+
+		return nil
+	}
+}
+
+// networkModeOpts returns the spec options of the node network mode.
+// The containers attached to the mgmt network and the containers with the none network mode get their own netns.
+func (r *ContainerdRuntime) networkModeOpts(ctx context.Context, node *types.NodeConfig) ([]oci.SpecOpts, error) {
+	netMode := strings.SplitN(node.NetworkMode, ":", 2)
+
+	switch netMode[0] {
+	case "host":
+		return []oci.SpecOpts{
+			oci.WithHostNamespace(specs.NetworkNamespace),
+			oci.WithHostname(node.GetHostname()),
+		}, nil
+	// clab allows its containers to be attached to a netns of another container
+	// this can be a container that is managed by clab, or an external container.
+	case "container":
+		// We expect exactly two arguments in this case ("container" keyword & cont. name/ID)
+		if len(netMode) != 2 || netMode[1] == "" {
+			return nil, fmt.Errorf("container network mode was specified for container %q, but we failed to parse the network-mode instruction: %q",
+				node.ShortName, node.NetworkMode)
+		}
+
+		// the container of the lab is looked up by its prefixed name first,
+		// otherwise the name refers to an external container
+		contName := strings.SplitN(node.LongName, node.ShortName, 2)[0] + netMode[1]
+
+		nsPath, err := r.GetNSPath(ctx, contName)
+		if err != nil {
+			log.Debugf("container %q was not found by its name, assuming it is exists externally with unprefixed", contName)
+
+			if nsPath, err = r.GetNSPath(ctx, netMode[1]); err != nil {
+				return nil, fmt.Errorf("container %q is referenced in network-mode, but was not found", netMode[1])
+			}
+		}
+
+		// the hostname is not set, since the container shares the network stack of another one
+		return []oci.SpecOpts{
+			oci.WithLinuxNamespace(specs.LinuxNamespace{Type: specs.NetworkNamespace, Path: nsPath}),
+		}, nil
+	default:
+		return []oci.SpecOpts{oci.WithHostname(node.GetHostname())}, nil
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/containerd/cgroups/v3/cgroup1/stats"
+	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
+	"github.com/containerd/containerd"
+	"github.com/containerd/typeurl/v2"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// statsInterval is the interval between the two samples the CPU usage of a container is calculated over.
+const statsInterval = time.Second
+
+// unlimitedMemory is the lower bound of the memory limit values cgroups report for the unlimited memory.
+const unlimitedMemory = 1 << 62
+
+// taskMetrics is the resource usage of a container task decoded from the cgroup v1 or v2 metrics.
+type taskMetrics struct {
+	// memoryUsage is the memory used by the container in bytes, including the page cache.
+	memoryUsage uint64
+	// inactiveFile is the inactive page cache of the container in bytes.
+	inactiveFile uint64
+	// memoryLimit is the memory limit of the container in bytes.
+	memoryLimit uint64
+	// cpuUsage is the total CPU time consumed by the container.
+	cpuUsage time.Duration
+	oomKills uint64
+}
+
+// taskMetrics returns the resource usage of the container task.
+func (*ContainerdRuntime) taskMetrics(ctx context.Context, task containerd.Task) (*taskMetrics, error) {
+	metric, err := task.Metrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := typeurl.UnmarshalAny(metric.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &taskMetrics{}
+
+	switch s := data.(type) {
+	case *v1.Metrics:
+		if s.Memory != nil {
+			m.inactiveFile = s.Memory.TotalInactiveFile
+			if s.Memory.Usage != nil {
+				m.memoryUsage = s.Memory.Usage.Usage
+				m.memoryLimit = s.Memory.Usage.Limit
+			}
+		}
+
+		if s.CPU != nil && s.CPU.Usage != nil {
+			m.cpuUsage = time.Duration(s.CPU.Usage.Total)
+		}
+
+		if s.MemoryOomControl != nil {
+			m.oomKills = s.MemoryOomControl.OomKill
+		}
+	case *v2.Metrics:
+		if s.Memory != nil {
+			m.memoryUsage = s.Memory.Usage
+			m.inactiveFile = s.Memory.InactiveFile
+			m.memoryLimit = s.Memory.UsageLimit
+		}
+
+		if s.CPU != nil {
+			m.cpuUsage = time.Duration(s.CPU.UsageUsec) * time.Microsecond
+		}
+
+		if s.MemoryEvents != nil {
+			m.oomKills = s.MemoryEvents.OomKill
+		}
+	default:
+		return nil, fmt.Errorf("unsupported metrics type %T of task %s", data, task.ID())
+	}
+
+	return m, nil
+}
+
+// GetContainerStats returns the current resource usage of the container.
+// The CPU usage is calculated over the interval between the two samples taken statsInterval apart.
+func (r *ContainerdRuntime) GetContainerStats(ctx context.Context, cID string) (*runtime.ContainerStats, error) {
+	_, task, err := r.task(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := r.taskMetrics(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	select {
+	case <-time.After(statsInterval):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	second, err := r.taskMetrics(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runtime.ContainerStats{
+		MemoryUsage: second.memoryUsageWithoutCache(),
+		MemoryLimit: second.memoryLimitIfSet(),
+		CPUPercent:  cpuPercent(first.cpuUsage, second.cpuUsage, time.Since(start)),
+	}, nil
+}
+
+// memoryUsageWithoutCache returns the memory usage without the page cache, the same way the docker CLI does.
+func (m *taskMetrics) memoryUsageWithoutCache() uint64 {
+	if m.inactiveFile < m.memoryUsage {
+		return m.memoryUsage - m.inactiveFile
+	}
+
+	return m.memoryUsage
+}
+
+// memoryLimitIfSet returns the memory limit of the container, or zero when the memory is unlimited.
+// The unlimited memory is reported as the page aligned max int64 by cgroup v1 and as the max uint64 by cgroup v2.
+func (m *taskMetrics) memoryLimitIfSet() uint64 {
+	if m.memoryLimit >= unlimitedMemory {
+		return 0
+	}
+
+	return m.memoryLimit
+}
+
+// cpuPercent returns the CPU usage percentage over the interval between the CPU usage samples,
+// 100% being a single CPU core fully used.
+func cpuPercent(first, second, interval time.Duration) float64 {
+	delta := second - first
+	if delta <= 0 || interval <= 0 {
+		return 0
+	}
+
+	return float64(delta) / float64(interval) * 100
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package containerd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// containerd has no volumes, the named volumes are the directories in the volumes directory of the state dir
+// with the volume data in the data directory and the volume labels in the labels file.
+const (
+	volumeDataName   = "_data"
+	volumeLabelsFile = "labels.json"
+)
+
+// volumesDir returns the directory of the named volumes.
+func volumesDir() string {
+	return filepath.Join(stateDir, "volumes")
+}
+
+// volumeDataDir returns the data directory of the named volume, the volume is created if it doesn't exist.
+func (r *ContainerdRuntime) volumeDataDir(name string) (string, error) {
+	if err := r.CreateVolume(context.Background(), name, nil); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(volumesDir(), name, volumeDataName), nil
+}
+
+// CreateVolume creates the named volume with the labels, an existing volume is left intact.
+func (*ContainerdRuntime) CreateVolume(_ context.Context, name string, labels map[string]string) error {
+	dir := filepath.Join(volumesDir(), name)
+
+	if _, err := os.Stat(dir); err == nil {
+		log.Debugf("Volume %q already exists", name)
+		return nil
+	}
+
+	log.Debugf("Creating volume %q", name)
+
+	if err := os.MkdirAll(filepath.Join(dir, volumeDataName), 0755); err != nil {
+		return fmt.Errorf("failed to create volume %q: %w", name, err)
+	}
+
+	b, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, volumeLabelsFile), b, 0644)
+}
+
+// ListVolumes returns the names of the volumes matching the label filters.
+func (*ContainerdRuntime) ListVolumes(_ context.Context, gfilters []*types.GenericFilter) ([]string, error) {
+	entries, err := os.ReadDir(volumesDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		var labels map[string]string
+
+		b, err := os.ReadFile(filepath.Join(volumesDir(), e.Name(), volumeLabelsFile))
+		if err == nil {
+			err = json.Unmarshal(b, &labels)
+		}
+
+		if err != nil {
+			log.Debugf("failed to read the labels of volume %q: %v", e.Name(), err)
+		}
+
+		if matchLabels(labels, gfilters) {
+			names = append(names, e.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// matchLabels returns true if the labels match all the label filters.
+func matchLabels(labels map[string]string, gfilters []*types.GenericFilter) bool {
+	for _, f := range gfilters {
+		if f.FilterType != "label" {
+			continue
+		}
+
+		v, ok := labels[f.Field]
+
+		switch f.Operator {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "=":
+			if !ok || v != f.Match {
+				return false
+			}
+		case "!=":
+			if ok && v == f.Match {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// DeleteVolume deletes the named volume with its data.
+func (*ContainerdRuntime) DeleteVolume(_ context.Context, name string) error {
+	dir := filepath.Join(volumesDir(), name)
+
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("volume %q not found: %w", name, err)
+	}
+
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cri
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// ListContainers lists all containers using the provided filters.
+// The CRI label selector matches the label values only, so the filters are matched by containerlab.
+func (r *CRIRuntime) ListContainers(ctx context.Context, gfilters []*types.GenericFilter) ([]runtime.GenericContainer, error) {
+	resp, err := r.rsc.ListContainers(ctx, &runtimeapi.ListContainersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var ctrs []*runtimeapi.Container
+
+	for _, c := range resp.Containers {
+		if matchFilters(c.GetMetadata().GetName(), c.Labels, gfilters) {
+			ctrs = append(ctrs, c)
+		}
+	}
+
+	return r.produceGenericContainerList(ctx, ctrs)
+}
+
+// matchFilters returns true if the container with the name and labels matches all generic filters.
+func matchFilters(name string, labels map[string]string, gFilters []*types.GenericFilter) bool {
+	for _, gF := range gFilters {
+		if gF.FilterType == "name" {
+			if name != gF.Match {
+				return false
+			}
+
+			continue
+		}
+
+		v, ok := labels[gF.Field]
+
+		switch gF.Operator {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "=":
+			if !ok || v != gF.Match {
+				return false
+			}
+		case "!=":
+			if ok && v == gF.Match {
+				return false
+			}
+		default:
+			log.Warnf("received a filter with unsupported match type: %+v", gF)
+		}
+	}
+
+	return true
+}
+
+// containerState maps the CRI container state to the docker container state.
+func containerState(s runtimeapi.ContainerState) string {
+	switch s {
+	case runtimeapi.ContainerState_CONTAINER_RUNNING:
+		return "running"
+	case runtimeapi.ContainerState_CONTAINER_CREATED:
+		return "created"
+	case runtimeapi.ContainerState_CONTAINER_EXITED:
+		return "exited"
+	}
+
+	return "unknown"
+}
+
+// produceGenericContainerList transforms the CRI containers to the generic container format.
+// The mgmt addresses are the addresses of the pod sandboxes of the containers.
+func (r *CRIRuntime) produceGenericContainerList(ctx context.Context,
+	ctrs []*runtimeapi.Container,
+) ([]runtime.GenericContainer, error) {
+	result := make([]runtime.GenericContainer, 0, len(ctrs))
+
+	for _, c := range ctrs {
+		name := c.GetMetadata().GetName()
+
+		ctr := runtime.GenericContainer{
+			Names:   []string{name},
+			ID:      c.Id,
+			ShortID: c.Id,
+			Image:   c.GetImage().GetImage(),
+			State:   containerState(c.State),
+			Status:  "Created",
+			Labels:  c.Labels,
+			Ports:   []*types.GenericPortBinding{},
+		}
+
+		if len(ctr.ShortID) > 12 {
+			ctr.ShortID = ctr.ShortID[:12]
+		}
+
+		ips, err := r.sandboxIPs(ctx, c.PodSandboxId)
+		if err != nil {
+			log.Debugf("failed to get the addresses of container %q: %v", name, err)
+		}
+
+		ctr.NetworkSettings = ips
+
+		status, err := r.rsc.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{ContainerId: c.Id, Verbose: true})
+		if err != nil {
+			return nil, err
+		}
+
+		setStatus(&ctr, status)
+
+		ctr.SetRuntime(r)
+
+		result = append(result, ctr)
+	}
+
+	return result, nil
+}
+
+// setStatus sets the status, the pid, the mounts and the exit status of the container from its CRI status.
+func setStatus(ctr *runtime.GenericContainer, resp *runtimeapi.ContainerStatusResponse) {
+	s := resp.GetStatus()
+
+	if s.StartedAt != 0 {
+		ctr.StartedAt = time.Unix(0, s.StartedAt)
+	}
+
+	if s.FinishedAt != 0 {
+		ctr.FinishedAt = time.Unix(0, s.FinishedAt)
+	}
+
+	if pid, err := containerPid(resp); err == nil {
+		ctr.Pid = pid
+	}
+
+	switch s.State {
+	case runtimeapi.ContainerState_CONTAINER_RUNNING:
+		ctr.Status = "Up"
+		if !ctr.StartedAt.IsZero() {
+			ctr.Status = "Up since " + ctr.StartedAt.Format(time.RFC3339)
+		}
+	case runtimeapi.ContainerState_CONTAINER_EXITED:
+		ctr.ExitCode = int(s.ExitCode)
+		ctr.Status = "Exited"
+		if s.Reason != "" {
+			ctr.Status += " (" + s.Reason + ")"
+		}
+	}
+
+	for _, m := range s.Mounts {
+		// the hosts file managed by the runtime is not reported
+		if strings.HasPrefix(m.HostPath, stateDir+"/containers/") {
+			continue
+		}
+
+		ctr.Mounts = append(ctr.Mounts, runtime.ContainerMount{
+			Source:      m.HostPath,
+			Destination: m.ContainerPath,
+			Type:        "bind",
+			ReadOnly:    m.Readonly,
+		})
+	}
+}
@@ -0,0 +1,630 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	RuntimeName    = "cri"
+	defaultTimeout = 120 * time.Second
+	// defaultSocket is the path of the CRI API socket used when the socket is not set by a user,
+	// the CRI plugin of containerd serves the API on the containerd socket.
+	defaultSocket = "/run/containerd/containerd.sock"
+	// podNamespace is the namespace of the pod sandboxes the lab containers run in.
+	podNamespace = "clab"
+	// stateDir keeps the files the CRI runtime doesn't manage, e.g. the hosts files and the logs of the containers.
+	stateDir = "/var/lib/containerlab/cri"
+)
+
+// versionRequirements are the CRI API versions required by containerlab.
+var versionRequirements = []runtime.VersionRequirement{
+	{MinVersion: "1"},
+}
+
+var (
+	errNotSupported = errors.New("not supported by the " + RuntimeName + " runtime")
+	errNotFound     = errors.New("not found")
+)
+
+// CRIRuntime is the runtime managing the containers with a Container Runtime Interface endpoint,
+// each container runs in its own pod sandbox, which netns is set up by the CNI of the CRI runtime.
+type CRIRuntime struct {
+	config *runtime.RuntimeConfig
+	mgmt   *types.MgmtNet
+	conn   *grpc.ClientConn
+	rsc    runtimeapi.RuntimeServiceClient
+	isc    runtimeapi.ImageServiceClient
+}
+
+func init() {
+	runtime.Register(RuntimeName, func() runtime.ContainerRuntime {
+		return &CRIRuntime{
+			config: &runtime.RuntimeConfig{},
+			mgmt:   &types.MgmtNet{},
+		}
+	})
+}
+
+// Init is used to initialize our runtime struct by calling all methods received from the caller
+// and connects to the CRI socket.
+func (r *CRIRuntime) Init(opts ...runtime.RuntimeOption) error {
+	for _, f := range opts {
+		f(r)
+	}
+
+	socket := defaultSocket
+	if r.config.Socket != "" {
+		socket = r.config.Socket
+	}
+
+	var err error
+	r.conn, err = grpc.Dial(runtime.SocketURI(socket), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to CRI socket %s: %w", socket, err)
+	}
+
+	r.rsc = runtimeapi.NewRuntimeServiceClient(r.conn)
+	r.isc = runtimeapi.NewImageServiceClient(r.conn)
+
+	r.config.VerifyLinkParams = links.NewVerifyLinkParams()
+	r.config.VerifyLinkParams.RunBridgeExistsCheck = false
+
+	return nil
+}
+
+func (r *CRIRuntime) Mgmt() *types.MgmtNet { return r.mgmt }
+
+func (r *CRIRuntime) WithConfig(cfg *runtime.RuntimeConfig) {
+	log.Debugf("CRI method WithConfig was called with cfg params: %+v", cfg)
+	// Check for nil pointers on input
+	if cfg == nil {
+		log.Errorf("Method WithConfig has received a nil pointer")
+		return
+	}
+	r.config = cfg
+	if r.config.Timeout <= 0 {
+		r.config.Timeout = defaultTimeout
+	}
+}
+
+// WithMgmtNet assigns struct mgmt net parameters to the runtime struct.
+func (r *CRIRuntime) WithMgmtNet(net *types.MgmtNet) {
+	// Check for nil pointers on input
+	if net == nil {
+		log.Errorf("Method WithMgmtNet has received a nil pointer")
+		return
+	}
+	log.Debugf("CRI method WithMgmtNet was called with net params: %+v", net)
+	r.mgmt = net
+}
+
+// WithKeepMgmtNet defines that we shouldn't delete mgmt network(s).
+func (r *CRIRuntime) WithKeepMgmtNet() {
+	r.config.KeepMgmtNet = true
+}
+
+// Config returns the runtime configuration options.
+func (r *CRIRuntime) Config() runtime.RuntimeConfig {
+	return *r.config
+}
+
+// GetName returns runtime name as a string.
+func (*CRIRuntime) GetName() string {
+	return RuntimeName
+}
+
+// CheckVersion checks the CRI API version against the version required by containerlab.
+func (r *CRIRuntime) CheckVersion(ctx context.Context, nodes []*types.NodeConfig) error {
+	nctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	v, err := r.rsc.Version(nctx, &runtimeapi.VersionRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get the CRI version: %w", err)
+	}
+
+	log.Debugf("CRI runtime %s version %s, API version %s", v.RuntimeName, v.RuntimeVersion, v.RuntimeApiVersion)
+
+	return runtime.CheckVersionRequirements(RuntimeName, strings.TrimPrefix(v.RuntimeApiVersion, "v"),
+		v.RuntimeName+" "+v.RuntimeVersion, versionRequirements, nodes)
+}
+
+// CreateNet is a no-op, the pod sandboxes are attached to the network of the CNI configured
+// in the CRI runtime, which is managed outside of containerlab.
+func (r *CRIRuntime) CreateNet(_ context.Context) error {
+	log.Debugf("The containers of the %s runtime are attached to the CNI network of the CRI runtime, "+
+		"network %q is not created", RuntimeName, r.mgmt.Network)
+
+	return nil
+}
+
+// DeleteNet is a no-op, the CNI network of the CRI runtime is not managed by containerlab.
+func (*CRIRuntime) DeleteNet(_ context.Context) error {
+	return nil
+}
+
+// InspectMgmtNet returns the mgmt network as configured, since the CNI network of the CRI runtime
+// can't be inspected over the CRI API. The network has no containerlab labels, as it's not created by containerlab.
+func (r *CRIRuntime) InspectMgmtNet(_ context.Context) (*runtime.NetworkInfo, error) {
+	return &runtime.NetworkInfo{
+		Name:        r.mgmt.Network,
+		IPv4Subnet:  r.mgmt.IPv4Subnet,
+		IPv6Subnet:  r.mgmt.IPv6Subnet,
+		IPv4Gateway: r.mgmt.IPv4Gw,
+		IPv6Gateway: r.mgmt.IPv6Gw,
+		MTU:         r.mgmt.MTU,
+	}, nil
+}
+
+// CreateContainer runs the pod sandbox of the node and creates the container in it, but does not start it.
+func (r *CRIRuntime) CreateContainer(ctx context.Context, node *types.NodeConfig) (string, error) {
+	log.Infof("Creating container: %q", node.ShortName)
+
+	spec, err := r.containerSpec(node)
+	if err != nil {
+		return "", fmt.Errorf("error while trying to create a container spec for node %q: %w", node.LongName, err)
+	}
+
+	if node.MgmtIPv4Address != "" || node.MgmtIPv6Address != "" {
+		log.Warnf("the %s runtime doesn't support static management addresses, node %q gets the addresses from the CNI",
+			RuntimeName, node.ShortName)
+	}
+
+	if err := writeContainerFiles(node); err != nil {
+		return "", err
+	}
+
+	sb, err := r.rsc.RunPodSandbox(ctx, &runtimeapi.RunPodSandboxRequest{Config: spec.Sandbox})
+	if err != nil {
+		return "", fmt.Errorf("failed to run the pod sandbox of node %q: %w", node.LongName, err)
+	}
+
+	cont, err := r.rsc.CreateContainer(ctx, &runtimeapi.CreateContainerRequest{
+		PodSandboxId:  sb.PodSandboxId,
+		Config:        spec.Container,
+		SandboxConfig: spec.Sandbox,
+	})
+	if err != nil {
+		r.removeSandbox(ctx, sb.PodSandboxId)
+		return "", fmt.Errorf("failed to create the container of node %q: %w", node.LongName, err)
+	}
+
+	log.Debugf("Created container %q in pod sandbox %q", cont.ContainerId, sb.PodSandboxId)
+
+	return node.LongName, nil
+}
+
+// StartContainer starts the container created by CreateContainer.
+func (r *CRIRuntime) StartContainer(ctx context.Context, cID string, node runtime.Node) (interface{}, error) {
+	cfg := node.Config()
+
+	c, err := r.container(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Start container: %q", cfg.LongName)
+
+	if _, err := r.rsc.StartContainer(ctx, &runtimeapi.StartContainerRequest{ContainerId: c.Id}); err != nil {
+		return nil, fmt.Errorf("error while starting a container %q: %w", cfg.LongName, err)
+	}
+
+	log.Debugf("Container started: %q", cfg.LongName)
+
+	return nil, r.postStartActions(ctx, c, cfg)
+}
+
+// postStartActions performs misc. tasks that are needed after the container starts,
+// the mgmt addresses of the node are the addresses assigned to the pod sandbox by the CNI.
+func (r *CRIRuntime) postStartActions(ctx context.Context, c *runtimeapi.Container, cfg *types.NodeConfig) error {
+	var err error
+	cfg.NSPath, err = r.GetNSPath(ctx, cfg.LongName)
+	if err != nil {
+		return err
+	}
+
+	ips, err := r.sandboxIPs(ctx, c.PodSandboxId)
+	if err != nil {
+		return err
+	}
+
+	cfg.MgmtIPv4Address, cfg.MgmtIPv4PrefixLength = ips.IPv4addr, ips.IPv4pLen
+	cfg.MgmtIPv6Address, cfg.MgmtIPv6PrefixLength = ips.IPv6addr, ips.IPv6pLen
+
+	return utils.LinkContainerNS(cfg.NSPath, cfg.LongName)
+}
+
+// container returns the container by its name, the CRI runtime identifies the containers
+// by the generated ids, while containerlab refers to them by their names.
+func (r *CRIRuntime) container(ctx context.Context, name string) (*runtimeapi.Container, error) {
+	resp, err := r.rsc.ListContainers(ctx, &runtimeapi.ListContainersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range resp.Containers {
+		if c.Id == name || c.GetMetadata().GetName() == name {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("container %s: %w", name, errNotFound)
+}
+
+// containerStatus returns the verbose status of the container by its name.
+func (r *CRIRuntime) containerStatus(ctx context.Context, name string) (*runtimeapi.ContainerStatusResponse, error) {
+	c, err := r.container(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.rsc.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{ContainerId: c.Id, Verbose: true})
+}
+
+// containerPid returns the pid of the container process from the verbose info of the container status,
+// containerd and CRI-O report it in the info JSON.
+func containerPid(status *runtimeapi.ContainerStatusResponse) (int, error) {
+	var info struct {
+		Pid int `json:"pid"`
+	}
+
+	if err := json.Unmarshal([]byte(status.GetInfo()["info"]), &info); err != nil {
+		return 0, fmt.Errorf("failed to decode the info of container %s: %w", status.GetStatus().GetId(), err)
+	}
+
+	if info.Pid == 0 {
+		return 0, fmt.Errorf("container %s is not running", status.GetStatus().GetId())
+	}
+
+	return info.Pid, nil
+}
+
+// StopContainer stops the container, which is killed if it doesn't exit within the runtime timeout.
+// The CRI API sends the stop signal of the container image, only SIGTERM is accepted as the signal.
+func (r *CRIRuntime) StopContainer(ctx context.Context, cID, signal string) error {
+	sig, err := runtime.ParseStopSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	if sig != syscall.SIGTERM {
+		return fmt.Errorf("stopping container %s with signal %d: %w", cID, sig, errNotSupported)
+	}
+
+	c, err := r.container(ctx, cID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.rsc.StopContainer(ctx, &runtimeapi.StopContainerRequest{
+		ContainerId: c.Id,
+		Timeout:     int64(r.config.Timeout.Seconds()),
+	})
+
+	return err
+}
+
+// PauseContainer is not supported, the CRI API has no means to pause a container.
+func (*CRIRuntime) PauseContainer(_ context.Context, cID string) error {
+	return fmt.Errorf("pausing container %s: %w", cID, errNotSupported)
+}
+
+// UnpauseContainer is not supported, the CRI API has no means to pause a container.
+func (*CRIRuntime) UnpauseContainer(_ context.Context, cID string) error {
+	return fmt.Errorf("unpausing container %s: %w", cID, errNotSupported)
+}
+
+// DeleteContainer removes the container with its pod sandbox and files.
+// With the graceful shutdown the container process is terminated and waited for before being killed.
+func (r *CRIRuntime) DeleteContainer(ctx context.Context, cID string) error {
+	c, err := r.container(ctx, cID)
+	if errors.Is(err, errNotFound) {
+		log.Debugf("Container %s not found, nothing to remove", cID)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if r.config.GracefulShutdown {
+		log.Infof("Stopping container: %s", cID)
+
+		if _, err := r.rsc.StopContainer(ctx, &runtimeapi.StopContainerRequest{
+			ContainerId: c.Id,
+			Timeout:     int64(r.config.Timeout.Seconds()),
+		}); err != nil {
+			log.Errorf("could not stop container %q: %v", cID, err)
+		}
+	}
+
+	log.Debugf("Removing container: %s", cID)
+
+	if _, err := r.rsc.RemoveContainer(ctx, &runtimeapi.RemoveContainerRequest{ContainerId: c.Id}); err != nil {
+		return err
+	}
+
+	r.removeSandbox(ctx, c.PodSandboxId)
+
+	if err := os.RemoveAll(containerDir(cID)); err != nil {
+		log.Warnf("failed to remove the files of container %q: %v", cID, err)
+	}
+
+	log.Infof("Removed container: %s", cID)
+
+	return nil
+}
+
+// removeSandbox stops and removes the pod sandbox, the errors are logged only.
+func (r *CRIRuntime) removeSandbox(ctx context.Context, id string) {
+	if _, err := r.rsc.StopPodSandbox(ctx, &runtimeapi.StopPodSandboxRequest{PodSandboxId: id}); err != nil {
+		log.Debugf("failed to stop pod sandbox %q: %v", id, err)
+	}
+
+	if _, err := r.rsc.RemovePodSandbox(ctx, &runtimeapi.RemovePodSandboxRequest{PodSandboxId: id}); err != nil {
+		log.Debugf("failed to remove pod sandbox %q: %v", id, err)
+	}
+}
+
+// GetNSPath returns the netns path of the container using the pid of its process.
+func (r *CRIRuntime) GetNSPath(ctx context.Context, cID string) (string, error) {
+	status, err := r.containerStatus(ctx, cID)
+	if err != nil {
+		return "", err
+	}
+
+	pid, err := containerPid(status)
+	if err != nil {
+		return "", err
+	}
+
+	return "/proc/" + strconv.Itoa(pid) + "/ns/net", nil
+}
+
+// GetHostsPath returns fs path to a file which is mounted as /etc/hosts into a given container.
+func (*CRIRuntime) GetHostsPath(_ context.Context, cID string) (string, error) {
+	hostsPath := containerFile(cID, hostsFile)
+	if !utils.FileExists(hostsPath) {
+		return "", fmt.Errorf("hosts file of container %s not found", cID)
+	}
+
+	log.Debugf("Method GetHostsPath was called with a resulting path %q", hostsPath)
+
+	return hostsPath, nil
+}
+
+// GetContainerStatus retrieves the ContainerStatus of the named container.
+func (r *CRIRuntime) GetContainerStatus(ctx context.Context, cID string) runtime.ContainerStatus {
+	c, err := r.container(ctx, cID)
+	if err != nil {
+		return runtime.NotFound
+	}
+
+	if c.State == runtimeapi.ContainerState_CONTAINER_RUNNING {
+		return runtime.Running
+	}
+
+	return runtime.Stopped
+}
+
+// IsContainerOOMKilled reports whether the container was killed by the OOM killer.
+func (r *CRIRuntime) IsContainerOOMKilled(ctx context.Context, cID string) (bool, error) {
+	status, err := r.containerStatus(ctx, cID)
+	if err != nil {
+		return false, err
+	}
+
+	return status.GetStatus().GetReason() == "OOMKilled", nil
+}
+
+// Exec executes cmd on container identified with id and returns stdout, stderr bytes and an error.
+func (r *CRIRuntime) Exec(ctx context.Context, cID string, execCmd *exec.ExecCmd) (*exec.ExecResult, error) {
+	c, err := r.container(ctx, cID)
+	if err != nil {
+		log.Errorf("failed to create exec in container %q: %v", cID, err)
+		return nil, err
+	}
+
+	resp, err := r.rsc.ExecSync(ctx, &runtimeapi.ExecSyncRequest{
+		ContainerId: c.Id,
+		Cmd:         execCmd.GetCmd(),
+	})
+	if err != nil {
+		log.Errorf("failed to exec in container %q: %v", cID, err)
+		return nil, err
+	}
+
+	log.Debugf("Exec in the container %q got stdout %q and stderr %q", cID, resp.Stdout, resp.Stderr)
+
+	execResult := exec.NewExecResult(execCmd)
+	execResult.SetStdOut(resp.Stdout)
+	execResult.SetStdErr(resp.Stderr)
+	execResult.SetReturnCode(int(resp.ExitCode))
+
+	return execResult, nil
+}
+
+// ExecNotWait executes cmd on container identified with id but doesn't wait for output nor attaches stdout/err.
+// The CRI API has no detached exec, so the command runs in the background and its errors are logged only.
+func (r *CRIRuntime) ExecNotWait(ctx context.Context, cID string, execCmd *exec.ExecCmd) error {
+	c, err := r.container(ctx, cID)
+	if err != nil {
+		log.Errorf("failed to create exec in container %q: %v", cID, err)
+		return err
+	}
+
+	go func() {
+		if _, err := r.rsc.ExecSync(context.Background(), &runtimeapi.ExecSyncRequest{
+			ContainerId: c.Id,
+			Cmd:         execCmd.GetCmd(),
+		}); err != nil {
+			log.Errorf("failed to exec in container %q: %v", cID, err)
+		}
+	}()
+
+	return nil
+}
+
+// CopyFromContainer copies the file by srcPath in the container to the dstPath on the host.
+// The file is read from the root filesystem of the running container.
+func (r *CRIRuntime) CopyFromContainer(ctx context.Context, cID, srcPath, dstPath string) error {
+	status, err := r.containerStatus(ctx, cID)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	pid, err := containerPid(status)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	src := filepath.Join("/proc", strconv.Itoa(pid), "root", srcPath)
+
+	fi, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	if err := utils.CopyFile(src, dstPath, fi.Mode()); err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	return nil
+}
+
+// GetContainerStats returns the current resource usage of the container as reported by the CRI runtime.
+func (r *CRIRuntime) GetContainerStats(ctx context.Context, cID string) (*runtime.ContainerStats, error) {
+	c, err := r.container(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.rsc.ContainerStats(ctx, &runtimeapi.ContainerStatsRequest{ContainerId: c.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &runtime.ContainerStats{
+		MemoryUsage: resp.GetStats().GetMemory().GetWorkingSetBytes().GetValue(),
+	}
+
+	// the nano cores are the CPU usage rate, 1e9 being a single core fully used
+	if nc := resp.GetStats().GetCpu().GetUsageNanoCores(); nc != nil {
+		stats.CPUPercent = float64(nc.GetValue()) / 1e7
+	}
+
+	return stats, nil
+}
+
+// CreateVolume is not supported, the CRI API has no volumes.
+func (*CRIRuntime) CreateVolume(_ context.Context, name string, _ map[string]string) error {
+	return fmt.Errorf("creating volume %s: %w", name, errNotSupported)
+}
+
+// ListVolumes returns no volumes, the CRI API has no volumes.
+func (*CRIRuntime) ListVolumes(_ context.Context, _ []*types.GenericFilter) ([]string, error) {
+	return nil, nil
+}
+
+// DeleteVolume is not supported, the CRI API has no volumes.
+func (*CRIRuntime) DeleteVolume(_ context.Context, name string) error {
+	return fmt.Errorf("deleting volume %s: %w", name, errNotSupported)
+}
+
+// sandboxIPs returns the addresses assigned to the pod sandbox by the CNI.
+// The CRI API reports the addresses only, so the prefix lengths are taken from the mgmt network subnets
+// and are the host prefixes when the subnets are not set.
+func (r *CRIRuntime) sandboxIPs(ctx context.Context, id string) (runtime.GenericMgmtIPs, error) {
+	ips := runtime.GenericMgmtIPs{Network: r.mgmt.Network}
+
+	resp, err := r.rsc.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: id})
+	if err != nil {
+		return ips, err
+	}
+
+	network := resp.GetStatus().GetNetwork()
+	if network == nil {
+		return ips, nil
+	}
+
+	addrs := []string{network.Ip}
+	for _, a := range network.AdditionalIps {
+		addrs = append(addrs, a.Ip)
+	}
+
+	for _, a := range addrs {
+		ip, err := netip.ParseAddr(a)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case ip.Is4() && ips.IPv4addr == "":
+			ips.IPv4addr, ips.IPv4pLen, ips.IPv4Gw = ip.String(), prefixLen(r.mgmt.IPv4Subnet, 32), r.mgmt.IPv4Gw
+		case ip.Is6() && ips.IPv6addr == "":
+			ips.IPv6addr, ips.IPv6pLen, ips.IPv6Gw = ip.String(), prefixLen(r.mgmt.IPv6Subnet, 128), r.mgmt.IPv6Gw
+		}
+	}
+
+	return ips, nil
+}
+
+// prefixLen returns the prefix length of the subnet, def when the subnet is not set or invalid.
+func prefixLen(subnet string, def int) int {
+	p, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return def
+	}
+
+	return p.Bits()
+}
+
+// GetContainerLogs returns the stdout and stderr of the container interleaved in a single stream.
+// The logs are read from the log file the CRI runtime writes, the log line prefixes are stripped.
+func (r *CRIRuntime) GetContainerLogs(ctx context.Context, cID string, opts runtime.LogOptions) (io.ReadCloser, error) {
+	if _, err := r.container(ctx, cID); err != nil {
+		return nil, err
+	}
+
+	if opts.Follow {
+		return nil, fmt.Errorf("following the logs of container %s: %w", cID, errNotSupported)
+	}
+
+	f, err := os.Open(containerFile(cID, logFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := criLogs(f, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(strings.NewReader(b)), nil
+}
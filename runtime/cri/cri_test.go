@@ -0,0 +1,198 @@
+package cri
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestMatchFilters(t *testing.T) {
+	labels := map[string]string{"containerlab": "lab", "clab-node-kind": "linux"}
+
+	tests := map[string]struct {
+		filters []*types.GenericFilter
+		want    bool
+	}{
+		"no-filters": {want: true},
+		"name": {
+			filters: []*types.GenericFilter{{FilterType: "name", Match: "clab-lab-node1"}},
+			want:    true,
+		},
+		"other-name": {
+			filters: []*types.GenericFilter{{FilterType: "name", Match: "clab-lab-node2"}},
+		},
+		"labels": {
+			filters: []*types.GenericFilter{
+				{FilterType: "label", Field: "containerlab", Operator: "=", Match: "lab"},
+				{FilterType: "label", Field: "clab-node-kind", Operator: "!=", Match: "srl"},
+				{FilterType: "label", Field: "clab-node-kind", Operator: "exists"},
+			},
+			want: true,
+		},
+		"equal-other-value": {
+			filters: []*types.GenericFilter{{FilterType: "label", Field: "containerlab", Operator: "=", Match: "lab2"}},
+		},
+		"not-equal": {
+			filters: []*types.GenericFilter{{FilterType: "label", Field: "clab-node-kind", Operator: "!=", Match: "linux"}},
+		},
+		"missing-label": {
+			filters: []*types.GenericFilter{{FilterType: "label", Field: "clab-topo-file", Operator: "exists"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := matchFilters("clab-lab-node1", labels, tt.filters); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCRILogs(t *testing.T) {
+	logs := strings.Join([]string{
+		"2023-10-01T10:00:00.000000000Z stdout F first",
+		"2023-10-01T10:00:01.000000000Z stderr P sec",
+		"2023-10-01T10:00:01.100000000Z stderr F ond",
+		"not a log line",
+		"2023-10-01T10:00:02.000000000Z stdout F third line",
+	}, "\n")
+
+	tests := map[string]struct {
+		opts runtime.LogOptions
+		want string
+	}{
+		"all": {
+			want: "first\nsecond\nthird line\n",
+		},
+		"tail": {
+			opts: runtime.LogOptions{Tail: 2},
+			want: "second\nthird line\n",
+		},
+		"since": {
+			opts: runtime.LogOptions{Since: time.Date(2023, 10, 1, 10, 0, 1, 500, time.UTC)},
+			want: "ond\nthird line\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := criLogs(strings.NewReader(logs), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerSpec(t *testing.T) {
+	node := &types.NodeConfig{
+		ShortName: "node1",
+		LongName:  "clab-lab-node1",
+		Image:     "alpine",
+		Cmd:       "sleep infinity",
+		User:      "1000:1000",
+		Env:       map[string]string{"B": "2", "A": "1"},
+		Binds:     []string{"/tmp/cfg:/cfg:ro"},
+		Labels:    map[string]string{"containerlab": "lab"},
+		Sysctls:   map[string]string{"net.ipv4.ip_forward": "1"},
+		CPU:       1.5,
+		PortBindings: nat.PortMap{
+			"80/tcp": []nat.PortBinding{{HostPort: "8080"}},
+		},
+	}
+
+	spec, err := (&CRIRuntime{}).containerSpec(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.Sandbox.Hostname != "node1" {
+		t.Errorf("got hostname %q, want node1", spec.Sandbox.Hostname)
+	}
+
+	if spec.Sandbox.Linux.SecurityContext.NamespaceOptions.Network != runtimeapi.NamespaceMode_POD {
+		t.Errorf("got network namespace mode %s, want POD", spec.Sandbox.Linux.SecurityContext.NamespaceOptions.Network)
+	}
+
+	wantPorts := []*runtimeapi.PortMapping{{Protocol: runtimeapi.Protocol_TCP, ContainerPort: 80, HostPort: 8080}}
+	if d := cmp.Diff(wantPorts, spec.Sandbox.PortMappings); d != "" {
+		t.Errorf("port mappings diff (-want +got):\n%s", d)
+	}
+
+	c := spec.Container
+
+	if d := cmp.Diff([]string{"sleep", "infinity"}, c.Args); d != "" {
+		t.Errorf("args diff (-want +got):\n%s", d)
+	}
+
+	if c.Command != nil {
+		t.Errorf("got command %v, the image entrypoint is expected", c.Command)
+	}
+
+	wantEnvs := []*runtimeapi.KeyValue{{Key: "A", Value: "1"}, {Key: "B", Value: "2"}}
+	if d := cmp.Diff(wantEnvs, c.Envs); d != "" {
+		t.Errorf("envs diff (-want +got):\n%s", d)
+	}
+
+	wantMounts := []*runtimeapi.Mount{
+		{ContainerPath: "/cfg", HostPath: "/tmp/cfg", Readonly: true},
+		{ContainerPath: "/etc/hosts", HostPath: "/var/lib/containerlab/cri/containers/clab-lab-node1/hosts"},
+	}
+	if d := cmp.Diff(wantMounts, c.Mounts); d != "" {
+		t.Errorf("mounts diff (-want +got):\n%s", d)
+	}
+
+	secCtx := c.Linux.SecurityContext
+	if !secCtx.Privileged || secCtx.RunAsUser.GetValue() != 1000 || secCtx.RunAsGroup.GetValue() != 1000 {
+		t.Errorf("unexpected security context %+v", secCtx)
+	}
+
+	if res := c.Linux.Resources; res.CpuQuota != 150000 || res.CpuPeriod != 100000 {
+		t.Errorf("got cpu quota %d and period %d, want 150000 and 100000", res.CpuQuota, res.CpuPeriod)
+	}
+}
+
+func TestContainerSpecNetworkMode(t *testing.T) {
+	tests := map[string]struct {
+		mode    string
+		want    runtimeapi.NamespaceMode
+		wantErr bool
+	}{
+		"default":   {want: runtimeapi.NamespaceMode_POD},
+		"host":      {mode: "host", want: runtimeapi.NamespaceMode_NODE},
+		"container": {mode: "container:node2", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			node := &types.NodeConfig{ShortName: "node1", LongName: "clab-lab-node1", NetworkMode: tt.mode}
+
+			spec, err := (&CRIRuntime{}).containerSpec(node)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := spec.Sandbox.Linux.SecurityContext.NamespaceOptions.Network; got != tt.want {
+				t.Errorf("got network namespace mode %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
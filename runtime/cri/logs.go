@@ -0,0 +1,80 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cri
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// criLogs returns the container output of the CRI log file with the log line prefixes stripped.
+// The CRI log lines are formatted as "<RFC3339Nano time> <stdout|stderr> <P|F> <message>",
+// the partial lines (P) are joined with the following ones.
+func criLogs(r io.Reader, opts runtime.LogOptions) (string, error) {
+	var lines []string
+
+	var partial strings.Builder
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for sc.Scan() {
+		ts, tag, msg, ok := parseLogLine(sc.Text())
+		if !ok {
+			continue
+		}
+
+		if !opts.Since.IsZero() && ts.Before(opts.Since) {
+			continue
+		}
+
+		partial.WriteString(msg)
+
+		if tag == "P" {
+			continue
+		}
+
+		lines = append(lines, partial.String()+"\n")
+		partial.Reset()
+	}
+
+	if err := sc.Err(); err != nil {
+		return "", err
+	}
+
+	if partial.Len() > 0 {
+		lines = append(lines, partial.String())
+	}
+
+	if opts.Tail > 0 && len(lines) > opts.Tail {
+		lines = lines[len(lines)-opts.Tail:]
+	}
+
+	return strings.Join(lines, ""), nil
+}
+
+// parseLogLine splits the CRI log line into its time, its tag and its message.
+func parseLogLine(line string) (time.Time, string, string, bool) {
+	parts := strings.SplitN(line, " ", 4)
+	if len(parts) < 3 {
+		return time.Time{}, "", "", false
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, "", "", false
+	}
+
+	var msg string
+	if len(parts) == 4 {
+		msg = parts[3]
+	}
+
+	return ts, parts[2], msg, true
+}
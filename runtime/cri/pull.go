@@ -0,0 +1,167 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/distribution/reference"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	dockerRuntime "github.com/srl-labs/containerlab/runtime/docker"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// dockerV1IndexAuthKey is a key under which credentials for dockerhub images are stored.
+const dockerV1IndexAuthKey = "https://index.docker.io/v1/"
+
+// PullImage pulls the container image using the provided image pull policy value, mirroring the docker runtime.
+// The CRI API pulls the images of the host platform only.
+func (r *CRIRuntime) PullImage(ctx context.Context, imageName string, pullPolicy types.PullPolicyValue,
+	platform string,
+) error {
+	log.Debugf("Looking up %s image", imageName)
+
+	canonicalImageName := utils.GetCanonicalImageName(imageName)
+
+	img, err := r.imageStatus(ctx, canonicalImageName)
+	if err != nil {
+		return err
+	}
+
+	exists := img != nil
+
+	switch pullPolicy {
+	case types.PullPolicyNever:
+		if !exists {
+			// image not found but pull policy = never
+			return fmt.Errorf("image %s not found locally, and image-pull-policy=%s prevents containerlab from pulling it", imageName, pullPolicy)
+		}
+		// image present, all good
+		log.Debugf("Image %s present, skip pulling", imageName)
+		return nil
+	case types.PullPolicyIfNotPresent:
+		if exists {
+			// pull policy == IfNotPresent and image is present
+			log.Debugf("Image %s present, skip pulling", imageName)
+			return nil
+		}
+	}
+
+	if platform != "" {
+		log.Warnf("the %s runtime pulls the images of the host platform, platform %s of image %s is ignored",
+			RuntimeName, platform, imageName)
+	}
+
+	log.Infof("Pulling %s image", canonicalImageName)
+
+	if _, err := r.isc.PullImage(ctx, &runtimeapi.PullImageRequest{
+		Image: &runtimeapi.ImageSpec{Image: canonicalImageName},
+		Auth:  registryAuth(canonicalImageName),
+	}); err != nil {
+		return runtime.NewImagePullError(RuntimeName, imageName, err)
+	}
+
+	log.Infof("Done pulling %s", canonicalImageName)
+
+	return nil
+}
+
+// imageStatus returns the local image by its name, nil if the image is not present.
+func (r *CRIRuntime) imageStatus(ctx context.Context, imageName string) (*runtimeapi.Image, error) {
+	resp, err := r.isc.ImageStatus(ctx, &runtimeapi.ImageStatusRequest{
+		Image: &runtimeapi.ImageSpec{Image: imageName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Image, nil
+}
+
+// registryAuth returns the auth of the registry of the image stored in the default docker config file.
+// Nil is returned if the config file or the registry credentials are not found.
+func registryAuth(image string) *runtimeapi.AuthConfig {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil
+	}
+
+	dockerConfig, err := dockerRuntime.GetDockerConfig("")
+	if err != nil {
+		log.Debug("docker config file not found")
+		return nil
+	}
+
+	domain := reference.Domain(ref)
+
+	keys := []string{domain}
+	// the docker hub credentials are stored under the docker.io domain or the v1 index key
+	if domain == "docker.io" {
+		keys = append(keys, dockerV1IndexAuthKey)
+	}
+
+	for _, k := range keys {
+		if auth, ok := dockerConfig.Auths[k]; ok && auth.Auth != "" {
+			return &runtimeapi.AuthConfig{Auth: auth.Auth, ServerAddress: domain}
+		}
+	}
+
+	return nil
+}
+
+// ImportImage is not supported, the CRI API has no means to load the image archives.
+func (*CRIRuntime) ImportImage(_ context.Context, tarPath string) error {
+	return fmt.Errorf("importing image archive %s: %w", tarPath, errNotSupported)
+}
+
+// BuildImage is not supported, the CRI API has no means to build the images.
+func (*CRIRuntime) BuildImage(_ context.Context, spec *types.ImageBuild) error {
+	return fmt.Errorf("building image %s: %w", spec.Tag, errNotSupported)
+}
+
+// InspectImage returns the details of the local container image.
+// The image environment is read from the verbose image info, which is reported by containerd.
+func (r *CRIRuntime) InspectImage(ctx context.Context, imageName string) (*runtime.ImageInfo, error) {
+	resp, err := r.isc.ImageStatus(ctx, &runtimeapi.ImageStatusRequest{
+		Image:   &runtimeapi.ImageSpec{Image: utils.GetCanonicalImageName(imageName)},
+		Verbose: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Image == nil {
+		return nil, fmt.Errorf("image %s: %w", imageName, errNotFound)
+	}
+
+	return &runtime.ImageInfo{
+		ID:   resp.Image.Id,
+		Size: int64(resp.Image.Size_),
+		Env:  imageEnv(resp.Info),
+	}, nil
+}
+
+// imageEnv returns the environment of the image from the verbose image info,
+// nil is returned if the info doesn't contain the image spec.
+func imageEnv(info map[string]string) []string {
+	var i struct {
+		ImageSpec struct {
+			Config struct {
+				Env []string `json:"Env"`
+			} `json:"config"`
+		} `json:"imageSpec"`
+	}
+
+	if err := json.Unmarshal([]byte(info["info"]), &i); err != nil {
+		return nil
+	}
+
+	return i.ImageSpec.Config.Env
+}
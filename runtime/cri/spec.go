@@ -0,0 +1,351 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cri
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/shlex"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// the files of a container kept in its directory in the state dir.
+const (
+	hostsFile = "hosts"
+	logFile   = "container.log"
+)
+
+// containerDir returns the directory of the container files in the state dir.
+func containerDir(cID string) string {
+	return filepath.Join(stateDir, "containers", cID)
+}
+
+// containerFile returns the path of the named container file in the container directory.
+func containerFile(cID, name string) string {
+	return filepath.Join(containerDir(cID), name)
+}
+
+// writeContainerFiles writes the hosts file of the node to the container directory,
+// the file is bind mounted into the container.
+func writeContainerFiles(node *types.NodeConfig) error {
+	dir := containerDir(node.LongName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create the directory of container %q: %w", node.LongName, err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, hostsFile), []byte(hostsContent(node.GetHostname(), node.ExtraHosts)), 0644)
+}
+
+// hostsContent returns the /etc/hosts content of a container with the hostname and the extra hosts
+// in the host:ip format.
+func hostsContent(hostname string, extraHosts []string) string {
+	var sb strings.Builder
+
+	sb.WriteString("127.0.0.1\tlocalhost\n")
+	sb.WriteString("::1\tlocalhost ip6-localhost ip6-loopback\n")
+
+	if hostname != "" {
+		sb.WriteString("127.0.1.1\t" + hostname + "\n")
+	}
+
+	for _, h := range extraHosts {
+		// the IPv6 address of an extra host contains colons too
+		name, ip, ok := strings.Cut(h, ":")
+		if !ok {
+			continue
+		}
+
+		sb.WriteString(ip + "\t" + name + "\n")
+	}
+
+	return sb.String()
+}
+
+// containerSpec is the pod sandbox and the container configs CreateContainer submits to the CRI runtime.
+type containerSpec struct {
+	Sandbox   *runtimeapi.PodSandboxConfig `json:"sandbox"`
+	Container *runtimeapi.ContainerConfig  `json:"container"`
+}
+
+// GetContainerSpec returns the pod sandbox and the container configs CreateContainer creates the container of the node with.
+func (r *CRIRuntime) GetContainerSpec(_ context.Context, node *types.NodeConfig) (interface{}, error) {
+	spec, err := r.containerSpec(node)
+	if err != nil {
+		return nil, fmt.Errorf("error while trying to create a container spec for node %q: %w", node.LongName, err)
+	}
+
+	return spec, nil
+}
+
+// containerSpec returns the pod sandbox and the container configs of the node.
+// The containers are privileged and run in their own pod sandbox named after the container.
+func (*CRIRuntime) containerSpec(node *types.NodeConfig) (*containerSpec, error) {
+	nsOpts, err := namespaceOptions(node)
+	if err != nil {
+		return nil, err
+	}
+
+	sb := &runtimeapi.PodSandboxConfig{
+		Metadata: &runtimeapi.PodSandboxMetadata{
+			Name:      node.LongName,
+			Uid:       node.LongName,
+			Namespace: podNamespace,
+		},
+		LogDirectory: containerDir(node.LongName),
+		Labels:       node.Labels,
+		PortMappings: portMappings(node),
+		Linux: &runtimeapi.LinuxPodSandboxConfig{
+			CgroupParent: node.CgroupParent,
+			Sysctls:      node.Sysctls,
+			SecurityContext: &runtimeapi.LinuxSandboxSecurityContext{
+				NamespaceOptions: nsOpts,
+				Privileged:       true,
+			},
+		},
+	}
+
+	if nsOpts.Network != runtimeapi.NamespaceMode_NODE {
+		sb.Hostname = node.GetHostname()
+	}
+
+	if node.DNS != nil {
+		sb.DnsConfig = &runtimeapi.DNSConfig{
+			Servers:  node.DNS.Servers,
+			Searches: node.DNS.Search,
+			Options:  node.DNS.Options,
+		}
+	}
+
+	mounts, err := convertMounts(node.Binds)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts = append(mounts, &runtimeapi.Mount{
+		ContainerPath: "/etc/hosts",
+		HostPath:      containerFile(node.LongName, hostsFile),
+	})
+
+	secCtx := &runtimeapi.LinuxContainerSecurityContext{
+		NamespaceOptions: nsOpts,
+		Privileged:       true,
+	}
+
+	if err := setUser(secCtx, node.User); err != nil {
+		return nil, err
+	}
+
+	res, err := resources(node)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &runtimeapi.ContainerConfig{
+		Metadata: &runtimeapi.ContainerMetadata{Name: node.LongName},
+		Image:    &runtimeapi.ImageSpec{Image: utils.GetCanonicalImageName(node.Image)},
+		Envs:     envs(node.Env),
+		Mounts:   mounts,
+		Labels:   node.Labels,
+		LogPath:  logFile,
+		Linux: &runtimeapi.LinuxContainerConfig{
+			Resources:       res,
+			SecurityContext: secCtx,
+		},
+	}
+
+	// the image entrypoint is replaced by the command and the image cmd by the args,
+	// the image cmd is not used when the command is set
+	if node.Entrypoint != "" {
+		if c.Command, err = shlex.Split(node.Entrypoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if node.Cmd != "" {
+		if c.Args, err = shlex.Split(node.Cmd); err != nil {
+			return nil, err
+		}
+	}
+
+	return &containerSpec{Sandbox: sb, Container: c}, nil
+}
+
+// namespaceOptions returns the namespace options of the node network mode.
+// The pod sandboxes get their netns from the CNI of the CRI runtime unless the host network mode is used.
+func namespaceOptions(node *types.NodeConfig) (*runtimeapi.NamespaceOption, error) {
+	opts := &runtimeapi.NamespaceOption{
+		Network: runtimeapi.NamespaceMode_POD,
+		Pid:     runtimeapi.NamespaceMode_CONTAINER,
+		Ipc:     runtimeapi.NamespaceMode_POD,
+	}
+
+	mode, _, _ := strings.Cut(node.NetworkMode, ":")
+
+	switch mode {
+	case "":
+	case "host":
+		opts.Network = runtimeapi.NamespaceMode_NODE
+	default:
+		return nil, fmt.Errorf("network-mode %q of node %q: %w", node.NetworkMode, node.ShortName, errNotSupported)
+	}
+
+	return opts, nil
+}
+
+// portMappings returns the port mappings of the node port bindings.
+func portMappings(node *types.NodeConfig) []*runtimeapi.PortMapping {
+	var pms []*runtimeapi.PortMapping
+
+	for port, bindings := range node.PortBindings {
+		proto := runtimeapi.Protocol_TCP
+
+		switch port.Proto() {
+		case "udp":
+			proto = runtimeapi.Protocol_UDP
+		case "sctp":
+			proto = runtimeapi.Protocol_SCTP
+		}
+
+		for _, b := range bindings {
+			hostPort, _ := strconv.Atoi(b.HostPort)
+
+			pms = append(pms, &runtimeapi.PortMapping{
+				Protocol:      proto,
+				ContainerPort: int32(port.Int()),
+				HostPort:      int32(hostPort),
+				HostIp:        b.HostIP,
+			})
+		}
+	}
+
+	sort.Slice(pms, func(i, j int) bool {
+		if pms[i].ContainerPort != pms[j].ContainerPort {
+			return pms[i].ContainerPort < pms[j].ContainerPort
+		}
+
+		return pms[i].HostPort < pms[j].HostPort
+	})
+
+	return pms
+}
+
+// convertMounts takes a list of filesystem mount binds in docker/clab format (src:dest:options)
+// and converts it into the CRI mounts. The named volumes are not supported, since the CRI API has no volumes.
+func convertMounts(binds []string) ([]*runtimeapi.Mount, error) {
+	mounts := make([]*runtimeapi.Mount, 0, len(binds))
+
+	for _, bind := range binds {
+		b, err := types.NewBind(bind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bind mount provided: %s", bind)
+		}
+
+		if b.IsVolume() {
+			return nil, fmt.Errorf("volume bind %s: %w", bind, errNotSupported)
+		}
+
+		m := &runtimeapi.Mount{
+			ContainerPath: b.Dst(),
+			HostPath:      b.Src(),
+		}
+
+		for _, o := range strings.Split(b.Mode(), ",") {
+			if o == "ro" {
+				m.Readonly = true
+			}
+		}
+
+		mounts = append(mounts, m)
+	}
+
+	return mounts, nil
+}
+
+// envs returns the environment of the node as the CRI key-values sorted by the key.
+func envs(env map[string]string) []*runtimeapi.KeyValue {
+	kvs := make([]*runtimeapi.KeyValue, 0, len(env))
+	for k, v := range env {
+		kvs = append(kvs, &runtimeapi.KeyValue{Key: k, Value: v})
+	}
+
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+
+	return kvs
+}
+
+// setUser sets the user the container process runs as, the user is set as name|uid[:group|gid]
+// and the group is only supported as gid.
+func setUser(secCtx *runtimeapi.LinuxContainerSecurityContext, user string) error {
+	if user == "" {
+		return nil
+	}
+
+	u, g, hasGroup := strings.Cut(user, ":")
+
+	if uid, err := strconv.ParseInt(u, 10, 64); err == nil {
+		secCtx.RunAsUser = &runtimeapi.Int64Value{Value: uid}
+	} else {
+		secCtx.RunAsUsername = u
+	}
+
+	if !hasGroup {
+		return nil
+	}
+
+	gid, err := strconv.ParseInt(g, 10, 64)
+	if err != nil {
+		return fmt.Errorf("user %q: the group must be set by its gid", user)
+	}
+
+	secCtx.RunAsGroup = &runtimeapi.Int64Value{Value: gid}
+
+	return nil
+}
+
+// resources translates the resource limits of the node to the CRI container resources.
+func resources(node *types.NodeConfig) (*runtimeapi.LinuxContainerResources, error) {
+	res := &runtimeapi.LinuxContainerResources{
+		CpusetCpus: node.CPUSet,
+	}
+
+	if node.Memory != "" {
+		mem, err := humanize.ParseBytes(node.Memory)
+		if err != nil {
+			return nil, err
+		}
+
+		res.MemoryLimitInBytes = int64(mem)
+	}
+
+	if node.MemorySwap != "" {
+		swap, err := runtime.ParseMemorySwap(node.MemorySwap)
+		if err != nil {
+			return nil, err
+		}
+
+		res.MemorySwapLimitInBytes = swap
+	}
+
+	if node.CPU != 0 {
+		res.CpuPeriod = 100000
+		res.CpuQuota = int64(node.CPU * 100000)
+	}
+
+	if node.OomScoreAdj != nil {
+		res.OomScoreAdj = int64(*node.OomScoreAdj)
+	}
+
+	return res, nil
+}
@@ -120,6 +120,20 @@ func GetDockerAuth(dockerConfig *DockerConfig, imageName string) (string, error)
 	return authString, nil
 }
 
+// registryAuth returns the auth string for the registry of the given container image name
+// based on the credentials stored in the default docker config file.
+// An empty auth string is returned if the config file is not found.
+func registryAuth(imageName string) (string, error) {
+	// get docker config based on an empty path (default docker config path will be assumed)
+	dockerConfig, err := GetDockerConfig("")
+	if err != nil {
+		log.Debug("docker config file not found")
+		return "", nil
+	}
+
+	return GetDockerAuth(dockerConfig, imageName)
+}
+
 // getAuthString fetches the authentication string from config.json
 // for a given image domain name.
 func getAuthString(imageDomain string, auths map[string]DockerConfigAuth) string {
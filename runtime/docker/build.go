@@ -0,0 +1,75 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// BuildImage builds the image from the Dockerfile of the build context and tags it with the build tag.
+// The built image is labeled with the hash of the build context, so that the build is skipped
+// when the image with the tag is built from the same context, unless the build is forced.
+func (d *DockerRuntime) BuildImage(ctx context.Context, spec *types.ImageBuild) error {
+	files, err := utils.BuildContextFiles(spec.Context, spec.GetDockerfile())
+	if err != nil {
+		return err
+	}
+
+	hash, err := utils.BuildContextHash(spec.Context, files)
+	if err != nil {
+		return fmt.Errorf("failed to hash the build context %q: %w", spec.Context, err)
+	}
+
+	if !spec.Force && d.imageBuiltFrom(ctx, spec.Tag, hash) {
+		log.Infof("Image %s is built from the unchanged context %s, skip building", spec.Tag, spec.Context)
+		return nil
+	}
+
+	log.Infof("Building image %s from %s", spec.Tag, spec.Context)
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(utils.WriteBuildContext(pw, spec.Context, files))
+	}()
+	defer pr.Close()
+
+	resp, err := d.Client.ImageBuild(ctx, pr, dockerTypes.ImageBuildOptions{
+		Tags:       []string{spec.Tag},
+		Dockerfile: spec.GetDockerfile(),
+		Labels:     map[string]string{labels.ImageBuildContextHash: hash},
+		Remove:     true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build image %s: %w", spec.Tag, err)
+	}
+	defer resp.Body.Close()
+
+	// the build errors are reported in the same json stream format the image pull uses
+	if err := readImagePullStream(resp.Body); err != nil {
+		return fmt.Errorf("failed to build image %s: %w", spec.Tag, err)
+	}
+
+	log.Infof("Done building %s", spec.Tag)
+
+	return nil
+}
+
+// imageBuiltFrom returns true if the image with the tag exists and is built from the build context with the hash.
+func (d *DockerRuntime) imageBuiltFrom(ctx context.Context, tag, hash string) bool {
+	ii, _, err := d.Client.ImageInspectWithRaw(ctx, tag)
+	if err != nil || ii.Config == nil {
+		return false
+	}
+
+	return ii.Config.Labels[labels.ImageBuildContextHash] == hash
+}
@@ -0,0 +1,150 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	dockerC "github.com/docker/docker/client"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// fakeBuildDaemon is a docker daemon serving the image inspect and build requests.
+type fakeBuildDaemon struct {
+	mu sync.Mutex
+	// images are the context hash labels of the images, keyed by the tag
+	images map[string]string
+	// builds are the tags of the build requests
+	builds []string
+	// files are the names of the files of the build context of the last build
+	files []string
+}
+
+func (f *fakeBuildDaemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/json") && strings.Contains(r.URL.Path, "/images/"):
+		tag := strings.TrimSuffix(r.URL.Path[strings.Index(r.URL.Path, "/images/")+len("/images/"):], "/json")
+
+		hash, ok := f.images[tag]
+		if !ok {
+			http.Error(w, `{"message":"No such image"}`, http.StatusNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(dockerTypes.ImageInspect{
+			ID:     "sha256:1",
+			Config: &container.Config{Labels: map[string]string{labels.ImageBuildContextHash: hash}},
+		})
+
+	case strings.HasSuffix(r.URL.Path, "/build"):
+		tag := r.URL.Query().Get("t")
+
+		f.files = nil
+		tr := tar.NewReader(r.Body)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f.files = append(f.files, hdr.Name)
+		}
+
+		var lbls map[string]string
+		json.Unmarshal([]byte(r.URL.Query().Get("labels")), &lbls)
+
+		f.builds = append(f.builds, tag)
+		f.images[tag] = lbls[labels.ImageBuildContextHash]
+
+		fmt.Fprintf(w, `{"stream":"Successfully tagged %s\n"}`, tag)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestBuildImage(t *testing.T) {
+	daemon := &fakeBuildDaemon{images: map[string]string{}}
+
+	srv := httptest.NewServer(daemon)
+	defer srv.Close()
+
+	client, err := dockerC.NewClientWithOpts(dockerC.WithHTTPClient(srv.Client()),
+		dockerC.WithHost("tcp://"+srv.Listener.Addr().String()), dockerC.WithVersion("1.41"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &DockerRuntime{Client: client}
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{"Dockerfile": "FROM alpine", "app.sh": "echo 1", ".dockerignore": "*.log", "x.log": ""} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spec := &types.ImageBuild{Context: dir, Tag: "lab/app:latest"}
+	ctx := context.Background()
+
+	steps := []struct {
+		name   string
+		change func()
+		builds int
+	}{
+		{name: "first build", builds: 1},
+		{name: "unchanged context", builds: 1},
+		{name: "forced", change: func() { spec.Force = true }, builds: 2},
+		{name: "changed context", change: func() {
+			spec.Force = false
+			if err := os.WriteFile(filepath.Join(dir, "app.sh"), []byte("echo 2"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}, builds: 3},
+	}
+
+	for _, s := range steps {
+		if s.change != nil {
+			s.change()
+		}
+
+		if err := d.BuildImage(ctx, spec); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+
+		if len(daemon.builds) != s.builds {
+			t.Errorf("%s: got %d builds, want %d", s.name, len(daemon.builds), s.builds)
+		}
+	}
+
+	if got := strings.Join(daemon.files, ","); got != ".dockerignore,Dockerfile,app.sh" {
+		t.Errorf("got build context files %s", got)
+	}
+
+	files, _ := utils.BuildContextFiles(dir, types.DefaultDockerfile)
+	hash, _ := utils.BuildContextHash(dir, files)
+
+	if daemon.images[spec.Tag] != hash {
+		t.Errorf("built image is labeled with hash %q, want %q", daemon.images[spec.Tag], hash)
+	}
+}
@@ -7,6 +7,7 @@ package docker
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -23,12 +24,15 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
 	dockerC "github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/dustin/go-humanize"
 	"github.com/google/shlex"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
 	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -43,6 +47,8 @@ const (
 	rLimitMaxValue = 1048576
 	// defaultDockerNetwork is a name of a docker network that docker uses by default when creating containers.
 	defaultDockerNetwork = "bridge"
+	// certsDir is the directory the docker daemon reads the registry TLS material from.
+	certsDir = "/etc/docker/certs.d"
 )
 
 func init() {
@@ -57,18 +63,42 @@ type DockerRuntime struct {
 	config runtime.RuntimeConfig
 	Client *dockerC.Client
 	mgmt   *types.MgmtNet
+	// imageNames caches the canonical names of the pulled images by the original image names
+	imageNames *runtime.LookupCache
+	// registryAuths caches the auth strings by the registry host
+	registryAuths *runtime.LookupCache
+	// registryTLS caches the installation of the registry TLS material by the registry host
+	registryTLS *runtime.LookupCache
 }
 
 func (d *DockerRuntime) Init(opts ...runtime.RuntimeOption) error {
 	var err error
 	log.Debug("Runtime: Docker")
-	d.Client, err = dockerC.NewClientWithOpts(dockerC.FromEnv, dockerC.WithAPIVersionNegotiation())
+	for _, o := range opts {
+		o(d)
+	}
+
+	clientOpts := []dockerC.Opt{dockerC.FromEnv, dockerC.WithAPIVersionNegotiation()}
+	// the socket set by a user takes precedence over the DOCKER_HOST env var
+	if d.config.Socket != "" {
+		clientOpts = append(clientOpts, dockerC.WithHost(runtime.SocketURI(d.config.Socket)))
+	}
+
+	d.Client, err = dockerC.NewClientWithOpts(clientOpts...)
 	if err != nil {
 		return err
 	}
-	for _, o := range opts {
-		o(d)
+
+	// the runtimes initialized without the mgmt network, e.g. by the tools commands, skip the detection
+	if d.mgmt != nil {
+		d.detectMgmtNet()
 	}
+
+	// the lookups are cached for the lifetime of the runtime, so the nodes sharing the images
+	// resolve and authenticate them once
+	d.imageNames = runtime.NewLookupCache()
+	d.registryAuths = runtime.NewLookupCache()
+	d.registryTLS = runtime.NewLookupCache()
 	d.config.VerifyLinkParams = links.NewVerifyLinkParams()
 	return nil
 }
@@ -86,6 +116,10 @@ func (d *DockerRuntime) WithConfig(cfg *runtime.RuntimeConfig) {
 	d.config.Timeout = cfg.Timeout
 	d.config.Debug = cfg.Debug
 	d.config.GracefulShutdown = cfg.GracefulShutdown
+	d.config.LabName = cfg.LabName
+	d.config.Socket = cfg.Socket
+	d.config.RegistryTLS = cfg.RegistryTLS
+	d.config.PullRetries = cfg.PullRetries
 	if d.config.Timeout <= 0 {
 		d.config.Timeout = defaultTimeout
 	}
@@ -93,8 +127,13 @@ func (d *DockerRuntime) WithConfig(cfg *runtime.RuntimeConfig) {
 
 func (d *DockerRuntime) WithMgmtNet(n *types.MgmtNet) {
 	d.mgmt = n
+}
+
+// detectMgmtNet populates the mgmt network MTU and bridge name that are not set by a user
+// with the values of the existing docker networks.
+func (d *DockerRuntime) detectMgmtNet() {
 	// return if MTU value was set by a user via config file
-	if n.MTU != 0 {
+	if d.mgmt.MTU != 0 {
 		return
 	}
 
@@ -137,6 +176,9 @@ func (d *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 
 	// linux bridge name that is used by docker network
 	bridgeName := d.mgmt.Bridge
+	// created is set when the network is created by this call and not reused,
+	// only the created network is rolled back on failure
+	created := false
 
 	log.Debugf("Checking if docker network %q exists", d.mgmt.Network)
 	netResource, err := d.Client.NetworkInspect(nctx, d.mgmt.Network, dockerTypes.NetworkInspectOptions{})
@@ -146,6 +188,7 @@ func (d *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 		if err != nil {
 			return err
 		}
+		created = true
 	case err == nil:
 		log.Debugf("network %q was found. Reusing it...", d.mgmt.Network)
 		if len(netResource.ID) < 12 {
@@ -166,6 +209,7 @@ func (d *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 		return err
 	}
 
+	userBridge := d.mgmt.Bridge
 	if d.mgmt.Bridge == "" {
 		d.mgmt.Bridge = bridgeName
 	}
@@ -175,12 +219,45 @@ func (d *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 	// this was added to allow mgmt network gw ip to be available in a startup config templation step (ceos)
 	d.mgmt.IPv4Gw, d.mgmt.IPv6Gw, err = getMgmtBridgeIPs(bridgeName, netResource)
 	if err != nil {
-		return err
+		return d.rollbackNet(ctx, created, userBridge, err)
 	}
 
 	log.Debugf("Docker network %q, bridge name %q", d.mgmt.Network, bridgeName)
 
-	return d.postCreateNetActions()
+	// host tuning failures leave the network functional, so it is not rolled back
+	err = d.postCreateNetActions()
+	var tuningErr *runtime.HostTuningError
+	if err != nil && !errors.As(err, &tuningErr) {
+		return d.rollbackNet(ctx, created, userBridge, err)
+	}
+
+	return err
+}
+
+// rollbackNet deletes the mgmt network that was created by CreateNet call which failed afterwards
+// so that the next deployment doesn't reuse a half-configured network.
+// Networks that were reused by CreateNet are never deleted.
+// The original error is returned.
+func (d *DockerRuntime) rollbackNet(ctx context.Context, created bool, userBridge string, err error) error {
+	if !created {
+		return err
+	}
+
+	log.Warnf("Failed to configure the newly created docker network %q, removing it: %v", d.mgmt.Network, err)
+
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	if rmErr := d.Client.NetworkRemove(nctx, d.mgmt.Network); rmErr != nil {
+		log.Errorf("failed to remove docker network %q: %v", d.mgmt.Network, rmErr)
+		return err
+	}
+
+	// restore the bridge name to what a user has provided
+	// as the generated name belonged to the removed network
+	d.mgmt.Bridge = userBridge
+
+	return err
 }
 
 func (d *DockerRuntime) createMgmtBridge(nctx context.Context, bridgeName string) (string, error) {
@@ -255,10 +332,8 @@ func (d *DockerRuntime) createMgmtBridge(nctx context.Context, bridgeName string
 		IPAM:           ipam,
 		Internal:       false,
 		Attachable:     false,
-		Labels: map[string]string{
-			"containerlab": "",
-		},
-		Options: netwOpts,
+		Labels:         d.netLabels(),
+		Options:        netwOpts,
 	}
 
 	netCreateResponse, err := d.Client.NetworkCreate(nctx, d.mgmt.Network, opts)
@@ -307,6 +382,7 @@ func getMgmtBridgeIPs(bridgeName string, netResource dockerTypes.NetworkResource
 }
 
 // postCreateNetActions performs additional actions after the network has been created.
+// Failures of the bridge tuning actions are returned as *runtime.HostTuningError.
 func (d *DockerRuntime) postCreateNetActions() (err error) {
 	log.Debug("Disable RPF check on the docker host")
 	err = setSysctl("net/ipv4/conf/all/rp_filter", 0)
@@ -318,25 +394,29 @@ func (d *DockerRuntime) postCreateNetActions() (err error) {
 		return fmt.Errorf("failed to disable RP filter on docker host for the 'default' scope: %v", err)
 	}
 
-	log.Debugf("Enable LLDP on the linux bridge %s", d.mgmt.Bridge)
-	file := "/sys/class/net/" + d.mgmt.Bridge + "/bridge/group_fwd_mask"
+	tuningErr := &runtime.HostTuningError{
+		Bridge:        d.mgmt.Bridge,
+		Containerized: utils.IsRunningInContainer(),
+	}
 
-	err = os.WriteFile(file, []byte(strconv.Itoa(16384)), 0640) // skipcq: GO-S2306
+	log.Debugf("Enable LLDP on the linux bridge %s", d.mgmt.Bridge)
+	err = utils.EnableBridgeLLDP(d.mgmt.Bridge)
 	if err != nil {
-		log.Warnf("failed to enable LLDP on docker bridge: %v", err)
+		tuningErr.Add("lldp-forwarding",
+			fmt.Sprintf("echo %d > /sys/class/net/%s/bridge/group_fwd_mask", utils.BridgeGroupFwdMaskLLDP, d.mgmt.Bridge), err)
 	}
 
 	log.Debugf("Disabling TX checksum offloading for the %s bridge interface...", d.mgmt.Bridge)
 	err = utils.EthtoolTXOff(d.mgmt.Bridge)
 	if err != nil {
-		log.Warnf("failed to disable TX checksum offloading for the %s bridge interface: %v", d.mgmt.Bridge, err)
+		tuningErr.Add("tx-checksum-offload", fmt.Sprintf("ethtool -K %s tx off", d.mgmt.Bridge), err)
 	}
 	err = d.installIPTablesFwdRule()
 	if err != nil {
 		log.Warnf("errors during iptables rules install: %v", err)
 	}
 
-	return nil
+	return tuningErr.ErrOrNil()
 }
 
 // DeleteNet deletes a docker bridge.
@@ -353,6 +433,23 @@ func (d *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+
+	// the network created by another lab is only deleted when that lab is gone,
+	// its stopped containers would fail to start without the network
+	if owner := runtime.ForeignNetOwner(nres.Labels, d.config.LabName); owner != "" {
+		ctrs, err := d.ListContainers(ctx, []*types.GenericFilter{{
+			FilterType: "label", Field: labels.Containerlab, Operator: "=", Match: owner,
+		}})
+		if err != nil {
+			return err
+		}
+
+		if len(ctrs) > 0 {
+			log.Debugf("network %q is used by the lab %q, deletion skipped", network, owner)
+			return nil
+		}
+	}
+
 	numEndpoints := len(nres.Containers)
 	if numEndpoints > 0 {
 		if d.config.Debug {
@@ -376,6 +473,55 @@ func (d *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 	return nil
 }
 
+// netLabels returns the labels of the mgmt network created by the runtime.
+func (d *DockerRuntime) netLabels() map[string]string {
+	l := map[string]string{
+		labels.Containerlab: "",
+	}
+
+	if d.config.LabName != "" {
+		l[labels.LabName] = d.config.LabName
+	}
+
+	return l
+}
+
+// InspectMgmtNet returns the details of the existing docker mgmt network.
+// Nil is returned if the network doesn't exist.
+func (d *DockerRuntime) InspectMgmtNet(ctx context.Context) (*runtime.NetworkInfo, error) {
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	nres, err := d.Client.NetworkInspect(nctx, d.mgmt.Network, dockerTypes.NetworkInspectOptions{})
+	switch {
+	case dockerC.IsErrNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	netInfo := &runtime.NetworkInfo{
+		Name:   nres.Name,
+		Labels: nres.Labels,
+	}
+
+	for _, cfg := range nres.IPAM.Config {
+		if strings.Contains(cfg.Subnet, ":") {
+			netInfo.IPv6Subnet = cfg.Subnet
+			netInfo.IPv6Gateway = cfg.Gateway
+			continue
+		}
+		netInfo.IPv4Subnet = cfg.Subnet
+		netInfo.IPv4Gateway = cfg.Gateway
+	}
+
+	if mtu, err := strconv.Atoi(nres.Options["com.docker.network.driver.mtu"]); err == nil {
+		netInfo.MTU = mtu
+	}
+
+	return netInfo, nil
+}
+
 // PauseContainer Pauses a container identified by its name.
 func (d *DockerRuntime) PauseContainer(ctx context.Context, cID string) error {
 	return d.Client.ContainerPause(ctx, cID)
@@ -392,16 +538,56 @@ func (d *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
 	defer cancel()
 
-	cmd, err := shlex.Split(node.Cmd)
+	spec, err := d.containerSpec(nctx, node)
 	if err != nil {
 		return "", err
 	}
 
+	cont, err := d.Client.ContainerCreate(
+		nctx,
+		spec.Config,
+		spec.HostConfig,
+		spec.NetworkingConfig,
+		spec.Platform,
+		spec.Name,
+	)
+	log.Debugf("Container %q create response: %+v", node.ShortName, cont)
+	if err != nil {
+		return "", err
+	}
+	return cont.ID, nil
+}
+
+// containerSpec is the set of the container create request parameters.
+type containerSpec struct {
+	Name             string                    `json:"name"`
+	Config           *container.Config         `json:"config"`
+	HostConfig       *container.HostConfig     `json:"host-config"`
+	NetworkingConfig *network.NetworkingConfig `json:"networking-config"`
+	Platform         *ocispec.Platform         `json:"platform,omitempty"`
+}
+
+// GetContainerSpec returns the container, host and networking configs CreateContainer submits
+// to create the container of the node.
+func (d *DockerRuntime) GetContainerSpec(ctx context.Context, node *types.NodeConfig) (interface{}, error) {
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	return d.containerSpec(nctx, node)
+}
+
+// containerSpec builds the container create request parameters of the node.
+func (d *DockerRuntime) containerSpec(ctx context.Context, node *types.NodeConfig) (*containerSpec, error) {
+	cmd, err := shlex.Split(node.Cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	var entrypoint []string
 	if node.Entrypoint != "" {
 		entrypoint, err = shlex.Split(node.Entrypoint)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
@@ -412,27 +598,17 @@ func (d *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 		Env:          utils.ConvertEnvs(node.Env),
 		AttachStdout: true,
 		AttachStderr: true,
-		Hostname:     node.ShortName,
+		Hostname:     node.GetHostname(),
 		Tty:          true,
 		User:         node.User,
 		Labels:       node.Labels,
 		ExposedPorts: node.PortSet,
 		MacAddress:   node.MacAddress,
+		Healthcheck:  containerHealthcheck(node),
 	}
-	var resources container.Resources
-	if node.Memory != "" {
-		mem, err := humanize.ParseBytes(node.Memory)
-		if err != nil {
-			return "", err
-		}
-		resources.Memory = int64(mem)
-	}
-	if node.CPU != 0 {
-		resources.CPUQuota = int64(node.CPU * 100000)
-		resources.CPUPeriod = 100000
-	}
-	if node.CPUSet != "" {
-		resources.CpusetCpus = node.CPUSet
+	resources, err := containerResources(node)
+	if err != nil {
+		return nil, err
 	}
 	var rlimit unix.Rlimit
 	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &rlimit); err != nil {
@@ -458,6 +634,11 @@ func (d *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 		ExtraHosts:  node.ExtraHosts, // add static /etc/hosts entries
 		Resources:   resources,
 		AutoRemove:  node.AutoRemove,
+		Init:        node.Init,
+	}
+
+	if node.OomScoreAdj != nil {
+		containerHostConfig.OomScoreAdj = *node.OomScoreAdj
 	}
 
 	if node.DNS != nil {
@@ -469,7 +650,7 @@ func (d *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 	containerNetworkingConfig := &network.NetworkingConfig{}
 
 	if err := d.processNetworkMode(ctx, containerNetworkingConfig, containerHostConfig, containerConfig, node); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// regular linux containers may benefit from automatic restart on failure
@@ -478,19 +659,21 @@ func (d *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 		containerHostConfig.RestartPolicy.Name = "on-failure"
 	}
 
-	cont, err := d.Client.ContainerCreate(
-		nctx,
-		containerConfig,
-		containerHostConfig,
-		containerNetworkingConfig,
-		nil,
-		node.LongName,
-	)
-	log.Debugf("Container %q create response: %+v", node.ShortName, cont)
-	if err != nil {
-		return "", err
+	var platform *ocispec.Platform
+	if node.Platform != "" {
+		platform, err = utils.ParsePlatform(node.Platform)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return cont.ID, nil
+
+	return &containerSpec{
+		Name:             node.LongName,
+		Config:           containerConfig,
+		HostConfig:       containerHostConfig,
+		NetworkingConfig: containerNetworkingConfig,
+		Platform:         platform,
+	}, nil
 }
 
 // GetNSPath inspects a container by its name/id and returns a netns path using the pid of a container.
@@ -506,12 +689,29 @@ func (d *DockerRuntime) GetNSPath(ctx context.Context, cID string) (string, erro
 }
 
 // PullImage pulls the container image using the provided image pull policy value.
-func (d *DockerRuntime) PullImage(ctx context.Context, imageName string, pullpolicy types.PullPolicyValue) error {
+// When the platform is set, the image variant of that platform is pulled,
+// and the local image of a different platform is considered absent.
+func (d *DockerRuntime) PullImage(ctx context.Context, imageName string, pullpolicy types.PullPolicyValue, platform string) error {
 	log.Debugf("Looking up %s Docker image", imageName)
 
-	canonicalImageName := utils.GetCanonicalImageName(imageName)
+	canonicalImageName, _ := d.imageNames.Get(imageName, func() (string, error) {
+		return utils.GetCanonicalImageName(imageName), nil
+	})
+
+	var p *ocispec.Platform
+	if platform != "" {
+		var err error
+		if p, err = utils.ParsePlatform(platform); err != nil {
+			return err
+		}
+	}
+
+	ii, b, _ := d.Client.ImageInspectWithRaw(ctx, canonicalImageName)
+	if b != nil && !imageMatchesPlatform(ii, p) {
+		log.Debugf("Image %s present, but its platform %s/%s doesn't match %s", imageName, ii.Os, ii.Architecture, platform)
+		b = nil
+	}
 
-	_, b, _ := d.Client.ImageInspectWithRaw(ctx, canonicalImageName)
 	switch pullpolicy {
 	case types.PullPolicyNever:
 		if b == nil {
@@ -530,32 +730,156 @@ func (d *DockerRuntime) PullImage(ctx context.Context, imageName string, pullpol
 	}
 
 	// If Image doesn't exist or pullpolicy=always, we need to pull it
-	authString := ""
+	registry := getImageDomainName(canonicalImageName)
+	authString, err := d.registryAuths.Get(registry, func() (string, error) {
+		return registryAuth(canonicalImageName)
+	})
+	if err != nil {
+		return err
+	}
 
-	// get docker config based on an empty path (default docker config path will be assumed)
-	dockerConfig, err := GetDockerConfig("")
+	_, err = d.registryTLS.Get(registry, func() (string, error) {
+		return "", d.installRegistryTLS(registry)
+	})
 	if err != nil {
-		log.Debug("docker config file not found")
-	} else {
-		authString, err = GetDockerAuth(dockerConfig, canonicalImageName)
+		return err
+	}
+
+	log.Infof("Pulling %s Docker image", canonicalImageName)
+	err = runtime.PullWithRetries(ctx, canonicalImageName, runtime.PullRetries(d.config.PullRetries), func() error {
+		reader, err := d.Client.ImagePull(ctx, canonicalImageName, dockerTypes.ImagePullOptions{
+			RegistryAuth: authString,
+			Platform:     platform,
+		})
 		if err != nil {
 			return err
 		}
-	}
+		defer reader.Close()
 
-	log.Infof("Pulling %s Docker image", canonicalImageName)
-	reader, err := d.Client.ImagePull(ctx, canonicalImageName, dockerTypes.ImagePullOptions{
-		RegistryAuth: authString,
+		// must read from reader, otherwise image is not properly pulled.
+		// registry errors that happen after the pull has started are reported in the stream.
+		return readImagePullStream(reader)
 	})
+	if err != nil {
+		return d.imagePullError(ctx, imageName, err)
+	}
+	log.Infof("Done pulling %s", canonicalImageName)
+
+	return nil
+}
+
+// ImportImage loads the images of the image archive created with `docker save`.
+func (d *DockerRuntime) ImportImage(ctx context.Context, tarPath string) error {
+	f, err := os.Open(tarPath)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 
-	// must read from reader, otherwise image is not properly pulled
-	_, _ = io.Copy(io.Discard, reader)
-	log.Infof("Done pulling %s", canonicalImageName)
+	log.Infof("Importing images from %s", tarPath)
+	resp, err := d.Client.ImageLoad(ctx, f, true)
+	if err != nil {
+		return fmt.Errorf("failed to import image archive %q: %w", tarPath, err)
+	}
+	defer resp.Body.Close()
 
-	return reader.Close()
+	// the load errors are reported in the same json stream format the image pull uses
+	if err := readImagePullStream(resp.Body); err != nil {
+		return fmt.Errorf("failed to import image archive %q: %w", tarPath, err)
+	}
+	log.Infof("Done importing %s", tarPath)
+
+	return nil
+}
+
+// imageMatchesPlatform returns true if the inspected image is built for the platform p.
+// A nil platform matches any image.
+func imageMatchesPlatform(ii dockerTypes.ImageInspect, p *ocispec.Platform) bool {
+	if p == nil {
+		return true
+	}
+
+	if ii.Os != p.OS || ii.Architecture != p.Architecture {
+		return false
+	}
+
+	return p.Variant == "" || ii.Variant == p.Variant
+}
+
+// imagePullMessage is a message of the json stream returned by the image pull.
+// Only the error fields are decoded.
+type imagePullMessage struct {
+	Error       string `json:"error,omitempty"`
+	ErrorDetail *struct {
+		Message string `json:"message,omitempty"`
+	} `json:"errorDetail,omitempty"`
+}
+
+// readImagePullStream reads the json message stream returned by the image pull
+// and returns the error reported in the stream, if any.
+func readImagePullStream(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	for {
+		var msg imagePullMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		switch {
+		case msg.ErrorDetail != nil && msg.ErrorDetail.Message != "":
+			return errors.New(msg.ErrorDetail.Message)
+		case msg.Error != "":
+			return errors.New(msg.Error)
+		}
+	}
+}
+
+// installRegistryTLS installs the client TLS material of the mTLS-protected registry
+// to the docker daemon certs directory, so that the daemon uses it when pulling from the registry.
+func (d *DockerRuntime) installRegistryTLS(registry string) error {
+	tc, ok := d.config.RegistryTLS[registry]
+	if !ok {
+		return nil
+	}
+
+	// the certs directory is read by the daemon, so the files installed on this host
+	// are not seen by a remote daemon
+	if !strings.HasPrefix(d.Client.DaemonHost(), "unix://") {
+		log.Warnf("Registry %s TLS material is installed to %s on this host, make sure it is present on the docker daemon host %s",
+			registry, certsDir, d.Client.DaemonHost())
+	}
+
+	log.Debugf("Installing registry %s TLS material to %s", registry, certsDir)
+
+	return runtime.InstallRegistryTLS(certsDir, registry, tc)
+}
+
+// imagePullError wraps the error returned by the image pull into runtime.ImagePullError
+// and for the not found images tries to find a similar image available locally.
+func (d *DockerRuntime) imagePullError(ctx context.Context, imageName string, err error) error {
+	pullErr := runtime.NewImagePullError(RuntimeName, imageName, err)
+
+	if pullErr.Class != runtime.ImagePullErrorNotFound {
+		return pullErr
+	}
+
+	imgs, lErr := d.Client.ImageList(ctx, dockerTypes.ImageListOptions{})
+	if lErr != nil {
+		log.Debugf("failed to list local images: %v", lErr)
+		return pullErr
+	}
+
+	var localImages []string
+	for _, img := range imgs {
+		localImages = append(localImages, img.RepoTags...)
+	}
+
+	pullErr.LocalMatch = runtime.ClosestImageName(imageName, localImages)
+
+	return pullErr
 }
 
 // StartContainer starts a docker container.
@@ -680,6 +1004,40 @@ func (*DockerRuntime) buildFilterString(gFilters []*types.GenericFilter) filters
 	return filter
 }
 
+// setContainerState sets the exit code, the restart count and the start and finish times
+// of the last run of the container from its inspect data.
+func setContainerState(ctr *runtime.GenericContainer, inspect *dockerTypes.ContainerJSON) {
+	if inspect.ContainerJSONBase == nil {
+		return
+	}
+
+	ctr.RestartCount = inspect.RestartCount
+
+	if inspect.State == nil {
+		return
+	}
+
+	ctr.ExitCode = inspect.State.ExitCode
+	ctr.StartedAt = parseStateTime(inspect.State.StartedAt)
+	ctr.FinishedAt = parseStateTime(inspect.State.FinishedAt)
+
+	ctr.Health = runtime.HealthNone
+	if inspect.State.Health != nil {
+		ctr.Health = inspect.State.Health.Status
+	}
+}
+
+// parseStateTime parses the time reported in the container state.
+// Docker reports the times that are not set as "0001-01-01T00:00:00Z", those and the invalid ones are zero.
+func parseStateTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.IsZero() {
+		return time.Time{}
+	}
+
+	return t
+}
+
 // Transform docker-specific to generic container format.
 func (d *DockerRuntime) produceGenericContainerList(ctx context.Context, inputContainers []dockerTypes.Container,
 	inputNetworkResources []dockerTypes.NetworkResource,
@@ -705,6 +1063,7 @@ func (d *DockerRuntime) produceGenericContainerList(ctx context.Context, inputCo
 			Status:          i.Status,
 			Labels:          i.Labels,
 			NetworkSettings: runtime.GenericMgmtIPs{},
+			Created:         time.Unix(i.Created, 0),
 		}
 
 		ctr.Ports = make([]*types.GenericPortBinding, len(i.Ports))
@@ -716,10 +1075,16 @@ func (d *DockerRuntime) produceGenericContainerList(ctx context.Context, inputCo
 
 		bridgeName := d.mgmt.Network
 
-		var err error
-		ctr.Pid, err = d.containerPid(ctx, i.ID)
+		inspect, err := d.Client.ContainerInspect(ctx, i.ID)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("container %q cannot be found", i.ID)
+		}
+
+		ctr.Pid = inspect.State.Pid
+		setContainerState(&ctr, &inspect)
+
+		if inspect.Config != nil {
+			ctr.Env = inspect.Config.Env
 		}
 
 		// if bridgeName is empty, try to find a network created by clab that the container is connected to
@@ -752,6 +1117,7 @@ func (d *DockerRuntime) produceGenericContainerList(ctx context.Context, inputCo
 		}
 
 		if ifcfg, ok := i.NetworkSettings.Networks[bridgeName]; ok {
+			ctr.NetworkSettings.Network = bridgeName
 			ctr.NetworkSettings.IPv4addr = ifcfg.IPAddress
 			ctr.NetworkSettings.IPv4pLen = ifcfg.IPPrefixLen
 			ctr.NetworkSettings.IPv6addr = ifcfg.GlobalIPv6Address
@@ -761,12 +1127,15 @@ func (d *DockerRuntime) produceGenericContainerList(ctx context.Context, inputCo
 		}
 
 		// populating mounts information
-		var mount runtime.ContainerMount
 		for _, m := range i.Mounts {
-			mount.Source = m.Source
-			mount.Destination = m.Destination
+			ctr.Mounts = append(ctr.Mounts, runtime.ContainerMount{
+				Source:      m.Source,
+				Destination: m.Destination,
+				Type:        string(m.Type),
+				Name:        m.Name,
+				ReadOnly:    !m.RW,
+			})
 		}
-		ctr.Mounts = append(ctr.Mounts, mount)
 
 		result = append(result, ctr)
 	}
@@ -863,7 +1232,7 @@ func (d *DockerRuntime) DeleteContainer(ctx context.Context, cID string) error {
 	force := !d.config.GracefulShutdown
 	if d.config.GracefulShutdown {
 		log.Infof("Stopping container: %s", cID)
-		timeout := int(d.config.Timeout)
+		timeout := d.stopTimeout()
 		err = d.Client.ContainerStop(ctx, cID, container.StopOptions{Timeout: &timeout})
 		if err != nil {
 			log.Errorf("could not stop container %q: %v", cID, err)
@@ -884,8 +1253,24 @@ func setSysctl(sysctl string, newVal int) error {
 	return os.WriteFile(path.Join(sysctlBase, sysctl), []byte(strconv.Itoa(newVal)), 0600)
 }
 
-func (d *DockerRuntime) StopContainer(ctx context.Context, name string) error {
-	return d.Client.ContainerKill(ctx, name, "kill")
+// StopContainer stops the container sending it the signal, SIGTERM when empty,
+// and killing it with SIGKILL if it doesn't exit within the runtime timeout.
+func (d *DockerRuntime) StopContainer(ctx context.Context, name, signal string) error {
+	sig, err := runtime.ParseStopSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	timeout := d.stopTimeout()
+
+	log.Debugf("Stopping container %s with signal %d, it is killed if it doesn't exit within %ds", name, sig, timeout)
+
+	return d.Client.ContainerStop(ctx, name, container.StopOptions{Signal: strconv.Itoa(int(sig)), Timeout: &timeout})
+}
+
+// stopTimeout returns the seconds the container is given to exit after the stop signal before it is killed.
+func (d *DockerRuntime) stopTimeout() int {
+	return int(d.config.Timeout.Seconds())
 }
 
 // GetHostsPath returns fs path to a file which is mounted as /etc/hosts into a given container.
@@ -969,6 +1354,56 @@ func (d *DockerRuntime) processNetworkMode(
 	return nil
 }
 
+// containerHealthcheck translates the health check of the node to the docker container health check.
+// Nil is returned for the nodes without the health check, so that the HEALTHCHECK of the image is used.
+func containerHealthcheck(node *types.NodeConfig) *container.HealthConfig {
+	hc := node.Healthcheck
+	if hc == nil {
+		return nil
+	}
+
+	return &container.HealthConfig{
+		Test:        hc.Test,
+		Interval:    hc.Interval,
+		Timeout:     hc.Timeout,
+		StartPeriod: hc.StartPeriod,
+		Retries:     hc.Retries,
+	}
+}
+
+// containerResources translates the resource limits of the node to the docker container resources.
+func containerResources(node *types.NodeConfig) (container.Resources, error) {
+	var resources container.Resources
+	if node.Memory != "" {
+		mem, err := humanize.ParseBytes(node.Memory)
+		if err != nil {
+			return resources, err
+		}
+		resources.Memory = int64(mem)
+	}
+	if node.MemorySwap != "" {
+		swap, err := runtime.ParseMemorySwap(node.MemorySwap)
+		if err != nil {
+			return resources, err
+		}
+		resources.MemorySwap = swap
+	}
+	if node.CPU != 0 {
+		resources.CPUQuota = int64(node.CPU * 100000)
+		resources.CPUPeriod = 100000
+	}
+	if node.CPUSet != "" {
+		resources.CpusetCpus = node.CPUSet
+	}
+	if node.CgroupParent != "" {
+		resources.CgroupParent = node.CgroupParent
+	}
+	if node.OomKillDisable {
+		resources.OomKillDisable = &node.OomKillDisable
+	}
+	return resources, nil
+}
+
 // GetContainerStatus retrieves the ContainerStatus of the named container.
 func (d *DockerRuntime) GetContainerStatus(ctx context.Context, cID string) runtime.ContainerStatus {
 	inspect, err := d.Client.ContainerInspect(ctx, cID)
@@ -984,11 +1419,135 @@ func (d *DockerRuntime) GetContainerStatus(ctx context.Context, cID string) runt
 	return runtime.NotFound
 }
 
-// containerPid returns the pid of a container by its ID using inspect.
-func (d *DockerRuntime) containerPid(ctx context.Context, cID string) (int, error) {
+// IsContainerOOMKilled reports whether the container was killed by the OOM killer.
+func (d *DockerRuntime) IsContainerOOMKilled(ctx context.Context, cID string) (bool, error) {
 	inspect, err := d.Client.ContainerInspect(ctx, cID)
 	if err != nil {
-		return 0, fmt.Errorf("container %q cannot be found", cID)
+		return false, err
+	}
+
+	return inspect.State != nil && inspect.State.OOMKilled, nil
+}
+
+// CopyFromContainer copies the file by srcPath in the container to the dstPath on the host.
+func (d *DockerRuntime) CopyFromContainer(ctx context.Context, cID, srcPath, dstPath string) error {
+	rc, _, err := d.Client.CopyFromContainer(ctx, cID, srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+	defer rc.Close()
+
+	return utils.ExtractTarFile(rc, dstPath)
+}
+
+// GetContainerStats returns the current resource usage of the container.
+// The CPU usage is calculated over the interval between the two samples the daemon collects for a non-streamed request.
+func (d *DockerRuntime) GetContainerStats(ctx context.Context, cID string) (*runtime.ContainerStats, error) {
+	resp, err := d.Client.ContainerStats(ctx, cID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var s dockerTypes.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to decode stats of container %s: %w", cID, err)
 	}
-	return inspect.State.Pid, nil
+
+	return &runtime.ContainerStats{
+		MemoryUsage: dockerMemoryUsage(&s.MemoryStats),
+		MemoryLimit: s.MemoryStats.Limit,
+		CPUPercent:  dockerCPUPercent(&s),
+	}, nil
+}
+
+// dockerMemoryUsage returns the memory usage without the page cache, the same way the docker CLI does.
+func dockerMemoryUsage(m *dockerTypes.MemoryStats) uint64 {
+	// cgroup v1 reports the cache as total_inactive_file, cgroup v2 as inactive_file
+	for _, k := range []string{"total_inactive_file", "inactive_file"} {
+		if v, ok := m.Stats[k]; ok && v < m.Usage {
+			return m.Usage - v
+		}
+	}
+
+	return m.Usage
+}
+
+// dockerCPUPercent returns the CPU usage percentage, the same way the docker CLI does.
+func dockerCPUPercent(s *dockerTypes.StatsJSON) float64 {
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+
+	cpus := float64(s.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	return cpuDelta / systemDelta * cpus * 100
+}
+
+// InspectImage returns the details of the local container image.
+func (d *DockerRuntime) InspectImage(ctx context.Context, imageName string) (*runtime.ImageInfo, error) {
+	img, _, err := d.Client.ImageInspectWithRaw(ctx, utils.GetCanonicalImageName(imageName))
+	if err != nil {
+		return nil, err
+	}
+
+	info := &runtime.ImageInfo{
+		ID:   img.ID,
+		Size: img.Size,
+	}
+
+	if img.Config != nil {
+		info.Env = img.Config.Env
+	}
+
+	return info, nil
+}
+
+// CreateVolume creates the named volume with the labels, an existing volume is left intact.
+func (d *DockerRuntime) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	_, err := d.Client.VolumeInspect(ctx, name)
+	switch {
+	case err == nil:
+		log.Debugf("Volume %q already exists", name)
+		return nil
+	case !dockerC.IsErrNotFound(err):
+		return err
+	}
+
+	log.Debugf("Creating volume %q", name)
+
+	_, err = d.Client.VolumeCreate(ctx, volume.CreateOptions{
+		Name:   name,
+		Labels: labels,
+	})
+
+	return err
+}
+
+// ListVolumes returns the names of the volumes matching the filters.
+func (d *DockerRuntime) ListVolumes(ctx context.Context, gfilters []*types.GenericFilter) ([]string, error) {
+	resp, err := d.Client.VolumeList(ctx, volume.ListOptions{
+		Filters: d.buildFilterString(gfilters),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(resp.Volumes))
+	for _, v := range resp.Volumes {
+		names = append(names, v.Name)
+	}
+
+	return names, nil
+}
+
+// DeleteVolume deletes the named volume.
+func (d *DockerRuntime) DeleteVolume(ctx context.Context, name string) error {
+	return d.Client.VolumeRemove(ctx, name, false)
 }
@@ -0,0 +1,368 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	dockerC "github.com/docker/docker/client"
+	"github.com/google/go-cmp/cmp"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestReadImagePullStream(t *testing.T) {
+	tests := map[string]struct {
+		stream  string
+		wantErr string
+	}{
+		"successful-pull": {
+			stream: `{"status":"Pulling from library/alpine","id":"latest"}
+{"status":"Digest: sha256:7144f7bab3d4c2648d7e59409f15ec52a18006a128c733fcff20d3a4a54ba44a"}
+{"status":"Status: Downloaded newer image for alpine:latest"}
+`,
+		},
+		"error-detail": {
+			stream: `{"status":"Pulling from library/alpine","id":"3.99"}
+{"errorDetail":{"message":"manifest for alpine:3.99 not found: manifest unknown: manifest unknown"},"error":"manifest for alpine:3.99 not found: manifest unknown: manifest unknown"}
+`,
+			wantErr: "manifest unknown",
+		},
+		"error-only": {
+			stream:  `{"error":"toomanyrequests: You have reached your pull rate limit."}`,
+			wantErr: "toomanyrequests",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := readImagePullStream(strings.NewReader(tt.stream))
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStatsCalculation(t *testing.T) {
+	tests := map[string]struct {
+		stats   dockerTypes.StatsJSON
+		wantMem uint64
+		wantCPU float64
+	}{
+		"cgroup-v2": {
+			stats: func() dockerTypes.StatsJSON {
+				var s dockerTypes.StatsJSON
+				s.MemoryStats = dockerTypes.MemoryStats{
+					Usage: 100 << 20,
+					Stats: map[string]uint64{"inactive_file": 20 << 20},
+				}
+				s.CPUStats.CPUUsage.TotalUsage = 300
+				s.CPUStats.SystemUsage = 2000
+				s.CPUStats.OnlineCPUs = 4
+				s.PreCPUStats.CPUUsage.TotalUsage = 200
+				s.PreCPUStats.SystemUsage = 1000
+				return s
+			}(),
+			wantMem: 80 << 20,
+			wantCPU: 40,
+		},
+		"cgroup-v1-percpu": {
+			stats: func() dockerTypes.StatsJSON {
+				var s dockerTypes.StatsJSON
+				s.MemoryStats = dockerTypes.MemoryStats{
+					Usage: 100 << 20,
+					Stats: map[string]uint64{"total_inactive_file": 40 << 20},
+				}
+				s.CPUStats.CPUUsage.TotalUsage = 500
+				s.CPUStats.CPUUsage.PercpuUsage = []uint64{250, 250}
+				s.CPUStats.SystemUsage = 2000
+				return s
+			}(),
+			wantMem: 60 << 20,
+			wantCPU: 50,
+		},
+		"no-previous-sample": {
+			stats: func() dockerTypes.StatsJSON {
+				var s dockerTypes.StatsJSON
+				s.MemoryStats = dockerTypes.MemoryStats{Usage: 1 << 20}
+				s.CPUStats.CPUUsage.TotalUsage = 500
+				s.CPUStats.OnlineCPUs = 2
+				return s
+			}(),
+			wantMem: 1 << 20,
+			wantCPU: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := dockerMemoryUsage(&tt.stats.MemoryStats); got != tt.wantMem {
+				t.Errorf("got memory usage %d, want %d", got, tt.wantMem)
+			}
+
+			if got := dockerCPUPercent(&tt.stats); got != tt.wantCPU {
+				t.Errorf("got cpu usage %v, want %v", got, tt.wantCPU)
+			}
+		})
+	}
+}
+
+func TestImageMatchesPlatform(t *testing.T) {
+	armV8 := dockerTypes.ImageInspect{Os: "linux", Architecture: "arm64", Variant: "v8"}
+
+	tests := map[string]struct {
+		image    dockerTypes.ImageInspect
+		platform *ocispec.Platform
+		want     bool
+	}{
+		"no platform": {
+			image: armV8,
+			want:  true,
+		},
+		"same os and arch": {
+			image:    armV8,
+			platform: &ocispec.Platform{OS: "linux", Architecture: "arm64"},
+			want:     true,
+		},
+		"same variant": {
+			image:    armV8,
+			platform: &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			want:     true,
+		},
+		"different arch": {
+			image:    armV8,
+			platform: &ocispec.Platform{OS: "linux", Architecture: "amd64"},
+		},
+		"different variant": {
+			image:    armV8,
+			platform: &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v7"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := imageMatchesPlatform(tt.image, tt.platform); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerHealthcheck(t *testing.T) {
+	if hc := containerHealthcheck(&types.NodeConfig{}); hc != nil {
+		t.Errorf("containerHealthcheck() of the node without the health check = %+v, want nil", hc)
+	}
+
+	node := &types.NodeConfig{
+		Healthcheck: &types.HealthcheckConfig{
+			Test:        []string{"CMD", "cat", "/ready"},
+			Interval:    5 * time.Second,
+			Retries:     3,
+			StartPeriod: 10 * time.Second,
+		},
+	}
+
+	want := &container.HealthConfig{
+		Test:        []string{"CMD", "cat", "/ready"},
+		Interval:    5 * time.Second,
+		Retries:     3,
+		StartPeriod: 10 * time.Second,
+	}
+	if d := cmp.Diff(want, containerHealthcheck(node)); d != "" {
+		t.Errorf("containerHealthcheck() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestContainerResources(t *testing.T) {
+	tests := map[string]struct {
+		node           *types.NodeConfig
+		wantMemory     int64
+		wantMemorySwap int64
+		wantOomDisable bool
+		wantErr        bool
+	}{
+		"no limits": {
+			node: &types.NodeConfig{},
+		},
+		"swap disabled": {
+			node:           &types.NodeConfig{Memory: "4GiB", MemorySwap: "4GiB"},
+			wantMemory:     4 << 30,
+			wantMemorySwap: 4 << 30,
+		},
+		"unlimited swap": {
+			node:           &types.NodeConfig{Memory: "1GiB", MemorySwap: "-1"},
+			wantMemory:     1 << 30,
+			wantMemorySwap: -1,
+		},
+		"oom killer disabled": {
+			node:           &types.NodeConfig{Memory: "1GiB", OomKillDisable: true},
+			wantMemory:     1 << 30,
+			wantOomDisable: true,
+		},
+		"invalid memory-swap": {
+			node:    &types.NodeConfig{Memory: "1GiB", MemorySwap: "lots"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := containerResources(tt.node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("containerResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.Memory != tt.wantMemory || got.MemorySwap != tt.wantMemorySwap {
+				t.Errorf("containerResources() memory = %d, memory-swap = %d, want %d, %d",
+					got.Memory, got.MemorySwap, tt.wantMemory, tt.wantMemorySwap)
+			}
+
+			if oomDisable := got.OomKillDisable != nil && *got.OomKillDisable; oomDisable != tt.wantOomDisable {
+				t.Errorf("containerResources() oom-kill-disable = %v, want %v", oomDisable, tt.wantOomDisable)
+			}
+		})
+	}
+}
+
+func TestSetContainerState(t *testing.T) {
+	tests := map[string]struct {
+		inspect          string
+		wantExitCode     int
+		wantRestartCount int
+		wantStartedAt    time.Time
+		wantFinishedAt   time.Time
+		wantHealth       string
+	}{
+		"running": {
+			inspect: `{"RestartCount":0,"State":{"Status":"running","Running":true,"Pid":4242,"ExitCode":0,` +
+				`"StartedAt":"2024-03-01T10:00:00.123456789Z","FinishedAt":"0001-01-01T00:00:00Z"}}`,
+			wantStartedAt: time.Date(2024, 3, 1, 10, 0, 0, 123456789, time.UTC),
+			wantHealth:    runtime.HealthNone,
+		},
+		"unhealthy": {
+			inspect: `{"RestartCount":0,"State":{"Status":"running","Running":true,"Pid":4242,"ExitCode":0,` +
+				`"StartedAt":"2024-03-01T10:00:00Z","FinishedAt":"0001-01-01T00:00:00Z",` +
+				`"Health":{"Status":"unhealthy","FailingStreak":3}}}`,
+			wantStartedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+			wantHealth:    runtime.HealthUnhealthy,
+		},
+		"crash-looping": {
+			inspect: `{"RestartCount":12,"State":{"Status":"restarting","Restarting":true,"ExitCode":137,` +
+				`"StartedAt":"2024-03-01T10:05:00Z","FinishedAt":"2024-03-01T10:05:03.5Z"}}`,
+			wantExitCode:     137,
+			wantRestartCount: 12,
+			wantStartedAt:    time.Date(2024, 3, 1, 10, 5, 0, 0, time.UTC),
+			wantFinishedAt:   time.Date(2024, 3, 1, 10, 5, 3, 500000000, time.UTC),
+			wantHealth:       runtime.HealthNone,
+		},
+		"created": {
+			inspect: `{"RestartCount":0,"State":{"Status":"created","ExitCode":0,` +
+				`"StartedAt":"0001-01-01T00:00:00Z","FinishedAt":"0001-01-01T00:00:00Z"}}`,
+			wantHealth: runtime.HealthNone,
+		},
+		"no state": {
+			inspect:          `{"RestartCount":3}`,
+			wantRestartCount: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var inspect dockerTypes.ContainerJSON
+			if err := json.Unmarshal([]byte(tt.inspect), &inspect); err != nil {
+				t.Fatal(err)
+			}
+
+			ctr := &runtime.GenericContainer{}
+			setContainerState(ctr, &inspect)
+
+			if ctr.ExitCode != tt.wantExitCode || ctr.RestartCount != tt.wantRestartCount {
+				t.Errorf("got exit code %d and restart count %d, want %d and %d",
+					ctr.ExitCode, ctr.RestartCount, tt.wantExitCode, tt.wantRestartCount)
+			}
+
+			if !ctr.StartedAt.Equal(tt.wantStartedAt) || !ctr.FinishedAt.Equal(tt.wantFinishedAt) {
+				t.Errorf("got started at %v and finished at %v, want %v and %v",
+					ctr.StartedAt, ctr.FinishedAt, tt.wantStartedAt, tt.wantFinishedAt)
+			}
+
+			if ctr.Health != tt.wantHealth {
+				t.Errorf("got health %q, want %q", ctr.Health, tt.wantHealth)
+			}
+		})
+	}
+}
+
+func TestStopContainer(t *testing.T) {
+	var stops []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/containers/clab-lab-n1/stop") {
+			http.NotFound(w, r)
+			return
+		}
+
+		stops = append(stops, r.URL.RawQuery)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client, err := dockerC.NewClientWithOpts(dockerC.WithHTTPClient(srv.Client()),
+		dockerC.WithHost("tcp://"+srv.Listener.Addr().String()), dockerC.WithVersion("1.42"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &DockerRuntime{Client: client, config: runtime.RuntimeConfig{Timeout: 30 * time.Second}}
+
+	tests := map[string]struct {
+		signal  string
+		want    string
+		wantErr bool
+	}{
+		"default signal": {want: "signal=15&t=30"},
+		"custom signal":  {signal: "SIGINT", want: "signal=2&t=30"},
+		"unknown signal": {signal: "SIGNOPE", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			stops = nil
+
+			err := d.StopContainer(context.Background(), "clab-lab-n1", tt.signal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if len(stops) != 0 {
+					t.Errorf("container stopped with an invalid signal: %v", stops)
+				}
+
+				return
+			}
+
+			if len(stops) != 1 || stops[0] != tt.want {
+				t.Errorf("got stop requests %v, want [%s]", stops, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// GetContainerLogs returns the stdout and stderr of the container interleaved in a single stream.
+// The logs of a container without a tty are multiplexed by the daemon and are demultiplexed here.
+func (d *DockerRuntime) GetContainerLogs(ctx context.Context, cID string, opts runtime.LogOptions) (io.ReadCloser, error) {
+	inspect, err := d.Client.ContainerInspect(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := d.Client.ContainerLogs(ctx, cID, dockerLogsOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	if inspect.Config != nil && inspect.Config.Tty {
+		return rc, nil
+	}
+
+	return demuxLogs(rc), nil
+}
+
+// dockerLogsOptions translates the log options to the docker logs options.
+func dockerLogsOptions(opts runtime.LogOptions) dockerTypes.ContainerLogsOptions {
+	o := dockerTypes.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+	}
+
+	if opts.Tail > 0 {
+		o.Tail = strconv.Itoa(opts.Tail)
+	}
+
+	if !opts.Since.IsZero() {
+		o.Since = strconv.FormatInt(opts.Since.Unix(), 10)
+	}
+
+	return o
+}
+
+// demuxedLogs is the demultiplexed logs stream, closing it closes the multiplexed stream as well.
+type demuxedLogs struct {
+	*io.PipeReader
+	src io.Closer
+}
+
+func (l *demuxedLogs) Close() error {
+	l.PipeReader.Close()
+
+	return l.src.Close()
+}
+
+// demuxLogs writes the stdout and stderr frames of the multiplexed logs stream to a single stream
+// in the order they were received.
+func demuxLogs(rc io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, rc)
+		pw.CloseWithError(err)
+	}()
+
+	return &demuxedLogs{PipeReader: pr, src: rc}
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+func TestDemuxLogs(t *testing.T) {
+	var mux bytes.Buffer
+
+	stdout := stdcopy.NewStdWriter(&mux, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&mux, stdcopy.Stderr)
+
+	stdout.Write([]byte("booting\n"))
+	stderr.Write([]byte("warning: no license\n"))
+	stdout.Write([]byte("ready\n"))
+
+	rc := demuxLogs(io.NopCloser(&mux))
+	defer rc.Close()
+
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "booting\nwarning: no license\nready\n"
+	if string(got) != want {
+		t.Errorf("got logs %q, want %q", got, want)
+	}
+}
+
+func TestDockerLogsOptions(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+
+	tests := map[string]struct {
+		opts runtime.LogOptions
+		want dockerTypes.ContainerLogsOptions
+	}{
+		"all logs": {
+			want: dockerTypes.ContainerLogsOptions{ShowStdout: true, ShowStderr: true},
+		},
+		"follow tail since": {
+			opts: runtime.LogOptions{Follow: true, Tail: 50, Since: since},
+			want: dockerTypes.ContainerLogsOptions{
+				ShowStdout: true, ShowStderr: true,
+				Follow: true, Tail: "50", Since: "1700000000",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tt.want, dockerLogsOptions(tt.opts)); d != "" {
+				t.Errorf("options mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// versionRequirements are the docker API versions required by containerlab and by the features it uses.
+var versionRequirements = []runtime.VersionRequirement{
+	// docker engine 19.03
+	{MinVersion: "1.40"},
+	{
+		// the platform of the created containers is set since docker engine 20.10
+		Feature:    "the image platform selection",
+		MinVersion: "1.41",
+		UsedBy:     func(n *types.NodeConfig) bool { return n.Platform != "" },
+	},
+}
+
+// CheckVersion checks the API version of the docker engine against the versions
+// required by containerlab and by the features used by the nodes.
+func (d *DockerRuntime) CheckVersion(ctx context.Context, nodes []*types.NodeConfig) error {
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	v, err := d.Client.ServerVersion(nctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the docker engine version: %w", err)
+	}
+
+	log.Debugf("docker engine version %s, API version %s, negotiated client API version %s",
+		v.Version, v.APIVersion, d.Client.ClientVersion())
+
+	return runtime.CheckVersionRequirements(RuntimeName, v.APIVersion, "docker engine "+v.Version,
+		versionRequirements, nodes)
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// ExecFunc executes the command in the node.
+type ExecFunc func(context.Context, *exec.ExecCmd) (*exec.ExecResult, error)
+
+// RunExecPhase runs the node exec commands of the lifecycle phase one after another in the declaration order.
+// The results are added to the node exec results collection under the phase.
+func RunExecPhase(ctx context.Context, cfg *types.NodeConfig, phase types.ExecPhase, run ExecFunc) {
+	for _, e := range cfg.GetExecs(phase) {
+		execCmd, err := exec.NewExecCmdFromString(e.Cmd)
+		if err != nil {
+			log.Warnf("Failed to parse the command string: %s, %v", e.Cmd, err)
+			continue
+		}
+
+		if e.ExpectedRC != nil {
+			execCmd.SetExpectedReturnCode(*e.ExpectedRC)
+		}
+
+		res, err := run(ctx, execCmd)
+		if err != nil {
+			// kinds which do not support exec functionality are skipped
+			if errors.Is(err, exec.ErrRunExecNotSupported) {
+				return
+			}
+
+			log.Errorf("Failed to execute %s phase command %q on the node %q: %v", phase, e.Cmd, cfg.ShortName, err)
+
+			if e.ExpectedRC == nil {
+				continue
+			}
+
+			// the command with the expected return code is reported as failed
+			// even when it could not be executed
+			res = exec.NewExecResult(execCmd)
+			res.SetReturnCode(-1)
+			res.SetStdErr([]byte(err.Error()))
+		}
+
+		if cfg.ExecResults != nil {
+			cfg.ExecResults.AddPhase(cfg.ShortName, string(phase), res)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package runtime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// execCmdMatcher matches the exec command by its string representation.
+type execCmdMatcher string
+
+func (m execCmdMatcher) Matches(x interface{}) bool {
+	c, ok := x.(*exec.ExecCmd)
+	return ok && c.GetCmdString() == string(m)
+}
+
+func (m execCmdMatcher) String() string {
+	return "exec command " + string(m)
+}
+
+func TestRunExecPhase(t *testing.T) {
+	cfg := &types.NodeConfig{
+		ShortName: "node1",
+		Exec:      []string{"echo 2"},
+		ExecPhases: []*types.Exec{
+			{Phase: types.ExecPhasePostLinks, Cmd: "ip link"},
+			{Phase: types.ExecPhasePostStart, Cmd: "echo 1"},
+			{Phase: types.ExecPhasePostStart, Cmd: "echo 3"},
+			{Phase: types.ExecPhasePostStart, Cmd: "echo 4"},
+		},
+		ExecResults: exec.NewExecCollection(),
+	}
+
+	tests := map[types.ExecPhase][]string{
+		types.ExecPhasePostStart:   {"echo 1", "echo 3", "echo 4"},
+		types.ExecPhasePostLinks:   {"ip link"},
+		types.ExecPhasePostHealthy: {"echo 2"},
+	}
+
+	// phases run in the lifecycle order and the commands of a phase in the declaration order
+	for _, phase := range []types.ExecPhase{
+		types.ExecPhasePostStart,
+		types.ExecPhasePostLinks,
+		types.ExecPhasePostHealthy,
+	} {
+		ctrl := gomock.NewController(t)
+		rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+		calls := make([]*gomock.Call, 0, len(tests[phase]))
+		for _, c := range tests[phase] {
+			calls = append(calls, rt.EXPECT().Exec(gomock.Any(), "clab-node1", execCmdMatcher(c)).DoAndReturn(
+				func(_ context.Context, _ string, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+					return exec.NewExecResult(cmd), nil
+				}))
+		}
+		gomock.InOrder(calls...)
+
+		runtime.RunExecPhase(context.Background(), cfg, phase,
+			func(ctx context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+				return rt.Exec(ctx, "clab-node1", cmd)
+			})
+
+		ctrl.Finish()
+	}
+
+	// the results are keyed by the phase the commands were executed in
+	got := cfg.ExecResults.GetPhaseResults("node1")
+	if len(got) != len(tests) {
+		t.Fatalf("got exec results of %d phases, want %d", len(got), len(tests))
+	}
+
+	for phase, cmds := range tests {
+		res := got[string(phase)]
+		if len(res) != len(cmds) {
+			t.Fatalf("phase %s: got %d exec results, want %d", phase, len(res), len(cmds))
+		}
+
+		for i, r := range res {
+			if r.GetCmdString() != cmds[i] {
+				t.Errorf("phase %s result %d: got %q, want %q", phase, i, r.GetCmdString(), cmds[i])
+			}
+		}
+	}
+}
+
+func TestRunExecPhaseNotSupported(t *testing.T) {
+	cfg := &types.NodeConfig{
+		ShortName: "node1",
+		ExecPhases: []*types.Exec{
+			{Phase: types.ExecPhasePostStart, Cmd: "echo 1"},
+			{Phase: types.ExecPhasePostStart, Cmd: "echo 2"},
+		},
+		ExecResults: exec.NewExecCollection(),
+	}
+
+	calls := 0
+	runtime.RunExecPhase(context.Background(), cfg, types.ExecPhasePostStart,
+		func(context.Context, *exec.ExecCmd) (*exec.ExecResult, error) {
+			calls++
+			return nil, exec.ErrRunExecNotSupported
+		})
+
+	// the remaining commands are skipped for the kinds that don't support exec
+	if calls != 1 {
+		t.Fatalf("got %d exec calls, want 1", calls)
+	}
+
+	if res := cfg.ExecResults.GetResults("node1"); len(res) != 0 {
+		t.Fatalf("got %d exec results, want none", len(res))
+	}
+}
+
+func TestRunExecPhaseError(t *testing.T) {
+	cfg := &types.NodeConfig{
+		ShortName: "node1",
+		ExecPhases: []*types.Exec{
+			{Phase: types.ExecPhasePostStart, Cmd: "echo 1"},
+			{Phase: types.ExecPhasePostStart, Cmd: "echo 2"},
+		},
+		ExecResults: exec.NewExecCollection(),
+	}
+
+	runtime.RunExecPhase(context.Background(), cfg, types.ExecPhasePostStart,
+		func(_ context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+			if cmd.GetCmdString() == "echo 1" {
+				return nil, errors.New("container not running")
+			}
+			return exec.NewExecResult(cmd), nil
+		})
+
+	// the failed command doesn't stop the following ones
+	res := cfg.ExecResults.GetResults("node1")
+	if len(res) != 1 || res[0].GetCmdString() != "echo 2" {
+		t.Fatalf("unexpected exec results %v", res)
+	}
+}
+
+func TestRunExecPhaseExpectedReturnCode(t *testing.T) {
+	rc := func(i int) *int { return &i }
+
+	cfg := &types.NodeConfig{
+		ShortName: "node1",
+		ExecPhases: []*types.Exec{
+			{Phase: types.ExecPhasePostHealthy, Cmd: "test -f /tmp/ready", ExpectedRC: rc(1)},
+			{Phase: types.ExecPhasePostHealthy, Cmd: "ping -c1 10.0.0.2", ExpectedRC: rc(0)},
+			{Phase: types.ExecPhasePostHealthy, Cmd: "false"},
+			{Phase: types.ExecPhasePostHealthy, Cmd: "ip link", ExpectedRC: rc(0)},
+		},
+		ExecResults: exec.NewExecCollection(),
+	}
+
+	// the return codes the commands exit with
+	rcs := map[string]int{"test -f /tmp/ready": 1, "ping -c1 10.0.0.2": 1, "false": 1}
+
+	runtime.RunExecPhase(context.Background(), cfg, types.ExecPhasePostHealthy,
+		func(_ context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+			if cmd.GetCmdString() == "ip link" {
+				return nil, errors.New("container not running")
+			}
+			r := exec.NewExecResult(cmd)
+			r.SetReturnCode(rcs[cmd.GetCmdString()])
+			return r, nil
+		})
+
+	res := cfg.ExecResults.GetResults("node1")
+	if len(res) != 4 {
+		t.Fatalf("got %d exec results, want 4", len(res))
+	}
+
+	// the commands without the expected return code are not asserted
+	for i, want := range []bool{false, true, false, true} {
+		if got := res[i].Unexpected(); got != want {
+			t.Errorf("result %d %q: got unexpected %v, want %v", i, res[i].GetCmdString(), got, want)
+		}
+	}
+
+	if !res[2].Failed() {
+		t.Errorf("non-zero return code of %q is not reported as failed", res[2].GetCmdString())
+	}
+
+	if got := cfg.ExecResults.Unexpected(); len(got) != 2 {
+		t.Errorf("got unexpected results %q, want 2", got)
+	}
+}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/clab/exec"
@@ -23,11 +24,31 @@ type GenericContainer struct {
 	Mounts          []ContainerMount
 	runtime         ContainerRuntime
 	Ports           []*types.GenericPortBinding
+	// Env is the environment of the container in the KEY=value format.
+	Env []string
+	// ExitCode is the exit code of the last run of the container.
+	ExitCode int
+	// RestartCount is the number of times the container has been restarted by the runtime.
+	RestartCount int
+	// StartedAt and FinishedAt are the start and the finish times of the last run of the container,
+	// zero if the container has not been started or has not finished yet.
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Created is the creation time of the container.
+	Created time.Time
+	// Health is the status of the container health check, one of the Health* values.
+	// It is empty when the runtime doesn't report the health of the containers.
+	Health string
 }
 
 type ContainerMount struct {
 	Source      string
 	Destination string
+	// Type is the mount type, e.g. bind or volume.
+	Type string
+	// Name is the name of the named volume.
+	Name     string
+	ReadOnly bool
 }
 
 // SetRuntime sets the runtime for this GenericContainer.
@@ -72,6 +93,8 @@ func (ctr *GenericContainer) GetContainerIPv6() string {
 }
 
 type GenericMgmtIPs struct {
+	// Network is the name of the management network the addresses belong to.
+	Network  string
 	IPv4addr string
 	IPv4pLen int
 	IPv4Gw   string
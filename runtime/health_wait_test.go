@@ -0,0 +1,125 @@
+package runtime_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+func TestWaitForContainerHealthy(t *testing.T) {
+	tests := map[string]struct {
+		// statuses are the statuses of the container reported by the polls, the last one is repeated
+		statuses []string
+		timeout  time.Duration
+		cancel   bool
+		wantErr  bool
+	}{
+		"healthy": {
+			statuses: []string{"Up 1 second (health: starting)", "Up 2 seconds (healthy)"},
+			timeout:  time.Second,
+		},
+		"no health check": {
+			statuses: []string{"Up 1 second"},
+			timeout:  time.Second,
+		},
+		"never healthy": {
+			statuses: []string{"Up 1 second (unhealthy)"},
+			timeout:  50 * time.Millisecond,
+			wantErr:  true,
+		},
+		"cancelled": {
+			statuses: []string{"Up 1 second (health: starting)"},
+			timeout:  time.Minute,
+			cancel:   true,
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+			polls := 0
+			rt.EXPECT().ListContainers(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(context.Context, any) ([]runtime.GenericContainer, error) {
+					status := tt.statuses[len(tt.statuses)-1]
+					if polls < len(tt.statuses) {
+						status = tt.statuses[polls]
+					}
+					polls++
+					return []runtime.GenericContainer{{State: "running", Status: status}}, nil
+				}).AnyTimes()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tt.cancel {
+				time.AfterFunc(50*time.Millisecond, cancel)
+			}
+
+			start := time.Now()
+
+			err := runtime.WaitForContainerHealthy(ctx, rt, "clab-lab-srl1", "client1", tt.timeout, 5*time.Millisecond)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			// the wait gives up on timeout or cancellation instead of the default wait time
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("waited %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestWaitForContainerExited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rt := mockruntime.NewMockContainerRuntime(ctrl)
+
+	polls := 0
+	rt.EXPECT().GetContainerStatus(gomock.Any(), "clab-lab-job").DoAndReturn(
+		func(context.Context, string) runtime.ContainerStatus {
+			polls++
+			if polls < 3 {
+				return runtime.Running
+			}
+			return runtime.Stopped
+		}).AnyTimes()
+
+	if err := runtime.WaitForContainerExited(context.Background(), rt, "clab-lab-job", "client1", time.Minute, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	if polls != 3 {
+		t.Errorf("got %d polls, want 3", polls)
+	}
+
+	// the wait for the container that never exits is cancelled
+	rt = mockruntime.NewMockContainerRuntime(ctrl)
+	rt.EXPECT().GetContainerStatus(gomock.Any(), gomock.Any()).Return(runtime.ContainerStatus(runtime.Running)).AnyTimes()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := runtime.WaitForContainerExited(ctx, rt, "clab-lab-job", "client1", time.Minute, time.Millisecond); err == nil {
+		t.Error("cancelled wait returned no error")
+	}
+
+	// the wait for the container that never exits gives up on timeout
+	start := time.Now()
+
+	err := runtime.WaitForContainerExited(context.Background(), rt, "clab-lab-job", "client1",
+		20*time.Millisecond, time.Millisecond)
+	if err == nil {
+		t.Error("timed out wait returned no error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("waited %s", elapsed)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HostTuningFailure is a failure of a single host tuning action performed on the management network bridge.
+type HostTuningFailure struct {
+	// Setting is a name of the setting that failed to be applied.
+	Setting string
+	// Suggestion is a command a user can run on the host to apply the setting manually.
+	Suggestion string
+	Err        error
+}
+
+// HostTuningError is returned by CreateNet when the management network is functional,
+// but some of the host tuning actions performed on its bridge have failed.
+type HostTuningError struct {
+	Bridge string
+	// Containerized is set when containerlab runs inside a container,
+	// which is the most common reason for the host tuning failures.
+	Containerized bool
+	Failures      []HostTuningFailure
+}
+
+// Add adds a failure of a host tuning action.
+func (e *HostTuningError) Add(setting, suggestion string, err error) {
+	e.Failures = append(e.Failures, HostTuningFailure{
+		Setting:    setting,
+		Suggestion: suggestion,
+		Err:        err,
+	})
+}
+
+// ErrOrNil returns the HostTuningError if it contains any failures and nil otherwise.
+func (e *HostTuningError) ErrOrNil() error {
+	if e == nil || len(e.Failures) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *HostTuningError) Error() string {
+	settings := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		settings = append(settings, fmt.Sprintf("%s: %v", f.Setting, f.Err))
+	}
+
+	return fmt.Sprintf("failed to tune the management bridge %q: %s", e.Bridge, strings.Join(settings, "; "))
+}
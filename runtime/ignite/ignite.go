@@ -3,6 +3,7 @@ package ignite
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -130,6 +131,11 @@ func (c *IgniteRuntime) WithMgmtNet(n *types.MgmtNet) {
 	c.mgmt = n
 }
 
+// CheckVersion checks the version of the docker engine ignite runs the VMs with.
+func (c *IgniteRuntime) CheckVersion(ctx context.Context, nodes []*types.NodeConfig) error {
+	return c.ctrRuntime.CheckVersion(ctx, nodes)
+}
+
 func (c *IgniteRuntime) CreateNet(ctx context.Context) error {
 	return c.ctrRuntime.CreateNet(ctx)
 }
@@ -138,9 +144,13 @@ func (c *IgniteRuntime) DeleteNet(ctx context.Context) error {
 	return c.ctrRuntime.DeleteNet(ctx)
 }
 
+func (c *IgniteRuntime) InspectMgmtNet(ctx context.Context) (*runtime.NetworkInfo, error) {
+	return c.ctrRuntime.InspectMgmtNet(ctx)
+}
+
 // PullImage pulls the provided image name if it does not exist.
 // Ignite does ignore the pullPolicy though.
-func (*IgniteRuntime) PullImage(_ context.Context, imageName string, pullPolicy types.PullPolicyValue) error {
+func (*IgniteRuntime) PullImage(_ context.Context, imageName string, pullPolicy types.PullPolicyValue, _ string) error {
 	ociRef, err := meta.NewOCIImageRef(imageName)
 	if err != nil {
 		return fmt.Errorf("failed to parse OCI image ref %q: %s", imageName, err)
@@ -153,6 +163,14 @@ func (*IgniteRuntime) PullImage(_ context.Context, imageName string, pullPolicy
 	return nil
 }
 
+func (*IgniteRuntime) ImportImage(_ context.Context, _ string) error {
+	return fmt.Errorf("ImportImage is not yet implemented for Ignite runtime")
+}
+
+func (*IgniteRuntime) BuildImage(_ context.Context, _ *types.ImageBuild) error {
+	return fmt.Errorf("BuildImage is not yet implemented for Ignite runtime")
+}
+
 func (c *IgniteRuntime) StartContainer(ctx context.Context, _ string, node runtime.Node) (interface{}, error) {
 	vm := c.baseVM.DeepCopy()
 
@@ -277,6 +295,10 @@ func (*IgniteRuntime) CreateContainer(_ context.Context, node *types.NodeConfig)
 	return node.LongName, nil
 }
 
+func (*IgniteRuntime) GetContainerSpec(_ context.Context, _ *types.NodeConfig) (interface{}, error) {
+	return nil, fmt.Errorf("GetContainerSpec is not yet implemented for Ignite runtime")
+}
+
 func (*IgniteRuntime) PauseContainer(_ context.Context, cID string) error {
 	pid, err := utils.ContainerNSToPID(cID)
 	if err != nil {
@@ -295,7 +317,7 @@ func (*IgniteRuntime) UnpauseContainer(_ context.Context, cID string) error {
 	return utils.UnpauseProcessGroup(pid)
 }
 
-func (*IgniteRuntime) StopContainer(_ context.Context, _ string) error {
+func (*IgniteRuntime) StopContainer(_ context.Context, _, _ string) error {
 	// this is a no-op, only used by ceos at this stage
 	return nil
 }
@@ -463,3 +485,36 @@ func (*IgniteRuntime) GetContainerStatus(_ context.Context, containerID string)
 	}
 	return runtime.Stopped
 }
+
+func (*IgniteRuntime) CopyFromContainer(_ context.Context, _, _, _ string) error {
+	return fmt.Errorf("CopyFromContainer is not yet implemented for Ignite runtime")
+}
+
+func (*IgniteRuntime) GetContainerStats(_ context.Context, _ string) (*runtime.ContainerStats, error) {
+	return nil, fmt.Errorf("%w by the Ignite runtime", runtime.ErrStatsNotSupported)
+}
+
+func (*IgniteRuntime) GetContainerLogs(_ context.Context, _ string, _ runtime.LogOptions) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("GetContainerLogs is not yet implemented for Ignite runtime")
+}
+
+// IsContainerOOMKilled always reports false, as the ignite VMs are not subject to the container memory limits.
+func (*IgniteRuntime) IsContainerOOMKilled(_ context.Context, _ string) (bool, error) {
+	return false, nil
+}
+
+func (*IgniteRuntime) InspectImage(_ context.Context, _ string) (*runtime.ImageInfo, error) {
+	return nil, fmt.Errorf("InspectImage is not yet implemented for Ignite runtime")
+}
+
+func (*IgniteRuntime) CreateVolume(_ context.Context, _ string, _ map[string]string) error {
+	return fmt.Errorf("CreateVolume is not yet implemented for Ignite runtime")
+}
+
+func (*IgniteRuntime) ListVolumes(_ context.Context, _ []*types.GenericFilter) ([]string, error) {
+	return nil, fmt.Errorf("ListVolumes is not yet implemented for Ignite runtime")
+}
+
+func (*IgniteRuntime) DeleteVolume(_ context.Context, _ string) error {
+	return fmt.Errorf("DeleteVolume is not yet implemented for Ignite runtime")
+}
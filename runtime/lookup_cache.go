@@ -0,0 +1,58 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import "sync"
+
+// LookupCache caches the results of the lookups shared by the nodes, e.g. the canonical image names
+// and the registry auth strings, so that every unique key is resolved once.
+// Concurrent lookups of the same key wait for a single resolution.
+// The failed lookups are not cached and are retried by the later callers.
+// The cache lives as long as the runtime that owns it, i.e. it is not persisted across deployments.
+type LookupCache struct {
+	mu      sync.Mutex
+	entries map[string]*lookupEntry
+}
+
+// lookupEntry is a resolution of a LookupCache key, done is closed when the resolution is finished.
+type lookupEntry struct {
+	done chan struct{}
+	val  string
+	err  error
+}
+
+// NewLookupCache returns an empty LookupCache.
+func NewLookupCache() *LookupCache {
+	return &LookupCache{
+		entries: map[string]*lookupEntry{},
+	}
+}
+
+// Get returns the cached value of the key resolving it with the resolve function if the key is not cached.
+func (c *LookupCache) Get(key string, resolve func() (string, error)) (string, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		<-e.done
+
+		return e.val, e.err
+	}
+
+	e := &lookupEntry{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.val, e.err = resolve()
+
+	if e.err != nil {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+	}
+
+	close(e.done)
+
+	return e.val, e.err
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLookupCacheConcurrent(t *testing.T) {
+	c := NewLookupCache()
+
+	var calls atomic.Int32
+
+	resolve := func() (string, error) {
+		calls.Add(1)
+		// keep the resolution in flight while the other callers arrive
+		time.Sleep(50 * time.Millisecond)
+
+		return "docker.io/library/alpine:latest", nil
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 80; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v, err := c.Get("alpine", resolve)
+			if err != nil || v != "docker.io/library/alpine:latest" {
+				t.Errorf("got %q, %v", v, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if _, err := c.Get("alpine", resolve); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("the key was resolved %d times, want 1", n)
+	}
+}
+
+func TestLookupCacheKeys(t *testing.T) {
+	c := NewLookupCache()
+
+	calls := map[string]int{}
+
+	for _, key := range []string{"docker.io", "ghcr.io", "docker.io", "ghcr.io", "quay.io"} {
+		key := key
+
+		_, err := c.Get(key, func() (string, error) {
+			calls[key]++
+			return "auth-" + key, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for key, n := range calls {
+		if n != 1 {
+			t.Errorf("key %s was resolved %d times, want 1", key, n)
+		}
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("resolved %d keys, want 3", len(calls))
+	}
+}
+
+func TestLookupCacheError(t *testing.T) {
+	c := NewLookupCache()
+
+	calls := 0
+	resolve := func() (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("auth helper failed")
+		}
+
+		return "auth", nil
+	}
+
+	if _, err := c.Get("docker.io", resolve); err == nil {
+		t.Fatal("expected the resolution error")
+	}
+
+	// the failed resolution is retried
+	v, err := c.Get("docker.io", resolve)
+	if err != nil || v != "auth" {
+		t.Fatalf("got %q, %v", v, err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("resolved %d times, want 2", calls)
+	}
+}
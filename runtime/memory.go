@@ -0,0 +1,29 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+)
+
+// UnlimitedSwap is the memory-swap value allowing the container to use the unlimited swap.
+const UnlimitedSwap = "-1"
+
+// ParseMemorySwap parses the memory plus swap limit of the node in bytes.
+// The UnlimitedSwap value is returned as -1, the way the runtimes expect it.
+func ParseMemorySwap(s string) (int64, error) {
+	if s == UnlimitedSwap {
+		return -1, nil
+	}
+
+	v, err := humanize.ParseBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse memory-swap value %q: %w", s, err)
+	}
+
+	return int64(v), nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import "testing"
+
+func TestParseMemorySwap(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		"unlimited": {in: "-1", want: -1},
+		"gigabytes": {in: "2GB", want: 2000000000},
+		"gibibytes": {in: "2GiB", want: 2147483648},
+		"invalid":   {in: "lots", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseMemorySwap(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMemorySwap(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseMemorySwap(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import "github.com/srl-labs/containerlab/labels"
+
+// ForeignNetOwner returns the name of the lab that created the network with the labels netLabels
+// if it is not the lab labName. Empty string is returned for the networks of the lab labName and
+// for the networks without the lab name label, e.g. created by the older containerlab versions.
+func ForeignNetOwner(netLabels map[string]string, labName string) string {
+	owner := netLabels[labels.LabName]
+	if owner == labName {
+		return ""
+	}
+
+	return owner
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import "testing"
+
+func TestForeignNetOwner(t *testing.T) {
+	tests := map[string]struct {
+		labels map[string]string
+		lab    string
+		want   string
+	}{
+		"network of the lab": {
+			labels: map[string]string{"containerlab": "", "clab-lab-name": "lab1"},
+			lab:    "lab1",
+		},
+		"network of another lab": {
+			labels: map[string]string{"containerlab": "", "clab-lab-name": "lab2"},
+			lab:    "lab1",
+			want:   "lab2",
+		},
+		"network without the lab name": {
+			labels: map[string]string{"containerlab": ""},
+			lab:    "lab1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ForeignNetOwner(tt.labels, tt.lab); got != tt.want {
+				t.Errorf("ForeignNetOwner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,17 +4,28 @@
 package podman
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/containers/podman/v4/pkg/api/handlers"
 	"github.com/containers/podman/v4/pkg/bindings/containers"
 	"github.com/containers/podman/v4/pkg/bindings/images"
 	"github.com/containers/podman/v4/pkg/bindings/network"
+	"github.com/containers/podman/v4/pkg/bindings/system"
+	"github.com/containers/podman/v4/pkg/bindings/volumes"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/distribution/reference"
 	dockerTypes "github.com/docker/docker/api/types"
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
 	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -24,8 +35,18 @@ import (
 const (
 	RuntimeName    = "podman"
 	defaultTimeout = 120 * time.Second
+	// defaultSocket is the URI of the podman API socket used when the socket is not set by a user.
+	defaultSocket = "unix://run/podman/podman.sock"
+	// certsDir is the directory podman reads the registry TLS material from.
+	certsDir = "/etc/containers/certs.d"
 )
 
+// versionRequirements are the libpod API versions required by containerlab and by the features it uses.
+var versionRequirements = []runtime.VersionRequirement{
+	// the v4 bindings used by containerlab talk to the v4 API
+	{MinVersion: "4.0.0"},
+}
+
 type PodmanRuntime struct {
 	config *runtime.RuntimeConfig
 	mgmt   *types.MgmtNet
@@ -83,6 +104,23 @@ func (r *PodmanRuntime) WithKeepMgmtNet() {
 	r.config.KeepMgmtNet = true
 }
 
+// CheckVersion checks the libpod API version reported by the info endpoint against the versions
+// required by containerlab and by the features used by the nodes.
+func (r *PodmanRuntime) CheckVersion(ctx context.Context, nodes []*types.NodeConfig) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	info, err := system.Info(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the podman version: %w", err)
+	}
+
+	return runtime.CheckVersionRequirements(RuntimeName, info.Version.APIVersion, "podman "+info.Version.Version,
+		versionRequirements, nodes)
+}
+
 // CreateNet used to create a new bridge for clab mgmt network.
 func (r *PodmanRuntime) CreateNet(ctx context.Context) error {
 	ctx, err := r.connect(ctx)
@@ -96,6 +134,8 @@ func (r *PodmanRuntime) CreateNet(ctx context.Context) error {
 		return err
 	}
 	// Create if the network doesn't exist
+	// only the network created here is rolled back on failure
+	created := false
 	if !b {
 		netopts, err := r.netOpts(ctx)
 		if err != nil {
@@ -107,18 +147,72 @@ func (r *PodmanRuntime) CreateNet(ctx context.Context) error {
 			return err
 		}
 		log.Debugf("Create network response was: %+v", resp)
+		created = true
 	}
 	// set bridge name = network name if explicit name was not provided
 	if r.mgmt.Bridge == "" && r.mgmt.Network != "" {
 		details, err := network.Inspect(ctx, r.mgmt.Network, &network.InspectOptions{})
 		if err != nil {
-			return err
+			return r.rollbackNet(ctx, created, err)
 		}
 		r.mgmt.Bridge = details.NetworkInterface
 	}
 	return err
 }
 
+// rollbackNet removes the mgmt network that was created by CreateNet call which failed afterwards
+// so that the next deployment doesn't reuse a half-configured network.
+// Networks that were reused by CreateNet are never removed.
+// The original error is returned.
+func (r *PodmanRuntime) rollbackNet(ctx context.Context, created bool, err error) error {
+	if !created {
+		return err
+	}
+	log.Warnf("Failed to configure the newly created podman network %q, removing it: %v", r.mgmt.Network, err)
+	if _, rmErr := network.Remove(ctx, r.mgmt.Network, &network.RemoveOptions{}); rmErr != nil {
+		log.Errorf("failed to remove podman network %q: %v", r.mgmt.Network, rmErr)
+	}
+	return err
+}
+
+// InspectMgmtNet returns the details of the existing podman mgmt network.
+// Nil is returned if the network doesn't exist.
+func (r *PodmanRuntime) InspectMgmtNet(ctx context.Context) (*runtime.NetworkInfo, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := network.Exists(ctx, r.mgmt.Network, &network.ExistsOptions{})
+	if err != nil || !b {
+		return nil, err
+	}
+	details, err := network.Inspect(ctx, r.mgmt.Network, &network.InspectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	netInfo := &runtime.NetworkInfo{
+		Name:   details.Name,
+		Labels: details.Labels,
+	}
+	for _, s := range details.Subnets {
+		var gw string
+		if s.Gateway != nil {
+			gw = s.Gateway.String()
+		}
+		if s.Subnet.IP.To4() != nil {
+			netInfo.IPv4Subnet = s.Subnet.String()
+			netInfo.IPv4Gateway = gw
+			continue
+		}
+		netInfo.IPv6Subnet = s.Subnet.String()
+		netInfo.IPv6Gateway = gw
+	}
+	if mtu, err := strconv.Atoi(details.Options["mtu"]); err == nil {
+		netInfo.MTU = mtu
+	}
+	return netInfo, nil
+}
+
 // DeleteNet deletes a clab mgmt bridge.
 func (r *PodmanRuntime) DeleteNet(ctx context.Context) error {
 	// Skip if "keep mgmt" is set
@@ -130,6 +224,24 @@ func (r *PodmanRuntime) DeleteNet(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	details, err := network.Inspect(ctx, r.mgmt.Network, &network.InspectOptions{})
+	if err != nil {
+		return err
+	}
+	// the network created by another lab is only deleted when that lab is gone,
+	// its stopped containers would fail to start without the network
+	if owner := runtime.ForeignNetOwner(details.Labels, r.config.LabName); owner != "" {
+		ctrs, err := r.ListContainers(ctx, []*types.GenericFilter{{
+			FilterType: "label", Field: labels.Containerlab, Operator: "=", Match: owner,
+		}})
+		if err != nil {
+			return err
+		}
+		if len(ctrs) > 0 {
+			log.Debugf("network %q is used by the lab %q, deletion skipped", r.mgmt.Network, owner)
+			return nil
+		}
+	}
 	log.Debugf("trying to delete mgmt network %v", r.mgmt.Network)
 	_, err = network.Remove(ctx, r.mgmt.Network, &network.RemoveOptions{})
 	if err != nil {
@@ -138,7 +250,7 @@ func (r *PodmanRuntime) DeleteNet(ctx context.Context) error {
 	return nil
 }
 
-func (r *PodmanRuntime) PullImage(ctx context.Context, image string, pullPolicy types.PullPolicyValue) error {
+func (r *PodmanRuntime) PullImage(ctx context.Context, image string, pullPolicy types.PullPolicyValue, platform string) error {
 	ctx, err := r.connect(ctx)
 	if err != nil {
 		return err
@@ -147,11 +259,27 @@ func (r *PodmanRuntime) PullImage(ctx context.Context, image string, pullPolicy
 	// https://www.redhat.com/sysadmin/container-image-short-names
 	canonicalImage := utils.GetCanonicalImageName(image)
 
+	pullOpts := &images.PullOptions{}
+	if platform != "" {
+		p, err := utils.ParsePlatform(platform)
+		if err != nil {
+			return err
+		}
+		pullOpts = pullOpts.WithOS(p.OS).WithArch(p.Architecture).WithVariant(p.Variant)
+	}
+
 	// Check the existence
 	ex, err := images.Exists(ctx, canonicalImage, &images.ExistsOptions{})
 	if err != nil {
 		return err
 	}
+	// the local image of a different platform is considered absent
+	if ex && platform != "" {
+		ex, err = r.imageMatchesPlatform(ctx, canonicalImage, pullOpts)
+		if err != nil {
+			return err
+		}
+	}
 
 	if pullPolicy == types.PullPolicyNever {
 		if ex {
@@ -171,12 +299,109 @@ func (r *PodmanRuntime) PullImage(ctx context.Context, image string, pullPolicy
 
 	// Pull the image if it doesn't exist
 	if !ex {
-		_, err = images.Pull(ctx, canonicalImage, &images.PullOptions{})
+		if err := r.installRegistryTLS(canonicalImage); err != nil {
+			return err
+		}
+
+		err = runtime.PullWithRetries(ctx, canonicalImage, runtime.PullRetries(r.config.PullRetries), func() error {
+			_, err := images.Pull(ctx, canonicalImage, pullOpts)
+			return err
+		})
+		if err != nil {
+			return r.imagePullError(ctx, image, err)
+		}
 	}
-	return err
+	return nil
+}
+
+// ImportImage loads the images of the image archive created with `docker save`.
+func (r *PodmanRuntime) ImportImage(ctx context.Context, tarPath string) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	log.Infof("Importing images from %s", tarPath)
+	report, err := images.Load(ctx, f)
+	if err != nil {
+		return fmt.Errorf("failed to import image archive %q: %w", tarPath, err)
+	}
+	log.Debugf("Imported images %v from %s", report.Names, tarPath)
+
+	return nil
+}
+
+// BuildImage is not yet implemented for the podman runtime.
+func (*PodmanRuntime) BuildImage(_ context.Context, spec *types.ImageBuild) error {
+	return fmt.Errorf("building image %s is not yet implemented for the podman runtime", spec.Tag)
+}
+
+// installRegistryTLS installs the client TLS material of the mTLS-protected registry of the image
+// to the podman certs directory, so that podman uses it when pulling from the registry.
+func (r *PodmanRuntime) installRegistryTLS(image string) error {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+
+	registry := reference.Domain(ref)
+
+	tc, ok := r.config.RegistryTLS[registry]
+	if !ok {
+		return nil
+	}
+
+	log.Debugf("Installing registry %s TLS material to %s", registry, certsDir)
+
+	return runtime.InstallRegistryTLS(certsDir, registry, tc)
+}
+
+// imagePullError wraps the error returned by the image pull into runtime.ImagePullError
+// and for the not found images tries to find a similar image available locally.
+func (*PodmanRuntime) imagePullError(ctx context.Context, image string, err error) error {
+	pullErr := runtime.NewImagePullError(RuntimeName, image, err)
+
+	if pullErr.Class != runtime.ImagePullErrorNotFound {
+		return pullErr
+	}
+
+	imgs, lErr := images.List(ctx, &images.ListOptions{})
+	if lErr != nil {
+		log.Debugf("failed to list local images: %v", lErr)
+		return pullErr
+	}
+
+	var localImages []string
+	for _, img := range imgs {
+		localImages = append(localImages, img.RepoTags...)
+	}
+
+	pullErr.LocalMatch = runtime.ClosestImageName(image, localImages)
+
+	return pullErr
 }
 
 // CreateContainer creates a container, but does not start it.
+// imageMatchesPlatform returns true if the local image is built for the platform set in the pull options.
+func (*PodmanRuntime) imageMatchesPlatform(ctx context.Context, image string, opts *images.PullOptions) (bool, error) {
+	ii, err := images.GetImage(ctx, image, &images.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	if ii.Os != opts.GetOS() || ii.Architecture != opts.GetArch() {
+		return false, nil
+	}
+
+	return opts.GetVariant() == "" || ii.Variant == opts.GetVariant(), nil
+}
+
 func (r *PodmanRuntime) CreateContainer(ctx context.Context, cfg *types.NodeConfig) (string, error) {
 	ctx, err := r.connect(ctx)
 	if err != nil {
@@ -191,6 +416,19 @@ func (r *PodmanRuntime) CreateContainer(ctx context.Context, cfg *types.NodeConf
 	return res.ID, err
 }
 
+// GetContainerSpec returns the spec generator CreateContainer submits to create the container of the node.
+func (r *PodmanRuntime) GetContainerSpec(ctx context.Context, cfg *types.NodeConfig) (interface{}, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sg, err := r.createContainerSpec(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error while trying to create a container spec for node %q: %w", cfg.LongName, err)
+	}
+	return &sg, nil
+}
+
 // StartContainer starts a previously created container by ID or its name and executes post-start actions method.
 func (r *PodmanRuntime) StartContainer(ctx context.Context, cID string, node runtime.Node) (interface{}, error) {
 	ctx, err := r.connect(ctx)
@@ -226,16 +464,29 @@ func (r *PodmanRuntime) UnpauseContainer(ctx context.Context, cID string) error
 	return containers.Unpause(ctx, cID, &containers.UnpauseOptions{})
 }
 
-func (r *PodmanRuntime) StopContainer(ctx context.Context, cID string) error {
-	ctx, err := r.connect(ctx)
+// StopContainer stops the container sending it the signal, SIGTERM when empty,
+// and killing it if it doesn't exit within the runtime timeout.
+func (r *PodmanRuntime) StopContainer(ctx context.Context, cID, signal string) error {
+	sig, err := runtime.ParseStopSignal(signal)
 	if err != nil {
 		return err
 	}
-	err = containers.Stop(ctx, cID, &containers.StopOptions{})
+
+	ctx, err = r.connect(ctx)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	timeout := uint(r.config.Timeout.Seconds())
+
+	// the stop request always sends the stop signal of the container, other signals are sent beforehand
+	if sig != syscall.SIGTERM {
+		if err := containers.Kill(ctx, cID, new(containers.KillOptions).WithSignal(strconv.Itoa(int(sig)))); err != nil {
+			return err
+		}
+	}
+
+	return containers.Stop(ctx, cID, new(containers.StopOptions).WithTimeout(timeout))
 }
 
 // ListContainers returns a list of all available containers in the system in a containerlab-specific struct.
@@ -378,6 +629,195 @@ func (r *PodmanRuntime) GetHostsPath(ctx context.Context, cID string) (string, e
 	return hostsPath, nil
 }
 
+// CopyFromContainer copies the file by srcPath in the container to the dstPath on the host.
+func (r *PodmanRuntime) CopyFromContainer(ctx context.Context, cID, srcPath, dstPath string) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	copyFunc, err := containers.CopyToArchive(ctx, cID, srcPath, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	if err := copyFunc(); err != nil {
+		return fmt.Errorf("failed to copy %s from container %s: %w", srcPath, cID, err)
+	}
+
+	return utils.ExtractTarFile(&buf, dstPath)
+}
+
+// GetContainerStats returns the current resource usage of the container.
+func (r *PodmanRuntime) GetContainerStats(ctx context.Context, cID string) (*runtime.ContainerStats, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := containers.Stats(ctx, []string{cID}, new(containers.StatsOptions).WithStream(false))
+	if err != nil {
+		return nil, err
+	}
+
+	for report := range reports {
+		if report.Error != nil {
+			return nil, report.Error
+		}
+
+		for _, s := range report.Stats {
+			return &runtime.ContainerStats{
+				MemoryUsage: s.MemUsage,
+				MemoryLimit: s.MemLimit,
+				CPUPercent:  s.CPU,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no stats returned for container %s", cID)
+}
+
+// InspectImage returns the details of the local container image.
+func (r *PodmanRuntime) InspectImage(ctx context.Context, imageName string) (*runtime.ImageInfo, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := images.GetImage(ctx, utils.GetCanonicalImageName(imageName), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &runtime.ImageInfo{
+		ID:   img.ID,
+		Size: img.Size,
+	}
+
+	if img.Config != nil {
+		info.Env = img.Config.Env
+	}
+
+	return info, nil
+}
+
+// GetContainerLogs returns the stdout and stderr of the container interleaved in a single stream.
+func (r *PodmanRuntime) GetContainerLogs(ctx context.Context, cID string, opts runtime.LogOptions) (io.ReadCloser, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logOpts := new(containers.LogOptions).WithStdout(true).WithStderr(true).WithFollow(opts.Follow)
+	if opts.Tail > 0 {
+		logOpts = logOpts.WithTail(strconv.Itoa(opts.Tail))
+	}
+
+	if !opts.Since.IsZero() {
+		logOpts = logOpts.WithSince(opts.Since.Format(time.RFC3339))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+
+	// the bindings deliver the log frames over the channel,
+	// stdout and stderr frames share the channel to keep their order
+	frames := make(chan string)
+	errCh := make(chan error, 1)
+
+	go func() {
+		err := containers.Logs(ctx, cID, logOpts, frames, frames)
+		if errors.Is(err, context.Canceled) {
+			err = nil
+		}
+
+		errCh <- err
+		close(frames)
+	}()
+
+	go func() {
+		for f := range frames {
+			// the channel is drained after the reader is closed to not block the bindings
+			pw.Write([]byte(f))
+		}
+
+		pw.CloseWithError(<-errCh)
+	}()
+
+	return &podmanLogs{PipeReader: pr, cancel: cancel}, nil
+}
+
+// podmanLogs is the logs stream of a container, closing it stops the logs request.
+type podmanLogs struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (l *podmanLogs) Close() error {
+	l.cancel()
+
+	return l.PipeReader.Close()
+}
+
+// CreateVolume creates the named volume with the labels, an existing volume is left intact.
+func (r *PodmanRuntime) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	exists, err := volumes.Exists(ctx, name, nil)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		log.Debugf("Volume %q already exists", name)
+		return nil
+	}
+
+	log.Debugf("Creating volume %q", name)
+
+	_, err = volumes.Create(ctx, entities.VolumeCreateOptions{
+		Name:  name,
+		Label: labels,
+	}, nil)
+
+	return err
+}
+
+// ListVolumes returns the names of the volumes matching the filters.
+func (r *PodmanRuntime) ListVolumes(ctx context.Context, gfilters []*types.GenericFilter) ([]string, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vols, err := volumes.List(ctx, new(volumes.ListOptions).WithFilters(r.buildFilterString(gfilters)))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(vols))
+	for _, v := range vols {
+		names = append(names, v.Name)
+	}
+
+	return names, nil
+}
+
+// DeleteVolume deletes the named volume.
+func (r *PodmanRuntime) DeleteVolume(ctx context.Context, name string) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return volumes.Remove(ctx, name, nil)
+}
+
 // GetContainerStatus retrieves the ContainerStatus of the named container.
 func (r *PodmanRuntime) GetContainerStatus(ctx context.Context, cID string) runtime.ContainerStatus {
 	ctx, err := r.connect(ctx)
@@ -393,3 +833,16 @@ func (r *PodmanRuntime) GetContainerStatus(ctx context.Context, cID string) runt
 	}
 	return runtime.Stopped
 }
+
+// IsContainerOOMKilled reports whether the container was killed by the OOM killer.
+func (r *PodmanRuntime) IsContainerOOMKilled(ctx context.Context, cID string) (bool, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return false, err
+	}
+	icd, err := containers.Inspect(ctx, cID, nil)
+	if err != nil {
+		return false, err
+	}
+	return icd.State != nil && icd.State.OOMKilled, nil
+}
@@ -11,6 +11,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	netTypes "github.com/containers/common/libnetwork/types"
 	"github.com/containers/podman/v4/pkg/bindings/containers"
@@ -22,6 +23,7 @@ import (
 	"github.com/containers/podman/v4/pkg/bindings"
 	"github.com/google/shlex"
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
@@ -37,8 +39,13 @@ func (*podmanWriterCloser) Close() error {
 	return nil
 }
 
-func (*PodmanRuntime) connect(ctx context.Context) (context.Context, error) {
-	return bindings.NewConnection(ctx, "unix://run/podman/podman.sock")
+func (r *PodmanRuntime) connect(ctx context.Context) (context.Context, error) {
+	socket := defaultSocket
+	if r.config.Socket != "" {
+		socket = runtime.SocketURI(r.config.Socket)
+	}
+
+	return bindings.NewConnection(ctx, socket)
 }
 
 func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.NodeConfig) (specgen.SpecGenerator, error) {
@@ -65,25 +72,26 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 		Terminal:   true,
 		Stdin:      true,
 		Labels:     cfg.Labels,
-		Hostname:   cfg.ShortName,
+		Hostname:   cfg.GetHostname(),
 		Sysctl:     cfg.Sysctls,
 		Remove:     false,
 	}
 	// Storage, image and mounts
-	mounts, err := r.convertMounts(ctx, cfg.Binds)
+	mounts, vols, err := r.convertMounts(ctx, cfg.Binds)
 	if err != nil {
 		log.Errorf("Cannot convert mounts %v: %v", cfg.Binds, err)
 		mounts = nil
+		vols = nil
 	}
 	specStorageConfig := specgen.ContainerStorageConfig{
 		Image: cfg.Image,
 		// Rootfs:            "",
 		// ImageVolumeMode:   "",
 		// VolumesFrom:       nil,
-		// Init:              false,
+		Init: cfg.Init != nil && *cfg.Init,
 		// InitPath:          "",
-		Mounts: mounts,
-		// Volumes:           nil,
+		Mounts:  mounts,
+		Volumes: vols,
 		// OverlayVolumes:    nil,
 		// ImageVolumes:      nil,
 		// Devices:           nil,
@@ -95,6 +103,16 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 		// Secrets:           nil,
 		// Volatile:          false,
 	}
+	// Image platform, the image of a foreign platform runs under emulation
+	if cfg.Platform != "" {
+		p, err := utils.ParsePlatform(cfg.Platform)
+		if err != nil {
+			return sg, err
+		}
+		specStorageConfig.ImageOS = p.OS
+		specStorageConfig.ImageArch = p.Architecture
+		specStorageConfig.ImageVariant = p.Variant
+	}
 	// Security
 	specSecurityConfig := specgen.ContainerSecurityConfig{
 		Privileged: true,
@@ -102,7 +120,8 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 	}
 	// Going with the defaults for cgroups
 	specCgroupConfig := specgen.ContainerCgroupConfig{
-		CgroupNS: specgen.Namespace{},
+		CgroupNS:     specgen.Namespace{},
+		CgroupParent: cfg.CgroupParent,
 	}
 	// Resource limits
 	var (
@@ -119,6 +138,17 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 		}
 		lMem.Limit = &mem64
 	}
+	if cfg.MemorySwap != "" {
+		swap, err := runtime.ParseMemorySwap(cfg.MemorySwap)
+		if err != nil {
+			log.Warnf("Unable to parse memory-swap limit %q for node %q", cfg.MemorySwap, cfg.LongName)
+		} else {
+			lMem.Swap = &swap
+		}
+	}
+	if cfg.OomKillDisable {
+		lMem.DisableOOMKiller = &cfg.OomKillDisable
+	}
 	resLimits.Memory = &lMem
 	// CPU resources limits
 	if cfg.CPU != 0 {
@@ -134,8 +164,8 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 
 	specResConfig := specgen.ContainerResourceConfig{
 		ResourceLimits: &resLimits,
+		OOMScoreAdj:    cfg.OomScoreAdj,
 		// Rlimits:                 nil,
-		// OOMScoreAdj:             nil,
 		// WeightDevice:            nil,
 		// ThrottleReadBpsDevice:   nil,
 		// ThrottleWriteBpsDevice:  nil,
@@ -147,6 +177,10 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 	}
 	// Defaults for health checks
 	specHCheckConfig := specgen.ContainerHealthCheckConfig{}
+	if cfg.Healthcheck != nil {
+		log.Warnf("Node %q: the healthcheck is not supported by the podman runtime, the health check of the image is used",
+			cfg.ShortName)
+	}
 	// Everything below is related to network spec of a container
 	specNetConfig := specgen.ContainerNetworkConfig{}
 
@@ -258,32 +292,47 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 
 // convertMounts takes a list of filesystem mount binds in docker/clab format (src:dest:options)
 // and converts it into an opencontainers spec format.
-func (*PodmanRuntime) convertMounts(_ context.Context, mounts []string) ([]specs.Mount, error) {
+// The binds with a named volume as a source are returned as the named volumes.
+func (*PodmanRuntime) convertMounts(_ context.Context, mounts []string) ([]specs.Mount, []*specgen.NamedVolume, error) {
 	if len(mounts) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
-	mntSpec := make([]specs.Mount, len(mounts))
+	mntSpec := make([]specs.Mount, 0, len(mounts))
+	var volSpec []*specgen.NamedVolume
 	// Note: we don't do any input validation here
-	for i, mnt := range mounts {
+	for _, mnt := range mounts {
 		mntSplit := strings.SplitN(mnt, ":", 3)
 
 		if len(mntSplit) == 1 {
-			return nil, fmt.Errorf("%w: %s", errInvalidBind, mnt)
-		}
-
-		mntSpec[i] = specs.Mount{
-			Destination: mntSplit[1],
-			Type:        "bind",
-			Source:      mntSplit[0],
+			return nil, nil, fmt.Errorf("%w: %s", errInvalidBind, mnt)
 		}
 
+		var opts []string
 		// when options are provided in the bind mount spec
 		if len(mntSplit) == 3 {
-			mntSpec[i].Options = strings.Split(mntSplit[2], ",")
+			opts = strings.Split(mntSplit[2], ",")
 		}
+
+		if b, err := types.NewBind(mnt); err == nil && b.IsVolume() {
+			volSpec = append(volSpec, &specgen.NamedVolume{
+				Name:    mntSplit[0],
+				Dest:    mntSplit[1],
+				Options: opts,
+			})
+
+			continue
+		}
+
+		mntSpec = append(mntSpec, specs.Mount{
+			Destination: mntSplit[1],
+			Type:        "bind",
+			Source:      mntSplit[0],
+			Options:     opts,
+		})
 	}
-	log.Debugf("convertMounts method received mounts %v and produced %+v as a result", mounts, mntSpec)
-	return mntSpec, nil
+	log.Debugf("convertMounts method received mounts %v and produced %+v and volumes %+v as a result",
+		mounts, mntSpec, volSpec)
+	return mntSpec, volSpec, nil
 }
 
 // produceGenericContainerList takes a list of containers in a podman entities.ListContainer format
@@ -308,6 +357,12 @@ func (r *PodmanRuntime) produceGenericContainerList(ctx context.Context,
 			Pid:             v.Pid,
 			NetworkSettings: netSettings,
 			Ports:           []*types.GenericPortBinding{},
+			ExitCode:        int(v.ExitCode),
+			RestartCount:    int(v.Restarts),
+			StartedAt:       unixTime(v.StartedAt),
+			FinishedAt:      unixTime(v.ExitedAt),
+			Created:         v.Created,
+			Health:          containerHealth(ctx, v.ID),
 		}
 
 		// convert the exposed ports the GenericPorts and add them to the GenericContainer
@@ -322,6 +377,30 @@ func (r *PodmanRuntime) produceGenericContainerList(ctx context.Context,
 	return genericList, nil
 }
 
+// containerHealth returns the status of the container health check, empty if the container can't be inspected.
+func containerHealth(ctx context.Context, cID string) string {
+	inspectRes, err := containers.Inspect(ctx, cID, &containers.InspectOptions{})
+	if err != nil || inspectRes.State == nil {
+		log.Debugf("Couldn't get the health of container %q, %v", cID, err)
+		return ""
+	}
+
+	if inspectRes.Config == nil || inspectRes.Config.Healthcheck == nil {
+		return runtime.HealthNone
+	}
+
+	return inspectRes.State.Health.Status
+}
+
+// unixTime converts the unix time reported by podman to time.Time, the times that are not set are zero.
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, 0)
+}
+
 func netTypesPortMappingToGenericPortBinding(pm netTypes.PortMapping) []*types.GenericPortBinding {
 	// convert netTypes.PortMapping to types.GenericPort
 	// resolving the ranges into single port entries
@@ -399,13 +478,17 @@ func (r *PodmanRuntime) netOpts(_ context.Context) (netTypes.Network, error) {
 		ipv6        = false
 		dnsEnabled  = false
 		options     = map[string]string{}
-		labels      = map[string]string{"containerlab": ""}
+		netLabels   = map[string]string{labels.Containerlab: ""}
 		err         error
 		ipamOptions = map[string]string{}
 		v4subnet    = netTypes.Subnet{}
 		v6subnet    = netTypes.Subnet{}
 		subnets     = make([]netTypes.Subnet, 0)
 	)
+	// the lab name label scopes the network to the lab that created it
+	if r.config.LabName != "" {
+		netLabels[labels.LabName] = r.config.LabName
+	}
 	// parse mgmt subnets
 	// check if v4 is defined
 	if r.mgmt.IPv4Subnet != "" {
@@ -444,7 +527,7 @@ func (r *PodmanRuntime) netOpts(_ context.Context) (netTypes.Network, error) {
 		DNSEnabled:       dnsEnabled,
 		Driver:           driver,
 		Internal:         internal,
-		Labels:           labels,
+		Labels:           netLabels,
 		Subnets:          subnets,
 		IPv6Enabled:      ipv6,
 		Options:          options,
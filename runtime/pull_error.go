@@ -0,0 +1,310 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// ImagePullErrorClass is a class of the image pull failure.
+type ImagePullErrorClass string
+
+const (
+	ImagePullErrorAuth        ImagePullErrorClass = "auth"
+	ImagePullErrorNotFound    ImagePullErrorClass = "not-found"
+	ImagePullErrorRateLimited ImagePullErrorClass = "rate-limited"
+	ImagePullErrorNetwork     ImagePullErrorClass = "network"
+	ImagePullErrorUnknown     ImagePullErrorClass = "unknown"
+)
+
+// imagePullErrorPatterns maps lowercased substrings of the registry/daemon error messages
+// returned by docker and podman to the image pull error classes.
+// The order matters, as the first match wins.
+var imagePullErrorPatterns = []struct {
+	class    ImagePullErrorClass
+	patterns []string
+}{
+	{
+		class: ImagePullErrorRateLimited,
+		patterns: []string{
+			"toomanyrequests",
+			"pull rate limit",
+			"too many requests",
+		},
+	},
+	{
+		class: ImagePullErrorAuth,
+		patterns: []string{
+			"unauthorized",
+			"authentication required",
+			"access to the requested resource is not authorized",
+			"requested access to the resource is denied",
+			"may require 'docker login'",
+			"denied:",
+			"no basic auth credentials",
+		},
+	},
+	{
+		class: ImagePullErrorNotFound,
+		patterns: []string{
+			"manifest unknown",
+			"name unknown",
+			"not found: manifest",
+			"manifest for",
+			"repository does not exist",
+			"no such image",
+			"image not known",
+		},
+	},
+	{
+		class: ImagePullErrorNetwork,
+		patterns: []string{
+			"dial tcp",
+			"no such host",
+			"connection refused",
+			"i/o timeout",
+			"tls handshake timeout",
+			"network is unreachable",
+			"connection reset by peer",
+			"client.timeout exceeded",
+			"pinging container registry",
+			"x509:",
+		},
+	},
+}
+
+// ImagePullError is an error returned by the runtimes when an image pull fails.
+// It carries the class of the failure as well as the names of the nodes that use the image.
+type ImagePullError struct {
+	// Image is the image name as referenced in the topology.
+	Image string
+	// CanonicalImage is the fully qualified image name that was pulled.
+	CanonicalImage string
+	// Runtime is the name of the runtime that failed to pull the image.
+	Runtime string
+	Class   ImagePullErrorClass
+	// LocalMatch is a name of a locally available image that closely matches the image that was not found.
+	LocalMatch string
+	// Nodes is a list of node names that use the image.
+	Nodes []string
+	Err   error
+}
+
+// NewImagePullError returns a new ImagePullError with the class derived from the error returned by the runtime.
+func NewImagePullError(runtimeName, image string, err error) *ImagePullError {
+	return &ImagePullError{
+		Image:          image,
+		CanonicalImage: utils.GetCanonicalImageName(image),
+		Runtime:        runtimeName,
+		Class:          ClassifyImagePullError(err),
+		Err:            err,
+	}
+}
+
+func (e *ImagePullError) Error() string {
+	return fmt.Sprintf("failed to pull image %q (%s): %v", e.Image, e.Class, e.Err)
+}
+
+func (e *ImagePullError) Unwrap() error {
+	return e.Err
+}
+
+// AddNode adds a node name to the list of nodes that use the image.
+func (e *ImagePullError) AddNode(name string) {
+	for _, n := range e.Nodes {
+		if n == name {
+			return
+		}
+	}
+
+	e.Nodes = append(e.Nodes, name)
+	sort.Strings(e.Nodes)
+}
+
+// Registry returns the registry domain of the image.
+func (e *ImagePullError) Registry() string {
+	return strings.SplitN(e.CanonicalImage, "/", 2)[0]
+}
+
+// Hint returns a remediation hint for the image pull error based on its class.
+func (e *ImagePullError) Hint() string {
+	switch e.Class {
+	case ImagePullErrorAuth:
+		return fmt.Sprintf("log in to the registry with '%s login %s' and make sure the image name is correct and you have access to it",
+			e.runtimeCLI(), e.Registry())
+	case ImagePullErrorNotFound:
+		if e.LocalMatch != "" {
+			return fmt.Sprintf("image %s was not found, but a similar image %s exists locally. Did you mean it?",
+				e.CanonicalImage, e.LocalMatch)
+		}
+		return fmt.Sprintf("check that the image name and tag are correct, the image was resolved to %s", e.CanonicalImage)
+	case ImagePullErrorRateLimited:
+		return fmt.Sprintf("registry %s rate-limited the pull. Log in with '%s login %s' to get a higher limit or retry later",
+			e.Registry(), e.runtimeCLI(), e.Registry())
+	case ImagePullErrorNetwork:
+		return fmt.Sprintf("registry %s is not reachable. Check the network connectivity, DNS and proxy settings of the host",
+			e.Registry())
+	}
+
+	return "inspect the error message above for details"
+}
+
+func (e *ImagePullError) runtimeCLI() string {
+	if e.Runtime == "" {
+		return "docker"
+	}
+
+	return e.Runtime
+}
+
+// ClassifyImagePullError returns the class of the image pull error
+// by matching the error message against the known docker and podman error shapes.
+func ClassifyImagePullError(err error) ImagePullErrorClass {
+	if err == nil {
+		return ImagePullErrorUnknown
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	for _, p := range imagePullErrorPatterns {
+		for _, s := range p.patterns {
+			if strings.Contains(msg, s) {
+				return p.class
+			}
+		}
+	}
+
+	return ImagePullErrorUnknown
+}
+
+// ClosestImageName returns the name from the candidates list that is the closest to the image name.
+// Only the candidates that share the repository name with the image or are within a small edit distance
+// of it are considered. An empty string is returned when no close match is found.
+func ClosestImageName(image string, candidates []string) string {
+	canonical := utils.GetCanonicalImageName(image)
+	repo, _ := splitImageTag(canonical)
+
+	best := ""
+	bestDist := -1
+
+	for _, c := range candidates {
+		cc := utils.GetCanonicalImageName(c)
+		if cc == canonical {
+			continue
+		}
+
+		cRepo, _ := splitImageTag(cc)
+
+		dist := levenshtein(canonical, cc)
+		// same repository with a different tag is always considered a close match
+		// otherwise allow up to 3 edits in the image name
+		if cRepo != repo && levenshtein(repo, cRepo) > 3 {
+			continue
+		}
+
+		if bestDist == -1 || dist < bestDist {
+			best = c
+			bestDist = dist
+		}
+	}
+
+	return best
+}
+
+// splitImageTag splits canonical image name into repository and tag parts.
+func splitImageTag(image string) (string, string) {
+	idx := strings.LastIndex(image, ":")
+	// colon might be a part of the registry port
+	if idx == -1 || strings.Contains(image[idx:], "/") {
+		return image, ""
+	}
+
+	return image[:idx], image[idx+1:]
+}
+
+// levenshtein calculates the edit distance between two strings.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = prev[j] + 1
+			if v := cur[j-1] + 1; v < cur[j] {
+				cur[j] = v
+			}
+			if v := prev[j-1] + cost; v < cur[j] {
+				cur[j] = v
+			}
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+// ImagePullErrors is a collection of image pull errors indexed by the canonical image name.
+type ImagePullErrors map[string]*ImagePullError
+
+// Add adds the image pull error to the collection merging the node names
+// of the errors reported for the same image.
+// Returns false if the err is not an ImagePullError.
+func (ipe ImagePullErrors) Add(err error) bool {
+	var pullErr *ImagePullError
+	if !errors.As(err, &pullErr) {
+		return false
+	}
+
+	existing, ok := ipe[pullErr.CanonicalImage]
+	if !ok {
+		ipe[pullErr.CanonicalImage] = pullErr
+		return true
+	}
+
+	for _, n := range pullErr.Nodes {
+		existing.AddNode(n)
+	}
+
+	return true
+}
+
+// Report returns a consolidated report of the image problems with remediation hints.
+func (ipe ImagePullErrors) Report() string {
+	imgs := make([]string, 0, len(ipe))
+	for img := range ipe {
+		imgs = append(imgs, img)
+	}
+	sort.Strings(imgs)
+
+	sb := &strings.Builder{}
+	sb.WriteString("image problems:\n")
+
+	for _, img := range imgs {
+		e := ipe[img]
+		fmt.Fprintf(sb, "  - image: %s\n", e.Image)
+		fmt.Fprintf(sb, "    nodes: %s\n", strings.Join(e.Nodes, ", "))
+		fmt.Fprintf(sb, "    problem: %s\n", e.Class)
+		fmt.Fprintf(sb, "    error: %v\n", e.Err)
+		fmt.Fprintf(sb, "    hint: %s\n", e.Hint())
+	}
+
+	return sb.String()
+}
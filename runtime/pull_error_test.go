@@ -0,0 +1,205 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestClassifyImagePullError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want ImagePullErrorClass
+	}{
+		"docker-manifest-unknown": {
+			err:  errors.New("Error response from daemon: manifest for alpine:3.99 not found: manifest unknown: manifest unknown"),
+			want: ImagePullErrorNotFound,
+		},
+		"docker-pull-access-denied": {
+			err: errors.New("Error response from daemon: pull access denied for ghcr.io/nokia/srlinux-private, " +
+				"repository does not exist or may require 'docker login': denied: requested access to the resource is denied"),
+			want: ImagePullErrorAuth,
+		},
+		"docker-unauthorized": {
+			err:  errors.New("Error response from daemon: Head \"https://ghcr.io/v2/org/img/manifests/latest\": unauthorized"),
+			want: ImagePullErrorAuth,
+		},
+		"docker-rate-limit": {
+			err: errors.New("Error response from daemon: toomanyrequests: You have reached your pull rate limit. " +
+				"You may increase the limit by authenticating and upgrading: https://www.docker.com/increase-rate-limit"),
+			want: ImagePullErrorRateLimited,
+		},
+		"docker-dns-failure": {
+			err: errors.New("Error response from daemon: Get \"https://registry-1.docker.io/v2/\": " +
+				"dial tcp: lookup registry-1.docker.io on 127.0.0.53:53: no such host"),
+			want: ImagePullErrorNetwork,
+		},
+		"docker-timeout": {
+			err: errors.New("Error response from daemon: Get \"https://registry-1.docker.io/v2/\": " +
+				"net/http: request canceled while waiting for connection (Client.Timeout exceeded while awaiting headers)"),
+			want: ImagePullErrorNetwork,
+		},
+		"podman-manifest-unknown": {
+			err: errors.New("initializing source docker://docker.io/library/alpine:3.99: " +
+				"reading manifest 3.99 in docker.io/library/alpine: manifest unknown"),
+			want: ImagePullErrorNotFound,
+		},
+		"podman-name-unknown": {
+			err: errors.New("initializing source docker://quay.io/foo/bar:latest: " +
+				"reading manifest latest in quay.io/foo/bar: name unknown: repository not found"),
+			want: ImagePullErrorNotFound,
+		},
+		"podman-unauthorized": {
+			err: errors.New("initializing source docker://registry.example.com/img:latest: " +
+				"reading manifest latest in registry.example.com/img: unauthorized: access to the requested resource is not authorized"),
+			want: ImagePullErrorAuth,
+		},
+		"podman-rate-limit": {
+			err: errors.New("initializing source docker://alpine:latest: reading manifest latest in docker.io/library/alpine: " +
+				"toomanyrequests: You have reached your pull rate limit."),
+			want: ImagePullErrorRateLimited,
+		},
+		"podman-connection-refused": {
+			err: errors.New("initializing source docker://localhost:5000/img:latest: pinging container registry localhost:5000: " +
+				"Get \"https://localhost:5000/v2/\": dial tcp [::1]:5000: connect: connection refused"),
+			want: ImagePullErrorNetwork,
+		},
+		"unknown": {
+			err:  errors.New("something unexpected happened"),
+			want: ImagePullErrorUnknown,
+		},
+		"nil": {
+			err:  nil,
+			want: ImagePullErrorUnknown,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ClassifyImagePullError(tt.err)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosestImageName(t *testing.T) {
+	tests := map[string]struct {
+		image      string
+		candidates []string
+		want       string
+	}{
+		"different-tag": {
+			image:      "ghcr.io/nokia/srlinux:23.10.1",
+			candidates: []string{"alpine:latest", "ghcr.io/nokia/srlinux:23.7.1"},
+			want:       "ghcr.io/nokia/srlinux:23.7.1",
+		},
+		"typo-in-repo": {
+			image:      "ghcr.io/nokia/srlinx:23.7.1",
+			candidates: []string{"alpine:latest", "ghcr.io/nokia/srlinux:23.7.1"},
+			want:       "ghcr.io/nokia/srlinux:23.7.1",
+		},
+		"short-name": {
+			image:      "alpine:3.99",
+			candidates: []string{"docker.io/library/alpine:3.18", "nginx:latest"},
+			want:       "docker.io/library/alpine:3.18",
+		},
+		"no-match": {
+			image:      "ceos:4.30",
+			candidates: []string{"alpine:latest", "ghcr.io/nokia/srlinux:23.7.1"},
+			want:       "",
+		},
+		"no-candidates": {
+			image: "ceos:4.30",
+			want:  "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ClosestImageName(tt.image, tt.candidates)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImagePullErrorsAdd(t *testing.T) {
+	errs := ImagePullErrors{}
+
+	e1 := NewImagePullError("docker", "alpine:3.99", errors.New("manifest unknown"))
+	e1.AddNode("node2")
+
+	e2 := NewImagePullError("docker", "docker.io/library/alpine:3.99", errors.New("manifest unknown"))
+	e2.AddNode("node1")
+
+	if !errs.Add(fmt.Errorf("wrapped: %w", e1)) {
+		t.Fatal("expected wrapped image pull error to be added")
+	}
+
+	if !errs.Add(e2) {
+		t.Fatal("expected image pull error to be added")
+	}
+
+	if errs.Add(errors.New("not a pull error")) {
+		t.Fatal("expected generic error not to be added")
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected errors for the same image to be merged, got %d entries", len(errs))
+	}
+
+	got := errs["docker.io/library/alpine:3.99"].Nodes
+	if strings.Join(got, ",") != "node1,node2" {
+		t.Errorf("got nodes %v, want [node1 node2]", got)
+	}
+}
+
+func TestImagePullErrorHint(t *testing.T) {
+	tests := map[string]struct {
+		err  *ImagePullError
+		want string
+	}{
+		"auth-docker": {
+			err:  NewImagePullError("docker", "ghcr.io/org/img:1.0", errors.New("unauthorized")),
+			want: "docker login ghcr.io",
+		},
+		"auth-podman": {
+			err:  NewImagePullError("podman", "quay.io/org/img:1.0", errors.New("unauthorized")),
+			want: "podman login quay.io",
+		},
+		"not-found-local-match": {
+			err: &ImagePullError{
+				Image:          "alpine:3.99",
+				CanonicalImage: "docker.io/library/alpine:3.99",
+				Class:          ImagePullErrorNotFound,
+				LocalMatch:     "alpine:3.18",
+			},
+			want: "similar image alpine:3.18 exists locally",
+		},
+		"not-found": {
+			err:  NewImagePullError("docker", "alpine:3.99", errors.New("manifest unknown")),
+			want: "resolved to docker.io/library/alpine:3.99",
+		},
+		"rate-limited": {
+			err:  NewImagePullError("docker", "alpine", errors.New("toomanyrequests")),
+			want: "docker login docker.io",
+		},
+		"network": {
+			err:  NewImagePullError("docker", "alpine", errors.New("dial tcp: no such host")),
+			want: "registry docker.io is not reachable",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.err.Hint()
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("hint %q does not contain %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultPullRetries is the number of times a failed image pull is retried when the retries are not configured.
+const DefaultPullRetries = 2
+
+// pullRetryBackoff is the delay before the first retry of the image pull, doubled for every next retry.
+var pullRetryBackoff = 2 * time.Second
+
+// serverErrorPatterns are the lowercased substrings of the messages of the registry server errors,
+// which are usually transient on the busy registries.
+var serverErrorPatterns = []string{
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+	"unexpected http status: 5",
+}
+
+// PullRetries returns the number of times a failed image pull is retried for the configured value,
+// zero selects DefaultPullRetries and a negative value disables the retries.
+func PullRetries(configured int) int {
+	switch {
+	case configured == 0:
+		return DefaultPullRetries
+	case configured < 0:
+		return 0
+	}
+
+	return configured
+}
+
+// IsRetryablePullError returns true if the image pull error is transient, i.e. the registry was not reachable
+// or failed with a server error. Pulls failed for the missing image, credentials or the rate limit are not retried.
+func IsRetryablePullError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if ClassifyImagePullError(err) == ImagePullErrorNetwork {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, p := range serverErrorPatterns {
+		if strings.Contains(msg, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PullWithRetries calls pull until it succeeds, fails with the error that is not transient,
+// or the retries are exhausted. The retries are delayed with the exponential backoff
+// and are stopped when the context is done. The error of the last attempt is returned.
+func PullWithRetries(ctx context.Context, image string, retries int, pull func() error) error {
+	backoff := pullRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := pull()
+		if err == nil || attempt >= retries || !IsRetryablePullError(err) {
+			return err
+		}
+
+		log.Warnf("Failed to pull image %s (attempt %d of %d), retrying in %s: %v",
+			image, attempt+1, retries+1, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
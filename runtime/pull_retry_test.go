@@ -0,0 +1,111 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIsRetryablePullError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"nil": {},
+		"network": {
+			err:  errors.New("Get \"https://registry-1.docker.io/v2/\": dial tcp: i/o timeout"),
+			want: true,
+		},
+		"server error": {
+			err:  errors.New("received unexpected HTTP status: 503 Service Unavailable"),
+			want: true,
+		},
+		"not found": {
+			err: errors.New("manifest unknown: manifest unknown"),
+		},
+		"rate limited": {
+			err: errors.New("toomanyrequests: You have reached your pull rate limit"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsRetryablePullError(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullWithRetries(t *testing.T) {
+	defer func(b time.Duration) { pullRetryBackoff = b }(pullRetryBackoff)
+	pullRetryBackoff = time.Millisecond
+
+	transient := errors.New("received unexpected HTTP status: 502 Bad Gateway")
+
+	tests := map[string]struct {
+		retries int
+		// errs are the errors returned by the pull attempts, the attempts past them succeed
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		"success": {
+			retries:      2,
+			wantAttempts: 1,
+		},
+		"recovered": {
+			retries:      2,
+			errs:         []error{transient, transient},
+			wantAttempts: 3,
+		},
+		"exhausted": {
+			retries:      2,
+			errs:         []error{transient, transient, transient, transient},
+			wantErr:      transient,
+			wantAttempts: 3,
+		},
+		"not retryable": {
+			retries:      2,
+			errs:         []error{errors.New("manifest unknown")},
+			wantErr:      errors.New("manifest unknown"),
+			wantAttempts: 1,
+		},
+		"no retries": {
+			errs:         []error{transient},
+			wantErr:      transient,
+			wantAttempts: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+
+			err := PullWithRetries(context.Background(), "alpine", tt.retries, func() error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("got %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestPullRetries(t *testing.T) {
+	for configured, want := range map[int]int{0: DefaultPullRetries, -1: 0, 5: 5} {
+		if got := PullRetries(configured); got != want {
+			t.Errorf("PullRetries(%d) = %d, want %d", configured, got, want)
+		}
+	}
+}
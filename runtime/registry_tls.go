@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// the names of the registry TLS files the docker and podman daemons look up in the registry certs directory.
+const (
+	registryClientCertFile = "client.cert"
+	registryClientKeyFile  = "client.key"
+	registryCAFile         = "ca.crt"
+)
+
+// InstallRegistryTLS copies the client certificate, key and CA of the registry to the <certsDir>/<registry> directory.
+// The image pulls are done by the runtime daemon and not by the API client,
+// so the daemon picks the TLS material of a mTLS-protected registry from its certs directory,
+// which it reads on every pull.
+func InstallRegistryTLS(certsDir, registry string, tc *types.TLSConfig) error {
+	if !tc.IsSet() {
+		return nil
+	}
+
+	if registry == "" || strings.ContainsAny(registry, `/\`) || strings.Contains(registry, "..") {
+		return fmt.Errorf("invalid registry name %q", registry)
+	}
+
+	if (tc.Cert == "") != (tc.Key == "") {
+		return fmt.Errorf("registry %q: both tls cert and key must be set", registry)
+	}
+
+	dir := filepath.Join(certsDir, registry)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("registry %q: failed to create the certs directory: %w", registry, err)
+	}
+
+	files := []struct {
+		src, name string
+		mode      os.FileMode
+	}{
+		{tc.Cert, registryClientCertFile, 0644},
+		{tc.Key, registryClientKeyFile, 0600},
+		{tc.CA, registryCAFile, 0644},
+	}
+
+	for _, f := range files {
+		if f.src == "" {
+			continue
+		}
+
+		if err := utils.CopyFile(f.src, filepath.Join(dir, f.name), f.mode); err != nil {
+			return fmt.Errorf("registry %q: failed to install %s: %w", registry, f.src, err)
+		}
+	}
+
+	return nil
+}
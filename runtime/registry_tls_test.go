@@ -0,0 +1,86 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestInstallRegistryTLS(t *testing.T) {
+	src := t.TempDir()
+	for name, content := range map[string]string{
+		"client.pem": "cert",
+		"client.key": "key",
+		"ca.pem":     "ca",
+	} {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	certsDir := t.TempDir()
+
+	tc := &types.TLSConfig{
+		Cert: filepath.Join(src, "client.pem"),
+		Key:  filepath.Join(src, "client.key"),
+		CA:   filepath.Join(src, "ca.pem"),
+	}
+
+	if err := InstallRegistryTLS(certsDir, "registry.example.com:5000", tc); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"client.cert": "cert",
+		"client.key":  "key",
+		"ca.crt":      "ca",
+	} {
+		got, err := os.ReadFile(filepath.Join(certsDir, "registry.example.com:5000", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	// no tls material, nothing is installed
+	if err := InstallRegistryTLS(certsDir, "other.example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(certsDir, "other.example.com")); !os.IsNotExist(err) {
+		t.Errorf("certs directory of a registry without tls material is created")
+	}
+
+	tests := map[string]struct {
+		registry string
+		tc       *types.TLSConfig
+	}{
+		"cert without key": {
+			registry: "registry.example.com",
+			tc:       &types.TLSConfig{Cert: tc.Cert},
+		},
+		"path traversal": {
+			registry: "../registry.example.com",
+			tc:       tc,
+		},
+		"missing file": {
+			registry: "registry.example.com",
+			tc:       &types.TLSConfig{CA: filepath.Join(src, "missing.pem")},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := InstallRegistryTLS(certsDir, tt.registry, tt.tc); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -6,7 +6,10 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -26,19 +29,35 @@ type ContainerRuntime interface {
 	WithMgmtNet(*types.MgmtNet)
 	// Instructs the runtime not to delete the mgmt network on destroy
 	WithKeepMgmtNet()
+	// CheckVersion checks the runtime API version against the version required by containerlab
+	// and by the features used by the nodes
+	CheckVersion(ctx context.Context, nodes []*types.NodeConfig) error
 	// Create container (bridge) network
 	CreateNet(context.Context) error
 	// Delete container (bridge) network
 	DeleteNet(context.Context) error
-	// Pull container image if not present
-	PullImage(context.Context, string, types.PullPolicyValue) error
+	// InspectMgmtNet returns the details of the existing management network
+	// or nil if the network doesn't exist
+	InspectMgmtNet(context.Context) (*NetworkInfo, error)
+	// Pull container image if not present.
+	// The last argument is the image platform (os/arch[/variant]), empty value selects the host platform
+	PullImage(context.Context, string, types.PullPolicyValue, string) error
+	// ImportImage loads the images of the image archive created with `docker save` by its path
+	ImportImage(ctx context.Context, tarPath string) error
+	// BuildImage builds the image from the Dockerfile of the build context and tags it with the build tag.
+	// The build is skipped when the image with the tag is built from the same context, unless it is forced
+	BuildImage(ctx context.Context, spec *types.ImageBuild) error
 	// CreateContainer creates a container, but does not start it
 	CreateContainer(context.Context, *types.NodeConfig) (string, error)
+	// GetContainerSpec returns the runtime-specific spec CreateContainer submits to create the container of the node,
+	// without creating the container
+	GetContainerSpec(context.Context, *types.NodeConfig) (interface{}, error)
 	// Start pre-created container by its name. Returns an extra interface that can be used to receive signals
 	// about the container life-cycle after it was created, e.g. for post-deploy tasks
 	StartContainer(context.Context, string, Node) (interface{}, error)
-	// Stop running container by its name
-	StopContainer(context.Context, string) error
+	// StopContainer stops the running container by its name. The signal, SIGTERM when empty, is sent to the container
+	// first and the container is killed if it doesn't exit within the runtime timeout
+	StopContainer(ctx context.Context, cID string, signal string) error
 	// Pause a container identified by its name
 	PauseContainer(context.Context, string) error
 	// UnPause / resume a container identified by its name
@@ -60,8 +79,29 @@ type ContainerRuntime interface {
 	GetHostsPath(context.Context, string) (string, error)
 	// GetContainerStatus retrieves the ContainerStatus of the named container
 	GetContainerStatus(ctx context.Context, cID string) ContainerStatus
+	// IsContainerOOMKilled reports whether the container was killed by the OOM killer
+	IsContainerOOMKilled(ctx context.Context, cID string) (bool, error)
+	// CopyFromContainer copies the file by srcPath in the container to the dstPath on the host
+	CopyFromContainer(ctx context.Context, cID, srcPath, dstPath string) error
+	// GetContainerStats returns the current resource usage of the container.
+	// ErrStatsNotSupported is returned by the runtimes that can't report the resource usage
+	GetContainerStats(ctx context.Context, cID string) (*ContainerStats, error)
+	// InspectImage returns the details of the local container image
+	InspectImage(ctx context.Context, imageName string) (*ImageInfo, error)
+	// CreateVolume creates the named volume with the labels, an existing volume is left intact
+	CreateVolume(ctx context.Context, name string, labels map[string]string) error
+	// ListVolumes returns the names of the volumes matching the filters
+	ListVolumes(ctx context.Context, filters []*types.GenericFilter) ([]string, error)
+	// DeleteVolume deletes the named volume
+	DeleteVolume(ctx context.Context, name string) error
+	// GetContainerLogs returns the stdout and stderr of the container interleaved in a single stream
+	GetContainerLogs(ctx context.Context, cID string, opts LogOptions) (io.ReadCloser, error)
 }
 
+// ErrStatsNotSupported is returned when the resource usage of the container can't be retrieved,
+// e.g. by the runtimes without the stats API or for the nodes that don't run in a container.
+var ErrStatsNotSupported = errors.New("container stats are not supported")
+
 type ContainerStatus string
 
 const (
@@ -70,6 +110,58 @@ const (
 	Stopped  = "Stopped"
 )
 
+// statuses of the container health check.
+const (
+	// HealthNone is the health of the container without a health check.
+	HealthNone      = "none"
+	HealthStarting  = "starting"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// NetworkInfo contains the details of an existing container network.
+type NetworkInfo struct {
+	Name       string
+	IPv4Subnet string
+	IPv6Subnet string
+	// IPv4Gateway and IPv6Gateway are empty when the network doesn't report the gateway of the subnet
+	IPv4Gateway string
+	IPv6Gateway string
+	// MTU is zero when the network doesn't have an MTU set explicitly
+	MTU    int
+	Labels map[string]string
+}
+
+// ContainerStats is the resource usage of a container.
+type ContainerStats struct {
+	// MemoryUsage is the memory used by the container in bytes, excluding the page cache.
+	MemoryUsage uint64
+	// MemoryLimit is the memory limit of the container in bytes, zero when it is not known.
+	// Docker and podman report the memory of the host for the containers without a memory limit.
+	MemoryLimit uint64
+	// CPUPercent is the CPU usage of the container, 100% being a single CPU core fully used.
+	CPUPercent float64
+}
+
+// LogOptions are the options of the container logs request.
+type LogOptions struct {
+	// Follow keeps streaming the new logs until the context is cancelled or the container stops.
+	Follow bool
+	// Tail is the number of the last lines to return, all lines are returned when it is zero.
+	Tail int
+	// Since returns the logs produced after the time, all logs are returned when it is zero.
+	Since time.Time
+}
+
+// ImageInfo contains the details of a local container image.
+type ImageInfo struct {
+	ID string
+	// Size is the size of the image on disk in bytes.
+	Size int64
+	// Env is the environment set by the image in the KEY=value format.
+	Env []string
+}
+
 type Initializer func() ContainerRuntime
 
 type RuntimeOption func(ContainerRuntime)
@@ -80,6 +172,15 @@ type RuntimeConfig struct {
 	Debug            bool
 	KeepMgmtNet      bool
 	VerifyLinkParams *links.VerifyLinkParams
+	// LabName is the name of the lab the runtime manages the resources of, e.g. the mgmt network.
+	LabName string
+	// Socket is the path or the URI of the runtime API socket overriding the runtime default.
+	Socket string
+	// RegistryTLS is the client TLS material of the mTLS-protected registries by the registry host
+	RegistryTLS map[string]*types.TLSConfig
+	// PullRetries is the number of times a failed image pull is retried,
+	// zero selects DefaultPullRetries and a negative value disables the retries.
+	PullRetries int
 }
 
 var ContainerRuntimes = map[string]Initializer{}
@@ -88,6 +189,15 @@ func Register(name string, initFn Initializer) {
 	ContainerRuntimes[name] = initFn
 }
 
+// SocketURI returns the URI of the runtime API socket set by a path or by a URI.
+func SocketURI(socket string) string {
+	if strings.Contains(socket, "://") {
+		return socket
+	}
+
+	return "unix://" + socket
+}
+
 func WithConfig(cfg *RuntimeConfig) RuntimeOption {
 	return func(r ContainerRuntime) {
 		r.WithConfig(cfg)
@@ -149,6 +259,76 @@ TIMEOUT_LOOP:
 	return resultErr
 }
 
+// WaitForContainerHealthy waits for the health check of the container to report it healthy by polling its status.
+// The container without a health check is considered healthy once it is running.
+// The status is checked every interval until the timeout expires or the context is done.
+func WaitForContainerHealthy(ctx context.Context, r ContainerRuntime, contName, nodeName string,
+	timeout, interval time.Duration,
+) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	timeoutC := time.After(timeout)
+	startTime := time.Now()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctrs, err := r.ListContainers(ctx, []*types.GenericFilter{{FilterType: "name", Match: contName}})
+			if err == nil && len(ctrs) > 0 && ctrs[0].State == "running" {
+				switch status := ctrs[0].Status; {
+				case strings.Contains(status, "(healthy)"):
+					return nil
+				case !strings.Contains(status, "health"):
+					log.Warnf("node %q waits for container %q to become healthy, but the container has no health check",
+						nodeName, contName)
+					return nil
+				}
+			}
+
+			log.Infof("node %q depends on container %q, which is not healthy yet. Waited %s. Retrying...",
+				nodeName, contName, time.Since(startTime).Truncate(time.Second))
+
+		case <-timeoutC:
+			return fmt.Errorf("node %q waited %s for container %q to become healthy, which did not happen. Giving up now",
+				nodeName, time.Since(startTime).Truncate(time.Second), contName)
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitForContainerExited waits for the container to exit by polling its status every interval
+// until the timeout expires or the context is done.
+// The container that is not found, e.g. removed on exit, is considered exited.
+func WaitForContainerExited(ctx context.Context, r ContainerRuntime, contName, nodeName string,
+	timeout, interval time.Duration,
+) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	timeoutC := time.After(timeout)
+	startTime := time.Now()
+
+	for {
+		select {
+		case <-ticker.C:
+			if r.GetContainerStatus(ctx, contName) != Running {
+				return nil
+			}
+
+			log.Debugf("node %q waits for container %q to exit. Waited %s. Retrying...",
+				nodeName, contName, time.Since(startTime).Truncate(time.Second))
+
+		case <-timeoutC:
+			return fmt.Errorf("node %q waited %s for container %q to exit, which did not happen. Giving up now",
+				nodeName, time.Since(startTime).Truncate(time.Second), contName)
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // Node is an interface that represents a node in the lab
 // and is implemented by containerlab nodes.
 type Node interface {
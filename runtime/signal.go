@@ -0,0 +1,45 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultStopSignal is the signal the containers are stopped with when no signal is requested.
+const DefaultStopSignal = "SIGTERM"
+
+// ParseStopSignal returns the signal the container is stopped with by its name, with or without
+// the SIG prefix, or by its number. The empty name is the DefaultStopSignal.
+func ParseStopSignal(s string) (syscall.Signal, error) {
+	if s == "" {
+		s = DefaultStopSignal
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 {
+			return 0, fmt.Errorf("invalid stop signal %q", s)
+		}
+
+		return syscall.Signal(n), nil
+	}
+
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return 0, fmt.Errorf("unknown stop signal %q", s)
+	}
+
+	return sig, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestParseStopSignal(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    syscall.Signal
+		wantErr bool
+	}{
+		"default":     {in: "", want: syscall.SIGTERM},
+		"full name":   {in: "SIGINT", want: syscall.SIGINT},
+		"short name":  {in: "term", want: syscall.SIGTERM},
+		"number":      {in: "9", want: syscall.SIGKILL},
+		"unknown":     {in: "SIGNOPE", wantErr: true},
+		"zero number": {in: "0", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseStopSignal(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStopSignal(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseStopSignal(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
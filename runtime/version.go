@@ -0,0 +1,175 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// VersionRequirement is the minimum runtime API version required by containerlab or by one of its features.
+type VersionRequirement struct {
+	// Feature is the feature that needs the version, empty for the version containerlab needs to work at all.
+	Feature string
+	// MinVersion is the minimum API version, e.g. 1.41.
+	MinVersion string
+	// UsedBy returns true if the node uses the feature.
+	// The requirement applies to every lab when UsedBy is nil.
+	UsedBy func(*types.NodeConfig) bool
+}
+
+// VersionError is returned when the runtime API version doesn't meet a version requirement.
+type VersionError struct {
+	Runtime string
+	// Version is the API version detected, Detail describes where it was detected, e.g. the engine version.
+	Version string
+	Detail  string
+	Req     VersionRequirement
+	// Nodes are the nodes using the feature of the requirement.
+	Nodes []string
+}
+
+func (e *VersionError) Error() string {
+	detected := fmt.Sprintf("%s API version %s", e.Runtime, e.Version)
+	if e.Detail != "" {
+		detected += " (" + e.Detail + ")"
+	}
+
+	if e.Req.Feature == "" {
+		return fmt.Sprintf("%s is not supported, containerlab requires version %s or newer",
+			detected, e.Req.MinVersion)
+	}
+
+	return fmt.Sprintf("%s is too old for %s used by nodes %s, version %s or newer is required",
+		detected, e.Req.Feature, strings.Join(e.Nodes, ", "), e.Req.MinVersion)
+}
+
+// CheckVersionRequirements checks the runtime API version against the requirements applying to the nodes.
+// detail is added to the errors to describe the detected version, e.g. the engine version.
+func CheckVersionRequirements(runtimeName, version, detail string, reqs []VersionRequirement,
+	nodes []*types.NodeConfig,
+) error {
+	var errs []error
+
+	for _, req := range reqs {
+		var users []string
+
+		if req.UsedBy != nil {
+			for _, n := range nodes {
+				if req.UsedBy(n) {
+					users = append(users, n.ShortName)
+				}
+			}
+
+			if len(users) == 0 {
+				continue
+			}
+
+			sort.Strings(users)
+		}
+
+		cmp, err := CompareVersions(version, req.MinVersion)
+		if err != nil {
+			return fmt.Errorf("failed to check the %s API version: %w", runtimeName, err)
+		}
+
+		if cmp < 0 {
+			errs = append(errs, &VersionError{
+				Runtime: runtimeName,
+				Version: version,
+				Detail:  detail,
+				Req:     req,
+				Nodes:   users,
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CompareVersions compares the dot separated versions a and b and returns -1, 0 or +1
+// when a is lower, equal or higher than b. The missing trailing components are zero,
+// so 1.41 equals 1.41.0. A pre-release version, e.g. 24.0.0-rc.1, is lower than the release
+// with the same version components, and the pre-release suffixes are compared as strings.
+// The build metadata, e.g. +dfsg1, is ignored.
+func CompareVersions(a, b string) (int, error) {
+	aNums, aPre, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bNums, bPre, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < len(aNums) || i < len(bNums); i++ {
+		var x, y int
+		if i < len(aNums) {
+			x = aNums[i]
+		}
+
+		if i < len(bNums) {
+			y = bNums[i]
+		}
+
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0, nil
+	// a release is newer than any of its pre-releases
+	case aPre == "":
+		return 1, nil
+	case bPre == "":
+		return -1, nil
+	}
+
+	return strings.Compare(aPre, bPre), nil
+}
+
+// parseVersion splits the version into its numeric components and the pre-release suffix.
+// The leading "v" and the build metadata are dropped.
+func parseVersion(v string) ([]int, string, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(v), "v")
+
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+
+	var pre string
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, pre = s[:i], s[i+1:]
+	}
+
+	if s == "" {
+		return nil, "", fmt.Errorf("invalid version %q", v)
+	}
+
+	parts := strings.Split(s, ".")
+	nums := make([]int, 0, len(parts))
+
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("invalid version %q", v)
+		}
+
+		nums = append(nums, n)
+	}
+
+	return nums, pre, nil
+}
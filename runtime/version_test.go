@@ -0,0 +1,112 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{a: "1.41", b: "1.41", want: 0},
+		{a: "1.41", b: "1.41.0", want: 0},
+		{a: "1.40", b: "1.41", want: -1},
+		{a: "1.43", b: "1.41", want: 1},
+		// the components are compared as numbers, not as strings
+		{a: "1.9", b: "1.10", want: -1},
+		{a: "v4.7.1", b: "4.0.0", want: 1},
+		{a: "24.0.0-rc.1", b: "24.0.0", want: -1},
+		{a: "24.0.0", b: "24.0.0-rc.1", want: 1},
+		{a: "24.0.0-rc.1", b: "24.0.0-rc.2", want: -1},
+		{a: "4.4.0-dev", b: "4.4.0-dev", want: 0},
+		{a: "4.4.0-dev", b: "4.3.1", want: 1},
+		{a: "20.10.24+dfsg1", b: "20.10.24", want: 0},
+		{a: "", b: "1.41", wantErr: true},
+		{a: "1.x", b: "1.41", wantErr: true},
+		{a: "1.41", b: "-rc1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := CompareVersions(tt.a, tt.b)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CompareVersions(%q, %q) got error %v, want error %v", tt.a, tt.b, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckVersionRequirements(t *testing.T) {
+	reqs := []VersionRequirement{
+		{MinVersion: "1.40"},
+		{
+			Feature:    "image platform selection",
+			MinVersion: "1.41",
+			UsedBy:     func(n *types.NodeConfig) bool { return n.Platform != "" },
+		},
+	}
+
+	nodes := []*types.NodeConfig{
+		{ShortName: "srl2", Platform: "linux/arm64"},
+		{ShortName: "srl1", Platform: "linux/amd64"},
+		{ShortName: "client"},
+	}
+
+	tests := map[string]struct {
+		version string
+		nodes   []*types.NodeConfig
+		wantErr string
+	}{
+		"newer version": {
+			version: "1.43",
+			nodes:   nodes,
+		},
+		"feature not used": {
+			version: "1.40",
+			nodes:   nodes[2:],
+		},
+		"feature too old": {
+			version: "1.40",
+			nodes:   nodes,
+			wantErr: "docker API version 1.40 (engine 19.03.15) is too old for image platform selection " +
+				"used by nodes srl1, srl2, version 1.41 or newer is required",
+		},
+		"unsupported version": {
+			version: "1.39",
+			nodes:   nodes[2:],
+			wantErr: "docker API version 1.39 (engine 19.03.15) is not supported, " +
+				"containerlab requires version 1.40 or newer",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckVersionRequirements("docker", tt.version, "engine 19.03.15", reqs, tt.nodes)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+
+			var verr *VersionError
+			if tt.wantErr != "" && !errors.As(err, &verr) {
+				t.Errorf("got error of type %T, want *VersionError", err)
+			}
+		})
+	}
+}
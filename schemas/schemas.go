@@ -0,0 +1,19 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package schemas embeds the JSON schemas of the documents produced by containerlab.
+package schemas
+
+import _ "embed"
+
+// Graph is the JSON schema of the graph document produced by the graph command with the json format.
+//
+//go:embed graph.schema.json
+var Graph []byte
+
+// TopologyData is the JSON schema of the topology data exported to the lab directory.
+// The same data model is used by the json and the msgpack export formats.
+//
+//go:embed topology-data.schema.json
+var TopologyData []byte
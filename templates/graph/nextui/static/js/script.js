@@ -249,6 +249,12 @@
                     return model._data.group
                 }
             },
+            // disabled nodes are greyed out
+            color: function (model) {
+                if (model._data.state === 'disabled') {
+                    return '#D1D5DB'
+                }
+            },
         },
         linkConfig: {
             linkType: 'curve',
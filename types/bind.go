@@ -2,9 +2,14 @@ package types
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+// volumeNameRe matches the names of the named volumes, same as docker does.
+// Host paths start with `/`, `.` or `~`, and relative host paths have at least one `/`.
+var volumeNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
 // Bind represents a bind mount.
 type Bind struct {
 	src  string
@@ -41,6 +46,11 @@ func (b *Bind) Dst() string {
 	return b.dst
 }
 
+// IsVolume returns true if the source of the bind mount is a named volume rather than a host path.
+func (b *Bind) IsVolume() bool {
+	return volumeNameRe.MatchString(b.src)
+}
+
 // Mode returns the mode of the bind mount.
 func (b *Bind) Mode() string {
 	return b.mode
@@ -0,0 +1,35 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import "testing"
+
+func TestBindIsVolume(t *testing.T) {
+	tests := map[string]bool{
+		"/opt/configs:/configs":      false,
+		"./configs:/configs":         false,
+		"../configs:/configs":        false,
+		"~/configs:/configs":         false,
+		"configs/srl1:/configs":      false,
+		"__clabNodeDir__:/configs":   false,
+		"data:/data":                 true,
+		"srl1-data:/data:rw":         true,
+		"srl_1.data:/var/lib/db:ro":  true,
+		"__clabDir__/topo.json:/tmp": false,
+	}
+
+	for bind, want := range tests {
+		t.Run(bind, func(t *testing.T) {
+			b, err := NewBind(bind)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := b.IsVolume(); got != want {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ExecPhase is the node lifecycle phase the exec command runs in.
+type ExecPhase string
+
+const (
+	// ExecPhasePostStart commands run right after the node container is started, before its links are created.
+	ExecPhasePostStart ExecPhase = "post-start"
+	// ExecPhasePostLinks commands run when all the links of the node are created.
+	ExecPhasePostLinks ExecPhase = "post-links"
+	// ExecPhasePostHealthy commands run after the post-deploy phase of the lab nodes.
+	ExecPhasePostHealthy ExecPhase = "post-healthy"
+
+	// DefaultExecPhase is the phase of the exec commands defined without a phase.
+	DefaultExecPhase = ExecPhasePostHealthy
+)
+
+// Exec is a command executed in the node in one of its lifecycle phases.
+// In the topology file it is defined either as a command string that runs in the default phase,
+// or as a map with the phase, cmd and expected-rc keys.
+type Exec struct {
+	Phase ExecPhase `yaml:"phase,omitempty" json:"phase"`
+	Cmd   string    `yaml:"cmd" json:"cmd"`
+	// ExpectedRC is the return code the command is expected to exit with,
+	// the deployment fails when the command exits with a different one.
+	ExpectedRC *int `yaml:"expected-rc,omitempty" json:"expected-rc,omitempty"`
+}
+
+// Interface compliance.
+var (
+	_ yaml.Unmarshaler = &Exec{}
+	_ yaml.Marshaler   = &Exec{}
+)
+
+// UnmarshalYAML is a custom unmarshaller for Exec that allows to define the exec entry as a plain command string.
+func (e *Exec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var cmd string
+	if err := unmarshal(&cmd); err == nil {
+		e.Cmd = cmd
+		e.Phase = DefaultExecPhase
+
+		return nil
+	}
+
+	// define an alias type to avoid recursion during unmarshalling
+	type ExecAlias Exec
+	var ex ExecAlias
+	if err := unmarshal(&ex); err != nil {
+		return err
+	}
+
+	if ex.Cmd == "" {
+		return fmt.Errorf("exec entry %+v has no cmd", ex)
+	}
+
+	switch ex.Phase {
+	case "":
+		ex.Phase = DefaultExecPhase
+	case ExecPhasePostStart, ExecPhasePostLinks, ExecPhasePostHealthy:
+	default:
+		return fmt.Errorf("exec %q has an unknown phase %q, supported phases %q", ex.Cmd, ex.Phase,
+			[]ExecPhase{ExecPhasePostStart, ExecPhasePostLinks, ExecPhasePostHealthy})
+	}
+
+	*e = Exec(ex)
+
+	return nil
+}
+
+// MarshalYAML is a custom marshaller for Exec that writes the exec entry of the default phase
+// without the expected return code as a plain command string.
+func (e *Exec) MarshalYAML() (interface{}, error) {
+	if e.isPlain() {
+		return e.Cmd, nil
+	}
+
+	// define an alias type to avoid recursion during marshalling
+	type ExecAlias Exec
+
+	return (*ExecAlias)(e), nil
+}
+
+// isPlain returns true if the exec entry is fully defined by its command string.
+func (e *Exec) isPlain() bool {
+	return (e.Phase == "" || e.Phase == DefaultExecPhase) && e.ExpectedRC == nil
+}
+
+// splitExecEntries splits the exec entries of the topology file into the plain commands of the default phase
+// and the other entries, keeping the declaration order. The plain commands of the default phase declared after
+// an entry with the expected return code stay with the other entries to run in the declaration order.
+func splitExecEntries(entries []*Exec) ([]string, []*Exec) {
+	var cmds []string
+	var phases []*Exec
+
+	asserted := false
+
+	for _, e := range entries {
+		if !e.isPlain() && (e.Phase == "" || e.Phase == DefaultExecPhase) {
+			asserted = true
+		}
+
+		if e.isPlain() && !asserted {
+			cmds = append(cmds, e.Cmd)
+			continue
+		}
+
+		phases = append(phases, e)
+	}
+
+	return cmds, phases
+}
+
+// joinExecEntries is the reverse of splitExecEntries.
+func joinExecEntries(cmds []string, phases []*Exec) []*Exec {
+	var entries []*Exec
+
+	for _, c := range cmds {
+		entries = append(entries, &Exec{Cmd: c})
+	}
+
+	return append(entries, phases...)
+}
+
+// GetExecCmds returns the exec commands of the node for the phase p in the declaration order.
+func (n *NodeConfig) GetExecCmds(p ExecPhase) []string {
+	var cmds []string
+
+	for _, e := range n.GetExecs(p) {
+		cmds = append(cmds, e.Cmd)
+	}
+
+	return cmds
+}
+
+// GetExecs returns the exec entries of the node for the phase p in the declaration order.
+func (n *NodeConfig) GetExecs(p ExecPhase) []*Exec {
+	var execs []*Exec
+
+	if p == DefaultExecPhase {
+		for _, c := range n.Exec {
+			execs = append(execs, &Exec{Phase: p, Cmd: c})
+		}
+	}
+
+	for _, e := range n.ExecPhases {
+		if e.Phase == p || (e.Phase == "" && p == DefaultExecPhase) {
+			execs = append(execs, e)
+		}
+	}
+
+	return execs
+}
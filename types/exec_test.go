@@ -0,0 +1,163 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExecUnmarshalYAML(t *testing.T) {
+	tests := map[string]struct {
+		in         string
+		want       []string
+		wantPhases []*Exec
+		wantErr    bool
+	}{
+		"plain strings": {
+			in:   `exec: ["ip link", "echo hi"]`,
+			want: []string{"ip link", "echo hi"},
+		},
+		"mixed entries": {
+			in: `
+exec:
+  - phase: post-start
+    cmd: sysctl -w net.ipv4.ip_forward=1
+  - echo hi
+  - phase: post-links
+    cmd: ip link
+  - cmd: echo default`,
+			want: []string{"echo hi", "echo default"},
+			wantPhases: []*Exec{
+				{Phase: ExecPhasePostStart, Cmd: "sysctl -w net.ipv4.ip_forward=1"},
+				{Phase: ExecPhasePostLinks, Cmd: "ip link"},
+			},
+		},
+		"expected return codes": {
+			in: `
+exec:
+  - echo 1
+  - cmd: test -f /tmp/ready
+    expected-rc: 1
+  - echo 2
+  - phase: post-links
+    cmd: ping -c1 10.0.0.2
+    expected-rc: 0`,
+			want: []string{"echo 1"},
+			wantPhases: []*Exec{
+				{Phase: ExecPhasePostHealthy, Cmd: "test -f /tmp/ready", ExpectedRC: utils.IntPointer(1)},
+				{Phase: ExecPhasePostHealthy, Cmd: "echo 2"},
+				{Phase: ExecPhasePostLinks, Cmd: "ping -c1 10.0.0.2", ExpectedRC: utils.IntPointer(0)},
+			},
+		},
+		"unknown phase": {
+			in: `
+exec:
+  - phase: pre-start
+    cmd: echo hi`,
+			wantErr: true,
+		},
+		"missing cmd": {
+			in: `
+exec:
+  - phase: post-start`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := &NodeDefinition{}
+
+			err := yaml.Unmarshal([]byte(tt.in), got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v %+v", got.Exec, got.ExecPhases)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tt.want, got.Exec); d != "" {
+				t.Fatalf("exec mismatch (-want +got):\n%s", d)
+			}
+
+			if d := cmp.Diff(tt.wantPhases, got.ExecPhases); d != "" {
+				t.Fatalf("exec phases mismatch (-want +got):\n%s", d)
+			}
+
+			// the marshalled node definition unmarshals to the same exec commands
+			b, err := yaml.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			again := &NodeDefinition{}
+			if err := yaml.Unmarshal(b, again); err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(got, again); d != "" {
+				t.Fatalf("marshalled node definition mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestGetExecCmds(t *testing.T) {
+	n := &NodeConfig{
+		Exec: []string{"echo 1", "echo 3"},
+		ExecPhases: []*Exec{
+			{Phase: ExecPhasePostStart, Cmd: "echo 2"},
+			{Phase: ExecPhasePostLinks, Cmd: "echo 5"},
+			{Phase: ExecPhasePostStart, Cmd: "echo 4"},
+		},
+	}
+
+	want := map[ExecPhase][]string{
+		ExecPhasePostStart:   {"echo 2", "echo 4"},
+		ExecPhasePostLinks:   {"echo 5"},
+		ExecPhasePostHealthy: {"echo 1", "echo 3"},
+	}
+
+	for p, w := range want {
+		if d := cmp.Diff(w, n.GetExecCmds(p)); d != "" {
+			t.Errorf("phase %s commands mismatch (-want +got):\n%s", p, d)
+		}
+	}
+}
+
+func TestGetExecs(t *testing.T) {
+	n := &NodeConfig{
+		Exec: []string{"echo 1"},
+		ExecPhases: []*Exec{
+			{Phase: ExecPhasePostStart, Cmd: "echo 2", ExpectedRC: utils.IntPointer(0)},
+			{Phase: ExecPhasePostHealthy, Cmd: "test -f /tmp/ready", ExpectedRC: utils.IntPointer(1)},
+			{Phase: ExecPhasePostHealthy, Cmd: "echo 3"},
+		},
+	}
+
+	want := map[ExecPhase][]*Exec{
+		ExecPhasePostStart: {{Phase: ExecPhasePostStart, Cmd: "echo 2", ExpectedRC: utils.IntPointer(0)}},
+		ExecPhasePostLinks: nil,
+		ExecPhasePostHealthy: {
+			{Phase: ExecPhasePostHealthy, Cmd: "echo 1"},
+			{Phase: ExecPhasePostHealthy, Cmd: "test -f /tmp/ready", ExpectedRC: utils.IntPointer(1)},
+			{Phase: ExecPhasePostHealthy, Cmd: "echo 3"},
+		},
+	}
+
+	for p, w := range want {
+		if d := cmp.Diff(w, n.GetExecs(p)); d != "" {
+			t.Errorf("phase %s execs mismatch (-want +got):\n%s", p, d)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormatExposedPorts returns the host ports of the exposed services in the comma separated
+// <service>:<host port> format, sorted by the service name.
+func FormatExposedPorts(ports map[string]int) string {
+	services := make([]string, 0, len(ports))
+	for s := range ports {
+		services = append(services, s)
+	}
+
+	sort.Strings(services)
+
+	entries := make([]string, 0, len(services))
+	for _, s := range services {
+		entries = append(entries, s+":"+strconv.Itoa(ports[s]))
+	}
+
+	return strings.Join(entries, ",")
+}
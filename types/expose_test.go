@@ -0,0 +1,25 @@
+package types
+
+import "testing"
+
+func TestFormatExposedPorts(t *testing.T) {
+	tests := map[string]struct {
+		ports map[string]int
+		want  string
+	}{
+		"none":   {},
+		"single": {ports: map[string]int{"ssh": 49152}, want: "ssh:49152"},
+		"sorted": {
+			ports: map[string]int{"ssh": 49152, "gnmi": 49153, "netconf": 49154},
+			want:  "gnmi:49153,netconf:49154,ssh:49152",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := FormatExposedPorts(tt.ports); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
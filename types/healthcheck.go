@@ -0,0 +1,109 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// HealthcheckTestNone disables the health check of the image.
+	HealthcheckTestNone = "NONE"
+	// HealthcheckTestCmd runs the health check command with its arguments directly.
+	HealthcheckTestCmd = "CMD"
+	// HealthcheckTestCmdShell runs the health check command with the default shell of the container.
+	HealthcheckTestCmdShell = "CMD-SHELL"
+)
+
+// HealthcheckConfig is the health check of the node container, it overrides the HEALTHCHECK of the image.
+// The unset fields are inherited from the image.
+type HealthcheckConfig struct {
+	// Test is the health check command, one of ["NONE"], ["CMD", args...] or ["CMD-SHELL", command]
+	Test []string `yaml:"test,omitempty" json:"test,omitempty"`
+	// Interval is the time between the health checks
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Timeout is the time after which a single health check is considered failed
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Retries is the number of the consecutive failed health checks reporting the container unhealthy
+	Retries int `yaml:"retries,omitempty" json:"retries,omitempty"`
+	// StartPeriod is the time the container is given to start before the failed health checks are counted
+	StartPeriod time.Duration `yaml:"start-period,omitempty" json:"start-period,omitempty"`
+}
+
+// Merge merges the given HealthcheckConfig into the current one.
+func (h *HealthcheckConfig) Merge(x *HealthcheckConfig) *HealthcheckConfig {
+	if x == nil {
+		return h
+	}
+
+	if len(x.Test) != 0 {
+		h.Test = x.Test
+	}
+
+	if x.Interval != 0 {
+		h.Interval = x.Interval
+	}
+
+	if x.Timeout != 0 {
+		h.Timeout = x.Timeout
+	}
+
+	if x.Retries != 0 {
+		h.Retries = x.Retries
+	}
+
+	if x.StartPeriod != 0 {
+		h.StartPeriod = x.StartPeriod
+	}
+
+	return h
+}
+
+// IsSet returns true if any of the health check settings is defined.
+func (h *HealthcheckConfig) IsSet() bool {
+	return h != nil && (len(h.Test) != 0 || h.Interval != 0 || h.Timeout != 0 || h.Retries != 0 || h.StartPeriod != 0)
+}
+
+// Validate checks that the test command of the health check has the known form
+// and that the durations and the retries are not negative.
+func (h *HealthcheckConfig) Validate() error {
+	if h == nil {
+		return nil
+	}
+
+	if len(h.Test) != 0 {
+		switch h.Test[0] {
+		case HealthcheckTestNone:
+			if len(h.Test) != 1 {
+				return fmt.Errorf("invalid healthcheck test %q, %s takes no arguments", h.Test, HealthcheckTestNone)
+			}
+		case HealthcheckTestCmd, HealthcheckTestCmdShell:
+			if len(h.Test) < 2 {
+				return fmt.Errorf("invalid healthcheck test %q, %s requires a command", h.Test, h.Test[0])
+			}
+		default:
+			return fmt.Errorf("invalid healthcheck test %q, the test must start with one of %s, %s or %s",
+				h.Test, HealthcheckTestNone, HealthcheckTestCmd, HealthcheckTestCmdShell)
+		}
+	}
+
+	durations := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"interval", h.Interval},
+		{"timeout", h.Timeout},
+		{"start-period", h.StartPeriod},
+	}
+
+	for _, d := range durations {
+		if d.d < 0 {
+			return fmt.Errorf("invalid healthcheck %s %s, must not be negative", d.name, d.d)
+		}
+	}
+
+	if h.Retries < 0 {
+		return fmt.Errorf("invalid healthcheck retries %d, must not be negative", h.Retries)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+)
+
+func TestHealthcheckConfigUnmarshal(t *testing.T) {
+	data := `
+test: ["CMD", "cat", "/ready"]
+interval: 5s
+retries: 3
+start-period: 10s
+`
+
+	var got HealthcheckConfig
+	if err := yaml.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := HealthcheckConfig{
+		Test:        []string{"CMD", "cat", "/ready"},
+		Interval:    5 * time.Second,
+		Retries:     3,
+		StartPeriod: 10 * time.Second,
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("healthcheck mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestHealthcheckConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		hc      *HealthcheckConfig
+		wantErr bool
+	}{
+		"nil":             {},
+		"cmd":             {hc: &HealthcheckConfig{Test: []string{"CMD", "cat", "/ready"}}},
+		"cmd-shell":       {hc: &HealthcheckConfig{Test: []string{"CMD-SHELL", "cat /ready"}}},
+		"none":            {hc: &HealthcheckConfig{Test: []string{"NONE"}}},
+		"image test":      {hc: &HealthcheckConfig{Interval: time.Second, Retries: 5}},
+		"cmd without arg": {hc: &HealthcheckConfig{Test: []string{"CMD"}}, wantErr: true},
+		"none with arg":   {hc: &HealthcheckConfig{Test: []string{"NONE", "true"}}, wantErr: true},
+		"unknown test":    {hc: &HealthcheckConfig{Test: []string{"cat", "/ready"}}, wantErr: true},
+		"negative period": {hc: &HealthcheckConfig{StartPeriod: -time.Second}, wantErr: true},
+		"negative retry":  {hc: &HealthcheckConfig{Retries: -1}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.hc.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
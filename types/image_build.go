@@ -0,0 +1,51 @@
+package types
+
+// DefaultDockerfile is the Dockerfile of the image build context used when the dockerfile is not set.
+const DefaultDockerfile = "Dockerfile"
+
+// ImageBuild is the image of the node built from a Dockerfile on deploy.
+type ImageBuild struct {
+	// Context is the path to the directory of the build context
+	Context string `yaml:"context,omitempty" json:"context,omitempty"`
+	// Dockerfile is the path to the Dockerfile relative to the build context
+	Dockerfile string `yaml:"dockerfile,omitempty" json:"dockerfile,omitempty"`
+	// Tag is the tag of the built image, the image of the node is used when not set
+	Tag string `yaml:"tag,omitempty" json:"tag,omitempty"`
+	// Force rebuilds the image even if the image with the tag is built from the same context
+	Force bool `yaml:"-" json:"-"`
+}
+
+// Merge merges the given ImageBuild into the current one.
+func (b *ImageBuild) Merge(x *ImageBuild) *ImageBuild {
+	if x == nil {
+		return b
+	}
+
+	if x.Context != "" {
+		b.Context = x.Context
+	}
+
+	if x.Dockerfile != "" {
+		b.Dockerfile = x.Dockerfile
+	}
+
+	if x.Tag != "" {
+		b.Tag = x.Tag
+	}
+
+	return b
+}
+
+// IsSet returns true if the image of the node is built.
+func (b *ImageBuild) IsSet() bool {
+	return b != nil && (b.Context != "" || b.Dockerfile != "" || b.Tag != "")
+}
+
+// GetDockerfile returns the path to the Dockerfile relative to the build context.
+func (b *ImageBuild) GetDockerfile() string {
+	if b == nil || b.Dockerfile == "" {
+		return DefaultDockerfile
+	}
+
+	return b.Dockerfile
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// networkModes maps the network-mode keywords to whether the mode takes an argument after the colon,
+// e.g. the container name of container:<name>. The empty mode is the default mode, the node is attached
+// to the management network.
+var networkModes = map[string]bool{
+	"":          false,
+	"host":      false,
+	"none":      false,
+	"container": true,
+}
+
+// supportedNetworkModes is the list of the network modes reported in the validation errors.
+const supportedNetworkModes = "host, none, container:<name>[:<phase>]"
+
+// The phases of the network namespace owner container a node with the container:<name>:<phase> network mode waits for
+// before it is created. By default the node waits for the owner container to be running.
+const (
+	ContainerPhaseRunning = "running"
+	// ContainerPhaseHealthy waits for the health check of the owner container to report it healthy.
+	ContainerPhaseHealthy = "healthy"
+)
+
+// ValidateNetworkMode checks the syntax of the network-mode value.
+// It doesn't check that the container referenced by container:<name> exists,
+// since it can be a container deployed outside of the lab.
+func ValidateNetworkMode(mode string) error {
+	keyword, arg, hasArg := strings.Cut(mode, ":")
+
+	needsArg, ok := networkModes[keyword]
+	if !ok || (keyword == "" && hasArg) {
+		return fmt.Errorf("invalid network-mode %q, supported modes are: %s", mode, supportedNetworkModes)
+	}
+
+	switch {
+	case needsArg && strings.TrimSpace(arg) == "":
+		return fmt.Errorf("invalid network-mode %q, %s mode requires a name in the format %s:<name>",
+			mode, keyword, keyword)
+	case !needsArg && hasArg:
+		return fmt.Errorf("invalid network-mode %q, %s mode doesn't take arguments", mode, keyword)
+	}
+
+	if keyword == "container" {
+		name, phase := SplitNetworkModePhase(mode)
+		switch {
+		case name == "container:":
+			return fmt.Errorf("invalid network-mode %q, container mode requires a name in the format container:<name>", mode)
+		case phase != "" && phase != ContainerPhaseRunning && phase != ContainerPhaseHealthy:
+			return fmt.Errorf("invalid network-mode %q, unknown phase %q, supported phases are: %s, %s",
+				mode, phase, ContainerPhaseRunning, ContainerPhaseHealthy)
+		}
+	}
+
+	return nil
+}
+
+// SplitNetworkModePhase splits the container:<name>:<phase> network mode into the container:<name> mode
+// and the phase of the owner container the node waits for. The phase is empty when the mode has none.
+func SplitNetworkModePhase(mode string) (string, string) {
+	if !strings.HasPrefix(mode, "container:") {
+		return mode, ""
+	}
+
+	name, phase, ok := strings.Cut(strings.TrimPrefix(mode, "container:"), ":")
+	if !ok {
+		return mode, ""
+	}
+
+	return "container:" + name, phase
+}
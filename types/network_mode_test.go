@@ -0,0 +1,62 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNetworkMode(t *testing.T) {
+	tests := map[string]struct {
+		mode    string
+		wantErr string
+	}{
+		"default":            {mode: ""},
+		"host":               {mode: "host"},
+		"none":               {mode: "none"},
+		"container":          {mode: "container:node1"},
+		"container healthy":  {mode: "container:node1:healthy"},
+		"container running":  {mode: "container:node1:running"},
+		"unknown phase":      {mode: "container:node1:ready", wantErr: `unknown phase "ready"`},
+		"phase without name": {mode: "container::healthy", wantErr: "container mode requires a name"},
+		"typo":               {mode: "containr:node1", wantErr: "supported modes are: host, none, container:<name>[:<phase>]"},
+		"unknown":            {mode: "bridge", wantErr: "supported modes are"},
+		"container no name":  {mode: "container", wantErr: "container mode requires a name"},
+		"container empty":    {mode: "container:", wantErr: "container mode requires a name"},
+		"host with argument": {mode: "host:node1", wantErr: "host mode doesn't take arguments"},
+		"colon only":         {mode: ":node1", wantErr: "supported modes are"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateNetworkMode(tt.mode)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitNetworkModePhase(t *testing.T) {
+	tests := map[string]struct {
+		mode      string
+		wantMode  string
+		wantPhase string
+	}{
+		"host":              {mode: "host", wantMode: "host"},
+		"container":         {mode: "container:node1", wantMode: "container:node1"},
+		"container healthy": {mode: "container:node1:healthy", wantMode: "container:node1", wantPhase: "healthy"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mode, phase := SplitNetworkModePhase(tt.mode)
+			if mode != tt.wantMode || phase != tt.wantPhase {
+				t.Fatalf("got %q, %q, want %q, %q", mode, phase, tt.wantMode, tt.wantPhase)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package types
 
 import (
 	"os"
+	"reflect"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
@@ -24,19 +26,27 @@ type NodeDefinition struct {
 	AutoRemove            *bool             `yaml:"auto-remove,omitempty"`
 	Config                *ConfigDispatcher `yaml:"config,omitempty"`
 	Image                 string            `yaml:"image,omitempty"`
+	ImageArchMap          map[string]string `yaml:"image-arch-map,omitempty"`
 	ImagePullPolicy       string            `yaml:"image-pull-policy,omitempty"`
 	License               string            `yaml:"license,omitempty"`
+	LicensePool           LicensePool       `yaml:"license-pool,omitempty"`
 	Position              string            `yaml:"position,omitempty"`
 	Entrypoint            string            `yaml:"entrypoint,omitempty"`
 	Cmd                   string            `yaml:"cmd,omitempty"`
 	// list of subject Alternative Names (SAN) to be added to the node's certificate
 	SANs []string `yaml:"SANs,omitempty"`
-	// list of commands to run in container
-	Exec []string `yaml:"exec,omitempty"`
+	// list of commands to run in container after the lab nodes are healthy
+	Exec []string `yaml:"-"`
+	// list of commands to run in container in the other node lifecycle phases,
+	// both lists are set from the exec entries of the topology file
+	ExecPhases []*Exec `yaml:"-"`
 	// list of bind mount compatible strings
 	Binds []string `yaml:"binds,omitempty"`
 	// list of port bindings
 	Ports []string `yaml:"ports,omitempty"`
+	// list of the kind services published on the host ports picked from the dynamic port range,
+	// an empty list disables the services exposed by the kind or the defaults
+	Expose []string `yaml:"expose,omitempty"`
 	// user-defined IPv4 address in the management network
 	MgmtIPv4 string `yaml:"mgmt-ipv4,omitempty"`
 	// user-defined IPv6 address in the management network
@@ -64,20 +74,55 @@ type NodeDefinition struct {
 	CPUSet string `yaml:"cpu-set,omitempty"`
 	// Set node Memory (cgroup or hypervisor)
 	Memory string `yaml:"memory,omitempty"`
+	// Set node memory plus swap limit, equal to the memory limit disables the swap
+	MemorySwap string `yaml:"memory-swap,omitempty"`
+	// Parent cgroup the node container is placed under
+	CgroupParent string `yaml:"cgroup-parent,omitempty"`
+	// Platform of the node image in the os/arch[/variant] format, e.g. linux/amd64
+	Platform string `yaml:"platform,omitempty"`
+	// Disable OOM killer for the node
+	OomKillDisable *bool `yaml:"oom-kill-disable,omitempty"`
+	// OOM score adjustment of the node processes
+	OomScoreAdj *int `yaml:"oom-score-adj,omitempty"`
+	// Run an init process as PID 1 to reap the zombie processes
+	Init *bool `yaml:"init,omitempty"`
 	// Set the nodes Sysctl
 	Sysctls map[string]string `yaml:"sysctls,omitempty"`
 	// Extra options, may be kind specific
 	Extras *Extras `yaml:"extras,omitempty"`
-	// List of node names to wait for before satarting this particular node
-	WaitFor []string `yaml:"wait-for,omitempty"`
+	// List of the nodes and their phases to wait for before starting this particular node
+	WaitFor []WaitFor `yaml:"wait-for,omitempty"`
+	// Enabled controls whether the node is deployed, nodes are enabled by default
+	Enabled *bool `yaml:"enabled,omitempty"`
 	// DNS configuration
 	DNS *DNSConfig `yaml:"dns,omitempty"`
+	// Time zone of the node from the host zoneinfo database, e.g. Europe/Berlin
+	Timezone string `yaml:"timezone,omitempty"`
+	// NTP servers templated into the startup config of the node
+	NTPServers []string `yaml:"ntp-servers,omitempty"`
 	// Certificate Configuration
 	Certificate *CertificateConfig `yaml:"certificate,omitempty"`
+	// Externally issued TLS material
+	TLS *TLSConfig `yaml:"tls,omitempty"`
+	// Serial console access configuration
+	Console *ConsoleConfig `yaml:"console,omitempty"`
+	// StartupWait is the readiness condition the node must meet before the nodes depending on it are created
+	StartupWait *StartupWait `yaml:"startup-wait,omitempty"`
+	// BootLog captures the container logs to the boot log file in the lab directory during the deployment
+	BootLog *bool `yaml:"boot-log,omitempty"`
+	// HealthcheckTimeout is the maximum time the nodes waiting for the node to become healthy or to exit wait for it
+	HealthcheckTimeout *time.Duration `yaml:"healthcheck-timeout,omitempty"`
+	// Healthcheck is the health check of the node container overriding the HEALTHCHECK of the image
+	Healthcheck *HealthcheckConfig `yaml:"healthcheck,omitempty"`
+	// ImageBuild builds the image of the node from a Dockerfile on deploy
+	ImageBuild *ImageBuild `yaml:"image-build,omitempty"`
 }
 
 // Interface compliance.
-var _ yaml.Unmarshaler = &NodeDefinition{}
+var (
+	_ yaml.Unmarshaler = &NodeDefinition{}
+	_ yaml.Marshaler   = &NodeDefinition{}
+)
 
 // UnmarshalYAML is a custom unmarshaller for NodeDefinition type that allows to map old attributes to new ones.
 func (n *NodeDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -86,8 +131,9 @@ func (n *NodeDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error
 
 	type NodeDefinitionWithDeprecatedFields struct {
 		NodeDefinitionAlias `yaml:",inline"`
-		DeprecatedMgmtIPv4  string `yaml:"mgmt_ipv4,omitempty"`
-		DeprecatedMgmtIPv6  string `yaml:"mgmt_ipv6,omitempty"`
+		DeprecatedMgmtIPv4  string  `yaml:"mgmt_ipv4,omitempty"`
+		DeprecatedMgmtIPv6  string  `yaml:"mgmt_ipv6,omitempty"`
+		ExecEntries         []*Exec `yaml:"exec,omitempty"`
 	}
 
 	nd := &NodeDefinitionWithDeprecatedFields{}
@@ -108,11 +154,32 @@ func (n *NodeDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error
 		nd.MgmtIPv6 = nd.DeprecatedMgmtIPv6
 	}
 
+	if nd.ExecEntries != nil {
+		nd.Exec, nd.ExecPhases = splitExecEntries(nd.ExecEntries)
+	}
+
 	*n = (NodeDefinition)(nd.NodeDefinitionAlias)
 
 	return nil
 }
 
+// MarshalYAML is a custom marshaller for NodeDefinition type that puts the exec commands of all phases
+// back under the exec key.
+func (n *NodeDefinition) MarshalYAML() (interface{}, error) {
+	// define an alias type to avoid recursion during marshalling
+	type NodeDefinitionAlias NodeDefinition
+
+	type NodeDefinitionWithExec struct {
+		NodeDefinitionAlias `yaml:",inline"`
+		ExecEntries         []*Exec `yaml:"exec,omitempty"`
+	}
+
+	return &NodeDefinitionWithExec{
+		NodeDefinitionAlias: (NodeDefinitionAlias)(*n),
+		ExecEntries:         joinExecEntries(n.Exec, n.ExecPhases),
+	}, nil
+}
+
 func (n *NodeDefinition) GetKind() string {
 	if n == nil {
 		return ""
@@ -197,6 +264,13 @@ func (n *NodeDefinition) GetLicense() string {
 	return n.License
 }
 
+func (n *NodeDefinition) GetLicensePool() LicensePool {
+	if n == nil {
+		return nil
+	}
+	return n.LicensePool
+}
+
 func (n *NodeDefinition) GetPostion() string {
 	if n == nil {
 		return ""
@@ -253,6 +327,13 @@ func (n *NodeDefinition) GetPublish() []string {
 	return n.Publish
 }
 
+func (n *NodeDefinition) GetExpose() []string {
+	if n == nil {
+		return nil
+	}
+	return n.Expose
+}
+
 func (n *NodeDefinition) GetEnv() map[string]string {
 	if n == nil {
 		return nil
@@ -330,6 +411,55 @@ func (n *NodeDefinition) GetNodeMemory() string {
 	return n.Memory
 }
 
+func (n *NodeDefinition) GetNodeMemorySwap() string {
+	if n == nil {
+		return ""
+	}
+	return n.MemorySwap
+}
+
+func (n *NodeDefinition) GetNodeCgroupParent() string {
+	if n == nil {
+		return ""
+	}
+	return n.CgroupParent
+}
+
+func (n *NodeDefinition) GetNodePlatform() string {
+	if n == nil {
+		return ""
+	}
+	return n.Platform
+}
+
+func (n *NodeDefinition) GetOomKillDisable() *bool {
+	if n == nil {
+		return nil
+	}
+	return n.OomKillDisable
+}
+
+func (n *NodeDefinition) GetOomScoreAdj() *int {
+	if n == nil {
+		return nil
+	}
+	return n.OomScoreAdj
+}
+
+func (n *NodeDefinition) GetInit() *bool {
+	if n == nil {
+		return nil
+	}
+	return n.Init
+}
+
+func (n *NodeDefinition) GetEnabled() *bool {
+	if n == nil {
+		return nil
+	}
+	return n.Enabled
+}
+
 func (n *NodeDefinition) GetExec() []string {
 	if n == nil {
 		return nil
@@ -337,6 +467,13 @@ func (n *NodeDefinition) GetExec() []string {
 	return n.Exec
 }
 
+func (n *NodeDefinition) GetExecPhases() []*Exec {
+	if n == nil {
+		return nil
+	}
+	return n.ExecPhases
+}
+
 func (n *NodeDefinition) GetSysctls() map[string]string {
 	if n == nil || n.Sysctls == nil {
 		return map[string]string{}
@@ -359,9 +496,9 @@ func (n *NodeDefinition) GetSANs() []string {
 	return n.SANs
 }
 
-func (n *NodeDefinition) GetWaitFor() []string {
+func (n *NodeDefinition) GetWaitFor() []WaitFor {
 	if n == nil {
-		return []string{}
+		return nil
 	}
 	return n.WaitFor
 }
@@ -373,6 +510,20 @@ func (n *NodeDefinition) GetDns() *DNSConfig {
 	return n.DNS
 }
 
+func (n *NodeDefinition) GetTimezone() string {
+	if n == nil {
+		return ""
+	}
+	return n.Timezone
+}
+
+func (n *NodeDefinition) GetNTPServers() []string {
+	if n == nil {
+		return nil
+	}
+	return n.NTPServers
+}
+
 func (n *NodeDefinition) GetCertificateConfig() *CertificateConfig {
 	if n == nil {
 		return nil
@@ -380,6 +531,62 @@ func (n *NodeDefinition) GetCertificateConfig() *CertificateConfig {
 	return n.Certificate
 }
 
+func (n *NodeDefinition) GetTLSConfig() *TLSConfig {
+	if n == nil {
+		return nil
+	}
+	return n.TLS
+}
+
+func (n *NodeDefinition) GetConsoleConfig() *ConsoleConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Console
+}
+
+func (n *NodeDefinition) GetStartupWait() *StartupWait {
+	if n == nil {
+		return nil
+	}
+	return n.StartupWait
+}
+
+func (n *NodeDefinition) GetBootLog() *bool {
+	if n == nil {
+		return nil
+	}
+	return n.BootLog
+}
+
+func (n *NodeDefinition) GetHealthcheckTimeout() *time.Duration {
+	if n == nil {
+		return nil
+	}
+	return n.HealthcheckTimeout
+}
+
+func (n *NodeDefinition) GetHealthcheck() *HealthcheckConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Healthcheck
+}
+
+func (n *NodeDefinition) GetImageArchMap() map[string]string {
+	if n == nil {
+		return nil
+	}
+	return n.ImageArchMap
+}
+
+func (n *NodeDefinition) GetImageBuild() *ImageBuild {
+	if n == nil {
+		return nil
+	}
+	return n.ImageBuild
+}
+
 // ImportEnvs imports all environment variales defined in the shell
 // if __IMPORT_ENVS is set to true.
 func (n *NodeDefinition) ImportEnvs() {
@@ -409,3 +616,67 @@ func (n *NodeDefinition) ImportEnvs() {
 		n.Env[kv[0]] = kv[1]
 	}
 }
+
+// NodeDefinitionFields returns the names of the node definition fields as they are written in the topology file.
+func NodeDefinitionFields() []string {
+	t := reflect.TypeOf(NodeDefinition{})
+
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := yamlFieldName(t.Field(i)); name != "" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// SetFields returns the names of the fields set in the node definition as they are written in the topology file.
+// The kind of the node is not reported.
+func (n *NodeDefinition) SetFields() []string {
+	if n == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(*n)
+
+	var fields []string
+	for i := 0; i < v.NumField(); i++ {
+		name := yamlFieldName(v.Type().Field(i))
+		if name == "" || name == "kind" || v.Field(i).IsZero() {
+			continue
+		}
+
+		fields = append(fields, name)
+	}
+
+	return fields
+}
+
+// FieldValue returns the value of the string field of the node definition with the given topology file name.
+// An empty string is returned for the fields which are not set or are not strings.
+func (n *NodeDefinition) FieldValue(field string) string {
+	if n == nil {
+		return ""
+	}
+
+	v := reflect.ValueOf(*n)
+	for i := 0; i < v.NumField(); i++ {
+		if yamlFieldName(v.Type().Field(i)) == field && v.Field(i).Kind() == reflect.String {
+			return v.Field(i).String()
+		}
+	}
+
+	return ""
+}
+
+// yamlFieldName returns the name of the struct field in the yaml document,
+// or an empty string for the fields not read from the yaml document.
+func yamlFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNodeDefinitionSetFields(t *testing.T) {
+	tests := map[string]struct {
+		def  *NodeDefinition
+		want []string
+	}{
+		"nil":   {},
+		"empty": {def: &NodeDefinition{}},
+		"kind only": {
+			def: &NodeDefinition{Kind: "srl"},
+		},
+		"set fields": {
+			def: &NodeDefinition{
+				Kind:        "srl",
+				License:     "license.key",
+				NetworkMode: "host",
+				Env:         map[string]string{"A": "B"},
+				Exec:        []string{"ip link"},
+				Enabled:     new(bool),
+			},
+			want: []string{"license", "env", "network-mode", "enabled"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tt.want, tt.def.SetFields()); d != "" {
+				t.Errorf("set fields diff (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestNodeDefinitionFields(t *testing.T) {
+	fields := map[string]bool{}
+	for _, f := range NodeDefinitionFields() {
+		fields[f] = true
+	}
+
+	for _, f := range []string{"kind", "license", "network-mode", "startup-config", "boot-log"} {
+		if !fields[f] {
+			t.Errorf("field %q is missing", f)
+		}
+	}
+
+	for _, f := range []string{"", "-"} {
+		if fields[f] {
+			t.Errorf("unexpected field %q", f)
+		}
+	}
+}
+
+func TestNodeDefinitionFieldValue(t *testing.T) {
+	def := &NodeDefinition{NetworkMode: "host", Env: map[string]string{"A": "B"}}
+
+	for field, want := range map[string]string{
+		"network-mode": "host",
+		"license":      "",
+		"env":          "",
+		"unknown":      "",
+	} {
+		if got := def.FieldValue(field); got != want {
+			t.Errorf("FieldValue(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
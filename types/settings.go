@@ -2,9 +2,68 @@ package types
 
 import "time"
 
+// DefaultStartupConfigBackups is the default number of the previous startup config renderings kept in the node directory.
+const DefaultStartupConfigBackups = 3
+
 // Settings is the structure for global containerlab settings.
 type Settings struct {
 	CertificateAuthority *CertificateAuthority `yaml:"certificate-authority"`
+	// StartupConfigBackups is the number of the previous startup config renderings
+	// kept as numbered backups when the config is regenerated. 0 disables the backups.
+	StartupConfigBackups *int `yaml:"startup-config-backups,omitempty"`
+	// RuntimeSocket is the path or the URI of the container runtime API socket
+	// overriding the runtime default one.
+	RuntimeSocket string `yaml:"runtime-socket,omitempty"`
+	// RegistryTLS is the client TLS material used to pull the images from the mTLS-protected registries
+	// by the registry host, e.g. registry.example.com:5000.
+	RegistryTLS map[string]*TLSConfig `yaml:"registry-tls,omitempty"`
+	// Timeout is the timeout of the requests to the external resources, e.g. the container runtime,
+	// used when the timeout is not set with the --timeout flag.
+	Timeout *time.Duration `yaml:"timeout,omitempty"`
+	// LinkHosts enables the /etc/hosts entries of the nodes named <node>-<interface>
+	// pointing to the ip addresses of the link endpoints.
+	LinkHosts bool `yaml:"link-hosts,omitempty"`
+}
+
+// GetLinkHosts returns true if the /etc/hosts entries of the link endpoint addresses are enabled.
+func (s *Settings) GetLinkHosts() bool {
+	return s != nil && s.LinkHosts
+}
+
+// GetTimeout returns the timeout set in the settings, 0 if not set.
+func (s *Settings) GetTimeout() time.Duration {
+	if s == nil || s.Timeout == nil {
+		return 0
+	}
+
+	return *s.Timeout
+}
+
+// GetRegistryTLS returns the client TLS material of the registries set in the settings.
+func (s *Settings) GetRegistryTLS() map[string]*TLSConfig {
+	if s == nil {
+		return nil
+	}
+
+	return s.RegistryTLS
+}
+
+// GetRuntimeSocket returns the container runtime API socket set in the settings.
+func (s *Settings) GetRuntimeSocket() string {
+	if s == nil {
+		return ""
+	}
+
+	return s.RuntimeSocket
+}
+
+// GetStartupConfigBackups returns the number of the startup config backups to keep.
+func (s *Settings) GetStartupConfigBackups() int {
+	if s == nil || s.StartupConfigBackups == nil || *s.StartupConfigBackups < 0 {
+		return DefaultStartupConfigBackups
+	}
+
+	return *s.StartupConfigBackups
 }
 
 // CertificateAuthority is the structure for global containerlab certificate authority settings.
@@ -0,0 +1,68 @@
+package types
+
+// DefaultSSHMaxKeys is the maximum number of the public keys added to the authorized keys of the nodes
+// when the limit is not set in the ssh config.
+const DefaultSSHMaxKeys = 32
+
+// SSHConfig controls the sources of the public keys added to the authorized keys of the nodes.
+type SSHConfig struct {
+	// IncludeHostKeys adds the ~/.ssh/*.pub and ~/.ssh/authorized_keys keys of the invoking user.
+	IncludeHostKeys *bool `yaml:"include-host-keys,omitempty"`
+	// IncludeAgentKeys adds the keys of the ssh-agent referenced by SSH_AUTH_SOCK.
+	IncludeAgentKeys *bool `yaml:"include-agent-keys,omitempty"`
+	// KeyFiles are the public key files added regardless of the IncludeHostKeys value.
+	KeyFiles []string `yaml:"key-files,omitempty"`
+	// AuthorizedKeysFile is the authorized keys file added regardless of the IncludeHostKeys value.
+	AuthorizedKeysFile string `yaml:"authorized-keys-file,omitempty"`
+	// KeyComments are the glob patterns of the key comments, when set only the keys
+	// with the comment matching any of the patterns are added.
+	KeyComments []string `yaml:"key-comments,omitempty"`
+	// MaxKeys is the maximum number of the added keys, the keys past it are dropped with a warning.
+	MaxKeys *int `yaml:"max-keys,omitempty"`
+}
+
+// GetIncludeHostKeys returns true if the host keys of the invoking user are added, which is the default.
+func (s *SSHConfig) GetIncludeHostKeys() bool {
+	return s == nil || s.IncludeHostKeys == nil || *s.IncludeHostKeys
+}
+
+// GetIncludeAgentKeys returns true if the ssh-agent keys are added, which is the default.
+func (s *SSHConfig) GetIncludeAgentKeys() bool {
+	return s == nil || s.IncludeAgentKeys == nil || *s.IncludeAgentKeys
+}
+
+// GetKeyFiles returns the public key files set in the ssh config.
+func (s *SSHConfig) GetKeyFiles() []string {
+	if s == nil {
+		return nil
+	}
+
+	return s.KeyFiles
+}
+
+// GetAuthorizedKeysFile returns the authorized keys file set in the ssh config.
+func (s *SSHConfig) GetAuthorizedKeysFile() string {
+	if s == nil {
+		return ""
+	}
+
+	return s.AuthorizedKeysFile
+}
+
+// GetKeyComments returns the key comment patterns set in the ssh config.
+func (s *SSHConfig) GetKeyComments() []string {
+	if s == nil {
+		return nil
+	}
+
+	return s.KeyComments
+}
+
+// GetMaxKeys returns the maximum number of the added keys, 0 means no limit.
+func (s *SSHConfig) GetMaxKeys() int {
+	if s == nil || s.MaxKeys == nil || *s.MaxKeys < 0 {
+		return DefaultSSHMaxKeys
+	}
+
+	return *s.MaxKeys
+}
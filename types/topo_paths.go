@@ -13,6 +13,14 @@ import (
 const (
 	ansibleInventoryFileName  = "ansible-inventory.yml"
 	topologyExportDatFileName = "topology-data.json"
+	topologyExportMsgpackName = "topology-data.msgpack"
+	deployLogFileName         = "deploy.log"
+	labMetadataFileName       = "lab-metadata.json"
+	deployReportFileName      = "deploy-report.json"
+	labEnvFileName            = ".env"
+	resolvedTopologyFileName  = "topology.resolved.yml"
+	originalTopologyFileName  = "topology.original.yml"
+	interfacesFileName        = "interfaces.json"
 	authzKeysFileName         = "authorized_keys"
 	tlsDir                    = ".tls"
 	caDir                     = "ca"
@@ -151,11 +159,59 @@ func (t *TopoPaths) NodeDir(nodeName string) string {
 	return path.Join(t.labDir, nodeName)
 }
 
+// NodeInterfacesFileAbsPath returns the absolute path to the file listing the interfaces of the node.
+func (t *TopoPaths) NodeInterfacesFileAbsPath(nodeName string) string {
+	return path.Join(t.NodeDir(nodeName), interfacesFileName)
+}
+
 // TopoExportFile returns the path for the topology-export file.
 func (t *TopoPaths) TopoExportFile() string {
 	return path.Join(t.labDir, topologyExportDatFileName)
 }
 
+// TopoExportMsgpackFile returns the path for the topology-export file in the msgpack format.
+func (t *TopoPaths) TopoExportMsgpackFile() string {
+	return path.Join(t.labDir, topologyExportMsgpackName)
+}
+
+// DeployLogFileAbsPath returns the absolute path to the file the deploy logs are written to
+// while the live deployment status is displayed.
+func (t *TopoPaths) DeployLogFileAbsPath() string {
+	return path.Join(t.labDir, deployLogFileName)
+}
+
+// LabMetadataFileAbsPath returns the absolute path to the lab metadata file.
+func (t *TopoPaths) LabMetadataFileAbsPath() string {
+	return LabMetadataFilePath(t.labDir)
+}
+
+// LabMetadataFilePath returns the path to the lab metadata file in the lab directory.
+func LabMetadataFilePath(labDir string) string {
+	return path.Join(labDir, labMetadataFileName)
+}
+
+// LabEnvFileAbsPath returns the absolute path to the file with the lab environment variables.
+func (t *TopoPaths) LabEnvFileAbsPath() string {
+	return path.Join(t.labDir, labEnvFileName)
+}
+
+// DeployReportFileAbsPath returns the absolute path to the deploy report file.
+func (t *TopoPaths) DeployReportFileAbsPath() string {
+	return path.Join(t.labDir, deployReportFileName)
+}
+
+// ResolvedTopologyFileAbsPath returns the absolute path to the copy of the resolved topology
+// the lab was deployed from.
+func (t *TopoPaths) ResolvedTopologyFileAbsPath() string {
+	return path.Join(t.labDir, resolvedTopologyFileName)
+}
+
+// OriginalTopologyFileAbsPath returns the absolute path to the copy of the topology file
+// the lab was deployed from.
+func (t *TopoPaths) OriginalTopologyFileAbsPath() string {
+	return path.Join(t.labDir, originalTopologyFileName)
+}
+
 // AnsibleInventoryFileAbsPath returns the absolute path to the ansible-inventory file.
 func (t *TopoPaths) AnsibleInventoryFileAbsPath() string {
 	return path.Join(t.labDir, ansibleInventoryFileName)
@@ -208,6 +264,19 @@ func (t *TopoPaths) TopologyBakFileAbsPath() string {
 	return path.Join(t.TopologyFileDir(), backupFilePrefix+t.TopologyFilenameBase()+backupFileSuffix)
 }
 
+// BackupFilePath returns the path of the n-th numbered backup of the file p.
+// The backup is a hidden file in the same directory, e.g. `.config.json.bak.1` for `config.json`.
+func BackupFilePath(p string, n int) string {
+	return filepath.Join(filepath.Dir(p),
+		fmt.Sprintf("%s%s%s.%d", backupFilePrefix, filepath.Base(p), backupFileSuffix, n))
+}
+
+// BackupFileGlob returns the glob pattern matching the numbered backups of the file p.
+func BackupFileGlob(p string) string {
+	return filepath.Join(filepath.Dir(p),
+		fmt.Sprintf("%s%s%s.*", backupFilePrefix, filepath.Base(p), backupFileSuffix))
+}
+
 // TopologyFileDir returns the abs path to the topology file directory.
 func (t *TopoPaths) TopologyFileDir() string {
 	return filepath.Dir(t.topoFile)
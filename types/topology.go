@@ -1,6 +1,9 @@
 package types
 
 import (
+	"runtime"
+	"time"
+
 	"github.com/docker/go-connections/nat"
 	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/utils"
@@ -11,7 +14,16 @@ type Topology struct {
 	Defaults *NodeDefinition            `yaml:"defaults,omitempty"`
 	Kinds    map[string]*NodeDefinition `yaml:"kinds,omitempty"`
 	Nodes    map[string]*NodeDefinition `yaml:"nodes,omitempty"`
-	Links    []*links.LinkDefinition    `yaml:"links,omitempty"`
+	// Groups is the configuration of the node groups keyed by the group name.
+	Groups map[string]*GroupDefinition `yaml:"groups,omitempty"`
+	Links  []*links.LinkDefinition     `yaml:"links,omitempty"`
+}
+
+// GroupDefinition is the configuration of the nodes sharing the group.
+type GroupDefinition struct {
+	// MaxWorkers limits the number of the group nodes deployed concurrently.
+	// Zero means the group nodes are limited by the global number of workers only.
+	MaxWorkers uint `yaml:"max-workers,omitempty"`
 }
 
 func NewTopology() *Topology {
@@ -47,6 +59,19 @@ func (t *Topology) GetKinds() map[string]*NodeDefinition {
 	return t.Kinds
 }
 
+// GetGroupsMaxWorkers returns the max-workers limits of the groups which have it set, keyed by the group name.
+func (t *Topology) GetGroupsMaxWorkers() map[string]uint {
+	limits := map[string]uint{}
+
+	for name, g := range t.Groups {
+		if g != nil && g.MaxWorkers > 0 {
+			limits[name] = g.MaxWorkers
+		}
+	}
+
+	return limits
+}
+
 func (t *Topology) GetNodeKind(name string) string {
 	if ndef, ok := t.Nodes[name]; ok {
 		if v := ndef.GetKind(); v != "" {
@@ -151,6 +176,26 @@ func (t *Topology) GetNodePublish(name string) []string {
 	return nil
 }
 
+// GetNodeExpose returns the services the node exposes on the host. The services set for the node replace
+// the services set for its kind, which replace the defaults, and an empty list disables the exposure.
+// fromDefaults is true when the services are inherited from the defaults.
+func (t *Topology) GetNodeExpose(name string) (services []string, fromDefaults bool) {
+	ndef, ok := t.Nodes[name]
+	if !ok {
+		return nil, false
+	}
+
+	if ndef.GetExpose() != nil {
+		return ndef.GetExpose(), false
+	}
+
+	if kdef := t.GetKind(t.GetNodeKind(name)); kdef.GetExpose() != nil {
+		return kdef.GetExpose(), false
+	}
+
+	return t.GetDefaults().GetExpose(), true
+}
+
 func (t *Topology) GetNodeLabels(name string) map[string]string {
 	if ndef, ok := t.Nodes[name]; ok {
 		return utils.MergeStringMaps(t.Defaults.GetLabels(),
@@ -256,16 +301,43 @@ func (t *Topology) GetNodeLicense(name string) string {
 	return t.GetDefaults().GetLicense()
 }
 
+// GetNodeImage returns the image of the given node. The image-arch-map entry of the architecture
+// of the node platform, or of the host when the platform is not set, is selected over the plain image
+// set at the same level, while the images of the higher levels override the lower level ones.
 func (t *Topology) GetNodeImage(name string) string {
+	levels := []*NodeDefinition{t.GetDefaults()}
 	if ndef, ok := t.Nodes[name]; ok {
-		if v := ndef.GetImage(); v != "" {
-			return v
+		levels = []*NodeDefinition{ndef, t.GetKind(t.GetNodeKind(name)), t.GetDefaults()}
+	}
+
+	archs := imageArchKeys(t.GetNodePlatform(name))
+
+	for _, l := range levels {
+		for _, a := range archs {
+			if v := l.GetImageArchMap()[a]; v != "" {
+				return v
+			}
 		}
-		if v := t.GetKind(t.GetNodeKind(name)).GetImage(); v != "" {
+		if v := l.GetImage(); v != "" {
 			return v
 		}
 	}
-	return t.GetDefaults().GetImage()
+	return ""
+}
+
+// imageArchKeys returns the image-arch-map keys matching the platform, from the most specific one,
+// i.e. arch/variant and arch. The host architecture is used when the platform is not set or invalid.
+func imageArchKeys(platform string) []string {
+	p, err := utils.ParsePlatform(platform)
+	if err != nil {
+		return []string{runtime.GOARCH}
+	}
+
+	if p.Variant != "" {
+		return []string{p.Architecture + "/" + p.Variant, p.Architecture}
+	}
+
+	return []string{p.Architecture}
 }
 
 func (t *Topology) GetNodeImagePullPolicy(name string) PullPolicyValue {
@@ -351,6 +423,22 @@ func (t *Topology) GetNodeExec(name string) []string {
 	return nil
 }
 
+// GetNodeExecPhases returns the exec commands of the node that run in the phases other than the default one.
+func (t *Topology) GetNodeExecPhases(name string) []*Exec {
+	if ndef, ok := t.Nodes[name]; ok {
+		d := t.GetDefaults().GetExecPhases()
+		k := t.GetKind(t.GetNodeKind(name)).GetExecPhases()
+		n := ndef.GetExecPhases()
+
+		var execs []*Exec
+		execs = append(execs, d...)
+		execs = append(execs, k...)
+
+		return append(execs, n...)
+	}
+	return nil
+}
+
 func (t *Topology) GetNodeUser(name string) string {
 	if ndef, ok := t.Nodes[name]; ok {
 		if v := ndef.GetUser(); v != "" {
@@ -447,6 +535,127 @@ func (t *Topology) GetNodeMemory(name string) string {
 	return t.GetDefaults().GetNodeMemory()
 }
 
+func (t *Topology) GetNodeMemorySwap(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetNodeMemorySwap(); v != "" {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetNodeMemorySwap(); v != "" {
+			return v
+		}
+	}
+	return t.GetDefaults().GetNodeMemorySwap()
+}
+
+func (t *Topology) GetNodeOomKillDisable(name string) bool {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetOomKillDisable(); v != nil {
+			return *v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetOomKillDisable(); v != nil {
+			return *v
+		}
+	}
+	if v := t.GetDefaults().GetOomKillDisable(); v != nil {
+		return *v
+	}
+	return false
+}
+
+func (t *Topology) GetNodeOomScoreAdj(name string) *int {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetOomScoreAdj(); v != nil {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetOomScoreAdj(); v != nil {
+			return v
+		}
+	}
+	return t.GetDefaults().GetOomScoreAdj()
+}
+
+func (t *Topology) GetNodeInit(name string) *bool {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetInit(); v != nil {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetInit(); v != nil {
+			return v
+		}
+	}
+	return t.GetDefaults().GetInit()
+}
+
+// GetNodeBootLog returns true if the container logs of the node are captured to the boot log file.
+func (t *Topology) GetNodeBootLog(name string) bool {
+	if v := t.Nodes[name].GetBootLog(); v != nil {
+		return *v
+	}
+	if v := t.GetKind(t.GetNodeKind(name)).GetBootLog(); v != nil {
+		return *v
+	}
+	if v := t.GetDefaults().GetBootLog(); v != nil {
+		return *v
+	}
+	return false
+}
+
+// GetNodeHealthcheckTimeout returns the maximum time the nodes waiting for the given node
+// to become healthy wait for it, zero if not set.
+func (t *Topology) GetNodeHealthcheckTimeout(name string) time.Duration {
+	if v := t.Nodes[name].GetHealthcheckTimeout(); v != nil {
+		return *v
+	}
+	if v := t.GetKind(t.GetNodeKind(name)).GetHealthcheckTimeout(); v != nil {
+		return *v
+	}
+	if v := t.GetDefaults().GetHealthcheckTimeout(); v != nil {
+		return *v
+	}
+	return 0
+}
+
+func (t *Topology) GetNodeCgroupParent(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetNodeCgroupParent(); v != "" {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetNodeCgroupParent(); v != "" {
+			return v
+		}
+	}
+	return t.GetDefaults().GetNodeCgroupParent()
+}
+
+func (t *Topology) GetNodePlatform(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetNodePlatform(); v != "" {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetNodePlatform(); v != "" {
+			return v
+		}
+	}
+	return t.GetDefaults().GetNodePlatform()
+}
+
+// GetNodeEnabled returns true if the node is enabled and thus should be deployed.
+// Nodes are enabled unless disabled on the node, kind or defaults level.
+func (t *Topology) GetNodeEnabled(name string) bool {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetEnabled(); v != nil {
+			return *v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetEnabled(); v != nil {
+			return *v
+		}
+	}
+	if v := t.GetDefaults().GetEnabled(); v != nil {
+		return *v
+	}
+	return true
+}
+
 // GetSysCtl return the Sysctl configuration for the given node.
 func (t *Topology) GetSysCtl(name string) map[string]string {
 	if ndef, ok := t.Nodes[name]; ok {
@@ -484,9 +693,9 @@ func (t *Topology) GetNodeExtras(name string) *Extras {
 }
 
 // GetWaitFor return the wait-for configuration for the given node.
-func (t *Topology) GetWaitFor(name string) []string {
+func (t *Topology) GetWaitFor(name string) []WaitFor {
 	if ndef, ok := t.Nodes[name]; ok {
-		return utils.MergeStringSlices(
+		return MergeWaitFor(
 			t.GetKind(t.GetNodeKind(name)).GetWaitFor(),
 			ndef.GetWaitFor())
 	}
@@ -527,6 +736,115 @@ func (t *Topology) GetNodeDns(name string) *DNSConfig {
 	return defaultDNS
 }
 
+// GetNodeTimezone returns the time zone of the given node.
+func (t *Topology) GetNodeTimezone(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetTimezone(); v != "" {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetTimezone(); v != "" {
+			return v
+		}
+	}
+	return t.GetDefaults().GetTimezone()
+}
+
+// GetNodeNTPServers returns the NTP servers of the given node.
+// The list set on the node level replaces the kind and defaults lists.
+func (t *Topology) GetNodeNTPServers(name string) []string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetNTPServers(); len(v) > 0 {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetNTPServers(); len(v) > 0 {
+			return v
+		}
+	}
+	return t.GetDefaults().GetNTPServers()
+}
+
+// GetNodeConsoleConfig returns the serial console configuration for the given node.
+func (t *Topology) GetNodeConsoleConfig(name string) *ConsoleConfig {
+	// console is not exposed by default and the host port is picked automatically
+	cc := &ConsoleConfig{
+		Expose:   utils.BoolPointer(false),
+		HostPort: ConsoleHostPortAuto,
+	}
+
+	cc.Merge(
+		t.GetDefaults().GetConsoleConfig()).Merge(
+		t.GetKind(t.GetNodeKind(name)).GetConsoleConfig()).Merge(
+		t.Nodes[name].GetConsoleConfig())
+
+	return cc
+}
+
+// GetNodeHealthcheck returns the health check of the given node merged with the defaults < kinds < nodes precedence
+// or nil if the health check is not defined.
+func (t *Topology) GetNodeHealthcheck(name string) *HealthcheckConfig {
+	hc := &HealthcheckConfig{}
+
+	hc.Merge(
+		t.GetDefaults().GetHealthcheck()).Merge(
+		t.GetKind(t.GetNodeKind(name)).GetHealthcheck()).Merge(
+		t.Nodes[name].GetHealthcheck())
+
+	if !hc.IsSet() {
+		return nil
+	}
+
+	return hc
+}
+
+// GetNodeStartupWait returns the startup-wait condition of the given node
+// or nil if the condition is not defined.
+func (t *Topology) GetNodeStartupWait(name string) *StartupWait {
+	sw := &StartupWait{
+		Timeout: DefaultStartupWaitTimeout,
+	}
+
+	sw.Merge(
+		t.GetDefaults().GetStartupWait()).Merge(
+		t.GetKind(t.GetNodeKind(name)).GetStartupWait()).Merge(
+		t.Nodes[name].GetStartupWait())
+
+	if !sw.IsSet() {
+		return nil
+	}
+
+	return sw
+}
+
+// GetNodeTLSConfig returns the paths to the externally issued TLS material of the given node
+// or nil if the material is not provided.
+func (t *Topology) GetNodeTLSConfig(name string) *TLSConfig {
+	tc := (&TLSConfig{}).Merge(
+		t.GetDefaults().GetTLSConfig()).Merge(
+		t.GetKind(t.GetNodeKind(name)).GetTLSConfig()).Merge(
+		t.Nodes[name].GetTLSConfig())
+
+	if !tc.IsSet() {
+		return nil
+	}
+
+	return tc
+}
+
+// GetNodeImageBuild returns the build of the image of the given node merged from the defaults,
+// kind and node levels, or nil if the image of the node is not built.
+func (t *Topology) GetNodeImageBuild(name string) *ImageBuild {
+	b := (&ImageBuild{}).Merge(
+		t.GetDefaults().GetImageBuild()).Merge(
+		t.GetKind(t.GetNodeKind(name)).GetImageBuild()).Merge(
+		t.Nodes[name].GetImageBuild())
+
+	if !b.IsSet() {
+		return nil
+	}
+
+	return b
+}
+
 // GetCertificateConfig returns the certificate configuration for the given node.
 func (t *Topology) GetCertificateConfig(name string) *CertificateConfig {
 	// default for issuing node certificates is false
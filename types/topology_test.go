@@ -1,7 +1,9 @@
 package types
 
 import (
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/srl-labs/containerlab/utils"
@@ -79,9 +81,13 @@ var topologyTestSet = map[string]struct {
 						"label1": "v1",
 						"label2": "v2",
 					},
-					CPU:        1,
-					Memory:     "1G",
-					AutoRemove: utils.BoolPointer(true),
+					CPU:            1,
+					Memory:         "1G",
+					MemorySwap:     "2G",
+					AutoRemove:     utils.BoolPointer(true),
+					OomKillDisable: utils.BoolPointer(true),
+					OomScoreAdj:    utils.IntPointer(-500),
+					Platform:       "linux/amd64",
 					DNS: &DNSConfig{
 						Servers: []string{"8.8.8.8"},
 						Search:  []string{"bar.com"},
@@ -101,8 +107,9 @@ var topologyTestSet = map[string]struct {
 					Labels: map[string]string{
 						"label2": "notv2",
 					},
-					Memory:     "2G",
-					AutoRemove: utils.BoolPointer(false),
+					Memory:      "2G",
+					AutoRemove:  utils.BoolPointer(false),
+					OomScoreAdj: utils.IntPointer(-900),
 					DNS: &DNSConfig{
 						Servers: []string{"1.1.1.1"},
 						Search:  []string{"foo.com"},
@@ -141,9 +148,13 @@ var topologyTestSet = map[string]struct {
 					"label1": "v1",
 					"label2": "notv2",
 				},
-				CPU:        1,
-				Memory:     "2G",
-				AutoRemove: utils.BoolPointer(false),
+				CPU:            1,
+				Memory:         "2G",
+				MemorySwap:     "2G",
+				AutoRemove:     utils.BoolPointer(false),
+				OomKillDisable: utils.BoolPointer(true),
+				OomScoreAdj:    utils.IntPointer(-900),
+				Platform:       "linux/amd64",
 				DNS: &DNSConfig{
 					Servers: []string{"1.1.1.1"},
 					Search:  []string{"foo.com"},
@@ -288,8 +299,9 @@ var topologyTestSet = map[string]struct {
 					"label1": "v1",
 					"label2": "v2",
 				},
-				CPU:    1,
-				Memory: "1G",
+				CPU:          1,
+				Memory:       "1G",
+				CgroupParent: "clab.slice",
 				DNS: &DNSConfig{
 					Servers: []string{"1.1.1.1"},
 					Search:  []string{"foo.com"},
@@ -329,9 +341,10 @@ var topologyTestSet = map[string]struct {
 					"label1": "v1",
 					"label2": "v2",
 				},
-				CPU:        1,
-				Memory:     "1G",
-				AutoRemove: utils.BoolPointer(false),
+				CPU:          1,
+				Memory:       "1G",
+				CgroupParent: "clab.slice",
+				AutoRemove:   utils.BoolPointer(false),
 				DNS: &DNSConfig{
 					Servers: []string{"1.1.1.1"},
 					Search:  []string{"foo.com"},
@@ -415,6 +428,46 @@ func TestGetNodeImage(t *testing.T) {
 	}
 }
 
+func TestGetNodeImageArchMap(t *testing.T) {
+	host := runtime.GOARCH
+	other := "arm64"
+	if host == other {
+		other = "amd64"
+	}
+
+	topo := &Topology{
+		Kinds: map[string]*NodeDefinition{
+			"srl": {
+				Image:        "srlinux",
+				ImageArchMap: map[string]string{host: "srlinux:" + host, "arm/v7": "srlinux:armv7"},
+			},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"host":     {Kind: "srl"},
+			"platform": {Kind: "srl", Platform: "linux/" + other},
+			"variant":  {Kind: "srl", Platform: "linux/arm/v7"},
+			"override": {Kind: "srl", Image: "srlinux:custom"},
+			"node-map": {Kind: "linux", Image: "alpine", ImageArchMap: map[string]string{other: "alpine:" + other}},
+		},
+	}
+
+	want := map[string]string{
+		"host": "srlinux:" + host,
+		// the plain image is used when no map entry matches
+		"platform": "srlinux",
+		"variant":  "srlinux:armv7",
+		// the image of the node overrides the arch map of the kind
+		"override": "srlinux:custom",
+		"node-map": "alpine",
+	}
+
+	for node, w := range want {
+		if got := topo.GetNodeImage(node); got != w {
+			t.Errorf("image of node %q = %q, want %q", node, got, w)
+		}
+	}
+}
+
 func TestGetNodeLicense(t *testing.T) {
 	for name, item := range topologyTestSet {
 		t.Logf("%q test item", name)
@@ -542,6 +595,257 @@ func TestGetNodeAutoRemove(t *testing.T) {
 	}
 }
 
+func TestGetNodeCgroupParent(t *testing.T) {
+	for name, item := range topologyTestSet {
+		t.Logf("%q test item", name)
+		cgroupParent := item.input.GetNodeCgroupParent("node1")
+		if item.want["node1"].CgroupParent != cgroupParent {
+			t.Errorf("item %q failed", name)
+			t.Errorf("item %q exp %q", name, item.want["node1"].CgroupParent)
+			t.Errorf("item %q got %q", name, cgroupParent)
+			t.Fail()
+		}
+	}
+}
+
+func TestGetNodePlatform(t *testing.T) {
+	for name, item := range topologyTestSet {
+		t.Logf("%q test item", name)
+		platform := item.input.GetNodePlatform("node1")
+		if item.want["node1"].Platform != platform {
+			t.Errorf("item %q failed", name)
+			t.Errorf("item %q exp %q", name, item.want["node1"].Platform)
+			t.Errorf("item %q got %q", name, platform)
+			t.Fail()
+		}
+	}
+}
+
+func TestGetNodeMemorySwap(t *testing.T) {
+	for name, item := range topologyTestSet {
+		t.Logf("%q test item", name)
+		memorySwap := item.input.GetNodeMemorySwap("node1")
+		if item.want["node1"].MemorySwap != memorySwap {
+			t.Errorf("item %q failed", name)
+			t.Errorf("item %q exp %q", name, item.want["node1"].MemorySwap)
+			t.Errorf("item %q got %q", name, memorySwap)
+		}
+	}
+}
+
+func TestGetNodeOomSettings(t *testing.T) {
+	for name, item := range topologyTestSet {
+		t.Logf("%q test item", name)
+		oomKillDisable := item.input.GetNodeOomKillDisable("node1")
+		want := item.want["node1"].GetOomKillDisable()
+		if (want != nil && *want) != oomKillDisable {
+			t.Errorf("item %q failed", name)
+			t.Errorf("item %q exp oom-kill-disable %v", name, want != nil && *want)
+			t.Errorf("item %q got oom-kill-disable %v", name, oomKillDisable)
+		}
+		if d := cmp.Diff(item.want["node1"].OomScoreAdj, item.input.GetNodeOomScoreAdj("node1")); d != "" {
+			t.Errorf("item %q oom-score-adj doesn't match.\nDiff\n%s", name, d)
+		}
+	}
+}
+
+func TestGetNodeEnabled(t *testing.T) {
+	topo := &Topology{
+		Kinds: map[string]*NodeDefinition{
+			"linux": {Enabled: utils.BoolPointer(false)},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "srl"},
+			"node2": {Kind: "srl", Enabled: utils.BoolPointer(false)},
+			"node3": {Kind: "linux"},
+			"node4": {Kind: "linux", Enabled: utils.BoolPointer(true)},
+		},
+	}
+
+	want := map[string]bool{
+		"node1": true,
+		"node2": false,
+		"node3": false,
+		"node4": true,
+	}
+
+	for name, enabled := range want {
+		if got := topo.GetNodeEnabled(name); got != enabled {
+			t.Errorf("node %q: got enabled %v, want %v", name, got, enabled)
+		}
+	}
+}
+
+func TestGetNodeInit(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{Init: utils.BoolPointer(true)},
+		Kinds: map[string]*NodeDefinition{
+			"linux": {Init: utils.BoolPointer(false)},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "srl"},
+			"node2": {Kind: "linux"},
+			"node3": {Kind: "linux", Init: utils.BoolPointer(true)},
+		},
+	}
+
+	want := map[string]bool{
+		"node1": true,
+		"node2": false,
+		"node3": true,
+	}
+
+	for name, init := range want {
+		got := topo.GetNodeInit(name)
+		if got == nil || *got != init {
+			t.Errorf("node %q: got init %v, want %v", name, got, init)
+		}
+	}
+
+	// init is unset when not defined on any level
+	unset := &Topology{Nodes: map[string]*NodeDefinition{"node1": {Kind: "linux"}}}
+	if got := unset.GetNodeInit("node1"); got != nil {
+		t.Errorf("got init %v, want nil", *got)
+	}
+}
+
+func TestGetNodeBootLog(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{BootLog: utils.BoolPointer(true)},
+		Kinds: map[string]*NodeDefinition{
+			"linux": {BootLog: utils.BoolPointer(false)},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "srl"},
+			"node2": {Kind: "linux"},
+			"node3": {Kind: "linux", BootLog: utils.BoolPointer(true)},
+		},
+	}
+
+	want := map[string]bool{
+		"node1": true,
+		"node2": false,
+		"node3": true,
+	}
+
+	for name, bootLog := range want {
+		if got := topo.GetNodeBootLog(name); got != bootLog {
+			t.Errorf("node %q: got boot-log %v, want %v", name, got, bootLog)
+		}
+	}
+
+	// boot log is disabled when not defined on any level
+	unset := &Topology{Nodes: map[string]*NodeDefinition{"node1": {Kind: "linux"}}}
+	if unset.GetNodeBootLog("node1") {
+		t.Error("got boot-log enabled, want disabled")
+	}
+}
+
+func TestGetNodeTimezoneAndNTPServers(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{
+			Timezone:   "Europe/Berlin",
+			NTPServers: []string{"192.0.2.1", "192.0.2.2"},
+		},
+		Kinds: map[string]*NodeDefinition{
+			"linux": {Timezone: "UTC"},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "srl"},
+			"node2": {Kind: "linux"},
+			"node3": {Kind: "linux", Timezone: "America/New_York", NTPServers: []string{"ntp.example.com"}},
+		},
+	}
+
+	wantTZ := map[string]string{
+		"node1": "Europe/Berlin",
+		"node2": "UTC",
+		"node3": "America/New_York",
+	}
+	wantNTP := map[string][]string{
+		"node1": {"192.0.2.1", "192.0.2.2"},
+		"node2": {"192.0.2.1", "192.0.2.2"},
+		"node3": {"ntp.example.com"},
+	}
+
+	for name, tz := range wantTZ {
+		if got := topo.GetNodeTimezone(name); got != tz {
+			t.Errorf("node %q: got timezone %q, want %q", name, got, tz)
+		}
+		if d := cmp.Diff(wantNTP[name], topo.GetNodeNTPServers(name)); d != "" {
+			t.Errorf("node %q: ntp-servers mismatch (-want +got):\n%s", name, d)
+		}
+	}
+
+	unset := &Topology{Nodes: map[string]*NodeDefinition{"node1": {Kind: "linux"}}}
+	if got := unset.GetNodeTimezone("node1"); got != "" {
+		t.Errorf("got timezone %q, want none", got)
+	}
+	if got := unset.GetNodeNTPServers("node1"); got != nil {
+		t.Errorf("got ntp-servers %v, want none", got)
+	}
+}
+
+func TestGetNodeConsoleConfig(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{
+			Console: &ConsoleConfig{HostPort: "5100"},
+		},
+		Kinds: map[string]*NodeDefinition{
+			"vr-sros": {Console: &ConsoleConfig{Expose: utils.BoolPointer(true)}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "linux"},
+			"node2": {Kind: "vr-sros"},
+			"node3": {Kind: "vr-sros", Console: &ConsoleConfig{HostPort: ConsoleHostPortAuto}},
+			"node4": {Kind: "vr-sros", Console: &ConsoleConfig{Expose: utils.BoolPointer(false)}},
+		},
+	}
+
+	want := map[string]*ConsoleConfig{
+		"node1": {Expose: utils.BoolPointer(false), HostPort: "5100"},
+		"node2": {Expose: utils.BoolPointer(true), HostPort: "5100"},
+		"node3": {Expose: utils.BoolPointer(true), HostPort: ConsoleHostPortAuto},
+		"node4": {Expose: utils.BoolPointer(false), HostPort: "5100"},
+	}
+
+	for name, w := range want {
+		if d := cmp.Diff(w, topo.GetNodeConsoleConfig(name)); d != "" {
+			t.Errorf("node %q: console config mismatch (-want +got):\n%s", name, d)
+		}
+	}
+}
+
+func TestGetNodeTLSConfig(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{
+			TLS: &TLSConfig{CA: "ca.pem"},
+		},
+		Kinds: map[string]*NodeDefinition{
+			"srl": {TLS: &TLSConfig{CA: "srl-ca.pem"}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "linux"},
+			"node2": {Kind: "srl", TLS: &TLSConfig{Cert: "node2.pem", Key: "node2.key"}},
+		},
+	}
+
+	want := map[string]*TLSConfig{
+		"node1": {CA: "ca.pem"},
+		"node2": {Cert: "node2.pem", Key: "node2.key", CA: "srl-ca.pem"},
+	}
+
+	for name, w := range want {
+		if d := cmp.Diff(w, topo.GetNodeTLSConfig(name)); d != "" {
+			t.Errorf("node %q: tls config mismatch (-want +got):\n%s", name, d)
+		}
+	}
+
+	if tc := (&Topology{Nodes: map[string]*NodeDefinition{"node1": {}}}).GetNodeTLSConfig("node1"); tc != nil {
+		t.Errorf("got tls config %+v for the node without the tls material", tc)
+	}
+}
+
 func TestGetNodeDNS(t *testing.T) {
 	for name, item := range topologyTestSet {
 		t.Logf("%q test item", name)
@@ -570,3 +874,247 @@ func TestGetNodeCertificateConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGetGroupsMaxWorkers(t *testing.T) {
+	topo := &Topology{
+		Groups: map[string]*GroupDefinition{
+			"spine":  {MaxWorkers: 1},
+			"leaf":   {MaxWorkers: 8},
+			"server": {},
+			"empty":  nil,
+		},
+	}
+
+	want := map[string]uint{"spine": 1, "leaf": 8}
+
+	if d := cmp.Diff(want, topo.GetGroupsMaxWorkers()); d != "" {
+		t.Errorf("groups max-workers mismatch (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff(map[string]uint{}, NewTopology().GetGroupsMaxWorkers()); d != "" {
+		t.Errorf("groups max-workers of the topology without groups mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestGetNodeRuntime(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{Runtime: "docker"},
+		Kinds: map[string]*NodeDefinition{
+			"linux": {Runtime: "podman"},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"l1":   {Kind: "linux"},
+			"l2":   {Kind: "linux", Runtime: "ignite"},
+			"srl1": {Kind: "srl"},
+		},
+	}
+
+	want := map[string]string{
+		"l1":   "podman",
+		"l2":   "ignite",
+		"srl1": "docker",
+	}
+
+	for node, rt := range want {
+		if got := topo.GetNodeRuntime(node); got != rt {
+			t.Errorf("got runtime %q of node %q, want %q", got, node, rt)
+		}
+	}
+}
+
+func TestGetNodeStartupWait(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{StartupWait: &StartupWait{Timeout: 5 * time.Minute}},
+		Kinds: map[string]*NodeDefinition{
+			"srl": {StartupWait: &StartupWait{TCPPort: 57400}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"srl1": {Kind: "srl"},
+			"srl2": {Kind: "srl", StartupWait: &StartupWait{TCPPort: 22, Timeout: time.Minute}},
+			"srl3": {Kind: "srl", StartupWait: &StartupWait{LogLine: "System is ready"}},
+			"l1":   {Kind: "linux"},
+			"l2":   {Kind: "linux", StartupWait: &StartupWait{LogLine: "listening on"}},
+		},
+	}
+
+	want := map[string]*StartupWait{
+		"srl1": {TCPPort: 57400, Timeout: 5 * time.Minute},
+		"srl2": {TCPPort: 22, Timeout: time.Minute},
+		"srl3": {TCPPort: 57400, LogLine: "System is ready", Timeout: 5 * time.Minute},
+		"l2":   {LogLine: "listening on", Timeout: 5 * time.Minute},
+		// the timeout alone doesn't define the condition
+		"l1": nil,
+	}
+
+	for node, sw := range want {
+		if d := cmp.Diff(sw, topo.GetNodeStartupWait(node)); d != "" {
+			t.Errorf("startup-wait of node %q mismatch (-want +got):\n%s", node, d)
+		}
+	}
+
+	if d := cmp.Diff(&StartupWait{TCPPort: 830, Timeout: DefaultStartupWaitTimeout},
+		(&Topology{Nodes: map[string]*NodeDefinition{
+			"n1": {StartupWait: &StartupWait{TCPPort: 830}},
+		}}).GetNodeStartupWait("n1")); d != "" {
+		t.Errorf("startup-wait with the default timeout mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestStartupWaitValidate(t *testing.T) {
+	tests := map[string]struct {
+		sw      *StartupWait
+		wantErr bool
+	}{
+		"nil":            {},
+		"tcp port":       {sw: &StartupWait{TCPPort: 22}},
+		"log line":       {sw: &StartupWait{LogLine: `^ready on port \d+$`}},
+		"invalid regexp": {sw: &StartupWait{LogLine: "ready ("}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.sw.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetNodeHealthcheckTimeout(t *testing.T) {
+	d := func(v time.Duration) *time.Duration { return &v }
+
+	topo := &Topology{
+		Defaults: &NodeDefinition{HealthcheckTimeout: d(2 * time.Minute)},
+		Kinds: map[string]*NodeDefinition{
+			"srl": {HealthcheckTimeout: d(10 * time.Minute)},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"srl1": {Kind: "srl"},
+			"srl2": {Kind: "srl", HealthcheckTimeout: d(time.Minute)},
+			"l1":   {Kind: "linux"},
+		},
+	}
+
+	want := map[string]time.Duration{
+		"srl1": 10 * time.Minute,
+		"srl2": time.Minute,
+		"l1":   2 * time.Minute,
+	}
+
+	for node, w := range want {
+		if got := topo.GetNodeHealthcheckTimeout(node); got != w {
+			t.Errorf("healthcheck-timeout of node %q = %s, want %s", node, got, w)
+		}
+	}
+
+	if got := (&Topology{Nodes: map[string]*NodeDefinition{"n1": {}}}).GetNodeHealthcheckTimeout("n1"); got != 0 {
+		t.Errorf("unset healthcheck-timeout = %s, want 0", got)
+	}
+}
+
+func TestGetNodeImageBuild(t *testing.T) {
+	topo := &Topology{
+		Kinds: map[string]*NodeDefinition{
+			"linux": {ImageBuild: &ImageBuild{Context: "./app", Dockerfile: "Dockerfile.dev"}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"app1": {Kind: "linux", ImageBuild: &ImageBuild{Tag: "lab/app:1"}},
+			"app2": {Kind: "linux", ImageBuild: &ImageBuild{Context: "./app2", Tag: "lab/app:2"}},
+			"srl1": {Kind: "srl"},
+		},
+	}
+
+	want := map[string]*ImageBuild{
+		"app1": {Context: "./app", Dockerfile: "Dockerfile.dev", Tag: "lab/app:1"},
+		"app2": {Context: "./app2", Dockerfile: "Dockerfile.dev", Tag: "lab/app:2"},
+		"srl1": nil,
+	}
+
+	for node, w := range want {
+		if d := cmp.Diff(w, topo.GetNodeImageBuild(node)); d != "" {
+			t.Errorf("image-build of node %q mismatch (-want +got):\n%s", node, d)
+		}
+	}
+
+	// the kind level build is not changed by the nodes
+	if d := cmp.Diff(&ImageBuild{Context: "./app", Dockerfile: "Dockerfile.dev"}, topo.Kinds["linux"].ImageBuild); d != "" {
+		t.Errorf("kind image-build changed (-want +got):\n%s", d)
+	}
+}
+
+func TestGetNodeExpose(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{Expose: []string{"ssh"}},
+		Kinds: map[string]*NodeDefinition{
+			"srl": {Expose: []string{"ssh", "gnmi"}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"client":   {Kind: "linux"},
+			"srl1":     {Kind: "srl"},
+			"srl2":     {Kind: "srl", Expose: []string{"netconf"}},
+			"srl3":     {Kind: "srl", Expose: []string{}},
+			"disabled": {Kind: "linux", Expose: []string{}},
+		},
+	}
+
+	tests := map[string]struct {
+		want         []string
+		fromDefaults bool
+	}{
+		"client": {want: []string{"ssh"}, fromDefaults: true},
+		"srl1":   {want: []string{"ssh", "gnmi"}},
+		// the node services replace the kind services
+		"srl2": {want: []string{"netconf"}},
+		// the empty list disables the services of the kind and the defaults
+		"srl3":     {want: []string{}},
+		"disabled": {want: []string{}},
+	}
+
+	for node, tt := range tests {
+		t.Run(node, func(t *testing.T) {
+			got, fromDefaults := topo.GetNodeExpose(node)
+			if d := cmp.Diff(tt.want, got); d != "" || fromDefaults != tt.fromDefaults {
+				t.Errorf("expose mismatch (-want +got):\n%s, from defaults %v, want %v", d, fromDefaults, tt.fromDefaults)
+			}
+		})
+	}
+}
+
+func TestGetNodeHealthcheck(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{Healthcheck: &HealthcheckConfig{Interval: 10 * time.Second}},
+		Kinds: map[string]*NodeDefinition{
+			"linux": {Healthcheck: &HealthcheckConfig{Test: []string{"CMD", "cat", "/ready"}, Retries: 3}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"l1": {Kind: "linux"},
+			"l2": {Kind: "linux", Healthcheck: &HealthcheckConfig{
+				Test:        []string{"CMD-SHELL", "curl -f http://localhost"},
+				StartPeriod: 30 * time.Second,
+			}},
+			"srl1": {Kind: "srl"},
+		},
+	}
+
+	want := map[string]*HealthcheckConfig{
+		"l1": {Test: []string{"CMD", "cat", "/ready"}, Interval: 10 * time.Second, Retries: 3},
+		"l2": {
+			Test:        []string{"CMD-SHELL", "curl -f http://localhost"},
+			Interval:    10 * time.Second,
+			Retries:     3,
+			StartPeriod: 30 * time.Second,
+		},
+		// the defaults alone tune the health check of the image
+		"srl1": {Interval: 10 * time.Second},
+	}
+
+	for node, hc := range want {
+		if d := cmp.Diff(hc, topo.GetNodeHealthcheck(node)); d != "" {
+			t.Errorf("healthcheck of node %q mismatch (-want +got):\n%s", node, d)
+		}
+	}
+
+	if hc := (&Topology{Nodes: map[string]*NodeDefinition{"n1": {}}}).GetNodeHealthcheck("n1"); hc != nil {
+		t.Errorf("unset healthcheck = %+v, want nil", hc)
+	}
+}
@@ -6,12 +6,14 @@ package types
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/docker/go-connections/nat"
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
 	"github.com/srl-labs/containerlab/utils"
 	"gopkg.in/yaml.v2"
 )
@@ -57,6 +59,8 @@ type MgmtNet struct {
 	IPv6Range      string `yaml:"ipv6-range,omitempty" json:"ipv6-range,omitempty"`
 	MTU            int    `yaml:"mtu,omitempty" json:"mtu,omitempty"`
 	ExternalAccess *bool  `yaml:"external-access,omitempty" json:"external-access,omitempty"`
+	// EnableForwarding enables the IPv4/IPv6 packet forwarding on the host when it is disabled
+	EnableForwarding *bool `yaml:"enable-forwarding,omitempty" json:"enable-forwarding,omitempty"`
 }
 
 // Interface compliance.
@@ -102,6 +106,9 @@ type NodeConfig struct {
 	// containerlab-prefixed unique container name
 	LongName string `json:"longname,omitempty"`
 	Fqdn     string `json:"fqdn,omitempty"`
+	// Hostname is the hostname set in the node, it matches the node name
+	// unless the node name is mapped to a valid hostname with the relaxed node names.
+	Hostname string `json:"hostname,omitempty"`
 	// LabDir is a directory related to the node, it contains config items and/or other persistent state
 	LabDir string `json:"labdir,omitempty"`
 	Index  int    `json:"index,omitempty"`
@@ -111,10 +118,20 @@ type NodeConfig struct {
 	StartupConfig string `json:"startup-config,omitempty"`
 	// optional delay (in seconds) to wait before creating this node
 	StartupDelay uint `json:"startup-delay,omitempty"`
+	// readiness condition the node must meet before the nodes depending on it are created
+	StartupWait *StartupWait `json:"startup-wait,omitempty"`
+	// capture the container logs to the boot log file in the lab directory during the deployment
+	BootLog bool `json:"boot-log,omitempty"`
+	// maximum time the nodes waiting for the node to become healthy wait for it, zero selects the lab default
+	HealthcheckTimeout time.Duration `json:"healthcheck-timeout,omitempty"`
+	// health check of the node container overriding the HEALTHCHECK of the image, nil keeps the image one
+	Healthcheck *HealthcheckConfig `json:"healthcheck,omitempty"`
 	// when set to true will enforce the use of startup-config, even when config is present in the lab directory
 	EnforceStartupConfig bool `json:"enforce-startup-config,omitempty"`
 	// when set to true will prevent creation of a startup-config, for auto-provisioning testing (ZTP)
 	SuppressStartupConfig bool `json:"suppress-startup-config,omitempty"`
+	// number of the previous startup config renderings to keep as numbered backups
+	StartupConfigBackups int `json:"startup-config-backups,omitempty"`
 	// when set to true will auto-remove a stopped/failed container
 	AutoRemove bool `json:"auto-remove,omitempty"`
 	// path to config file that is actually mounted to the container and is a result of templation
@@ -132,8 +149,12 @@ type NodeConfig struct {
 	Entrypoint      string            `json:"entrypoint,omitempty"`
 	Cmd             string            `json:"cmd,omitempty"`
 	// Exec is a list of commands to execute inside the container backing the node.
-	Exec []string          `json:"exec,omitempty"`
-	Env  map[string]string `json:"env,omitempty"`
+	Exec []string `json:"exec,omitempty"`
+	// ExecPhases is a list of commands to execute inside the container in the post-start and post-links phases.
+	ExecPhases []*Exec `json:"exec-phases,omitempty"`
+	// ExecResults collects the results of the exec commands run in the node lifecycle phases
+	ExecResults *exec.ExecCollection `json:"-"`
+	Env         map[string]string    `json:"env,omitempty"`
 	// Bind mounts strings (src:dest:options).
 	Binds []string `json:"binds,omitempty"`
 	// PortBindings define the bindings between the container ports and host ports
@@ -145,6 +166,9 @@ type NodeConfig struct {
 	// NetworkMode defines container networking mode.
 	// If set to `host` the host networking will be used for this node, else bridged network
 	NetworkMode string `json:"networkmode,omitempty"`
+	// NetworkModePhase is the phase of the network namespace owner container of the container:<name> network mode
+	// the node waits for before it is created, the owner container is waited to be running by default.
+	NetworkModePhase string `json:"networkmode-phase,omitempty"`
 	// MgmtNet is the name of the docker network this node is connected to with its first interface
 	MgmtNet string `json:"mgmt-net,omitempty"`
 	// MgmtIntf can be used to be rendered by the default node template
@@ -162,7 +186,9 @@ type NodeConfig struct {
 	TLSAnchor            string `json:"tls-anchor,omitempty"`
 	// TLS Certificate configuration
 	Certificate *CertificateConfig
-	NSPath      string `json:"nspath,omitempty"` // network namespace path for this node
+	// Externally issued TLS material used instead of the generated certificate
+	TLS    *TLSConfig `json:"tls,omitempty"`
+	NSPath string     `json:"nspath,omitempty"` // network namespace path for this node
 	// list of ports to publish with mysocketctl
 	Publish []string `json:"publish,omitempty"`
 	// Extra /etc/hosts entries for all nodes.
@@ -179,11 +205,37 @@ type NodeConfig struct {
 	CPU    float64 `json:"cpu,omitempty"`
 	CPUSet string  `json:"cpuset,omitempty"`
 	Memory string  `json:"memory,omitempty"`
+	// MemorySwap is the memory plus swap limit, "-1" allows the unlimited swap
+	MemorySwap string `json:"memory-swap,omitempty"`
+	// Parent cgroup of the container
+	CgroupParent string `json:"cgroup-parent,omitempty"`
+	// Platform of the container image (os/arch[/variant])
+	Platform string `json:"platform,omitempty"`
+	// PulledImages are the images pulled by the lab before the node deployment conditions are checked,
+	// the node doesn't pull them again.
+	PulledImages map[string]bool `json:"-"`
+	// ImageBuild is the build of the node image, the node container is created from the built image
+	ImageBuild *ImageBuild `json:"image-build,omitempty"`
+	// OOM killer settings
+	OomKillDisable bool `json:"oom-kill-disable,omitempty"`
+	OomScoreAdj    *int `json:"oom-score-adj,omitempty"`
+	// Init runs an init process as PID 1 of the container, unset value leaves the choice to the node kind
+	Init *bool `json:"init,omitempty"`
+	// Serial console access configuration
+	Console *ConsoleConfig `json:"console,omitempty"`
+	// ConsolePort is the host port the serial console of the node is published on
+	ConsolePort int `json:"console-port,omitempty"`
+	// ExposedPorts are the host ports the services exposed by the node are published on, keyed by the service name
+	ExposedPorts map[string]int `json:"exposed-ports,omitempty"`
 
 	// Extra node parameters
 	Extras  *Extras    `json:"extras,omitempty"`
-	WaitFor []string   `json:"wait-for,omitempty"`
+	WaitFor []WaitFor  `json:"wait-for,omitempty"`
 	DNS     *DNSConfig `json:"dns,omitempty"`
+	// Time zone of the node, e.g. Europe/Berlin
+	Timezone string `json:"timezone,omitempty"`
+	// NTPServers is the list of NTP servers available to the startup config templates
+	NTPServers []string `json:"ntp-servers,omitempty"`
 
 	// Kind parameters
 	////////////////////
@@ -197,6 +249,15 @@ type NodeConfig struct {
 	SkipUniquenessCheck bool
 }
 
+// GetHostname returns the hostname of the node, the node name is used when the hostname is not set.
+func (n *NodeConfig) GetHostname() string {
+	if n.Hostname != "" {
+		return n.Hostname
+	}
+
+	return n.ShortName
+}
+
 func DisableTxOffload(n *NodeConfig) error {
 	// skip this if node runs in host mode
 	if strings.ToLower(n.NetworkMode) == "host" || strings.ToLower(n.NetworkMode) == "none" {
@@ -289,9 +350,33 @@ type ContainerDetails struct {
 	Kind        string                `json:"kind,omitempty"`
 	Group       string                `json:"group,omitempty"`
 	State       string                `json:"state,omitempty"`
+	Status      string                `json:"status,omitempty"`
 	IPv4Address string                `json:"ipv4_address,omitempty"`
 	IPv6Address string                `json:"ipv6_address,omitempty"`
 	Ports       []*GenericPortBinding `json:"ports,omitempty"`
+	ConsolePort string                `json:"console_port,omitempty"`
+	Owner       string                `json:"owner,omitempty"`
+	// ClabVersion is the containerlab version that deployed the container.
+	ClabVersion string `json:"clab_version,omitempty"`
+	// ExposedPorts are the host ports of the services exposed by the node in the <service>:<host port> format
+	ExposedPorts string `json:"exposed_ports,omitempty"`
+	// the resource limits configured for the node and the current resource usage of the container
+	MemoryLimit string `json:"memory_limit,omitempty"`
+	MemoryUsage string `json:"memory_usage,omitempty"`
+	CPULimit    string `json:"cpu_limit,omitempty"`
+	CPUUsage    string `json:"cpu_usage,omitempty"`
+	// MemoryUsageLimit is the memory usage of the container along with the memory limit reported by the runtime
+	MemoryUsageLimit string `json:"memory_usage_limit,omitempty"`
+	// ExitCode is the exit code of the last run of the container, set for the containers that are not running
+	ExitCode     *int   `json:"exit_code,omitempty"`
+	RestartCount int    `json:"restart_count,omitempty"`
+	StartedAt    string `json:"started_at,omitempty"`
+	FinishedAt   string `json:"finished_at,omitempty"`
+	// Created is the creation time of the container, Uptime is the time since the start of the running container
+	Created string `json:"created,omitempty"`
+	Uptime  string `json:"uptime,omitempty"`
+	// Health is the status of the container health check: starting, healthy, unhealthy or none
+	Health string `json:"health,omitempty"`
 }
 
 // GenericPortBinding represents a port binding.
@@ -358,6 +443,154 @@ func (c *CertificateConfig) Merge(x *CertificateConfig) *CertificateConfig {
 	return c
 }
 
+// TLSConfig represents the paths to the externally issued TLS material of a node.
+type TLSConfig struct {
+	// Cert is the path to the PEM encoded certificate of the node
+	Cert string `yaml:"cert,omitempty" json:"cert,omitempty"`
+	// Key is the path to the PEM encoded private key of the certificate
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+	// CA is the path to the PEM encoded certificate of the CA which issued the node certificate
+	CA string `yaml:"ca,omitempty" json:"ca,omitempty"`
+}
+
+// Merge merges the given TLSConfig into the current one.
+func (c *TLSConfig) Merge(x *TLSConfig) *TLSConfig {
+	if x == nil {
+		return c
+	}
+
+	if x.Cert != "" {
+		c.Cert = x.Cert
+	}
+
+	if x.Key != "" {
+		c.Key = x.Key
+	}
+
+	if x.CA != "" {
+		c.CA = x.CA
+	}
+
+	return c
+}
+
+// IsSet returns true if the externally issued TLS material is provided for the node.
+func (c *TLSConfig) IsSet() bool {
+	return c != nil && (c.Cert != "" || c.Key != "" || c.CA != "")
+}
+
+// LicensePool is the list of the license files and the directories with the license files
+// which are assigned to the nodes of a kind. A single path can be set as a plain string.
+type LicensePool []string
+
+// Interface compliance.
+var _ yaml.Unmarshaler = &LicensePool{}
+
+// UnmarshalYAML is a custom unmarshaller for LicensePool that allows to define the pool as a single path.
+func (p *LicensePool) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		*p = LicensePool{path}
+
+		return nil
+	}
+
+	var paths []string
+	if err := unmarshal(&paths); err != nil {
+		return err
+	}
+
+	*p = paths
+
+	return nil
+}
+
+// ConsoleHostPortAuto is the console host port value that makes containerlab pick a free host port.
+const ConsoleHostPortAuto = "auto"
+
+// ConsoleConfig represents the serial console access parameters set for a node.
+type ConsoleConfig struct {
+	// Expose publishes the serial console port of the node to the host
+	Expose *bool `yaml:"expose,omitempty" json:"expose,omitempty"`
+	// HostPort is the host port the console is published on,
+	// a port number or "auto" to pick a free port from the dynamic port range
+	HostPort string `yaml:"host-port,omitempty" json:"host-port,omitempty"`
+}
+
+// Merge merges the given ConsoleConfig into the current one.
+func (c *ConsoleConfig) Merge(x *ConsoleConfig) *ConsoleConfig {
+	if x == nil {
+		return c
+	}
+
+	if x.Expose != nil {
+		c.Expose = x.Expose
+	}
+
+	if x.HostPort != "" {
+		c.HostPort = x.HostPort
+	}
+
+	return c
+}
+
+// DefaultStartupWaitTimeout is the time the node is given to meet its startup-wait condition by default.
+const DefaultStartupWaitTimeout = 10 * time.Minute
+
+// StartupWait is the readiness condition the node must meet before the nodes depending on it are created.
+type StartupWait struct {
+	// TCPPort is the port on the management address of the node that must accept the connections
+	TCPPort int `yaml:"tcp-port,omitempty" json:"tcp-port,omitempty"`
+	// LogLine is the regular expression a line of the container logs must match
+	LogLine string `yaml:"log-line,omitempty" json:"log-line,omitempty"`
+	// Timeout is the maximum time to wait for the condition to be met
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// Merge merges the given StartupWait into the current one.
+func (s *StartupWait) Merge(x *StartupWait) *StartupWait {
+	if x == nil {
+		return s
+	}
+
+	if x.TCPPort != 0 {
+		s.TCPPort = x.TCPPort
+	}
+
+	if x.LogLine != "" {
+		s.LogLine = x.LogLine
+	}
+
+	if x.Timeout != 0 {
+		s.Timeout = x.Timeout
+	}
+
+	return s
+}
+
+// IsSet returns true if the startup-wait condition is defined.
+func (s *StartupWait) IsSet() bool {
+	return s != nil && (s.TCPPort != 0 || s.LogLine != "")
+}
+
+// Validate checks that the log line of the startup-wait condition is a valid regular expression.
+func (s *StartupWait) Validate() error {
+	if s == nil || s.LogLine == "" {
+		return nil
+	}
+
+	if _, err := regexp.Compile(s.LogLine); err != nil {
+		return fmt.Errorf("invalid startup-wait log-line %q: %w", s.LogLine, err)
+	}
+
+	return nil
+}
+
+// IsExposed returns true if the serial console is to be published to the host.
+func (c *ConsoleConfig) IsExposed() bool {
+	return c != nil && c.Expose != nil && *c.Expose
+}
+
 // PullPolicyValue represents Image pull policy values.
 type PullPolicyValue string
 
@@ -384,3 +617,15 @@ func ParsePullPolicyValue(s string) PullPolicyValue {
 	// default to IfNotPresent
 	return PullPolicyIfNotPresent
 }
+
+// ValidatePullPolicyValue returns an error if the given string is neither empty
+// nor one of the image pull policy values, which ParsePullPolicyValue would silently replace with the default.
+func ValidatePullPolicyValue(s string) error {
+	switch strings.TrimSpace(strings.ToLower(s)) {
+	case "", "always", "never", "ifnotpresent":
+		return nil
+	}
+
+	return fmt.Errorf("invalid image pull policy %q, valid values are %s, %s and %s",
+		s, PullPolicyAlways, PullPolicyIfNotPresent, PullPolicyNever)
+}
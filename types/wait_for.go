@@ -0,0 +1,159 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// WaitForPhase is the phase of the node a wait-for entry waits for before the waiting node is created.
+type WaitForPhase string
+
+const (
+	// WaitForPhaseCreated waits for the node to be created, the default phase.
+	WaitForPhaseCreated WaitForPhase = "created"
+	// WaitForPhaseConfigured waits for the post-deploy configuration of the node to finish.
+	WaitForPhaseConfigured WaitForPhase = "configured"
+	// WaitForPhaseHealthy waits for the health check of the node container to report it healthy.
+	WaitForPhaseHealthy WaitForPhase = "healthy"
+	// WaitForPhaseExit waits for the node container to exit, e.g. for the node running a one-off job.
+	WaitForPhaseExit WaitForPhase = "exit"
+)
+
+// WaitForPhases are the supported wait-for phases.
+var WaitForPhases = []WaitForPhase{
+	WaitForPhaseCreated,
+	WaitForPhaseConfigured,
+	WaitForPhaseHealthy,
+	WaitForPhaseExit,
+}
+
+// WaitFor is an entry of the wait-for list of a node. In the topology file it is defined either
+// as a string in the <node>[:<phase>] format or as a map with the node and phase keys.
+type WaitFor struct {
+	Node  string       `yaml:"node"`
+	Phase WaitForPhase `yaml:"phase,omitempty"`
+}
+
+// Interface compliance.
+var (
+	_ yaml.Unmarshaler = &WaitFor{}
+	_ yaml.Marshaler   = WaitFor{}
+	_ json.Marshaler   = WaitFor{}
+	_ json.Unmarshaler = &WaitFor{}
+)
+
+// ParseWaitFor parses the wait-for entry in the <node>[:<phase>] format.
+// The created phase is set for the entries without a phase.
+func ParseWaitFor(entry string) WaitFor {
+	node, phase, ok := strings.Cut(entry, ":")
+	if !ok {
+		return WaitFor{Node: entry, Phase: WaitForPhaseCreated}
+	}
+
+	return WaitFor{Node: node, Phase: WaitForPhase(phase)}
+}
+
+// UnmarshalYAML is a custom unmarshaller for WaitFor that allows to define the entry
+// as a string in the <node>[:<phase>] format.
+func (w *WaitFor) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var entry string
+	if err := unmarshal(&entry); err == nil {
+		*w = ParseWaitFor(entry)
+		return nil
+	}
+
+	// define an alias type to avoid recursion during unmarshalling
+	type WaitForAlias WaitFor
+	var wa WaitForAlias
+	if err := unmarshal(&wa); err != nil {
+		return err
+	}
+
+	if wa.Phase == "" {
+		wa.Phase = WaitForPhaseCreated
+	}
+
+	*w = WaitFor(wa)
+
+	return nil
+}
+
+// MarshalYAML writes the entry in the <node>[:<phase>] format.
+func (w WaitFor) MarshalYAML() (interface{}, error) {
+	return w.String(), nil
+}
+
+// MarshalJSON writes the entry in the <node>[:<phase>] format.
+func (w WaitFor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.String())
+}
+
+// UnmarshalJSON reads the entry in the <node>[:<phase>] format.
+func (w *WaitFor) UnmarshalJSON(b []byte) error {
+	var entry string
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return err
+	}
+
+	*w = ParseWaitFor(entry)
+
+	return nil
+}
+
+// String returns the entry in the <node>[:<phase>] format, the created phase is omitted.
+func (w WaitFor) String() string {
+	if w.Phase == "" || w.Phase == WaitForPhaseCreated {
+		return w.Node
+	}
+
+	return w.Node + ":" + string(w.Phase)
+}
+
+// Validate checks that the entry names the node and has a supported phase.
+func (w WaitFor) Validate() error {
+	if w.Node == "" {
+		return fmt.Errorf("invalid wait-for entry %q, the node name is missing", w.String())
+	}
+
+	for _, p := range WaitForPhases {
+		if w.Phase == p {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid wait-for entry %q, unknown phase %q, supported phases are: %s",
+		w.String(), w.Phase, joinWaitForPhases())
+}
+
+// joinWaitForPhases returns the comma separated list of the supported wait-for phases.
+func joinWaitForPhases() string {
+	phases := make([]string, 0, len(WaitForPhases))
+	for _, p := range WaitForPhases {
+		phases = append(phases, string(p))
+	}
+
+	return strings.Join(phases, ", ")
+}
+
+// MergeWaitFor returns the wait-for entries of the lists, the entries repeated in the later lists are skipped.
+func MergeWaitFor(lists ...[]WaitFor) []WaitFor {
+	var res []WaitFor
+
+	seen := map[WaitFor]struct{}{}
+
+	for _, l := range lists {
+		for _, w := range l {
+			if _, ok := seen[w]; ok {
+				continue
+			}
+
+			seen[w] = struct{}{}
+			res = append(res, w)
+		}
+	}
+
+	return res
+}
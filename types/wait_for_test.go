@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseWaitFor(t *testing.T) {
+	tests := map[string]struct {
+		entry   string
+		want    WaitFor
+		wantErr bool
+	}{
+		"node":             {entry: "srl1", want: WaitFor{Node: "srl1", Phase: WaitForPhaseCreated}},
+		"created phase":    {entry: "srl1:created", want: WaitFor{Node: "srl1", Phase: WaitForPhaseCreated}},
+		"configured phase": {entry: "srl1:configured", want: WaitFor{Node: "srl1", Phase: WaitForPhaseConfigured}},
+		"healthy phase":    {entry: "srl1:healthy", want: WaitFor{Node: "srl1", Phase: WaitForPhaseHealthy}},
+		"exit phase":       {entry: "job:exit", want: WaitFor{Node: "job", Phase: WaitForPhaseExit}},
+		"unknown phase":    {entry: "srl1:running", want: WaitFor{Node: "srl1", Phase: "running"}, wantErr: true},
+		"empty phase":      {entry: "srl1:", want: WaitFor{Node: "srl1"}, wantErr: true},
+		"no node":          {entry: ":healthy", want: WaitFor{Phase: WaitForPhaseHealthy}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ParseWaitFor(tt.entry)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("ParseWaitFor(%q) mismatch (-want +got):\n%s", tt.entry, d)
+			}
+
+			if err := got.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, want error %v", tt.entry, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForUnmarshalYAML(t *testing.T) {
+	in := `
+wait-for:
+  - srl1
+  - srl2:healthy
+  - node: router
+    phase: configured
+  - node: job
+`
+	var def NodeDefinition
+	if err := yaml.Unmarshal([]byte(in), &def); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []WaitFor{
+		{Node: "srl1", Phase: WaitForPhaseCreated},
+		{Node: "srl2", Phase: WaitForPhaseHealthy},
+		{Node: "router", Phase: WaitForPhaseConfigured},
+		{Node: "job", Phase: WaitForPhaseCreated},
+	}
+	if d := cmp.Diff(want, def.WaitFor); d != "" {
+		t.Errorf("wait-for mismatch (-want +got):\n%s", d)
+	}
+
+	// the entries are written in the short form
+	b, err := json.Marshal(def.WaitFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(b); got != `["srl1","srl2:healthy","router:configured","job"]` {
+		t.Errorf("got json %s", got)
+	}
+
+	var back []WaitFor
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff(want, back); d != "" {
+		t.Errorf("json round trip mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestMergeWaitFor(t *testing.T) {
+	kind := []WaitFor{ParseWaitFor("srl1"), ParseWaitFor("srl2:healthy")}
+	node := []WaitFor{ParseWaitFor("srl1:created"), ParseWaitFor("srl2")}
+
+	want := []WaitFor{ParseWaitFor("srl1"), ParseWaitFor("srl2:healthy"), ParseWaitFor("srl2")}
+	if d := cmp.Diff(want, MergeWaitFor(kind, node)); d != "" {
+		t.Errorf("merged wait-for mismatch (-want +got):\n%s", d)
+	}
+
+	if got := MergeWaitFor(nil, nil); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"archive/tar"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// dockerignoreFile is the file of the build context listing the paths excluded from the context.
+const dockerignoreFile = ".dockerignore"
+
+// BuildContextFiles returns the paths of the files of the image build context directory relative to it,
+// in the slash separated form and sorted. The paths matching the patterns of the .dockerignore file
+// of the context are excluded, except for the Dockerfile and the .dockerignore file itself.
+// The dockerfile is the path of the Dockerfile relative to the context and must be inside the context.
+func BuildContextFiles(contextDir, dockerfile string) ([]string, error) {
+	dockerfile = path.Clean(filepath.ToSlash(dockerfile))
+	if dockerfile == ".." || strings.HasPrefix(dockerfile, "../") || path.IsAbs(dockerfile) {
+		return nil, fmt.Errorf("dockerfile %q must be inside the build context %q", dockerfile, contextDir)
+	}
+
+	if !FileExists(filepath.Join(contextDir, dockerfile)) {
+		return nil, fmt.Errorf("dockerfile %q not found in the build context %q", dockerfile, contextDir)
+	}
+
+	patterns, err := readDockerignore(filepath.Join(contextDir, dockerignoreFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	err = filepath.WalkDir(contextDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(contextDir, p)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if rel != dockerfile && rel != dockerignoreFile && dockerignored(rel, patterns) {
+			// the files of an excluded directory may be re-included by an exception pattern,
+			// so the directory is skipped only when there are no exceptions
+			if d.IsDir() && !hasDockerignoreExceptions(patterns) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Type().IsRegular() || d.Type()&fs.ModeSymlink != 0 {
+			files = append(files, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the build context %q: %w", contextDir, err)
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// BuildContextHash returns the hash of the names, modes and contents of the files of the build context.
+// The files are the paths relative to the context as returned by BuildContextFiles.
+func BuildContextHash(contextDir string, files []string) (string, error) {
+	h := sha256.New()
+
+	for _, f := range files {
+		p := filepath.Join(contextDir, filepath.FromSlash(f))
+
+		fi, err := os.Lstat(p)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(h, "%s\x00%o\x00", f, fi.Mode())
+
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(p)
+			if err != nil {
+				return "", err
+			}
+
+			fmt.Fprintf(h, "%s\x00", target)
+
+			continue
+		}
+
+		if err := copyFileTo(h, p); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteBuildContext writes the files of the build context to w as a tar archive,
+// the format the image build APIs expect the build context in.
+func WriteBuildContext(w io.Writer, contextDir string, files []string) error {
+	tw := tar.NewWriter(w)
+
+	for _, f := range files {
+		p := filepath.Join(contextDir, filepath.FromSlash(f))
+
+		fi, err := os.Lstat(p)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = f
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if link != "" {
+			continue
+		}
+
+		if err := copyFileTo(tw, p); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// copyFileTo copies the contents of the file to w.
+func copyFileTo(w io.Writer, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+
+	return err
+}
+
+// readDockerignore reads the patterns of the .dockerignore file, a missing file has no patterns.
+func readDockerignore(p string) ([]string, error) {
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		exception := strings.HasPrefix(l, "!")
+		l = path.Clean(strings.TrimPrefix(strings.TrimPrefix(l, "!"), "/"))
+
+		if exception {
+			l = "!" + l
+		}
+
+		patterns = append(patterns, l)
+	}
+
+	return patterns, s.Err()
+}
+
+// dockerignored returns true if the path is excluded by the .dockerignore patterns.
+// The last pattern matching the path or one of its parent directories wins,
+// the patterns prefixed with ! re-include the matching paths.
+func dockerignored(rel string, patterns []string) bool {
+	ignored := false
+
+	for _, p := range patterns {
+		exception := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+
+		if dockerignoreMatches(p, rel) {
+			ignored = !exception
+		}
+	}
+
+	return ignored
+}
+
+// dockerignoreMatches returns true if the pattern matches the path or one of its parent directories.
+func dockerignoreMatches(pattern, rel string) bool {
+	for p := rel; p != "." && p != "/"; p = path.Dir(p) {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasDockerignoreExceptions returns true if any of the .dockerignore patterns is an exception.
+func hasDockerignoreExceptions(patterns []string) bool {
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			return true
+		}
+	}
+
+	return false
+}
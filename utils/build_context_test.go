@@ -0,0 +1,159 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeContext creates the files of the build context in a temp directory.
+func writeContext(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestBuildContextFiles(t *testing.T) {
+	tests := map[string]struct {
+		files      map[string]string
+		dockerfile string
+		want       []string
+		wantErr    bool
+	}{
+		"all files": {
+			files:      map[string]string{"Dockerfile": "FROM alpine", "app/main.sh": "", "app/lib/x.sh": ""},
+			dockerfile: "Dockerfile",
+			want:       []string{"Dockerfile", "app/lib/x.sh", "app/main.sh"},
+		},
+		"dockerignore": {
+			files: map[string]string{
+				"Dockerfile":    "FROM alpine",
+				".dockerignore": "# comment\n*.log\n/build\nDockerfile\n",
+				"app.sh":        "",
+				"debug.log":     "",
+				"build/out":     "",
+			},
+			dockerfile: "Dockerfile",
+			want:       []string{".dockerignore", "Dockerfile", "app.sh"},
+		},
+		"dockerignore exception": {
+			files: map[string]string{
+				"docker/Dockerfile": "FROM alpine",
+				".dockerignore":     "data\n!data/keep.txt\n",
+				"data/keep.txt":     "",
+				"data/drop.txt":     "",
+			},
+			dockerfile: "docker/Dockerfile",
+			want:       []string{".dockerignore", "data/keep.txt", "docker/Dockerfile"},
+		},
+		"missing dockerfile": {
+			files:      map[string]string{"app.sh": ""},
+			dockerfile: "Dockerfile",
+			wantErr:    true,
+		},
+		"dockerfile outside the context": {
+			files:      map[string]string{"Dockerfile": ""},
+			dockerfile: "../Dockerfile",
+			wantErr:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeContext(t, tt.files)
+
+			got, err := BuildContextFiles(dir, tt.dockerfile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("files mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestBuildContextHash(t *testing.T) {
+	dir := writeContext(t, map[string]string{"Dockerfile": "FROM alpine", "app.sh": "echo 1"})
+	files := []string{"Dockerfile", "app.sh"}
+
+	h1, err := BuildContextHash(dir, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the modification time of the unchanged file doesn't change the hash
+	if err := os.WriteFile(filepath.Join(dir, "app.sh"), []byte("echo 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if h, _ := BuildContextHash(dir, files); h != h1 {
+		t.Error("hash of the unchanged context changed")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "app.sh"), []byte("echo 2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if h, _ := BuildContextHash(dir, files); h == h1 {
+		t.Error("hash of the changed context is unchanged")
+	}
+}
+
+func TestWriteBuildContext(t *testing.T) {
+	dir := writeContext(t, map[string]string{"Dockerfile": "FROM alpine", "app/main.sh": "echo"})
+
+	buf := new(bytes.Buffer)
+	if err := WriteBuildContext(buf, dir, []string{"Dockerfile", "app/main.sh"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+
+	tr := tar.NewReader(buf)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got[hdr.Name] = string(b)
+	}
+
+	want := map[string]string{"Dockerfile": "FROM alpine", "app/main.sh": "echo"}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("archive mismatch (-want +got):\n%s", d)
+	}
+}
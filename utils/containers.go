@@ -1,17 +1,33 @@
 package utils
 
 import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 const (
 	cniBin = "/opt/cni/bin"
+
+	// ImageArchivePrefix is the scheme of the node image referring to an image archive
+	// created with `docker save`, e.g. file:///path/to/image.tar.
+	ImageArchivePrefix = "file://"
+	// imageArchiveManifest is the manifest file of the docker image archive listing the archived images.
+	imageArchiveManifest = "manifest.json"
 )
 
+// platformRe matches the container image platform in the os/arch[/variant] format.
+var platformRe = regexp.MustCompile(`^([a-z0-9_]+)/([a-z0-9_]+)(?:/([a-z0-9_]+))?$`)
+
 // GetCanonicalImageName produces a canonical image name.
 // if the input name did not specify a tag, the implicit "latest" tag is returned.
 func GetCanonicalImageName(imageName string) string {
@@ -44,6 +60,77 @@ func GetCanonicalImageName(imageName string) string {
 	return canonicalImageName
 }
 
+// ParsePlatform parses the container image platform in the os/arch[/variant] format,
+// e.g. linux/amd64 or linux/arm64/v8.
+func ParsePlatform(platform string) (*ocispec.Platform, error) {
+	m := platformRe.FindStringSubmatch(platform)
+	if m == nil {
+		return nil, fmt.Errorf("invalid platform %q, expected os/arch[/variant] format, e.g. linux/amd64", platform)
+	}
+
+	return &ocispec.Platform{
+		OS:           m[1],
+		Architecture: m[2],
+		Variant:      m[3],
+	}, nil
+}
+
+// ImageArchivePath returns the path of the image archive the image refers to
+// and true if the image is an image archive reference.
+func ImageArchivePath(image string) (string, bool) {
+	if !strings.HasPrefix(image, ImageArchivePrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(image, ImageArchivePrefix), true
+}
+
+// ImageArchiveTags returns the tags of the images in the image archive created with `docker save`,
+// in the order the images are listed in the archive manifest.
+func ImageArchiveTags(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("image archive %q has no %s file", path, imageArchiveManifest)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image archive %q: %w", path, err)
+		}
+
+		if hdr.Name != imageArchiveManifest {
+			continue
+		}
+
+		var manifest []struct {
+			RepoTags []string
+		}
+
+		if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("failed to decode the manifest of image archive %q: %w", path, err)
+		}
+
+		var tags []string
+		for _, m := range manifest {
+			tags = append(tags, m.RepoTags...)
+		}
+
+		if len(tags) == 0 {
+			return nil, fmt.Errorf("image archive %q has no tagged images", path)
+		}
+
+		return tags, nil
+	}
+}
+
 func GetCNIBinaryPath() string {
 	var cniPath string
 	var ok bool
@@ -56,7 +143,7 @@ func GetCNIBinaryPath() string {
 // ContainerNSToPID resolves the name of a container via
 // the "/run/netns/<CONTAINERNAME>" to its PID.
 func ContainerNSToPID(cID string) (int, error) {
-	pnns, err := filepath.EvalSymlinks("/run/netns/" + cID)
+	pnns, err := filepath.EvalSymlinks(filepath.Join(NetnsDir, cID))
 	if err != nil {
 		return 0, err
 	}
@@ -70,3 +157,26 @@ func ContainerNSToPID(cID string) (int, error) {
 	}
 	return pid, nil
 }
+
+// containerEnvFiles are the files that container runtimes create inside the containers they run.
+var containerEnvFiles = []string{
+	"/.dockerenv",
+	"/run/.containerenv",
+}
+
+// IsRunningInContainer returns true if containerlab itself runs inside a container,
+// e.g. in a docker-in-docker setup.
+func IsRunningInContainer() bool {
+	// systemd-nspawn, podman and lxc set the container env var
+	if os.Getenv("container") != "" {
+		return true
+	}
+
+	for _, f := range containerEnvFiles {
+		if _, err := os.Stat(f); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
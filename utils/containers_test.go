@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"archive/tar"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 func TestGetCanonicalImageName(t *testing.T) {
@@ -47,3 +51,136 @@ func TestGetCanonicalImageName(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlatform(t *testing.T) {
+	tests := map[string]struct {
+		platform string
+		want     *ocispec.Platform
+		wantErr  bool
+	}{
+		"os and arch": {
+			platform: "linux/amd64",
+			want:     &ocispec.Platform{OS: "linux", Architecture: "amd64"},
+		},
+		"with variant": {
+			platform: "linux/arm64/v8",
+			want:     &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+		"arch only": {
+			platform: "amd64",
+			wantErr:  true,
+		},
+		"empty arch": {
+			platform: "linux/",
+			wantErr:  true,
+		},
+		"too many elements": {
+			platform: "linux/arm64/v8/extra",
+			wantErr:  true,
+		},
+		"uppercase": {
+			platform: "Linux/AMD64",
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePlatform(tc.platform)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Fatalf("platform mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+// writeImageArchive writes the image archive with the files to the test temp dir.
+func writeImageArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "image.tar")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestImageArchiveTags(t *testing.T) {
+	tests := map[string]struct {
+		files   map[string]string
+		want    []string
+		wantErr bool
+	}{
+		"multiple images and tags": {
+			files: map[string]string{
+				"blobs/sha256/0123": "layer",
+				"manifest.json": `[{"Config":"blobs/sha256/a","RepoTags":["alpine:3","alpine:latest"]},` +
+					`{"Config":"blobs/sha256/b","RepoTags":["nginx:1.25"]}]`,
+			},
+			want: []string{"alpine:3", "alpine:latest", "nginx:1.25"},
+		},
+		"untagged images": {
+			files: map[string]string{
+				"manifest.json": `[{"Config":"blobs/sha256/a","RepoTags":null}]`,
+			},
+			wantErr: true,
+		},
+		"no manifest": {
+			files: map[string]string{
+				"index.json": `{}`,
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ImageArchiveTags(writeImageArchive(t, tc.files))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Fatalf("tags mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContextLines is the number of unchanged lines surrounding the changes in a unified diff hunk.
+const diffContextLines = 3
+
+// diffLine is a line of a line-based diff with its operation: ' ' for an unchanged line,
+// '-' for a line removed from the original text and '+' for a line added to the new text.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff returns the line-based diff of the texts from and to in the unified format
+// with the fromName and toName file names in the header.
+// An empty string is returned when the texts are equal.
+func UnifiedDiff(from, to, fromName, toName string) string {
+	lines := diffLines(from, to)
+
+	// keep marks the lines that belong to a hunk, i.e. the changed lines and their context
+	keep := make([]bool, len(lines))
+
+	changed := false
+
+	for i, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+
+		changed = true
+
+		for j := i - diffContextLines; j <= i+diffContextLines; j++ {
+			if j >= 0 && j < len(lines) {
+				keep[j] = true
+			}
+		}
+	}
+
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// fromLine and toLine are the numbers of the original and the new lines preceding the current line
+	fromLine, toLine := 0, 0
+
+	for i := 0; i < len(lines); {
+		if !keep[i] {
+			fromLine, toLine = advanceDiffLines(lines[i], fromLine, toLine)
+			i++
+
+			continue
+		}
+
+		// the hunk spans the consecutive lines kept from the i-th line
+		end := i
+		for end < len(lines) && keep[end] {
+			end++
+		}
+
+		fromStart, toStart := fromLine, toLine
+
+		var hunk strings.Builder
+
+		for _, l := range lines[i:end] {
+			hunk.WriteByte(l.op)
+			hunk.WriteString(l.text)
+			hunk.WriteByte('\n')
+
+			fromLine, toLine = advanceDiffLines(l, fromLine, toLine)
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n%s",
+			hunkRange(fromStart, fromLine-fromStart), hunkRange(toStart, toLine-toStart), hunk.String())
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+// diffLines returns the line-based diff of the texts from and to.
+func diffLines(from, to string) []diffLine {
+	// every distinct line is encoded as a rune, so that the lines are diffed as characters
+	index := map[string]rune{}
+	texts := map[rune]string{}
+
+	encode := func(s string) []rune {
+		var runes []rune
+
+		for _, text := range strings.SplitAfter(s, "\n") {
+			if text == "" {
+				continue
+			}
+
+			text = strings.TrimSuffix(text, "\n")
+
+			r, ok := index[text]
+			if !ok {
+				r = rune(len(texts))
+				// the surrogate code points are not valid in the diff texts
+				if r >= 0xd800 {
+					r += 0x800
+				}
+
+				index[text] = r
+				texts[r] = text
+			}
+
+			runes = append(runes, r)
+		}
+
+		return runes
+	}
+
+	a, b := encode(from), encode(to)
+
+	var lines []diffLine
+
+	for _, d := range diffmatchpatch.New().DiffMainRunes(a, b, false) {
+		op := byte(' ')
+
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+
+		for _, r := range d.Text {
+			lines = append(lines, diffLine{op: op, text: texts[r]})
+		}
+	}
+
+	return lines
+}
+
+// advanceDiffLines returns the numbers of the original and the new lines after the line l.
+func advanceDiffLines(l diffLine, fromLine, toLine int) (int, int) {
+	switch l.op {
+	case '-':
+		fromLine++
+	case '+':
+		toLine++
+	default:
+		fromLine++
+		toLine++
+	}
+
+	return fromLine, toLine
+}
+
+// hunkRange returns the range of a unified diff hunk header for the hunk
+// of n lines following the start line.
+func hunkRange(start, n int) string {
+	// the empty range refers to the line preceding the hunk
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := map[string]struct {
+		from string
+		to   string
+		want string
+	}{
+		"equal": {
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		"changed line": {
+			from: "a\nb\nc\n",
+			to:   "a\nx\nc\n",
+			want: "--- from\n+++ to\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		"added to empty": {
+			from: "",
+			to:   "a\n",
+			want: "--- from\n+++ to\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		"separate hunks": {
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			to:   "x\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			want: "--- from\n+++ to\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,3 @@\n 7\n 8\n 9\n-10\n",
+		},
+		"merged hunks": {
+			from: "1\n2\n3\n4\n5\n6\n7\n",
+			to:   "x\n2\n3\n4\n5\n6\ny\n",
+			want: "--- from\n+++ to\n@@ -1,7 +1,7 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n-7\n+y\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := UnifiedDiff(tt.from, tt.to, "from", "to"); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
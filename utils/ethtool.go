@@ -38,7 +38,24 @@ func ioctlEthtool(fd int, argp uintptr) error {
 }
 
 // EthtoolTXOff disables TX checksum offload on specified interface.
+// When containerlab runs in a container the ethtool netlink family is used,
+// otherwise the ethtool ioctl is used with the fallback to netlink.
 func EthtoolTXOff(name string) error {
+	if IsRunningInContainer() {
+		return EthtoolTXOffNetlink(name)
+	}
+
+	if err := ethtoolTXOffIoctl(name); err != nil {
+		if nlErr := EthtoolTXOffNetlink(name); nlErr != nil {
+			return fmt.Errorf("%w; %w", err, nlErr)
+		}
+	}
+
+	return nil
+}
+
+// ethtoolTXOffIoctl disables TX checksum offload on specified interface using the ethtool ioctl.
+func ethtoolTXOffIoctl(name string) error {
 	if len(name)+1 > IFNAMSIZ {
 		return fmt.Errorf("name too long")
 	}
@@ -0,0 +1,128 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ethtoolGenlName    = "ethtool" // linux/ethtool_netlink.h
+	ethtoolGenlVersion = 1         // linux/ethtool_netlink.h
+	ethtoolMsgFeatSet  = 12        // ETHTOOL_MSG_FEATURES_SET
+	ethtoolAHeader     = 1         // ETHTOOL_A_FEATURES_HEADER
+	ethtoolAFeatWanted = 3         // ETHTOOL_A_FEATURES_WANTED
+	ethtoolAHeaderName = 2         // ETHTOOL_A_HEADER_DEV_NAME
+	ethtoolABitsetBits = 3         // ETHTOOL_A_BITSET_BITS
+	ethtoolABitsBit    = 1         // ETHTOOL_A_BITSET_BITS_BIT
+	ethtoolABitName    = 2         // ETHTOOL_A_BITSET_BIT_NAME
+)
+
+// BridgeGroupFwdMaskLLDP is a bridge group_fwd_mask bit that allows LLDP frames (01:80:C2:00:00:0E) to be forwarded.
+const BridgeGroupFwdMaskLLDP uint16 = 1 << 14
+
+// txChecksumFeatures is a list of the netdev features that `ethtool -K <dev> tx off` disables.
+var txChecksumFeatures = []string{
+	"tx-checksum-ipv4",
+	"tx-checksum-ip-generic",
+	"tx-checksum-ipv6",
+	"tx-checksum-fcoe-crc",
+	"tx-checksum-sctp",
+}
+
+// EthtoolTXOffNetlink disables TX checksum offload on specified interface
+// using the ethtool generic netlink family.
+// Unlike the ioctl-based EthtoolTXOff, it doesn't require an AF_INET socket
+// and works in the environments where the ethtool ioctls are not available.
+func EthtoolTXOffNetlink(name string) error {
+	if len(name)+1 > IFNAMSIZ {
+		return fmt.Errorf("name too long")
+	}
+
+	family, err := netlink.GenlFamilyGet(ethtoolGenlName)
+	if err != nil {
+		return fmt.Errorf("failed to get %s generic netlink family: %w", ethtoolGenlName, err)
+	}
+
+	req := nl.NewNetlinkRequest(int(family.ID), unix.NLM_F_ACK)
+	req.AddData(&nl.Genlmsg{
+		Command: ethtoolMsgFeatSet,
+		Version: ethtoolGenlVersion,
+	})
+
+	header := nl.NewRtAttr(ethtoolAHeader|unix.NLA_F_NESTED, nil)
+	header.AddRtAttr(ethtoolAHeaderName, nl.ZeroTerminated(name))
+	req.AddData(header)
+
+	// wanted features are encoded as a compact list of bits without the value flag,
+	// which means that the listed bits are set to 0, and the others are left untouched
+	wanted := nl.NewRtAttr(ethtoolAFeatWanted|unix.NLA_F_NESTED, nil)
+	bits := wanted.AddRtAttr(ethtoolABitsetBits|unix.NLA_F_NESTED, nil)
+	for _, f := range txChecksumFeatures {
+		bit := bits.AddRtAttr(ethtoolABitsBit|unix.NLA_F_NESTED, nil)
+		bit.AddRtAttr(ethtoolABitName, nl.ZeroTerminated(f))
+	}
+	req.AddData(wanted)
+
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return fmt.Errorf("failed to disable TX checksum offload on %s via netlink: %w", name, err)
+	}
+
+	return nil
+}
+
+// EnableBridgeLLDP allows the linux bridge to forward LLDP frames by setting its group_fwd_mask.
+// When containerlab runs in a container, where sysfs is often read-only, netlink is used,
+// otherwise sysfs is used with the fallback to netlink.
+func EnableBridgeLLDP(bridge string) error {
+	if IsRunningInContainer() {
+		return SetBridgeGroupFwdMask(bridge, BridgeGroupFwdMaskLLDP)
+	}
+
+	file := "/sys/class/net/" + bridge + "/bridge/group_fwd_mask"
+
+	err := os.WriteFile(file, []byte(strconv.Itoa(int(BridgeGroupFwdMaskLLDP))), 0640) // skipcq: GO-S2306
+	if err != nil {
+		if nlErr := SetBridgeGroupFwdMask(bridge, BridgeGroupFwdMaskLLDP); nlErr != nil {
+			return fmt.Errorf("%w; %w", err, nlErr)
+		}
+	}
+
+	return nil
+}
+
+// SetBridgeGroupFwdMask sets the group_fwd_mask of the linux bridge using netlink.
+func SetBridgeGroupFwdMask(bridge string, mask uint16) error {
+	l, err := netlink.LinkByName(bridge)
+	if err != nil {
+		return fmt.Errorf("failed to lookup bridge %q: %w", bridge, err)
+	}
+
+	req := nl.NewNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = int32(l.Attrs().Index)
+	req.AddData(msg)
+
+	linkInfo := nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	linkInfo.AddRtAttr(nl.IFLA_INFO_KIND, nl.NonZeroTerminated("bridge"))
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+	data.AddRtAttr(nl.IFLA_BR_GROUP_FWD_MASK, nl.Uint16Attr(mask))
+	req.AddData(linkInfo)
+
+	_, err = req.Execute(unix.NETLINK_ROUTE, 0)
+	if err != nil {
+		return fmt.Errorf("failed to set group_fwd_mask on bridge %q via netlink: %w", bridge, err)
+	}
+
+	return nil
+}
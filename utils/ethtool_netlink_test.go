@@ -0,0 +1,90 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/srl-labs/containerlab/internal/netnstest"
+	"github.com/vishvananda/netlink"
+)
+
+// txChecksumEnabled returns the TX checksum offload state of the interface using the ethtool ioctl.
+func txChecksumEnabled(t *testing.T, name string) bool {
+	t.Helper()
+
+	socket, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(socket)
+
+	value := EthtoolValue{Cmd: ETHTOOL_GTXCSUM}
+	request := IFReqData{Data: uintptr(unsafe.Pointer(&value))} // skipcq: GSC-G103
+	copy(request.Name[:], name)
+
+	if err := ioctlEthtool(socket, uintptr(unsafe.Pointer(&request))); err != nil { // skipcq: GSC-G103
+		t.Fatal(err)
+	}
+
+	return value.Data != 0
+}
+
+func TestEthtoolTXOffNetlink(t *testing.T) {
+	netnstest.Run(t, func() {
+		veth := &netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: "clabtest0"},
+			PeerName:  "clabtest1",
+		}
+		if err := netlink.LinkAdd(veth); err != nil {
+			t.Fatal(err)
+		}
+
+		if !txChecksumEnabled(t, "clabtest0") {
+			t.Fatal("expected TX checksum offload to be enabled on a new veth")
+		}
+
+		if err := EthtoolTXOffNetlink("clabtest0"); err != nil {
+			t.Fatal(err)
+		}
+
+		if txChecksumEnabled(t, "clabtest0") {
+			t.Error("TX checksum offload is still enabled")
+		}
+
+		// the peer must not be affected
+		if !txChecksumEnabled(t, "clabtest1") {
+			t.Error("TX checksum offload was disabled on the peer interface")
+		}
+
+		// disabling already disabled offload is not an error
+		if err := EthtoolTXOffNetlink("clabtest0"); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestEthtoolTXOffNetlinkMissingLink(t *testing.T) {
+	netnstest.Run(t, func() {
+		if err := EthtoolTXOffNetlink("clabmissing0"); err == nil {
+			t.Error("expected an error for a missing interface")
+		}
+	})
+}
+
+func TestSetBridgeGroupFwdMask(t *testing.T) {
+	netnstest.Run(t, func() {
+		br := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "clabtestbr0"}}
+		if err := netlink.LinkAdd(br); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := SetBridgeGroupFwdMask("clabtestbr0", BridgeGroupFwdMaskLLDP); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
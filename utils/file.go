@@ -5,6 +5,7 @@
 package utils
 
 import (
+	"archive/tar"
 	"bufio"
 	"errors"
 	"fmt"
@@ -36,6 +37,15 @@ func FileExists(filename string) bool {
 	return !f.IsDir()
 }
 
+// DirExists returns true if a directory referenced by path exists & accessible.
+func DirExists(path string) bool {
+	f, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return f.IsDir()
+}
+
 // CopyFile copies a file from src to dst. If src and dst files exist, and are
 // the same, then return success. Otherwise, copy the file contents from src to dst.
 // mode is the desired target file permissions, e.g. "0644".
@@ -156,6 +166,106 @@ func CreateFile(file, content string) (err error) {
 	return f.Close()
 }
 
+// WriteFileAtomic writes data to a temporary file in the directory of dst and renames it to dst.
+// The rename is atomic, so dst either keeps its previous content or gets the complete data.
+func WriteFileAtomic(dst string, data []byte, perm os.FileMode) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	// remove the temp file if it wasn't renamed to dst
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	if err = f.Chmod(perm); err != nil {
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), dst)
+}
+
+// RotateFile copies the file p to its first backup, shifting the existing backups by one
+// and keeping at most keep backups. backupPath returns the path of the n-th backup, starting from 1.
+// Nothing is done when keep is not positive or the file p doesn't exist.
+func RotateFile(p string, keep int, backupPath func(n int) string) error {
+	if keep <= 0 || !FileExists(p) {
+		return nil
+	}
+
+	err := os.Remove(backupPath(keep))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for n := keep - 1; n >= 1; n-- {
+		err := os.Rename(backupPath(n), backupPath(n+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+
+	return CopyFileContents(p, backupPath(1), fi.Mode().Perm())
+}
+
+// ExtractTarFile writes the content of the first regular file found in the tar stream r to dst.
+// It is used to extract the files copied out of the containers, which the runtimes provide as tar archives.
+func ExtractTarFile(r io.Reader, dst string) error {
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return errors.New("no regular file found in the archive")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+
+		return f.Close()
+	}
+}
+
 // CreateDirectory creates a directory by a path with a mode/permission specified by perm.
 // If directory exists, the function does not do anything.
 func CreateDirectory(path string, perm os.FileMode) {
@@ -171,41 +281,72 @@ func ReadFileContent(file string) ([]byte, error) {
 	return b, err
 }
 
-// ExpandHome expands `~` char in the path to home path of a current user in provided path p.
-// When sudo is used, it expands to home dir of a sudo user.
-func ExpandHome(p string) string {
+// lookupUserID and lookupUser look up the users by the id and the name, replaced in tests.
+var (
+	lookupUserID = user.LookupId
+	lookupUser   = user.Lookup
+)
+
+// HomeDir returns the home dir of the user invoking containerlab.
+// When sudo is used, it is the home dir of the sudo user rather than the one of root.
+func HomeDir() string {
 	// current user home dir, used when sudo is not used
 	// or when errors occur during sudo user lookup
 	curUserHomeDir, _ := os.UserHomeDir()
 
 	userId, isSet := os.LookupEnv("SUDO_UID")
-	if !isSet {
+	if !isSet || userId == "" {
 		log.Debugf("SUDO_UID env var is not set, using current user home dir: %v", curUserHomeDir)
-		p = strings.Replace(p, "~", curUserHomeDir, 1)
-		return p
+		return curUserHomeDir
 	}
 
 	// lookup user to figure out Home Directory
-	u, err := user.LookupId(userId)
-	if err != nil {
-		log.Debugf("error while looking up user by id using os/user.LookupId %v: %v", userId, err)
-		// user.LookupId fails when ActiveDirectory is used, so we try to use getent command
-		homedir := lookupUserHomeDirViaGetent(userId)
-		if homedir != "" {
-			log.Debugf("user home dir %v found using getent command", homedir)
-			p = strings.Replace(p, "~", homedir, 1)
-			return p
+	u, err := lookupUserID(userId)
+	if err == nil {
+		log.Debugf("user home dir %v found using os/user.LookupId", u.HomeDir)
+		return u.HomeDir
+	}
+
+	log.Debugf("error while looking up user by id using os/user.LookupId %v: %v", userId, err)
+
+	// the lookup by id may fail for the users not known to the local user database
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		if u, err := lookupUser(name); err == nil {
+			log.Debugf("user home dir %v found using os/user.Lookup", u.HomeDir)
+			return u.HomeDir
 		}
-		// fallback to current user home dir if getent command fails
-		p = strings.Replace(p, "~", curUserHomeDir, 1)
-		return p
 	}
 
-	p = strings.Replace(p, "~", u.HomeDir, 1)
+	// user.LookupId fails when ActiveDirectory is used, so we try to use getent command
+	if homedir := lookupUserHomeDirViaGetent(userId); homedir != "" {
+		log.Debugf("user home dir %v found using getent command", homedir)
+		return homedir
+	}
 
-	log.Debugf("user home dir %v found using os/user.LookupId", u.HomeDir)
+	// fallback to current user home dir if getent command fails
+	return curUserHomeDir
+}
 
-	return p
+// ExpandHome expands `~` char in the path to home path of a current user in provided path p.
+// When sudo is used, it expands to home dir of a sudo user.
+func ExpandHome(p string) string {
+	return strings.Replace(p, "~", HomeDir(), 1)
+}
+
+// GetOwner returns the name of the user running containerlab.
+// When sudo is used, it returns the name of the sudo user.
+func GetOwner() string {
+	if u := os.Getenv("SUDO_USER"); u != "" {
+		return u
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		log.Debugf("error while looking up the current user: %v", err)
+		return ""
+	}
+
+	return u.Username
 }
 
 // lookupUserHomeDirViaGetent looks up user's homedir by using `getent passwd` command.
@@ -230,24 +371,41 @@ func lookupUserHomeDirViaGetent(userId string) string {
 	return parts[5]
 }
 
-// ResolvePath resolves a string path by expanding `~` to home dir
-// or resolving a relative path by joining it with the base path.
+// ResolvePath resolves a user-provided path by expanding the env vars, e.g. $HOME or ${LAB_DIR},
+// and the leading `~` to the home dir, and resolving a relative path by joining it with the base path.
+// All path fields of the topology are resolved with this function, so that they behave the same way.
 func ResolvePath(p, base string) string {
 	if p == "" {
 		return p
 	}
 
+	if strings.Contains(p, "$") {
+		if p = os.Expand(p, expandPathEnv); p == "" {
+			return p
+		}
+	}
+
 	switch {
 	// resolve ~/ path
 	case p[0] == '~':
 		p = ExpandHome(p)
-	case p[0] == '/':
-		return p
+	case filepath.IsAbs(p):
 	default:
 		// join relative path with the base path
 		p = filepath.Join(base, p)
 	}
-	return p
+
+	return filepath.Clean(p)
+}
+
+// expandPathEnv returns the value of the env var referenced in a path.
+// HOME is the home dir of the invoking user, as sudo sets it to the home dir of root.
+func expandPathEnv(name string) string {
+	if name == "HOME" {
+		return HomeDir()
+	}
+
+	return os.Getenv(name)
 }
 
 const (
@@ -5,6 +5,13 @@
 package utils
 
 import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -84,3 +91,188 @@ func TestFileLines(t *testing.T) {
 		})
 	}
 }
+
+func TestRotateFile(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "config")
+	backupPath := func(n int) string { return fmt.Sprintf("%s.%d", p, n) }
+
+	for i := 1; i <= 4; i++ {
+		if err := WriteFileAtomic(p, []byte(strconv.Itoa(i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if i < 4 {
+			if err := RotateFile(p, 2, backupPath); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	want := map[string]string{
+		p:             "4",
+		backupPath(1): "3",
+		backupPath(2): "2",
+	}
+
+	for f, w := range want {
+		got, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != w {
+			t.Errorf("file %s: got %q, want %q", f, got, w)
+		}
+	}
+
+	// no backups beyond the limit and no leftover temp files
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != len(want) {
+		t.Errorf("got %d files in the dir, want %d", len(entries), len(want))
+	}
+
+	// a missing file and a disabled rotation are no-ops
+	if err := RotateFile(filepath.Join(dir, "missing"), 2, backupPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RotateFile(p, 0, backupPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := os.ReadFile(backupPath(1)); string(got) != "3" {
+		t.Errorf("got backup %q after disabled rotation, want %q", got, "3")
+	}
+}
+
+func TestExtractTarFile(t *testing.T) {
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("show tech output")
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "show-tech.txt", Typeflag: tar.TypeReg, Mode: 0600,
+		Size: int64(len(content)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "diagnostics", "bundle.txt")
+
+	if err := ExtractTarFile(&buf, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, content) {
+		t.Errorf("got %q, want %q", got, content)
+	}
+
+	// archive without regular files
+	if err := ExtractTarFile(bytes.NewReader(nil), dst); err == nil {
+		t.Error("wanted error for an empty archive")
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("home dir is unknown: %v", err)
+	}
+
+	t.Setenv("SUDO_UID", "")
+	os.Unsetenv("SUDO_UID")
+	t.Setenv("CLAB_TEST_DIR", "/opt/labs")
+
+	tests := map[string]struct {
+		path string
+		want string
+	}{
+		"empty":            {},
+		"absolute":         {path: "/etc/license.key", want: "/etc/license.key"},
+		"relative":         {path: "configs/srl1.cfg", want: "/topo/configs/srl1.cfg"},
+		"parent":           {path: "../configs/srl1.cfg", want: "/configs/srl1.cfg"},
+		"home":             {path: "~/license.key", want: filepath.Join(home, "license.key")},
+		"home env var":     {path: "$HOME/license.key", want: filepath.Join(home, "license.key")},
+		"braced env var":   {path: "${CLAB_TEST_DIR}/srl1.cfg", want: "/opt/labs/srl1.cfg"},
+		"relative env":     {path: "$CLAB_TEST_DIR/../srl1.cfg", want: "/opt/srl1.cfg"},
+		"unset env var":    {path: "$CLAB_TEST_UNSET"},
+		"unclean absolute": {path: "/etc//clab/./license.key", want: "/etc/clab/license.key"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ResolvePath(tt.path, "/topo"); got != tt.want {
+				t.Errorf("ResolvePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHomeDir(t *testing.T) {
+	defer func(id, name func(string) (*user.User, error)) { lookupUserID, lookupUser = id, name }(lookupUserID, lookupUser)
+
+	// the fixture home dirs of the users known to the lookups
+	users := map[string]*user.User{
+		"1000":  {Uid: "1000", Username: "alice", HomeDir: "/home/alice"},
+		"alice": {Uid: "1000", Username: "alice", HomeDir: "/home/alice"},
+		"bob":   {Uid: "1001", Username: "bob", HomeDir: "/home/bob"},
+	}
+	lookup := func(k string) (*user.User, error) {
+		if u, ok := users[k]; ok {
+			return u, nil
+		}
+		return nil, fmt.Errorf("user %s not found", k)
+	}
+	lookupUserID, lookupUser = lookup, lookup
+
+	t.Setenv("HOME", "/root")
+
+	tests := map[string]struct {
+		sudoUID  string
+		sudoUser string
+		want     string
+	}{
+		"no sudo":               {want: "/root"},
+		"sudo":                  {sudoUID: "1000", sudoUser: "alice", want: "/home/alice"},
+		"sudo user by name":     {sudoUID: "1001", sudoUser: "bob", want: "/home/bob"},
+		"unknown sudo user":     {sudoUID: "987654", sudoUser: "carol", want: "/root"},
+		"sudo user name absent": {sudoUID: "987654", want: "/root"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("SUDO_UID", tt.sudoUID)
+			t.Setenv("SUDO_USER", tt.sudoUser)
+
+			if got := HomeDir(); got != tt.want {
+				t.Errorf("HomeDir() = %q, want %q", got, tt.want)
+			}
+
+			if got, want := ResolvePath("$HOME/.ssh/id_rsa.pub", ""), filepath.Join(tt.want, ".ssh/id_rsa.pub"); got != want {
+				t.Errorf("ResolvePath($HOME) = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// LockFile takes the exclusive advisory lock of the file, creating the file when it doesn't exist.
+// It blocks until the lock held by another process is released.
+// The returned function releases the lock.
+func LockFile(path string) (func() error, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock file %s: %w", path, err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	return func() error {
+		defer f.Close()
+
+		return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	}, nil
+}
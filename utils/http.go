@@ -2,10 +2,15 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -79,11 +84,21 @@ func (u *GithubURL) Parse(ghURL string) error {
 
 // CloneGithubRepo clones the github repo into the current directory.
 func CloneGithubRepo(u *GithubURL) error {
+	return CloneGithubRepoToDir(u, "")
+}
+
+// CloneGithubRepoToDir clones the github repo into the dir directory.
+// When dir is empty, the repo is cloned into the current directory.
+func CloneGithubRepoToDir(u *GithubURL, dir string) error {
 	cloneArgs := []string{"clone", u.URLBase + "/" + u.ProjectOwner + "/" + u.RepositoryName, "--depth", "1"}
 	if u.GitBranch != "" {
 		cloneArgs = append(cloneArgs, []string{"--branch", u.GitBranch}...)
 	}
 
+	if dir != "" {
+		cloneArgs = append(cloneArgs, dir)
+	}
+
 	cmd := exec.Command("git", cloneArgs...)
 
 	log.Infof("cloning %s/%s", u.ProjectOwner, u.RepositoryName)
@@ -108,3 +123,42 @@ func IsGitHubURL(url string) bool {
 	return strings.Contains(url, "github.com") ||
 		strings.Contains(url, "github.dev")
 }
+
+// DownloadFile downloads the http(s) resource referenced by src to the dst file.
+// The headers are added to the request, e.g. to provide the authorization token.
+// Responses with non-2xx status codes are returned as errors.
+func DownloadFile(ctx context.Context, src, dst string, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", errHTTPFetch, src, err)
+	}
+	defer resp.Body.Close() // skipcq: GO-S2307
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s: server responded with %q", errHTTPFetch, src, resp.Status)
+	}
+
+	err = os.MkdirAll(filepath.Dir(dst), 0750)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+
+	return err
+}
@@ -1,7 +1,12 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -191,3 +196,69 @@ func TestGithubURLParse(t *testing.T) {
 		})
 	}
 }
+
+func TestDownloadFile(t *testing.T) {
+	const content = "name: test\n"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/lab.clab.yml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		path    string
+		headers map[string]string
+		wantErr bool
+	}{
+		"ok": {
+			path:    "/lab.clab.yml",
+			headers: map[string]string{"Authorization": "Bearer token"},
+		},
+		"unauthorized": {
+			path:    "/lab.clab.yml",
+			wantErr: true,
+		},
+		"not-found": {
+			path:    "/missing.clab.yml",
+			headers: map[string]string{"Authorization": "Bearer token"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "lab.clab.yml")
+
+			err := DownloadFile(context.Background(), srv.URL+tt.path, dst, tt.headers)
+			if tt.wantErr {
+				if !errors.Is(err, errHTTPFetch) {
+					t.Fatalf("got error %v, want %v", err, errHTTPFetch)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != content {
+				t.Errorf("got content %q, want %q", b, content)
+			}
+		})
+	}
+}
@@ -3,28 +3,147 @@ package utils
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/pmorjan/kmod"
 	log "github.com/sirupsen/logrus"
 )
 
+// modulesDir is the directory with the modules of the kernel releases.
+const modulesDir = "/lib/modules"
+
+// procModulesPath is the list of the loaded kernel modules.
+// It is a variable to allow tests to point it to a fixture.
+var procModulesPath = "/proc/modules"
+
 // IsKernelModuleLoaded checks if a kernel module is loaded by parsing /proc/modules file.
+// The module name is matched exactly, the dashes and underscores are treated as the same character.
 func IsKernelModuleLoaded(name string) (bool, error) {
-	f, err := os.Open("/proc/modules")
+	f, err := os.Open(procModulesPath)
 	if err != nil {
 		return false, err
 	}
+	defer f.Close()
+
+	modules, err := parseProcModules(f)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := modules[NormalizeKernelModuleName(name)]
+
+	return ok, nil
+}
+
+// KernelModules returns the names of the kernel modules loaded into the running kernel
+// or built into it. The dashes in the names are replaced with underscores, as the kernel does.
+func KernelModules() (map[string]struct{}, error) {
+	f, err := os.Open(procModulesPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	modules, err := parseProcModules(f)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := os.ReadFile(kernelOSReleasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the list of the builtin modules is missing when the modules of the kernel are not installed
+	builtin, err := os.Open(path.Join(modulesDir, strings.TrimSpace(string(release)), "modules.builtin"))
+	if err != nil {
+		log.Debugf("failed to read the list of the builtin kernel modules: %v", err)
+
+		return modules, nil
+	}
+	defer builtin.Close()
+
+	builtinModules, err := parseModulesBuiltin(builtin)
+	if err != nil {
+		return nil, err
+	}
+
+	for m := range builtinModules {
+		modules[m] = struct{}{}
+	}
+
+	return modules, nil
+}
+
+// parseProcModules returns the names of the modules listed in the /proc/modules format.
+func parseProcModules(r io.Reader) (map[string]struct{}, error) {
+	modules := map[string]struct{}{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		modules[NormalizeKernelModuleName(fields[0])] = struct{}{}
+	}
+
+	return modules, scanner.Err()
+}
+
+// parseModulesBuiltin returns the names of the modules listed in the modules.builtin format,
+// i.e. the paths of the module files, such as kernel/drivers/net/veth.ko.
+func parseModulesBuiltin(r io.Reader) (map[string]struct{}, error) {
+	modules := map[string]struct{}{}
 
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
-		if strings.HasPrefix(strings.Fields(scanner.Text())[0], name) {
-			return true, nil
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
 		}
+
+		modules[NormalizeKernelModuleName(strings.TrimSuffix(path.Base(line), ".ko"))] = struct{}{}
+	}
+
+	return modules, scanner.Err()
+}
+
+// NormalizeKernelModuleName returns the module name with the dashes replaced with underscores,
+// the kernel treats both as the same character in the module names.
+func NormalizeKernelModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// LoadKernelModule loads the kernel module and its dependencies.
+// The modprobe binary is used when the module loader can't be initialized,
+// e.g. when the modules dependency file is not found.
+func LoadKernelModule(name string) error {
+	km, err := kmod.New()
+	if err == nil {
+		return km.Load(name, "", 0)
 	}
-	return false, f.Close()
+
+	log.Debugf("failed to init the kernel module loader: %v, falling back to modprobe", err)
+
+	modprobe, lookErr := exec.LookPath("modprobe")
+	if lookErr != nil {
+		return fmt.Errorf("the kernel module loader is unavailable (%v) and the modprobe binary is not found", err)
+	}
+
+	out, err := exec.Command(modprobe, name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %v: %s", modprobe, name, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
 }
 
 const kernelOSReleasePath = "/proc/sys/kernel/osrelease"
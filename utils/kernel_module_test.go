@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -47,6 +50,38 @@ func TestIsKernelModuleLoaded(t *testing.T) {
 	}
 }
 
+func TestIsKernelModuleLoadedExactName(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "modules")
+	if err := os.WriteFile(p, []byte("bonding_foo 16384 0 - Live 0x0000000000000000\n"+
+		"ip6_udp_tunnel 16384 0 - Live 0x0000000000000000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := procModulesPath
+	procModulesPath = p
+	t.Cleanup(func() { procModulesPath = orig })
+
+	tests := map[string]bool{
+		"bonding_foo":    true,
+		"ip6-udp-tunnel": true,
+		// the prefix of a loaded module name is not loaded
+		"bond":  false,
+		"ip6":   false,
+		"vxlan": false,
+	}
+
+	for name, want := range tests {
+		got, err := IsKernelModuleLoaded(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != want {
+			t.Errorf("IsKernelModuleLoaded(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
 func TestParseKernelVersion(t *testing.T) {
 	tests := []struct {
 		input     []byte
@@ -100,3 +135,41 @@ func TestKernelVersionGreaterOrEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestParseProcModules(t *testing.T) {
+	procModules := `vxlan 73728 0 - Live 0x0000000000000000
+ip6_udp_tunnel 16384 1 vxlan, Live 0x0000000000000000
+ip_tables 32768 0 - Live 0x0000000000000000
+
+`
+
+	got, err := parseProcModules(strings.NewReader(procModules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct{}{"vxlan": {}, "ip6_udp_tunnel": {}, "ip_tables": {}}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("modules mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestParseModulesBuiltin(t *testing.T) {
+	builtin := `kernel/drivers/net/veth.ko
+kernel/drivers/net/macvlan.ko
+kernel/net/8021q/8021q.ko
+kernel/drivers/net/dummy-xyz.ko
+`
+
+	got, err := parseModulesBuiltin(strings.NewReader(builtin))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct{}{"veth": {}, "macvlan": {}, "8021q": {}, "dummy_xyz": {}}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("modules mismatch (-want +got):\n%s", d)
+	}
+}
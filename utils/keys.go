@@ -5,11 +5,34 @@ import (
 	"golang.org/x/crypto/ssh"
 )
 
+// SSHAuthorizedKey is a public key along with its comment.
+type SSHAuthorizedKey struct {
+	Key     ssh.PublicKey
+	Comment string
+}
+
 // LoadSSHPubKeysFromFiles parses openssh keys from the files referenced by the paths
 // and returns a slice of ssh.PublicKey pointers.
 // The files may contain multiple keys each on a separate line.
 func LoadSSHPubKeysFromFiles(paths []string) ([]ssh.PublicKey, error) {
-	var keys []ssh.PublicKey
+	authzKeys, err := LoadSSHAuthorizedKeysFromFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]ssh.PublicKey, 0, len(authzKeys))
+	for _, k := range authzKeys {
+		keys = append(keys, k.Key)
+	}
+
+	return keys, nil
+}
+
+// LoadSSHAuthorizedKeysFromFiles parses openssh keys from the files referenced by the paths
+// keeping the comments of the keys.
+// The files may contain multiple keys each on a separate line.
+func LoadSSHAuthorizedKeysFromFiles(paths []string) ([]SSHAuthorizedKey, error) {
+	var keys []SSHAuthorizedKey
 
 	for _, p := range paths {
 		lines, err := FileLines(p, "#")
@@ -18,7 +41,7 @@ func LoadSSHPubKeysFromFiles(paths []string) ([]ssh.PublicKey, error) {
 		}
 
 		for _, l := range lines {
-			pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(l))
+			pubKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(l))
 
 			log.Debugf("Loaded public key %s", l)
 
@@ -26,7 +49,7 @@ func LoadSSHPubKeysFromFiles(paths []string) ([]ssh.PublicKey, error) {
 				return nil, err
 			}
 
-			keys = append(keys, pubKey)
+			keys = append(keys, SSHAuthorizedKey{Key: pubKey, Comment: comment})
 		}
 
 	}
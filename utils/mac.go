@@ -0,0 +1,53 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// ParseInterfaceMAC parses s as a MAC address of a network interface.
+// The colon, dash and dot separated formats are accepted,
+// the returned address is formatted colon separated in lowercase by its String method.
+// Only 48-bit unicast addresses are valid, multicast and all-zeros addresses are rejected.
+func ParseInterfaceMAC(s string) (net.HardwareAddr, error) {
+	m, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m) != 6 {
+		return nil, fmt.Errorf("MAC address %s is not a 48-bit address", s)
+	}
+
+	// the least significant bit of the first octet is the multicast bit
+	if m[0]&0x01 != 0 {
+		return nil, fmt.Errorf("MAC address %s is a multicast address", s)
+	}
+
+	if bytes.Equal(m, make(net.HardwareAddr, 6)) {
+		return nil, fmt.Errorf("MAC address %s is an all-zeros address", s)
+	}
+
+	return m, nil
+}
+
+// IncrementMAC returns the MAC address following m, the overflow of an octet is carried to the previous one.
+// The address following ff:ff:ff:ff:ff:ff wraps around to 00:00:00:00:00:00.
+func IncrementMAC(m net.HardwareAddr) net.HardwareAddr {
+	r := make(net.HardwareAddr, len(m))
+	copy(r, m)
+
+	for i := len(r) - 1; i >= 0; i-- {
+		r[i]++
+		if r[i] != 0 {
+			break
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseInterfaceMAC(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		"colon":                {in: "00:1c:73:00:00:01", want: "00:1c:73:00:00:01"},
+		"uppercase":            {in: "00:1C:73:AB:CD:EF", want: "00:1c:73:ab:cd:ef"},
+		"dash":                 {in: "aa-c1-ab-00-00-01", want: "aa:c1:ab:00:00:01"},
+		"dot":                  {in: "001c.7300.0001", want: "00:1c:73:00:00:01"},
+		"locally-administered": {in: "02:00:00:00:00:01", want: "02:00:00:00:00:01"},
+		"invalid-octet":        {in: "00:1c:73:zz:00:01", wantErr: true},
+		"too-short":            {in: "00:1c:73:00:01", wantErr: true},
+		"eui-64":               {in: "00:1c:73:ff:fe:00:00:01", wantErr: true},
+		"multicast":            {in: "01:00:5e:00:00:01", wantErr: true},
+		"broadcast":            {in: "ff:ff:ff:ff:ff:ff", wantErr: true},
+		"all-zeros":            {in: "00:00:00:00:00:00", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseInterfaceMAC(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.String() != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncrementMAC(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"last-octet":     {in: "00:1c:73:00:00:01", want: "00:1c:73:00:00:02"},
+		"carry":          {in: "00:1c:73:00:00:ff", want: "00:1c:73:00:01:00"},
+		"multiple-carry": {in: "00:1c:73:ff:ff:ff", want: "00:1c:74:00:00:00"},
+		"wrap-around":    {in: "ff:ff:ff:ff:ff:ff", want: "00:00:00:00:00:00"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := net.ParseMAC(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := IncrementMAC(m)
+
+			if got.String() != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+
+			// the input address is not modified
+			if m.String() != tt.in {
+				t.Fatalf("input address modified to %s", m)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+// NaturalLess reports whether a sorts before b in the natural order, where the runs of digits
+// are compared by their numeric value, so that node2 sorts before node10.
+// The numbers with the same value and a different number of leading zeros are ordered by the length.
+func NaturalLess(a, b string) bool {
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		if !isDigit(a[i]) || !isDigit(b[j]) {
+			if a[i] != b[j] {
+				return a[i] < b[j]
+			}
+
+			i++
+			j++
+
+			continue
+		}
+
+		// compare the runs of digits, skipping the leading zeros
+		ai, bj := i, j
+		for ai < len(a) && a[ai] == '0' {
+			ai++
+		}
+
+		for bj < len(b) && b[bj] == '0' {
+			bj++
+		}
+
+		ae, be := ai, bj
+		for ae < len(a) && isDigit(a[ae]) {
+			ae++
+		}
+
+		for be < len(b) && isDigit(b[be]) {
+			be++
+		}
+
+		// the longer run without the leading zeros is the bigger number
+		if ae-ai != be-bj {
+			return ae-ai < be-bj
+		}
+
+		if a[ai:ae] != b[bj:be] {
+			return a[ai:ae] < b[bj:be]
+		}
+
+		// the same numbers with fewer leading zeros go first
+		if ae-i != be-j {
+			return ae-i < be-j
+		}
+
+		i, j = ae, be
+	}
+
+	return len(a)-i < len(b)-j
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNaturalLess(t *testing.T) {
+	got := []string{
+		"clab-lab-srl10",
+		"clab-lab-srl2",
+		"clab-lab-client",
+		"clab-lab-srl1",
+		"clab-lab-srl02",
+		"clab-lab-srl1a",
+		"clab-lab-srl",
+		"clab-lab-leaf1-1",
+		"clab-lab-leaf1-10",
+		"clab-lab-leaf1-2",
+	}
+
+	want := []string{
+		"clab-lab-client",
+		"clab-lab-leaf1-1",
+		"clab-lab-leaf1-2",
+		"clab-lab-leaf1-10",
+		"clab-lab-srl",
+		"clab-lab-srl1",
+		"clab-lab-srl1a",
+		"clab-lab-srl2",
+		"clab-lab-srl02",
+		"clab-lab-srl10",
+	}
+
+	sort.Slice(got, func(i, j int) bool { return NaturalLess(got[i], got[j]) })
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("natural sort mismatch (-want +got):\n%s", d)
+	}
+
+	for _, s := range want {
+		if NaturalLess(s, s) {
+			t.Errorf("NaturalLess(%q, %q) is true", s, s)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/jsimonetti/rtnetlink/rtnl"
@@ -16,6 +17,9 @@ import (
 	"github.com/vishvananda/netlink"
 )
 
+// NetnsDir is a directory where the symlinks to the containers network namespaces are created.
+var NetnsDir = "/run/netns"
+
 // BridgeByName returns a *netlink.Bridge referenced by its name.
 func BridgeByName(name string) (*netlink.Bridge, error) {
 	l, err := LinkByNameOrAlias(name)
@@ -32,8 +36,8 @@ func BridgeByName(name string) (*netlink.Bridge, error) {
 // LinkContainerNS creates a symlink for containers network namespace
 // so that it can be managed by iproute2 utility.
 func LinkContainerNS(nspath, containerName string) error {
-	CreateDirectory("/run/netns/", 0755)
-	dst := "/run/netns/" + containerName
+	CreateDirectory(NetnsDir, 0755)
+	dst := filepath.Join(NetnsDir, containerName)
 	if _, err := os.Lstat(dst); err == nil {
 		os.Remove(dst)
 	}
@@ -56,7 +60,7 @@ func GenMac(oui string) (net.HardwareAddr, error) {
 // DeleteNetnsSymlink deletes a network namespace and removes the symlink created by LinkContainerNS func.
 func DeleteNetnsSymlink(n string) error {
 	log.Debug("Deleting netns symlink: ", n)
-	sl := fmt.Sprintf("/run/netns/%s", n)
+	sl := filepath.Join(NetnsDir, n)
 	err := os.Remove(sl)
 	if err != nil {
 		log.Debug("Failed to delete netns symlink by path:", sl)
@@ -4,3 +4,8 @@ package utils
 func BoolPointer(b bool) *bool {
 	return &b
 }
+
+// IntPointer returns a pointer to an int.
+func IntPointer(i int) *int {
+	return &i
+}
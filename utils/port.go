@@ -0,0 +1,39 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+const (
+	// DynamicPortRangeStart is the first port of the IANA dynamic port range.
+	DynamicPortRangeStart = 49152
+	// DynamicPortRangeEnd is the last port of the IANA dynamic port range.
+	DynamicPortRangeEnd = 65535
+)
+
+// FreeTCPPort returns the first TCP port from the [start, end] range
+// that is not in the reserved set and can be bound on the host.
+func FreeTCPPort(start, end int, reserved map[int]struct{}) (int, error) {
+	for p := start; p <= end; p++ {
+		if _, ok := reserved[p]; ok {
+			continue
+		}
+
+		l, err := net.Listen("tcp", ":"+strconv.Itoa(p))
+		if err != nil {
+			continue
+		}
+
+		l.Close()
+
+		return p, nil
+	}
+
+	return 0, fmt.Errorf("no free tcp port found in the %d-%d range", start, end)
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFreeTCPPort(t *testing.T) {
+	// occupy a port to check that it is skipped
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	busy := l.Addr().(*net.TCPAddr).Port
+
+	tests := map[string]struct {
+		start, end int
+		reserved   map[int]struct{}
+		want       int
+		wantErr    bool
+	}{
+		"busy port skipped": {
+			start: busy,
+			end:   busy + 1,
+			want:  busy + 1,
+		},
+		"reserved port skipped": {
+			start:    busy + 1,
+			end:      busy + 2,
+			reserved: map[int]struct{}{busy + 1: {}},
+			want:     busy + 2,
+		},
+		"no free port": {
+			start:    busy,
+			end:      busy + 1,
+			reserved: map[int]struct{}{busy + 1: {}},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := FreeTCPPort(tt.start, tt.end, tt.reserved)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("wanted error, got port %d", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got port %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
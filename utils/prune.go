@@ -0,0 +1,82 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// PruneDir removes the entries of the root directory not modified within the max age
+// and returns their paths sorted. All the entries are removed when the max age is zero.
+// The entries for which keep returns true are left in place.
+// With the dry run the entries are returned without being removed.
+// The root must be an absolute path of a directory and not a symlink. Only the entries of the root
+// are removed, the symlinks are removed without following them.
+func PruneDir(root string, maxAge time.Duration, keep func(path string) bool, dryRun bool) ([]string, error) {
+	if !filepath.IsAbs(root) || filepath.Clean(root) != root {
+		return nil, fmt.Errorf("pruned directory %q must be an absolute clean path", root)
+	}
+
+	fi, err := os.Lstat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("pruned path %s is not a directory", root)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	var pruned []string
+
+	for _, e := range entries {
+		p := filepath.Join(root, e.Name())
+		// the entry names read from the directory can't escape it, checked nonetheless
+		if filepath.Dir(p) != root {
+			return pruned, fmt.Errorf("entry %q escapes the pruned directory %s", e.Name(), root)
+		}
+
+		if keep != nil && keep(p) {
+			continue
+		}
+
+		info, err := os.Lstat(p)
+		if err != nil {
+			// the entry removed meanwhile
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return pruned, err
+		}
+
+		if maxAge > 0 && now.Sub(info.ModTime()) < maxAge {
+			continue
+		}
+
+		if !dryRun {
+			// RemoveAll doesn't follow the symlinks, the link itself is removed
+			if err := os.RemoveAll(p); err != nil {
+				return pruned, err
+			}
+		}
+
+		pruned = append(pruned, p)
+	}
+
+	sort.Strings(pruned)
+
+	return pruned, nil
+}
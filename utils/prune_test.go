@@ -0,0 +1,186 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// pruneTree creates the entries of the pruned directory modified the given time ago
+// along with a directory outside of it the old symlink points to.
+func pruneTree(t *testing.T) (root, outside string) {
+	t.Helper()
+
+	root, outside = filepath.Join(t.TempDir(), "root"), t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(outside, "target"), []byte("target"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := map[string]time.Duration{
+		"old.cfg":     10 * 24 * time.Hour,
+		"old-dir":     10 * 24 * time.Hour,
+		"kept.cfg":    10 * 24 * time.Hour,
+		"new.cfg":     time.Hour,
+		"old-symlink": 10 * 24 * time.Hour,
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "old-dir", "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, age := range entries {
+		p := filepath.Join(root, name)
+
+		switch name {
+		case "old-dir":
+		case "old-symlink":
+			if err := os.Symlink(outside, p); err != nil {
+				t.Fatal(err)
+			}
+		default:
+			if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		mtime := time.Now().Add(-age)
+		if name == "old-symlink" {
+			// the mtime of the symlink itself can't be set portably, the link is pruned with the zero max age
+			continue
+		}
+
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root, outside
+}
+
+func TestPruneDir(t *testing.T) {
+	keep := func(p string) bool { return filepath.Base(p) == "kept.cfg" }
+
+	tests := map[string]struct {
+		maxAge     time.Duration
+		dryRun     bool
+		wantPruned []string
+		wantLeft   []string
+	}{
+		"aged entries": {
+			maxAge:     7 * 24 * time.Hour,
+			wantPruned: []string{"old-dir", "old.cfg"},
+			wantLeft:   []string{"kept.cfg", "new.cfg", "old-symlink"},
+		},
+		"all entries": {
+			wantPruned: []string{"new.cfg", "old-dir", "old-symlink", "old.cfg"},
+			wantLeft:   []string{"kept.cfg"},
+		},
+		"dry run": {
+			dryRun:     true,
+			wantPruned: []string{"new.cfg", "old-dir", "old-symlink", "old.cfg"},
+			wantLeft:   []string{"kept.cfg", "new.cfg", "old-dir", "old-symlink", "old.cfg"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root, outside := pruneTree(t)
+
+			pruned, err := PruneDir(root, tt.maxAge, keep, tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want []string
+			for _, n := range tt.wantPruned {
+				want = append(want, filepath.Join(root, n))
+			}
+
+			if d := cmp.Diff(want, pruned); d != "" {
+				t.Errorf("pruned entries mismatch (-want +got):\n%s", d)
+			}
+
+			entries, err := os.ReadDir(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var left []string
+			for _, e := range entries {
+				left = append(left, e.Name())
+			}
+
+			if d := cmp.Diff(tt.wantLeft, left); d != "" {
+				t.Errorf("left entries mismatch (-want +got):\n%s", d)
+			}
+
+			// the symlink target outside of the pruned directory is never removed
+			if !FileExists(filepath.Join(outside, "target")) {
+				t.Error("symlink target outside of the pruned directory was removed")
+			}
+		})
+	}
+}
+
+func TestPruneDirInvalidRoot(t *testing.T) {
+	dir := t.TempDir()
+
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(t.TempDir(), link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, root := range []string{"relative/dir", dir + "/../" + filepath.Base(dir), link} {
+		if _, err := PruneDir(root, 0, nil, false); err == nil {
+			t.Errorf("PruneDir(%q) succeeded, want error", root)
+		}
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".lock")
+
+	unlock, err := LockFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := make(chan struct{})
+
+	go func() {
+		unlock, err := LockFile(p)
+		if err != nil {
+			t.Error(err)
+			close(locked)
+
+			return
+		}
+
+		close(locked)
+		unlock()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("the lock was taken while held")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lock was not taken after the release")
+	}
+}